@@ -16,6 +16,25 @@ playlistv0alpha1: {
 			"UPDATE",
 		]
 	}
+	routes: {
+		// scheduled returns the playlist a kiosk device plays now. It doesn't depend on the playlist
+		// in the path, and is also served from /namespaces/{namespace}/scheduled.
+		"/scheduled": {
+			"GET": {
+				name: "getScheduledPlaylist"
+				request: {
+					query: {
+						// kiosk is the name of the kiosk device. Schedules for other kiosk devices are ignored.
+						kiosk?: string
+					}
+				}
+				response: {
+					// name of the playlist the kiosk device plays now.
+					name: string
+				}
+			}
+		}
+	}
 	schema: {
 		#Item: {
 			// type of the item.
@@ -28,11 +47,36 @@ playlistv0alpha1: {
 			//  dashboards behind the tag will be added to the playlist.
 			//  - dashboard_by_uid: The value is the dashboard UID
 			value: string
+			// duration overrides the interval of the playlist for the dashboards of this item, for example "2m".
+			duration?: string
+			// timeRange overrides the time range of the dashboards of this item.
+			timeRange?: #TimeRange
+		}
+		#TimeRange: {
+			// from is the start of the time range, for example "now-6h".
+			from: string
+			// to is the end of the time range, for example "now".
+			to: string
+		}
+		#Schedule: {
+			// weekdays the schedule applies to, in lower case ("monday" to "sunday"). It applies to every day if empty.
+			weekdays?: [...string]
+			// from is the time of day the schedule starts at, formatted as "15:04".
+			from: string
+			// to is the time of day the schedule ends at, formatted as "15:04". The schedule continues
+			// on the next day if it is before from.
+			to: string
+			// timezone of from and to, as an IANA time zone name. Defaults to UTC.
+			timezone?: string
+			// kiosk is the name of the kiosk devices the schedule applies to. It applies to every kiosk if empty.
+			kiosk?: string
 		}
 		spec: {
 			title:    string
 			interval: string
 			items: [...#Item]
+			// schedules are the times at which kiosk devices play the playlist.
+			schedules?: [...#Schedule]
 		}
 	}
 }
@@ -0,0 +1,122 @@
+package v0alpha1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScheduleTimeFormat is the format of the from and to times of day of playlist schedules
+const ScheduleTimeFormat = "15:04"
+
+// Parse returns the weekdays, the start and end of the schedule as minutes since midnight, and its location
+func (s *PlaylistSchedule) Parse() (weekdays map[time.Weekday]bool, from int, to int, loc *time.Location, err error) {
+	weekdays = map[time.Weekday]bool{}
+	for _, name := range s.Weekdays {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, 0, 0, nil, fmt.Errorf("invalid weekday %q", name)
+		}
+		weekdays[day] = true
+	}
+
+	if from, err = parseTimeOfDay(s.From); err != nil {
+		return nil, 0, 0, nil, err
+	}
+	if to, err = parseTimeOfDay(s.To); err != nil {
+		return nil, 0, 0, nil, err
+	}
+	if from == to {
+		return nil, 0, 0, nil, fmt.Errorf("schedule starts and ends at %s", s.From)
+	}
+
+	loc = time.UTC
+	if s.Timezone != nil && *s.Timezone != "" {
+		if loc, err = time.LoadLocation(*s.Timezone); err != nil {
+			return nil, 0, 0, nil, fmt.Errorf("invalid timezone %q: %w", *s.Timezone, err)
+		}
+	}
+	return weekdays, from, to, loc, nil
+}
+
+// IsActive returns true if the schedule applies at t. A schedule that ends on the next day applies on the days
+// it starts on, so a schedule from 22:00 to 06:00 on fridays applies until saturday 06:00.
+func (s *PlaylistSchedule) IsActive(t time.Time) bool {
+	weekdays, from, to, loc, err := s.Parse()
+	if err != nil {
+		return false
+	}
+
+	t = t.In(loc)
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if from > to && minute < to {
+		// the part of the schedule after midnight belongs to the day before
+		day = (day + 6) % 7
+	} else if minute < from || (from < to && minute >= to) {
+		return false
+	}
+	return len(weekdays) == 0 || weekdays[day]
+}
+
+// AppliesToKiosk returns true if the schedule applies to the kiosk devices with the name
+func (s *PlaylistSchedule) AppliesToKiosk(kiosk string) bool {
+	return s.Kiosk == nil || *s.Kiosk == "" || *s.Kiosk == kiosk
+}
+
+// ScheduledPlaylist returns the playlist a kiosk plays at t, or nil if no schedule applies.
+// Schedules for the kiosk take precedence over schedules for every kiosk, and playlists are otherwise
+// picked by title so that every kiosk picks the same playlist.
+func ScheduledPlaylist(playlists []Playlist, kiosk string, t time.Time) *Playlist {
+	candidates := make([]*Playlist, 0)
+	seen := map[*Playlist]bool{}
+	specific := map[*Playlist]bool{}
+	for i := range playlists {
+		p := &playlists[i]
+		for j := range p.Spec.Schedules {
+			schedule := &p.Spec.Schedules[j]
+			if !schedule.AppliesToKiosk(kiosk) || !schedule.IsActive(t) {
+				continue
+			}
+			if !seen[p] {
+				seen[p] = true
+				candidates = append(candidates, p)
+			}
+			if schedule.Kiosk != nil && *schedule.Kiosk != "" {
+				specific[p] = true
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if specific[candidates[i]] != specific[candidates[j]] {
+			return specific[candidates[i]]
+		}
+		if candidates[i].Spec.Title != candidates[j].Spec.Title {
+			return candidates[i].Spec.Title < candidates[j].Spec.Title
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates[0]
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse(ScheduleTimeFormat, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected the format %s", value, ScheduleTimeFormat)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
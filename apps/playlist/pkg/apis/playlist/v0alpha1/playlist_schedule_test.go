@@ -0,0 +1,94 @@
+package v0alpha1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlaylistScheduleIsActive(t *testing.T) {
+	berlin := "Europe/Berlin"
+	// a friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule PlaylistSchedule
+		at       time.Time
+		active   bool
+	}{
+		{name: "inside daily schedule", schedule: PlaylistSchedule{From: "08:00", To: "18:00"}, at: friday(12, 0), active: true},
+		{name: "at the start", schedule: PlaylistSchedule{From: "08:00", To: "18:00"}, at: friday(8, 0), active: true},
+		{name: "at the end", schedule: PlaylistSchedule{From: "08:00", To: "18:00"}, at: friday(18, 0), active: false},
+		{name: "before the start", schedule: PlaylistSchedule{From: "08:00", To: "18:00"}, at: friday(7, 59), active: false},
+		{name: "on a weekday of the schedule", schedule: PlaylistSchedule{Weekdays: []string{"friday"}, From: "08:00", To: "18:00"}, at: friday(12, 0), active: true},
+		{name: "on another weekday", schedule: PlaylistSchedule{Weekdays: []string{"Monday"}, From: "08:00", To: "18:00"}, at: friday(12, 0), active: false},
+		{name: "overnight before midnight", schedule: PlaylistSchedule{Weekdays: []string{"friday"}, From: "22:00", To: "06:00"}, at: friday(23, 0), active: true},
+		{name: "overnight after midnight", schedule: PlaylistSchedule{Weekdays: []string{"friday"}, From: "22:00", To: "06:00"}, at: friday(23, 0).Add(6 * time.Hour), active: true},
+		{name: "overnight after midnight of another day", schedule: PlaylistSchedule{Weekdays: []string{"friday"}, From: "22:00", To: "06:00"}, at: friday(5, 0), active: false},
+		{name: "in a timezone", schedule: PlaylistSchedule{From: "12:00", To: "14:00", Timezone: &berlin}, at: friday(11, 30), active: true},
+		{name: "invalid schedule", schedule: PlaylistSchedule{From: "8am", To: "18:00"}, at: friday(12, 0), active: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if active := tc.schedule.IsActive(tc.at); active != tc.active {
+				t.Errorf("expected active to be %t, got %t", tc.active, active)
+			}
+		})
+	}
+}
+
+func TestPlaylistScheduleParse(t *testing.T) {
+	invalidTimezone := "Mars/Olympus_Mons"
+	for name, schedule := range map[string]PlaylistSchedule{
+		"invalid weekday":  {Weekdays: []string{"caturday"}, From: "08:00", To: "18:00"},
+		"invalid from":     {From: "25:00", To: "18:00"},
+		"invalid to":       {From: "08:00", To: "6pm"},
+		"empty schedule":   {From: "08:00", To: "08:00"},
+		"invalid timezone": {From: "08:00", To: "18:00", Timezone: &invalidTimezone},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, _, _, err := schedule.Parse(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestScheduledPlaylist(t *testing.T) {
+	noc := "noc"
+	newPlaylist := func(name string, schedules ...PlaylistSchedule) Playlist {
+		p := Playlist{Spec: PlaylistSpec{Title: name, Schedules: schedules}}
+		p.Name = name
+		return p
+	}
+	playlists := []Playlist{
+		newPlaylist("unscheduled"),
+		newPlaylist("night", PlaylistSchedule{From: "20:00", To: "08:00"}),
+		newPlaylist("day", PlaylistSchedule{From: "08:00", To: "20:00"}),
+		newPlaylist("noc day", PlaylistSchedule{From: "08:00", To: "20:00", Kiosk: &noc}),
+	}
+
+	tests := []struct {
+		kiosk    string
+		hour     int
+		expected string
+	}{
+		{kiosk: "lobby", hour: 10, expected: "day"},
+		{kiosk: "lobby", hour: 22, expected: "night"},
+		{kiosk: "noc", hour: 10, expected: "noc day"},
+		{kiosk: "noc", hour: 22, expected: "night"},
+	}
+	for _, tc := range tests {
+		scheduled := ScheduledPlaylist(playlists, tc.kiosk, time.Date(2024, time.March, 1, tc.hour, 0, 0, 0, time.UTC))
+		if scheduled == nil || scheduled.Name != tc.expected {
+			t.Errorf("expected %s to play %q at %d:00, got %v", tc.kiosk, tc.expected, tc.hour, scheduled)
+		}
+	}
+
+	if scheduled := ScheduledPlaylist(playlists[:1], "lobby", time.Now()); scheduled != nil {
+		t.Errorf("expected no scheduled playlist, got %q", scheduled.Name)
+	}
+}
@@ -14,6 +14,10 @@ type PlaylistItem struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+	// duration overrides the interval of the playlist for the dashboards of this item, for example "2m".
+	Duration *string `json:"duration,omitempty"`
+	// timeRange overrides the time range of the dashboards of this item.
+	TimeRange *PlaylistTimeRange `json:"timeRange,omitempty"`
 }
 
 // NewPlaylistItem creates a new PlaylistItem object.
@@ -21,11 +25,46 @@ func NewPlaylistItem() *PlaylistItem {
 	return &PlaylistItem{}
 }
 
+// +k8s:openapi-gen=true
+type PlaylistTimeRange struct {
+	// from is the start of the time range, for example "now-6h".
+	From string `json:"from"`
+	// to is the end of the time range, for example "now".
+	To string `json:"to"`
+}
+
+// NewPlaylistTimeRange creates a new PlaylistTimeRange object.
+func NewPlaylistTimeRange() *PlaylistTimeRange {
+	return &PlaylistTimeRange{}
+}
+
+// +k8s:openapi-gen=true
+type PlaylistSchedule struct {
+	// weekdays the schedule applies to, in lower case ("monday" to "sunday"). It applies to every day if empty.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// from is the time of day the schedule starts at, formatted as "15:04".
+	From string `json:"from"`
+	// to is the time of day the schedule ends at, formatted as "15:04". The schedule continues
+	// on the next day if it is before from.
+	To string `json:"to"`
+	// timezone of from and to, as an IANA time zone name. Defaults to UTC.
+	Timezone *string `json:"timezone,omitempty"`
+	// kiosk is the name of the kiosk devices the schedule applies to. It applies to every kiosk if empty.
+	Kiosk *string `json:"kiosk,omitempty"`
+}
+
+// NewPlaylistSchedule creates a new PlaylistSchedule object.
+func NewPlaylistSchedule() *PlaylistSchedule {
+	return &PlaylistSchedule{}
+}
+
 // +k8s:openapi-gen=true
 type PlaylistSpec struct {
 	Title    string         `json:"title"`
 	Interval string         `json:"interval"`
 	Items    []PlaylistItem `json:"items"`
+	// schedules are the times at which kiosk devices play the playlist.
+	Schedules []PlaylistSchedule `json:"schedules,omitempty"`
 }
 
 // NewPlaylistSpec creates a new PlaylistSpec object.
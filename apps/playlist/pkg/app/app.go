@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/grafana/grafana-app-sdk/app"
 	"github.com/grafana/grafana-app-sdk/k8s"
@@ -46,6 +48,11 @@ func New(cfg app.Config) (app.App, error) {
 		}
 	}
 
+	client, err := k8s.NewClientRegistry(cfg.KubeConfig, k8s.ClientConfig{}).ClientFor(playlistv0alpha1.PlaylistKind())
+	if err != nil {
+		return nil, err
+	}
+
 	simpleConfig := simple.AppConfig{
 		Name:       "playlist",
 		KubeConfig: cfg.KubeConfig,
@@ -68,10 +75,16 @@ func New(cfg app.Config) (app.App, error) {
 				},
 				Validator: &simple.Validator{
 					ValidateFunc: func(ctx context.Context, req *app.AdmissionRequest) error {
-						// do something here if needed
-						return nil
+						playlist, ok := req.Object.(*playlistv0alpha1.Playlist)
+						if !ok {
+							return fmt.Errorf("expected Playlist object, got %T", req.Object)
+						}
+						return validatePlaylist(playlist)
 					},
 				},
+				CustomRoutes: simple.AppCustomRouteHandlers{
+					{Method: simple.AppCustomRouteMethodGet, Path: "scheduled"}: newScheduledPlaylistHandler(client, time.Now),
+				},
 			},
 		},
 	}
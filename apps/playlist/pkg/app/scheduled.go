@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-app-sdk/app"
+	"github.com/grafana/grafana-app-sdk/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	playlistv0alpha1 "github.com/grafana/grafana/apps/playlist/pkg/apis/playlist/v0alpha1"
+)
+
+// newScheduledPlaylistHandler returns the handler of the scheduled route, which returns the playlist a
+// kiosk device plays now. The route is authorized as a read of playlists, and every playlist of the
+// namespace can be read by the users that can read playlists, so the playlists are listed with the client
+// of the app.
+func newScheduledPlaylistHandler(client resource.Client, now func() time.Time) func(context.Context, app.CustomRouteResponseWriter, *app.CustomRouteRequest) error {
+	return func(ctx context.Context, w app.CustomRouteResponseWriter, req *app.CustomRouteRequest) error {
+		list := &playlistv0alpha1.PlaylistList{}
+		if err := client.ListInto(ctx, req.ResourceIdentifier.Namespace, resource.ListOptions{}, list); err != nil {
+			return err
+		}
+
+		scheduled := playlistv0alpha1.ScheduledPlaylist(list.Items, req.URL.Query().Get("kiosk"), now())
+		if scheduled == nil {
+			kind := playlistv0alpha1.PlaylistKind()
+			return apierrors.NewNotFound(kind.GroupVersionResource().GroupResource(), "scheduled")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(playlistv0alpha1.GetScheduledPlaylist{Name: scheduled.Name})
+	}
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	playlistv0alpha1 "github.com/grafana/grafana/apps/playlist/pkg/apis/playlist/v0alpha1"
+)
+
+var (
+	ErrInvalidItemDuration  = errors.New("invalid playlist item duration")
+	ErrInvalidItemTimeRange = errors.New("invalid playlist item time range")
+	ErrInvalidSchedule      = errors.New("invalid playlist schedule")
+)
+
+// validatePlaylist checks the item overrides and the schedules of a playlist
+func validatePlaylist(p *playlistv0alpha1.Playlist) error {
+	for i, item := range p.Spec.Items {
+		if item.Duration != nil && *item.Duration != "" {
+			d, err := time.ParseDuration(*item.Duration)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("%w: item %d has duration %q, expected a positive duration like 20s or 2m", ErrInvalidItemDuration, i, *item.Duration)
+			}
+		}
+		if item.TimeRange != nil && (item.TimeRange.From == "" || item.TimeRange.To == "") {
+			return fmt.Errorf("%w: item %d needs both from and to", ErrInvalidItemTimeRange, i)
+		}
+	}
+
+	for i := range p.Spec.Schedules {
+		if _, _, _, _, err := p.Spec.Schedules[i].Parse(); err != nil {
+			return fmt.Errorf("%w: schedule %d: %s", ErrInvalidSchedule, i, err)
+		}
+	}
+	return nil
+}
@@ -8,10 +8,16 @@ shorturl: {
 		spec: {
 			// The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel
 			path: string
+			// The unix time in seconds after which the short URL no longer resolves, 0 if it never expires
+			expiresAt: int64 | *0
+			// The number of times the short URL can be used, 0 if it is unlimited
+			maxUses: int64 | *0
 		}
 		status: {
 			// The last time the short URL was used, 0 is the initial value
 			lastSeenAt: int64
+			// The number of times the short URL was used
+			useCount: int64 | *0
 		}
 	}
 }
@@ -6,9 +6,16 @@ package v1alpha1
 type ShortURLSpec struct {
 	// The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel
 	Path string `json:"path"`
+	// The unix time in seconds after which the short URL no longer resolves, 0 if it never expires
+	ExpiresAt int64 `json:"expiresAt"`
+	// The number of times the short URL can be used, 0 if it is unlimited
+	MaxUses int64 `json:"maxUses"`
 }
 
 // NewShortURLSpec creates a new ShortURLSpec object.
 func NewShortURLSpec() *ShortURLSpec {
-	return &ShortURLSpec{}
+	return &ShortURLSpec{
+		ExpiresAt: 0,
+		MaxUses:   0,
+	}
 }
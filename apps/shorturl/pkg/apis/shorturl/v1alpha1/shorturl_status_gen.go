@@ -24,6 +24,8 @@ func NewShortURLstatusOperatorState() *ShortURLstatusOperatorState {
 type ShortURLStatus struct {
 	// The last time the short URL was used, 0 is the initial value
 	LastSeenAt int64 `json:"lastSeenAt"`
+	// The number of times the short URL was used
+	UseCount int64 `json:"useCount"`
 	// operatorStates is a map of operator ID to operator state evaluations.
 	// Any operator which consumes this kind SHOULD add its state evaluation information to this field.
 	OperatorStates map[string]ShortURLstatusOperatorState `json:"operatorStates,omitempty"`
@@ -33,7 +35,9 @@ type ShortURLStatus struct {
 
 // NewShortURLStatus creates a new ShortURLStatus object.
 func NewShortURLStatus() *ShortURLStatus {
-	return &ShortURLStatus{}
+	return &ShortURLStatus{
+		UseCount: 0,
+	}
 }
 
 // +k8s:openapi-gen=true
@@ -3,9 +3,15 @@
 export interface Spec {
 	// The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel
 	path: string;
+	// The unix time in seconds after which the short URL no longer resolves, 0 if it never expires
+	expiresAt: number;
+	// The number of times the short URL can be used, 0 if it is unlimited
+	maxUses: number;
 }
 
 export const defaultSpec = (): Spec => ({
 	path: "",
+	expiresAt: 0,
+	maxUses: 0,
 });
 
@@ -69,6 +69,9 @@ export interface DataLink<T extends DataQuery = any> {
 export enum SupportedTransformationType {
   Regex = 'regex',
   Logfmt = 'logfmt',
+  Json = 'json',
+  Label = 'label',
+  Template = 'template',
 }
 
 /** @internal */
@@ -4,6 +4,7 @@ import { DataQuery, DataQueryRequest, DataQueryResponse } from '@grafana/data';
 
 import { config } from '../config';
 import { getBackendSrv } from '../services/backendSrv';
+import { getTemplateSrv } from '../services/templateSrv';
 
 import { BackendDataSourceResponse, toDataQueryResponse } from './queryResponse';
 
@@ -30,6 +31,7 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       to: toRange.valueOf().toString(),
       timezone: request.timezone,
     },
+    variables: getVariableValues(),
   };
 
   return getBackendSrv()
@@ -48,3 +50,17 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       })
     );
 }
+
+/**
+ * Returns the selected values of the template variables. The backend only interpolates the variables that viewers
+ * of the public dashboard are allowed to change, and rejects values that are not allowed.
+ */
+function getVariableValues(): Record<string, string> {
+  const values: Record<string, string> = {};
+  for (const variable of getTemplateSrv().getVariables()) {
+    if ('current' in variable && typeof variable.current.value === 'string') {
+      values[variable.name] = variable.current.value;
+    }
+  }
+  return values;
+}
@@ -13,12 +13,16 @@ import (
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginaccesscontrol"
+	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/tsdb/grafanads"
 )
 
 // API related actions
 const (
 	ActionProvisioningReload = "provisioning:reload"
+
+	ActionNotificationOutboxRead  = "notifications.outbox:read"
+	ActionNotificationOutboxWrite = "notifications.outbox:write"
 )
 
 // API related scopes
@@ -56,6 +60,39 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{ac.RoleGrafanaAdmin},
 	}
 
+	notificationOutboxReaderRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:notifications.outbox:reader",
+			DisplayName: "Outbox reader",
+			Description: "List the queued and failed outgoing emails and webhooks.",
+			Group:       "Notifications",
+			Permissions: []ac.Permission{
+				{
+					Action: ActionNotificationOutboxRead,
+				},
+			},
+		},
+		Grants: []string{ac.RoleGrafanaAdmin},
+	}
+
+	notificationOutboxWriterRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:notifications.outbox:writer",
+			DisplayName: "Outbox writer",
+			Description: "List, retry and delete the queued and failed outgoing emails and webhooks.",
+			Group:       "Notifications",
+			Permissions: []ac.Permission{
+				{
+					Action: ActionNotificationOutboxRead,
+				},
+				{
+					Action: ActionNotificationOutboxWrite,
+				},
+			},
+		},
+		Grants: []string{ac.RoleGrafanaAdmin},
+	}
+
 	datasourcesExplorerRole := ac.RoleRegistration{
 		Role: ac.RoleDTO{
 			Name:        "fixed:datasources:explorer",
@@ -644,7 +681,20 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{string(org.RoleViewer)},
 	}
 
-	roles := []ac.RoleRegistration{provisioningWriterRole, datasourcesReaderRole, builtInDatasourceReader, datasourcesWriterRole,
+	shortURLsRevokerRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:shorturls:revoker",
+			DisplayName: "Revoker",
+			Description: "Revoke short URLs created by other users",
+			Group:       "Short URLs",
+			Permissions: []ac.Permission{
+				{Action: shorturls.ActionRevoke, Scope: shorturls.ScopeAll},
+			},
+		},
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	roles := []ac.RoleRegistration{provisioningWriterRole, notificationOutboxReaderRole, notificationOutboxWriterRole, datasourcesReaderRole, builtInDatasourceReader, datasourcesWriterRole,
 		datasourcesIdReaderRole, datasourcesCreatorRole, orgReaderRole, orgWriterRole,
 		orgMaintainerRole, teamsCreatorRole, teamsWriterRole, teamsReaderRole, datasourcesExplorerRole,
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
@@ -652,7 +702,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		foldersCreatorRole, foldersReaderRole, generalFolderReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
 		publicDashboardsWriterRole, featuremgmtReaderRole, featuremgmtWriterRole, libraryPanelsCreatorRole,
 		libraryPanelsReaderRole, libraryPanelsWriterRole, libraryPanelsGeneralReaderRole, libraryPanelsGeneralWriterRole,
-		snapshotsCreatorRole, snapshotsDeleterRole, snapshotsReaderRole}
+		snapshotsCreatorRole, snapshotsDeleterRole, snapshotsReaderRole, shortURLsRevokerRole}
 
 	if hs.Features.IsEnabled(context.Background(), featuremgmt.FlagAnnotationPermissionUpdate) {
 		allAnnotationsReaderRole := ac.RoleRegistration{
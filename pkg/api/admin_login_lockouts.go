@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /admin/login-lockouts admin_login_lockouts adminGetLoginLockouts
+//
+// Get the usernames, IP addresses and subnets that are currently locked out after too many failed login attempts.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:read` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminGetLoginLockoutsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminGetLoginLockouts(c *contextmodel.ReqContext) response.Response {
+	lockouts, err := hs.loginAttemptService.GetLockouts(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get login lockouts", err)
+	}
+
+	result := make([]dtos.LoginLockout, 0, len(lockouts))
+	for _, lockout := range lockouts {
+		result = append(result, dtos.LoginLockout{
+			Id:          lockout.Id,
+			Kind:        string(lockout.Kind),
+			Subject:     lockout.Subject,
+			Lockouts:    lockout.Lockouts,
+			Attempts:    lockout.Attempts,
+			LockedUntil: time.Unix(lockout.LockedUntil, 0),
+		})
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route DELETE /admin/login-lockouts/{lockout_id} admin_login_lockouts adminDeleteLoginLockout
+//
+// Clear a login lockout and the failed login attempts that caused it, the username, IP address or subnet can log in again right away.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:write` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminDeleteLoginLockout(c *contextmodel.ReqContext) response.Response {
+	lockoutID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := hs.loginAttemptService.DeleteLockout(c.Req.Context(), lockoutID); err != nil {
+		if errors.Is(err, loginattempt.ErrLockoutNotFound) {
+			return response.Error(http.StatusNotFound, "Login lockout not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to delete login lockout", err)
+	}
+
+	return response.Success("Login lockout deleted")
+}
+
+// swagger:parameters adminDeleteLoginLockout
+type AdminDeleteLoginLockoutParams struct {
+	// in:path
+	// required:true
+	LockoutID int64 `json:"lockout_id"`
+}
+
+// swagger:response adminGetLoginLockoutsResponse
+type AdminGetLoginLockoutsResponse struct {
+	// in:body
+	Body []dtos.LoginLockout `json:"body"`
+}
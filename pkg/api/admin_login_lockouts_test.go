@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAdminAPI_LoginLockouts(t *testing.T) {
+	lockedUntil := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	readPermissions := []accesscontrol.Permission{{Action: accesscontrol.ActionUsersRead, Scope: accesscontrol.ScopeGlobalUsersAll}}
+	writePermissions := []accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: accesscontrol.ScopeGlobalUsersAll}}
+
+	setup := func(t *testing.T, loginAttempts *loginattempttest.MockLoginAttemptService) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.loginAttemptService = loginAttempts
+		})
+	}
+
+	t.Run("should list the current lockouts", func(t *testing.T) {
+		server := setup(t, &loginattempttest.MockLoginAttemptService{
+			ExpectedLockouts: []*loginattempt.LoginLockout{
+				{Id: 1, Kind: loginattempt.LockoutKindUsername, Subject: "admin", Lockouts: 2, Attempts: 5, LockedUntil: lockedUntil.Unix()},
+			},
+		})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/login-lockouts"), userWithPermissions(accesscontrol.GlobalOrgID, readPermissions)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var lockouts []dtos.LoginLockout
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&lockouts))
+		require.NoError(t, res.Body.Close())
+		require.Len(t, lockouts, 1)
+		assert.Equal(t, "username", lockouts[0].Kind)
+		assert.Equal(t, "admin", lockouts[0].Subject)
+		assert.Equal(t, int64(2), lockouts[0].Lockouts)
+		assert.True(t, lockedUntil.Equal(lockouts[0].LockedUntil))
+	})
+
+	t.Run("should not list the lockouts without permissions", func(t *testing.T) {
+		server := setup(t, &loginattempttest.MockLoginAttemptService{})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/login-lockouts"), userWithPermissions(accesscontrol.GlobalOrgID, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should clear a lockout", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{}
+		server := setup(t, loginAttempts)
+
+		req := server.NewRequest(http.MethodDelete, "/api/admin/login-lockouts/1", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, writePermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.True(t, loginAttempts.DeleteLockoutCalled)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return not found for an unknown lockout", func(t *testing.T) {
+		server := setup(t, &loginattempttest.MockLoginAttemptService{ExpectedErr: loginattempt.ErrLockoutNotFound})
+
+		req := server.NewRequest(http.MethodDelete, "/api/admin/login-lockouts/1", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, writePermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not clear a lockout with read permissions only", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{}
+		server := setup(t, loginAttempts)
+
+		req := server.NewRequest(http.MethodDelete, "/api/admin/login-lockouts/1", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, readPermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		assert.False(t, loginAttempts.DeleteLockoutCalled)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultOutboxLimit = 100
+	maxOutboxLimit     = 1000
+)
+
+// swagger:route GET /admin/notifications/outbox admin_notification_outbox adminGetNotificationOutbox
+//
+// Get the outgoing emails and webhooks that are waiting to be sent, or that failed to be sent after all attempts, newest first.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `notifications.outbox:read`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminGetNotificationOutboxResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminGetNotificationOutbox(c *contextmodel.ReqContext) response.Response {
+	query := notifications.GetOutboxMessagesQuery{
+		Status: notifications.OutboxMessageStatus(c.Query("status")),
+		Limit:  c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", notifications.OutboxMessageStatusPending, notifications.OutboxMessageStatusFailed:
+	default:
+		return response.Error(http.StatusBadRequest, "status must be pending or failed", nil)
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultOutboxLimit
+	}
+	query.Limit = min(query.Limit, maxOutboxLimit)
+
+	messages, err := hs.NotificationService.GetOutboxMessages(c.Req.Context(), &query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get outbox messages", err)
+	}
+
+	result := make([]dtos.NotificationOutboxMessage, 0, len(messages))
+	for _, m := range messages {
+		result = append(result, dtos.NotificationOutboxMessage{
+			Id:          m.ID,
+			Kind:        string(m.Kind),
+			Target:      m.Target,
+			Subject:     m.Subject,
+			Status:      string(m.Status),
+			Attempts:    m.Attempts,
+			LastError:   m.LastError,
+			NextAttempt: m.NextAttempt,
+			Created:     m.Created,
+			Updated:     m.Updated,
+		})
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /admin/notifications/outbox/{message_id}/retry admin_notification_outbox adminRetryNotificationOutboxMessage
+//
+// Retry a failed outgoing email or webhook right away, with a fresh set of attempts.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `notifications.outbox:write`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminRetryNotificationOutboxMessage(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := hs.NotificationService.RetryOutboxMessage(c.Req.Context(), id); err != nil {
+		if errors.Is(err, notifications.ErrOutboxMessageNotFound) {
+			return response.Error(http.StatusNotFound, "Failed outbox message not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to retry outbox message", err)
+	}
+
+	return response.Success("Outbox message queued for retry")
+}
+
+// swagger:route DELETE /admin/notifications/outbox/{message_id} admin_notification_outbox adminDeleteNotificationOutboxMessage
+//
+// Delete an outgoing email or webhook, it is not sent or retried anymore.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `notifications.outbox:write`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminDeleteNotificationOutboxMessage(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := hs.NotificationService.DeleteOutboxMessage(c.Req.Context(), id); err != nil {
+		if errors.Is(err, notifications.ErrOutboxMessageNotFound) {
+			return response.Error(http.StatusNotFound, "Outbox message not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to delete outbox message", err)
+	}
+
+	return response.Success("Outbox message deleted")
+}
+
+// swagger:parameters adminGetNotificationOutbox
+type AdminGetNotificationOutboxParams struct {
+	// Only return the messages with this status, pending or failed.
+	// in:query
+	// required:false
+	Status string `json:"status"`
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters adminRetryNotificationOutboxMessage adminDeleteNotificationOutboxMessage
+type AdminNotificationOutboxMessageParams struct {
+	// in:path
+	// required:true
+	MessageID int64 `json:"message_id"`
+}
+
+// swagger:response adminGetNotificationOutboxResponse
+type AdminGetNotificationOutboxResponse struct {
+	// in:body
+	Body []dtos.NotificationOutboxMessage `json:"body"`
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAdminAPI_NotificationOutbox(t *testing.T) {
+	nextAttempt := time.Now().Add(time.Minute).Truncate(time.Second)
+	readPermissions := []accesscontrol.Permission{{Action: ActionNotificationOutboxRead}}
+	writePermissions := []accesscontrol.Permission{{Action: ActionNotificationOutboxWrite}}
+
+	setup := func(t *testing.T, ns *notifications.NotificationServiceMock) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.NotificationService = ns
+		})
+	}
+
+	t.Run("should list the outbox messages", func(t *testing.T) {
+		server := setup(t, &notifications.NotificationServiceMock{
+			ExpectedOutboxMessages: []*notifications.OutboxMessage{
+				{ID: 1, Kind: notifications.OutboxMessageKindEmail, Target: "admin@example.com", Subject: "Reset password", Status: notifications.OutboxMessageStatusPending, Attempts: 2, LastError: "connection refused", NextAttempt: nextAttempt},
+			},
+		})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/notifications/outbox?status=pending"), userWithPermissions(accesscontrol.GlobalOrgID, readPermissions)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var messages []dtos.NotificationOutboxMessage
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&messages))
+		require.NoError(t, res.Body.Close())
+		require.Len(t, messages, 1)
+		assert.Equal(t, "email", messages[0].Kind)
+		assert.Equal(t, "admin@example.com", messages[0].Target)
+		assert.Equal(t, 2, messages[0].Attempts)
+		assert.Equal(t, "connection refused", messages[0].LastError)
+		assert.True(t, nextAttempt.Equal(messages[0].NextAttempt))
+	})
+
+	t.Run("should reject an unknown status", func(t *testing.T) {
+		server := setup(t, &notifications.NotificationServiceMock{})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/notifications/outbox?status=sent"), userWithPermissions(accesscontrol.GlobalOrgID, readPermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not list the outbox messages without permissions", func(t *testing.T) {
+		server := setup(t, &notifications.NotificationServiceMock{})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/notifications/outbox"), userWithPermissions(accesscontrol.GlobalOrgID, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should retry a failed message", func(t *testing.T) {
+		ns := &notifications.NotificationServiceMock{}
+		server := setup(t, ns)
+
+		req := server.NewRequest(http.MethodPost, "/api/admin/notifications/outbox/3/retry", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, writePermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, int64(3), ns.RetriedOutboxMessage)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not retry without permissions", func(t *testing.T) {
+		ns := &notifications.NotificationServiceMock{}
+		server := setup(t, ns)
+
+		req := server.NewRequest(http.MethodPost, "/api/admin/notifications/outbox/3/retry", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, readPermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		assert.Zero(t, ns.RetriedOutboxMessage)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should delete a message", func(t *testing.T) {
+		ns := &notifications.NotificationServiceMock{}
+		server := setup(t, ns)
+
+		req := server.NewRequest(http.MethodDelete, "/api/admin/notifications/outbox/4", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, writePermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, int64(4), ns.DeletedOutboxMessage)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return not found for an unknown message", func(t *testing.T) {
+		server := setup(t, &notifications.NotificationServiceMock{ShouldError: notifications.ErrOutboxMessageNotFound})
+
+		req := server.NewRequest(http.MethodDelete, "/api/admin/notifications/outbox/4", nil)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(accesscontrol.GlobalOrgID, writePermissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
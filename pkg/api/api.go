@@ -224,6 +224,19 @@ func (hs *HTTPServer) registerRoutes() {
 		r.Post("/api/login/passwordless/authenticate", requestmeta.SetOwner(requestmeta.TeamAuth), quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginPasswordless))
 	}
 
+	if hs.Cfg.AuthMFA.Enabled && !hs.Cfg.DisableLoginForm {
+		r.Post("/api/login/mfa", requestmeta.SetOwner(requestmeta.TeamAuth), quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginMFA))
+		if hs.Cfg.AuthMFA.EnforceForAdmins {
+			r.Post("/api/login/mfa/enroll", requestmeta.SetOwner(requestmeta.TeamAuth), routing.Wrap(hs.LoginMFAEnroll))
+		}
+	}
+
+	// device authorization grant for devices that can't complete a browser redirect
+	if hs.Cfg.OAuthDeviceLoginEnabled {
+		r.Post("/api/login/device/authorize", requestmeta.SetOwner(requestmeta.TeamAuth), quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.StartOAuthDeviceLogin))
+		r.Post("/api/login/device/token", requestmeta.SetOwner(requestmeta.TeamAuth), quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginOAuthDevice))
+	}
+
 	// invited
 	r.Get("/api/user/invite/:code", routing.Wrap(hs.GetInviteInfoByCode))
 	r.Post("/api/user/invite/complete", routing.Wrap(hs.CompleteInvite))
@@ -299,6 +312,19 @@ func (hs *HTTPServer) registerRoutes() {
 
 			userRoute.Get("/auth-tokens", requestmeta.SetOwner(requestmeta.TeamAuth), routing.Wrap(hs.GetUserAuthTokens))
 			userRoute.Post("/revoke-auth-token", requestmeta.SetOwner(requestmeta.TeamAuth), routing.Wrap(hs.RevokeUserAuthToken))
+
+			if hs.Cfg.AuthMFA.Enabled {
+				userRoute.Group("/mfa", func(mfaRoute routing.RouteRegister) {
+					mfaRoute.Get("/factors", routing.Wrap(hs.GetUserMFAFactors))
+					mfaRoute.Post("/challenge", routing.Wrap(hs.CreateUserMFAChallenge))
+					mfaRoute.Delete("/factors/:factorId", routing.Wrap(hs.DeleteUserMFAFactor))
+					mfaRoute.Post("/totp", routing.Wrap(hs.EnrollUserTOTP))
+					mfaRoute.Post("/totp/verify", routing.Wrap(hs.VerifyUserTOTP))
+					mfaRoute.Post("/webauthn/register/begin", routing.Wrap(hs.BeginUserWebAuthnRegistration))
+					mfaRoute.Post("/webauthn/register/finish", routing.Wrap(hs.FinishUserWebAuthnRegistration))
+					mfaRoute.Post("/recovery-codes", routing.Wrap(hs.RegenerateUserRecoveryCodes))
+				}, requestmeta.SetOwner(requestmeta.TeamAuth))
+			}
 		}, reqSignedInNoAnonymous)
 
 		apiRoute.Group("/users", func(usersRoute routing.RouteRegister) {
@@ -569,6 +595,13 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/plugins/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+
+		adminRoute.Get("/login-lockouts", authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersRead, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminGetLoginLockouts))
+		adminRoute.Delete("/login-lockouts/:id", authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersWrite, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminDeleteLoginLockout))
+
+		adminRoute.Get("/notifications/outbox", authorize(ac.EvalPermission(ActionNotificationOutboxRead)), routing.Wrap(hs.AdminGetNotificationOutbox))
+		adminRoute.Post("/notifications/outbox/:id/retry", authorize(ac.EvalPermission(ActionNotificationOutboxWrite)), routing.Wrap(hs.AdminRetryNotificationOutboxMessage))
+		adminRoute.Delete("/notifications/outbox/:id", authorize(ac.EvalPermission(ActionNotificationOutboxWrite)), routing.Wrap(hs.AdminDeleteNotificationOutboxMessage))
 	}, reqSignedIn)
 
 	// Administering users
@@ -587,6 +620,11 @@ func (hs *HTTPServer) registerRoutes() {
 		adminUserRoute.Post("/:id/logout", userUIDResolver, authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersLogout, userIDScope)), routing.Wrap(hs.AdminLogoutUser))
 		adminUserRoute.Get("/:id/auth-tokens", userUIDResolver, authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersAuthTokenList, userIDScope)), routing.Wrap(hs.AdminGetUserAuthTokens))
 		adminUserRoute.Post("/:id/revoke-auth-token", userUIDResolver, authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersAuthTokenUpdate, userIDScope)), routing.Wrap(hs.AdminRevokeUserAuthToken))
+
+		if hs.Cfg.AuthMFA.Enabled {
+			adminUserRoute.Get("/:id/mfa/factors", userUIDResolver, authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersRead, userIDScope)), routing.Wrap(hs.AdminGetUserMFAFactors))
+			adminUserRoute.Delete("/:id/mfa/factors", userUIDResolver, authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionUsersWrite, userIDScope)), routing.Wrap(hs.AdminResetUserMFAFactors))
+		}
 	}, reqSignedIn)
 
 	// rendering
@@ -603,6 +641,8 @@ func (hs *HTTPServer) registerRoutes() {
 
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrCreate, hs.getCreatedSnapshotHandler())
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
+	r.Post("/api/snapshots/:key/export", reqSignedIn, routing.Wrap(hs.ExportDashboardSnapshot))
+	r.Post("/api/snapshots-import", reqSignedIn, authorize(ac.EvalPermission(dashboards.ActionSnapshotsCreate)), routing.Wrap(hs.ImportDashboardSnapshot))
 	r.Delete("/api/snapshots/:key", authorize(ac.EvalPermission(dashboards.ActionSnapshotsDelete)), routing.Wrap(hs.DeleteDashboardSnapshot))
 
 	// Snapshots delete for public mode or using the deleteKey
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errhttp"
 	"github.com/grafana/grafana/pkg/web"
@@ -142,6 +143,86 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *contextmodel.ReqContext) response.
 	return response.JSON(http.StatusOK, dto).SetHeader("Cache-Control", "public, max-age=3600")
 }
 
+// swagger:route POST /snapshots/{key}/export snapshots exportDashboardSnapshot
+//
+// Export snapshot as a bundle.
+//
+// Returns a file with the snapshot, its data and its metadata that can be imported in another Grafana instance. The bundle is encrypted if a passphrase is given.
+//
+// Responses:
+// 200: exportDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) ExportDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.ExportBundleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	key := web.Params(c.Req)[":key"]
+	snapshot, err := hs.dashboardsnapshotsService.GetDashboardSnapshot(c.Req.Context(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: key})
+	if err != nil {
+		return response.Err(err)
+	}
+	if snapshot.OrgID != c.GetOrgID() || snapshot.Expires.Before(time.Now()) {
+		return response.Error(http.StatusNotFound, "Dashboard snapshot not found", nil)
+	}
+
+	bundle, err := dashboardsnapshots.ExportBundle(snapshot, setting.ToAbsUrl("dashboard/snapshot/"+snapshot.Key), cmd.Passphrase)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export dashboard snapshot", err)
+	}
+
+	return response.JSONDownload(http.StatusOK, bundle, snapshot.Key+".snapshot.json")
+}
+
+// swagger:route POST /snapshots-import snapshots importDashboardSnapshot
+//
+// Import snapshot bundle.
+//
+// Creates a snapshot from a bundle exported from another Grafana instance. The snapshot keeps its expiry, its original URL and, if it is not used by another snapshot, its key.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ImportDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.ImportBundleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	cmd.OrgID = c.GetOrgID()
+	cmd.UserID = c.UserID
+
+	snapshot, err := dashboardsnapshots.ImportBundle(c.Req.Context(), &cmd, hs.dashboardsnapshotsService)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to import dashboard snapshot", err)
+	}
+
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, dashboardsnapshot.DashboardCreateResponse{
+		Key:       snapshot.Key,
+		DeleteKey: snapshot.DeleteKey,
+		URL:       setting.ToAbsUrl("dashboard/snapshot/" + snapshot.Key),
+		DeleteURL: setting.ToAbsUrl("api/snapshots-delete/" + snapshot.DeleteKey),
+	})
+}
+
 // swagger:route GET /snapshots-delete/{deleteKey} snapshots deleteDashboardSnapshotByDeleteKey
 //
 // Delete Snapshot by deleteKey.
@@ -331,6 +412,21 @@ type DeleteDashboardSnapshotParams struct {
 	Key string `json:"key"`
 }
 
+// swagger:parameters exportDashboardSnapshot
+type ExportDashboardSnapshotParams struct {
+	// in:path
+	Key string `json:"key"`
+	// in:body
+	Body dashboardsnapshots.ExportBundleCommand `json:"body"`
+}
+
+// swagger:parameters importDashboardSnapshot
+type ImportDashboardSnapshotParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.ImportBundleCommand `json:"body"`
+}
+
 // swagger:parameters deleteDashboardSnapshotByDeleteKey
 type DeleteSnapshotByDeleteKeyParams struct {
 	// in:path
@@ -358,6 +454,12 @@ type SearchDashboardSnapshotsResponse struct {
 	Body []*dashboardsnapshots.DashboardSnapshotDTO `json:"body"`
 }
 
+// swagger:response exportDashboardSnapshotResponse
+type ExportDashboardSnapshotResponse struct {
+	// in:body
+	Body dashboardsnapshots.SnapshotBundle `json:"body"`
+}
+
 // swagger:response getDashboardSnapshotResponse
 type GetDashboardSnapshotResponse DashboardResponse
 
@@ -0,0 +1,12 @@
+package dtos
+
+import "time"
+
+type LoginLockout struct {
+	Id          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	Lockouts    int64     `json:"lockouts"`
+	Attempts    int64     `json:"attempts"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
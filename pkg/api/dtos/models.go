@@ -27,6 +27,10 @@ type LoginCommand struct {
 	Remember bool   `json:"remember"`
 }
 
+type LoginMFAEnrollForm struct {
+	Token string `json:"token" binding:"Required"`
+}
+
 type CurrentUser struct {
 	IsSignedIn                 bool               `json:"isSignedIn"`
 	Id                         int64              `json:"id"`
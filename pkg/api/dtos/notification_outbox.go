@@ -0,0 +1,16 @@
+package dtos
+
+import "time"
+
+type NotificationOutboxMessage struct {
+	Id          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Target      string    `json:"target"`
+	Subject     string    `json:"subject,omitempty"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"lastError,omitempty"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
@@ -8,4 +8,42 @@ type ShortURL struct {
 type CreateShortURLCmd struct {
 	Path string `json:"path"`
 	UID  string `json:"uid,omitempty"`
+	// ExpiresAt is the unix time in seconds after which the short URL no longer resolves
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	// MaxUses is the number of times the short URL can be resolved
+	MaxUses int64 `json:"maxUses,omitempty"`
+	// Password that is required to resolve the short URL
+	Password string `json:"password,omitempty"`
+}
+
+type ResolveShortURLCmd struct {
+	Password string `json:"password"`
+}
+
+type ResolvedShortURL struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// ShortURLInfo describes a short URL in the lists of short URLs
+type ShortURLInfo struct {
+	UID               string `json:"uid"`
+	URL               string `json:"url"`
+	OrgID             int64  `json:"orgId"`
+	Path              string `json:"path"`
+	DashboardUID      string `json:"dashboardUid,omitempty"`
+	CreatedBy         int64  `json:"createdBy"`
+	CreatedAt         int64  `json:"createdAt"`
+	LastSeenAt        int64  `json:"lastSeenAt"`
+	ExpiresAt         int64  `json:"expiresAt,omitempty"`
+	MaxUses           int64  `json:"maxUses,omitempty"`
+	UseCount          int64  `json:"useCount"`
+	PasswordProtected bool   `json:"passwordProtected"`
+}
+
+type SearchShortURLsResult struct {
+	ShortURLs  []*ShortURLInfo `json:"shortUrls"`
+	TotalCount int64           `json:"totalCount"`
+	Page       int             `json:"page"`
+	PerPage    int             `json:"perPage"`
 }
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
@@ -28,6 +29,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 const (
@@ -268,6 +270,38 @@ func (hs *HTTPServer) LoginPasswordless(c *contextmodel.ReqContext) response.Res
 	return authn.HandleLoginResponse(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo, hs.Features)
 }
 
+// LoginMFA completes a login of a user with a second factor with the answer to the
+// challenge returned by LoginPost.
+func (hs *HTTPServer) LoginMFA(c *contextmodel.ReqContext) response.Response {
+	identity, err := hs.authnService.Login(c.Req.Context(), authn.ClientMFA, &authn.Request{HTTPRequest: c.Req})
+	if err != nil {
+		tokenErr := &auth.CreateTokenErr{}
+		if errors.As(err, &tokenErr) {
+			return response.Error(tokenErr.StatusCode, tokenErr.ExternalErr, tokenErr.InternalErr)
+		}
+		return response.Err(err)
+	}
+
+	metrics.MApiLoginPost.Inc()
+	return authn.HandleLoginResponse(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo, hs.Features)
+}
+
+// LoginMFAEnroll starts the enrollment of an authenticator app for a user that has to
+// enroll a second factor before logging in. The login is completed with LoginMFA and a
+// code generated by the app.
+func (hs *HTTPServer) LoginMFAEnroll(c *contextmodel.ReqContext) response.Response {
+	form := dtos.LoginMFAEnrollForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	enrollment, err := hs.mfaService.EnrollChallengeTOTP(c.Req.Context(), form.Token)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enroll authenticator app", err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
 func (hs *HTTPServer) StartPasswordless(c *contextmodel.ReqContext) {
 	redirect, err := hs.authnService.RedirectURL(c.Req.Context(), authn.ClientPasswordless, &authn.Request{HTTPRequest: c.Req})
 	if err != nil {
@@ -1,12 +1,23 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/middleware/cookies"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -62,3 +73,91 @@ func (hs *HTTPServer) OAuthLogin(reqCtx *contextmodel.ReqContext) {
 	metrics.MApiLoginOAuth.Inc()
 	authn.HandleLoginRedirect(reqCtx.Req, reqCtx.Resp, hs.Cfg, identity, hs.ValidateRedirectTo, hs.Features)
 }
+
+// OAuthDeviceAuthorization is returned to a device starting a login with the device authorization grant.
+// The device shows the user code and verification uri to the user and polls for the session at the interval.
+type OAuthDeviceAuthorization struct {
+	DeviceCode              string `json:"deviceCode"`
+	UserCode                string `json:"userCode"`
+	VerificationURI         string `json:"verificationUri"`
+	VerificationURIComplete string `json:"verificationUriComplete,omitempty"`
+	ExpiresIn               int64  `json:"expiresIn,omitempty"`
+	Interval                int64  `json:"interval"`
+}
+
+// StartOAuthDeviceLogin starts a device authorization with the generic OAuth provider.
+func (hs *HTTPServer) StartOAuthDeviceLogin(c *contextmodel.ReqContext) response.Response {
+	redirect, err := hs.authnService.RedirectURL(c.Req.Context(), authn.ClientOAuthDevice, &authn.Request{HTTPRequest: c.Req})
+	if err != nil {
+		return response.Err(err)
+	}
+
+	result := OAuthDeviceAuthorization{
+		DeviceCode:              redirect.Extra[authn.KeyOAuthDeviceCode],
+		UserCode:                redirect.Extra[authn.KeyOAuthUserCode],
+		VerificationURI:         redirect.Extra[authn.KeyOAuthVerificationURI],
+		VerificationURIComplete: redirect.Extra[authn.KeyOAuthVerificationURIComplete],
+	}
+	result.ExpiresIn, _ = strconv.ParseInt(redirect.Extra[authn.KeyOAuthExpiresIn], 10, 64)
+	result.Interval, _ = strconv.ParseInt(redirect.Extra[authn.KeyOAuthInterval], 10, 64)
+	if result.Interval <= 0 {
+		// https://datatracker.ietf.org/doc/html/rfc8628#section-3.2
+		result.Interval = 5
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// LoginOAuthDevice creates a session for the device once the user has approved it, or a service account
+// token when the device asks for one. Until then the device gets an error with the
+// auth.oauth.device.authorization-pending message id.
+func (hs *HTTPServer) LoginOAuthDevice(c *contextmodel.ReqContext) response.Response {
+	r := &authn.Request{HTTPRequest: c.Req}
+	identity, err := hs.authnService.Login(c.Req.Context(), authn.ClientOAuthDevice, r)
+	if err != nil {
+		tokenErr := &auth.CreateTokenErr{}
+		if errors.As(err, &tokenErr) {
+			return response.Error(tokenErr.StatusCode, tokenErr.ExternalErr, tokenErr.InternalErr)
+		}
+		return response.Err(err)
+	}
+
+	metrics.MApiLoginOAuth.Inc()
+	if r.GetMeta(authn.MetaKeyOAuthDeviceServiceAccount) != "" {
+		return hs.createOAuthDeviceToken(c, identity, r)
+	}
+	return authn.HandleLoginResponse(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo, hs.Features)
+}
+
+// createOAuthDeviceToken creates a token of the service account the device asked for, on behalf of the
+// user who approved the device. The device doesn't get a session, the one created by the login is revoked.
+func (hs *HTTPServer) createOAuthDeviceToken(c *contextmodel.ReqContext, identity *authn.Identity, r *authn.Request) response.Response {
+	ctx := c.Req.Context()
+	defer func() {
+		if err := hs.AuthTokenService.RevokeToken(ctx, identity.SessionToken, false); err != nil {
+			hs.log.FromContext(ctx).Warn("Failed to revoke the session of a device login", "error", err)
+		}
+	}()
+
+	saID, err := strconv.ParseInt(r.GetMeta(authn.MetaKeyOAuthDeviceServiceAccount), 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service account ID is invalid", err)
+	}
+	secondsToLive, _ := strconv.ParseInt(r.GetMeta(authn.MetaKeyOAuthDeviceSecondsToLive), 10, 64)
+
+	evaluator := ac.EvalPermission(serviceaccounts.ActionWrite, ac.Scope("serviceaccounts", "id", strconv.FormatInt(saID, 10)))
+	if ok, err := hs.AccessControl.Evaluate(ctx, identity, evaluator); err != nil || !ok {
+		return response.Error(http.StatusForbidden, "You are not allowed to create tokens for the service account", err)
+	}
+
+	token, err := hs.serviceAccountsService.CreateServiceAccountToken(ctx, saID, &serviceaccounts.AddServiceAccountTokenCommand{
+		Name:          fmt.Sprintf("device-%s-%d", identity.GetLogin(), time.Now().Unix()),
+		OrgId:         identity.GetOrgID(),
+		SecondsToLive: secondsToLive,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add service account token", err)
+	}
+
+	return response.JSON(http.StatusOK, &dtos.NewApiKeyResult{ID: token.ID, Name: token.Name, Key: token.Secret})
+}
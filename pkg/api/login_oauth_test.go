@@ -1,18 +1,28 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models/usertoken"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	satests "github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -203,3 +213,111 @@ func TestOAuthLogin_Error(t *testing.T) {
 	assert.Equal(t, loginErrorCookieName, errCookie.Name)
 	require.NoError(t, res.Body.Close())
 }
+
+func TestOAuthDeviceLogin_Routes(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("device login enabled: %t", enabled), func(t *testing.T) {
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.Cfg.OAuthDeviceLoginEnabled = enabled
+				hs.authnService = &authntest.FakeService{ExpectedErr: errors.New("some error")}
+			})
+
+			res, err := server.Send(server.NewPostRequest("/api/login/device/token", strings.NewReader(`{"deviceCode":"code"}`)))
+			require.NoError(t, err)
+			if enabled {
+				assert.NotEqual(t, http.StatusNotFound, res.StatusCode)
+			} else {
+				assert.Equal(t, http.StatusNotFound, res.StatusCode)
+			}
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+type fakeDeviceLoginService struct {
+	authntest.FakeService
+	serviceAccountID string
+	secondsToLive    string
+}
+
+func (f *fakeDeviceLoginService) Login(ctx context.Context, client string, r *authn.Request) (*authn.Identity, error) {
+	r.SetMeta(authn.MetaKeyOAuthDeviceServiceAccount, f.serviceAccountID)
+	r.SetMeta(authn.MetaKeyOAuthDeviceSecondsToLive, f.secondsToLive)
+	return f.FakeService.Login(ctx, client, r)
+}
+
+func TestOAuthDeviceLogin_CreateToken(t *testing.T) {
+	type testCase struct {
+		desc          string
+		permissions   map[string][]string
+		expectedErr   error
+		expectedToken *serviceaccounts.NewServiceAccountToken
+		expectedCode  int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should not create a token without permission to write the service account",
+			permissions:  map[string][]string{serviceaccounts.ActionWrite: {"serviceaccounts:id:2"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not create a token that exceeds the expiration limit",
+			permissions:  map[string][]string{serviceaccounts.ActionWrite: {"serviceaccounts:id:1"}},
+			expectedErr:  serviceaccounts.ErrTokenExpirationDateLimit.Errorf(""),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:          "should create a token of the service account",
+			permissions:   map[string][]string{serviceaccounts.ActionWrite: {"serviceaccounts:id:1"}},
+			expectedToken: &serviceaccounts.NewServiceAccountToken{ID: 1, Name: "device-test", Secret: "glsa_secret"},
+			expectedCode:  http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var revoked bool
+			tokenService := authtest.NewFakeUserAuthTokenService()
+			tokenService.RevokeTokenProvider = func(ctx context.Context, token *usertoken.UserToken, soft bool) error {
+				revoked = true
+				return nil
+			}
+
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.Cfg.OAuthDeviceLoginEnabled = true
+				hs.AuthTokenService = tokenService
+				hs.authnService = &fakeDeviceLoginService{
+					FakeService: authntest.FakeService{ExpectedIdentity: &authn.Identity{
+						ID:           "1",
+						Type:         claims.TypeUser,
+						OrgID:        1,
+						Login:        "test",
+						SessionToken: &usertoken.UserToken{Id: 1},
+						Permissions:  map[int64]map[string][]string{1: tt.permissions},
+					}},
+					serviceAccountID: "1",
+					secondsToLive:    "3600",
+				}
+				hs.serviceAccountsService = &satests.FakeServiceAccountService{
+					ExpectedErr:                 tt.expectedErr,
+					ExpectedServiceAccountToken: tt.expectedToken,
+				}
+			})
+
+			res, err := server.Send(server.NewPostRequest("/api/login/device/token", strings.NewReader(`{"deviceCode":"code"}`)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			assert.True(t, revoked)
+
+			if tt.expectedToken != nil {
+				result := dtos.NewApiKeyResult{}
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+				assert.Equal(t, tt.expectedToken.Secret, result.Key)
+			}
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
@@ -3,9 +3,11 @@ package api
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
@@ -24,9 +26,9 @@ import (
 func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 	// Register the actual handlers
 	// TODO: remove kubernetesPlaylists feature flag
+	// Use k8s client to implement legacy API
+	handler := newPlaylistK8sHandler(hs)
 	apiRoute.Group("/playlists", func(playlistRoute routing.RouteRegister) {
-		// Use k8s client to implement legacy API
-		handler := newPlaylistK8sHandler(hs)
 		playlistRoute.Get("/", handler.searchPlaylists)
 		playlistRoute.Get("/:uid", handler.getPlaylist)
 		playlistRoute.Get("/:uid/items", handler.getPlaylistItems)
@@ -34,6 +36,8 @@ func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 		playlistRoute.Put("/:uid", handler.updatePlaylist)
 		playlistRoute.Post("/", handler.createPlaylist)
 	})
+	// outside of /playlists so it doesn't shadow a playlist with the uid "scheduled"
+	apiRoute.Get("/playlists-scheduled", handler.getScheduledPlaylist)
 }
 
 // swagger:parameters searchPlaylists
@@ -53,6 +57,14 @@ type GetPlaylistParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters getScheduledPlaylist
+type GetScheduledPlaylistParams struct {
+	// Name of the kiosk device. Schedules for other kiosk devices are ignored.
+	// in:query
+	// required:false
+	Kiosk string `json:"kiosk"`
+}
+
 // swagger:parameters getPlaylistItems
 type GetPlaylistItemsParams struct {
 	// in:path
@@ -217,6 +229,51 @@ func (pk8s *playlistK8sHandler) getPlaylist(c *contextmodel.ReqContext) {
 	c.JSON(http.StatusOK, internalplaylist.UnstructuredToLegacyPlaylistDTO(*out))
 }
 
+// swagger:route GET /playlists-scheduled playlists getScheduledPlaylist
+//
+// Get the playlist a kiosk device plays now, according to the schedules of the playlists.
+//
+// Responses:
+// 200: getPlaylistResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (pk8s *playlistK8sHandler) getScheduledPlaylist(c *contextmodel.ReqContext) {
+	client, ok := pk8s.getClient(c)
+	if !ok {
+		return // error is already sent
+	}
+	out, err := client.List(c.Req.Context(), v1.ListOptions{})
+	if err != nil {
+		pk8s.writeError(c, err)
+		return
+	}
+
+	playlists := make([]v0alpha1.Playlist, 0, len(out.Items))
+	for _, item := range out.Items {
+		p := v0alpha1.Playlist{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &p); err != nil {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to read playlist", err)
+			return
+		}
+		playlists = append(playlists, p)
+	}
+
+	scheduled := v0alpha1.ScheduledPlaylist(playlists, c.Query("kiosk"), time.Now())
+	if scheduled == nil {
+		c.JsonApiErr(http.StatusNotFound, "No playlist is scheduled", nil)
+		return
+	}
+	for _, item := range out.Items {
+		if item.GetName() == scheduled.Name {
+			c.JSON(http.StatusOK, internalplaylist.UnstructuredToLegacyPlaylistDTO(item))
+			return
+		}
+	}
+	c.JsonApiErr(http.StatusInternalServerError, "Failed to find the scheduled playlist", nil)
+}
+
 // swagger:route GET /playlists/{uid}/items playlists getPlaylistItems
 //
 // Get playlist items.
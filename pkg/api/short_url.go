@@ -9,6 +9,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
@@ -35,11 +36,22 @@ func (hs *HTTPServer) registerShortURLAPI(apiRoute routing.RouteRegister) {
 		handler := newShortURLK8sHandler(hs)
 		apiRoute.Post("/api/short-urls", reqSignedIn, handler.createKubernetesShortURLsHandler)
 		apiRoute.Get("/api/short-urls/:uid", reqSignedIn, handler.getKubernetesShortURLsHandler)
+		apiRoute.Post("/api/short-urls/:uid/resolve", reqSignedIn, handler.resolveKubernetesShortURLHandler)
 		apiRoute.Get("/goto/:uid", reqSignedIn, handler.getKubernetesRedirectFromShortURL, hs.Index)
+		// The short URLs of the kubernetes API don't record their creator, so they can't be listed per creator,
+		// revoked by their creator or audited. Requests for these get an error rather than a missing route.
+		apiRoute.Get("/api/short-urls", reqSignedIn, routing.Wrap(handler.notSupportedHandler))
+		apiRoute.Delete("/api/short-urls/:uid", reqSignedIn, routing.Wrap(handler.notSupportedHandler))
+		apiRoute.Get("/api/admin/short-urls", middleware.ReqGrafanaAdmin, routing.Wrap(handler.notSupportedHandler))
 	} else {
 		apiRoute.Post("/api/short-urls", reqSignedIn, hs.createShortURL)
+		apiRoute.Get("/api/short-urls", reqSignedIn, routing.Wrap(hs.searchShortURLs))
 		apiRoute.Get("/api/short-urls/:uid", reqSignedIn, hs.getShortURL)
+		apiRoute.Delete("/api/short-urls/:uid", reqSignedIn, routing.Wrap(hs.revokeShortURL))
+		apiRoute.Post("/api/short-urls/:uid/resolve", reqSignedIn, routing.Wrap(hs.resolveShortURL))
+		apiRoute.Get("/api/admin/short-urls", middleware.ReqGrafanaAdmin, routing.Wrap(hs.auditShortURLs))
 		apiRoute.Get("/goto/:uid", reqSignedIn, hs.redirectFromShortURL, hs.Index)
+		apiRoute.Get("/goto/:uid/password", reqSignedIn, hs.Index)
 	}
 }
 
@@ -68,13 +80,19 @@ func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 		return
 	}
 
-	shortURL, err := hs.ShortURLService.GetShortURLByUID(c.Req.Context(), c.SignedInUser, shortURLUID)
+	shortURL, err := hs.ShortURLService.ResolveShortURL(c.Req.Context(), c.SignedInUser, shortURLUID, "")
 	if err != nil {
+		// Password protected short URLs are resolved by the frontend, which asks for the password
+		if shorturls.ErrShortURLPassword.Is(err) {
+			hs.log.Debug("Redirecting password protected short URL to password prompt", "uid", shortURLUID)
+			c.Redirect(fmt.Sprintf("%s/goto/%s/password", hs.Cfg.AppSubURL, shortURLUID), http.StatusFound)
+			return
+		}
 		// If we didn't get the URL for whatever reason, we redirect to the
 		// main page, otherwise we get into an endless loops of redirects, as
 		// we would try to redirect again.
-		if shorturls.ErrShortURLNotFound.Is(err) {
-			hs.log.Debug("Not redirecting short URL since not found", "uid", shortURLUID)
+		if shorturls.ErrShortURLNotFound.Is(err) || shorturls.ErrShortURLExpired.Is(err) || shorturls.ErrShortURLUsedUp.Is(err) {
+			hs.log.Debug("Not redirecting short URL since not found", "uid", shortURLUID, "reason", err)
 			c.Redirect(hs.Cfg.AppURL, http.StatusPermanentRedirect)
 			return
 		}
@@ -83,11 +101,6 @@ func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 		return
 	}
 
-	// Failure to update LastSeenAt should still allow to redirect
-	if err := hs.ShortURLService.UpdateLastSeenAt(c.Req.Context(), shortURL); err != nil {
-		hs.log.Error("Failed to update short URL last seen at", "error", err)
-	}
-
 	hs.log.Debug("Redirecting short URL", "path", shortURL.Path)
 	c.Redirect(setting.ToAbsUrl(shortURL.Path), http.StatusFound)
 }
@@ -100,16 +113,132 @@ func (hs *HTTPServer) getShortURL(c *contextmodel.ReqContext) response.Response
 		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("invalid uid"))
 	}
 
-	shortURL, err := hs.ShortURLService.GetShortURLByUID(c.Req.Context(), c.SignedInUser, shortURLUID)
+	// Getting a short URL returns its path, so it counts as a use like the redirect. The path of a password
+	// protected short URL is only returned by resolving it with its password.
+	shortURL, err := hs.ShortURLService.ResolveShortURL(c.Req.Context(), c.SignedInUser, shortURLUID, "")
 	if err != nil {
-		if shorturls.ErrShortURLNotFound.Is(err) {
-			return response.Err(shorturls.ErrShortURLNotFound.Errorf("shorturl not found: %w", err))
-		}
+		return response.Err(err)
 	}
 
 	return response.JSON(http.StatusOK, shortURL)
 }
 
+// resolveShortURL handles requests to resolve short URLs, which counts as a use of the short URL.
+func (hs *HTTPServer) resolveShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("invalid uid"))
+	}
+
+	cmd := dtos.ResolveShortURLCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("bad request data: %w", err))
+	}
+
+	// Wrong passwords count toward the brute force login protection, both for the short URL and the IP address
+	ctx := c.Req.Context()
+	attemptKey := shortURLAttemptKey(shortURLUID)
+	ok, err := hs.loginAttemptService.Validate(ctx, attemptKey)
+	if err != nil {
+		return response.Err(shorturls.ErrShortURLInternal.Errorf("failed to validate attempts: %w", err))
+	}
+	if ok {
+		ok, err = hs.loginAttemptService.ValidateIPAddress(ctx, c.RemoteAddr())
+		if err != nil {
+			return response.Err(shorturls.ErrShortURLInternal.Errorf("failed to validate attempts: %w", err))
+		}
+	}
+	if !ok {
+		return response.Err(shorturls.ErrShortURLTooManyAttempts.Errorf("too many incorrect passwords for short URL %s", shortURLUID))
+	}
+
+	shortURL, err := hs.ShortURLService.ResolveShortURL(ctx, c.SignedInUser, shortURLUID, cmd.Password)
+	if err != nil {
+		if shorturls.ErrShortURLPassword.Is(err) {
+			if err := hs.loginAttemptService.Add(ctx, attemptKey, c.RemoteAddr()); err != nil {
+				hs.log.Warn("Failed to add short URL password attempt", "uid", shortURLUID, "err", err)
+			}
+		}
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, dtos.ResolvedShortURL{
+		Path: shortURL.Path,
+		URL:  setting.ToAbsUrl(shortURL.Path),
+	})
+}
+
+// shortURLAttemptKey is the name that wrong passwords of a short URL are counted for by the login attempt service
+func shortURLAttemptKey(uid string) string {
+	return "shorturl:" + uid
+}
+
+// searchShortURLs handles requests to list the short URLs the signed in user created.
+func (hs *HTTPServer) searchShortURLs(c *contextmodel.ReqContext) response.Response {
+	return hs.searchShortURLsResponse(c, &shorturls.SearchShortURLsQuery{
+		OrgId:     c.SignedInUser.GetOrgID(),
+		CreatedBy: c.UserID,
+		Page:      c.QueryInt("page"),
+		Limit:     c.QueryInt("perpage"),
+	})
+}
+
+// auditShortURLs handles requests of server admins to list the short URLs of all organizations.
+func (hs *HTTPServer) auditShortURLs(c *contextmodel.ReqContext) response.Response {
+	return hs.searchShortURLsResponse(c, &shorturls.SearchShortURLsQuery{
+		OrgId:     c.QueryInt64("orgId"),
+		CreatedBy: c.QueryInt64("userId"),
+		Page:      c.QueryInt("page"),
+		Limit:     c.QueryInt("perpage"),
+	})
+}
+
+func (hs *HTTPServer) searchShortURLsResponse(c *contextmodel.ReqContext, query *shorturls.SearchShortURLsQuery) response.Response {
+	result, err := hs.ShortURLService.SearchShortURLs(c.Req.Context(), query)
+	if err != nil {
+		return response.Err(shorturls.ErrShortURLInternal.Errorf("failed to search short URLs: %w", err))
+	}
+
+	dto := dtos.SearchShortURLsResult{
+		ShortURLs:  make([]*dtos.ShortURLInfo, 0, len(result.ShortURLs)),
+		TotalCount: result.TotalCount,
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+	}
+	for _, shortURL := range result.ShortURLs {
+		dto.ShortURLs = append(dto.ShortURLs, &dtos.ShortURLInfo{
+			UID:               shortURL.Uid,
+			URL:               hs.ShortURLService.ConvertShortURLToDTO(shortURL, hs.Cfg.AppURL).URL,
+			OrgID:             shortURL.OrgId,
+			Path:              shortURL.Path,
+			DashboardUID:      shortURL.DashboardUID(),
+			CreatedBy:         shortURL.CreatedBy,
+			CreatedAt:         shortURL.CreatedAt,
+			LastSeenAt:        shortURL.LastSeenAt,
+			ExpiresAt:         shortURL.ExpiresAt,
+			MaxUses:           shortURL.MaxUses,
+			UseCount:          shortURL.UseCount,
+			PasswordProtected: shortURL.IsPasswordProtected(),
+		})
+	}
+
+	return response.JSON(http.StatusOK, dto)
+}
+
+// revokeShortURL handles requests to revoke short URLs.
+func (hs *HTTPServer) revokeShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("invalid uid"))
+	}
+
+	if err := hs.ShortURLService.RevokeShortURL(c.Req.Context(), c.SignedInUser, shortURLUID); err != nil {
+		return response.Err(err)
+	}
+
+	return response.Success("Short URL revoked")
+}
+
 type shortURLK8sHandler struct {
 	namespacer           request.NamespaceMapper
 	gvr                  schema.GroupVersionResource
@@ -144,7 +273,7 @@ func (sk8s *shortURLK8sHandler) getKubernetesShortURLsHandler(c *contextmodel.Re
 	}
 
 	c.Logger.Debug("Fetching short URL", "uid", shortURLUID)
-	out, err := client.Get(c.Req.Context(), shortURLUID, v1.GetOptions{})
+	out, err := sk8s.useShortURL(c, client, shortURLUID)
 	if err != nil {
 		sk8s.writeError(c, err)
 		return
@@ -153,7 +282,7 @@ func (sk8s *shortURLK8sHandler) getKubernetesShortURLsHandler(c *contextmodel.Re
 	c.JSON(http.StatusOK, shorturl.UnstructuredToLegacyShortURL(*out))
 }
 
-func (sk8s *shortURLK8sHandler) getKubernetesRedirectFromShortURL(c *contextmodel.ReqContext) {
+func (sk8s *shortURLK8sHandler) resolveKubernetesShortURLHandler(c *contextmodel.ReqContext) {
 	client, ok := sk8s.getClient(c)
 	if !ok {
 		return
@@ -161,34 +290,45 @@ func (sk8s *shortURLK8sHandler) getKubernetesRedirectFromShortURL(c *contextmode
 
 	shortURLUID := web.Params(c.Req)[":uid"]
 	if !util.IsValidShortUID(shortURLUID) {
-		c.Logger.Warn("Invalid short URL UID format", "uid", shortURLUID)
-		c.Redirect(sk8s.cfg.AppURL, http.StatusFound)
+		c.JsonApiErr(http.StatusBadRequest, "Invalid short URL UID format", fmt.Errorf("invalid short URL UID: %s", shortURLUID))
 		return
 	}
 
-	// Get Object
-	obj, err := client.Get(c.Req.Context(), shortURLUID, v1.GetOptions{})
+	out, err := sk8s.useShortURL(c, client, shortURLUID)
 	if err != nil {
 		sk8s.writeError(c, err)
 		return
 	}
 
-	// Modify status
-	status := obj.Object["status"].(map[string]interface{})
-	newTimestamp := time.Now().Unix()
-	status["lastSeenAt"] = newTimestamp
+	path, _, _ := unstructured.NestedString(out.Object, "spec", "path")
+	c.JSON(http.StatusOK, dtos.ResolvedShortURL{
+		Path: path,
+		URL:  setting.ToAbsUrl(path),
+	})
+}
 
-	// Try status subresource first (works in Mode 5), fallback to main resource (works in Mode 0)
-	out, err := client.Update(c.Req.Context(), obj, v1.UpdateOptions{}, "status")
+func (sk8s *shortURLK8sHandler) getKubernetesRedirectFromShortURL(c *contextmodel.ReqContext) {
+	client, ok := sk8s.getClient(c)
+	if !ok {
+		return
+	}
+
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		c.Logger.Warn("Invalid short URL UID format", "uid", shortURLUID)
+		c.Redirect(sk8s.cfg.AppURL, http.StatusFound)
+		return
+	}
+
+	out, err := sk8s.useShortURL(c, client, shortURLUID)
 	if err != nil {
-		c.Logger.Debug("Status subresource update failed, trying main resource", "error", err)
-		// Fallback to main resource update (for Mode 0)
-		out, err = client.Update(c.Req.Context(), obj, v1.UpdateOptions{})
-		if err != nil {
-			c.Logger.Error("Both status and main resource updates failed", "error", err)
-			sk8s.writeError(c, err)
+		if shorturls.ErrShortURLExpired.Is(err) || shorturls.ErrShortURLUsedUp.Is(err) {
+			c.Logger.Debug("Not redirecting short URL since it can no longer be used", "uid", shortURLUID, "reason", err)
+			c.Redirect(sk8s.cfg.AppURL, http.StatusFound)
 			return
 		}
+		sk8s.writeError(c, err)
+		return
 	}
 
 	spec := out.Object["spec"].(map[string]any)
@@ -210,6 +350,20 @@ func (sk8s *shortURLK8sHandler) createKubernetesShortURLsHandler(c *contextmodel
 		return
 	}
 
+	// The spec of a short URL can be read by anyone in the organization, so it can't hold a password
+	if cmd.Password != "" {
+		c.JsonApiErr(http.StatusBadRequest, "Password protected short URLs are not supported by the kubernetes short URL API", nil)
+		return
+	}
+	if cmd.ExpiresAt != 0 && cmd.ExpiresAt <= time.Now().Unix() {
+		c.JsonApiErr(http.StatusBadRequest, "Expiry must be in the future", nil)
+		return
+	}
+	if cmd.MaxUses < 0 {
+		c.JsonApiErr(http.StatusBadRequest, "Maximum number of uses cannot be negative", nil)
+		return
+	}
+
 	c.Logger.Debug("Creating short URL", "path", cmd.Path)
 	obj := shorturl.LegacyCreateCommandToUnstructured(cmd)
 
@@ -231,6 +385,10 @@ func (sk8s *shortURLK8sHandler) createKubernetesShortURLsHandler(c *contextmodel
 	c.JSON(http.StatusOK, shorturl.UnstructuredToLegacyShortURLDTO(*out, sk8s.cfg.AppURL))
 }
 
+func (sk8s *shortURLK8sHandler) notSupportedHandler(c *contextmodel.ReqContext) response.Response {
+	return response.Err(shorturls.ErrShortURLNotSupported.Errorf("%s %s is not supported by the kubernetes short URL API", c.Req.Method, c.Req.URL.Path))
+}
+
 //-----------------------------------------------------------------------------------------
 // Utility functions
 //-----------------------------------------------------------------------------------------
@@ -244,6 +402,44 @@ func (sk8s *shortURLK8sHandler) getClient(c *contextmodel.ReqContext) (dynamic.R
 	return dyn.Resource(sk8s.gvr).Namespace(sk8s.namespacer(c.OrgID)), true
 }
 
+// useShortURL records a use of the short URL in its status, unless it has expired or has been used up.
+func (sk8s *shortURLK8sHandler) useShortURL(c *contextmodel.ReqContext, client dynamic.ResourceInterface, uid string) (*unstructured.Unstructured, error) {
+	obj, err := client.Get(c.Req.Context(), uid, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	current := shorturl.UnstructuredToLegacyShortURL(*obj)
+	now := time.Now()
+	if current.IsExpired(now) {
+		return nil, shorturls.ErrShortURLExpired.Errorf("short URL %s expired at %d", uid, current.ExpiresAt)
+	}
+	if current.IsUsedUp() {
+		return nil, shorturls.ErrShortURLUsedUp.Errorf("short URL %s was used %d times", uid, current.UseCount)
+	}
+
+	if err := unstructured.SetNestedField(obj.Object, now.Unix(), "status", "lastSeenAt"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(obj.Object, current.UseCount+1, "status", "useCount"); err != nil {
+		return nil, err
+	}
+
+	// Try status subresource first (works in Mode 5), fallback to main resource (works in Mode 0).
+	// The resource version of obj makes concurrent uses conflict, so the maximum number of uses can't be exceeded.
+	out, err := client.Update(c.Req.Context(), obj, v1.UpdateOptions{}, "status")
+	if err != nil {
+		c.Logger.Debug("Status subresource update failed, trying main resource", "error", err)
+		// Fallback to main resource update (for Mode 0)
+		out, err = client.Update(c.Req.Context(), obj, v1.UpdateOptions{})
+		if err != nil {
+			c.Logger.Error("Both status and main resource updates failed", "error", err)
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
 func (sk8s *shortURLK8sHandler) writeError(c *contextmodel.ReqContext, err error) {
 	//nolint:errorlint
 	statusError, ok := err.(*errors.StatusError)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,9 +15,14 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestShortURLAPIEndpoint(t *testing.T) {
@@ -52,6 +59,91 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestShortURLAPI_Resolve(t *testing.T) {
+	resolved := 0
+	service := &fakeShortURLService{
+		resolveShortURLFunc: func(ctx context.Context, user *user.SignedInUser, uid string, password string) (*shorturls.ShortUrl, error) {
+			if password != "secret" {
+				return nil, shorturls.ErrShortURLPassword.Errorf("invalid password")
+			}
+			resolved++
+			return &shorturls.ShortUrl{Uid: uid, Path: "d/abc"}, nil
+		},
+	}
+
+	setup := func(t *testing.T, loginAttempts loginattempt.Service) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.log = log.New("test")
+			hs.ShortURLService = service
+			hs.loginAttemptService = loginAttempts
+		})
+	}
+	resolve := func(t *testing.T, server *webtest.Server, body string) *http.Response {
+		req := server.NewRequest(http.MethodPost, "/api/short-urls/abc/resolve", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer}))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res
+	}
+
+	t.Run("should resolve short URL with its password", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: true}
+		res := resolve(t, setup(t, loginAttempts), `{"password": "secret"}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, 1, resolved)
+		require.False(t, loginAttempts.AddCalled)
+	})
+
+	t.Run("should count wrong passwords as login attempts", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: true}
+		res := resolve(t, setup(t, loginAttempts), `{"password": "wrong"}`)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.True(t, loginAttempts.AddCalled)
+	})
+
+	t.Run("should not resolve short URL when attempts are blocked", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: false}
+		res := resolve(t, setup(t, loginAttempts), `{"password": "secret"}`)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, 1, resolved)
+	})
+
+	t.Run("should count getting a short URL as a use", func(t *testing.T) {
+		server := setup(t, &loginattempttest.MockLoginAttemptService{ExpectedValid: true})
+		service.resolveShortURLFunc = func(ctx context.Context, user *user.SignedInUser, uid string, password string) (*shorturls.ShortUrl, error) {
+			resolved++
+			return nil, shorturls.ErrShortURLUsedUp.Errorf("used up")
+		}
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/short-urls/abc"), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer}))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.Equal(t, 2, resolved)
+	})
+}
+
+func TestShortURLAPI_KubernetesNotSupported(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.log = log.New("test")
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagKubernetesShortURLs)
+	})
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, IsGrafanaAdmin: true}
+
+	for _, req := range []*http.Request{
+		server.NewGetRequest("/api/short-urls"),
+		server.NewRequest(http.MethodDelete, "/api/short-urls/abc", nil),
+		server.NewGetRequest("/api/admin/short-urls"),
+	} {
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, admin))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusNotImplemented, res.StatusCode, req.URL.Path)
+	}
+}
+
 func callCreateShortURL(sc *scenarioContext) {
 	sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
 }
@@ -83,6 +175,7 @@ func createShortURLScenario(t *testing.T, desc string, url string, routePattern
 type fakeShortURLService struct {
 	createShortURLFunc         func(ctx context.Context, user *user.SignedInUser, cmd *dtos.CreateShortURLCmd) (*shorturls.ShortUrl, error)
 	createConvertShortURLToDTO func(shortURL *shorturls.ShortUrl, appURL string) *dtos.ShortURL
+	resolveShortURLFunc        func(ctx context.Context, user *user.SignedInUser, uid string, password string) (*shorturls.ShortUrl, error)
 }
 
 func (s *fakeShortURLService) GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error) {
@@ -97,6 +190,21 @@ func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.Sig
 	return nil, nil
 }
 
+func (s *fakeShortURLService) ResolveShortURL(ctx context.Context, user *user.SignedInUser, uid string, password string) (*shorturls.ShortUrl, error) {
+	if s.resolveShortURLFunc != nil {
+		return s.resolveShortURLFunc(ctx, user, uid, password)
+	}
+	return nil, nil
+}
+
+func (s *fakeShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	return &shorturls.SearchShortURLsResult{}, nil
+}
+
+func (s *fakeShortURLService) RevokeShortURL(ctx context.Context, user *user.SignedInUser, uid string) error {
+	return nil
+}
+
 func (s *fakeShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	return nil
 }
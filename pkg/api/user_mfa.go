@@ -0,0 +1,463 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /user/mfa/factors signed_in_user getUserMFAFactors
+//
+// Get the second factors of the actual User.
+//
+// Responses:
+// 200: getMFAFactorsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetUserMFAFactors(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	factors, err := hs.mfaService.GetFactors(c.Req.Context(), userID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get second factors", err)
+	}
+	return response.JSON(http.StatusOK, factors)
+}
+
+// swagger:route POST /user/mfa/challenge signed_in_user createUserMFAChallenge
+//
+// Create a second factor challenge for the actual User.
+//
+// Deleting a second factor and replacing the recovery codes require the answer to this challenge or the password of the user.
+//
+// Responses:
+// 200: mfaChallengeResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreateUserMFAChallenge(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	challenge, err := hs.mfaService.CreateChallenge(c.Req.Context(), userID, c.GetLogin())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create second factor challenge", err)
+	}
+	return response.JSON(http.StatusOK, challenge)
+}
+
+// swagger:route POST /user/mfa/totp signed_in_user enrollUserTOTP
+//
+// Start the enrollment of an authenticator app for the actual User.
+//
+// Returns the secret and the otpauth:// URL to add to the authenticator app. The
+// enrollment has to be completed with a code generated by the app.
+//
+// Responses:
+// 200: enrollTOTPResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (hs *HTTPServer) EnrollUserTOTP(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	enrollment, err := hs.mfaService.EnrollTOTP(c.Req.Context(), userID, c.GetLogin())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enroll authenticator app", err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+type VerifyTOTPEnrollmentForm struct {
+	Code string `json:"code" binding:"Required"`
+}
+
+// swagger:route POST /user/mfa/totp/verify signed_in_user verifyUserTOTP
+//
+// Complete the enrollment of an authenticator app for the actual User.
+//
+// Recovery codes are returned when this is the first second factor of the user.
+//
+// Responses:
+// 200: mfaEnrollmentResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) VerifyUserTOTP(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	form := VerifyTOTPEnrollmentForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := hs.mfaService.VerifyTOTPEnrollment(c.Req.Context(), userID, form.Code)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to verify authenticator app", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /user/mfa/webauthn/register/begin signed_in_user beginUserWebAuthnRegistration
+//
+// Start the registration of a security key or passkey for the actual User.
+//
+// Returns the options for navigator.credentials.create().
+//
+// Responses:
+// 200: webAuthnCreationOptionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) BeginUserWebAuthnRegistration(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	options, err := hs.mfaService.BeginWebAuthnRegistration(c.Req.Context(), userID, c.GetLogin())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to start security key registration", err)
+	}
+	return response.JSON(http.StatusOK, options)
+}
+
+// swagger:route POST /user/mfa/webauthn/register/finish signed_in_user finishUserWebAuthnRegistration
+//
+// Complete the registration of a security key or passkey for the actual User.
+//
+// Recovery codes are returned when this is the first second factor of the user.
+//
+// Responses:
+// 200: mfaEnrollmentResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (hs *HTTPServer) FinishUserWebAuthnRegistration(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	cmd := mfa.FinishWebAuthnRegistrationCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := hs.mfaService.FinishWebAuthnRegistration(c.Req.Context(), userID, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to register security key", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// MFAReverificationForm proves that the request is made by the user and not only with
+// their session. It holds either the password of the user or the answer to a challenge
+// created with POST /user/mfa/challenge.
+type MFAReverificationForm struct {
+	Password string          `json:"password"`
+	Token    string          `json:"token"`
+	Code     string          `json:"code"`
+	WebAuthn json.RawMessage `json:"webauthn"`
+}
+
+// swagger:route DELETE /user/mfa/factors/{factor_id} signed_in_user deleteUserMFAFactor
+//
+// Delete a second factor of the actual User.
+//
+// Recovery codes are deleted together with the last second factor. Requires the password of the user or the answer to a second factor challenge.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteUserMFAFactor(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	factorID, err := strconv.ParseInt(web.Params(c.Req)[":factorId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "factorId is invalid", err)
+	}
+
+	form := MFAReverificationForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if errResp := hs.reverifyMFAUser(c, userID, &form); errResp != nil {
+		return errResp
+	}
+
+	if err := hs.mfaService.DeleteFactor(c.Req.Context(), userID, factorID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete second factor", err)
+	}
+	return response.Success("Second factor deleted")
+}
+
+// swagger:route POST /user/mfa/recovery-codes signed_in_user regenerateUserRecoveryCodes
+//
+// Replace the recovery codes of the actual User.
+//
+// Requires the password of the user or the answer to a second factor challenge.
+//
+// Responses:
+// 200: recoveryCodesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) RegenerateUserRecoveryCodes(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := mfaUserID(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	form := MFAReverificationForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if errResp := hs.reverifyMFAUser(c, userID, &form); errResp != nil {
+		return errResp
+	}
+
+	codes, err := hs.mfaService.RegenerateRecoveryCodes(c.Req.Context(), userID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to generate recovery codes", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"recoveryCodes": codes})
+}
+
+// swagger:route GET /admin/users/{user_id}/mfa/factors admin_users adminGetUserMFAFactors
+//
+// Get the second factors of a user.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:read` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: getMFAFactorsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminGetUserMFAFactors(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	factors, err := hs.mfaService.GetFactors(c.Req.Context(), userID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get second factors", err)
+	}
+	return response.JSON(http.StatusOK, factors)
+}
+
+// swagger:route DELETE /admin/users/{user_id}/mfa/factors admin_users adminResetUserMFAFactors
+//
+// Reset the second factors of a user.
+//
+// Deletes all second factors and recovery codes of the user, so that the user can log in with the password only and enroll new second factors.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:write` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminResetUserMFAFactors(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := hs.mfaService.ResetFactors(c.Req.Context(), userID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset second factors", err)
+	}
+
+	hs.log.FromContext(c.Req.Context()).Info("Second factors of user reset", "userId", userID, "by", c.GetID())
+	return response.Success("Second factors reset")
+}
+
+// reverifyMFAUser checks the password or the answer to a second factor challenge in the
+// form, so that a stolen session isn't enough to remove the second factors of a user.
+// Failed attempts count toward the brute force login protection.
+func (hs *HTTPServer) reverifyMFAUser(c *contextmodel.ReqContext, userID int64, form *MFAReverificationForm) response.Response {
+	ctx := c.Req.Context()
+	login := c.GetLogin()
+
+	ok, err := hs.loginAttemptService.Validate(ctx, login)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to validate login attempts", err)
+	}
+	if ok {
+		ok, err = hs.loginAttemptService.ValidateIPAddress(ctx, c.RemoteAddr())
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to validate login attempts", err)
+		}
+	}
+	if !ok {
+		return response.Error(http.StatusTooManyRequests, "Too many consecutive incorrect attempts", nil)
+	}
+
+	var verifyErr error
+	switch {
+	case form.Token != "":
+		challenge, err := hs.mfaService.GetChallenge(ctx, form.Token)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get second factor challenge", err)
+		}
+		if challenge.UserID != userID {
+			return response.Error(http.StatusForbidden, "Second factor challenge belongs to another user", nil)
+		}
+		_, verifyErr = hs.mfaService.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: form.Token, Code: form.Code, WebAuthn: form.WebAuthn})
+	case form.Password != "":
+		usr, err := hs.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get user", err)
+		}
+		hashed, err := user.Password(form.Password).Hash(usr.Salt)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to verify password", err)
+		}
+		if subtle.ConstantTimeCompare([]byte(hashed), []byte(usr.Password)) != 1 {
+			verifyErr = user.ErrPasswordMissmatch.Errorf("password does not match stored password")
+		}
+	default:
+		return response.Error(http.StatusForbidden, "Password or second factor verification required", nil)
+	}
+
+	if verifyErr != nil {
+		if err := hs.loginAttemptService.Add(ctx, login, c.RemoteAddr()); err != nil {
+			hs.log.FromContext(ctx).Warn("Failed to add login attempt", "error", err)
+		}
+		return response.Error(http.StatusForbidden, "Password or second factor verification failed", verifyErr)
+	}
+	return nil
+}
+
+func mfaUserID(c *contextmodel.ReqContext) (int64, response.Response) {
+	if !c.IsIdentityType(claims.TypeUser) {
+		return 0, response.Error(http.StatusForbidden, "entity not allowed to manage second factors", nil)
+	}
+
+	userID, err := c.GetInternalID()
+	if err != nil {
+		return 0, response.Error(http.StatusInternalServerError, "failed to parse user id", err)
+	}
+	return userID, nil
+}
+
+// swagger:parameters verifyUserTOTP
+type VerifyUserTOTPParams struct {
+	// in:body
+	// required:true
+	Body VerifyTOTPEnrollmentForm `json:"body"`
+}
+
+// swagger:parameters finishUserWebAuthnRegistration
+type FinishUserWebAuthnRegistrationParams struct {
+	// in:body
+	// required:true
+	Body mfa.FinishWebAuthnRegistrationCommand `json:"body"`
+}
+
+// swagger:parameters deleteUserMFAFactor
+type DeleteUserMFAFactorParams struct {
+	// in:path
+	// required:true
+	FactorID int64 `json:"factor_id"`
+	// in:body
+	// required:true
+	Body MFAReverificationForm `json:"body"`
+}
+
+// swagger:parameters regenerateUserRecoveryCodes
+type RegenerateUserRecoveryCodesParams struct {
+	// in:body
+	// required:true
+	Body MFAReverificationForm `json:"body"`
+}
+
+// swagger:parameters adminGetUserMFAFactors adminResetUserMFAFactors
+type AdminUserMFAFactorsParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
+// swagger:response getMFAFactorsResponse
+type GetMFAFactorsResponse struct {
+	// in:body
+	Body []*mfa.FactorDTO `json:"body"`
+}
+
+// swagger:response mfaChallengeResponse
+type MFAChallengeResponse struct {
+	// in:body
+	Body *mfa.Challenge `json:"body"`
+}
+
+// swagger:response enrollTOTPResponse
+type EnrollTOTPResponse struct {
+	// in:body
+	Body *mfa.TOTPEnrollment `json:"body"`
+}
+
+// swagger:response mfaEnrollmentResponse
+type MFAEnrollmentResponse struct {
+	// in:body
+	Body *mfa.EnrollmentResult `json:"body"`
+}
+
+// swagger:response webAuthnCreationOptionsResponse
+type WebAuthnCreationOptionsResponse struct {
+	// in:body
+	Body *mfa.WebAuthnCreationOptions `json:"body"`
+}
+
+// swagger:response recoveryCodesResponse
+type RecoveryCodesResponse struct {
+	// in:body
+	Body struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	} `json:"body"`
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_AdminResetUserMFAFactors(t *testing.T) {
+	type testCase struct {
+		desc         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should reset factors for user with users:write permission",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: "global.users:*"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should return 403 for user without permission",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersRead, Scope: "global.users:*"}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mfaService := &mfatest.FakeService{}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.Cfg.AuthMFA.Enabled = true
+				hs.log = log.New()
+				hs.mfaService = mfaService
+			})
+
+			res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/admin/users/2/mfa/factors", nil), globalUserWithPermissions(tt.permissions)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, int64(2), mfaService.ResetUserID)
+			} else {
+				assert.Zero(t, mfaService.ResetUserID)
+			}
+		})
+	}
+}
+
+func TestAPI_GetUserMFAFactors(t *testing.T) {
+	mfaService := &mfatest.FakeService{ExpectedFactors: []*mfa.FactorDTO{{ID: 1, Type: mfa.FactorTOTP, Name: "Authenticator app"}}}
+
+	t.Run("should return factors of signed in user", func(t *testing.T) {
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.AuthMFA.Enabled = true
+			hs.mfaService = mfaService
+		})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/user/mfa/factors"), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var factors []*mfa.FactorDTO
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&factors))
+		require.NoError(t, res.Body.Close())
+		assert.Len(t, factors, 1)
+		assert.Equal(t, mfa.FactorTOTP, factors[0].Type)
+	})
+
+	t.Run("should not register routes when disabled", func(t *testing.T) {
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.mfaService = mfaService
+		})
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/user/mfa/factors"), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer}))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func TestAPI_DeleteUserMFAFactor(t *testing.T) {
+	hashed, err := user.Password("password").Hash("salt")
+	require.NoError(t, err)
+	usr := &user.User{ID: 1, Login: "user", Salt: "salt", Password: hashed}
+
+	type testCase struct {
+		desc           string
+		body           string
+		challenge      *mfa.Challenge
+		verifyErr      error
+		blocked        bool
+		expectedCode   int
+		expectedFailed bool
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should return 403 without password or second factor",
+			body:         `{}`,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should delete factor with password",
+			body:         `{"password": "password"}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:           "should return 403 for wrong password",
+			body:           `{"password": "wrong"}`,
+			expectedCode:   http.StatusForbidden,
+			expectedFailed: true,
+		},
+		{
+			desc:         "should delete factor with answer to challenge",
+			body:         `{"token": "token", "code": "123456"}`,
+			challenge:    &mfa.Challenge{Token: "token", UserID: 1},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:           "should return 403 for wrong answer to challenge",
+			body:           `{"token": "token", "code": "000000"}`,
+			challenge:      &mfa.Challenge{Token: "token", UserID: 1},
+			verifyErr:      mfa.ErrInvalidCode.Errorf("invalid"),
+			expectedCode:   http.StatusForbidden,
+			expectedFailed: true,
+		},
+		{
+			desc:         "should return 403 for challenge of another user",
+			body:         `{"token": "token", "code": "123456"}`,
+			challenge:    &mfa.Challenge{Token: "token", UserID: 2},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should return 429 when attempts are blocked",
+			body:         `{"password": "password"}`,
+			blocked:      true,
+			expectedCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: !tt.blocked}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.Cfg.AuthMFA.Enabled = true
+				hs.log = log.New()
+				hs.mfaService = &mfatest.FakeService{ExpectedChallenge: tt.challenge, ExpectedVerifyErr: tt.verifyErr}
+				hs.userService = &usertest.FakeUserService{ExpectedUser: usr}
+				hs.loginAttemptService = loginAttempts
+			})
+
+			req := server.NewRequest(http.MethodDelete, "/api/user/mfa/factors/1", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, Login: "user", OrgID: 1, OrgRole: org.RoleViewer}))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			assert.Equal(t, tt.expectedFailed, loginAttempts.AddCalled)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+// globalUserWithPermissions returns a user signed in to the global org, so that the
+// permissions of admin routes are evaluated without resolving the user in another org.
+func globalUserWithPermissions(permissions []accesscontrol.Permission) *user.SignedInUser {
+	return &user.SignedInUser{UserID: 1, OrgID: accesscontrol.GlobalOrgID, OrgRole: org.RoleViewer, Permissions: map[int64]map[string][]string{
+		accesscontrol.GlobalOrgID: accesscontrol.GroupScopesByActionContext(context.Background(), permissions),
+	}}
+}
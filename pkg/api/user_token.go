@@ -11,6 +11,8 @@ import (
 	claims "github.com/grafana/authlib/types"
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
@@ -152,7 +154,7 @@ func (hs *HTTPServer) rotateToken(c *contextmodel.ReqContext) error {
 func (hs *HTTPServer) logoutUserFromAllDevicesInternal(ctx context.Context, userID int64) response.Response {
 	userQuery := user.GetUserByIDQuery{ID: userID}
 
-	_, err := hs.userService.GetByID(ctx, &userQuery)
+	usr, err := hs.userService.GetByID(ctx, &userQuery)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return response.Error(http.StatusNotFound, "User not found", err)
@@ -165,6 +167,20 @@ func (hs *HTTPServer) logoutUserFromAllDevicesInternal(ctx context.Context, user
 		return response.Error(http.StatusInternalServerError, "Failed to logout user", err)
 	}
 
+	var revokedBy string
+	if requester, err := identity.GetRequester(ctx); err == nil {
+		revokedBy = requester.GetLogin()
+	}
+	if err := hs.bus.Publish(ctx, &events.UserTokenRevoked{
+		Timestamp: time.Now(),
+		Kind:      events.UserTokenRevokedAllSessions,
+		UserID:    usr.ID,
+		Login:     usr.Login,
+		RevokedBy: revokedBy,
+	}); err != nil {
+		hs.log.Warn("Failed to publish user token revoked event", "error", err)
+	}
+
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "User logged out",
 	})
@@ -251,7 +267,7 @@ func (hs *HTTPServer) getUserAuthTokensInternal(c *contextmodel.ReqContext, user
 
 func (hs *HTTPServer) revokeUserAuthTokenInternal(c *contextmodel.ReqContext, userID int64, cmd auth.RevokeAuthTokenCmd) response.Response {
 	userQuery := user.GetUserByIDQuery{ID: userID}
-	_, err := hs.userService.GetByID(c.Req.Context(), &userQuery)
+	usr, err := hs.userService.GetByID(c.Req.Context(), &userQuery)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return response.Error(http.StatusNotFound, "User not found", err)
@@ -279,6 +295,17 @@ func (hs *HTTPServer) revokeUserAuthTokenInternal(c *contextmodel.ReqContext, us
 		return response.Error(http.StatusInternalServerError, "Failed to revoke user auth token", err)
 	}
 
+	if err := hs.bus.Publish(c.Req.Context(), &events.UserTokenRevoked{
+		Timestamp: time.Now(),
+		Kind:      events.UserTokenRevokedSession,
+		UserID:    usr.ID,
+		Login:     usr.Login,
+		TokenID:   token.Id,
+		RevokedBy: c.SignedInUser.GetLogin(),
+	}); err != nil {
+		hs.log.Warn("Failed to publish user token revoked event", "error", err)
+	}
+
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "User auth token revoked",
 	})
@@ -13,7 +13,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -390,6 +392,7 @@ func logoutUserFromAllDevicesInternalScenario(t *testing.T, desc string, userId
 		hs := HTTPServer{
 			AuthTokenService: authtest.NewFakeUserAuthTokenService(),
 			userService:      userService,
+			bus:              bus.ProvideBus(tracing.InitializeTracerForTest()),
 		}
 
 		sc := setupScenarioContext(t, "/")
@@ -416,6 +419,7 @@ func revokeUserAuthTokenInternalScenario(t *testing.T, desc string, cmd auth.Rev
 		hs := HTTPServer{
 			AuthTokenService: fakeAuthTokenService,
 			userService:      userService,
+			bus:              bus.ProvideBus(tracing.InitializeTracerForTest()),
 		}
 
 		sc := setupScenarioContext(t, "/")
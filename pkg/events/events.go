@@ -81,3 +81,47 @@ type FolderFullPathUpdated struct {
 	UIDs      []string  `json:"uids"`
 	OrgID     int64     `json:"org_id"`
 }
+
+// LoginLockedOut is emitted when a username, IP address or subnet is locked out
+// after too many failed login attempts.
+type LoginLockedOut struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	Attempts    int64     `json:"attempts"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// Kinds of tokens revoked by a UserTokenRevoked event.
+const (
+	UserTokenRevokedSession             = "session"
+	UserTokenRevokedAllSessions         = "all_sessions"
+	UserTokenRevokedServiceAccountToken = "service_account_token"
+)
+
+// UserTokenRevoked is emitted when a session of a user, all the sessions of a user or a token
+// of a service account is revoked. TokenID is 0 when all the sessions are revoked, and UserID is
+// the ID of the service account when a service account token is revoked.
+type UserTokenRevoked struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	UserID    int64     `json:"user_id"`
+	Login     string    `json:"login"`
+	TokenID   int64     `json:"token_id"`
+	RevokedBy string    `json:"revoked_by"`
+}
+
+// ProvisioningSyncFailed is emitted when resources can't be provisioned from the
+// provisioning files.
+type ProvisioningSyncFailed struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Error     string    `json:"error"`
+}
+
+// UpdateAvailable is emitted when the update check finds a newer stable version of Grafana.
+type UpdateAvailable struct {
+	Timestamp      time.Time `json:"timestamp"`
+	CurrentVersion string    `json:"current_version"`
+	LatestVersion  string    `json:"latest_version"`
+}
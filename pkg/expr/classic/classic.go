@@ -34,9 +34,16 @@ import (
 //	false OR true AND true
 //
 // then the outcome of ConditionsCmd is true.
+//
+// By default the outcome is a single number without labels. When GroupByLabels is set the
+// conditions are evaluated separately for each set of labels in the input data, and the
+// outcome is one number per set of labels. See executeByLabels for details.
 type ConditionsCmd struct {
 	Conditions []condition
 	RefID      string
+
+	// GroupByLabels enables multi-dimensional evaluation of the conditions.
+	GroupByLabels bool
 }
 
 // condition is a single condition in ConditionsCmd.
@@ -73,6 +80,11 @@ func (cmd *ConditionsCmd) NeedsVars() []string {
 func (cmd *ConditionsCmd) Execute(ctx context.Context, t time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
 	ctx, span := tracer.Start(ctx, "SSE.ExecuteClassicConditions")
 	defer span.End()
+
+	if cmd.GroupByLabels {
+		return cmd.executeByLabels(ctx, t, vars)
+	}
+
 	// isFiring and isNoData contains the outcome of ConditionsCmd, and is derived from the
 	// boolean comparison of isCondFiring and isCondNoData of all conditions in ConditionsCmd
 	var isFiring, isNoData bool
@@ -164,28 +176,9 @@ func (cmd *ConditionsCmd) executeCond(_ context.Context, _ time.Time, cond condi
 	// Look at all values and compare them against the condition. The values can contain
 	// either no data, numbers, or time series.
 	for _, value := range data.Values {
-		var (
-			name   string
-			number mathexp.Number
-		)
-		switch v := value.(type) {
-		case mathexp.NoData:
-			// Reduce expressions return v.New(), however ConditionsCmds use the operator
-			// in the condition to determine if the outcome is no data. To keep this code as
-			// simple as possible we translate mathexp.NoData into a mathexp.Number with a
-			// nil value so number.GetFloat64Value() returns nil
-			number = mathexp.NewNumber("no data", nil)
-			number.SetValue(nil)
-		case mathexp.Number:
-			if len(v.Frame.Fields) > 0 {
-				name = v.Frame.Fields[0].Name
-			}
-			number = v
-		case mathexp.Series:
-			name = v.GetName()
-			number = cond.Reducer.Reduce(v)
-		default:
-			return false, false, nil, fmt.Errorf("can only reduce type series, got type %v", v.Type())
+		name, number, err := reduceValue(cond, value)
+		if err != nil {
+			return false, false, nil, err
 		}
 
 		isValueFiring := cond.Evaluator.Eval(number)
@@ -222,6 +215,31 @@ func (cmd *ConditionsCmd) executeCond(_ context.Context, _ time.Time, cond condi
 	return isCondFiring, isCondNoData, matches, nil
 }
 
+// reduceValue returns the name of the value and its reduction to a number using the
+// reducer of the condition.
+func reduceValue(cond condition, value mathexp.Value) (string, mathexp.Number, error) {
+	switch v := value.(type) {
+	case mathexp.NoData:
+		// Reduce expressions return v.New(), however ConditionsCmds use the operator
+		// in the condition to determine if the outcome is no data. To keep this code as
+		// simple as possible we translate mathexp.NoData into a mathexp.Number with a
+		// nil value so number.GetFloat64Value() returns nil
+		number := mathexp.NewNumber("no data", nil)
+		number.SetValue(nil)
+		return "", number, nil
+	case mathexp.Number:
+		var name string
+		if len(v.Frame.Fields) > 0 {
+			name = v.Frame.Fields[0].Name
+		}
+		return name, v, nil
+	case mathexp.Series:
+		return v.GetName(), cond.Reducer.Reduce(v), nil
+	default:
+		return "", mathexp.Number{}, fmt.Errorf("can only reduce type series, got type %v", v.Type())
+	}
+}
+
 func (cmd *ConditionsCmd) Type() string {
 	return "classic_condition"
 }
@@ -295,9 +313,10 @@ type ConditionReducerJSON struct {
 	// Params []any `json:"params"` (Unused)
 }
 
-func NewConditionCmd(refID string, ccj []ConditionJSON) (*ConditionsCmd, error) {
+func NewConditionCmd(refID string, ccj []ConditionJSON, groupByLabels bool) (*ConditionsCmd, error) {
 	c := &ConditionsCmd{
-		RefID: refID,
+		RefID:         refID,
+		GroupByLabels: groupByLabels,
 	}
 
 	var err error
@@ -343,5 +362,6 @@ func UnmarshalConditionsCmd(rawQuery map[string]any, refID string) (*ConditionsC
 	if err = json.Unmarshal(jsonFromM, &ccj); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal remarshaled classic condition body: %w", err)
 	}
-	return NewConditionCmd(refID, ccj)
+	groupByLabels, _ := rawQuery["groupByLabels"].(bool)
+	return NewConditionCmd(refID, ccj, groupByLabels)
 }
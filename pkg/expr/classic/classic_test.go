@@ -646,6 +646,171 @@ func TestConditionsCmd(t *testing.T) {
 	}
 }
 
+func TestConditionsCmdGroupByLabels(t *testing.T) {
+	hostA := data.Labels{"host": "a"}
+	hostB := data.Labels{"host": "b"}
+
+	tests := []struct {
+		name     string
+		cmd      *ConditionsCmd
+		vars     mathexp.Vars
+		expected func() mathexp.Results
+	}{{
+		name: "single condition returns one result per series",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{
+					newSeriesWithLabels(hostB, util.Pointer(1.0), util.Pointer(2.0)),
+					newSeriesWithLabels(hostA, util.Pointer(5.0), util.Pointer(10.0)),
+				},
+			},
+		},
+		cmd: &ConditionsCmd{
+			GroupByLabels: true,
+			Conditions: []condition{
+				{
+					InputRefID: "A",
+					Reducer:    reducer("max"),
+					Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 5},
+				},
+			}},
+		expected: func() mathexp.Results {
+			a := newNumberWithLabels(hostA, util.Pointer(1.0))
+			a.SetMeta([]EvalMatch{{Value: util.Pointer(10.0), Labels: hostA}})
+			b := newNumberWithLabels(hostB, util.Pointer(0.0))
+			b.SetMeta([]EvalMatch{})
+			return newResults(a, b)
+		},
+	}, {
+		name: "condition without labels applies to all series",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{
+					newSeriesWithLabels(hostA, util.Pointer(10.0)),
+					newSeriesWithLabels(hostB, util.Pointer(10.0)),
+				},
+			},
+			"B": mathexp.Results{
+				Values: []mathexp.Value{
+					newSeries(util.Pointer(1.0)),
+				},
+			},
+		},
+		cmd: &ConditionsCmd{
+			GroupByLabels: true,
+			Conditions: []condition{
+				{
+					InputRefID: "A",
+					Reducer:    reducer("last"),
+					Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 5},
+				},
+				{
+					InputRefID: "B",
+					Reducer:    reducer("last"),
+					Operator:   "and",
+					Evaluator:  &thresholdEvaluator{Type: "lt", Threshold: 2},
+				},
+			}},
+		expected: func() mathexp.Results {
+			a := newNumberWithLabels(hostA, util.Pointer(1.0))
+			a.SetMeta([]EvalMatch{{Value: util.Pointer(10.0), Labels: hostA}, {Value: util.Pointer(1.0)}})
+			b := newNumberWithLabels(hostB, util.Pointer(1.0))
+			b.SetMeta([]EvalMatch{{Value: util.Pointer(10.0), Labels: hostB}, {Value: util.Pointer(1.0)}})
+			return newResults(a, b)
+		},
+	}, {
+		name: "series missing from a condition is no data",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{
+					newSeriesWithLabels(hostA, util.Pointer(10.0)),
+					newSeriesWithLabels(hostB, util.Pointer(10.0)),
+				},
+			},
+			"B": mathexp.Results{
+				Values: []mathexp.Value{
+					newSeriesWithLabels(hostA, util.Pointer(10.0)),
+				},
+			},
+		},
+		cmd: &ConditionsCmd{
+			GroupByLabels: true,
+			Conditions: []condition{
+				{
+					InputRefID: "A",
+					Reducer:    reducer("last"),
+					Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 5},
+				},
+				{
+					InputRefID: "B",
+					Reducer:    reducer("last"),
+					Operator:   "or",
+					Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 5},
+				},
+			}},
+		expected: func() mathexp.Results {
+			a := newNumberWithLabels(hostA, util.Pointer(1.0))
+			a.SetMeta([]EvalMatch{{Value: util.Pointer(10.0), Labels: hostA}, {Value: util.Pointer(10.0), Labels: hostA}})
+			b := newNumberWithLabels(hostB, nil)
+			b.SetMeta([]EvalMatch{{Value: util.Pointer(10.0), Labels: hostB}, {Metric: "NoData", Labels: hostB}})
+			return newResults(a, b)
+		},
+	}, {
+		name: "series without values is no data",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{
+					newSeriesWithLabels(hostA, util.Pointer(10.0)),
+					newSeriesWithLabels(hostB, nil),
+				},
+			},
+		},
+		cmd: &ConditionsCmd{
+			GroupByLabels: true,
+			Conditions: []condition{
+				{
+					InputRefID: "A",
+					Reducer:    reducer("last"),
+					Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 5},
+				},
+			}},
+		expected: func() mathexp.Results {
+			a := newNumberWithLabels(hostA, util.Pointer(1.0))
+			a.SetMeta([]EvalMatch{{Value: util.Pointer(10.0), Labels: hostA}})
+			b := newNumberWithLabels(hostB, nil)
+			b.SetMeta([]EvalMatch{{Metric: "NoData", Labels: hostB}})
+			return newResults(a, b)
+		},
+	}, {
+		name: "no values returns a single result without labels",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{},
+		},
+		cmd: &ConditionsCmd{
+			GroupByLabels: true,
+			Conditions: []condition{
+				{
+					InputRefID: "A",
+					Reducer:    reducer("last"),
+					Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 5},
+				},
+			}},
+		expected: func() mathexp.Results {
+			v := newNumber(nil)
+			v.SetMeta([]EvalMatch{{Metric: "NoData"}})
+			return newResults(v)
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.cmd.Execute(context.Background(), time.Now(), tt.vars, tracing.InitializeTracerForTest(), nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected(), res)
+		})
+	}
+}
+
 func TestUnmarshalConditionsCmd(t *testing.T) {
 	var tests = []struct {
 		name            string
@@ -732,6 +897,47 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 			},
 			needsVars: []string{"A"},
 		},
+		{
+			name: "group by labels",
+			rawJSON: `{
+				"groupByLabels": true,
+				"conditions": [
+				  {
+					"evaluator": {
+					  "params": [
+						2
+					  ],
+					  "type": "gt"
+					},
+					"operator": {
+					  "type": "and"
+					},
+					"query": {
+					  "params": [
+						"A"
+					  ]
+					},
+					"reducer": {
+					  "params": [],
+					  "type": "avg"
+					},
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				GroupByLabels: true,
+				Conditions: []condition{
+					{
+						InputRefID: "A",
+						Reducer:    reducer("avg"),
+						Operator:   "and",
+						Evaluator:  &thresholdEvaluator{Type: "gt", Threshold: 2},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,204 @@
+package classic
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// labeledCondResult is the outcome of a condition for a single set of labels.
+type labeledCondResult struct {
+	labels   data.Labels
+	isFiring bool
+	isNoData bool
+	matches  []EvalMatch
+}
+
+// executeByLabels evaluates the conditions separately for each set of labels in the input
+// data and returns one number per set of labels. The number is 1, 0 or nil depending on
+// whether the conditions are firing, normal or no data for that set of labels, and has
+// the matches for those labels in its metadata.
+//
+// The sets of labels in the result are the union of the labels of all conditions, where
+// labels that are a subset of other labels are dropped. When a condition has no result
+// for a set of labels, the result with the most specific subset of those labels is used
+// instead. This means that a condition on a single value without labels, or on series
+// with fewer labels, applies to all sets of labels. If there is no such result either,
+// the condition is no data for that set of labels.
+func (cmd *ConditionsCmd) executeByLabels(_ context.Context, _ time.Time, vars mathexp.Vars) (mathexp.Results, error) {
+	condResults := make([][]labeledCondResult, 0, len(cmd.Conditions))
+	for _, cond := range cmd.Conditions {
+		results, err := cmd.executeCondByLabels(cond, vars)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		condResults = append(condResults, results)
+	}
+
+	res := mathexp.Results{}
+	for _, labels := range resultLabels(condResults) {
+		var isFiring, isNoData bool
+		matches := make([]EvalMatch, 0)
+		for i, cond := range cmd.Conditions {
+			// Avoid operate subsequent conditions for LogicOr when it is already firing, see #87483
+			if isFiring && cond.Operator == ConditionOperatorLogicOr {
+				break
+			}
+
+			isCondFiring, isCondNoData := false, true
+			condMatches := []EvalMatch{{Metric: "NoData", Labels: copyLabels(labels)}}
+			if r := findLabeledCondResult(condResults[i], labels); r != nil {
+				isCondFiring, isCondNoData, condMatches = r.isFiring, r.isNoData, r.matches
+			}
+
+			if i == 0 {
+				isFiring = isCondFiring
+				isNoData = isCondNoData
+			} else {
+				isFiring = compareWithOperator(isFiring, isCondFiring, cond.Operator)
+				isNoData = compareWithOperator(isNoData, isCondNoData, cond.Operator)
+			}
+
+			matches = append(matches, condMatches...)
+		}
+
+		number := mathexp.NewNumber("", labels)
+		number.SetMeta(matches)
+
+		var v float64
+		if isNoData {
+			number.SetValue(nil)
+		} else if isFiring {
+			v = 1
+			number.SetValue(&v)
+		} else {
+			number.SetValue(&v)
+		}
+		res.Values = append(res.Values, number)
+	}
+
+	return res, nil
+}
+
+// executeCondByLabels evaluates a condition for each set of labels in its input data.
+// Values with the same labels are evaluated together as in executeCond.
+func (cmd *ConditionsCmd) executeCondByLabels(cond condition, vars mathexp.Vars) ([]labeledCondResult, error) {
+	input := vars[cond.InputRefID]
+
+	if len(input.Values) == 0 {
+		if cond.Evaluator.Kind() == EvaluatorNoValue {
+			return []labeledCondResult{{isFiring: true, matches: []EvalMatch{{Value: nil}}}}, nil
+		}
+		return []labeledCondResult{{isNoData: true, matches: []EvalMatch{{Metric: "NoData"}}}}, nil
+	}
+
+	var results []*labeledCondResult
+	byFingerprint := make(map[data.Fingerprint]*labeledCondResult)
+	numNoData := make(map[data.Fingerprint]int)
+	numValues := make(map[data.Fingerprint]int)
+
+	for _, value := range input.Values {
+		name, number, err := reduceValue(cond, value)
+		if err != nil {
+			return nil, err
+		}
+
+		labels := number.GetLabels()
+		fp := labels.Fingerprint()
+		r, ok := byFingerprint[fp]
+		if !ok {
+			r = &labeledCondResult{labels: copyLabels(labels), matches: make([]EvalMatch, 0)}
+			byFingerprint[fp] = r
+			results = append(results, r)
+		}
+		numValues[fp]++
+
+		if cond.Evaluator.Eval(number) {
+			r.isFiring = true
+			r.matches = append(r.matches, EvalMatch{
+				Metric: name,
+				Value:  number.GetFloat64Value(),
+				Labels: copyLabels(r.labels),
+			})
+		} else if number.GetFloat64Value() == nil {
+			numNoData[fp]++
+		}
+	}
+
+	condResults := make([]labeledCondResult, 0, len(results))
+	for _, r := range results {
+		fp := r.labels.Fingerprint()
+		r.isNoData = numNoData[fp] == numValues[fp]
+		if r.isNoData {
+			r.matches = append(r.matches, EvalMatch{Metric: "NoData", Labels: copyLabels(r.labels)})
+		}
+		condResults = append(condResults, *r)
+	}
+	return condResults, nil
+}
+
+// resultLabels returns the sets of labels of the result, sorted so that the
+// order of the results is stable.
+func resultLabels(condResults [][]labeledCondResult) []data.Labels {
+	var all []data.Labels
+	seen := make(map[data.Fingerprint]struct{})
+	for _, results := range condResults {
+		for _, r := range results {
+			fp := r.labels.Fingerprint()
+			if _, ok := seen[fp]; ok {
+				continue
+			}
+			seen[fp] = struct{}{}
+			all = append(all, r.labels)
+		}
+	}
+
+	labels := make([]data.Labels, 0, len(all))
+	for _, l := range all {
+		isSubset := false
+		for _, other := range all {
+			if len(other) > len(l) && other.Contains(l) {
+				isSubset = true
+				break
+			}
+		}
+		if !isSubset {
+			labels = append(labels, l)
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].String() < labels[j].String()
+	})
+	return labels
+}
+
+// findLabeledCondResult returns the result with the same labels, or otherwise the result
+// with the largest subset of the labels. It returns nil if there is no such result.
+func findLabeledCondResult(results []labeledCondResult, labels data.Labels) *labeledCondResult {
+	var found *labeledCondResult
+	for i := range results {
+		r := &results[i]
+		if !labels.Contains(r.labels) {
+			continue
+		}
+		if len(r.labels) == len(labels) {
+			return r
+		}
+		if found == nil || len(r.labels) > len(found.labels) {
+			found = r
+		}
+	}
+	return found
+}
+
+func copyLabels(labels data.Labels) data.Labels {
+	if labels == nil {
+		return nil
+	}
+	return labels.Copy()
+}
@@ -412,6 +412,12 @@ func newNumber(f *float64) mathexp.Number {
 	return num
 }
 
+func newNumberWithLabels(labels data.Labels, f *float64) mathexp.Number {
+	num := mathexp.NewNumber("", labels)
+	num.SetValue(f)
+	return num
+}
+
 func newSeries(points ...*float64) mathexp.Series {
 	series := mathexp.NewSeries("", nil, len(points))
 	for idx, point := range points {
@@ -1,7 +1,12 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +73,18 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"label_replace": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString, parse.TypeString, parse.TypeString, parse.TypeString},
+		VariantReturn: true,
+		F:             labelReplace,
+		Check:         checkLabelReplace,
+	},
+	"label_join": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString, parse.TypeString, parse.TypeString},
+		VariantReturn: true,
+		Variadic:      true,
+		F:             labelJoin,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +324,87 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// labelReplace sets the dst label of each result in NumberSet or SeriesSet to the replacement
+// when the value of the src label matches the regular expression. The replacement may refer to
+// capture groups of the regular expression with $1, $2 or $name. The dst label is removed when
+// the replacement is empty, and results whose src label does not match are returned unchanged.
+func labelReplace(e *State, varSet Results, dst, replacement, src, regex string) (Results, error) {
+	re, err := compileLabelRegex(regex)
+	if err != nil {
+		return Results{}, err
+	}
+	return relabel(e, varSet, "label_replace", func(labels data.Labels) {
+		value := labels[src]
+		match := re.FindStringSubmatchIndex(value)
+		if match == nil {
+			return
+		}
+		if res := re.ExpandString(nil, replacement, value, match); len(res) > 0 {
+			labels[dst] = string(res)
+		} else {
+			delete(labels, dst)
+		}
+	})
+}
+
+// labelJoin sets the dst label of each result in NumberSet or SeriesSet to the values of
+// the src labels joined with the separator. The dst label is removed when the joined value is empty.
+func labelJoin(e *State, varSet Results, dst, separator string, src ...string) (Results, error) {
+	return relabel(e, varSet, "label_join", func(labels data.Labels) {
+		values := make([]string, 0, len(src))
+		for _, name := range src {
+			values = append(values, labels[name])
+		}
+		if joined := strings.Join(values, separator); joined != "" {
+			labels[dst] = joined
+		} else {
+			delete(labels, dst)
+		}
+	})
+}
+
+// checkLabelReplace validates the regular expression of label_replace when the expression is parsed.
+func checkLabelReplace(_ *parse.Tree, f *parse.FuncNode) error {
+	if _, err := compileLabelRegex(f.Args[4].(*parse.StringNode).Text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// compileLabelRegex compiles a regular expression that must match the entire label value.
+func compileLabelRegex(regex string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression in label_replace(): %s", regex)
+	}
+	return re, nil
+}
+
+// relabel returns a copy of each result with the labels modified by relabelF. It is an error
+// for the results to have the same labels after they are modified.
+func relabel(e *State, varSet Results, name string, relabelF func(labels data.Labels)) (Results, error) {
+	newRes := Results{}
+	seen := make(map[data.Fingerprint]struct{}, len(varSet.Values))
+	for _, res := range varSet.Values {
+		newVal, err := perNullableFloat(e, res, func(f *float64) *float64 { return f })
+		if err != nil {
+			return newRes, err
+		}
+		if t := res.Type(); t == parse.TypeNumberSet || t == parse.TypeSeriesSet {
+			labels := data.Labels{}
+			for k, v := range res.GetLabels() {
+				labels[k] = v
+			}
+			relabelF(labels)
+			fp := labels.Fingerprint()
+			if _, ok := seen[fp]; ok {
+				return newRes, fmt.Errorf("%s(): more than one result with the labels {%s}", name, labels)
+			}
+			seen[fp] = struct{}{}
+			newVal.SetLabels(labels)
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
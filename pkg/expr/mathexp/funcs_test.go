@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/stretchr/testify/require"
 )
@@ -140,3 +141,90 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelFuncs(t *testing.T) {
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name: "label_replace with capture group",
+			expr: `label_replace($A, "host", "$1", "instance", "(.*):.*")`,
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", data.Labels{"instance": "a:9100"}, float64Pointer(1))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", data.Labels{"instance": "a:9100", "host": "a"}, float64Pointer(1))),
+		},
+		{
+			name: "label_replace without a match leaves the labels unchanged",
+			expr: `label_replace($A, "host", "$1", "instance", "(.*):80")`,
+			vars: Vars{
+				"A": resultValuesNoErr(makeSeries("", data.Labels{"instance": "a:9100"}, tp{time.Unix(5, 0), float64Pointer(1)})),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeSeries("", data.Labels{"instance": "a:9100"}, tp{time.Unix(5, 0), float64Pointer(1)})),
+		},
+		{
+			name: "label_replace with empty replacement removes the label",
+			expr: `label_replace($A, "instance", "", "instance", ".*")`,
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", data.Labels{"instance": "a:9100", "job": "node"}, float64Pointer(1))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", data.Labels{"job": "node"}, float64Pointer(1))),
+		},
+		{
+			name: "label_replace resulting in duplicate labels should error",
+			expr: `label_replace($A, "instance", "", "instance", ".*")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeNumber("", data.Labels{"instance": "a:9100"}, float64Pointer(1)),
+					makeNumber("", data.Labels{"instance": "b:9100"}, float64Pointer(2)),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "label_replace with invalid regex should error",
+			expr:     `label_replace($A, "host", "$1", "instance", "(.*")`,
+			newErrIs: require.Error,
+		},
+		{
+			name: "label_join",
+			expr: `label_join($A, "id", "-", "host", "cpu", "missing")`,
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", data.Labels{"host": "a", "cpu": "0"}, float64Pointer(1))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", data.Labels{"host": "a", "cpu": "0", "id": "a-0-"}, float64Pointer(1))),
+		},
+		{
+			name:     "label_join without source labels should error",
+			expr:     `label_join($A, "id", "-")`,
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				if err == nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package mathexp
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// matchingUnion creates Union objects for a binary operation with vector matching
+// modifiers, e.g. $A / on(host) group_left(region) $B. Values are paired when their
+// labels are equal after applying on or ignoring to them. Unlike union, a value
+// on the "one" side of the operation must be unique for its matching labels, and
+// values without a match are dropped.
+func (e *State) matchingUnion(aResults, bResults Results, biNode *parse.BinaryNode) ([]*Union, error) {
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return []*Union{}, nil
+	}
+	if isNoDataResults(aResults) || isNoDataResults(bResults) {
+		// No data on a side is handled the same way as without matching modifiers.
+		return e.union(aResults, bResults, biNode), nil
+	}
+
+	m := biNode.Matching
+	aMatched := make([]bool, len(aResults.Values))
+	bMatched := make([]bool, len(bResults.Values))
+
+	// The "many" side of the operation is the left side unless it is group_right.
+	// For one-to-one matching both sides must be unique, which is checked for the
+	// many side while pairing the values.
+	many, one := aResults.Values, bResults.Values
+	manyMatched, oneMatched := aMatched, bMatched
+	manySide, oneSide := "left", "right"
+	if m.Card == parse.CardOneToMany {
+		many, one = one, many
+		manyMatched, oneMatched = oneMatched, manyMatched
+		manySide, oneSide = oneSide, manySide
+	}
+
+	oneByKey := make(map[string]int, len(one))
+	for i, v := range one {
+		if v.Type() == parse.TypeNoData {
+			continue
+		}
+		key := matchingLabels(v.GetLabels(), m).String()
+		if _, ok := oneByKey[key]; ok {
+			return nil, fmt.Errorf("found duplicate series for the match group {%s} on the %s hand side of the operation %q, many-to-many matching is not allowed", key, oneSide, biNode)
+		}
+		oneByKey[key] = i
+	}
+
+	unions := []*Union{}
+	seen := make(map[string]bool, len(many))
+	for i, v := range many {
+		if v.Type() == parse.TypeNoData {
+			continue
+		}
+		key := matchingLabels(v.GetLabels(), m).String()
+		j, ok := oneByKey[key]
+		if !ok {
+			continue
+		}
+		if m.Card == parse.CardOneToOne {
+			if seen[key] {
+				return nil, fmt.Errorf("found duplicate series for the match group {%s} on the %s hand side of the operation %q, use group_left or group_right for many-to-one matching", key, manySide, biNode)
+			}
+			seen[key] = true
+		}
+
+		u := &Union{
+			Labels: joinedLabels(v.GetLabels(), one[j].GetLabels(), m),
+			A:      v,
+			B:      one[j],
+		}
+		if m.Card == parse.CardOneToMany {
+			u.A, u.B = u.B, u.A
+		}
+		unions = append(unions, u)
+		manyMatched[i] = true
+		oneMatched[j] = true
+	}
+
+	e.collectDrops(biNode, aMatched, bMatched, aResults, bResults)
+	return unions, nil
+}
+
+func isNoDataResults(r Results) bool {
+	return len(r.Values) == 1 && r.Values[0].Type() == parse.TypeNoData
+}
+
+// matchingLabels returns the labels that are used to match values with on or ignoring.
+func matchingLabels(labels data.Labels, m *parse.VectorMatching) data.Labels {
+	matching := data.Labels{}
+	if m.On {
+		for _, name := range m.MatchingLabels {
+			if value, ok := labels[name]; ok {
+				matching[name] = value
+			}
+		}
+		return matching
+	}
+	for name, value := range labels {
+		matching[name] = value
+	}
+	for _, name := range m.MatchingLabels {
+		delete(matching, name)
+	}
+	return matching
+}
+
+// joinedLabels returns the labels of the result of a matched pair of values. For
+// one-to-one matching these are the matching labels. Otherwise they are the labels
+// of the value on the "many" side, where the included labels are copied from the
+// value on the "one" side, or removed if that value does not have them.
+func joinedLabels(manyLabels, oneLabels data.Labels, m *parse.VectorMatching) data.Labels {
+	if m.Card == parse.CardOneToOne {
+		return matchingLabels(manyLabels, m)
+	}
+	labels := manyLabels.Copy()
+	for _, name := range m.Include {
+		if value, ok := oneLabels[name]; ok && value != "" {
+			labels[name] = value
+		} else {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
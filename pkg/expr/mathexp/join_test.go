@@ -0,0 +1,178 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestVectorMatching(t *testing.T) {
+	cpu := resultValuesNoErr(
+		makeNumber("", data.Labels{"host": "a", "cpu": "0", "region": "eu"}, float64Pointer(2)),
+		makeNumber("", data.Labels{"host": "a", "cpu": "1", "region": "eu"}, float64Pointer(4)),
+		makeNumber("", data.Labels{"host": "b", "cpu": "0", "region": "us"}, float64Pointer(6)),
+	)
+	capacity := resultValuesNoErr(
+		makeNumber("", data.Labels{"host": "a", "team": "x"}, float64Pointer(8)),
+		makeNumber("", data.Labels{"host": "b", "team": "y"}, float64Pointer(12)),
+		makeNumber("", data.Labels{"host": "c", "team": "z"}, float64Pointer(1)),
+	)
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+		drops     int64
+	}{
+		{
+			name: "on with one-to-one matching keeps the matching labels",
+			expr: "$A + on(host) $B",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeNumber("", data.Labels{"host": "a", "job": "node"}, float64Pointer(1)),
+					makeNumber("", data.Labels{"host": "b", "job": "node"}, float64Pointer(2)),
+				),
+				"B": capacity,
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(9)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(14)),
+			),
+			drops: 1,
+		},
+		{
+			name: "ignoring with one-to-one matching removes the ignored labels",
+			expr: "$A - ignoring(job) $B",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", data.Labels{"host": "a", "job": "node"}, float64Pointer(5))),
+				"B": resultValuesNoErr(makeNumber("", data.Labels{"host": "a", "job": "cadvisor"}, float64Pointer(3))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", data.Labels{"host": "a"}, float64Pointer(2))),
+		},
+		{
+			name:      "one-to-one matching with duplicates on the left should error",
+			expr:      "$A / on(host) $B",
+			vars:      Vars{"A": cpu, "B": capacity},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "group_left divides many series by one series with different labels",
+			expr:      "$A / on(host) group_left(team) $B",
+			vars:      Vars{"A": cpu, "B": capacity},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "cpu": "0", "region": "eu", "team": "x"}, float64Pointer(0.25)),
+				makeNumber("", data.Labels{"host": "a", "cpu": "1", "region": "eu", "team": "x"}, float64Pointer(0.5)),
+				makeNumber("", data.Labels{"host": "b", "cpu": "0", "region": "us", "team": "y"}, float64Pointer(0.5)),
+			),
+			drops: 1,
+		},
+		{
+			name:      "group_right keeps the labels of the right side",
+			expr:      "$B * on(host) group_right $A",
+			vars:      Vars{"A": cpu, "B": capacity},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "cpu": "0", "region": "eu"}, float64Pointer(16)),
+				makeNumber("", data.Labels{"host": "a", "cpu": "1", "region": "eu"}, float64Pointer(32)),
+				makeNumber("", data.Labels{"host": "b", "cpu": "0", "region": "us"}, float64Pointer(72)),
+			),
+			drops: 1,
+		},
+		{
+			name:      "group_left with duplicates on the one side should error",
+			expr:      "$B / on(host) group_left $A",
+			vars:      Vars{"A": cpu, "B": capacity},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "group_left on series",
+			expr: `$A / on("host.name") group_left $B`,
+			vars: Vars{
+				"A": resultValuesNoErr(makeSeries("", data.Labels{"host.name": "a", "cpu": "0"},
+					tp{time.Unix(5, 0), float64Pointer(2)},
+					tp{time.Unix(10, 0), float64Pointer(4)},
+				)),
+				"B": resultValuesNoErr(makeNumber("", data.Labels{"host.name": "a"}, float64Pointer(8))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(makeSeries("", data.Labels{"host.name": "a", "cpu": "0"},
+				tp{time.Unix(5, 0), float64Pointer(0.25)},
+				tp{time.Unix(10, 0), float64Pointer(0.5)},
+			)),
+		},
+		{
+			name: "matching with no data",
+			expr: "$A / on(host) $B",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", data.Labels{"host": "a"}, float64Pointer(2))),
+				"B": resultValuesNoErr(NewNoData()),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewNoData()),
+			drops:     1,
+		},
+		{
+			name:     "group_left without on or ignoring should error",
+			expr:     "$A / group_left $B",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "matching with scalar should error",
+			expr:     "$A / on(host) 2",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "label in on and group_left should error",
+			expr:     "$A / on(host) group_left(host) $B",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e == nil {
+				return
+			}
+			s := &State{Expr: e, Vars: tt.vars, tracer: tracing.InitializeTracerForTest()}
+			res, err := s.walk(e.Root)
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tt.results.Values, res.Values)
+			require.Equal(t, tt.drops, s.DropCount)
+		})
+	}
+}
+
+func TestVectorMatchingString(t *testing.T) {
+	for _, expr := range []string{
+		"$A / on(host) $B",
+		"$A / ignoring(cpu, job) group_left $B",
+		`$A / on("host.name") group_right(team, region) $B`,
+		"$A / on() $B",
+	} {
+		e, err := New(expr)
+		require.NoError(t, err)
+		require.Equal(t, expr, e.Root.String())
+	}
+}
@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"unicode"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -165,12 +166,12 @@ func (f *FuncNode) StringAST() string {
 func (f *FuncNode) Check(t *Tree) error {
 	if len(f.Args) < len(f.F.Args) {
 		return fmt.Errorf("parse: not enough arguments for %s", f.Name)
-	} else if len(f.Args) > len(f.F.Args) {
+	} else if len(f.Args) > len(f.F.Args) && !f.F.Variadic {
 		return fmt.Errorf("parse: too many arguments for %s", f.Name)
 	}
 
 	for i, arg := range f.Args {
-		funcType := f.F.Args[i]
+		funcType := f.F.Args[min(i, len(f.F.Args)-1)]
 		argType := arg.Return()
 		// if funcType == TypeNumberSet && argType == TypeScalar {
 		// 	argType = TypeNumberSet
@@ -300,6 +301,9 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// Matching holds the optional vector matching modifiers of the operation.
+	// When nil, the arguments are matched on equal labels or label subsets.
+	Matching *VectorMatching
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -308,16 +312,39 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s[%s](%s, %s)", b.Operator.val, b.Matching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
+	if b.Matching == nil {
+		return nil
+	}
+	for _, arg := range b.Args {
+		if rt := arg.Return(); rt == TypeScalar {
+			return fmt.Errorf("parse: vector matching in %s is only allowed between numbers and series, got %s", b, rt)
+		}
+	}
+	if b.Matching.On {
+		for _, include := range b.Matching.Include {
+			for _, label := range b.Matching.MatchingLabels {
+				if include == label {
+					return fmt.Errorf("parse: label %q must not occur in on and %s at the same time", label, b.Matching.Card.groupModifier())
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -331,6 +358,87 @@ func (b *BinaryNode) Return() ReturnType {
 	return t0
 }
 
+// VectorMatchCardinality describes how values on the two sides of a binary operation are paired.
+type VectorMatchCardinality int
+
+const (
+	// CardOneToOne pairs each value on the left with at most one value on the right.
+	CardOneToOne VectorMatchCardinality = iota
+	// CardManyToOne pairs many values on the left with one value on the right (group_left).
+	CardManyToOne
+	// CardOneToMany pairs one value on the left with many values on the right (group_right).
+	CardOneToMany
+)
+
+func (c VectorMatchCardinality) groupModifier() string {
+	switch c {
+	case CardManyToOne:
+		return "group_left"
+	case CardOneToMany:
+		return "group_right"
+	default:
+		return ""
+	}
+}
+
+// VectorMatching holds the label matching modifiers of a binary operation,
+// for example on(host) group_left(region).
+type VectorMatching struct {
+	// Card is the cardinality of the matching.
+	Card VectorMatchCardinality
+	// On is true if MatchingLabels are the only labels to match on (on), and false
+	// if they are the labels to leave out when matching (ignoring).
+	On             bool
+	MatchingLabels []string
+	// Include are labels copied from the "one" side to the result of a
+	// many-to-one or one-to-many matching.
+	Include []string
+}
+
+// String returns the string representation of the modifiers as they are written in an expression.
+func (m *VectorMatching) String() string {
+	modifier := "ignoring"
+	if m.On {
+		modifier = "on"
+	}
+	s := modifier + labelList(m.MatchingLabels)
+	if m.Card != CardOneToOne {
+		s += " " + m.Card.groupModifier()
+		if len(m.Include) > 0 {
+			s += labelList(m.Include)
+		}
+	}
+	return s
+}
+
+func labelList(labels []string) string {
+	s := "("
+	for i, l := range labels {
+		if i > 0 {
+			s += ", "
+		}
+		if isLabelName(l) {
+			s += l
+		} else {
+			s += strconv.Quote(l)
+		}
+	}
+	return s + ")"
+}
+
+// isLabelName reports whether a label can be written in an expression without quotes.
+func isLabelName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && !(i > 0 && unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
 // UnaryNode holds one argument and an operator.
 type UnaryNode struct {
 	NodeType
@@ -72,6 +72,9 @@ type ThresholdQuery struct {
 
 type ClassicQuery struct {
 	Conditions []classic.ConditionJSON `json:"conditions"`
+
+	// Evaluate the conditions for each set of labels and return one result per set of labels
+	GroupByLabels bool `json:"groupByLabels,omitempty"`
 }
 
 // SQLQuery requires the sqlExpression feature flag
@@ -124,7 +124,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 		err = iter.ReadVal(q)
 		if err == nil {
 			eq.Properties = q
-			eq.Command, err = classic.NewConditionCmd(common.RefID, q.Conditions)
+			eq.Command, err = classic.NewConditionCmd(common.RefID, q.Conditions, q.GroupByLabels)
 		}
 
 	case QueryTypeSQL:
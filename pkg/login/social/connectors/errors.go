@@ -7,8 +7,9 @@ import (
 )
 
 var (
-	ErrIDTokenNotFound = errors.New("id_token not found")
-	ErrEmailNotFound   = errors.New("error getting user info: no email found in access token")
+	ErrIDTokenNotFound         = errors.New("id_token not found")
+	ErrEmailNotFound           = errors.New("error getting user info: no email found in access token")
+	ErrDeviceAuthNotConfigured = errors.New("device authorization url is not configured")
 
 	errRoleAttributePathNotSet = errutil.BadRequest("oauth.role_attribute_path_not_set",
 		errutil.WithPublicMessage("Instance role_attribute_path misconfigured, please contact your administrator"))
@@ -25,6 +25,7 @@ const (
 	nameAttributePathKey    = "name_attribute_path"
 	loginAttributePathKey   = "login_attribute_path"
 	idTokenAttributeNameKey = "id_token_attribute_name" // #nosec G101 not a hardcoded credential
+	deviceAuthURLKey        = "device_auth_url"
 )
 
 var ExtraGenericOAuthSettingKeys = map[string]ExtraKeyInfo{
@@ -33,9 +34,11 @@ var ExtraGenericOAuthSettingKeys = map[string]ExtraKeyInfo{
 	idTokenAttributeNameKey: {Type: String},
 	teamIdsKey:              {Type: String},
 	allowedOrganizationsKey: {Type: String},
+	deviceAuthURLKey:        {Type: String},
 }
 
 var _ social.SocialConnector = (*SocialGenericOAuth)(nil)
+var _ social.DeviceAuthorizationConnector = (*SocialGenericOAuth)(nil)
 var _ ssosettings.Reloadable = (*SocialGenericOAuth)(nil)
 
 type SocialGenericOAuth struct {
@@ -50,6 +53,7 @@ type SocialGenericOAuth struct {
 	idTokenAttributeName string
 	teamIdsAttributePath string
 	teamIds              []string
+	deviceAuthURL        string
 }
 
 func NewGenericOAuthProvider(info *social.OAuthInfo, cfg *setting.Cfg, orgRoleMapper *OrgRoleMapper, ssoSettings ssosettings.Service, features featuremgmt.FeatureToggles) *SocialGenericOAuth {
@@ -77,6 +81,7 @@ func NewGenericOAuthProvider(info *social.OAuthInfo, cfg *setting.Cfg, orgRoleMa
 		teamIdsAttributePath: info.TeamIdsAttributePath,
 		teamIds:              teamIds,
 		allowedOrganizations: allowedOrganizations,
+		deviceAuthURL:        info.Extra[deviceAuthURLKey],
 	}
 
 	ssoSettings.RegisterReloadable(social.GenericOAuthProviderName, provider)
@@ -103,7 +108,8 @@ func (s *SocialGenericOAuth) Validate(ctx context.Context, newSettings ssoModels
 	err = validation.Validate(info, requester,
 		validation.UrlValidator(info.AuthUrl, "Auth URL"),
 		validation.UrlValidator(info.TokenUrl, "Token URL"),
-		validateTeamsUrlWhenNotEmpty)
+		validateTeamsUrlWhenNotEmpty,
+		validateDeviceAuthURLWhenNotEmpty)
 
 	if err != nil {
 		return err
@@ -128,6 +134,13 @@ func validateTeamsUrlWhenNotEmpty(info *social.OAuthInfo, requester identity.Req
 	return validation.UrlValidator(info.TeamsUrl, "Teams URL")(info, requester)
 }
 
+func validateDeviceAuthURLWhenNotEmpty(info *social.OAuthInfo, requester identity.Requester) error {
+	if info.Extra[deviceAuthURLKey] == "" {
+		return nil
+	}
+	return validation.UrlValidator(info.Extra[deviceAuthURLKey], "Device authorization URL")(info, requester)
+}
+
 func (s *SocialGenericOAuth) Reload(ctx context.Context, settings ssoModels.SSOSettings) error {
 	newInfo, err := CreateOAuthInfoFromKeyValuesWithLogging(s.log, social.GenericOAuthProviderName, settings.Settings)
 	if err != nil {
@@ -158,6 +171,7 @@ func (s *SocialGenericOAuth) Reload(ctx context.Context, settings ssoModels.SSOS
 	s.teamIdsAttributePath = newInfo.TeamIdsAttributePath
 	s.teamIds = teamIds
 	s.allowedOrganizations = allowedOrganizations
+	s.deviceAuthURL = newInfo.Extra[deviceAuthURLKey]
 
 	return nil
 }
@@ -729,6 +743,7 @@ func (s *SocialGenericOAuth) SupportBundleContent(bf *bytes.Buffer) error {
 	fmt.Fprintf(bf, "team_ids_attribute_path = %s\n", s.teamIdsAttributePath)
 	fmt.Fprintf(bf, "team_ids = %v\n", s.teamIds)
 	fmt.Fprintf(bf, "allowed_organizations = %v\n", s.allowedOrganizations)
+	fmt.Fprintf(bf, "device_auth_url = %v\n", s.deviceAuthURL)
 	bf.WriteString("```\n\n")
 
 	return s.getBaseSupportBundleContent(bf)
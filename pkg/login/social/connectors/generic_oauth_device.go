@@ -0,0 +1,133 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuth starts an OAuth 2.0 device authorization grant (RFC 8628) with the provider.
+func (s *SocialGenericOAuth) DeviceAuth(ctx context.Context) (*oauth2.DeviceAuthResponse, error) {
+	config, err := s.deviceAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.DeviceAuth(ctx)
+}
+
+// DeviceAccessToken requests a token for the device code once. Unlike oauth2.Config.DeviceAccessToken
+// it doesn't wait for the user to approve the device, the device is expected to poll Grafana instead.
+func (s *SocialGenericOAuth) DeviceAccessToken(ctx context.Context, deviceCode string) (*oauth2.Token, error) {
+	config, err := s.deviceAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// https://datatracker.ietf.org/doc/html/rfc8628#section-3.4
+	v := url.Values{
+		"client_id":   {config.ClientID},
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceCode},
+	}
+	if config.Endpoint.AuthStyle == oauth2.AuthStyleInParams && config.ClientSecret != "" {
+		v.Set("client_secret", config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Endpoint.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if config.Endpoint.AuthStyle != oauth2.AuthStyleInParams && config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+	}
+
+	client := http.DefaultClient
+	if c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		client = c
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device token response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		var errResponse struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		// the error code is optional in the returned error, the body is kept for the details
+		_ = json.Unmarshal(body, &errResponse)
+		return nil, &oauth2.RetrieveError{
+			Response:         res,
+			Body:             body,
+			ErrorCode:        errResponse.Error,
+			ErrorDescription: errResponse.ErrorDescription,
+		}
+	}
+
+	var tokenResponse struct {
+		AccessToken  string      `json:"access_token"`
+		TokenType    string      `json:"token_type"`
+		RefreshToken string      `json:"refresh_token"`
+		ExpiresIn    json.Number `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode device token response: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("device token response is missing the access token")
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode device token response: %w", err)
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  tokenResponse.AccessToken,
+		TokenType:    tokenResponse.TokenType,
+		RefreshToken: tokenResponse.RefreshToken,
+	}
+	if expiresIn, err := tokenResponse.ExpiresIn.Int64(); err == nil && expiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return token.WithExtra(raw), nil
+}
+
+func (s *SocialGenericOAuth) deviceAuthConfig() (*oauth2.Config, error) {
+	s.reloadMutex.RLock()
+	defer s.reloadMutex.RUnlock()
+
+	if s.deviceAuthURL == "" {
+		return nil, ErrDeviceAuthNotConfigured
+	}
+
+	config := *s.Config
+	config.Endpoint.DeviceAuthURL = s.deviceAuthURL
+
+	return &config, nil
+}
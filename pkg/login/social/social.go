@@ -60,6 +60,16 @@ type SocialConnector interface {
 	SupportBundleContent(*bytes.Buffer) error
 }
 
+// DeviceAuthorizationConnector is an optional interface that connectors can implement
+// to support the OAuth 2.0 device authorization grant (RFC 8628).
+type DeviceAuthorizationConnector interface {
+	// DeviceAuth starts a device authorization and returns the codes shown to the user
+	DeviceAuth(ctx context.Context) (*oauth2.DeviceAuthResponse, error)
+	// DeviceAccessToken makes a single token request for the device code, pending
+	// authorizations are returned as an *oauth2.RetrieveError with the RFC 8628 error code
+	DeviceAccessToken(ctx context.Context, deviceCode string) (*oauth2.Token, error)
+}
+
 type OAuthInfo struct {
 	AllowAssignGrafanaAdmin     bool              `mapstructure:"allow_assign_grafana_admin" toml:"allow_assign_grafana_admin"`
 	AllowSignup                 bool              `mapstructure:"allow_sign_up" toml:"allow_sign_up"`
@@ -47,6 +47,8 @@ type QueryData struct {
 	TimeInterval       string
 	exemplarSampler    func() exemplar.Sampler
 	featureToggles     backend.FeatureToggles
+	split              splitSettings
+	chunkCache         *chunkCache
 }
 
 func New(
@@ -74,6 +76,16 @@ func New(
 		return nil, err
 	}
 
+	split, err := parseSplitSettings(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	var cache *chunkCache
+	if split.Duration > 0 {
+		cache = newChunkCache(chunkCacheTTL, chunkCacheMaxEntries)
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL, queryTimeout)
 
 	// standard deviation sampler is the default for backwards compatibility
@@ -89,6 +101,8 @@ func New(
 		URL:                settings.URL,
 		exemplarSampler:    exemplarSampler,
 		featureToggles:     featureToggles,
+		split:              split,
+		chunkCache:         cache,
 	}, nil
 }
 
@@ -111,9 +125,10 @@ func (s *QueryData) Execute(ctx context.Context, req *backend.QueryDataRequest)
 		concurrentQueryCount = 10
 	}
 
+	cacheScope := chunkCacheScope(req)
 	_ = concurrency.ForEachJob(ctx, len(req.Queries), concurrentQueryCount, func(ctx context.Context, idx int) error {
 		query := req.Queries[idx]
-		r := s.handleQuery(ctx, query, fromAlert, hasPromQLScopeFeatureFlag)
+		r := s.handleQuery(ctx, query, fromAlert, hasPromQLScopeFeatureFlag, cacheScope)
 		if r != nil {
 			m.Lock()
 			result.Responses[query.RefID] = *r
@@ -126,7 +141,7 @@ func (s *QueryData) Execute(ctx context.Context, req *backend.QueryDataRequest)
 }
 
 func (s *QueryData) handleQuery(ctx context.Context, bq backend.DataQuery, fromAlert,
-	hasPromQLScopeFeatureFlag bool) *backend.DataResponse {
+	hasPromQLScopeFeatureFlag bool, cacheScope string) *backend.DataResponse {
 	traceCtx, span := s.tracer.Start(ctx, "datasource.prometheus")
 	defer span.End()
 	query, err := models.Parse(span, bq, s.TimeInterval, s.intervalCalculator, fromAlert, hasPromQLScopeFeatureFlag)
@@ -136,14 +151,14 @@ func (s *QueryData) handleQuery(ctx context.Context, bq backend.DataQuery, fromA
 		}
 	}
 
-	r := s.fetch(traceCtx, s.client, query)
+	r := s.fetch(traceCtx, s.client, query, cacheScope)
 	if r == nil {
 		s.log.FromContext(ctx).Debug("Received nil response from runQuery", "query", query.Expr)
 	}
 	return r
 }
 
-func (s *QueryData) fetch(traceCtx context.Context, client *client.Client, q *models.Query) *backend.DataResponse {
+func (s *QueryData) fetch(traceCtx context.Context, client *client.Client, q *models.Query, cacheScope string) *backend.DataResponse {
 	logger := s.log.FromContext(traceCtx)
 	logger.Debug("Sending query", "start", q.Start, "end", q.End, "step", q.Step, "query", q.Expr /*, "queryTimeout", s.QueryTimeout*/)
 
@@ -172,7 +187,7 @@ func (s *QueryData) fetch(traceCtx context.Context, client *client.Client, q *mo
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			res := s.rangeQuery(traceCtx, client, q)
+			res := s.splitRangeQuery(traceCtx, client, q, cacheScope)
 			m.Lock()
 			addDataResponse(&res, dr)
 			m.Unlock()
@@ -0,0 +1,366 @@
+package querydata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/utils/maputil"
+
+	"github.com/grafana/grafana/pkg/promlib/client"
+	"github.com/grafana/grafana/pkg/promlib/models"
+)
+
+const (
+	defaultSplitConcurrency = 4
+	// defaultOverlapWindow matches the default of the frontend incremental querying,
+	// chunks ending within this window from now are never cached.
+	defaultOverlapWindow = 10 * time.Minute
+	chunkCacheTTL        = time.Hour
+	chunkCacheMaxEntries = 1000
+)
+
+// splitSettings configures splitting of long range queries into smaller chunks.
+type splitSettings struct {
+	// Duration is the maximum duration of a single chunk. Splitting is disabled when zero.
+	Duration time.Duration
+	// Concurrency is the maximum number of chunks queried at the same time.
+	Concurrency int
+	// OverlapWindow is the period before now in which results may still change.
+	OverlapWindow time.Duration
+}
+
+func parseSplitSettings(jsonData map[string]any) (splitSettings, error) {
+	settings := splitSettings{
+		Concurrency:   defaultSplitConcurrency,
+		OverlapWindow: defaultOverlapWindow,
+	}
+
+	splitDuration, err := maputil.GetStringOptional(jsonData, "querySplitDuration")
+	if err != nil {
+		return settings, err
+	}
+	if splitDuration != "" {
+		settings.Duration, err = gtime.ParseDuration(splitDuration)
+		if err != nil {
+			return settings, fmt.Errorf("invalid querySplitDuration %q: %w", splitDuration, err)
+		}
+	}
+
+	if v, ok := jsonData["querySplitConcurrency"].(float64); ok && v > 0 {
+		settings.Concurrency = int(v)
+	}
+
+	overlapWindow, err := maputil.GetStringOptional(jsonData, "incrementalQueryOverlapWindow")
+	if err != nil {
+		return settings, err
+	}
+	if overlapWindow != "" {
+		settings.OverlapWindow, err = gtime.ParseDuration(overlapWindow)
+		if err != nil {
+			return settings, fmt.Errorf("invalid incrementalQueryOverlapWindow %q: %w", overlapWindow, err)
+		}
+	}
+
+	return settings, nil
+}
+
+// queryChunk is a part of a range query produced by splitTimeRange.
+type queryChunk struct {
+	query *models.Query
+	// complete is true when the chunk covers a whole chunk interval. Only those
+	// are cached, as partial chunks at the start of a range change on every refresh.
+	complete bool
+}
+
+// splitTimeRange splits a range query into consecutive chunks of at most splitDuration.
+// Chunk boundaries are aligned to multiples of the chunk size rather than to the query
+// start, so that the same chunks are produced for a moving time range and can be reused.
+// The chunk size is a multiple of the step, chunks never share a sample timestamp.
+func splitTimeRange(q *models.Query, splitDuration time.Duration) []queryChunk {
+	tr := q.TimeRange()
+	if splitDuration <= 0 || tr.Step <= 0 || tr.End.Sub(tr.Start) <= splitDuration {
+		return []queryChunk{{query: q}}
+	}
+
+	chunkSize := splitDuration.Truncate(tr.Step)
+	if chunkSize < tr.Step {
+		chunkSize = tr.Step
+	}
+
+	var chunks []queryChunk
+	for start := tr.Start; !start.After(tr.End); {
+		chunkStart := models.AlignTimeRange(start, chunkSize, q.UtcOffsetSec)
+		next := chunkStart.Add(chunkSize)
+		end := next.Add(-tr.Step)
+		complete := chunkStart.Equal(start)
+		if end.After(tr.End) {
+			end = tr.End
+			complete = false
+		}
+
+		chunk := *q
+		chunk.Start = start
+		chunk.End = end
+		chunk.RangeQuery = true
+		chunk.InstantQuery = false
+		chunk.ExemplarQuery = false
+		chunks = append(chunks, queryChunk{query: &chunk, complete: complete})
+
+		start = next
+	}
+
+	return chunks
+}
+
+// splitRangeQuery runs a range query as multiple smaller range queries with bounded
+// concurrency and merges the results. Chunks that ended before the overlap window are
+// served from and stored in the chunk cache, under the cache scope of the request.
+func (s *QueryData) splitRangeQuery(ctx context.Context, c *client.Client, q *models.Query, cacheScope string) backend.DataResponse {
+	chunks := splitTimeRange(q, s.split.Duration)
+	if len(chunks) == 1 {
+		return s.rangeQuery(ctx, c, q)
+	}
+
+	cacheBefore := time.Now().Add(-s.split.OverlapWindow)
+	responses := make([]backend.DataResponse, len(chunks))
+	err := concurrency.ForEachJob(ctx, len(chunks), s.split.Concurrency, func(ctx context.Context, idx int) error {
+		chunk := chunks[idx]
+		cacheable := s.chunkCache != nil && chunk.complete && chunk.query.End.Before(cacheBefore)
+		key := chunkCacheKey(cacheScope, chunk.query)
+		if cacheable {
+			if res, ok := s.chunkCache.get(key); ok {
+				responses[idx] = res
+				return nil
+			}
+		}
+
+		res := s.rangeQuery(ctx, c, chunk.query)
+		if cacheable && res.Error == nil {
+			s.chunkCache.set(key, res)
+		}
+		responses[idx] = res
+		return nil
+	})
+	if err != nil {
+		return addErrorSourceToDataResponse(err)
+	}
+
+	merged := mergeChunkResponses(responses)
+	if len(merged.Frames) > 0 {
+		// the frame carrying the query metadata might have come from an empty chunk
+		frame := merged.Frames[0]
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		frame.Meta.ExecutedQueryString = executedQueryString(q)
+		frame.Meta.Custom = map[string]any{"calculatedMinStep": q.Step.Milliseconds()}
+	}
+	return merged
+}
+
+// mergeChunkResponses merges the responses of consecutive chunks of a range query into
+// a single response. Frames of the same series are concatenated in chunk order. The
+// returned frames are new frames, the chunk responses are not modified as they may be
+// shared through the chunk cache.
+func mergeChunkResponses(responses []backend.DataResponse) backend.DataResponse {
+	merged := backend.DataResponse{
+		Frames: data.Frames{},
+	}
+	seriesFrames := map[string]*data.Frame{}
+	var emptyFrame *data.Frame
+
+	for i := range responses {
+		res := responses[i]
+		if res.Error != nil {
+			addDataResponse(&backend.DataResponse{Error: res.Error, Status: res.Status}, &merged)
+			continue
+		}
+		if merged.Status == 0 {
+			merged.Status = res.Status
+		}
+
+		for _, frame := range res.Frames {
+			if len(frame.Fields) == 0 {
+				if emptyFrame == nil {
+					emptyFrame = frame
+				}
+				continue
+			}
+
+			key := seriesKey(frame)
+			existing, ok := seriesFrames[key]
+			if !ok || !appendFrameRows(existing, frame) {
+				copied := copyFrame(frame)
+				seriesFrames[key] = copied
+				merged.Frames = append(merged.Frames, copied)
+			}
+		}
+	}
+
+	if len(merged.Frames) == 0 && emptyFrame != nil {
+		merged.Frames = append(merged.Frames, copyFrame(emptyFrame))
+	}
+
+	return merged
+}
+
+// seriesKey identifies the series a frame belongs to.
+func seriesKey(frame *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(frame.Name)
+	if frame.Meta != nil {
+		sb.WriteString("|")
+		sb.WriteString(string(frame.Meta.Type))
+	}
+	for _, field := range frame.Fields {
+		sb.WriteString("|")
+		sb.WriteString(field.Name)
+		sb.WriteString(field.Labels.String())
+	}
+	return sb.String()
+}
+
+// appendFrameRows appends the rows of src to dst and reports whether both frames
+// have the same field types.
+func appendFrameRows(dst, src *data.Frame) bool {
+	if len(dst.Fields) != len(src.Fields) {
+		return false
+	}
+	for i := range dst.Fields {
+		if dst.Fields[i].Type() != src.Fields[i].Type() {
+			return false
+		}
+	}
+	for i, field := range src.Fields {
+		for row := 0; row < field.Len(); row++ {
+			dst.Fields[i].Append(field.CopyAt(row))
+		}
+	}
+	return true
+}
+
+func copyFrame(frame *data.Frame) *data.Frame {
+	copied := data.NewFrame(frame.Name)
+	copied.RefID = frame.RefID
+	if frame.Meta != nil {
+		meta := *frame.Meta
+		copied.Meta = &meta
+	}
+	for _, field := range frame.Fields {
+		f := data.NewFieldFromFieldType(field.Type(), 0)
+		f.Name = field.Name
+		f.Labels = field.Labels.Copy()
+		if field.Config != nil {
+			config := *field.Config
+			f.Config = &config
+		}
+		copied.Fields = append(copied.Fields, f)
+	}
+	appendFrameRows(copied, frame)
+	return copied
+}
+
+// chunkCacheScope identifies who the queries of a request run as. The user and the forwarded
+// HTTP headers, such as OAuth tokens, cookies and label based access policies, change the
+// results returned by the data source, so chunks are only shared between requests with the
+// same scope.
+func chunkCacheScope(req *backend.QueryDataRequest) string {
+	h := sha256.New()
+	if req.PluginContext.User != nil {
+		_, _ = fmt.Fprintf(h, "%s\x00", req.PluginContext.User.Login)
+	}
+
+	headers := req.GetHTTPHeaders()
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00", name, strings.Join(headers[name], "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func chunkCacheKey(scope string, q *models.Query) string {
+	tr := q.TimeRange()
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%d\x00%d", scope, q.Expr, q.LegendFormat, tr.Step.Milliseconds(), tr.Start.UnixMilli(), tr.End.UnixMilli())
+}
+
+type chunkCacheEntry struct {
+	response backend.DataResponse
+	expires  time.Time
+}
+
+// chunkCache is a size bounded in-memory cache of range query chunk results.
+// Entries are evicted in insertion order once the cache is full.
+type chunkCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]chunkCacheEntry
+	keys       []string
+}
+
+func newChunkCache(ttl time.Duration, maxEntries int) *chunkCache {
+	return &chunkCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]chunkCacheEntry),
+	}
+}
+
+func (c *chunkCache) get(key string) (backend.DataResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return backend.DataResponse{}, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return backend.DataResponse{}, false
+	}
+	return entry.response, true
+}
+
+func (c *chunkCache) set(key string, response backend.DataResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.entries[key] = chunkCacheEntry{response: response, expires: time.Now().Add(c.ttl)}
+
+	for len(c.entries) > c.maxEntries && len(c.keys) > 0 {
+		delete(c.entries, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+	// keys of expired entries removed in get are dropped lazily here
+	if len(c.keys) > 2*c.maxEntries {
+		keys := make([]string, 0, len(c.entries))
+		seen := make(map[string]struct{}, len(c.entries))
+		for _, k := range c.keys {
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			if _, ok := c.entries[k]; ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+		c.keys = keys
+	}
+}
@@ -0,0 +1,230 @@
+package querydata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/promlib/models"
+)
+
+func TestParseSplitSettings(t *testing.T) {
+	t.Run("splitting is disabled by default", func(t *testing.T) {
+		settings, err := parseSplitSettings(map[string]any{})
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), settings.Duration)
+		require.Equal(t, defaultSplitConcurrency, settings.Concurrency)
+		require.Equal(t, defaultOverlapWindow, settings.OverlapWindow)
+	})
+
+	t.Run("settings are read from json data", func(t *testing.T) {
+		settings, err := parseSplitSettings(map[string]any{
+			"querySplitDuration":            "1d",
+			"querySplitConcurrency":         float64(2),
+			"incrementalQueryOverlapWindow": "5m",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 24*time.Hour, settings.Duration)
+		require.Equal(t, 2, settings.Concurrency)
+		require.Equal(t, 5*time.Minute, settings.OverlapWindow)
+	})
+
+	t.Run("invalid split duration", func(t *testing.T) {
+		_, err := parseSplitSettings(map[string]any{"querySplitDuration": "soon"})
+		require.Error(t, err)
+	})
+}
+
+func TestSplitTimeRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("short range is not split", func(t *testing.T) {
+		q := &models.Query{Start: start, End: start.Add(time.Hour), Step: time.Minute, RangeQuery: true}
+		chunks := splitTimeRange(q, 6*time.Hour)
+		require.Len(t, chunks, 1)
+		require.Same(t, q, chunks[0].query)
+	})
+
+	t.Run("chunks are aligned and do not overlap", func(t *testing.T) {
+		q := &models.Query{Start: start, End: start.Add(25 * time.Hour), Step: time.Minute, RangeQuery: true}
+		chunks := splitTimeRange(q, 6*time.Hour)
+		require.Len(t, chunks, 5)
+
+		require.Equal(t, start, chunks[0].query.Start)
+		require.Equal(t, time.Date(2024, 1, 1, 11, 59, 0, 0, time.UTC), chunks[0].query.End)
+		require.False(t, chunks[0].complete)
+
+		for i := 1; i < len(chunks)-1; i++ {
+			require.Equal(t, chunks[i-1].query.End.Add(q.Step), chunks[i].query.Start)
+			require.Equal(t, 6*time.Hour-q.Step, chunks[i].query.End.Sub(chunks[i].query.Start))
+			require.True(t, chunks[i].complete)
+		}
+
+		last := chunks[len(chunks)-1]
+		require.Equal(t, time.Date(2024, 1, 2, 11, 30, 0, 0, time.UTC), last.query.End)
+		require.False(t, last.complete)
+	})
+
+	t.Run("chunk size is rounded down to a multiple of the step", func(t *testing.T) {
+		q := &models.Query{Start: start, End: start.Add(3 * time.Hour), Step: 7 * time.Minute, RangeQuery: true}
+		chunks := splitTimeRange(q, time.Hour)
+		for _, chunk := range chunks {
+			require.LessOrEqual(t, chunk.query.End.Sub(chunk.query.Start), 63*time.Minute-q.Step)
+		}
+	})
+}
+
+func TestMergeChunkResponses(t *testing.T) {
+	newFrame := func(labels data.Labels, times []time.Time, values []float64) *data.Frame {
+		return data.NewFrame("",
+			data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+			data.NewField(data.TimeSeriesValueFieldName, labels, values),
+		).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti})
+	}
+	t0 := time.Unix(0, 0).UTC()
+	t1 := t0.Add(time.Minute)
+
+	first := backend.DataResponse{Frames: data.Frames{
+		newFrame(data.Labels{"job": "a"}, []time.Time{t0}, []float64{1}),
+	}}
+	second := backend.DataResponse{Frames: data.Frames{
+		newFrame(data.Labels{"job": "b"}, []time.Time{t1}, []float64{3}),
+		newFrame(data.Labels{"job": "a"}, []time.Time{t1}, []float64{2}),
+	}}
+
+	merged := mergeChunkResponses([]backend.DataResponse{first, second})
+	require.NoError(t, merged.Error)
+	require.Len(t, merged.Frames, 2)
+	require.Equal(t, 2, merged.Frames[0].Rows())
+	require.Equal(t, []any{t0, 1.0}, merged.Frames[0].RowCopy(0))
+	require.Equal(t, []any{t1, 2.0}, merged.Frames[0].RowCopy(1))
+	require.Equal(t, 1, merged.Frames[1].Rows())
+
+	// chunk responses may be cached and must not be modified
+	require.Equal(t, 1, first.Frames[0].Rows())
+
+	t.Run("errors are kept", func(t *testing.T) {
+		failed := backend.DataResponse{Error: fmt.Errorf("boom"), Status: backend.StatusBadGateway}
+		merged := mergeChunkResponses([]backend.DataResponse{first, failed})
+		require.ErrorContains(t, merged.Error, "boom")
+		require.Len(t, merged.Frames, 1)
+	})
+}
+
+func TestChunkCache(t *testing.T) {
+	t.Run("oldest entries are evicted", func(t *testing.T) {
+		cache := newChunkCache(time.Hour, 2)
+		cache.set("a", backend.DataResponse{})
+		cache.set("b", backend.DataResponse{})
+		cache.set("c", backend.DataResponse{})
+
+		_, ok := cache.get("a")
+		require.False(t, ok)
+		_, ok = cache.get("c")
+		require.True(t, ok)
+	})
+
+	t.Run("expired entries are not returned", func(t *testing.T) {
+		cache := newChunkCache(-time.Second, 2)
+		cache.set("a", backend.DataResponse{})
+		_, ok := cache.get("a")
+		require.False(t, ok)
+	})
+}
+
+func TestSplitRangeQuery(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		start, _ := strconv.ParseFloat(r.Form.Get("start"), 64)
+		end, _ := strconv.ParseFloat(r.Form.Get("end"), 64)
+		step, _ := strconv.ParseFloat(r.Form.Get("step"), 64)
+
+		var values []string
+		for ts := start; ts <= end; ts += step {
+			values = append(values, fmt.Sprintf(`[%v,"1"]`, ts))
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"a"},"values":[%s]}]}}`, strings.Join(values, ","))
+	}))
+	t.Cleanup(srv.Close)
+
+	settings := backend.DataSourceInstanceSettings{
+		URL:      srv.URL,
+		JSONData: json.RawMessage(`{"httpMethod":"GET","querySplitDuration":"1h","incrementalQueryOverlapWindow":"10m"}`),
+	}
+	qd, err := New(srv.Client(), settings, log.New(), backend.FeatureToggles{})
+	require.NoError(t, err)
+
+	to := time.Now().Add(-24 * time.Hour).Truncate(time.Hour).Add(30 * time.Minute)
+	from := to.Add(-4 * time.Hour)
+	req := &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{
+			RefID:     "A",
+			Interval:  time.Minute,
+			TimeRange: backend.TimeRange{From: from, To: to},
+			JSON:      []byte(`{"expr":"up","range":true,"refId":"A","interval":"1m"}`),
+		}},
+	}
+	cfg := backend.NewGrafanaCfg(map[string]string{
+		"concurrent_query_count": "10",
+	})
+	req.PluginContext.GrafanaConfig = cfg
+	ctx := backend.WithGrafanaConfig(context.Background(), cfg)
+
+	res, err := qd.Execute(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["A"].Error)
+	require.Equal(t, int32(5), requests.Load())
+
+	frames := res.Responses["A"].Frames
+	require.Len(t, frames, 1)
+	require.Equal(t, 241, frames[0].Rows())
+	require.NotEmpty(t, frames[0].Meta.ExecutedQueryString)
+
+	// the three complete chunks in the middle are served from the cache
+	requests.Store(0)
+	res, err = qd.Execute(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["A"].Error)
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, 241, res.Responses["A"].Frames[0].Rows())
+
+	// chunks aren't shared with other users or requests with other forwarded headers
+	requests.Store(0)
+	req.PluginContext.User = &backend.User{Login: "other"}
+	res, err = qd.Execute(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["A"].Error)
+	require.Equal(t, int32(5), requests.Load())
+
+	requests.Store(0)
+	req.SetHTTPHeader("X-Prom-Label-Policy", `1:{job="b"}`)
+	res, err = qd.Execute(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["A"].Error)
+	require.Equal(t, int32(5), requests.Load())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, span := qd.tracer.Start(ctx, "test")
+	defer span.End()
+	q, err := models.Parse(span, req.Queries[0], "", qd.intervalCalculator, false, false)
+	require.NoError(t, err)
+	require.ErrorIs(t, qd.splitRangeQuery(canceled, qd.client, q, "").Error, context.Canceled)
+}
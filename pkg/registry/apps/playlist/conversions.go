@@ -18,20 +18,55 @@ import (
 )
 
 func LegacyUpdateCommandToUnstructured(cmd playlistsvc.UpdatePlaylistCommand) unstructured.Unstructured {
-	items := []map[string]string{}
+	items := []map[string]any{}
 	for _, item := range cmd.Items {
-		items = append(items, map[string]string{
+		v := map[string]any{
 			"type":  item.Type,
 			"value": item.Value,
-		})
+		}
+		if item.Duration != "" {
+			v["duration"] = item.Duration
+		}
+		if item.TimeRange != nil {
+			v["timeRange"] = map[string]any{
+				"from": item.TimeRange.From,
+				"to":   item.TimeRange.To,
+			}
+		}
+		items = append(items, v)
+	}
+	spec := map[string]interface{}{
+		"title":    cmd.Name,
+		"interval": cmd.Interval,
+		"items":    items,
+	}
+	if len(cmd.Schedules) > 0 {
+		schedules := []map[string]any{}
+		for _, schedule := range cmd.Schedules {
+			v := map[string]any{
+				"from": schedule.From,
+				"to":   schedule.To,
+			}
+			if len(schedule.Weekdays) > 0 {
+				weekdays := []any{}
+				for _, day := range schedule.Weekdays {
+					weekdays = append(weekdays, day)
+				}
+				v["weekdays"] = weekdays
+			}
+			if schedule.Timezone != "" {
+				v["timezone"] = schedule.Timezone
+			}
+			if schedule.Kiosk != "" {
+				v["kiosk"] = schedule.Kiosk
+			}
+			schedules = append(schedules, v)
+		}
+		spec["schedules"] = schedules
 	}
 	obj := unstructured.Unstructured{
 		Object: map[string]interface{}{
-			"spec": map[string]interface{}{
-				"title":    cmd.Name,
-				"interval": cmd.Interval,
-				"items":    items,
-			},
+			"spec": spec,
 		},
 	}
 	if cmd.UID == "" {
@@ -66,6 +101,13 @@ func UnstructuredToLegacyPlaylistDTO(item unstructured.Unstructured) *playlistsv
 			_ = json.Unmarshal(b, &dto.Items)
 		}
 	}
+	schedules := spec["schedules"]
+	if schedules != nil {
+		b, err := json.Marshal(schedules)
+		if err == nil {
+			_ = json.Unmarshal(b, &dto.Schedules)
+		}
+	}
 	return dto
 }
 
@@ -75,10 +117,31 @@ func convertToK8sResource(v *playlistsvc.PlaylistDTO, namespacer request.Namespa
 		Interval: v.Interval,
 	}
 	for _, item := range v.Items {
-		spec.Items = append(spec.Items, playlist.PlaylistItem{
+		k8sItem := playlist.PlaylistItem{
 			Type:  playlist.PlaylistItemType(item.Type),
 			Value: item.Value,
-		})
+		}
+		if item.Duration != "" {
+			k8sItem.Duration = &item.Duration
+		}
+		if item.TimeRange != nil {
+			k8sItem.TimeRange = &playlist.PlaylistTimeRange{From: item.TimeRange.From, To: item.TimeRange.To}
+		}
+		spec.Items = append(spec.Items, k8sItem)
+	}
+	for _, schedule := range v.Schedules {
+		k8sSchedule := playlist.PlaylistSchedule{
+			Weekdays: schedule.Weekdays,
+			From:     schedule.From,
+			To:       schedule.To,
+		}
+		if schedule.Timezone != "" {
+			k8sSchedule.Timezone = &schedule.Timezone
+		}
+		if schedule.Kiosk != "" {
+			k8sSchedule.Kiosk = &schedule.Kiosk
+		}
+		spec.Schedules = append(spec.Schedules, k8sSchedule)
 	}
 
 	p := &playlist.Playlist{
@@ -115,10 +178,31 @@ func convertToLegacyUpdateCommand(p *playlist.Playlist, orgId int64) (*playlists
 		if item.Type == playlist.PlaylistItemTypeDashboardById {
 			return nil, fmt.Errorf("unsupported item type: %s", item.Type)
 		}
-		cmd.Items = append(cmd.Items, playlistsvc.PlaylistItem{
+		legacyItem := playlistsvc.PlaylistItem{
 			Type:  string(item.Type),
 			Value: item.Value,
-		})
+		}
+		if item.Duration != nil {
+			legacyItem.Duration = *item.Duration
+		}
+		if item.TimeRange != nil {
+			legacyItem.TimeRange = &playlistsvc.PlaylistTimeRange{From: item.TimeRange.From, To: item.TimeRange.To}
+		}
+		cmd.Items = append(cmd.Items, legacyItem)
+	}
+	for _, schedule := range spec.Schedules {
+		legacySchedule := playlistsvc.PlaylistSchedule{
+			Weekdays: schedule.Weekdays,
+			From:     schedule.From,
+			To:       schedule.To,
+		}
+		if schedule.Timezone != nil {
+			legacySchedule.Timezone = *schedule.Timezone
+		}
+		if schedule.Kiosk != nil {
+			legacySchedule.Kiosk = *schedule.Kiosk
+		}
+		cmd.Schedules = append(cmd.Schedules, legacySchedule)
 	}
 	return cmd, nil
 }
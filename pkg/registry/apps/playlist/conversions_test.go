@@ -69,3 +69,35 @@ func TestPlaylistConversion(t *testing.T) {
 		"status": {}
 	}`, string(out))
 }
+
+func TestPlaylistConversionWithOverridesAndSchedules(t *testing.T) {
+	src := &playlist.PlaylistDTO{
+		OrgID:    1,
+		Uid:      "noc",
+		Name:     "NOC",
+		Interval: "20s",
+		Items: []playlist.PlaylistItemDTO{
+			{Type: "dashboard_by_uid", Value: "incidents", Duration: "2m", TimeRange: &playlist.PlaylistTimeRange{From: "now-1h", To: "now"}},
+			{Type: "dashboard_by_tag", Value: "noc"},
+		},
+		Schedules: []playlist.PlaylistSchedule{
+			{Weekdays: []string{"saturday", "sunday"}, From: "00:00", To: "23:59", Timezone: "UTC", Kiosk: "noc-wall"},
+		},
+	}
+	dst := convertToK8sResource(src, request.GetNamespaceMapper(nil))
+	require.Equal(t, "2m", *dst.Spec.Items[0].Duration)
+	require.Nil(t, dst.Spec.Items[1].Duration)
+	require.Equal(t, "noc-wall", *dst.Spec.Schedules[0].Kiosk)
+
+	cmd, err := convertToLegacyUpdateCommand(dst, 1)
+	require.NoError(t, err)
+	require.Equal(t, "2m", cmd.Items[0].Duration)
+	require.Equal(t, &playlist.PlaylistTimeRange{From: "now-1h", To: "now"}, cmd.Items[0].TimeRange)
+	require.Equal(t, src.Schedules, cmd.Schedules)
+
+	// the legacy API converts commands to unstructured objects and back
+	obj := LegacyUpdateCommandToUnstructured(*cmd)
+	dto := UnstructuredToLegacyPlaylistDTO(obj)
+	require.Equal(t, src.Items, dto.Items)
+	require.Equal(t, src.Schedules, dto.Schedules)
+}
@@ -117,11 +117,12 @@ func (s *legacyStorage) Create(ctx context.Context,
 		return nil, err
 	}
 	out, err := s.service.Create(ctx, &playlistsvc.CreatePlaylistCommand{
-		UID:      p.Name,
-		Name:     cmd.Name,
-		Interval: cmd.Interval,
-		Items:    cmd.Items,
-		OrgId:    cmd.OrgId,
+		UID:       p.Name,
+		Name:      cmd.Name,
+		Interval:  cmd.Interval,
+		Items:     cmd.Items,
+		Schedules: cmd.Schedules,
+		OrgId:     cmd.OrgId,
 	})
 	if err != nil {
 		return nil, err
@@ -17,10 +17,13 @@ import (
 
 func convertToK8sResource(v *shorturls.ShortUrl, namespacer request.NamespaceMapper) *shorturl.ShortURL {
 	spec := shorturl.ShortURLSpec{
-		Path: v.Path,
+		Path:      v.Path,
+		ExpiresAt: v.ExpiresAt,
+		MaxUses:   v.MaxUses,
 	}
 	status := shorturl.ShortURLStatus{
 		LastSeenAt: v.LastSeenAt,
+		UseCount:   v.UseCount,
 	}
 	p := &shorturl.ShortURL{
 		ObjectMeta: metav1.ObjectMeta{
@@ -43,7 +46,9 @@ func LegacyCreateCommandToUnstructured(cmd dtos.CreateShortURLCmd) unstructured.
 				"name": cmd.UID,
 			},
 			"spec": map[string]interface{}{
-				"path": cmd.Path,
+				"path":      cmd.Path,
+				"expiresAt": cmd.ExpiresAt,
+				"maxUses":   cmd.MaxUses,
 			},
 		},
 	}
@@ -60,12 +65,18 @@ func UnstructuredToLegacyShortURLDTO(item unstructured.Unstructured, appURL stri
 }
 
 func UnstructuredToLegacyShortURL(item unstructured.Unstructured) *shorturls.ShortUrl {
-	spec := item.Object["spec"].(map[string]interface{})
-	status := item.Object["status"].(map[string]interface{})
+	path, _, _ := unstructured.NestedString(item.Object, "spec", "path")
+	expiresAt, _, _ := unstructured.NestedInt64(item.Object, "spec", "expiresAt")
+	maxUses, _, _ := unstructured.NestedInt64(item.Object, "spec", "maxUses")
+	lastSeenAt, _, _ := unstructured.NestedInt64(item.Object, "status", "lastSeenAt")
+	useCount, _, _ := unstructured.NestedInt64(item.Object, "status", "useCount")
 
 	return &shorturls.ShortUrl{
 		Uid:        item.GetName(),
-		Path:       spec["path"].(string),
-		LastSeenAt: status["lastSeenAt"].(int64),
+		Path:       path,
+		LastSeenAt: lastSeenAt,
+		ExpiresAt:  expiresAt,
+		MaxUses:    maxUses,
+		UseCount:   useCount,
 	}
 }
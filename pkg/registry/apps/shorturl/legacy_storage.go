@@ -90,6 +90,10 @@ func (s *legacyStorage) Get(ctx context.Context, name string, options *metav1.Ge
 		}
 		return nil, err
 	}
+	// Password protected short URLs are only resolved with their password by the legacy API
+	if dto.IsPasswordProtected() {
+		return nil, shorturls.ErrShortURLPassword.Errorf("short URL %s is password protected", name)
+	}
 
 	return convertToK8sResource(dto, s.namespacer), nil
 }
@@ -123,8 +127,10 @@ func (s *legacyStorage) Create(ctx context.Context,
 		return nil, fmt.Errorf("expected shorturl?")
 	}
 	cmd := &dtos.CreateShortURLCmd{
-		Path: p.Spec.Path,
-		UID:  p.Name,
+		Path:      p.Spec.Path,
+		UID:       p.Name,
+		ExpiresAt: p.Spec.ExpiresAt,
+		MaxUses:   p.Spec.MaxUses,
 	}
 	out, err := s.service.CreateShortURL(ctx, signedInUser, cmd)
 	if err != nil {
@@ -168,8 +174,8 @@ func (s *legacyStorage) Update(ctx context.Context,
 		return nil, false, err
 	}
 
-	err = s.service.UpdateLastSeenAt(ctx, shortURL)
-	if err != nil {
+	// Updates of the status record a use of the short URL, which fails once it has expired or been used up
+	if _, err := s.service.ResolveShortURL(ctx, signedInUser, name, ""); err != nil {
 		return nil, false, err
 	}
 	// Fetch the updated short URL to return
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -39,6 +40,9 @@ type Service interface {
 	GetUserPermissions(ctx context.Context, user identity.Requester, options Options) ([]Permission, error)
 	// SearchUsersPermissions returns all users' permissions filtered by an action prefix
 	SearchUsersPermissions(ctx context.Context, user identity.Requester, options SearchOptions) (map[int64][]Permission, error)
+	// GetUserPermissionSources returns the permissions granting an action to the user, grouped by the
+	// role assignment they come from. Team memberships are looked up from the database.
+	GetUserPermissionSources(ctx context.Context, user identity.Requester, action string) ([]PermissionSource, error)
 	// ClearUserPermissionCache removes the permission cache entry for the given user
 	ClearUserPermissionCache(user identity.Requester)
 	// SearchUserPermissions returns single user's permissions filtered by an action prefix or an action
@@ -56,6 +60,12 @@ type Service interface {
 	SaveExternalServiceRole(ctx context.Context, cmd SaveExternalServiceRoleCommand) error
 	// DeleteExternalServiceRole removes an external service's role and its assignment.
 	DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error
+	// GetRoleAssignments returns the roles assigned directly to a user or a team, with their expiry.
+	GetRoleAssignments(ctx context.Context, query GetRoleAssignmentsQuery) ([]RoleAssignment, error)
+	// SaveRoleAssignment assigns a role to a user or a team, until the expiry of the command when set.
+	SaveRoleAssignment(ctx context.Context, cmd SaveRoleAssignmentCommand) error
+	// DeleteRoleAssignment removes a role from a user or a team.
+	DeleteRoleAssignment(ctx context.Context, cmd DeleteRoleAssignmentCommand) error
 	// SyncUserRoles adds provided roles to user
 	SyncUserRoles(ctx context.Context, orgID int64, cmd SyncUserRolesCommand) error
 	// GetStaicRoles returns a map where key organization role and value is a static rbac role.
@@ -67,10 +77,15 @@ type Store interface {
 	GetUserPermissions(ctx context.Context, query GetUserPermissionsQuery) ([]Permission, error)
 	GetBasicRolesPermissions(ctx context.Context, query GetUserPermissionsQuery) ([]Permission, error)
 	GetTeamsPermissions(ctx context.Context, query GetUserPermissionsQuery) (map[int64][]Permission, error)
+	GetUserPermissionSources(ctx context.Context, query GetUserPermissionSourcesQuery) ([]PermissionSource, error)
 	SearchUsersPermissions(ctx context.Context, orgID int64, options SearchOptions) (map[int64][]Permission, error)
 	GetUsersBasicRoles(ctx context.Context, userFilter []int64, orgID int64) (map[int64][]string, error)
 	DeleteUserPermissions(ctx context.Context, orgID, userID int64) error
 	DeleteTeamPermissions(ctx context.Context, orgID, teamID int64) error
+	DeleteExpiredGrants(ctx context.Context, now time.Time) ([]ExpiredGrant, error)
+	GetRoleAssignments(ctx context.Context, query GetRoleAssignmentsQuery) ([]RoleAssignment, error)
+	SaveRoleAssignment(ctx context.Context, cmd SaveRoleAssignmentCommand) error
+	DeleteRoleAssignment(ctx context.Context, cmd DeleteRoleAssignmentCommand) error
 	SaveExternalServiceRole(ctx context.Context, cmd SaveExternalServiceRoleCommand) error
 	DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error
 }
@@ -316,6 +331,42 @@ func Reduce(ps []Permission) map[string][]string {
 	return reduced
 }
 
+// RestrictScopes limits granted permissions, grouped by action, to the restrictions.
+// Actions missing from the restrictions are dropped and actions restricted without
+// scopes keep all their granted scopes. Otherwise only the overlap between granted
+// and restricted scopes is kept, using the narrower scope of each matching pair.
+func RestrictScopes(granted, restrictions map[string][]string) map[string][]string {
+	restricted := make(map[string][]string, len(restrictions))
+	for action, allowed := range restrictions {
+		scopes, ok := granted[action]
+		if !ok {
+			continue
+		}
+		if len(allowed) == 0 {
+			restricted[action] = scopes
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, scope := range scopes {
+			for _, a := range allowed {
+				narrowest := ""
+				switch {
+				case match(a, scope):
+					narrowest = scope
+				case match(scope, a):
+					narrowest = a
+				}
+				if narrowest != "" && !seen[narrowest] {
+					seen[narrowest] = true
+					restricted[action] = append(restricted[action], narrowest)
+				}
+			}
+		}
+	}
+	return restricted
+}
+
 func ValidateScope(scope string) bool {
 	prefix, last := scope[:len(scope)-1], scope[len(scope)-1]
 	// verify that last char is either ':' or '/' if last character of scope is '*'
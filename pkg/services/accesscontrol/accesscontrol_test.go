@@ -129,6 +129,57 @@ func TestReduce(t *testing.T) {
 	}
 }
 
+func TestRestrictScopes(t *testing.T) {
+	granted := map[string][]string{
+		"dashboards:read":   {"dashboards:*", "folders:uid:ci"},
+		"dashboards:write":  {"dashboards:uid:1"},
+		"folders:read":      {"folders:uid:ci", "folders:uid:other"},
+		"datasources:query": {"datasources:uid:1"},
+		"users:read":        {""},
+	}
+
+	tests := []struct {
+		name         string
+		restrictions map[string][]string
+		want         map[string][]string
+	}{
+		{
+			name:         "should keep granted scopes for actions without restricted scopes",
+			restrictions: map[string][]string{"folders:read": nil, "users:read": {}},
+			want:         map[string][]string{"folders:read": {"folders:uid:ci", "folders:uid:other"}, "users:read": {""}},
+		},
+		{
+			name:         "should drop actions that are not granted",
+			restrictions: map[string][]string{"teams:read": nil},
+			want:         map[string][]string{},
+		},
+		{
+			name:         "should narrow wildcard grants to the restricted scope",
+			restrictions: map[string][]string{"dashboards:read": {"dashboards:uid:1", "folders:uid:ci"}},
+			want:         map[string][]string{"dashboards:read": {"dashboards:uid:1", "folders:uid:ci"}},
+		},
+		{
+			name:         "should keep specific grants covered by a restricted wildcard",
+			restrictions: map[string][]string{"folders:read": {"folders:*"}, "dashboards:write": {"*"}},
+			want:         map[string][]string{"folders:read": {"folders:uid:ci", "folders:uid:other"}, "dashboards:write": {"dashboards:uid:1"}},
+		},
+		{
+			name:         "should drop actions without overlapping scopes",
+			restrictions: map[string][]string{"datasources:query": {"datasources:uid:2"}, "users:read": {"users:id:1"}},
+			want:         map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RestrictScopes(granted, tt.restrictions)
+			require.Len(t, got, len(tt.want))
+			for action, scopes := range tt.want {
+				assert.ElementsMatch(t, scopes, got[action], action)
+			}
+		})
+	}
+}
+
 func TestGroupScopesByActionContext(t *testing.T) {
 	// test data = 3 actions with 2+i scopes each, including a duplicate
 	permissions := []Permission{}
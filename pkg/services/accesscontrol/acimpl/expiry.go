@@ -0,0 +1,77 @@
+package acimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const expiredGrantsCleanupInterval = time.Minute
+
+var auditLogger = log.New("accesscontrol.audit")
+
+// Run removes expired role assignments and resource permissions. Only one
+// instance runs the cleanup at a time, coordinated through the server lock.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(expiredGrantsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.lock.LockAndExecute(ctx, "delete expired rbac grants", expiredGrantsCleanupInterval, func(ctx context.Context) {
+				if err := s.deleteExpiredGrants(ctx, time.Now()); err != nil {
+					s.log.Error("Problem deleting expired role assignments and permissions", "error", err)
+				}
+			})
+			if err != nil {
+				s.log.Error("Failed to lock and execute expired grants cleanup", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) deleteExpiredGrants(ctx context.Context, now time.Time) error {
+	ctx, span := tracer.Start(ctx, "accesscontrol.acimpl.deleteExpiredGrants")
+	defer span.End()
+
+	grants, err := s.store.DeleteExpiredGrants(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range grants {
+		auditLogger.Info("Removed expired grant",
+			"type", g.Type,
+			"orgID", g.OrgID,
+			"role", g.RoleName,
+			"userID", g.UserID,
+			"teamID", g.TeamID,
+			"builtInRole", g.BuiltInRole,
+			"action", g.Action,
+			"scope", g.Scope,
+			"expires", g.Expires,
+		)
+		s.clearGrantPermissionCache(g)
+	}
+
+	return nil
+}
+
+// clearGrantPermissionCache removes the cached permissions of whoever held the grant
+// so that the change applies without waiting for the cache entries to expire.
+func (s *Service) clearGrantPermissionCache(g accesscontrol.ExpiredGrant) {
+	switch {
+	case g.UserID != 0:
+		s.ClearUserPermissionCache(&user.SignedInUser{UserID: g.UserID, OrgID: g.OrgID, IsServiceAccount: g.IsServiceAccount})
+	case g.TeamID != 0:
+		s.cache.Delete(accesscontrol.GetTeamPermissionCacheKey(g.TeamID, g.OrgID))
+	case g.BuiltInRole != "":
+		s.cache.Delete(accesscontrol.GetBasicRolePermissionCacheKey(g.BuiltInRole, g.OrgID))
+	}
+}
@@ -0,0 +1,57 @@
+package acimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestService_DeleteExpiredGrants(t *testing.T) {
+	now := time.Now()
+	serviceAccount := &user.SignedInUser{UserID: 2, OrgID: 1, IsServiceAccount: true}
+	otherUser := &user.SignedInUser{UserID: 3, OrgID: 1}
+
+	s := &Service{
+		cache: localcache.ProvideService(),
+		log:   log.New("accesscontrol"),
+		store: actest.FakeStore{ExpectedExpiredGrants: []accesscontrol.ExpiredGrant{
+			{Type: accesscontrol.ExpiredGrantPermission, OrgID: 1, UserID: 2, IsServiceAccount: true, Action: "dashboards:read", Scope: "dashboards:uid:1", Expires: now},
+			{Type: accesscontrol.ExpiredGrantTeamRole, OrgID: 1, TeamID: 5, Expires: now},
+			{Type: accesscontrol.ExpiredGrantPermission, OrgID: 1, BuiltInRole: "Viewer", Action: "folders:read", Scope: "folders:uid:1", Expires: now},
+		}},
+	}
+
+	keys := []string{
+		accesscontrol.GetUserDirectPermissionCacheKey(serviceAccount),
+		accesscontrol.GetTeamPermissionCacheKey(5, 1),
+		accesscontrol.GetBasicRolePermissionCacheKey("Viewer", 1),
+	}
+	untouched := []string{
+		accesscontrol.GetUserDirectPermissionCacheKey(otherUser),
+		accesscontrol.GetTeamPermissionCacheKey(5, 2),
+		accesscontrol.GetBasicRolePermissionCacheKey("Editor", 1),
+	}
+	for _, key := range append(keys, untouched...) {
+		s.cache.Set(key, []accesscontrol.Permission{}, time.Minute)
+	}
+
+	require.NoError(t, s.deleteExpiredGrants(context.Background(), now))
+
+	for _, key := range keys {
+		_, ok := s.cache.Get(key)
+		assert.False(t, ok, key)
+	}
+	for _, key := range untouched {
+		_, ok := s.cache.Get(key)
+		assert.True(t, ok, key)
+	}
+}
@@ -98,6 +98,7 @@ func ProvideOSSService(
 		roles:          accesscontrol.BuildBasicRoleDefinitions(),
 		store:          store,
 		permRegistry:   permRegistry,
+		lock:           lock,
 	}
 
 	return s
@@ -114,6 +115,7 @@ type Service struct {
 	roles          map[string]*accesscontrol.RoleDTO
 	store          accesscontrol.Store
 	permRegistry   permreg.PermissionRegistry
+	lock           *serverlock.ServerLockService
 }
 
 func (s *Service) GetUsageStats(_ context.Context) map[string]any {
@@ -234,6 +236,77 @@ func (s *Service) getUserDirectPermissions(ctx context.Context, user identity.Re
 	return permissions, nil
 }
 
+// GetUserPermissionSources returns the permissions granting action to the user, grouped by the role assignment they come from
+func (s *Service) GetUserPermissionSources(ctx context.Context, user identity.Requester, action string) ([]accesscontrol.PermissionSource, error) {
+	ctx, span := tracer.Start(ctx, "accesscontrol.acimpl.GetUserPermissionSources")
+	defer span.End()
+
+	matchAction := func(a string) bool { return a == action }
+	basicRoles := accesscontrol.GetOrgRoles(user)
+
+	// Fixed and plugin roles granted to basic roles are only registered in memory
+	sources := make([]accesscontrol.PermissionSource, 0)
+	s.registrations.Range(func(registration accesscontrol.RoleRegistration) bool {
+		grants := accesscontrol.BuiltInRolesWithParents(registration.Grants)
+		for _, basicRole := range basicRoles {
+			if _, ok := grants[basicRole]; !ok {
+				continue
+			}
+			var permissions []accesscontrol.Permission
+			for _, p := range registration.Role.Permissions {
+				if matchAction(p.Action) {
+					permissions = append(permissions, accesscontrol.Permission{Action: p.Action, Scope: p.Scope})
+				}
+			}
+			if len(permissions) > 0 {
+				sources = append(sources, accesscontrol.PermissionSource{
+					Type:        accesscontrol.PermissionSourceBasicRole,
+					RoleName:    registration.Role.Name,
+					BasicRole:   basicRole,
+					Permissions: permissions,
+				})
+			}
+		}
+		return true
+	})
+
+	var userID int64
+	if user.IsIdentityType(claims.TypeUser, claims.TypeServiceAccount) {
+		var err error
+		userID, err = user.GetInternalID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dbSources, err := s.store.GetUserPermissionSources(ctx, accesscontrol.GetUserPermissionSourcesQuery{
+		OrgID:        user.GetOrgID(),
+		UserID:       userID,
+		Roles:        basicRoles,
+		Actions:      append([]string{action}, s.actionResolver.ResolveAction(action)...),
+		RolePrefixes: OSSRolesPrefixes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range dbSources {
+		// Managed permissions are stored as action sets
+		var permissions []accesscontrol.Permission
+		for _, p := range s.actionResolver.ExpandActionSetsWithFilter(source.Permissions, matchAction) {
+			if matchAction(p.Action) {
+				permissions = append(permissions, p)
+			}
+		}
+		if len(permissions) > 0 {
+			source.Permissions = permissions
+			sources = append(sources, source)
+		}
+	}
+
+	return sources, nil
+}
+
 func (s *Service) getCachedUserPermissions(ctx context.Context, user identity.Requester, options accesscontrol.Options) ([]accesscontrol.Permission, error) {
 	ctx, span := tracer.Start(ctx, "accesscontrol.acimpl.getCachedUserPermissions")
 	defer span.End()
@@ -739,6 +812,50 @@ func (s *Service) DeleteExternalServiceRole(ctx context.Context, externalService
 	return s.store.DeleteExternalServiceRole(ctx, slug)
 }
 
+func (s *Service) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "accesscontrol.acimpl.GetRoleAssignments")
+	defer span.End()
+
+	return s.store.GetRoleAssignments(ctx, query)
+}
+
+func (s *Service) SaveRoleAssignment(ctx context.Context, cmd accesscontrol.SaveRoleAssignmentCommand) error {
+	ctx, span := tracer.Start(ctx, "accesscontrol.acimpl.SaveRoleAssignment")
+	defer span.End()
+
+	if err := s.store.SaveRoleAssignment(ctx, cmd); err != nil {
+		return err
+	}
+
+	auditLogger.Info("Assigned role", "orgID", cmd.OrgID, "role", cmd.RoleUID, "userID", cmd.UserID, "teamID", cmd.TeamID, "expires", cmd.Expires)
+	s.clearAssignmentPermissionCache(cmd.OrgID, cmd.UserID, cmd.TeamID)
+	return nil
+}
+
+func (s *Service) DeleteRoleAssignment(ctx context.Context, cmd accesscontrol.DeleteRoleAssignmentCommand) error {
+	ctx, span := tracer.Start(ctx, "accesscontrol.acimpl.DeleteRoleAssignment")
+	defer span.End()
+
+	if err := s.store.DeleteRoleAssignment(ctx, cmd); err != nil {
+		return err
+	}
+
+	auditLogger.Info("Removed role assignment", "orgID", cmd.OrgID, "role", cmd.RoleUID, "userID", cmd.UserID, "teamID", cmd.TeamID)
+	s.clearAssignmentPermissionCache(cmd.OrgID, cmd.UserID, cmd.TeamID)
+	return nil
+}
+
+// clearAssignmentPermissionCache removes the cached permissions of the assignee. The same ID
+// is cleared for users and service accounts since both are stored in the user table.
+func (s *Service) clearAssignmentPermissionCache(orgID, userID, teamID int64) {
+	if userID != 0 {
+		s.ClearUserPermissionCache(&user.SignedInUser{UserID: userID, OrgID: orgID})
+		s.ClearUserPermissionCache(&user.SignedInUser{UserID: userID, OrgID: orgID, IsServiceAccount: true})
+		return
+	}
+	s.cache.Delete(accesscontrol.GetTeamPermissionCacheKey(teamID, orgID))
+}
+
 func (s *Service) SyncUserRoles(ctx context.Context, orgID int64, cmd accesscontrol.SyncUserRolesCommand) error {
 	return nil
 }
@@ -1034,3 +1034,55 @@ func TestIntegrationService_GetRoleByName(t *testing.T) {
 		require.Equal(t, roleName, role.Name)
 	})
 }
+
+func TestIntegrationService_GetUserPermissionSources(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ac := setupTestEnv(t)
+	ac.registrations = accesscontrol.RegistrationList{}
+	ac.registrations.Append(accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{Name: "fixed:dashboards:reader", Permissions: []accesscontrol.Permission{
+			{Action: "dashboards:read", Scope: "dashboards:*"},
+			{Action: "dashboards:write", Scope: "dashboards:*"},
+		}},
+		Grants: []string{"Viewer"},
+	})
+	ac.registrations.Append(accesscontrol.RoleRegistration{
+		Role:   accesscontrol.RoleDTO{Name: "fixed:dashboards:admin", Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}}},
+		Grants: []string{"Admin"},
+	})
+	ac.store = actest.FakeStore{ExpectedPermissionSources: []accesscontrol.PermissionSource{
+		{
+			Type:     accesscontrol.PermissionSourceManagedPermission,
+			RoleName: "managed:teams:1:permissions",
+			TeamID:   1,
+			Permissions: []accesscontrol.Permission{
+				{Action: "dashboards:read", Scope: "dashboards:uid:1"},
+				{Action: "dashboards:delete", Scope: "dashboards:uid:1"},
+			},
+		},
+		{
+			Type:        accesscontrol.PermissionSourceRole,
+			RoleName:    "extsvc:other",
+			Permissions: []accesscontrol.Permission{{Action: "dashboards:delete", Scope: "dashboards:*"}},
+		},
+	}}
+
+	sources, err := ac.GetUserPermissionSources(context.Background(), &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: "Editor"}, "dashboards:read")
+	require.NoError(t, err)
+	assert.Equal(t, []accesscontrol.PermissionSource{
+		{
+			Type:        accesscontrol.PermissionSourceBasicRole,
+			RoleName:    "fixed:dashboards:reader",
+			BasicRole:   "Editor",
+			Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}},
+		},
+		{
+			Type:        accesscontrol.PermissionSourceManagedPermission,
+			RoleName:    "managed:teams:1:permissions",
+			TeamID:      1,
+			Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:uid:1"}},
+		},
+	}, sources)
+}
@@ -2,6 +2,7 @@ package actest
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -17,6 +18,8 @@ type FakeService struct {
 	ExpectedPermissions             []accesscontrol.Permission
 	ExpectedFilteredUserPermissions []accesscontrol.Permission
 	ExpectedUsersPermissions        map[int64][]accesscontrol.Permission
+	ExpectedPermissionSources       []accesscontrol.PermissionSource
+	ExpectedRoleAssignments         []accesscontrol.RoleAssignment
 }
 
 func (f FakeService) GetUsageStats(ctx context.Context) map[string]any {
@@ -35,6 +38,10 @@ func (f FakeService) SearchUserPermissions(ctx context.Context, orgID int64, sea
 	return f.ExpectedFilteredUserPermissions, f.ExpectedErr
 }
 
+func (f FakeService) GetUserPermissionSources(ctx context.Context, user identity.Requester, action string) ([]accesscontrol.PermissionSource, error) {
+	return f.ExpectedPermissionSources, f.ExpectedErr
+}
+
 func (f FakeService) ClearUserPermissionCache(user identity.Requester) {}
 
 func (f FakeService) DeleteUserPermissions(ctx context.Context, orgID, userID int64) error {
@@ -61,6 +68,18 @@ func (f FakeService) DeleteExternalServiceRole(ctx context.Context, externalServ
 	return f.ExpectedErr
 }
 
+func (f FakeService) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	return f.ExpectedRoleAssignments, f.ExpectedErr
+}
+
+func (f FakeService) SaveRoleAssignment(ctx context.Context, cmd accesscontrol.SaveRoleAssignmentCommand) error {
+	return f.ExpectedErr
+}
+
+func (f FakeService) DeleteRoleAssignment(ctx context.Context, cmd accesscontrol.DeleteRoleAssignmentCommand) error {
+	return f.ExpectedErr
+}
+
 var _ accesscontrol.AccessControl = new(FakeAccessControl)
 
 type FakeAccessControl struct {
@@ -85,6 +104,9 @@ type FakeStore struct {
 	ExpectedTeamsPermissions      map[int64][]accesscontrol.Permission
 	ExpectedUsersPermissions      map[int64][]accesscontrol.Permission
 	ExpectedUsersRoles            map[int64][]string
+	ExpectedPermissionSources     []accesscontrol.PermissionSource
+	ExpectedExpiredGrants         []accesscontrol.ExpiredGrant
+	ExpectedRoleAssignments       []accesscontrol.RoleAssignment
 	ExpectedErr                   error
 }
 
@@ -100,6 +122,10 @@ func (f FakeStore) GetTeamsPermissions(ctx context.Context, query accesscontrol.
 	return f.ExpectedTeamsPermissions, f.ExpectedErr
 }
 
+func (f FakeStore) GetUserPermissionSources(ctx context.Context, query accesscontrol.GetUserPermissionSourcesQuery) ([]accesscontrol.PermissionSource, error) {
+	return f.ExpectedPermissionSources, f.ExpectedErr
+}
+
 func (f FakeStore) SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error) {
 	return f.ExpectedUsersPermissions, f.ExpectedErr
 }
@@ -116,6 +142,22 @@ func (f FakeStore) DeleteTeamPermissions(ctx context.Context, orgID, teamID int6
 	return f.ExpectedErr
 }
 
+func (f FakeStore) DeleteExpiredGrants(ctx context.Context, now time.Time) ([]accesscontrol.ExpiredGrant, error) {
+	return f.ExpectedExpiredGrants, f.ExpectedErr
+}
+
+func (f FakeStore) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	return f.ExpectedRoleAssignments, f.ExpectedErr
+}
+
+func (f FakeStore) SaveRoleAssignment(ctx context.Context, cmd accesscontrol.SaveRoleAssignmentCommand) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) DeleteRoleAssignment(ctx context.Context, cmd accesscontrol.DeleteRoleAssignmentCommand) error {
+	return f.ExpectedErr
+}
+
 func (f FakeStore) SaveExternalServiceRole(ctx context.Context, cmd accesscontrol.SaveExternalServiceRoleCommand) error {
 	return f.ExpectedErr
 }
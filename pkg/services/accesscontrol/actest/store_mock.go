@@ -8,6 +8,8 @@ import (
 	accesscontrol "github.com/grafana/grafana/pkg/services/accesscontrol"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockStore is an autogenerated mock type for the Store type
@@ -15,6 +17,36 @@ type MockStore struct {
 	mock.Mock
 }
 
+// DeleteExpiredGrants provides a mock function with given fields: ctx, now
+func (_m *MockStore) DeleteExpiredGrants(ctx context.Context, now time.Time) ([]accesscontrol.ExpiredGrant, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredGrants")
+	}
+
+	var r0 []accesscontrol.ExpiredGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]accesscontrol.ExpiredGrant, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []accesscontrol.ExpiredGrant); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]accesscontrol.ExpiredGrant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteExternalServiceRole provides a mock function with given fields: ctx, externalServiceID
 func (_m *MockStore) DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error {
 	ret := _m.Called(ctx, externalServiceID)
@@ -33,6 +65,24 @@ func (_m *MockStore) DeleteExternalServiceRole(ctx context.Context, externalServ
 	return r0
 }
 
+// DeleteRoleAssignment provides a mock function with given fields: ctx, cmd
+func (_m *MockStore) DeleteRoleAssignment(ctx context.Context, cmd accesscontrol.DeleteRoleAssignmentCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRoleAssignment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.DeleteRoleAssignmentCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTeamPermissions provides a mock function with given fields: ctx, orgID, teamID
 func (_m *MockStore) DeleteTeamPermissions(ctx context.Context, orgID int64, teamID int64) error {
 	ret := _m.Called(ctx, orgID, teamID)
@@ -99,6 +149,36 @@ func (_m *MockStore) GetBasicRolesPermissions(ctx context.Context, query accessc
 	return r0, r1
 }
 
+// GetRoleAssignments provides a mock function with given fields: ctx, query
+func (_m *MockStore) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleAssignments")
+	}
+
+	var r0 []accesscontrol.RoleAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.GetRoleAssignmentsQuery) []accesscontrol.RoleAssignment); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]accesscontrol.RoleAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, accesscontrol.GetRoleAssignmentsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTeamsPermissions provides a mock function with given fields: ctx, query
 func (_m *MockStore) GetTeamsPermissions(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) (map[int64][]accesscontrol.Permission, error) {
 	ret := _m.Called(ctx, query)
@@ -129,6 +209,36 @@ func (_m *MockStore) GetTeamsPermissions(ctx context.Context, query accesscontro
 	return r0, r1
 }
 
+// GetUserPermissionSources provides a mock function with given fields: ctx, query
+func (_m *MockStore) GetUserPermissionSources(ctx context.Context, query accesscontrol.GetUserPermissionSourcesQuery) ([]accesscontrol.PermissionSource, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserPermissionSources")
+	}
+
+	var r0 []accesscontrol.PermissionSource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.GetUserPermissionSourcesQuery) ([]accesscontrol.PermissionSource, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.GetUserPermissionSourcesQuery) []accesscontrol.PermissionSource); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]accesscontrol.PermissionSource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, accesscontrol.GetUserPermissionSourcesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserPermissions provides a mock function with given fields: ctx, query
 func (_m *MockStore) GetUserPermissions(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) ([]accesscontrol.Permission, error) {
 	ret := _m.Called(ctx, query)
@@ -207,6 +317,24 @@ func (_m *MockStore) SaveExternalServiceRole(ctx context.Context, cmd accesscont
 	return r0
 }
 
+// SaveRoleAssignment provides a mock function with given fields: ctx, cmd
+func (_m *MockStore) SaveRoleAssignment(ctx context.Context, cmd accesscontrol.SaveRoleAssignmentCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRoleAssignment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.SaveRoleAssignmentCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SearchUsersPermissions provides a mock function with given fields: ctx, orgID, options
 func (_m *MockStore) SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error) {
 	ret := _m.Called(ctx, orgID, options)
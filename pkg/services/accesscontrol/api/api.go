@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -45,6 +48,18 @@ func (api *AccessControlAPI) RegisterAPIEndpoints() {
 		rr.Get("/user/actions", middleware.ReqSignedIn, routing.Wrap(api.getUserActions))
 		rr.Get("/user/permissions", middleware.ReqSignedIn, routing.Wrap(api.getUserPermissions))
 		rr.Get("/users/permissions/search", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead)), routing.Wrap(api.searchUsersPermissions))
+		rr.Get("/users/permissions/explain", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead)), routing.Wrap(api.explainUserPermission))
+
+		// Assigning a role grants its permissions in the organization, only server admins can assign roles.
+		userScope := ac.Scope("users", "id", ac.Parameter(":userId"))
+		teamScope := ac.Scope("teams", "id", ac.Parameter(":teamId"))
+		assignRoles := ac.EvalPermission(ac.ActionUsersPermissionsUpdate, ac.ScopeGlobalUsersAll)
+		rr.Get("/users/:userId/roles", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead, userScope)), routing.Wrap(api.getUserRoleAssignments))
+		rr.Post("/users/:userId/roles", authorize(assignRoles), routing.Wrap(api.addUserRoleAssignment))
+		rr.Delete("/users/:userId/roles/:roleUID", authorize(assignRoles), routing.Wrap(api.removeUserRoleAssignment))
+		rr.Get("/teams/:teamId/roles", authorize(ac.EvalPermission(ac.ActionTeamsPermissionsRead, teamScope)), routing.Wrap(api.getTeamRoleAssignments))
+		rr.Post("/teams/:teamId/roles", authorize(assignRoles), routing.Wrap(api.addTeamRoleAssignment))
+		rr.Delete("/teams/:teamId/roles/:roleUID", authorize(assignRoles), routing.Wrap(api.removeTeamRoleAssignment))
 	}, requestmeta.SetOwner(requestmeta.TeamAuth))
 }
 
@@ -124,6 +139,128 @@ func (api *AccessControlAPI) searchUsersPermissions(c *contextmodel.ReqContext)
 	return response.JSON(http.StatusOK, permsByAction)
 }
 
+type permissionSourceDTO struct {
+	Type      ac.PermissionSourceType `json:"type"`
+	RoleName  string                  `json:"roleName"`
+	BasicRole string                  `json:"basicRole,omitempty"`
+	TeamID    int64                   `json:"teamId,omitempty"`
+	// Expires is when the role assignment granting the permissions is removed.
+	Expires *time.Time `json:"expires,omitempty"`
+	Scopes  []string   `json:"scopes"`
+}
+
+type permissionExplanationDTO struct {
+	Action  string `json:"action"`
+	Scope   string `json:"scope,omitempty"`
+	Allowed bool   `json:"allowed"`
+	// Sources lists the permissions granting the action on the scope, grouped by where they come from.
+	Sources []permissionSourceDTO `json:"sources"`
+}
+
+// GET /api/access-control/users/permissions/explain
+func (api *AccessControlAPI) explainUserPermission(c *contextmodel.ReqContext) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "accesscontrol.api.explainUserPermission")
+	defer span.End()
+
+	typedID, action, scope := c.Query("namespacedId"), c.Query("action"), c.Query("scope")
+	if typedID == "" || action == "" {
+		return response.JSON(http.StatusBadRequest, "'namespacedId' and 'action' must be provided")
+	}
+
+	userID, err := api.ComputeUserID(ctx, typedID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.JSON(http.StatusBadRequest, err.Error())
+		}
+		return response.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	target, err := api.userSvc.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: userID, OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.JSON(http.StatusBadRequest, err.Error())
+		}
+		return response.Error(http.StatusInternalServerError, "could not get user", err)
+	}
+
+	sources, err := api.Service.GetUserPermissionSources(ctx, target, action)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "could not get user permissions", err)
+	}
+
+	explanation, err := api.explain(ctx, target, action, scope, sources)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "could not evaluate user permissions", err)
+	}
+
+	return response.JSON(http.StatusOK, explanation)
+}
+
+// explain evaluates every permission of the sources on its own, so that only the ones granting
+// the action on the scope are reported. Permissions which only grant access once the scope has
+// been resolved to its parent folders are reported as folder inheritance.
+func (api *AccessControlAPI) explain(ctx context.Context, target *user.SignedInUser, action, scope string, sources []ac.PermissionSource) (*permissionExplanationDTO, error) {
+	evaluator := ac.EvalPermission(action)
+	if scope != "" {
+		evaluator = ac.EvalPermission(action, scope)
+	}
+	withoutResolvers := api.AccessControl.WithoutResolvers()
+
+	explanation := &permissionExplanationDTO{Action: action, Scope: scope, Sources: []permissionSourceDTO{}}
+	type sourceKey struct {
+		sourceType ac.PermissionSourceType
+		roleName   string
+		basicRole  string
+		teamID     int64
+	}
+	groups := map[sourceKey]int{}
+	for _, source := range sources {
+		for _, p := range source.Permissions {
+			probe := &user.SignedInUser{
+				UserID:      target.UserID,
+				OrgID:       target.OrgID,
+				Permissions: map[int64]map[string][]string{target.OrgID: {p.Action: {p.Scope}}},
+			}
+
+			sourceType := source.Type
+			granted, err := withoutResolvers.Evaluate(ctx, probe, evaluator)
+			if err != nil {
+				return nil, err
+			}
+			if !granted {
+				if granted, err = api.AccessControl.Evaluate(ctx, probe, evaluator); err != nil {
+					return nil, err
+				}
+				if granted && strings.HasPrefix(p.Scope, dashboards.ScopeFoldersRoot+":") {
+					sourceType = ac.PermissionSourceFolderInheritance
+				}
+			}
+			if !granted {
+				continue
+			}
+
+			key := sourceKey{sourceType, source.RoleName, source.BasicRole, source.TeamID}
+			i, ok := groups[key]
+			if !ok {
+				i = len(explanation.Sources)
+				groups[key] = i
+				explanation.Sources = append(explanation.Sources, permissionSourceDTO{
+					Type:      sourceType,
+					RoleName:  source.RoleName,
+					BasicRole: source.BasicRole,
+					TeamID:    source.TeamID,
+					Expires:   source.Expires,
+					Scopes:    []string{},
+				})
+			}
+			explanation.Sources[i].Scopes = append(explanation.Sources[i].Scopes, p.Scope)
+		}
+	}
+	explanation.Allowed = len(explanation.Sources) > 0
+
+	return explanation, nil
+}
+
 func (api *AccessControlAPI) ComputeUserID(ctx context.Context, typedID string) (int64, error) {
 	if typedID == "" {
 		return -1, nil
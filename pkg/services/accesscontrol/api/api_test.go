@@ -1,14 +1,19 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -216,3 +221,240 @@ func TestAccessControlAPI_searchUsersPermissions(t *testing.T) {
 		})
 	}
 }
+
+// evaluatingAccessControl evaluates the permissions of the requester, resolving scopes like acimpl.AccessControl.
+type evaluatingAccessControl struct {
+	resolvers ac.Resolvers
+}
+
+func (a evaluatingAccessControl) Evaluate(ctx context.Context, usr identity.Requester, evaluator ac.Evaluator) (bool, error) {
+	if evaluator.Evaluate(usr.GetPermissions()) {
+		return true, nil
+	}
+	resolved, err := evaluator.MutateScopes(ctx, a.resolvers.GetScopeAttributeMutator(usr.GetOrgID()))
+	if err != nil {
+		return false, nil
+	}
+	return resolved.Evaluate(usr.GetPermissions()), nil
+}
+
+func (a evaluatingAccessControl) RegisterScopeAttributeResolver(prefix string, resolver ac.ScopeAttributeResolver) {
+	a.resolvers.AddScopeAttributeResolver(prefix, resolver)
+}
+
+func (a evaluatingAccessControl) WithoutResolvers() ac.AccessControl {
+	return evaluatingAccessControl{resolvers: ac.NewResolvers(log.NewNopLogger())}
+}
+
+func TestAPI_explainUserPermission(t *testing.T) {
+	sources := []ac.PermissionSource{
+		{
+			Type:        ac.PermissionSourceBasicRole,
+			RoleName:    "fixed:dashboards:reader",
+			BasicRole:   "Viewer",
+			Permissions: []ac.Permission{{Action: "dashboards:read", Scope: "dashboards:uid:other"}},
+		},
+		{
+			Type:        ac.PermissionSourceTeam,
+			RoleName:    "managed:teams:1:permissions",
+			TeamID:      1,
+			Permissions: []ac.Permission{{Action: "dashboards:read", Scope: "folders:uid:parent"}},
+		},
+		{
+			Type:        ac.PermissionSourceManagedPermission,
+			RoleName:    "managed:users:2:permissions",
+			Permissions: []ac.Permission{{Action: "dashboards:read", Scope: "dashboards:uid:abc"}},
+		},
+	}
+
+	type testCase struct {
+		desc           string
+		query          string
+		sources        []ac.PermissionSource
+		expectedCode   int
+		expectedOutput permissionExplanationDTO
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should reject if no action is provided",
+			query:        "?namespacedId=user:2",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Should reject if no identity is provided",
+			query:        "?action=dashboards:read",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Should group contributing permissions by source",
+			query:        "?namespacedId=user:2&action=dashboards:read&scope=dashboards:uid:abc",
+			sources:      sources,
+			expectedCode: http.StatusOK,
+			expectedOutput: permissionExplanationDTO{
+				Action:  "dashboards:read",
+				Scope:   "dashboards:uid:abc",
+				Allowed: true,
+				Sources: []permissionSourceDTO{
+					{Type: ac.PermissionSourceFolderInheritance, RoleName: "managed:teams:1:permissions", TeamID: 1, Scopes: []string{"folders:uid:parent"}},
+					{Type: ac.PermissionSourceManagedPermission, RoleName: "managed:users:2:permissions", Scopes: []string{"dashboards:uid:abc"}},
+				},
+			},
+		},
+		{
+			desc:         "Should deny when no permission grants the scope",
+			query:        "?namespacedId=user:2&action=dashboards:read&scope=dashboards:uid:unknown",
+			sources:      sources[:1],
+			expectedCode: http.StatusOK,
+			expectedOutput: permissionExplanationDTO{
+				Action:  "dashboards:read",
+				Scope:   "dashboards:uid:unknown",
+				Allowed: false,
+				Sources: []permissionSourceDTO{},
+			},
+		},
+		{
+			desc:         "Should report every permission with the action when no scope is provided",
+			query:        "?namespacedId=service-account:2&action=dashboards:read",
+			sources:      sources[:1],
+			expectedCode: http.StatusOK,
+			expectedOutput: permissionExplanationDTO{
+				Action:  "dashboards:read",
+				Allowed: true,
+				Sources: []permissionSourceDTO{
+					{Type: ac.PermissionSourceBasicRole, RoleName: "fixed:dashboards:reader", BasicRole: "Viewer", Scopes: []string{"dashboards:uid:other"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			acSvc := actest.FakeService{ExpectedPermissionSources: tt.sources}
+			accessControl := evaluatingAccessControl{resolvers: ac.NewResolvers(log.NewNopLogger())}
+			accessControl.RegisterScopeAttributeResolver("dashboards:uid:", ac.ScopeAttributeResolverFunc(func(ctx context.Context, orgID int64, scope string) ([]string, error) {
+				return []string{scope, "folders:uid:parent"}, nil
+			}))
+			userSvc := &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 2, OrgID: 1}}
+			api := NewAccessControlAPI(routing.NewRouteRegister(), accessControl, acSvc, userSvc)
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewGetRequest("/api/access-control/users/permissions/explain" + tt.query)
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: {ac.ActionUsersPermissionsRead: {"users:*"}}},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, res.Body.Close()) }()
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.expectedCode == http.StatusOK {
+				var output permissionExplanationDTO
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+				require.Equal(t, tt.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestAPI_roleAssignments(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	assignRoles := map[string][]string{ac.ActionUsersPermissionsUpdate: {ac.ScopeGlobalUsersAll}}
+
+	type testCase struct {
+		desc         string
+		method       string
+		url          string
+		body         string
+		permissions  map[string][]string
+		userErr      error
+		serviceErr   error
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should assign a role to a user until the expiry",
+			method:       http.MethodPost,
+			url:          "/api/access-control/users/2/roles",
+			body:         `{"roleUid": "on_call", "expires": "2030-01-01T00:00:00Z"}`,
+			permissions:  assignRoles,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "Should not assign a role without permission",
+			method:       http.MethodPost,
+			url:          "/api/access-control/teams/2/roles",
+			body:         `{"roleUid": "on_call"}`,
+			permissions:  map[string][]string{ac.ActionTeamsPermissionsWrite: {"teams:*"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "Should reject an expiry in the past",
+			method:       http.MethodPost,
+			url:          "/api/access-control/teams/2/roles",
+			body:         `{"roleUid": "on_call", "expires": "2020-01-01T00:00:00Z"}`,
+			permissions:  assignRoles,
+			serviceErr:   ac.ErrRoleAssignmentExpired.Errorf("expired"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Should not assign a role to a missing user",
+			method:       http.MethodPost,
+			url:          "/api/access-control/users/3/roles",
+			body:         `{"roleUid": "on_call"}`,
+			permissions:  assignRoles,
+			userErr:      user.ErrUserNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "Should not remove a missing role",
+			method:       http.MethodDelete,
+			url:          "/api/access-control/users/2/roles/unknown",
+			permissions:  assignRoles,
+			serviceErr:   ac.ErrRoleNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "Should list the role assignments of a team",
+			method:       http.MethodGet,
+			url:          "/api/access-control/teams/2/roles",
+			permissions:  map[string][]string{ac.ActionTeamsPermissionsRead: {"teams:id:2"}},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			acSvc := actest.FakeService{
+				ExpectedErr:             tt.serviceErr,
+				ExpectedRoleAssignments: []ac.RoleAssignment{{RoleUID: "on_call", RoleName: "custom:on_call", Expires: &expires}},
+			}
+			accessControl := evaluatingAccessControl{resolvers: ac.NewResolvers(log.NewNopLogger())}
+			userSvc := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 2}, ExpectedError: tt.userErr}
+			api := NewAccessControlAPI(routing.NewRouteRegister(), accessControl, acSvc, userSvc)
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, res.Body.Close()) }()
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.method == http.MethodGet && tt.expectedCode == http.StatusOK {
+				var output []ac.RoleAssignment
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+				require.Len(t, output, 1)
+				require.Equal(t, expires, output[0].Expires.UTC())
+			}
+		})
+	}
+}
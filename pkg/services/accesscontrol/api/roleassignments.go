@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type addRoleAssignmentCommand struct {
+	RoleUID string `json:"roleUid"`
+	// Expires is when the assignment is removed, the assignment never expires when omitted.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// GET /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) getUserRoleAssignments(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := api.parseAssignedUser(c)
+	if errResp != nil {
+		return errResp
+	}
+	return api.getRoleAssignments(c, ac.GetRoleAssignmentsQuery{OrgID: c.GetOrgID(), UserID: userID})
+}
+
+// POST /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) addUserRoleAssignment(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := api.parseAssignedUser(c)
+	if errResp != nil {
+		return errResp
+	}
+	return api.addRoleAssignment(c, ac.SaveRoleAssignmentCommand{OrgID: c.GetOrgID(), UserID: userID})
+}
+
+// DELETE /api/access-control/users/:userId/roles/:roleUID
+func (api *AccessControlAPI) removeUserRoleAssignment(c *contextmodel.ReqContext) response.Response {
+	userID, errResp := api.parseAssignedUser(c)
+	if errResp != nil {
+		return errResp
+	}
+	return api.removeRoleAssignment(c, ac.DeleteRoleAssignmentCommand{OrgID: c.GetOrgID(), UserID: userID, RoleUID: web.Params(c.Req)[":roleUID"]})
+}
+
+// GET /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) getTeamRoleAssignments(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil || teamID <= 0 {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+	return api.getRoleAssignments(c, ac.GetRoleAssignmentsQuery{OrgID: c.GetOrgID(), TeamID: teamID})
+}
+
+// POST /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) addTeamRoleAssignment(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil || teamID <= 0 {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+	return api.addRoleAssignment(c, ac.SaveRoleAssignmentCommand{OrgID: c.GetOrgID(), TeamID: teamID})
+}
+
+// DELETE /api/access-control/teams/:teamId/roles/:roleUID
+func (api *AccessControlAPI) removeTeamRoleAssignment(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil || teamID <= 0 {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+	return api.removeRoleAssignment(c, ac.DeleteRoleAssignmentCommand{OrgID: c.GetOrgID(), TeamID: teamID, RoleUID: web.Params(c.Req)[":roleUID"]})
+}
+
+// parseAssignedUser returns the ID of the user or service account of the request.
+func (api *AccessControlAPI) parseAssignedUser(c *contextmodel.ReqContext) (int64, response.Response) {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	if _, err := api.userSvc.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: userID}); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return 0, response.Error(http.StatusNotFound, "user not found", err)
+		}
+		return 0, response.Error(http.StatusInternalServerError, "could not get user", err)
+	}
+	return userID, nil
+}
+
+func (api *AccessControlAPI) getRoleAssignments(c *contextmodel.ReqContext, query ac.GetRoleAssignmentsQuery) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "accesscontrol.api.getRoleAssignments")
+	defer span.End()
+
+	assignments, err := api.Service.GetRoleAssignments(ctx, query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "could not get role assignments", err)
+	}
+	return response.JSON(http.StatusOK, assignments)
+}
+
+func (api *AccessControlAPI) addRoleAssignment(c *contextmodel.ReqContext, cmd ac.SaveRoleAssignmentCommand) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "accesscontrol.api.addRoleAssignment")
+	defer span.End()
+
+	var body addRoleAssignmentCommand
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if body.RoleUID == "" {
+		return response.Error(http.StatusBadRequest, "roleUid is required", nil)
+	}
+	cmd.RoleUID, cmd.Expires = body.RoleUID, body.Expires
+
+	if err := api.Service.SaveRoleAssignment(ctx, cmd); err != nil {
+		return roleAssignmentError(err)
+	}
+	return response.Success("Role assigned")
+}
+
+func (api *AccessControlAPI) removeRoleAssignment(c *contextmodel.ReqContext, cmd ac.DeleteRoleAssignmentCommand) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "accesscontrol.api.removeRoleAssignment")
+	defer span.End()
+
+	if err := api.Service.DeleteRoleAssignment(ctx, cmd); err != nil {
+		return roleAssignmentError(err)
+	}
+	return response.Success("Role assignment removed")
+}
+
+func roleAssignmentError(err error) response.Response {
+	if errors.Is(err, ac.ErrRoleNotFound) {
+		return response.Error(http.StatusNotFound, "role not found", err)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, "could not save role assignment", err)
+}
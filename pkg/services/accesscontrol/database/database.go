@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 
@@ -59,14 +60,16 @@ func (s *AccessControlStore) GetUserPermissions(ctx context.Context, query acces
 			return nil
 		}
 
-		filter, params := accesscontrol.UserRolesFilter(query.OrgID, query.UserID, query.TeamIDs, query.Roles, s.sql.GetDialect())
+		expiry, params := accesscontrol.NotExpiredFilter("permission.expires")
+		filter, filterParams := accesscontrol.UserRolesFilter(query.OrgID, query.UserID, query.TeamIDs, query.Roles, s.sql.GetDialect())
+		params = append(params, filterParams...)
 
 		q := `
 		SELECT
 			permission.action,
 			permission.scope
 			FROM permission
-			INNER JOIN role ON role.id = permission.role_id
+			INNER JOIN role ON role.id = permission.role_id AND ` + expiry + `
 		` + filter
 
 		if len(query.RolePrefixes) > 0 {
@@ -120,25 +123,28 @@ func (s *AccessControlStore) GetTeamsPermissions(ctx context.Context, query acce
 			return nil
 		}
 
+		permissionExpiry, params := accesscontrol.NotExpiredFilter("permission.expires")
+		teamRoleExpiry, teamRoleExpiryParams := accesscontrol.NotExpiredFilter("tr.expires")
 		q := `
 		SELECT
 			permission.action,
 			permission.scope,
 			all_role.team_id
 		FROM permission
-		INNER JOIN role ON role.id = permission.role_id
+		INNER JOIN role ON role.id = permission.role_id AND ` + permissionExpiry + `
 		INNER JOIN (
 			SELECT tr.role_id, tr.team_id FROM team_role as tr
 			WHERE tr.team_id IN(?` + strings.Repeat(", ?", len(teams)-1) + `)
 			  AND tr.org_id = ?
+			  AND ` + teamRoleExpiry + `
 		) as all_role ON role.id = all_role.role_id
 		`
 
-		params := make([]any, 0)
 		for _, team := range teams {
 			params = append(params, team)
 		}
 		params = append(params, orgID)
+		params = append(params, teamRoleExpiryParams...)
 
 		if len(rolePrefixes) > 0 {
 			rolePrefixesFilter, filterParams := accesscontrol.RolePrefixesFilter(rolePrefixes)
@@ -164,6 +170,123 @@ func (s *AccessControlStore) GetTeamsPermissions(ctx context.Context, query acce
 	return teamPermissions, err
 }
 
+type permissionSource struct {
+	Action    string
+	Scope     string
+	RoleName  string `xorm:"role_name"`
+	TeamID    int64  `xorm:"team_id"`
+	BasicRole string `xorm:"basic_role"`
+	Expires   *time.Time
+}
+
+func (p permissionSource) sourceType() accesscontrol.PermissionSourceType {
+	switch {
+	case strings.HasPrefix(p.RoleName, accesscontrol.ManagedRolePrefix):
+		return accesscontrol.PermissionSourceManagedPermission
+	case p.TeamID > 0:
+		return accesscontrol.PermissionSourceTeam
+	case p.BasicRole != "":
+		return accesscontrol.PermissionSourceBasicRole
+	default:
+		return accesscontrol.PermissionSourceRole
+	}
+}
+
+// GetUserPermissionSources returns the permissions with one of the query actions assigned to a user,
+// grouped by role assignment. Unlike GetUserPermissions, team memberships are resolved from team_member.
+func (s *AccessControlStore) GetUserPermissionSources(ctx context.Context, query accesscontrol.GetUserPermissionSourcesQuery) ([]accesscontrol.PermissionSource, error) {
+	ctx, span := tracer.Start(ctx, "accesscontrol.database.GetUserPermissionSources")
+	defer span.End()
+
+	if len(query.Actions) == 0 || (query.UserID <= 0 && len(query.Roles) == 0) {
+		return []accesscontrol.PermissionSource{}, nil
+	}
+
+	permissionExpiry, params := accesscontrol.NotExpiredFilter("permission.expires")
+	assignments := make([]string, 0, 3)
+	// Only allow real users to get user/team permissions, as in UserRolesFilter.
+	if query.UserID > 0 {
+		userRoleExpiry, userRoleExpiryParams := accesscontrol.NotExpiredFilter("ur.expires")
+		teamRoleExpiry, teamRoleExpiryParams := accesscontrol.NotExpiredFilter("tr.expires")
+		assignments = append(assignments, `
+			SELECT ur.role_id, 0 AS team_id, '' AS basic_role, ur.expires
+			FROM user_role AS ur
+			WHERE ur.user_id = ? AND (ur.org_id = ? OR ur.org_id = ?) AND `+userRoleExpiry, `
+			SELECT tr.role_id, tr.team_id, '' AS basic_role, tr.expires
+			FROM team_role AS tr
+			INNER JOIN team_member AS tm ON tm.team_id = tr.team_id
+			WHERE tm.user_id = ? AND tr.org_id = ? AND `+teamRoleExpiry)
+		params = append(params, query.UserID, query.OrgID, accesscontrol.GlobalOrgID)
+		params = append(params, userRoleExpiryParams...)
+		params = append(params, query.UserID, query.OrgID)
+		params = append(params, teamRoleExpiryParams...)
+	}
+	if len(query.Roles) > 0 {
+		assignments = append(assignments, `
+			SELECT br.role_id, 0 AS team_id, br.role AS basic_role, NULL AS expires
+			FROM builtin_role AS br
+			WHERE br.role IN (?`+strings.Repeat(", ?", len(query.Roles)-1)+`) AND (br.org_id = ? OR br.org_id = ?)`)
+		for _, role := range query.Roles {
+			params = append(params, role)
+		}
+		params = append(params, query.OrgID, accesscontrol.GlobalOrgID)
+	}
+
+	q := `
+		SELECT
+			permission.action,
+			permission.scope,
+			role.name AS role_name,
+			all_role.team_id,
+			all_role.basic_role,
+			all_role.expires
+		FROM permission
+		INNER JOIN role ON role.id = permission.role_id AND ` + permissionExpiry + `
+		INNER JOIN (` + strings.Join(assignments, " UNION ALL ") + `
+		) AS all_role ON role.id = all_role.role_id
+	`
+
+	if len(query.RolePrefixes) > 0 {
+		rolePrefixesFilter, filterParams := accesscontrol.RolePrefixesFilter(query.RolePrefixes)
+		q += rolePrefixesFilter + " AND"
+		params = append(params, filterParams...)
+	} else {
+		q += " WHERE"
+	}
+	q += " permission.action IN (?" + strings.Repeat(", ?", len(query.Actions)-1) + ")"
+	for _, action := range query.Actions {
+		params = append(params, action)
+	}
+	q += " ORDER BY role.name, all_role.team_id, all_role.basic_role, permission.id"
+
+	rows := make([]permissionSource, 0)
+	if err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(q, params...).Find(&rows)
+	}); err != nil {
+		return nil, err
+	}
+
+	sources := make([]accesscontrol.PermissionSource, 0)
+	for _, row := range rows {
+		permission := accesscontrol.Permission{Action: row.Action, Scope: row.Scope}
+		if n := len(sources); n > 0 && sources[n-1].RoleName == row.RoleName &&
+			sources[n-1].TeamID == row.TeamID && sources[n-1].BasicRole == row.BasicRole {
+			sources[n-1].Permissions = append(sources[n-1].Permissions, permission)
+			continue
+		}
+		sources = append(sources, accesscontrol.PermissionSource{
+			Type:        row.sourceType(),
+			RoleName:    row.RoleName,
+			BasicRole:   row.BasicRole,
+			TeamID:      row.TeamID,
+			Expires:     row.Expires,
+			Permissions: []accesscontrol.Permission{permission},
+		})
+	}
+
+	return sources, nil
+}
+
 // SearchUsersPermissions returns the list of user permissions in specific organization indexed by UserID
 func (s *AccessControlStore) SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error) {
 	ctx, span := tracer.Start(ctx, "accesscontrol.database.SearchUsersPermissions")
@@ -182,19 +305,24 @@ func (s *AccessControlStore) SearchUsersPermissions(ctx context.Context, orgID i
 			roleNameFilterJoin = "INNER JOIN role AS r ON up.role_id = r.id"
 		}
 
-		params := []any{}
-
-		direct := userAssignsSQL
-		team := teamAssignsSQL
-		basic := basicRoleAssignsSQL
-
+		// Expired assignments and permissions are ignored until they are removed.
+		userRoleExpiry, params := accesscontrol.NotExpiredFilter("ur.expires")
+		direct := userAssignsSQL + " WHERE " + userRoleExpiry
 		if options.UserID > 0 {
-			direct += " WHERE ur.user_id = ?"
+			direct += " AND ur.user_id = ?"
 			params = append(params, options.UserID)
+		}
 
-			team += " WHERE tm.user_id = ?"
+		teamRoleExpiry, teamRoleExpiryParams := accesscontrol.NotExpiredFilter("tr.expires")
+		team := teamAssignsSQL + " WHERE " + teamRoleExpiry
+		params = append(params, teamRoleExpiryParams...)
+		if options.UserID > 0 {
+			team += " AND tm.user_id = ?"
 			params = append(params, options.UserID)
+		}
 
+		basic := basicRoleAssignsSQL
+		if options.UserID > 0 {
 			basic += " WHERE ou.user_id = ?"
 			params = append(params, options.UserID)
 		}
@@ -207,6 +335,7 @@ func (s *AccessControlStore) SearchUsersPermissions(ctx context.Context, orgID i
 		}
 
 		// Find permissions
+		permissionExpiry, permissionExpiryParams := accesscontrol.NotExpiredFilter("p.expires")
 		q := `
 		SELECT
 			user_id,
@@ -222,9 +351,10 @@ func (s *AccessControlStore) SearchUsersPermissions(ctx context.Context, orgID i
 			` + grafanaAdmin + `
 		) AS up ` + roleNameFilterJoin + `
 		INNER JOIN permission AS p ON up.role_id = p.role_id
-		WHERE (up.org_id = ? OR up.org_id = ?)
+		WHERE (up.org_id = ? OR up.org_id = ?) AND ` + permissionExpiry + `
 		`
 		params = append(params, orgID, accesscontrol.GlobalOrgID)
+		params = append(params, permissionExpiryParams...)
 
 		if options.ActionPrefix != "" {
 			q += ` AND p.action LIKE ?`
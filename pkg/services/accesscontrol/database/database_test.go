@@ -236,6 +236,74 @@ func TestIntegrationAccessControlStore_GetTeamsPermissions(t *testing.T) {
 	}
 }
 
+func TestIntegrationAccessControlStore_GetUserPermissionSources(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+	store, permissionStore, userSvc, teamSvc, _, sql := setupTestEnv(t)
+	user, team := createUserAndTeam(t, sql, userSvc, teamSvc, 1)
+
+	setPermission := func(resourceID string, set func(cmd rs.SetResourcePermissionCommand) error) {
+		require.NoError(t, set(rs.SetResourcePermissionCommand{
+			Actions:           []string{"dashboards:read", "dashboards:write"},
+			Resource:          "dashboards",
+			ResourceAttribute: "uid",
+			ResourceID:        resourceID,
+		}))
+	}
+	setPermission("1", func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetUserResourcePermission(ctx, 1, accesscontrol.User{ID: user.ID}, cmd, nil)
+		return err
+	})
+	setPermission("2", func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetTeamResourcePermission(ctx, 1, team.ID, cmd, nil)
+		return err
+	})
+	setPermission("3", func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetBuiltInResourcePermission(ctx, 1, "Viewer", cmd, nil)
+		return err
+	})
+	setPermission("4", func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetBuiltInResourcePermission(ctx, 1, "Editor", cmd, nil)
+		return err
+	})
+
+	t.Run("should group permissions by role assignment", func(t *testing.T) {
+		sources, err := store.GetUserPermissionSources(ctx, accesscontrol.GetUserPermissionSourcesQuery{
+			OrgID:   1,
+			UserID:  user.ID,
+			Roles:   []string{"Viewer"},
+			Actions: []string{"dashboards:read"},
+		})
+		require.NoError(t, err)
+		require.Len(t, sources, 3)
+
+		byRole := map[string]accesscontrol.PermissionSource{}
+		for _, source := range sources {
+			assert.Equal(t, accesscontrol.PermissionSourceManagedPermission, source.Type)
+			require.Len(t, source.Permissions, 1)
+			assert.Equal(t, "dashboards:read", source.Permissions[0].Action)
+			byRole[source.RoleName] = source
+		}
+
+		assert.Equal(t, "dashboards:uid:1", byRole[fmt.Sprintf("managed:users:%d:permissions", user.ID)].Permissions[0].Scope)
+		assert.Equal(t, team.ID, byRole[fmt.Sprintf("managed:teams:%d:permissions", team.ID)].TeamID)
+		assert.Equal(t, "Viewer", byRole["managed:builtins:viewer:permissions"].BasicRole)
+	})
+
+	t.Run("should only return basic role assignments without a user", func(t *testing.T) {
+		sources, err := store.GetUserPermissionSources(ctx, accesscontrol.GetUserPermissionSourcesQuery{
+			OrgID:   1,
+			Roles:   []string{"Editor"},
+			Actions: []string{"dashboards:write"},
+		})
+		require.NoError(t, err)
+		require.Len(t, sources, 1)
+		assert.Equal(t, "dashboards:uid:4", sources[0].Permissions[0].Scope)
+	})
+}
+
 func TestIntegrationAccessControlStore_DeleteUserPermissions(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
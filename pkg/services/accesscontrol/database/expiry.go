@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+type expiredGrant struct {
+	ID               int64 `xorm:"id"`
+	OrgID            int64 `xorm:"org_id"`
+	RoleName         string
+	UserID           int64 `xorm:"user_id"`
+	IsServiceAccount bool  `xorm:"is_service_account"`
+	TeamID           int64 `xorm:"team_id"`
+	BuiltInRole      string
+	Action           string
+	Scope            string
+	Expires          time.Time
+}
+
+// expiredGrantAudit is the audit record of a grant removed once expired.
+type expiredGrantAudit struct {
+	ID               int64 `xorm:"pk autoincr 'id'"`
+	OrgID            int64 `xorm:"org_id"`
+	Type             string
+	RoleName         string
+	UserID           int64 `xorm:"user_id"`
+	IsServiceAccount bool  `xorm:"is_service_account"`
+	TeamID           int64 `xorm:"team_id"`
+	BuiltInRole      string
+	Action           string
+	Scope            string
+	Expires          time.Time
+	Removed          time.Time
+}
+
+func (expiredGrantAudit) TableName() string {
+	return "expired_grant_audit"
+}
+
+func (g expiredGrant) toExpiredGrant(typ accesscontrol.ExpiredGrantType) accesscontrol.ExpiredGrant {
+	return accesscontrol.ExpiredGrant{
+		Type:             typ,
+		OrgID:            g.OrgID,
+		RoleName:         g.RoleName,
+		UserID:           g.UserID,
+		IsServiceAccount: g.IsServiceAccount,
+		TeamID:           g.TeamID,
+		BuiltInRole:      g.BuiltInRole,
+		Action:           g.Action,
+		Scope:            g.Scope,
+		Expires:          g.Expires,
+	}
+}
+
+// DeleteExpiredGrants removes the permissions, user role assignments and team role
+// assignments whose expiry is before now and returns what was removed.
+func (s *AccessControlStore) DeleteExpiredGrants(ctx context.Context, now time.Time) ([]accesscontrol.ExpiredGrant, error) {
+	ctx, span := tracer.Start(ctx, "accesscontrol.database.DeleteExpiredGrants")
+	defer span.End()
+
+	result := make([]accesscontrol.ExpiredGrant, 0)
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		userTable := s.sql.GetDialect().Quote("user")
+
+		// A managed role is assigned to exactly one user, team or basic role,
+		// the left joins resolve which one the permission was granted to.
+		var permissions []expiredGrant
+		if err := sess.SQL(`
+			SELECT
+				p.id, p.action, p.scope, p.expires,
+				r.org_id, r.name AS role_name,
+				ur.user_id, u.is_service_account,
+				tr.team_id,
+				br.role AS built_in_role
+			FROM permission AS p
+				INNER JOIN role AS r ON r.id = p.role_id
+				LEFT JOIN user_role AS ur ON ur.role_id = r.id
+				LEFT JOIN `+userTable+` AS u ON u.id = ur.user_id
+				LEFT JOIN team_role AS tr ON tr.role_id = r.id
+				LEFT JOIN builtin_role AS br ON br.role_id = r.id
+			WHERE p.expires IS NOT NULL AND p.expires <= ?`, now).Find(&permissions); err != nil {
+			return err
+		}
+
+		var userRoles []expiredGrant
+		if err := sess.SQL(`
+			SELECT ur.id, ur.org_id, ur.user_id, ur.expires, r.name AS role_name, u.is_service_account
+			FROM user_role AS ur
+				INNER JOIN role AS r ON r.id = ur.role_id
+				LEFT JOIN `+userTable+` AS u ON u.id = ur.user_id
+			WHERE ur.expires IS NOT NULL AND ur.expires <= ?`, now).Find(&userRoles); err != nil {
+			return err
+		}
+
+		var teamRoles []expiredGrant
+		if err := sess.SQL(`
+			SELECT tr.id, tr.org_id, tr.team_id, tr.expires, r.name AS role_name
+			FROM team_role AS tr
+				INNER JOIN role AS r ON r.id = tr.role_id
+			WHERE tr.expires IS NOT NULL AND tr.expires <= ?`, now).Find(&teamRoles); err != nil {
+			return err
+		}
+
+		for _, table := range []string{"permission", "user_role", "team_role"} {
+			if _, err := sess.Exec("DELETE FROM "+table+" WHERE expires IS NOT NULL AND expires <= ?", now); err != nil {
+				return err
+			}
+		}
+
+		for _, g := range permissions {
+			result = append(result, g.toExpiredGrant(accesscontrol.ExpiredGrantPermission))
+		}
+		for _, g := range userRoles {
+			result = append(result, g.toExpiredGrant(accesscontrol.ExpiredGrantUserRole))
+		}
+		for _, g := range teamRoles {
+			result = append(result, g.toExpiredGrant(accesscontrol.ExpiredGrantTeamRole))
+		}
+
+		// The audit records are written with the removal so that no grant is removed without one.
+		removed := time.Now()
+		for _, g := range result {
+			if _, err := sess.Insert(&expiredGrantAudit{
+				OrgID:            g.OrgID,
+				Type:             string(g.Type),
+				RoleName:         g.RoleName,
+				UserID:           g.UserID,
+				IsServiceAccount: g.IsServiceAccount,
+				TeamID:           g.TeamID,
+				BuiltInRole:      g.BuiltInRole,
+				Action:           g.Action,
+				Scope:            g.Scope,
+				Expires:          g.Expires,
+				Removed:          removed,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,118 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	rs "github.com/grafana/grafana/pkg/services/accesscontrol/resourcepermissions"
+)
+
+func TestIntegrationAccessControlStore_DeleteExpiredGrants(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+	store, permissionStore, userSvc, teamSvc, _, sql := setupTestEnv(t)
+	user, team := createUserAndTeam(t, sql, userSvc, teamSvc, 1)
+
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	valid := now.Add(time.Hour)
+
+	setPermission := func(resourceID string, expires *time.Time) rs.SetResourcePermissionCommand {
+		return rs.SetResourcePermissionCommand{
+			Actions:           []string{"dashboards:read"},
+			Resource:          "dashboards",
+			ResourceAttribute: "uid",
+			ResourceID:        resourceID,
+			Expires:           expires,
+		}
+	}
+
+	_, err := permissionStore.SetUserResourcePermission(ctx, 1, accesscontrol.User{ID: user.ID}, setPermission("1", &expired), nil)
+	require.NoError(t, err)
+	_, err = permissionStore.SetUserResourcePermission(ctx, 1, accesscontrol.User{ID: user.ID}, setPermission("2", &valid), nil)
+	require.NoError(t, err)
+	_, err = permissionStore.SetUserResourcePermission(ctx, 1, accesscontrol.User{ID: user.ID}, setPermission("3", nil), nil)
+	require.NoError(t, err)
+	_, err = permissionStore.SetTeamResourcePermission(ctx, 1, team.ID, setPermission("4", &expired), nil)
+	require.NoError(t, err)
+	_, err = permissionStore.SetBuiltInResourcePermission(ctx, 1, "Viewer", setPermission("5", &expired), nil)
+	require.NoError(t, err)
+
+	err = sql.WithDbSession(ctx, func(sess *db.Session) error {
+		role := accesscontrol.Role{OrgID: 1, UID: "on_call", Name: "custom:on_call", Created: now, Updated: now}
+		if _, err := sess.Insert(&role); err != nil {
+			return err
+		}
+		if _, err := sess.Insert(&accesscontrol.UserRole{OrgID: 1, RoleID: role.ID, UserID: user.ID, Created: now, Expires: &expired}); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&accesscontrol.TeamRole{OrgID: 1, RoleID: role.ID, TeamID: team.ID, Created: now, Expires: &valid})
+		return err
+	})
+	require.NoError(t, err)
+
+	getScopes := func() []string {
+		permissions, err := store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
+			OrgID:   1,
+			UserID:  user.ID,
+			TeamIDs: []int64{team.ID},
+			Roles:   []string{"Viewer"},
+		})
+		require.NoError(t, err)
+		scopes := make([]string, 0, len(permissions))
+		for _, p := range permissions {
+			scopes = append(scopes, p.Scope)
+		}
+		return scopes
+	}
+
+	// expired grants are ignored before they are removed
+	assert.ElementsMatch(t, []string{"dashboards:uid:2", "dashboards:uid:3"}, getScopes())
+
+	grants, err := store.DeleteExpiredGrants(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, grants, 4)
+
+	var audited int64
+	err = sql.WithDbSession(ctx, func(sess *db.Session) error {
+		audited, err = sess.Table("expired_grant_audit").Count()
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), audited)
+
+	byScope := map[string]accesscontrol.ExpiredGrant{}
+	for _, g := range grants {
+		byScope[g.Scope] = g
+	}
+	assert.Equal(t, user.ID, byScope["dashboards:uid:1"].UserID)
+	assert.Equal(t, fmt.Sprintf("managed:users:%d:permissions", user.ID), byScope["dashboards:uid:1"].RoleName)
+	assert.Equal(t, team.ID, byScope["dashboards:uid:4"].TeamID)
+	assert.Equal(t, "Viewer", byScope["dashboards:uid:5"].BuiltInRole)
+	assert.Equal(t, accesscontrol.ExpiredGrant{
+		Type:     accesscontrol.ExpiredGrantUserRole,
+		OrgID:    1,
+		RoleName: "custom:on_call",
+		UserID:   user.ID,
+		Expires:  byScope[""].Expires,
+	}, byScope[""])
+
+	assert.ElementsMatch(t, []string{"dashboards:uid:2", "dashboards:uid:3"}, getScopes())
+
+	grants, err = store.DeleteExpiredGrants(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+
+	grants, err = store.DeleteExpiredGrants(ctx, valid.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+}
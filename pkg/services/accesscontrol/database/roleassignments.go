@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// GetRoleAssignments returns the roles assigned directly to a user or a team in the organization,
+// expired assignments are left out.
+func (s *AccessControlStore) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "accesscontrol.database.GetRoleAssignments")
+	defer span.End()
+
+	table, filter, assignee := assignmentTable(query.UserID, query.TeamID)
+	expiry, params := accesscontrol.NotExpiredFilter("a.expires")
+	params = append([]any{assignee, query.OrgID}, params...)
+
+	result := make([]accesscontrol.RoleAssignment, 0)
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(`
+			SELECT r.uid AS role_uid, r.name AS role_name, a.created, a.expires
+			FROM `+table+` AS a
+				INNER JOIN role AS r ON r.id = a.role_id
+			WHERE `+filter+` AND a.org_id = ? AND `+expiry+`
+			ORDER BY r.name`, params...).Find(&result)
+	})
+
+	return result, err
+}
+
+// SaveRoleAssignment assigns a custom or fixed role to a user or a team in the organization.
+// Assigning a role again replaces the expiry of the assignment.
+func (s *AccessControlStore) SaveRoleAssignment(ctx context.Context, cmd accesscontrol.SaveRoleAssignmentCommand) error {
+	ctx, span := tracer.Start(ctx, "accesscontrol.database.SaveRoleAssignment")
+	defer span.End()
+
+	if cmd.Expires != nil && !cmd.Expires.After(time.Now()) {
+		return accesscontrol.ErrRoleAssignmentExpired.Errorf("expiry %s is not in the future", cmd.Expires.Format(time.RFC3339))
+	}
+
+	table, filter, assignee := assignmentTable(cmd.UserID, cmd.TeamID)
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getAssignableRole(ctx, sess, cmd.OrgID, cmd.RoleUID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM "+table+" WHERE "+filter+" AND org_id = ? AND role_id = ?", assignee, cmd.OrgID, role.ID); err != nil {
+			return err
+		}
+
+		if cmd.UserID != 0 {
+			_, err = sess.Insert(&accesscontrol.UserRole{OrgID: cmd.OrgID, RoleID: role.ID, UserID: cmd.UserID, Created: time.Now(), Expires: cmd.Expires})
+		} else {
+			_, err = sess.Insert(&accesscontrol.TeamRole{OrgID: cmd.OrgID, RoleID: role.ID, TeamID: cmd.TeamID, Created: time.Now(), Expires: cmd.Expires})
+		}
+		return err
+	})
+}
+
+// DeleteRoleAssignment removes a custom or fixed role from a user or a team in the organization.
+func (s *AccessControlStore) DeleteRoleAssignment(ctx context.Context, cmd accesscontrol.DeleteRoleAssignmentCommand) error {
+	ctx, span := tracer.Start(ctx, "accesscontrol.database.DeleteRoleAssignment")
+	defer span.End()
+
+	table, filter, assignee := assignmentTable(cmd.UserID, cmd.TeamID)
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		role, err := getAssignableRole(ctx, sess, cmd.OrgID, cmd.RoleUID)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM "+table+" WHERE "+filter+" AND org_id = ? AND role_id = ?", assignee, cmd.OrgID, role.ID)
+		return err
+	})
+}
+
+// assignmentTable returns the table holding the role assignments of the user, or of the team
+// when no user is set, and the condition selecting the direct assignments of the assignee.
+// User roles assigned through group mappings are synced by the auth services.
+func assignmentTable(userID, teamID int64) (string, string, int64) {
+	if userID != 0 {
+		return "user_role", "user_id = ? AND (group_mapping_uid = '' OR group_mapping_uid IS NULL)", userID
+	}
+	return "team_role", "team_id = ?", teamID
+}
+
+// getAssignableRole returns a role of the organization, or a global role, that can be assigned
+// directly. Managed, basic and external service roles are assigned by the services owning them.
+func getAssignableRole(ctx context.Context, sess *db.Session, orgID int64, uid string) (*accesscontrol.Role, error) {
+	_, span := tracer.Start(ctx, "accesscontrol.database.getAssignableRole")
+	defer span.End()
+
+	var role accesscontrol.Role
+	has, err := sess.Where("uid = ? AND (org_id = ? OR org_id = ?)", uid, orgID, accesscontrol.GlobalOrgID).Get(&role)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, accesscontrol.ErrRoleNotFound
+	}
+	dto := accesscontrol.RoleDTO{UID: role.UID, Name: role.Name}
+	if dto.IsManaged() || dto.IsBasic() || dto.IsExternalService() {
+		return nil, accesscontrol.ErrRoleAssignmentNotAllowed.Errorf("role %s cannot be assigned directly", role.Name)
+	}
+	return &role, nil
+}
@@ -0,0 +1,100 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestIntegrationAccessControlStore_RoleAssignments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+	store, _, userSvc, teamSvc, _, sql := setupTestEnv(t)
+	user, team := createUserAndTeam(t, sql, userSvc, teamSvc, 1)
+
+	now := time.Now()
+	err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+		role := accesscontrol.Role{OrgID: 1, UID: "on_call", Name: "custom:on_call", Created: now, Updated: now}
+		if _, err := sess.Insert(&role); err != nil {
+			return err
+		}
+		if _, err := sess.Insert(&accesscontrol.Permission{RoleID: role.ID, Action: "dashboards:write", Scope: "dashboards:*", Created: now, Updated: now}); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&accesscontrol.Role{OrgID: 1, UID: "managed_1", Name: "managed:users:1:permissions", Created: now, Updated: now})
+		return err
+	})
+	require.NoError(t, err)
+
+	getPermissions := func() []accesscontrol.Permission {
+		permissions, err := store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 1, UserID: user.ID, TeamIDs: []int64{team.ID}})
+		require.NoError(t, err)
+		return permissions
+	}
+
+	t.Run("should assign a role to a user until the expiry", func(t *testing.T) {
+		expires := now.Add(time.Hour).Truncate(time.Second)
+		err := store.SaveRoleAssignment(ctx, accesscontrol.SaveRoleAssignmentCommand{OrgID: 1, RoleUID: "on_call", UserID: user.ID, Expires: &expires})
+		require.NoError(t, err)
+
+		assignments, err := store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{OrgID: 1, UserID: user.ID})
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		assert.Equal(t, "custom:on_call", assignments[0].RoleName)
+		require.NotNil(t, assignments[0].Expires)
+		assert.True(t, expires.Equal(*assignments[0].Expires))
+		assert.Len(t, getPermissions(), 1)
+
+		// assigning the role again removes the expiry
+		err = store.SaveRoleAssignment(ctx, accesscontrol.SaveRoleAssignmentCommand{OrgID: 1, RoleUID: "on_call", UserID: user.ID})
+		require.NoError(t, err)
+		assignments, err = store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{OrgID: 1, UserID: user.ID})
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		assert.Nil(t, assignments[0].Expires)
+
+		err = store.DeleteRoleAssignment(ctx, accesscontrol.DeleteRoleAssignmentCommand{OrgID: 1, RoleUID: "on_call", UserID: user.ID})
+		require.NoError(t, err)
+		assert.Empty(t, getPermissions())
+	})
+
+	t.Run("should ignore expired team role assignments", func(t *testing.T) {
+		expired := now.Add(-time.Hour)
+		err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+			var role accesscontrol.Role
+			if _, err := sess.Where("uid = ?", "on_call").Get(&role); err != nil {
+				return err
+			}
+			_, err := sess.Insert(&accesscontrol.TeamRole{OrgID: 1, RoleID: role.ID, TeamID: team.ID, Created: now, Expires: &expired})
+			return err
+		})
+		require.NoError(t, err)
+
+		assignments, err := store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{OrgID: 1, TeamID: team.ID})
+		require.NoError(t, err)
+		assert.Empty(t, assignments)
+		assert.Empty(t, getPermissions())
+	})
+
+	t.Run("should reject an expiry in the past", func(t *testing.T) {
+		expired := now.Add(-time.Minute)
+		err := store.SaveRoleAssignment(ctx, accesscontrol.SaveRoleAssignmentCommand{OrgID: 1, RoleUID: "on_call", TeamID: team.ID, Expires: &expired})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAssignmentExpired)
+	})
+
+	t.Run("should not assign managed or missing roles", func(t *testing.T) {
+		err := store.SaveRoleAssignment(ctx, accesscontrol.SaveRoleAssignmentCommand{OrgID: 1, RoleUID: "managed_1", UserID: user.ID})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAssignmentNotAllowed)
+
+		err = store.SaveRoleAssignment(ctx, accesscontrol.SaveRoleAssignmentCommand{OrgID: 1, RoleUID: "unknown", UserID: user.ID})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+}
@@ -18,6 +18,8 @@ var (
 	ErrNoneRoleAssignment       = errutil.BadRequest("accesscontrol.noneRoleAssignment", errutil.WithPublicMessage("none role cannot receive permissions"))
 	ErrAssignmentEntityNotFound = errutil.BadRequest("accesscontrol.assignmentEntityNotFound").
 					MustTemplate(assignmentEntityNotFoundMessage, errutil.WithPublic(assignmentEntityNotFoundMessage))
+	ErrRoleAssignmentNotAllowed = errutil.BadRequest("accesscontrol.roleAssignmentNotAllowed", errutil.WithPublicMessage("managed, basic and external service roles cannot be assigned"))
+	ErrRoleAssignmentExpired    = errutil.BadRequest("accesscontrol.roleAssignmentExpired", errutil.WithPublicMessage("expiry of the role assignment must be in the future"))
 
 	// Note: these are intended to be replaced by equivalent errutil implementations.
 	// Avoid creating new errors with errors.New and prefer errutil
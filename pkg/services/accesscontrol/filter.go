@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
@@ -20,6 +21,7 @@ var sqlIDAcceptList = map[string]struct{}{
 	"`user`.`id`":      {}, // For MySQL and SQLite
 	"dashboard.uid":    {},
 	"report.id":        {},
+	"saved_query.uid":  {},
 }
 
 var (
@@ -129,35 +131,46 @@ func SetAcceptListForTest(list map[string]struct{}) func() {
 	}
 }
 
+// NotExpiredFilter returns a condition matching the rows whose expires column is not set or in the future.
+func NotExpiredFilter(column string) (string, []any) {
+	return "(" + column + " IS NULL OR " + column + " > ?)", []any{time.Now()}
+}
+
 func UserRolesFilter(orgID, userID int64, teamIDs []int64, roles []string, dialect migrator.Dialect) (string, []any) {
 	var params []any
 	builder := strings.Builder{}
 
 	// This is an additional security. We should never have permissions granted to userID 0.
 	// Only allow real users to get user/team permissions (anonymous/apikeys)
+	// Expired assignments are ignored until the access control service removes them.
 	if userID > 0 {
+		expiry, expiryParams := NotExpiredFilter("ur.expires")
 		builder.WriteString(`
 			SELECT ur.role_id
 			FROM user_role AS ur
 			WHERE ur.user_id = ?
 			AND (ur.org_id = ? OR ur.org_id = ?)
+			AND ` + expiry + `
 		`)
-		params = []any{userID, orgID, GlobalOrgID}
+		params = append([]any{userID, orgID, GlobalOrgID}, expiryParams...)
 	}
 
 	if len(teamIDs) > 0 {
 		if builder.Len() > 0 {
 			builder.WriteString(dialect.UnionDistinct())
 		}
+		expiry, expiryParams := NotExpiredFilter("tr.expires")
 		builder.WriteString(`
 			SELECT tr.role_id FROM team_role as tr
 			WHERE tr.team_id IN(?` + strings.Repeat(", ?", len(teamIDs)-1) + `)
 			AND tr.org_id = ?
+			AND ` + expiry + `
 		`)
 		for _, id := range teamIDs {
 			params = append(params, id)
 		}
 		params = append(params, orgID)
+		params = append(params, expiryParams...)
 	}
 
 	if len(roles) != 0 {
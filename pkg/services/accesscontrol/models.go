@@ -171,6 +171,8 @@ type TeamRole struct {
 	TeamID int64 `json:"teamId" xorm:"team_id"`
 
 	Created time.Time
+	// Expires is when the assignment is removed, nil when it never expires.
+	Expires *time.Time `json:"expires,omitempty"`
 }
 
 type UserRole struct {
@@ -181,6 +183,8 @@ type UserRole struct {
 	GroupMappingUID string `json:"groupMappingUID" xorm:"group_mapping_uid"`
 
 	Created time.Time
+	// Expires is when the assignment is removed, nil when it never expires.
+	Expires *time.Time `json:"expires,omitempty"`
 }
 
 type BuiltinRole struct {
@@ -228,6 +232,46 @@ type GetUserPermissionsQuery struct {
 	RolePrefixes []string
 }
 
+// GetUserPermissionSourcesQuery selects the role assignments granting any of
+// the actions to a user, directly, through a team or through a basic role.
+type GetUserPermissionSourcesQuery struct {
+	OrgID        int64
+	UserID       int64
+	Roles        []string
+	Actions      []string
+	RolePrefixes []string
+}
+
+// PermissionSourceType describes how a permission ends up granted to a user.
+type PermissionSourceType string
+
+const (
+	// PermissionSourceBasicRole is a fixed or plugin role granted to a basic role.
+	PermissionSourceBasicRole PermissionSourceType = "basic_role"
+	// PermissionSourceRole is a fixed, custom or external service role assigned to the user.
+	PermissionSourceRole PermissionSourceType = "role"
+	// PermissionSourceTeam is a role assigned to a team the user belongs to.
+	PermissionSourceTeam PermissionSourceType = "team"
+	// PermissionSourceManagedPermission is a resource permission managed on the resource itself.
+	PermissionSourceManagedPermission PermissionSourceType = "managed_permission"
+	// PermissionSourceFolderInheritance is a permission granted on a parent folder of the resource.
+	PermissionSourceFolderInheritance PermissionSourceType = "folder_inheritance"
+)
+
+// PermissionSource groups the permissions a user gets from a single role assignment.
+type PermissionSource struct {
+	Type PermissionSourceType `json:"type"`
+	// RoleName is the name of the role holding the permissions.
+	RoleName string `json:"roleName"`
+	// BasicRole is set when the role is granted through a basic role.
+	BasicRole string `json:"basicRole,omitempty"`
+	// TeamID is set when the role is assigned to a team.
+	TeamID int64 `json:"teamId,omitempty"`
+	// Expires is when the user or team role assignment is removed, nil when it never expires.
+	Expires     *time.Time   `json:"expires,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
 // ResourcePermission is structure that holds all actions that either a team / user / builtin-role
 // can perform against specific resource.
 type ResourcePermission struct {
@@ -249,6 +293,7 @@ type ResourcePermission struct {
 	IsServiceAccount bool
 	Created          time.Time
 	Updated          time.Time
+	Expires          *time.Time
 }
 
 func (p *ResourcePermission) Contains(targetActions []string) bool {
@@ -279,6 +324,73 @@ type SetResourcePermissionCommand struct {
 	TeamID      int64  `json:"teamId,omitempty"`
 	BuiltinRole string `json:"builtInRole,omitempty"`
 	Permission  string `json:"permission"`
+	// Expires is when the permission is removed, nil when it never expires.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// ExpiredGrantType is the kind of grant removed once its expiry has passed.
+type ExpiredGrantType string
+
+const (
+	// ExpiredGrantUserRole is a role assigned to a user or a service account.
+	ExpiredGrantUserRole ExpiredGrantType = "user_role"
+	// ExpiredGrantTeamRole is a role assigned to a team.
+	ExpiredGrantTeamRole ExpiredGrantType = "team_role"
+	// ExpiredGrantPermission is a managed resource permission.
+	ExpiredGrantPermission ExpiredGrantType = "permission"
+)
+
+// ExpiredGrant describes a role assignment or a resource permission that was
+// removed because it expired. Exactly one of UserID, TeamID or BuiltInRole is set.
+type ExpiredGrant struct {
+	Type             ExpiredGrantType
+	OrgID            int64
+	RoleName         string
+	UserID           int64
+	IsServiceAccount bool
+	TeamID           int64
+	BuiltInRole      string
+	// Action and Scope are only set for permissions.
+	Action  string
+	Scope   string
+	Expires time.Time
+}
+
+// RoleAssignment is a role assigned to a user, a service account or a team.
+type RoleAssignment struct {
+	RoleUID  string    `json:"roleUid" xorm:"role_uid"`
+	RoleName string    `json:"roleName" xorm:"role_name"`
+	Created  time.Time `json:"created"`
+	// Expires is when the assignment is removed, nil when it never expires.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// GetRoleAssignmentsQuery lists the roles assigned to a user or a team in an organization.
+// Exactly one of UserID or TeamID is set.
+type GetRoleAssignmentsQuery struct {
+	OrgID  int64
+	UserID int64
+	TeamID int64
+}
+
+// SaveRoleAssignmentCommand assigns a role to a user or a team in an organization, assigning
+// it again replaces the expiry. Exactly one of UserID or TeamID is set.
+type SaveRoleAssignmentCommand struct {
+	OrgID   int64
+	RoleUID string
+	UserID  int64
+	TeamID  int64
+	// Expires is when the assignment is removed, nil when it never expires.
+	Expires *time.Time
+}
+
+// DeleteRoleAssignmentCommand removes a role from a user or a team in an organization.
+// Exactly one of UserID or TeamID is set.
+type DeleteRoleAssignmentCommand struct {
+	OrgID   int64
+	RoleUID string
+	UserID  int64
+	TeamID  int64
 }
 
 type SaveExternalServiceRoleCommand struct {
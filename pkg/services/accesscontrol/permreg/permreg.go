@@ -96,6 +96,8 @@ func newPermissionRegistry() *permissionRegistry {
 		"receivers":           "receivers:uid:",
 		"secret.securevalues": "secret.securevalues:uid:",
 		"secret.keepers":      "secret.keepers:uid:",
+		"savedqueries":        "savedqueries:uid:",
+		"shorturls":           "shorturls:uid:",
 	}
 	return &permissionRegistry{
 		actionScopePrefixes: make(map[string]PrefixSet, 200),
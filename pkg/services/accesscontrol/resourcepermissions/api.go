@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"go.opentelemetry.io/otel"
 
@@ -128,22 +129,23 @@ func (a *api) getDescription(c *contextmodel.ReqContext) response.Response {
 }
 
 type resourcePermissionDTO struct {
-	ID               int64    `json:"id"`
-	RoleName         string   `json:"roleName"`
-	IsManaged        bool     `json:"isManaged"`
-	IsInherited      bool     `json:"isInherited"`
-	IsServiceAccount bool     `json:"isServiceAccount"`
-	UserID           int64    `json:"userId,omitempty"`
-	UserUID          string   `json:"userUid,omitempty"`
-	UserLogin        string   `json:"userLogin,omitempty"`
-	UserAvatarUrl    string   `json:"userAvatarUrl,omitempty"`
-	Team             string   `json:"team,omitempty"`
-	TeamID           int64    `json:"teamId,omitempty"`
-	TeamUID          string   `json:"teamUid,omitempty"`
-	TeamAvatarUrl    string   `json:"teamAvatarUrl,omitempty"`
-	BuiltInRole      string   `json:"builtInRole,omitempty"`
-	Actions          []string `json:"actions"`
-	Permission       string   `json:"permission"`
+	ID               int64      `json:"id"`
+	RoleName         string     `json:"roleName"`
+	IsManaged        bool       `json:"isManaged"`
+	IsInherited      bool       `json:"isInherited"`
+	IsServiceAccount bool       `json:"isServiceAccount"`
+	UserID           int64      `json:"userId,omitempty"`
+	UserUID          string     `json:"userUid,omitempty"`
+	UserLogin        string     `json:"userLogin,omitempty"`
+	UserAvatarUrl    string     `json:"userAvatarUrl,omitempty"`
+	Team             string     `json:"team,omitempty"`
+	TeamID           int64      `json:"teamId,omitempty"`
+	TeamUID          string     `json:"teamUid,omitempty"`
+	TeamAvatarUrl    string     `json:"teamAvatarUrl,omitempty"`
+	BuiltInRole      string     `json:"builtInRole,omitempty"`
+	Actions          []string   `json:"actions"`
+	Permission       string     `json:"permission"`
+	Expires          *time.Time `json:"expires,omitempty"`
 }
 
 // swagger:parameters getResourcePermissions
@@ -214,6 +216,7 @@ func (a *api) getPermissions(c *contextmodel.ReqContext) response.Response {
 				IsManaged:        p.IsManaged,
 				IsInherited:      p.IsInherited,
 				IsServiceAccount: p.IsServiceAccount,
+				Expires:          p.Expires,
 			})
 		}
 	}
@@ -223,6 +226,8 @@ func (a *api) getPermissions(c *contextmodel.ReqContext) response.Response {
 
 type setPermissionCommand struct {
 	Permission string `json:"permission"`
+	// Expires is when the permission is removed, the permission never expires when omitted.
+	Expires *time.Time `json:"expires,omitempty"`
 }
 
 type setPermissionsCommand struct {
@@ -283,7 +288,13 @@ func (a *api) setUserPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	_, err = a.service.SetUserPermission(c.Req.Context(), c.GetOrgID(), accesscontrol.User{ID: userID}, resourceID, cmd.Permission)
+	if cmd.Expires != nil {
+		_, err = a.service.SetPermissions(c.Req.Context(), c.GetOrgID(), resourceID, accesscontrol.SetResourcePermissionCommand{
+			UserID: userID, Permission: cmd.Permission, Expires: cmd.Expires,
+		})
+	} else {
+		_, err = a.service.SetUserPermission(c.Req.Context(), c.GetOrgID(), accesscontrol.User{ID: userID}, resourceID, cmd.Permission)
+	}
 	if err != nil {
 		return response.Err(err)
 	}
@@ -340,7 +351,13 @@ func (a *api) setTeamPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	_, err = a.service.SetTeamPermission(c.Req.Context(), c.GetOrgID(), teamID, resourceID, cmd.Permission)
+	if cmd.Expires != nil {
+		_, err = a.service.SetPermissions(c.Req.Context(), c.GetOrgID(), resourceID, accesscontrol.SetResourcePermissionCommand{
+			TeamID: teamID, Permission: cmd.Permission, Expires: cmd.Expires,
+		})
+	} else {
+		_, err = a.service.SetTeamPermission(c.Req.Context(), c.GetOrgID(), teamID, resourceID, cmd.Permission)
+	}
 	if err != nil {
 		return response.Err(err)
 	}
@@ -394,7 +411,14 @@ func (a *api) setBuiltinRolePermission(c *contextmodel.ReqContext) response.Resp
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	_, err := a.service.SetBuiltInRolePermission(c.Req.Context(), c.GetOrgID(), builtInRole, resourceID, cmd.Permission)
+	var err error
+	if cmd.Expires != nil {
+		_, err = a.service.SetPermissions(c.Req.Context(), c.GetOrgID(), resourceID, accesscontrol.SetResourcePermissionCommand{
+			BuiltinRole: builtInRole, Permission: cmd.Permission, Expires: cmd.Expires,
+		})
+	} else {
+		_, err = a.service.SetBuiltInRolePermission(c.Req.Context(), c.GetOrgID(), builtInRole, resourceID, cmd.Permission)
+	}
 	if err != nil {
 		return response.Err(err)
 	}
@@ -1,6 +1,8 @@
 package resourcepermissions
 
 import (
+	"time"
+
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 )
 
@@ -9,6 +11,7 @@ const (
 	invalidAssignmentMessage = `Assignment [{{ .Public.assignment }}] is invalid for this resource type`
 	invalidParamMessage      = `Param [{{ .Public.param }}] is invalid`
 	invalidRequestBody       = `Request body is invalid: {{ .Public.reason }}`
+	invalidExpiryMessage     = `Expiry [{{ .Public.expires }}] must be in the future`
 )
 
 var (
@@ -20,6 +23,8 @@ var (
 				MustTemplate(invalidPermissionMessage, errutil.WithPublic(invalidPermissionMessage))
 	ErrInvalidAssignment = errutil.BadRequest("resourcePermissions.invalidAssignment").
 				MustTemplate(invalidAssignmentMessage, errutil.WithPublic(invalidAssignmentMessage))
+	ErrInvalidExpiry = errutil.BadRequest("resourcePermissions.invalidExpiry").
+				MustTemplate(invalidExpiryMessage, errutil.WithPublic(invalidExpiryMessage))
 )
 
 func ErrInvalidParamData(param string, err error) errutil.TemplateData {
@@ -54,3 +59,11 @@ func ErrInvalidAssignmentData(assignment string) errutil.TemplateData {
 		},
 	}
 }
+
+func ErrInvalidExpiryData(expires time.Time) errutil.TemplateData {
+	return errutil.TemplateData{
+		Public: map[string]any{
+			"expires": expires.Format(time.RFC3339),
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package resourcepermissions
 
 import (
+	"time"
+
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 )
@@ -11,6 +13,8 @@ type SetResourcePermissionCommand struct {
 	ResourceID        string
 	ResourceAttribute string
 	Permission        string
+	// Expires is when the permission is removed, nil when it never expires.
+	Expires *time.Time
 }
 
 type SetResourcePermissionsCommand struct {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/slices"
 
@@ -312,6 +313,10 @@ func (s *Service) SetPermissions(
 			return nil, err
 		}
 
+		if cmd.Expires != nil && !cmd.Expires.After(time.Now()) {
+			return nil, ErrInvalidExpiry.Build(ErrInvalidExpiryData(*cmd.Expires))
+		}
+
 		dbCommands = append(dbCommands, SetResourcePermissionsCommand{
 			User:        accesscontrol.User{ID: cmd.UserID},
 			TeamID:      cmd.TeamID,
@@ -322,6 +327,7 @@ func (s *Service) SetPermissions(
 				ResourceID:        resourceID,
 				ResourceAttribute: s.options.ResourceAttribute,
 				Permission:        cmd.Permission,
+				Expires:           cmd.Expires,
 			},
 		})
 	}
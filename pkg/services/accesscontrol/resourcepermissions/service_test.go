@@ -3,6 +3,7 @@ package resourcepermissions
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/userimpl"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 type setUserPermissionTest struct {
@@ -219,6 +221,40 @@ func TestIntegrationService_SetPermissions(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			desc: "should set permissions with an expiry",
+			options: Options{
+				Resource: "dashboards",
+				Assignments: Assignments{
+					Users:        true,
+					Teams:        true,
+					BuiltInRoles: true,
+				},
+				PermissionsToActions: map[string][]string{
+					"View": {"dashboards:read"},
+				},
+			},
+			commands: []accesscontrol.SetResourcePermissionCommand{
+				{UserID: 1, Permission: "View", Expires: util.Pointer(time.Now().Add(8 * time.Hour))},
+				{TeamID: 1, Permission: "View"},
+			},
+		},
+		{
+			desc: "should return error for expiry in the past",
+			options: Options{
+				Resource: "dashboards",
+				Assignments: Assignments{
+					Users: true,
+				},
+				PermissionsToActions: map[string][]string{
+					"View": {"dashboards:read"},
+				},
+			},
+			commands: []accesscontrol.SetResourcePermissionCommand{
+				{UserID: 1, Permission: "View", Expires: util.Pointer(time.Now().Add(-time.Minute))},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -241,7 +277,15 @@ func TestIntegrationService_SetPermissions(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, permissions, len(tt.commands))
+				require.Len(t, permissions, len(tt.commands))
+				for i, cmd := range tt.commands {
+					if cmd.Expires == nil {
+						assert.Nil(t, permissions[i].Expires)
+						continue
+					}
+					require.NotNil(t, permissions[i].Expires)
+					assert.WithinDuration(t, *cmd.Expires, *permissions[i].Expires, time.Second)
+				}
 			}
 		})
 	}
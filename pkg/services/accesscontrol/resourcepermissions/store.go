@@ -46,6 +46,7 @@ type flatResourcePermission struct {
 	IsServiceAccount bool `xorm:"is_service_account"`
 	Created          time.Time
 	Updated          time.Time
+	Expires          *time.Time
 }
 
 func (p *flatResourcePermission) IsManaged(scope string) bool {
@@ -291,6 +292,10 @@ func (s *store) setResourcePermission(
 		return nil, err
 	}
 
+	if err := setPermissionsExpiry(sess, role.ID, scope, cmd.Expires); err != nil {
+		return nil, err
+	}
+
 	permissions, err := s.getPermissions(sess, cmd.Resource, cmd.ResourceID, cmd.ResourceAttribute, role.ID)
 	if err != nil {
 		return nil, err
@@ -415,6 +420,11 @@ func (s *store) getResourcePermissions(sess *db.Session, orgID int64, query GetR
 		args = append(args, a)
 	}
 
+	// Expired permissions are hidden until they are removed.
+	expiry, expiryArgs := accesscontrol.NotExpiredFilter("p.expires")
+	where += " AND " + expiry
+	args = append(args, expiryArgs...)
+
 	initialLength := len(args)
 	userQuery := userSelect + userFrom + where
 	if query.EnforceAccessControl {
@@ -542,6 +552,7 @@ func flatPermissionsToResourcePermission(scope string, permissions []flatResourc
 		BuiltInRole:      first.BuiltInRole,
 		Created:          first.Created,
 		Updated:          first.Updated,
+		Expires:          first.Expires,
 		IsManaged:        first.IsManaged(scope),
 		IsInherited:      first.IsInherited(scope),
 		IsServiceAccount: first.IsServiceAccount,
@@ -763,6 +774,18 @@ func deletePermissions(sess *db.Session, ids []int64) error {
 	return nil
 }
 
+// setPermissionsExpiry sets the expiry of all the permissions of the role on the scope,
+// granting a permission again without an expiry makes it permanent.
+func setPermissionsExpiry(sess *db.Session, roleID int64, scope string, expires *time.Time) error {
+	var err error
+	if expires == nil {
+		_, err = sess.Exec("UPDATE permission SET expires = NULL WHERE role_id = ? AND scope = ?", roleID, scope)
+	} else {
+		_, err = sess.Exec("UPDATE permission SET expires = ? WHERE role_id = ? AND scope = ?", *expires, roleID, scope)
+	}
+	return err
+}
+
 func managedPermission(action, resource string, resourceID, resourceAttribute string) accesscontrol.Permission {
 	return accesscontrol.Permission{
 		Action: action,
@@ -95,6 +95,25 @@ func testIntegrationApiKeyDataAccess(t *testing.T, fn getStore) {
 			key, err := ss.GetApiKeyByName(context.Background(), &query)
 			assert.Nil(t, err)
 			assert.Nil(t, key.Expires)
+			assert.Empty(t, key.Permissions)
+			assert.Empty(t, key.AllowedCIDRs)
+		})
+
+		t.Run("Add a restricted key", func(t *testing.T) {
+			cmd := apikey.AddCommand{
+				OrgID:        1,
+				Name:         "restricted",
+				Key:          "restricted-key",
+				Permissions:  map[string][]string{"dashboards:read": {"folders:uid:ci"}, "folders:read": nil},
+				AllowedCIDRs: []string{"10.0.0.0/8", "192.168.1.10"},
+			}
+			_, err := ss.AddAPIKey(context.Background(), &cmd)
+			require.NoError(t, err)
+
+			key, err := ss.GetAPIKeyByHash(context.Background(), "restricted-key")
+			require.NoError(t, err)
+			assert.Equal(t, cmd.Permissions, key.Permissions)
+			assert.Equal(t, cmd.AllowedCIDRs, key.AllowedCIDRs)
 		})
 
 		t.Run("Add an expiring key", func(t *testing.T) {
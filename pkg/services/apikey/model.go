@@ -27,18 +27,25 @@ type APIKey struct {
 	Expires          *int64       `db:"expires"`
 	ServiceAccountId *int64       `db:"service_account_id"`
 	IsRevoked        *bool        `xorm:"is_revoked" db:"is_revoked"`
+	// Permissions restricts the key to a subset of the service account permissions,
+	// grouped by action. The key has all the service account permissions when empty.
+	Permissions map[string][]string `xorm:"permissions" db:"permissions"`
+	// AllowedCIDRs restricts the client addresses allowed to use the key, any address is allowed when empty.
+	AllowedCIDRs []string `xorm:"allowed_cidrs" db:"allowed_cidrs"`
 }
 
 func (k APIKey) TableName() string { return "api_key" }
 
 // swagger:model AddAPIKeyCommand
 type AddCommand struct {
-	Name             string       `json:"name" binding:"Required"`
-	Role             org.RoleType `json:"role" binding:"Required"`
-	OrgID            int64        `json:"-" xorm:"org_id"`
-	Key              string       `json:"-"`
-	SecondsToLive    int64        `json:"secondsToLive"`
-	ServiceAccountID *int64       `json:"-"`
+	Name             string              `json:"name" binding:"Required"`
+	Role             org.RoleType        `json:"role" binding:"Required"`
+	OrgID            int64               `json:"-" xorm:"org_id"`
+	Key              string              `json:"-"`
+	SecondsToLive    int64               `json:"secondsToLive"`
+	ServiceAccountID *int64              `json:"-"`
+	Permissions      map[string][]string `json:"-"`
+	AllowedCIDRs     []string            `json:"-"`
 }
 
 type GetByNameQuery struct {
@@ -64,6 +64,12 @@ var PathRewriters = []filters.PathRewriter{
 			return matches[1] + "/name" // connector requires a name
 		},
 	},
+	{
+		Pattern: regexp.MustCompile(`(/apis/playlist.grafana.app/v0alpha1/namespaces/[^/]+/)scheduled$`),
+		ReplaceFunc: func(matches []string) string {
+			return matches[1] + "playlists/name/scheduled" // connector requires a name
+		},
+	},
 }
 
 func GetDefaultBuildHandlerChainFunc(builders []APIGroupBuilder) BuildHandlerChainFunc {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/go-jose/go-jose/v3/jwt"
@@ -37,11 +38,31 @@ func (s *AuthService) init() error {
 		return nil
 	}
 
-	if err := s.initClaimExpectations(); err != nil {
-		return err
+	s.issuers = make(map[string]*issuer, len(s.Cfg.JWTAuth.Issuers))
+	for _, settings := range s.Cfg.JWTAuth.Issuers {
+		if settings.Issuer == "" {
+			return fmt.Errorf("%w: %s", ErrIssuerIsNotConfigured, settings.Name)
+		}
+		if _, exists := s.issuers[settings.Issuer]; exists {
+			return fmt.Errorf("%w: %s", ErrIssuerIsConfiguredTwice, settings.Issuer)
+		}
+
+		iss, err := s.newIssuer(settings)
+		if err != nil {
+			return fmt.Errorf("issuer %s: %w", settings.Name, err)
+		}
+		s.issuers[settings.Issuer] = iss
 	}
-	if err := s.initKeySet(); err != nil {
-		return err
+
+	// The [auth.jwt] section may be left without a key source once issuers
+	// are configured, in which case tokens from unknown issuers are rejected.
+	defaultSettings := s.Cfg.JWTAuth.DefaultIssuer()
+	if len(s.issuers) == 0 || !errors.Is(checkKeySetConfiguration(defaultSettings), ErrKeySetIsNotConfigured) {
+		iss, err := s.newIssuer(defaultSettings)
+		if err != nil {
+			return err
+		}
+		s.defaultIssuer = iss
 	}
 
 	return nil
@@ -51,12 +72,55 @@ type AuthService struct {
 	Cfg         *setting.Cfg
 	RemoteCache *remotecache.RemoteCache
 
+	log log.Logger
+	// issuers holds the issuers configured in [auth.jwt.issuer.<name>]
+	// sections, keyed by the value of their iss claim.
+	issuers map[string]*issuer
+	// defaultIssuer is built from the [auth.jwt] section. It verifies the
+	// tokens matching none of the configured issuers.
+	defaultIssuer *issuer
+}
+
+// issuer holds the key set and claim expectations of a trusted issuer.
+type issuer struct {
+	settings         setting.AuthJWTIssuerSettings
 	keySet           keySet
-	log              log.Logger
 	expect           map[string]any
 	expectRegistered jwt.Expected
 }
 
+func (s *AuthService) newIssuer(settings setting.AuthJWTIssuerSettings) (*issuer, error) {
+	iss := &issuer{settings: settings}
+	if err := iss.initClaimExpectations(); err != nil {
+		return nil, err
+	}
+
+	keySet, err := s.newKeySet(settings)
+	if err != nil {
+		return nil, err
+	}
+	iss.keySet = keySet
+
+	return iss, nil
+}
+
+// issuerFor selects the issuer used to verify a token from its unverified
+// iss claim.
+func (s *AuthService) issuerFor(token *jwt.JSONWebToken) (*issuer, error) {
+	var claims jwt.Claims
+	if err := token.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return nil, err
+	}
+
+	if iss, ok := s.issuers[claims.Issuer]; ok {
+		return iss, nil
+	}
+	if s.defaultIssuer == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIssuer, claims.Issuer)
+	}
+	return s.defaultIssuer, nil
+}
+
 // Sanitize JWT base64 strings to remove paddings everywhere
 func sanitizeJWT(jwtToken string) string {
 	// JWT can be compact, JSON flatened or JSON general
@@ -74,7 +138,12 @@ func (s *AuthService) Verify(ctx context.Context, strToken string) (map[string]a
 		return nil, err
 	}
 
-	keys, err := s.keySet.Key(ctx, token.Headers[0].KeyID)
+	iss, err := s.issuerFor(token)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := iss.keySet.Key(ctx, token.Headers[0].KeyID)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +165,7 @@ func (s *AuthService) Verify(ctx context.Context, strToken string) (map[string]a
 
 	s.log.Debug("Validating JSON Web Token claims")
 
-	if err = s.validateClaims(claims); err != nil {
+	if err = iss.validateClaims(claims); err != nil {
 		return nil, err
 	}
 
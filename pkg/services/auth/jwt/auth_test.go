@@ -89,18 +89,7 @@ func TestIntegrationVerifyUsingJWKSetFile(t *testing.T) {
 	configure := func(t *testing.T, cfg *setting.Cfg) {
 		t.Helper()
 
-		file, err := os.CreateTemp(os.TempDir(), "jwk-*.json")
-		require.NoError(t, err)
-		t.Cleanup(func() {
-			if err := os.Remove(file.Name()); err != nil {
-				panic(err)
-			}
-		})
-
-		require.NoError(t, json.NewEncoder(file).Encode(jwksPublic))
-		require.NoError(t, file.Close())
-
-		cfg.JWTAuth.JWKSetFile = file.Name()
+		cfg.JWTAuth.JWKSetFile = writeJWKSetFile(t)
 	}
 
 	scenario(t, "verifies a token signed with a key from the set", func(t *testing.T, sc scenarioContext) {
@@ -330,6 +319,93 @@ func TestIntegrationClaimValidation(t *testing.T) {
 	}, configurePKIXPublicKeyFile)
 }
 
+func TestIntegrationMultipleIssuers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	key := rsaKeys[0]
+
+	configureIssuers := func(t *testing.T, cfg *setting.Cfg) {
+		cfg.JWTAuth.Issuers = []setting.AuthJWTIssuerSettings{
+			{Name: "legacy", Issuer: "https://legacy.example.com", ExpectClaims: "{}", KeyFile: writePKIXPublicKeyFile(t)},
+			{Name: "next", Issuer: "https://next.example.com", Audience: []string{"grafana"}, ExpectClaims: `{"tenant": "acme"}`, JWKSetFile: writeJWKSetFile(t)},
+		}
+	}
+
+	scenario(t, "verifies tokens with the key set of their issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Issuer: "https://legacy.example.com", Subject: subject}, nil))
+		require.NoError(t, err)
+
+		verifiedClaims, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[0], map[string]any{
+			"iss": "https://next.example.com", "sub": subject, "aud": "grafana", "tenant": "acme",
+		}, nil))
+		require.NoError(t, err)
+		assert.Equal(t, subject, verifiedClaims["sub"])
+	}, configureIssuers)
+
+	scenario(t, "rejects tokens signed with the key of another issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, key, map[string]any{
+			"iss": "https://next.example.com", "sub": subject, "aud": "grafana", "tenant": "acme",
+		}, nil))
+		require.Error(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[0], jwt.Claims{Issuer: "https://legacy.example.com", Subject: subject}, nil))
+		require.Error(t, err)
+	}, configureIssuers)
+
+	scenario(t, "validates the audience and claims of the issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[0], map[string]any{
+			"iss": "https://next.example.com", "sub": subject, "aud": "other", "tenant": "acme",
+		}, nil))
+		require.Error(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[0], map[string]any{
+			"iss": "https://next.example.com", "sub": subject, "aud": "grafana", "tenant": "other",
+		}, nil))
+		require.Error(t, err)
+	}, configureIssuers)
+
+	scenario(t, "rejects tokens from unknown issuers", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Issuer: "https://unknown.example.com", Subject: subject}, nil))
+		require.ErrorIs(t, err, ErrUnknownIssuer)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Subject: subject}, nil))
+		require.ErrorIs(t, err, ErrUnknownIssuer)
+	}, configureIssuers)
+
+	scenario(t, "falls back to the [auth.jwt] key set for other issuers", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Issuer: "https://unknown.example.com", Subject: subject}, nil))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[0], jwt.Claims{Issuer: "https://unknown.example.com", Subject: subject}, nil))
+		require.Error(t, err)
+	}, configureIssuers, configurePKIXPublicKeyFile)
+
+	t.Run("should refuse to start with invalid issuers", func(t *testing.T) {
+		_, err := initAuthService(t, func(t *testing.T, cfg *setting.Cfg) {
+			cfg.JWTAuth.Issuers = []setting.AuthJWTIssuerSettings{
+				{Name: "missing", ExpectClaims: "{}", KeyFile: writePKIXPublicKeyFile(t)},
+			}
+		})
+		require.ErrorIs(t, err, ErrIssuerIsNotConfigured)
+
+		_, err = initAuthService(t, func(t *testing.T, cfg *setting.Cfg) {
+			cfg.JWTAuth.Issuers = []setting.AuthJWTIssuerSettings{
+				{Name: "a", Issuer: "https://example.com", ExpectClaims: "{}", KeyFile: writePKIXPublicKeyFile(t)},
+				{Name: "b", Issuer: "https://example.com", ExpectClaims: "{}", JWKSetFile: writeJWKSetFile(t)},
+			}
+		})
+		require.ErrorIs(t, err, ErrIssuerIsConfiguredTwice)
+
+		_, err = initAuthService(t, func(t *testing.T, cfg *setting.Cfg) {
+			cfg.JWTAuth.Issuers = []setting.AuthJWTIssuerSettings{
+				{Name: "a", Issuer: "https://example.com", ExpectClaims: "{}", KeyFile: writePKIXPublicKeyFile(t), JWKSetFile: writeJWKSetFile(t)},
+			}
+		})
+		require.ErrorIs(t, err, ErrKeySetConfigurationAmbiguous)
+	})
+}
+
 func jwkHTTPScenario(t *testing.T, desc string, fn scenarioFunc, cbs ...configureFunc) {
 	t.Helper()
 	t.Run(desc, func(t *testing.T) {
@@ -344,7 +420,7 @@ func jwkHTTPScenario(t *testing.T, desc string, fn scenarioFunc, cbs ...configur
 			cfg.JWTAuth.JWKSetURL = ts.URL
 		}
 		runner := scenarioRunner(func(t *testing.T, sc scenarioContext) {
-			keySet := sc.authJWTSvc.keySet.(*keySetHTTP)
+			keySet := sc.authJWTSvc.defaultIssuer.keySet.(*keySetHTTP)
 			keySet.client = ts.Client()
 			fn(t, sc)
 		}, append([]configureFunc{configure}, cbs...)...)
@@ -377,7 +453,7 @@ func jwkCachingScenario(t *testing.T, desc string, fn cachingScenarioFunc, cbs .
 			cfg.JWTAuth.CacheTTL = time.Hour
 		}
 		runner := scenarioRunner(func(t *testing.T, sc scenarioContext) {
-			keySet := sc.authJWTSvc.keySet.(*keySetHTTP)
+			keySet := sc.authJWTSvc.defaultIssuer.keySet.(*keySetHTTP)
 			keySet.client = ts.Client()
 			fn(t, cachingScenarioContext{scenarioContext: sc, reqCount: &reqCount})
 		}, append([]configureFunc{configure}, cbs...)...)
@@ -446,6 +522,12 @@ func scenarioRunner(fn scenarioFunc, cbs ...configureFunc) func(t *testing.T) {
 func configurePKIXPublicKeyFile(t *testing.T, cfg *setting.Cfg) {
 	t.Helper()
 
+	cfg.JWTAuth.KeyFile = writePKIXPublicKeyFile(t)
+}
+
+func writePKIXPublicKeyFile(t *testing.T) string {
+	t.Helper()
+
 	file, err := os.CreateTemp(os.TempDir(), "public-key-*.pem")
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -463,5 +545,22 @@ func configurePKIXPublicKeyFile(t *testing.T, cfg *setting.Cfg) {
 	}))
 	require.NoError(t, file.Close())
 
-	cfg.JWTAuth.KeyFile = file.Name()
+	return file.Name()
+}
+
+func writeJWKSetFile(t *testing.T) string {
+	t.Helper()
+
+	file, err := os.CreateTemp(os.TempDir(), "jwk-*.json")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := os.Remove(file.Name()); err != nil {
+			panic(err)
+		}
+	})
+
+	require.NoError(t, json.NewEncoder(file).Encode(jwksPublic))
+	require.NoError(t, file.Close())
+
+	return file.Name()
 }
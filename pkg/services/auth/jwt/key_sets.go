@@ -29,6 +29,9 @@ var ErrFailedToParsePemFile = errors.New("failed to parse pem-encoded file")
 var ErrKeySetIsNotConfigured = errors.New("key set for jwt verification is not configured")
 var ErrKeySetConfigurationAmbiguous = errors.New("key set configuration is ambiguous: you should set either key_file, jwk_set_file or jwk_set_url")
 var ErrJWTSetURLMustHaveHTTPSScheme = errors.New("jwt_set_url must have https scheme")
+var ErrIssuerIsNotConfigured = errors.New("issuer is not configured for jwt issuer section")
+var ErrIssuerIsConfiguredTwice = errors.New("issuer is configured in more than one jwt issuer section")
+var ErrUnknownIssuer = errors.New("jwt issuer is not trusted")
 
 type keySet interface {
 	Key(ctx context.Context, kid string) ([]jose.JSONWebKey, error)
@@ -47,15 +50,15 @@ type keySetHTTP struct {
 	cacheExpiration time.Duration
 }
 
-func (s *AuthService) checkKeySetConfiguration() error {
+func checkKeySetConfiguration(settings setting.AuthJWTIssuerSettings) error {
 	var count int
-	if s.Cfg.JWTAuth.KeyFile != "" {
+	if settings.KeyFile != "" {
 		count++
 	}
-	if s.Cfg.JWTAuth.JWKSetFile != "" {
+	if settings.JWKSetFile != "" {
 		count++
 	}
-	if s.Cfg.JWTAuth.JWKSetURL != "" {
+	if settings.JWKSetURL != "" {
 		count++
 	}
 
@@ -70,17 +73,17 @@ func (s *AuthService) checkKeySetConfiguration() error {
 	return nil
 }
 
-func (s *AuthService) initKeySet() error {
-	if err := s.checkKeySetConfiguration(); err != nil {
-		return err
+func (s *AuthService) newKeySet(settings setting.AuthJWTIssuerSettings) (keySet, error) {
+	if err := checkKeySetConfiguration(settings); err != nil {
+		return nil, err
 	}
 
-	if keyFilePath := s.Cfg.JWTAuth.KeyFile; keyFilePath != "" {
+	if keyFilePath := settings.KeyFile; keyFilePath != "" {
 		// nolint:gosec
 		// We can ignore the gosec G304 warning on this one because `fileName` comes from grafana configuration file
 		file, err := os.Open(keyFilePath)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer func() {
 			if err := file.Close(); err != nil {
@@ -90,50 +93,50 @@ func (s *AuthService) initKeySet() error {
 
 		data, err := io.ReadAll(file)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		block, _ := pem.Decode(data)
 		if block == nil {
-			return ErrFailedToParsePemFile
+			return nil, ErrFailedToParsePemFile
 		}
 
 		var key any
 		switch block.Type {
 		case "PUBLIC KEY":
 			if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "PRIVATE KEY":
 			if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "RSA PUBLIC KEY":
 			if key, err = x509.ParsePKCS1PublicKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "RSA PRIVATE KEY":
 			if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "EC PRIVATE KEY":
 			if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		default:
-			return fmt.Errorf("unknown pem block type %q", block.Type)
+			return nil, fmt.Errorf("unknown pem block type %q", block.Type)
 		}
 
-		s.keySet = &keySetJWKS{
+		return &keySetJWKS{
 			jose.JSONWebKeySet{
-				Keys: []jose.JSONWebKey{{Key: key, KeyID: s.Cfg.JWTAuth.KeyID}},
+				Keys: []jose.JSONWebKey{{Key: key, KeyID: settings.KeyID}},
 			},
-		}
-	} else if keyFilePath := s.Cfg.JWTAuth.JWKSetFile; keyFilePath != "" {
+		}, nil
+	} else if keyFilePath := settings.JWKSetFile; keyFilePath != "" {
 		// nolint:gosec
 		// We can ignore the gosec G304 warning on this one because `fileName` comes from grafana configuration file
 		file, err := os.Open(keyFilePath)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer func() {
 			if err := file.Close(); err != nil {
@@ -143,26 +146,26 @@ func (s *AuthService) initKeySet() error {
 
 		var jwks jose.JSONWebKeySet
 		if err := json.NewDecoder(file).Decode(&jwks); err != nil {
-			return err
+			return nil, err
 		}
 
-		s.keySet = &keySetJWKS{jwks}
-	} else if urlStr := s.Cfg.JWTAuth.JWKSetURL; urlStr != "" {
+		return &keySetJWKS{jwks}, nil
+	} else if urlStr := settings.JWKSetURL; urlStr != "" {
 		urlParsed, err := url.Parse(urlStr)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if urlParsed.Scheme != "https" && s.Cfg.Env != setting.Dev {
-			return ErrJWTSetURLMustHaveHTTPSScheme
+			return nil, ErrJWTSetURLMustHaveHTTPSScheme
 		}
-		s.keySet = &keySetHTTP{
+		return &keySetHTTP{
 			url: urlStr,
 			log: s.log,
 			client: &http.Client{
 				Transport: &http.Transport{
 					TLSClientConfig: &tls.Config{
 						Renegotiation:      tls.RenegotiateFreelyAsClient,
-						InsecureSkipVerify: s.Cfg.JWTAuth.TlsSkipVerify,
+						InsecureSkipVerify: settings.TlsSkipVerify,
 					},
 					Proxy: http.ProxyFromEnvironment,
 					DialContext: (&net.Dialer{
@@ -177,12 +180,12 @@ func (s *AuthService) initKeySet() error {
 				Timeout: time.Second * 30,
 			},
 			cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", urlStr),
-			cacheExpiration: s.Cfg.JWTAuth.CacheTTL,
+			cacheExpiration: settings.CacheTTL,
 			cache:           s.RemoteCache,
-		}
+		}, nil
 	}
 
-	return nil
+	return nil, ErrKeySetIsNotConfigured
 }
 
 func (ks *keySetJWKS) Key(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
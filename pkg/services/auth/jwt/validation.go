@@ -9,8 +9,8 @@ import (
 	"github.com/go-jose/go-jose/v3/jwt"
 )
 
-func (s *AuthService) initClaimExpectations() error {
-	if err := json.Unmarshal([]byte(s.Cfg.JWTAuth.ExpectClaims), &s.expect); err != nil {
+func (s *issuer) initClaimExpectations() error {
+	if err := json.Unmarshal([]byte(s.settings.ExpectClaims), &s.expect); err != nil {
 		return err
 	}
 
@@ -49,10 +49,19 @@ func (s *AuthService) initClaimExpectations() error {
 		}
 	}
 
+	// The issuer and audience of a named issuer take precedence over the
+	// registered claims listed in its expect_claims.
+	if s.settings.Issuer != "" {
+		s.expectRegistered.Issuer = s.settings.Issuer
+	}
+	if len(s.settings.Audience) > 0 {
+		s.expectRegistered.Audience = s.settings.Audience
+	}
+
 	return nil
 }
 
-func (s *AuthService) validateClaims(claims map[string]any) error {
+func (s *issuer) validateClaims(claims map[string]any) error {
 	var registeredClaims jwt.Claims
 	for key, value := range claims {
 		switch key {
@@ -30,6 +30,8 @@ const (
 	ClientProxy        = "auth.client.proxy"
 	ClientSAML         = "auth.client.saml"
 	ClientPasswordless = "auth.client.passwordless"
+	ClientMFA          = "auth.client.mfa"
+	ClientOAuthDevice  = "auth.client.oauth-device"
 	ClientLDAP         = "ldap"
 	ClientProvisioning = "auth.client.apiserver.provisioning"
 )
@@ -39,6 +41,11 @@ const (
 	MetaKeyAuthModule          = "authModule"
 	MetaKeyIsLogin             = "isLogin"
 	defaultRedirectToCookieKey = "redirect_to"
+
+	// MetaKeyOAuthDeviceServiceAccount is the service account a device login asks for a token of, instead of a session
+	MetaKeyOAuthDeviceServiceAccount = "oauthDeviceServiceAccount"
+	// MetaKeyOAuthDeviceSecondsToLive is how long the service account token of a device login is valid
+	MetaKeyOAuthDeviceSecondsToLive = "oauthDeviceSecondsToLive"
 )
 
 // ClientParams are hints to the auth service about how to handle the identity management
@@ -71,6 +78,9 @@ type ClientParams struct {
 type FetchPermissionsParams struct {
 	// RestrictedActions will restrict the permissions to only these actions
 	RestrictedActions []string
+	// RestrictedPermissions will restrict the permissions to only these actions
+	// and narrow them to the listed scopes, an action without scopes keeps all of its scopes
+	RestrictedPermissions map[string][]string
 	// AllowedActions will be added to the identity permissions
 	AllowedActions []string
 	// Note: Kept for backwards compatibility, use K8s style instead
@@ -243,6 +253,13 @@ func (r *Request) GetMeta(k string) string {
 const (
 	KeyOAuthPKCE  = "pkce"
 	KeyOAuthState = "state"
+
+	KeyOAuthDeviceCode              = "device_code"
+	KeyOAuthUserCode                = "user_code"
+	KeyOAuthVerificationURI         = "verification_uri"
+	KeyOAuthVerificationURIComplete = "verification_uri_complete"
+	KeyOAuthExpiresIn               = "expires_in"
+	KeyOAuthInterval                = "interval"
 )
 
 type Redirect struct {
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -44,6 +45,7 @@ func ProvideRegistration(
 	socialService social.Service, cache *remotecache.RemoteCache,
 	ldapService service.LDAP, settingsProviderService setting.Provider,
 	tracer tracing.Tracer, tempUserService tempuser.Service, notificationService notifications.Service,
+	mfaService mfa.Service,
 ) Registration {
 	logger := log.New("authn.registration")
 
@@ -72,13 +74,22 @@ func ProvideRegistration(
 
 	// if we have password clients configure check if basic auth or form auth is enabled
 	if len(passwordClients) > 0 {
+		// second factors are only verified for password logins when enabled
+		var passwordMFA mfa.Service
+		if cfg.AuthMFA.Enabled {
+			passwordMFA = mfaService
+		}
+
 		passwordClient := clients.ProvidePassword(loginAttempts, tracer, passwordClients...)
 		if cfg.BasicAuthEnabled {
-			authnSvc.RegisterClient(clients.ProvideBasic(passwordClient))
+			authnSvc.RegisterClient(clients.ProvideBasic(passwordClient, passwordMFA))
 		}
 
 		if !cfg.DisableLoginForm {
-			authnSvc.RegisterClient(clients.ProvideForm(passwordClient))
+			authnSvc.RegisterClient(clients.ProvideForm(passwordClient, passwordMFA))
+			if passwordMFA != nil {
+				authnSvc.RegisterClient(clients.ProvideMFA(passwordMFA, loginAttempts))
+			}
 		}
 	}
 
@@ -122,7 +133,13 @@ func ProvideRegistration(
 
 	for name := range socialService.GetOAuthProviders() {
 		clientName := authn.ClientWithPrefix(name)
-		authnSvc.RegisterClient(clients.ProvideOAuth(clientName, cfg, oauthTokenService, socialService, settingsProviderService, features, tracer))
+		oauthClient := clients.ProvideOAuth(clientName, cfg, oauthTokenService, socialService, settingsProviderService, features, tracer)
+		authnSvc.RegisterClient(oauthClient)
+
+		// devices without a browser can log in with the generic oauth provider using the device authorization grant
+		if name == social.GenericOAuthProviderName && cfg.OAuthDeviceLoginEnabled {
+			authnSvc.RegisterClient(clients.ProvideOAuthDevice(oauthClient))
+		}
 	}
 
 	if features.IsEnabledGlobally(featuremgmt.FlagProvisioning) {
@@ -73,6 +73,11 @@ func (s *RBACSync) SyncPermissionsHook(ctx context.Context, ident *authn.Identit
 		}
 		grouped = filtered
 	}
+
+	// Restrict access to a subset of the actions and scopes
+	if restrictions := ident.ClientParams.FetchPermissionsParams.RestrictedPermissions; len(restrictions) > 0 {
+		grouped = accesscontrol.RestrictScopes(grouped, restrictions)
+	}
 	ident.Permissions[ident.OrgID] = grouped
 
 	return nil
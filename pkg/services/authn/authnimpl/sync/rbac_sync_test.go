@@ -79,6 +79,22 @@ func TestRBACSync_FetchPermissions(t *testing.T) {
 			},
 			expectedPermissions: map[string][]string{accesscontrol.ActionUsersRead: {accesscontrol.ScopeUsersAll}},
 		},
+		{
+			name: "restrict permissions from store to scopes",
+			identity: &authn.Identity{
+				ID: "2", Type: claims.TypeServiceAccount, OrgID: 1,
+				ClientParams: authn.ClientParams{
+					SyncPermissions: true,
+					FetchPermissionsParams: authn.FetchPermissionsParams{
+						RestrictedPermissions: map[string][]string{
+							accesscontrol.ActionUsersRead: {"users:id:1"},
+							accesscontrol.ActionTeamsRead: nil,
+						},
+					},
+				},
+			},
+			expectedPermissions: map[string][]string{accesscontrol.ActionUsersRead: {"users:id:1"}},
+		},
 		{
 			name: "fetch roles permissions",
 			identity: &authn.Identity{
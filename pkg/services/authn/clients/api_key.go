@@ -3,6 +3,7 @@ package clients
 import (
 	"context"
 	"errors"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +26,7 @@ var (
 	errAPIKeyExpired     = errutil.Unauthorized("api-key.expired", errutil.WithPublicMessage("Expired API key"))
 	errAPIKeyRevoked     = errutil.Unauthorized("api-key.revoked", errutil.WithPublicMessage("Revoked API key"))
 	errAPIKeyOrgMismatch = errutil.Unauthorized("api-key.organization-mismatch", errutil.WithPublicMessage("API key does not belong to the requested organization"))
+	errAPIKeyAddress     = errutil.Unauthorized("api-key.address-not-allowed", errutil.WithPublicMessage("API key is not allowed from this address"))
 )
 
 var (
@@ -74,6 +76,10 @@ func (s *APIKey) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ide
 		return nil, err
 	}
 
+	if err := validateApiKeyAddress(r, key); err != nil {
+		return nil, err
+	}
+
 	// Set keyID so we can use it in last used hook
 	r.SetMeta(metaKeyID, strconv.FormatInt(key.ID, 10))
 	if !shouldUpdateLastUsedAt(key) {
@@ -230,13 +236,50 @@ func validateApiKey(orgID int64, key *apikey.APIKey) error {
 	return nil
 }
 
+// validateApiKeyAddress checks the address of the peer against the networks the key is restricted to.
+// Forwarding headers are not taken into account since they are set by the client.
+func validateApiKeyAddress(r *authn.Request, key *apikey.APIKey) error {
+	if len(key.AllowedCIDRs) == 0 {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(r.HTTPRequest.RemoteAddr)
+	if err != nil {
+		host = r.HTTPRequest.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errAPIKeyAddress.Errorf("could not parse remote address %q", r.HTTPRequest.RemoteAddr)
+	}
+
+	for _, allowed := range key.AllowedCIDRs {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return nil
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return nil
+		}
+	}
+
+	return errAPIKeyAddress.Errorf("API key is not allowed from %s", ip)
+}
+
 func newServiceAccountIdentity(key *apikey.APIKey) *authn.Identity {
 	return &authn.Identity{
 		ID:              strconv.FormatInt(*key.ServiceAccountId, 10),
 		Type:            claims.TypeServiceAccount,
 		OrgID:           key.OrgID,
 		AuthenticatedBy: login.APIKeyAuthModule,
-		ClientParams:    authn.ClientParams{FetchSyncedUser: true, SyncPermissions: true},
+		ClientParams: authn.ClientParams{
+			FetchSyncedUser: true,
+			SyncPermissions: true,
+			FetchPermissionsParams: authn.FetchPermissionsParams{
+				RestrictedPermissions: key.Permissions,
+			},
+		},
 	}
 }
 
@@ -103,6 +103,49 @@ func TestAPIKey_Authenticate(t *testing.T) {
 			},
 			expectedErr: errAPIKeyOrgMismatch,
 		},
+		{
+			desc: "should restrict permissions for token with permissions",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				RemoteAddr: "10.1.2.3:51234",
+				Header:     map[string][]string{"Authorization": {"Bearer " + secret}},
+			}},
+			expectedKey: &apikey.APIKey{
+				ID:               1,
+				OrgID:            1,
+				Key:              hash,
+				ServiceAccountId: intPtr(1),
+				Permissions:      map[string][]string{"dashboards:read": {"folders:uid:ci"}},
+				AllowedCIDRs:     []string{"192.168.0.1", "10.0.0.0/8"},
+			},
+			expectedIdentity: &authn.Identity{
+				ID:    "1",
+				Type:  claims.TypeServiceAccount,
+				OrgID: 1,
+				ClientParams: authn.ClientParams{
+					FetchSyncedUser: true,
+					SyncPermissions: true,
+					FetchPermissionsParams: authn.FetchPermissionsParams{
+						RestrictedPermissions: map[string][]string{"dashboards:read": {"folders:uid:ci"}},
+					},
+				},
+				AuthenticatedBy: login.APIKeyAuthModule,
+			},
+		},
+		{
+			desc: "should fail for api key used from an address that is not allowed",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				RemoteAddr: "[2001:db8::1]:51234",
+				Header:     map[string][]string{"Authorization": {"Bearer " + secret}, "X-Forwarded-For": {"10.1.2.3"}},
+			}},
+			expectedKey: &apikey.APIKey{
+				ID:               1,
+				OrgID:            1,
+				Key:              hash,
+				ServiceAccountId: intPtr(1),
+				AllowedCIDRs:     []string{"10.0.0.0/8"},
+			},
+			expectedErr: errAPIKeyAddress,
+		},
 	}
 
 	for _, tt := range tests {
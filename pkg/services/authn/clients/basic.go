@@ -5,18 +5,23 @@ import (
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/mfa"
 )
 
 var errDecodingBasicAuthHeader = errutil.BadRequest("basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
 
 var _ authn.ContextAwareClient = new(Basic)
 
-func ProvideBasic(client authn.PasswordClient) *Basic {
-	return &Basic{client}
+// ProvideBasic returns the client for basic auth. If mfaService is set, users with a
+// second factor can't authenticate with basic auth. Administrators that have to enroll
+// a second factor can, so that they can still manage users and automate with basic auth.
+func ProvideBasic(client authn.PasswordClient, mfaService mfa.Service) *Basic {
+	return &Basic{client, mfaService}
 }
 
 type Basic struct {
-	client authn.PasswordClient
+	client     authn.PasswordClient
+	mfaService mfa.Service
 }
 
 func (c *Basic) String() string {
@@ -33,7 +38,19 @@ func (c *Basic) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 		return nil, errDecodingBasicAuthHeader.Errorf("failed to decode basic auth header")
 	}
 
-	return c.client.AuthenticatePassword(ctx, r, username, password)
+	identity, err := c.client.AuthenticatePassword(ctx, r, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	_, required, enroll, err := secondFactorUserID(ctx, c.mfaService, identity)
+	if err != nil {
+		return nil, err
+	}
+	if required && !enroll {
+		return nil, errMFABasicAuth.Errorf("user has a second factor")
+	}
+	return identity, nil
 }
 
 func (c *Basic) IsEnabled() bool {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -12,12 +13,16 @@ var errBadForm = errutil.BadRequest("form-auth.invalid", errutil.WithPublicMessa
 
 var _ authn.Client = new(Form)
 
-func ProvideForm(client authn.PasswordClient) *Form {
-	return &Form{client}
+// ProvideForm returns the client for the login form. If mfaService is set, users with
+// a second factor get a challenge that has to be answered with the MFA client instead
+// of a session.
+func ProvideForm(client authn.PasswordClient, mfaService mfa.Service) *Form {
+	return &Form{client, mfaService}
 }
 
 type Form struct {
-	client authn.PasswordClient
+	client     authn.PasswordClient
+	mfaService mfa.Service
 }
 
 type loginForm struct {
@@ -34,7 +39,46 @@ func (c *Form) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ident
 	if err := web.Bind(r.HTTPRequest, &form); err != nil {
 		return nil, errBadForm.Errorf("failed to parse request: %w", err)
 	}
-	return c.client.AuthenticatePassword(ctx, r, form.Username, form.Password)
+
+	identity, err := c.client.AuthenticatePassword(ctx, r, form.Username, form.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, required, enroll, err := secondFactorUserID(ctx, c.mfaService, identity)
+	if err != nil {
+		return nil, err
+	}
+	if !required {
+		return identity, nil
+	}
+
+	if enroll {
+		// Admins without a second factor enroll an authenticator app before they get a session.
+		challenge, err := c.mfaService.CreateEnrollmentChallenge(ctx, userID, form.Username)
+		if err != nil {
+			return nil, err
+		}
+		mfaErr := errMFARequired.Errorf("user has to enroll a second factor")
+		mfaErr.PublicPayload = map[string]any{
+			"token":              challenge.Token,
+			"factors":            challenge.Factors,
+			"enrollmentRequired": true,
+		}
+		return nil, mfaErr
+	}
+
+	challenge, err := c.mfaService.CreateChallenge(ctx, userID, form.Username)
+	if err != nil {
+		return nil, err
+	}
+	mfaErr := errMFARequired.Errorf("user has a second factor")
+	mfaErr.PublicPayload = map[string]any{
+		"token":    challenge.Token,
+		"factors":  challenge.Factors,
+		"webauthn": challenge.WebAuthn,
+	}
+	return nil, mfaErr
 }
 
 func (c *Form) IsEnabled() bool {
@@ -33,23 +33,29 @@ var (
 )
 
 func ProvideJWT(jwtService auth.JWTVerifierService, orgRoleMapper *connectors.OrgRoleMapper, cfg *setting.Cfg, tracer trace.Tracer) *JWT {
+	orgMappingCfgs := make(map[string]connectors.MappingConfiguration, len(cfg.JWTAuth.Issuers)+1)
+	for _, issuer := range append([]setting.AuthJWTIssuerSettings{cfg.JWTAuth.DefaultIssuer()}, cfg.JWTAuth.Issuers...) {
+		orgMappingCfgs[issuer.Name] = orgRoleMapper.ParseOrgMappingSettings(context.Background(), issuer.OrgMapping, issuer.RoleAttributeStrict)
+	}
+
 	return &JWT{
-		cfg:           cfg,
-		log:           log.New(authn.ClientJWT),
-		jwtService:    jwtService,
-		orgRoleMapper: orgRoleMapper,
-		orgMappingCfg: orgRoleMapper.ParseOrgMappingSettings(context.Background(), cfg.JWTAuth.OrgMapping, cfg.JWTAuth.RoleAttributeStrict),
-		tracer:        tracer,
+		cfg:            cfg,
+		log:            log.New(authn.ClientJWT),
+		jwtService:     jwtService,
+		orgRoleMapper:  orgRoleMapper,
+		orgMappingCfgs: orgMappingCfgs,
+		tracer:         tracer,
 	}
 }
 
 type JWT struct {
 	cfg           *setting.Cfg
 	orgRoleMapper *connectors.OrgRoleMapper
-	orgMappingCfg connectors.MappingConfiguration
-	log           log.Logger
-	jwtService    auth.JWTVerifierService
-	tracer        trace.Tracer
+	// orgMappingCfgs holds the parsed org mapping of each issuer by name.
+	orgMappingCfgs map[string]connectors.MappingConfiguration
+	log            log.Logger
+	jwtService     auth.JWTVerifierService
+	tracer         trace.Tracer
 }
 
 func (s *JWT) Name() string {
@@ -74,6 +80,11 @@ func (s *JWT) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identi
 		return nil, errJWTMissingClaim.Errorf("missing mandatory 'sub' claim in JWT")
 	}
 
+	// The token has been verified with the key set of the issuer named in
+	// its iss claim, so the claim mappings of that issuer apply.
+	iss, _ := claims["iss"].(string)
+	issuer := s.cfg.JWTAuth.IssuerFor(iss)
+
 	id := &authn.Identity{
 		AuthenticatedBy: login.JWTModule,
 		AuthID:          sub,
@@ -82,28 +93,28 @@ func (s *JWT) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identi
 			SyncUser:        true,
 			FetchSyncedUser: true,
 			SyncPermissions: true,
-			SyncOrgRoles:    !s.cfg.JWTAuth.SkipOrgRoleSync,
-			AllowSignUp:     s.cfg.JWTAuth.AutoSignUp,
-			SyncTeams:       s.cfg.JWTAuth.GroupsAttributePath != "",
+			SyncOrgRoles:    !issuer.SkipOrgRoleSync,
+			AllowSignUp:     issuer.AutoSignUp,
+			SyncTeams:       issuer.GroupsAttributePath != "",
 		},
 	}
 
-	if key := s.cfg.JWTAuth.UsernameClaim; key != "" {
+	if key := issuer.UsernameClaim; key != "" {
 		id.Login, _ = claims[key].(string)
 		id.ClientParams.LookUpParams.Login = &id.Login
-	} else if key := s.cfg.JWTAuth.UsernameAttributePath; key != "" {
-		id.Login, err = util.SearchJSONForStringAttr(s.cfg.JWTAuth.UsernameAttributePath, claims)
+	} else if key := issuer.UsernameAttributePath; key != "" {
+		id.Login, err = util.SearchJSONForStringAttr(issuer.UsernameAttributePath, claims)
 		if err != nil {
 			return nil, err
 		}
 		id.ClientParams.LookUpParams.Login = &id.Login
 	}
 
-	if key := s.cfg.JWTAuth.EmailClaim; key != "" {
+	if key := issuer.EmailClaim; key != "" {
 		id.Email, _ = claims[key].(string)
 		id.ClientParams.LookUpParams.Email = &id.Email
-	} else if key := s.cfg.JWTAuth.EmailAttributePath; key != "" {
-		id.Email, err = util.SearchJSONForStringAttr(s.cfg.JWTAuth.EmailAttributePath, claims)
+	} else if key := issuer.EmailAttributePath; key != "" {
+		id.Email, err = util.SearchJSONForStringAttr(issuer.EmailAttributePath, claims)
 		if err != nil {
 			return nil, err
 		}
@@ -114,26 +125,26 @@ func (s *JWT) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identi
 		id.Name = name
 	}
 
-	id.Groups, err = s.extractGroups(claims)
+	id.Groups, err = s.extractGroups(issuer, claims)
 	if err != nil {
 		return nil, err
 	}
 
-	if !s.cfg.JWTAuth.SkipOrgRoleSync {
-		role, grafanaAdmin := s.extractRoleAndAdmin(claims)
+	if !issuer.SkipOrgRoleSync {
+		role, grafanaAdmin := s.extractRoleAndAdmin(issuer, claims)
 
-		if s.cfg.JWTAuth.AllowAssignGrafanaAdmin {
+		if issuer.AllowAssignGrafanaAdmin {
 			id.IsGrafanaAdmin = &grafanaAdmin
 		}
 
-		externalOrgs, err := s.extractOrgs(claims)
+		externalOrgs, err := s.extractOrgs(issuer, claims)
 		if err != nil {
 			s.log.Warn("Failed to extract orgs", "err", err)
 			return nil, err
 		}
 
-		id.OrgRoles = s.orgRoleMapper.MapOrgRoles(s.orgMappingCfg, externalOrgs, role)
-		if s.cfg.JWTAuth.RoleAttributeStrict && len(id.OrgRoles) == 0 {
+		id.OrgRoles = s.orgRoleMapper.MapOrgRoles(s.orgMappingCfgs[issuer.Name], externalOrgs, role)
+		if issuer.RoleAttributeStrict && len(id.OrgRoles) == 0 {
 			return nil, errJWTInvalidRole.Errorf("could not evaluate any valid roles using IdP provided data")
 		}
 	}
@@ -198,12 +209,12 @@ func (s *JWT) Priority() uint {
 
 const roleGrafanaAdmin = "GrafanaAdmin"
 
-func (s *JWT) extractRoleAndAdmin(claims map[string]any) (org.RoleType, bool) {
-	if s.cfg.JWTAuth.RoleAttributePath == "" {
+func (s *JWT) extractRoleAndAdmin(issuer setting.AuthJWTIssuerSettings, claims map[string]any) (org.RoleType, bool) {
+	if issuer.RoleAttributePath == "" {
 		return "", false
 	}
 
-	role, err := util.SearchJSONForStringAttr(s.cfg.JWTAuth.RoleAttributePath, claims)
+	role, err := util.SearchJSONForStringAttr(issuer.RoleAttributePath, claims)
 	if err != nil || role == "" {
 		return "", false
 	}
@@ -214,19 +225,19 @@ func (s *JWT) extractRoleAndAdmin(claims map[string]any) (org.RoleType, bool) {
 	return org.RoleType(role), false
 }
 
-func (s *JWT) extractGroups(claims map[string]any) ([]string, error) {
-	if s.cfg.JWTAuth.GroupsAttributePath == "" {
+func (s *JWT) extractGroups(issuer setting.AuthJWTIssuerSettings, claims map[string]any) ([]string, error) {
+	if issuer.GroupsAttributePath == "" {
 		return []string{}, nil
 	}
 
-	return util.SearchJSONForStringSliceAttr(s.cfg.JWTAuth.GroupsAttributePath, claims)
+	return util.SearchJSONForStringSliceAttr(issuer.GroupsAttributePath, claims)
 }
 
 // This code was copied from the social_base.go file and was adapted to match with the JWT structure
-func (s *JWT) extractOrgs(claims map[string]any) ([]string, error) {
-	if s.cfg.JWTAuth.OrgAttributePath == "" {
+func (s *JWT) extractOrgs(issuer setting.AuthJWTIssuerSettings, claims map[string]any) ([]string, error) {
+	if issuer.OrgAttributePath == "" {
 		return []string{}, nil
 	}
 
-	return util.SearchJSONForStringSliceAttr(s.cfg.JWTAuth.OrgAttributePath, claims)
+	return util.SearchJSONForStringSliceAttr(issuer.OrgAttributePath, claims)
 }
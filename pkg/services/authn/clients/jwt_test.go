@@ -395,6 +395,81 @@ func TestJWTClaimConfig(t *testing.T) {
 	}
 }
 
+func TestJWTIssuerClaimMappings(t *testing.T) {
+	t.Parallel()
+	jwtHeaderName := "X-Forwarded-User"
+
+	cfg := &setting.Cfg{
+		JWTAuth: setting.AuthJWTSettings{
+			Enabled:           true,
+			HeaderName:        jwtHeaderName,
+			EmailClaim:        "email",
+			RoleAttributePath: "role",
+			Issuers: []setting.AuthJWTIssuerSettings{
+				{
+					Name:                "next",
+					Issuer:              "https://next.example.com",
+					UsernameClaim:       "preferred_username",
+					AutoSignUp:          true,
+					RoleAttributePath:   "roles[0]",
+					RoleAttributeStrict: true,
+					OrgAttributePath:    "orgs[]",
+					OrgMapping:          []string{"org1:Org4:Editor"},
+				},
+			},
+		},
+	}
+
+	claims := map[string]any{
+		"sub":                "1234567890",
+		"email":              "eai.doe@cor.po",
+		"preferred_username": "eai-doe",
+		"role":               "Viewer",
+		"roles":              []any{"Admin"},
+		"orgs":               []any{"org1"},
+	}
+
+	authenticate := func(t *testing.T, iss string) *authn.Identity {
+		t.Helper()
+
+		jwtService := &jwt.FakeJWTService{
+			VerifyProvider: func(context.Context, string) (map[string]any, error) {
+				tokenClaims := map[string]any{"iss": iss}
+				for k, v := range claims {
+					tokenClaims[k] = v
+				}
+				return tokenClaims, nil
+			},
+		}
+		jwtClient := ProvideJWT(jwtService, connectors.ProvideOrgRoleMapper(cfg,
+			&orgtest.FakeOrgService{ExpectedOrgs: []*org.OrgDTO{{ID: 4, Name: "Org4"}}}),
+			cfg, tracing.InitializeTracerForTest())
+
+		id, err := jwtClient.Authenticate(context.Background(), &authn.Request{
+			OrgID:       1,
+			HTTPRequest: &http.Request{Header: map[string][]string{jwtHeaderName: {"sample-token"}}},
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("should use the claim mappings of the matching issuer", func(t *testing.T) {
+		id := authenticate(t, "https://next.example.com")
+		assert.Equal(t, "eai-doe", id.Login)
+		assert.Equal(t, "", id.Email)
+		assert.True(t, id.ClientParams.AllowSignUp)
+		assert.Equal(t, map[int64]org.RoleType{4: org.RoleAdmin}, id.OrgRoles)
+	})
+
+	t.Run("should use the [auth.jwt] claim mappings for other issuers", func(t *testing.T) {
+		id := authenticate(t, "https://legacy.example.com")
+		assert.Equal(t, "", id.Login)
+		assert.Equal(t, "eai.doe@cor.po", id.Email)
+		assert.False(t, id.ClientParams.AllowSignUp)
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleViewer}, id.OrgRoles)
+	})
+}
+
 func TestJWTTest(t *testing.T) {
 	t.Parallel()
 	jwtService := &jwt.FakeJWTService{}
@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"context"
+	"strconv"
+
+	claims "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var (
+	errMFARequired             = errutil.Unauthorized("mfa.required", errutil.WithPublicMessage("Second factor required"))
+	errMFABasicAuth            = errutil.Unauthorized("mfa.basic-auth", errutil.WithPublicMessage("Basic authentication is not allowed for users that require a second factor"))
+	errMFATooManyLoginAttempts = errutil.Unauthorized("mfa.invalid.login-attempt", errutil.WithPublicMessage("Login temporarily blocked"))
+	errMFABadRequest           = errutil.BadRequest("mfa.invalid-request", errutil.WithPublicMessage("Bad second factor data"))
+)
+
+var _ authn.Client = new(MFA)
+
+func ProvideMFA(mfaService mfa.Service, loginAttempts loginattempt.Service) *MFA {
+	return &MFA{mfaService, loginAttempts, log.New("authn.mfa")}
+}
+
+// MFA authenticates users with the answer to the second factor challenge that the
+// Form client returns for users with a second factor.
+type MFA struct {
+	mfaService    mfa.Service
+	loginAttempts loginattempt.Service
+	log           log.Logger
+}
+
+func (c *MFA) Name() string {
+	return authn.ClientMFA
+}
+
+func (c *MFA) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	cmd := mfa.VerifyChallengeCommand{}
+	if err := web.Bind(r.HTTPRequest, &cmd); err != nil {
+		return nil, errMFABadRequest.Errorf("failed to parse request: %w", err)
+	}
+
+	challenge, err := c.mfaService.GetChallenge(ctx, cmd.Token)
+	if err != nil {
+		return nil, err
+	}
+	r.SetMeta(authn.MetaKeyUsername, challenge.Login)
+
+	ok, err := c.loginAttempts.Validate(ctx, challenge.Login)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errMFATooManyLoginAttempts.Errorf("too many consecutive incorrect login attempts for user - login for user temporarily blocked")
+	}
+
+	ok, err = c.loginAttempts.ValidateIPAddress(ctx, web.RemoteAddr(r.HTTPRequest))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errMFATooManyLoginAttempts.Errorf("too many consecutive incorrect login attempts for IP address - login for IP address temporarily blocked")
+	}
+
+	verified, err := c.mfaService.VerifyChallenge(ctx, &cmd)
+	if err != nil {
+		if addErr := c.loginAttempts.Add(ctx, challenge.Login, web.RemoteAddr(r.HTTPRequest)); addErr != nil {
+			c.log.FromContext(ctx).Warn("Failed to add login attempt", "error", addErr)
+		}
+		return nil, err
+	}
+
+	r.SetMeta(authn.MetaKeyAuthModule, "grafana")
+
+	return &authn.Identity{
+		ID:              strconv.FormatInt(verified.UserID, 10),
+		Type:            claims.TypeUser,
+		OrgID:           r.OrgID,
+		ClientParams:    authn.ClientParams{FetchSyncedUser: true, SyncPermissions: true},
+		AuthenticatedBy: login.PasswordAuthModule,
+	}, nil
+}
+
+func (c *MFA) IsEnabled() bool {
+	return true
+}
+
+// secondFactorUserID returns the ID of the user if the identity is a Grafana user that
+// logged in with a password and either has a second factor or has to enroll one. Users
+// authenticated by LDAP have to use the second factor of their identity provider.
+func secondFactorUserID(ctx context.Context, mfaService mfa.Service, identity *authn.Identity) (userID int64, required bool, enroll bool, err error) {
+	if mfaService == nil || identity.AuthenticatedBy != login.PasswordAuthModule || !identity.IsIdentityType(claims.TypeUser) {
+		return 0, false, false, nil
+	}
+
+	userID, err = identity.GetInternalID()
+	if err != nil {
+		return 0, false, false, err
+	}
+	enrolled, err := mfaService.IsEnrolled(ctx, userID)
+	if err != nil || enrolled {
+		return userID, enrolled, false, err
+	}
+	enroll, err = mfaService.EnrollmentRequired(ctx, userID)
+	if err != nil {
+		return 0, false, false, err
+	}
+	return userID, enroll, enroll, nil
+}
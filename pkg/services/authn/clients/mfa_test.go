@@ -0,0 +1,165 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	claims "github.com/grafana/authlib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
+)
+
+func TestMFA_Authenticate(t *testing.T) {
+	type testCase struct {
+		desc             string
+		body             string
+		mfaService       *mfatest.FakeService
+		blockLogin       bool
+		expectedErr      error
+		expectedIdentity *authn.Identity
+		expectedAttempt  bool
+	}
+
+	challenge := &mfa.Challenge{Token: "token", UserID: 1, Login: "admin"}
+	tests := []testCase{
+		{
+			desc:       "should return identity for verified challenge",
+			body:       `{"token": "token", "code": "123456"}`,
+			mfaService: &mfatest.FakeService{ExpectedChallenge: challenge},
+			expectedIdentity: &authn.Identity{
+				ID:              "1",
+				Type:            claims.TypeUser,
+				OrgID:           1,
+				ClientParams:    authn.ClientParams{FetchSyncedUser: true, SyncPermissions: true},
+				AuthenticatedBy: login.PasswordAuthModule,
+			},
+		},
+		{
+			desc:        "should fail for missing token",
+			body:        `{"code": "123456"}`,
+			mfaService:  &mfatest.FakeService{ExpectedChallenge: challenge},
+			expectedErr: errMFABadRequest,
+		},
+		{
+			desc:        "should fail for unknown challenge",
+			body:        `{"token": "token", "code": "123456"}`,
+			mfaService:  &mfatest.FakeService{ExpectedErr: mfa.ErrChallengeNotFound.Errorf("not found")},
+			expectedErr: mfa.ErrChallengeNotFound,
+		},
+		{
+			desc:        "should fail when login is blocked",
+			body:        `{"token": "token", "code": "123456"}`,
+			mfaService:  &mfatest.FakeService{ExpectedChallenge: challenge},
+			blockLogin:  true,
+			expectedErr: errMFATooManyLoginAttempts,
+		},
+		{
+			desc:            "should add login attempt for invalid code",
+			body:            `{"token": "token", "code": "000000"}`,
+			mfaService:      &mfatest.FakeService{ExpectedChallenge: challenge, ExpectedVerifyErr: mfa.ErrInvalidCode.Errorf("invalid")},
+			expectedErr:     mfa.ErrInvalidCode,
+			expectedAttempt: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: !tt.blockLogin}
+			c := ProvideMFA(tt.mfaService, loginAttempts)
+
+			r := &authn.Request{OrgID: 1, HTTPRequest: &http.Request{
+				Header: map[string][]string{"Content-Type": {"application/json"}},
+				Body:   io.NopCloser(strings.NewReader(tt.body)),
+			}}
+			identity, err := c.Authenticate(context.Background(), r)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.EqualValues(t, tt.expectedIdentity, identity)
+			assert.Equal(t, tt.expectedAttempt, loginAttempts.AddCalled)
+		})
+	}
+}
+
+func TestForm_AuthenticateWithSecondFactor(t *testing.T) {
+	passwordIdentity := &authn.Identity{ID: "1", Type: claims.TypeUser, AuthenticatedBy: login.PasswordAuthModule}
+	ldapIdentity := &authn.Identity{ID: "2", Type: claims.TypeUser, AuthenticatedBy: login.LDAPAuthModule}
+	challenge := &mfa.Challenge{Token: "token", Factors: []mfa.FactorType{mfa.FactorTOTP}}
+
+	newRequest := func() *authn.Request {
+		return &authn.Request{HTTPRequest: &http.Request{
+			Header: map[string][]string{"Content-Type": {"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"user": "admin", "password": "password"}`)),
+		}}
+	}
+
+	t.Run("should return challenge for user with second factor", func(t *testing.T) {
+		c := ProvideForm(&authntest.FakePasswordClient{ExpectedIdentity: passwordIdentity}, &mfatest.FakeService{ExpectedEnrolled: true, ExpectedChallenge: challenge})
+		identity, err := c.Authenticate(context.Background(), newRequest())
+		require.ErrorIs(t, err, errMFARequired)
+		require.Nil(t, identity)
+
+		var mfaErr errutil.Error
+		require.True(t, errors.As(err, &mfaErr))
+		assert.Equal(t, "token", mfaErr.PublicPayload["token"])
+		assert.Equal(t, challenge.Factors, mfaErr.PublicPayload["factors"])
+	})
+
+	t.Run("should return enrollment challenge for admin without second factor", func(t *testing.T) {
+		c := ProvideForm(&authntest.FakePasswordClient{ExpectedIdentity: passwordIdentity}, &mfatest.FakeService{ExpectedEnrollRequired: true, ExpectedChallenge: challenge})
+		identity, err := c.Authenticate(context.Background(), newRequest())
+		require.ErrorIs(t, err, errMFARequired)
+		require.Nil(t, identity)
+
+		var mfaErr errutil.Error
+		require.True(t, errors.As(err, &mfaErr))
+		assert.Equal(t, "token", mfaErr.PublicPayload["token"])
+		assert.Equal(t, true, mfaErr.PublicPayload["enrollmentRequired"])
+	})
+
+	t.Run("should return identity for user without second factor", func(t *testing.T) {
+		c := ProvideForm(&authntest.FakePasswordClient{ExpectedIdentity: passwordIdentity}, &mfatest.FakeService{})
+		identity, err := c.Authenticate(context.Background(), newRequest())
+		require.NoError(t, err)
+		assert.Equal(t, passwordIdentity, identity)
+	})
+
+	t.Run("should not require second factor for ldap users", func(t *testing.T) {
+		c := ProvideForm(&authntest.FakePasswordClient{ExpectedIdentity: ldapIdentity}, &mfatest.FakeService{ExpectedEnrolled: true, ExpectedChallenge: challenge})
+		identity, err := c.Authenticate(context.Background(), newRequest())
+		require.NoError(t, err)
+		assert.Equal(t, ldapIdentity, identity)
+	})
+}
+
+func TestBasic_AuthenticateWithSecondFactor(t *testing.T) {
+	passwordIdentity := &authn.Identity{ID: "1", Type: claims.TypeUser, AuthenticatedBy: login.PasswordAuthModule}
+	newRequest := func() *authn.Request {
+		return &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{authorizationHeaderName: {encodeBasicAuth("admin", "password")}}}}
+	}
+
+	c := ProvideBasic(authntest.FakePasswordClient{ExpectedIdentity: passwordIdentity}, &mfatest.FakeService{ExpectedEnrolled: true})
+	_, err := c.Authenticate(context.Background(), newRequest())
+	assert.ErrorIs(t, err, errMFABasicAuth)
+
+	// Administrators that have to enroll a second factor can still use basic auth
+	c = ProvideBasic(authntest.FakePasswordClient{ExpectedIdentity: passwordIdentity}, &mfatest.FakeService{ExpectedEnrollRequired: true})
+	identity, err := c.Authenticate(context.Background(), newRequest())
+	require.NoError(t, err)
+	assert.Equal(t, passwordIdentity, identity)
+
+	c = ProvideBasic(authntest.FakePasswordClient{ExpectedIdentity: passwordIdentity}, &mfatest.FakeService{})
+	identity, err = c.Authenticate(context.Background(), newRequest())
+	require.NoError(t, err)
+	assert.Equal(t, passwordIdentity, identity)
+}
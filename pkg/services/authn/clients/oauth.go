@@ -166,6 +166,11 @@ func (c *OAuth) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 	}
 	token.TokenType = "Bearer"
 
+	return c.identityFromToken(ctx, clientCtx, connector, token)
+}
+
+// identityFromToken fetches the user info for the token and builds the identity synced with Grafana.
+func (c *OAuth) identityFromToken(ctx, clientCtx context.Context, connector social.SocialConnector, token *oauth2.Token) (*authn.Identity, error) {
 	userInfo, err := connector.UserInfo(ctx, connector.Client(clientCtx, token), token)
 	if err != nil {
 		var sErr *connectors.SocialError
@@ -0,0 +1,184 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/login/social"
+	"github.com/grafana/grafana/pkg/login/social/connectors"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// Error codes of the device access token response, https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+const (
+	deviceErrAuthorizationPending = "authorization_pending"
+	deviceErrSlowDown             = "slow_down"
+	deviceErrAccessDenied         = "access_denied"
+	deviceErrExpiredToken         = "expired_token"
+)
+
+var (
+	errOAuthDeviceNotSupported = errutil.BadRequest("auth.oauth.device.not-supported", errutil.WithPublicMessage("Device authorization is not configured for the OAuth provider"))
+	errOAuthDeviceMissingCode  = errutil.BadRequest("auth.oauth.device.missing-code", errutil.WithPublicMessage("Missing device code"))
+	errOAuthDeviceAuthorize    = errutil.Internal("auth.oauth.device.authorize", errutil.WithPublicMessage("Failed to start device authorization with provider"))
+
+	errOAuthDevicePending      = errutil.BadRequest("auth.oauth.device.authorization-pending", errutil.WithPublicMessage("The device has not been approved yet"))
+	errOAuthDeviceSlowDown     = errutil.TooManyRequests("auth.oauth.device.slow-down", errutil.WithPublicMessage("Polling too frequently, increase the interval by 5 seconds"))
+	errOAuthDeviceAccessDenied = errutil.Unauthorized("auth.oauth.device.access-denied", errutil.WithPublicMessage("The device authorization was denied"))
+	errOAuthDeviceExpired      = errutil.Unauthorized("auth.oauth.device.expired", errutil.WithPublicMessage("The device code has expired"))
+)
+
+var _ authn.RedirectClient = new(OAuthDevice)
+
+// ProvideOAuthDevice returns a client logging in devices that can't follow a browser redirect,
+// such as CLI tools and kiosks, with the OAuth 2.0 device authorization grant (RFC 8628).
+// The user info is resolved the same way as for the OAuth client of the provider.
+func ProvideOAuthDevice(oauth *OAuth) *OAuthDevice {
+	return &OAuthDevice{oauth}
+}
+
+type OAuthDevice struct {
+	oauth *OAuth
+}
+
+type deviceConnector interface {
+	social.SocialConnector
+	social.DeviceAuthorizationConnector
+}
+
+type OAuthDeviceForm struct {
+	DeviceCode string `json:"deviceCode"`
+	// ServiceAccountID asks for a token of the service account instead of a session,
+	// the user approving the device must be allowed to create tokens for it.
+	ServiceAccountID int64 `json:"serviceAccountId,omitempty"`
+	SecondsToLive    int64 `json:"secondsToLive,omitempty"`
+}
+
+func (c *OAuthDevice) Name() string {
+	return authn.ClientOAuthDevice
+}
+
+func (c *OAuthDevice) IsEnabled() bool {
+	return c.oauth.IsEnabled()
+}
+
+// RedirectURL starts the device authorization. The returned url is where the user approves the device,
+// the codes to display and the polling interval are returned as extras.
+func (c *OAuthDevice) RedirectURL(ctx context.Context, r *authn.Request) (*authn.Redirect, error) {
+	ctx, span := c.oauth.tracer.Start(ctx, "authn.oauth.device.RedirectURL")
+	defer span.End()
+
+	connector, clientCtx, err := c.connector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := connector.DeviceAuth(clientCtx)
+	if err != nil {
+		if errors.Is(err, connectors.ErrDeviceAuthNotConfigured) {
+			return nil, errOAuthDeviceNotSupported.Errorf("oauth client %s has no device authorization url", c.oauth.providerName)
+		}
+		return nil, errOAuthDeviceAuthorize.Errorf("failed to start device authorization: %w", err)
+	}
+
+	url := auth.VerificationURIComplete
+	if url == "" {
+		url = auth.VerificationURI
+	}
+
+	extra := map[string]string{
+		authn.KeyOAuthDeviceCode:              auth.DeviceCode,
+		authn.KeyOAuthUserCode:                auth.UserCode,
+		authn.KeyOAuthVerificationURI:         auth.VerificationURI,
+		authn.KeyOAuthVerificationURIComplete: auth.VerificationURIComplete,
+		authn.KeyOAuthInterval:                strconv.FormatInt(auth.Interval, 10),
+	}
+	if !auth.Expiry.IsZero() {
+		extra[authn.KeyOAuthExpiresIn] = strconv.FormatInt(int64(time.Until(auth.Expiry).Seconds()), 10)
+	}
+
+	return &authn.Redirect{URL: url, Extra: extra}, nil
+}
+
+// Authenticate exchanges the device code for a token once the user has approved the device.
+func (c *OAuthDevice) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	ctx, span := c.oauth.tracer.Start(ctx, "authn.oauth.device.Authenticate")
+	defer span.End()
+
+	r.SetMeta(authn.MetaKeyAuthModule, c.oauth.moduleName)
+
+	var form OAuthDeviceForm
+	if err := web.Bind(r.HTTPRequest, &form); err != nil {
+		return nil, err
+	}
+	if form.DeviceCode == "" {
+		return nil, errOAuthDeviceMissingCode.Errorf("missing device code")
+	}
+	if form.ServiceAccountID != 0 {
+		r.SetMeta(authn.MetaKeyOAuthDeviceServiceAccount, strconv.FormatInt(form.ServiceAccountID, 10))
+		r.SetMeta(authn.MetaKeyOAuthDeviceSecondsToLive, strconv.FormatInt(form.SecondsToLive, 10))
+	}
+
+	connector, clientCtx, err := c.connector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := connector.DeviceAccessToken(clientCtx, form.DeviceCode)
+	if err != nil {
+		return nil, fromDeviceTokenErr(err)
+	}
+	token.TokenType = "Bearer"
+
+	return c.oauth.identityFromToken(ctx, clientCtx, connector, token)
+}
+
+func (c *OAuthDevice) connector(ctx context.Context) (deviceConnector, context.Context, error) {
+	oauthCfg := c.oauth.socialService.GetOAuthInfoProvider(c.oauth.providerName)
+	if oauthCfg == nil || !oauthCfg.Enabled {
+		return nil, nil, errOAuthClientDisabled.Errorf("oauth client is disabled: %s", c.oauth.providerName)
+	}
+
+	connector, errConnector := c.oauth.socialService.GetConnector(c.oauth.providerName)
+	httpClient, errHTTPClient := c.oauth.socialService.GetOAuthHttpClient(c.oauth.providerName)
+	if errConnector != nil || errHTTPClient != nil {
+		return nil, nil, errOAuthInternal.Errorf("failed to get %s oauth client: %w", c.oauth.name, errors.Join(errConnector, errHTTPClient))
+	}
+
+	device, ok := connector.(deviceConnector)
+	if !ok {
+		return nil, nil, errOAuthDeviceNotSupported.Errorf("oauth client %s does not support device authorization", c.oauth.providerName)
+	}
+
+	return device, context.WithValue(ctx, oauth2.HTTPClient, httpClient), nil
+}
+
+func fromDeviceTokenErr(err error) error {
+	if errors.Is(err, connectors.ErrDeviceAuthNotConfigured) {
+		return errOAuthDeviceNotSupported.Errorf("%w", err)
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return errOAuthTokenExchange.Errorf("failed to exchange device code to token: %w", err)
+	}
+
+	switch retrieveErr.ErrorCode {
+	case deviceErrAuthorizationPending:
+		return errOAuthDevicePending.Errorf("device authorization is pending")
+	case deviceErrSlowDown:
+		return errOAuthDeviceSlowDown.Errorf("device is polling too frequently")
+	case deviceErrAccessDenied:
+		return errOAuthDeviceAccessDenied.Errorf("device authorization was denied")
+	case deviceErrExpiredToken:
+		return errOAuthDeviceExpired.Errorf("device code has expired")
+	default:
+		return errOAuthTokenExchange.Errorf("failed to exchange device code to token: %w", err)
+	}
+}
@@ -0,0 +1,254 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/login/social"
+	"github.com/grafana/grafana/pkg/login/social/connectors"
+	"github.com/grafana/grafana/pkg/login/social/socialtest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/ssosettings/ssosettingstests"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeDeviceIdP is a minimal OAuth provider supporting the device authorization grant.
+type fakeDeviceIdP struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// tokenErr is returned by the token endpoint, the token is issued when empty
+	tokenErr string
+	polls    int
+}
+
+func newFakeDeviceIdP(t *testing.T) *fakeDeviceIdP {
+	t.Helper()
+
+	idp := &fakeDeviceIdP{tokenErr: deviceErrAuthorizationPending}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "grafana", r.PostForm.Get("client_id"))
+		assert.Equal(t, "openid email", r.PostForm.Get("scope"))
+
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"device_code":               "device-code",
+			"user_code":                 "WDJB-MJHT",
+			"verification_uri":          idp.URL + "/activate",
+			"verification_uri_complete": idp.URL + "/activate?user_code=WDJB-MJHT",
+			"expires_in":                600,
+			"interval":                  2,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", r.PostForm.Get("grant_type"))
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "grafana", clientID)
+		assert.Equal(t, "secret", clientSecret)
+
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		idp.polls++
+
+		if r.PostForm.Get("device_code") != "device-code" {
+			writeJSON(t, w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+			return
+		}
+		if idp.tokenErr != "" {
+			writeJSON(t, w, http.StatusBadRequest, map[string]any{"error": idp.tokenErr})
+			return
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"access_token":  "access-token",
+			"refresh_token": "refresh-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"sub":   "alice-id",
+			"email": "alice@example.org",
+			"login": "alice",
+			"name":  "Alice",
+		})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+func (idp *fakeDeviceIdP) respond(tokenErr string) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.tokenErr = tokenErr
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+func newDeviceClient(t *testing.T, idp *fakeDeviceIdP, deviceAuthURL string) *OAuthDevice {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	info := &social.OAuthInfo{
+		Enabled:      true,
+		AllowSignup:  true,
+		ClientId:     "grafana",
+		ClientSecret: "secret",
+		AuthStyle:    "inheader",
+		Scopes:       []string{"openid", "email"},
+		AuthUrl:      idp.URL + "/authorize",
+		TokenUrl:     idp.URL + "/token",
+		ApiUrl:       idp.URL + "/userinfo",
+		Extra:        map[string]string{"device_auth_url": deviceAuthURL},
+	}
+	connector := connectors.NewGenericOAuthProvider(info, cfg, connectors.ProvideOrgRoleMapper(cfg, &orgtest.FakeOrgService{}),
+		ssosettingstests.NewFakeService(), featuremgmt.WithFeatures())
+
+	socialService := &socialtest.FakeSocialService{
+		ExpectedAuthInfoProvider: info,
+		ExpectedConnector:        connector,
+		ExpectedHttpClient:       idp.Client(),
+	}
+	oauth := ProvideOAuth(authn.ClientWithPrefix(social.GenericOAuthProviderName), cfg, nil, socialService,
+		&setting.OSSImpl{Cfg: cfg}, featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+
+	return ProvideOAuthDevice(oauth)
+}
+
+func deviceTokenRequest(deviceCode string) *authn.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/login/device/token", strings.NewReader(`{"deviceCode":"`+deviceCode+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	return &authn.Request{HTTPRequest: req}
+}
+
+func TestOAuthDevice_RedirectURL(t *testing.T) {
+	t.Run("should start device authorization with the provider", func(t *testing.T) {
+		idp := newFakeDeviceIdP(t)
+		c := newDeviceClient(t, idp, idp.URL+"/device")
+
+		redirect, err := c.RedirectURL(context.Background(), &authn.Request{})
+		require.NoError(t, err)
+
+		assert.Equal(t, idp.URL+"/activate?user_code=WDJB-MJHT", redirect.URL)
+		assert.Equal(t, "device-code", redirect.Extra[authn.KeyOAuthDeviceCode])
+		assert.Equal(t, "WDJB-MJHT", redirect.Extra[authn.KeyOAuthUserCode])
+		assert.Equal(t, idp.URL+"/activate", redirect.Extra[authn.KeyOAuthVerificationURI])
+		assert.Equal(t, "2", redirect.Extra[authn.KeyOAuthInterval])
+		assert.NotEmpty(t, redirect.Extra[authn.KeyOAuthExpiresIn])
+	})
+
+	t.Run("should fail when the provider has no device authorization url", func(t *testing.T) {
+		idp := newFakeDeviceIdP(t)
+		c := newDeviceClient(t, idp, "")
+
+		_, err := c.RedirectURL(context.Background(), &authn.Request{})
+		assert.ErrorIs(t, err, errOAuthDeviceNotSupported)
+	})
+}
+
+func TestOAuthDevice_Authenticate(t *testing.T) {
+	t.Run("should return pending until the user approves the device", func(t *testing.T) {
+		idp := newFakeDeviceIdP(t)
+		c := newDeviceClient(t, idp, idp.URL+"/device")
+
+		_, err := c.Authenticate(context.Background(), deviceTokenRequest("device-code"))
+		require.ErrorIs(t, err, errOAuthDevicePending)
+
+		idp.respond("")
+		r := deviceTokenRequest("device-code")
+		identity, err := c.Authenticate(context.Background(), r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "alice", identity.Login)
+		assert.Equal(t, "alice@example.org", identity.Email)
+		assert.Equal(t, "Alice", identity.Name)
+		assert.Equal(t, "alice-id", identity.AuthID)
+		assert.Equal(t, "oauth_generic_oauth", identity.AuthenticatedBy)
+		assert.Equal(t, "oauth_generic_oauth", r.GetMeta(authn.MetaKeyAuthModule))
+		assert.Equal(t, "access-token", identity.OAuthToken.AccessToken)
+		assert.Equal(t, "refresh-token", identity.OAuthToken.RefreshToken)
+		assert.True(t, identity.ClientParams.SyncUser)
+		assert.True(t, identity.ClientParams.AllowSignUp)
+		assert.Equal(t, 2, idp.polls)
+	})
+
+	t.Run("should ask for a service account token when a service account is set", func(t *testing.T) {
+		idp := newFakeDeviceIdP(t)
+		idp.respond("")
+		c := newDeviceClient(t, idp, idp.URL+"/device")
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/login/device/token",
+			strings.NewReader(`{"deviceCode":"device-code","serviceAccountId":3,"secondsToLive":600}`))
+		req.Header.Set("Content-Type", "application/json")
+		r := &authn.Request{HTTPRequest: req}
+
+		_, err := c.Authenticate(context.Background(), r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "3", r.GetMeta(authn.MetaKeyOAuthDeviceServiceAccount))
+		assert.Equal(t, "600", r.GetMeta(authn.MetaKeyOAuthDeviceSecondsToLive))
+	})
+
+	t.Run("should not ask for a service account token by default", func(t *testing.T) {
+		idp := newFakeDeviceIdP(t)
+		idp.respond("")
+		c := newDeviceClient(t, idp, idp.URL+"/device")
+
+		r := deviceTokenRequest("device-code")
+		_, err := c.Authenticate(context.Background(), r)
+		require.NoError(t, err)
+
+		assert.Empty(t, r.GetMeta(authn.MetaKeyOAuthDeviceServiceAccount))
+	})
+
+	errorTests := []struct {
+		desc        string
+		tokenErr    string
+		expectedErr error
+	}{
+		{desc: "should ask the device to slow down", tokenErr: deviceErrSlowDown, expectedErr: errOAuthDeviceSlowDown},
+		{desc: "should fail when the user denies the device", tokenErr: deviceErrAccessDenied, expectedErr: errOAuthDeviceAccessDenied},
+		{desc: "should fail when the device code has expired", tokenErr: deviceErrExpiredToken, expectedErr: errOAuthDeviceExpired},
+		{desc: "should fail on other token errors", tokenErr: "invalid_client", expectedErr: errOAuthTokenExchange},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.desc, func(t *testing.T) {
+			idp := newFakeDeviceIdP(t)
+			idp.respond(tt.tokenErr)
+			c := newDeviceClient(t, idp, idp.URL+"/device")
+
+			_, err := c.Authenticate(context.Background(), deviceTokenRequest("device-code"))
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+
+	t.Run("should fail without a device code", func(t *testing.T) {
+		idp := newFakeDeviceIdP(t)
+		c := newDeviceClient(t, idp, idp.URL+"/device")
+
+		_, err := c.Authenticate(context.Background(), deviceTokenRequest(""))
+		assert.ErrorIs(t, err, errOAuthDeviceMissingCode)
+		assert.Zero(t, idp.polls)
+	})
+}
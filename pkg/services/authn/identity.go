@@ -240,6 +240,12 @@ func (i *Identity) GetPermissions() map[string][]string {
 	return i.Permissions[i.GetOrgID()]
 }
 
+// GetRestrictedPermissions returns the permissions the entity is restricted to by the credentials it authenticated with,
+// grouped by action. It is empty when the entity isn't restricted.
+func (i *Identity) GetRestrictedPermissions() map[string][]string {
+	return i.ClientParams.FetchPermissionsParams.RestrictedPermissions
+}
+
 // GetGlobalPermissions returns the permissions of the active entity that are available across all organizations
 func (i *Identity) GetGlobalPermissions() map[string][]string {
 	if i.Permissions == nil {
@@ -306,6 +312,8 @@ func (i *Identity) SignedInUser() *user.SignedInUser {
 		IDTokenClaims:     i.IDTokenClaims,
 		AccessTokenClaims: i.AccessTokenClaims,
 		FallbackType:      i.Type,
+
+		RestrictedPermissions: i.GetRestrictedPermissions(),
 	}
 
 	if i.IsIdentityType(claims.TypeAPIKey) {
@@ -0,0 +1,166 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/fullstorydev/grpchan/inprocgrpc"
+	authzv1 "github.com/grafana/authlib/authz/proto/v1"
+	"github.com/grafana/authlib/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// restrictedIdentity is implemented by identities restricted to a subset of their permissions
+// by the credentials they authenticated with, such as scoped service account tokens.
+type restrictedIdentity interface {
+	// GetRestrictedPermissions returns the restrictions grouped by action, empty when the identity isn't restricted.
+	GetRestrictedPermissions() map[string][]string
+}
+
+// NewRestrictedClient returns an access client that applies the restrictions of restricted identities
+// on top of the permissions the wrapped client resolves for them. The permissions of the identities are
+// stored without their restrictions, so the service resolving them can't apply the restrictions itself.
+func NewRestrictedClient(client types.AccessClient, service *Service) types.AccessClient {
+	return &restrictedClient{client: client, service: service}
+}
+
+type restrictedClient struct {
+	client  types.AccessClient
+	service *Service
+}
+
+func (c *restrictedClient) Check(ctx context.Context, id types.AuthInfo, req types.CheckRequest) (types.CheckResponse, error) {
+	restrictions := getRestrictions(ctx, id)
+	if len(restrictions) == 0 {
+		return c.client.Check(ctx, id, req)
+	}
+
+	ns, err := types.ParseNamespace(req.Namespace)
+	if err != nil {
+		return types.CheckResponse{Allowed: false}, err
+	}
+	action, err := c.service.validateAction(ctx, req.Group, req.Resource, req.Verb)
+	if err != nil {
+		return types.CheckResponse{Allowed: false}, err
+	}
+	scopes, ok := restrictions[action]
+	if !ok {
+		return types.CheckResponse{Allowed: false}, nil
+	}
+
+	// An action restricted without scopes keeps all the scopes granted to the identity
+	if len(scopes) > 0 {
+		allowed, err := c.service.checkPermission(c.serviceContext(ctx, ns), getRestrictedScopeMap(scopes), &CheckRequest{
+			Namespace:    ns,
+			Action:       action,
+			Group:        req.Group,
+			Resource:     req.Resource,
+			Verb:         req.Verb,
+			Name:         req.Name,
+			ParentFolder: req.Folder,
+		})
+		if err != nil || !allowed {
+			return types.CheckResponse{Allowed: false}, err
+		}
+	}
+
+	return c.client.Check(ctx, id, req)
+}
+
+func (c *restrictedClient) Compile(ctx context.Context, id types.AuthInfo, req types.ListRequest) (types.ItemChecker, error) {
+	restrictions := getRestrictions(ctx, id)
+	if len(restrictions) == 0 {
+		return c.client.Compile(ctx, id, req)
+	}
+
+	ns, err := types.ParseNamespace(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	action, err := c.service.validateAction(ctx, req.Group, req.Resource, req.Verb)
+	if err != nil {
+		return nil, err
+	}
+	scopes, ok := restrictions[action]
+	if !ok {
+		return func(name, folder string) bool { return false }, nil
+	}
+
+	check, err := c.client.Compile(ctx, id, req)
+	if err != nil || len(scopes) == 0 {
+		return check, err
+	}
+
+	res, err := c.service.listPermission(c.serviceContext(ctx, ns), getRestrictedScopeMap(scopes), &ListRequest{
+		Namespace: ns,
+		Action:    action,
+		Group:     req.Group,
+		Resource:  req.Resource,
+		Verb:      req.Verb,
+	})
+	if err != nil {
+		return nil, err
+	}
+	restricted := newRestrictedItemChecker(res)
+
+	return func(name, folder string) bool {
+		return restricted(name, folder) && check(name, folder)
+	}, nil
+}
+
+// serviceContext lets the service read the folder tree as itself, the same as when it serves requests,
+// rather than as the restricted identity.
+func (c *restrictedClient) serviceContext(ctx context.Context, ns types.NamespaceInfo) context.Context {
+	return request.WithNamespace(identity.WithServiceIdentityContext(ctx, ns.OrgID), ns.Value)
+}
+
+// getRestrictions returns the restrictions of the identity. Services called in-process, such as the
+// resource server, rebuild the identity from its ID token and lose the restrictions, so they are read from
+// the identity of the in-process client instead.
+func getRestrictions(ctx context.Context, id types.AuthInfo) map[string][]string {
+	if restricted, ok := id.(restrictedIdentity); ok {
+		return restricted.GetRestrictedPermissions()
+	}
+
+	for clientCtx := inprocgrpc.ClientContext(ctx); clientCtx != nil; clientCtx = inprocgrpc.ClientContext(clientCtx) {
+		requester, err := identity.GetRequester(clientCtx)
+		if err != nil || requester.GetUID() != id.GetUID() {
+			continue
+		}
+		if restricted, ok := requester.(restrictedIdentity); ok {
+			return restricted.GetRestrictedPermissions()
+		}
+		return nil
+	}
+	return nil
+}
+
+func getRestrictedScopeMap(scopes []string) map[string]bool {
+	permissions := make([]accesscontrol.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		kind, attribute, identifier := accesscontrol.SplitScope(scope)
+		permissions = append(permissions, accesscontrol.Permission{Scope: scope, Kind: kind, Attribute: attribute, Identifier: identifier})
+	}
+	return getScopeMap(permissions)
+}
+
+func newRestrictedItemChecker(res *authzv1.ListResponse) types.ItemChecker {
+	if res.GetAll() {
+		return func(name, folder string) bool { return true }
+	}
+
+	items := make(map[string]bool, len(res.GetItems()))
+	for _, item := range res.GetItems() {
+		items[item] = true
+	}
+	folders := make(map[string]bool, len(res.GetFolders()))
+	for _, folder := range res.GetFolders() {
+		folders[folder] = true
+	}
+
+	return func(name, folder string) bool {
+		return items[name] || folders[folder]
+	}
+}
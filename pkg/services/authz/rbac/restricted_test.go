@@ -0,0 +1,96 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/services/authz/rbac/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRestrictedClient_Check(t *testing.T) {
+	type testCase struct {
+		name         string
+		restrictions map[string][]string
+		req          types.CheckRequest
+		expected     bool
+	}
+
+	dashboard := func(name, folder string) types.CheckRequest {
+		return types.CheckRequest{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards", Verb: "get", Name: name, Folder: folder}
+	}
+
+	testCases := []testCase{
+		{
+			name:     "should allow when the identity isn't restricted",
+			req:      dashboard("dash", ""),
+			expected: true,
+		},
+		{
+			name:         "should allow the dashboard the identity is restricted to",
+			restrictions: map[string][]string{"dashboards:read": {"dashboards:uid:dash"}},
+			req:          dashboard("dash", ""),
+			expected:     true,
+		},
+		{
+			name:         "should deny other dashboards",
+			restrictions: map[string][]string{"dashboards:read": {"dashboards:uid:dash"}},
+			req:          dashboard("other", ""),
+		},
+		{
+			name:         "should allow dashboards in the folder the identity is restricted to",
+			restrictions: map[string][]string{"dashboards:read": {"folders:uid:parent"}},
+			req:          dashboard("other", "child"),
+			expected:     true,
+		},
+		{
+			name:         "should allow all dashboards when the action is restricted without scopes",
+			restrictions: map[string][]string{"dashboards:read": {}},
+			req:          dashboard("other", ""),
+			expected:     true,
+		},
+		{
+			name:         "should deny actions missing from the restrictions",
+			restrictions: map[string][]string{"folders:read": {}},
+			req:          dashboard("dash", ""),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestRestrictedClient()
+			id := &user.SignedInUser{UserUID: "sa", IsServiceAccount: true, RestrictedPermissions: tc.restrictions}
+
+			res, err := client.Check(context.Background(), id, tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res.Allowed)
+		})
+	}
+}
+
+func TestRestrictedClient_Compile(t *testing.T) {
+	client := newTestRestrictedClient()
+	id := &user.SignedInUser{
+		UserUID:               "sa",
+		IsServiceAccount:      true,
+		RestrictedPermissions: map[string][]string{"dashboards:read": {"dashboards:uid:dash", "folders:uid:parent"}},
+	}
+
+	check, err := client.Compile(context.Background(), id, types.ListRequest{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards", Verb: "get"})
+	require.NoError(t, err)
+
+	assert.True(t, check("dash", ""))
+	assert.True(t, check("other", "child"))
+	assert.False(t, check("other", ""))
+}
+
+func newTestRestrictedClient() types.AccessClient {
+	s := setupService()
+	s.folderStore = &fakeStore{folders: []store.Folder{{UID: "parent"}, {UID: "child", ParentUID: strPtr("parent")}}}
+	return NewRestrictedClient(types.FixedAccessClient(true), s)
+}
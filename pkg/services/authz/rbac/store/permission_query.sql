@@ -7,13 +7,16 @@ INNER JOIN (
     {{ if .Query.UserID }}
   UNION ALL
   SELECT role_id FROM {{ .Ident .UserRoleTable }} as ur WHERE ur.user_id = {{ .Arg .Query.UserID }} AND (ur.org_id = {{ .Arg .Query.OrgID }} OR ur.org_id = 0)
+    AND (ur.expires IS NULL OR ur.expires > {{ .Arg .Now }})
     {{ end }}
     {{ if .Query.TeamIDs }}
   UNION ALL
   SELECT role_id FROM {{ .Ident .TeamRoleTable }} as tr WHERE tr.team_id IN ({{ .ArgList .Query.TeamIDs }}) AND tr.org_id = {{ .Arg .Query.OrgID }}
+    AND (tr.expires IS NULL OR tr.expires > {{ .Arg .Now }})
   {{ end }}
 ) as roles ON p.role_id = roles.role_id
 WHERE
+  (p.expires IS NULL OR p.expires > {{ .Arg .Now }}) AND
   {{ if .Query.ActionSets }}
   p.action IN ({{ .ArgList .Query.ActionSets }}, {{ .Arg .Query.Action }})
   {{ else }}
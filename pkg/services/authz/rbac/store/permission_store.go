@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/authlib/types"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -45,6 +46,9 @@ type getPermissionsQuery struct {
 	UserRoleTable    string
 	TeamRoleTable    string
 	BuiltinRoleTable string
+
+	// Now is compared with the expiry of role assignments and permissions.
+	Now time.Time
 }
 
 func (r getPermissionsQuery) Validate() error {
@@ -59,6 +63,7 @@ func newGetPermissions(sql *legacysql.LegacyDatabaseHelper, q *PermissionsQuery)
 		UserRoleTable:    sql.Table("user_role"),
 		TeamRoleTable:    sql.Table("team_role"),
 		BuiltinRoleTable: sql.Table("builtin_role"),
+		Now:              time.Now(),
 	}
 }
 
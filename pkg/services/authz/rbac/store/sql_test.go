@@ -3,6 +3,7 @@ package store
 import (
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/grafana/grafana/pkg/storage/legacysql"
 	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
@@ -32,6 +33,7 @@ func TestIdentityQueries(t *testing.T) {
 	getPermissions := func(q *PermissionsQuery) sqltemplate.SQLTemplate {
 		v := newGetPermissions(nodb, q)
 		v.SQLTemplate = mocks.NewTestingSQLTemplate()
+		v.Now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 		return &v
 	}
 
@@ -4,6 +4,8 @@ INNER JOIN (
     OR (br.role = 'Grafana Admin')
   UNION ALL
   SELECT role_id FROM `grafana`.`user_role` as ur WHERE ur.user_id = 1 AND (ur.org_id = 1 OR ur.org_id = 0)
+    AND (ur.expires IS NULL OR ur.expires > '2025-01-01 00:00:00 +0000 UTC')
 ) as roles ON p.role_id = roles.role_id
 WHERE
+  (p.expires IS NULL OR p.expires > '2025-01-01 00:00:00 +0000 UTC') AND
   p.action = 'folders:read'
@@ -3,4 +3,5 @@ INNER JOIN (
   SELECT role_id FROM `grafana`.`builtin_role` as br WHERE (br.role = 'Viewer' AND (br.org_id = 1 OR br.org_id = 0))
 ) as roles ON p.role_id = roles.role_id
 WHERE
+  (p.expires IS NULL OR p.expires > '2025-01-01 00:00:00 +0000 UTC') AND
   p.action = 'folders:read'
@@ -3,6 +3,8 @@ INNER JOIN (
   SELECT role_id FROM "grafana"."builtin_role" as br WHERE (br.role = 'Viewer' AND (br.org_id = 1 OR br.org_id = 0))
   UNION ALL
   SELECT role_id FROM "grafana"."user_role" as ur WHERE ur.user_id = 1 AND (ur.org_id = 1 OR ur.org_id = 0)
+    AND (ur.expires IS NULL OR ur.expires > '2025-01-01 00:00:00 +0000 UTC')
 ) as roles ON p.role_id = roles.role_id
 WHERE
+  (p.expires IS NULL OR p.expires > '2025-01-01 00:00:00 +0000 UTC') AND
   p.action IN ('folders:edit', 'folders:admin', 'folders:create')
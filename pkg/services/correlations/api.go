@@ -18,6 +18,7 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 	authorize := ac.Middleware(s.AccessControl)
 
 	s.RouteRegister.Get("/api/datasources/correlations", middleware.ReqSignedIn, authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationsHandler))
+	s.RouteRegister.Post("/api/datasources/correlations/evaluate", middleware.ReqSignedIn, authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.evaluateConfigHandler))
 
 	s.RouteRegister.Group("/api/datasources/uid/:uid/correlations", func(entities routing.RouteRegister) {
 		entities.Get("/", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationsBySourceUIDHandler))
@@ -27,6 +28,7 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 			entities.Get("/", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationHandler))
 			entities.Delete("/", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.deleteHandler))
 			entities.Patch("/", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+			entities.Post("/evaluate", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.evaluateHandler))
 		})
 	}, middleware.ReqSignedIn)
 }
@@ -352,3 +354,100 @@ type GetCorrelationsResponse struct {
 	// in: body
 	Body []Correlation `json:"body"`
 }
+
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/{correlationUID}/evaluate correlations evaluateCorrelation
+//
+// Applies a correlation to a sample row.
+//
+// Runs the transformations of the correlation on the row and returns the extracted variables and the target with the variables interpolated.
+//
+// Responses:
+// 200: evaluateCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) evaluateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := EvaluateCorrelationCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query := GetCorrelationQuery{
+		UID:       web.Params(c.Req)[":correlationUID"],
+		SourceUID: web.Params(c.Req)[":uid"],
+		OrgId:     c.GetOrgID(),
+	}
+
+	correlation, err := s.getCorrelation(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, ErrCorrelationNotFound) {
+			return response.Error(http.StatusNotFound, "Correlation not found", err)
+		}
+		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
+			return response.Error(http.StatusNotFound, "Source data source not found", err)
+		}
+
+		return response.Error(http.StatusInternalServerError, "Failed to get correlation", err)
+	}
+
+	result, err := EvaluateCorrelationConfig(correlation.Config, cmd.Row)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to evaluate correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters evaluateCorrelation
+type EvaluateCorrelationParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in: body
+	// required:true
+	Body EvaluateCorrelationCommand `json:"body"`
+}
+
+// swagger:route POST /datasources/correlations/evaluate correlations evaluateCorrelationConfig
+//
+// Applies a correlation configuration to a sample row.
+//
+// Validates the configuration, for example of a provisioned correlation, and applies it to the row without saving it.
+//
+// Responses:
+// 200: evaluateCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *CorrelationsService) evaluateConfigHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := EvaluateCorrelationConfigCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := EvaluateCorrelationConfig(cmd.Config, cmd.Row)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to evaluate correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters evaluateCorrelationConfig
+type EvaluateCorrelationConfigParams struct {
+	// in: body
+	// required:true
+	Body EvaluateCorrelationConfigCommand `json:"body"`
+}
+
+//swagger:response evaluateCorrelationResponse
+type EvaluateCorrelationResponse struct {
+	// in: body
+	Body EvaluateCorrelationResponseBody `json:"body"`
+}
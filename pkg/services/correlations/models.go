@@ -18,6 +18,10 @@ var (
 	ErrInvalidTransformationType     = errors.New("invalid transformation type")
 	ErrTransformationNotNested       = errors.New("transformations must be nested under config")
 	ErrTransformationRegexReqExp     = errors.New("regex transformations require expression")
+	ErrTransformationJSONReqExp      = errors.New("json transformations require expression")
+	ErrTransformationTemplateReqExp  = errors.New("template transformations require expression")
+	ErrTransformationTemplateReqMap  = errors.New("template transformations require mapValue")
+	ErrTransformationInvalidExp      = errors.New("invalid transformation expression")
 	ErrCorrelationsQuotaFailed       = errors.New("error getting correlations quota")
 	ErrCorrelationsQuotaReached      = errors.New("correlations quota reached")
 	ErrInvalidConfigType             = errors.New("correlation contains non default value in config.type")
@@ -37,12 +41,17 @@ const (
 	external CorrelationType = "external"
 )
 
+// Transformation extracts variables from a row for the target of the correlation. The transformations of a
+// correlation run in order, and each one can use the fields of the row and the variables extracted before it.
 type Transformation struct {
-	//Enum: regex,logfmt
-	Type       string `json:"type"`
+	//Enum: regex,logfmt,json,label,template
+	Type string `json:"type"`
+	// Regular expression, JSONPath expression, label name or Go template, depending on the type
 	Expression string `json:"expression,omitempty"`
-	Field      string `json:"field,omitempty"`
-	MapValue   string `json:"mapValue,omitempty"`
+	// Field or variable the transformation reads. Default is the correlation field, or labels for label transformations
+	Field string `json:"field,omitempty"`
+	// Name of the extracted variable. Default is the correlation field, or the label name for label transformations
+	MapValue string `json:"mapValue,omitempty"`
 }
 
 func (t CorrelationType) Validate() error {
@@ -54,10 +63,8 @@ func (t CorrelationType) Validate() error {
 
 func (t Transformations) Validate() error {
 	for _, v := range t {
-		if v.Type != "regex" && v.Type != "logfmt" {
-			return fmt.Errorf("%s: \"%s\"", ErrInvalidTransformationType, t)
-		} else if v.Type == "regex" && len(v.Expression) == 0 {
-			return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
+		if err := v.validate(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -242,9 +249,48 @@ func (c UpdateCorrelationCommand) Validate() error {
 		return ErrUpdateCorrelationEmptyParams
 	}
 
+	if c.Config != nil {
+		if err := Transformations(c.Config.Transformations).Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// EvaluateCorrelationCommand is the command for applying a correlation to a sample row
+// swagger:model
+type EvaluateCorrelationCommand struct {
+	// Values of the fields of the row by field name. Log labels are read from the labels field.
+	// required:true
+	// example: {"message":"level=error traceID=abc123","labels":{"app":"checkout"}}
+	Row map[string]any `json:"row" binding:"Required"`
+}
+
+// EvaluateCorrelationConfigCommand is the command for applying a correlation configuration that is not saved to a sample row
+// swagger:model
+type EvaluateCorrelationConfigCommand struct {
+	// Correlation Configuration
+	Config CorrelationConfig `json:"config" binding:"Required"`
+	// Values of the fields of the row by field name. Log labels are read from the labels field.
+	// required:true
+	Row map[string]any `json:"row" binding:"Required"`
+}
+
+func (c EvaluateCorrelationConfigCommand) Validate() error {
+	return c.Config.Transformations.Validate()
+}
+
+// swagger:model
+type EvaluateCorrelationResponseBody struct {
+	// Fields of the row and variables extracted by the transformations
+	Variables map[string]string `json:"variables"`
+	// Target of the correlation with the variables interpolated
+	Target map[string]any `json:"target"`
+	// Variables used in the target that are neither fields of the row nor extracted by the transformations
+	MissingVariables []string `json:"missingVariables"`
+}
+
 // GetCorrelationQuery is the query to retrieve a single correlation
 type GetCorrelationQuery struct {
 	// UID of the correlation
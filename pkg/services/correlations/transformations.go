@@ -0,0 +1,317 @@
+package correlations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-logfmt/logfmt"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	transformationRegex    = "regex"
+	transformationLogfmt   = "logfmt"
+	transformationJSON     = "json"
+	transformationLabel    = "label"
+	transformationTemplate = "template"
+
+	// defaultLabelsField is the field with the labels of the log lines in data plane logs frames
+	defaultLabelsField = "labels"
+)
+
+// variableRegex matches the ${var}, ${var:format} and $var variable syntaxes of correlation targets
+var variableRegex = regexp.MustCompile(`\$\{([\w.]+)(?::\w+)?\}|\$(\w+)`)
+
+// Explore applies the transformations in the browser as well, so json and template transformations are limited to
+// the syntax it supports: JSONPath expressions made of field names and array indexes, and templates that only
+// reference variables.
+var (
+	jsonPathRegex         = regexp.MustCompile(`^\$(\.[\w-]+|\[\d+\])*$`)
+	templateVariableRegex = regexp.MustCompile(`\{\{\s*\.\w+\s*\}\}`)
+)
+
+func (t Transformation) validate() error {
+	switch t.Type {
+	case transformationRegex:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationRegexReqExp, t.Type)
+		}
+		if _, err := compileTransformationRegex(t.Expression); err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExp, err)
+		}
+	case transformationJSON:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationJSONReqExp, t.Type)
+		}
+		if _, err := parseTransformationJSONPath(t.Expression); err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExp, err)
+		}
+	case transformationTemplate:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationTemplateReqExp, t.Type)
+		}
+		if len(t.MapValue) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationTemplateReqMap, t.Type)
+		}
+		if _, err := parseTransformationTemplate(t.Expression); err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExp, err)
+		}
+	case transformationLogfmt, transformationLabel:
+	default:
+		return fmt.Errorf("%w: \"%s\"", ErrInvalidTransformationType, t.Type)
+	}
+	return nil
+}
+
+// apply runs the transformation on the variables and adds the variables it extracts. The input of the
+// transformation is the variable named by its field, or the variable of the correlation field if it has no field.
+func (t Transformation) apply(variables map[string]any, correlationField string) error {
+	inputName := t.Field
+	if inputName == "" {
+		inputName = correlationField
+		if t.Type == transformationLabel {
+			inputName = defaultLabelsField
+		}
+	}
+	input, ok := variables[inputName]
+	if !ok && t.Type != transformationTemplate {
+		return nil
+	}
+
+	name := t.MapValue
+	if name == "" {
+		name = correlationField
+	}
+
+	switch t.Type {
+	case transformationRegex:
+		re, err := compileTransformationRegex(t.Expression)
+		if err != nil {
+			return err
+		}
+		groups := re.SubexpNames()
+		hasNamedGroups := false
+		for _, g := range groups {
+			hasNamedGroups = hasNamedGroups || g != ""
+		}
+		// like in Explore, every match overwrites the variables of the previous ones
+		for _, match := range re.FindAllStringSubmatch(stringifyVariable(input), -1) {
+			switch {
+			case hasNamedGroups:
+				for i, g := range groups {
+					if g != "" {
+						variables[g] = match[i]
+					}
+				}
+			case len(match) > 1 && match[1] != "":
+				variables[name] = match[1]
+			default:
+				variables[name] = match[0]
+			}
+		}
+	case transformationLogfmt:
+		decoder := logfmt.NewDecoder(strings.NewReader(stringifyVariable(input)))
+		for decoder.ScanRecord() {
+			for decoder.ScanKeyval() {
+				if decoder.Value() == nil {
+					variables[string(decoder.Key())] = "true"
+				} else {
+					variables[string(decoder.Key())] = string(decoder.Value())
+				}
+			}
+		}
+		if err := decoder.Err(); err != nil {
+			return fmt.Errorf("failed to parse logfmt: %w", err)
+		}
+	case transformationJSON:
+		path, err := parseTransformationJSONPath(t.Expression)
+		if err != nil {
+			return err
+		}
+		data := input
+		if s, ok := input.(string); ok {
+			if err := json.Unmarshal([]byte(s), &data); err != nil {
+				// a value that is not JSON has nothing to extract
+				return nil
+			}
+		}
+		results, err := path.FindResults(data)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			// missing values are reported as missing variables of the target
+			return nil
+		}
+		variables[name] = results[0][0].Interface()
+	case transformationLabel:
+		labels := input
+		if s, ok := input.(string); ok {
+			if err := json.Unmarshal([]byte(s), &labels); err != nil {
+				return nil
+			}
+		}
+		labelMap, ok := labels.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if t.Expression == "" {
+			for k, v := range labelMap {
+				variables[k] = v
+			}
+			return nil
+		}
+		if v, ok := labelMap[t.Expression]; ok {
+			if t.MapValue == "" {
+				name = t.Expression
+			}
+			variables[name] = v
+		}
+	case transformationTemplate:
+		tmpl, err := parseTransformationTemplate(t.Expression)
+		if err != nil {
+			return err
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, variables); err != nil {
+			return fmt.Errorf("failed to execute template of variable %s: %w", t.MapValue, err)
+		}
+		variables[t.MapValue] = b.String()
+	}
+	return nil
+}
+
+// Evaluate applies the transformations in order to the fields of a row. Every transformation can use the fields
+// and the variables extracted by the previous transformations. It returns the fields and the extracted variables.
+func (t Transformations) Evaluate(row map[string]any, correlationField string) (map[string]any, error) {
+	variables := make(map[string]any, len(row))
+	for k, v := range row {
+		variables[k] = v
+	}
+	for i, transformation := range t {
+		if err := transformation.apply(variables, correlationField); err != nil {
+			return nil, fmt.Errorf("transformation %d (%s): %w", i+1, transformation.Type, err)
+		}
+	}
+	return variables, nil
+}
+
+// EvaluateCorrelationConfig applies the correlation to a row, as Explore does when it shows the correlation link
+// of the row: it extracts the variables with the transformations and interpolates them in the target.
+func EvaluateCorrelationConfig(config CorrelationConfig, row map[string]any) (EvaluateCorrelationResponseBody, error) {
+	variables, err := config.Transformations.Evaluate(row, config.Field)
+	if err != nil {
+		return EvaluateCorrelationResponseBody{}, err
+	}
+
+	missing := map[string]bool{}
+	target := interpolateTarget(config.Target, func(name string) (string, bool) {
+		value, ok := lookupVariable(variables, row, config.Field, name)
+		// other built-in variables, like $__interval, are interpolated when the target query runs
+		if !ok && !strings.HasPrefix(name, "__") {
+			missing[name] = true
+		}
+		return value, ok
+	})
+
+	result := EvaluateCorrelationResponseBody{
+		Variables:        make(map[string]string, len(variables)),
+		Target:           target.(map[string]any),
+		MissingVariables: make([]string, 0, len(missing)),
+	}
+	for k, v := range variables {
+		result.Variables[k] = stringifyVariable(v)
+	}
+	for name := range missing {
+		result.MissingVariables = append(result.MissingVariables, name)
+	}
+	sort.Strings(result.MissingVariables)
+	return result, nil
+}
+
+// lookupVariable resolves the variables of a target, including the built-in variables of data links
+func lookupVariable(variables map[string]any, row map[string]any, correlationField string, name string) (string, bool) {
+	if v, ok := variables[name]; ok {
+		return stringifyVariable(v), true
+	}
+
+	var v any
+	var ok bool
+	switch {
+	case name == "__value.raw" || name == "__value.text" || name == "__targetField":
+		v, ok = row[correlationField]
+	case strings.HasPrefix(name, "__data.fields."):
+		v, ok = row[strings.TrimPrefix(name, "__data.fields.")]
+	}
+	if !ok {
+		return "", false
+	}
+	return stringifyVariable(v), true
+}
+
+func interpolateTarget(value any, lookup func(name string) (string, bool)) any {
+	switch v := value.(type) {
+	case string:
+		return variableRegex.ReplaceAllStringFunc(v, func(match string) string {
+			groups := variableRegex.FindStringSubmatch(match)
+			if value, ok := lookup(groups[1] + groups[2]); ok {
+				return value
+			}
+			return match
+		})
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = interpolateTarget(item, lookup)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = interpolateTarget(item, lookup)
+		}
+		return result
+	case nil:
+		return map[string]any{}
+	}
+	return value
+}
+
+func stringifyVariable(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// compileTransformationRegex compiles the expression of regex transformations, which Explore runs case-insensitive
+func compileTransformationRegex(expression string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + expression)
+}
+
+func parseTransformationJSONPath(expression string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, errors.New("JSONPath expressions must start with $")
+	}
+	if !jsonPathRegex.MatchString(expression) {
+		return nil, errors.New("JSONPath expressions can only contain field names and array indexes, like $.items[0].id")
+	}
+	path := jsonpath.New("transformation")
+	if err := path.Parse("{" + expression + "}"); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+func parseTransformationTemplate(expression string) (*template.Template, error) {
+	if strings.Contains(templateVariableRegex.ReplaceAllString(expression, ""), "{{") {
+		return nil, errors.New("templates can only reference variables, like {{ .app }}")
+	}
+	return template.New("transformation").Option("missingkey=error").Parse(expression)
+}
@@ -0,0 +1,128 @@
+package correlations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformationsValidate(t *testing.T) {
+	type test struct {
+		name           string
+		transformation Transformation
+		err            error
+	}
+
+	tests := []test{
+		{name: "logfmt", transformation: Transformation{Type: "logfmt"}},
+		{name: "label without expression", transformation: Transformation{Type: "label"}},
+		{name: "regex", transformation: Transformation{Type: "regex", Expression: `traceID=(\w+)`}},
+		{name: "regex without expression", transformation: Transformation{Type: "regex"}, err: ErrTransformationRegexReqExp},
+		{name: "invalid regex", transformation: Transformation{Type: "regex", Expression: "(unclosed"}, err: ErrTransformationInvalidExp},
+		{name: "json", transformation: Transformation{Type: "json", Expression: "$.user.id"}},
+		{name: "json without expression", transformation: Transformation{Type: "json"}, err: ErrTransformationJSONReqExp},
+		{name: "invalid json path", transformation: Transformation{Type: "json", Expression: "user.id"}, err: ErrTransformationInvalidExp},
+		{name: "json path with array index", transformation: Transformation{Type: "json", Expression: "$.user.roles[0]"}},
+		{name: "json path with filter", transformation: Transformation{Type: "json", Expression: "$.items[?(@.id==1)]"}, err: ErrTransformationInvalidExp},
+		{name: "template", transformation: Transformation{Type: "template", Expression: "{{ .app }}-{{ .env }}", MapValue: "service"}},
+		{name: "template without mapValue", transformation: Transformation{Type: "template", Expression: "{{ .app }}"}, err: ErrTransformationTemplateReqMap},
+		{name: "invalid template", transformation: Transformation{Type: "template", Expression: "{{ .app ", MapValue: "service"}, err: ErrTransformationInvalidExp},
+		{name: "template with functions", transformation: Transformation{Type: "template", Expression: `{{ printf "%s" .app }}`, MapValue: "service"}, err: ErrTransformationInvalidExp},
+		{name: "unknown type", transformation: Transformation{Type: "xml"}, err: ErrInvalidTransformationType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Transformations{tc.transformation}.Validate()
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestTransformationsEvaluate(t *testing.T) {
+	row := map[string]any{
+		"message": `level=error msg="payment failed" body="{\"order\":{\"id\":42,\"items\":[\"book\"]}}"`,
+		"labels":  map[string]any{"app": "checkout", "env": "prod"},
+	}
+
+	t.Run("regex extracts the first group into mapValue", func(t *testing.T) {
+		variables, err := Transformations{{Type: "regex", Expression: `level=(\w+)`, MapValue: "level"}}.Evaluate(row, "message")
+		require.NoError(t, err)
+		require.Equal(t, "error", variables["level"])
+	})
+
+	t.Run("regex extracts named groups", func(t *testing.T) {
+		variables, err := Transformations{{Type: "regex", Expression: `LEVEL=(?P<severity>\w+)`}}.Evaluate(row, "message")
+		require.NoError(t, err)
+		require.Equal(t, "error", variables["severity"])
+	})
+
+	t.Run("logfmt extracts every key", func(t *testing.T) {
+		variables, err := Transformations{{Type: "logfmt"}}.Evaluate(row, "message")
+		require.NoError(t, err)
+		require.Equal(t, "payment failed", variables["msg"])
+		require.Equal(t, `{"order":{"id":42,"items":["book"]}}`, variables["body"])
+	})
+
+	t.Run("label copies all labels or a single one", func(t *testing.T) {
+		variables, err := Transformations{{Type: "label"}}.Evaluate(row, "message")
+		require.NoError(t, err)
+		require.Equal(t, "checkout", variables["app"])
+		require.Equal(t, "prod", variables["env"])
+
+		variables, err = Transformations{{Type: "label", Expression: "app", MapValue: "service"}}.Evaluate(row, "message")
+		require.NoError(t, err)
+		require.Equal(t, "checkout", variables["service"])
+		require.NotContains(t, variables, "env")
+	})
+
+	t.Run("later steps use the output of earlier steps", func(t *testing.T) {
+		transformations := Transformations{
+			{Type: "logfmt"},
+			{Type: "json", Field: "body", Expression: "$.order.id", MapValue: "orderId"},
+			{Type: "label", Expression: "app"},
+			{Type: "template", Expression: "{{ .app }}/orders/{{ .orderId }}", MapValue: "path"},
+		}
+		variables, err := transformations.Evaluate(row, "message")
+		require.NoError(t, err)
+		require.Equal(t, "checkout/orders/42", variables["path"])
+	})
+
+	t.Run("template fails with undefined variables", func(t *testing.T) {
+		_, err := Transformations{{Type: "template", Expression: "{{ .missing }}", MapValue: "path"}}.Evaluate(row, "message")
+		require.Error(t, err)
+	})
+}
+
+func TestEvaluateCorrelationConfig(t *testing.T) {
+	config := CorrelationConfig{
+		Field: "message",
+		Target: map[string]any{
+			"expr":   `{app="${app}"} |= "${traceId}" | order="$orderId"`,
+			"step":   "$__interval",
+			"labels": []any{"${__data.fields.level}"},
+		},
+		Transformations: Transformations{
+			{Type: "regex", Expression: `order=(\d+)`, MapValue: "orderId"},
+			{Type: "label", Expression: "app"},
+		},
+	}
+	row := map[string]any{
+		"message": "order=42 failed",
+		"level":   "error",
+		"labels":  map[string]any{"app": "checkout"},
+	}
+
+	result, err := EvaluateCorrelationConfig(config, row)
+	require.NoError(t, err)
+	require.Equal(t, `{app="checkout"} |= "${traceId}" | order="42"`, result.Target["expr"])
+	require.Equal(t, "$__interval", result.Target["step"])
+	require.Equal(t, []any{"error"}, result.Target["labels"])
+	require.Equal(t, []string{"traceId"}, result.MissingVariables)
+	require.Equal(t, "42", result.Variables["orderId"])
+	require.Equal(t, `{"app":"checkout"}`, result.Variables["labels"])
+}
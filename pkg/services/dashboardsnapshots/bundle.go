@@ -0,0 +1,249 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"time"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// BundleFormatVersion is the version of the snapshot bundle format written by ExportBundle
+	BundleFormatVersion = 1
+
+	bundleCipher     = "aes-256-gcm"
+	bundleKDF        = "pbkdf2-sha256"
+	bundleIterations = 600000
+	bundleSaltLength = 16
+)
+
+// SnapshotBundle is a self-contained file with a snapshot, for moving it between Grafana instances.
+// The content is either in Snapshot or, when the bundle is protected by a passphrase, encrypted in EncryptedSnapshot.
+type SnapshotBundle struct {
+	FormatVersion     int                       `json:"formatVersion"`
+	Snapshot          *SnapshotBundleContent    `json:"snapshot,omitempty"`
+	Encryption        *SnapshotBundleEncryption `json:"encryption,omitempty"`
+	EncryptedSnapshot []byte                    `json:"encryptedSnapshot,omitempty"`
+}
+
+// SnapshotBundleContent is the snapshot in a bundle. The dashboard includes the data frames of the panels and the
+// annotations, like the dashboard of every snapshot.
+type SnapshotBundleContent struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	// OriginalURL is the path of the dashboard the snapshot was taken from.
+	OriginalURL string `json:"originalUrl"`
+	// SnapshotURL is the URL of the snapshot in the instance it was exported from.
+	SnapshotURL string           `json:"snapshotUrl"`
+	Created     time.Time        `json:"created"`
+	Expires     time.Time        `json:"expires"`
+	Dashboard   *simplejson.Json `json:"dashboard"`
+}
+
+// SnapshotBundleEncryption describes how the content of a bundle is encrypted with the key derived from its passphrase.
+type SnapshotBundleEncryption struct {
+	Cipher     string `json:"cipher"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+}
+
+// ExportBundleCommand is the command for exporting a snapshot as a bundle
+// swagger:model
+type ExportBundleCommand struct {
+	// Passphrase the bundle is encrypted with. The bundle is not encrypted if it is empty.
+	Passphrase string `json:"passphrase"`
+}
+
+// ImportBundleCommand is the command for importing a snapshot bundle
+// swagger:model
+type ImportBundleCommand struct {
+	// The content of the bundle file.
+	// required:true
+	Bundle SnapshotBundle `json:"bundle"`
+	// Passphrase the bundle is encrypted with.
+	Passphrase string `json:"passphrase"`
+
+	OrgID  int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
+
+// ExportBundle returns the bundle of a snapshot, encrypted with the passphrase if it is not empty
+func ExportBundle(snapshot *DashboardSnapshot, snapshotURL string, passphrase string) (*SnapshotBundle, error) {
+	if snapshot.External {
+		return nil, ErrBundleExternalSnapshot.Errorf("snapshot %s is stored on an external snapshot server", snapshot.Key)
+	}
+
+	content := &SnapshotBundleContent{
+		Name:        snapshot.Name,
+		Key:         snapshot.Key,
+		OriginalURL: snapshot.Dashboard.GetPath("snapshot", "originalUrl").MustString(),
+		SnapshotURL: snapshotURL,
+		Created:     snapshot.Created,
+		Expires:     snapshot.Expires,
+		Dashboard:   snapshot.Dashboard,
+	}
+
+	bundle := &SnapshotBundle{FormatVersion: BundleFormatVersion}
+	if passphrase == "" {
+		bundle.Snapshot = content
+		return bundle, nil
+	}
+
+	plaintext, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, bundleSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	encryption := &SnapshotBundleEncryption{
+		Cipher:     bundleCipher,
+		KDF:        bundleKDF,
+		Iterations: bundleIterations,
+		Salt:       salt,
+	}
+
+	gcm, err := encryption.newGCM(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	encryption.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(encryption.Nonce); err != nil {
+		return nil, err
+	}
+
+	bundle.Encryption = encryption
+	bundle.EncryptedSnapshot = gcm.Seal(nil, encryption.Nonce, plaintext, nil)
+	return bundle, nil
+}
+
+// ReadBundle returns the snapshot in the bundle, decrypted with the passphrase if the bundle is encrypted
+func ReadBundle(bundle *SnapshotBundle, passphrase string) (*SnapshotBundleContent, error) {
+	if bundle.FormatVersion != BundleFormatVersion {
+		return nil, ErrBundleInvalid.Errorf("unsupported bundle format version %d", bundle.FormatVersion)
+	}
+
+	content := bundle.Snapshot
+	if bundle.Encryption != nil {
+		if passphrase == "" {
+			return nil, ErrBundlePassphrase.Errorf("the bundle is encrypted and no passphrase was given")
+		}
+		if bundle.Encryption.Cipher != bundleCipher || bundle.Encryption.KDF != bundleKDF || bundle.Encryption.Iterations <= 0 {
+			return nil, ErrBundleInvalid.Errorf("unsupported bundle encryption %s with %s", bundle.Encryption.Cipher, bundle.Encryption.KDF)
+		}
+		// bundles are uploaded by users, more iterations than written by ExportBundle would only burn CPU
+		if bundle.Encryption.Iterations > bundleIterations {
+			return nil, ErrBundleInvalid.Errorf("bundle encryption iterations %d exceed the maximum of %d", bundle.Encryption.Iterations, bundleIterations)
+		}
+
+		gcm, err := bundle.Encryption.newGCM(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		if len(bundle.Encryption.Nonce) != gcm.NonceSize() {
+			return nil, ErrBundleInvalid.Errorf("invalid bundle encryption nonce")
+		}
+
+		plaintext, err := gcm.Open(nil, bundle.Encryption.Nonce, bundle.EncryptedSnapshot, nil)
+		if err != nil {
+			return nil, ErrBundlePassphrase.Errorf("failed to decrypt bundle: %w", err)
+		}
+
+		content = &SnapshotBundleContent{}
+		if err := json.Unmarshal(plaintext, content); err != nil {
+			return nil, ErrBundleInvalid.Errorf("failed to read bundle: %w", err)
+		}
+	}
+
+	if content == nil || content.Dashboard == nil {
+		return nil, ErrBundleInvalid.Errorf("the bundle has no snapshot")
+	}
+	return content, nil
+}
+
+func (e *SnapshotBundleEncryption) newGCM(passphrase string) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, e.Salt, e.Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ImportBundle creates a snapshot from a bundle. The snapshot keeps its expiry and its original URL, and its key if
+// it is not used by another snapshot of this instance.
+func ImportBundle(ctx context.Context, cmd *ImportBundleCommand, svc Service) (*DashboardSnapshot, error) {
+	content, err := ReadBundle(&cmd.Bundle, cmd.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if !content.Expires.IsZero() && content.Expires.Before(time.Now()) {
+		return nil, ErrBundleExpired.Errorf("the snapshot expired at %s", content.Expires.Format(time.RFC3339))
+	}
+
+	key := content.Key
+	if key != "" {
+		_, err := svc.GetDashboardSnapshot(ctx, &GetDashboardSnapshotQuery{Key: key})
+		switch {
+		case err == nil:
+			key = ""
+		case !errors.Is(err, ErrBaseNotFound):
+			return nil, err
+		}
+	}
+	if key == "" {
+		if key, err = util.GetRandomString(32); err != nil {
+			return nil, err
+		}
+	}
+
+	deleteKey, err := util.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := content.Dashboard.Map()
+	if err != nil {
+		return nil, ErrBundleInvalid.Errorf("invalid snapshot dashboard: %w", err)
+	}
+	dashboard := &common.Unstructured{}
+	dashboard.SetUnstructuredContent(model)
+	if content.OriginalURL != "" {
+		dashboard.SetNestedField(content.OriginalURL, "snapshot", "originalUrl")
+	}
+
+	name := content.Name
+	if name == "" {
+		name = "Unnamed snapshot"
+	}
+
+	return svc.CreateDashboardSnapshot(ctx, &CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      name,
+			Dashboard: dashboard,
+		},
+		Key:       key,
+		DeleteKey: deleteKey,
+		ExpiresAt: content.Expires,
+		OrgID:     cmd.OrgID,
+		UserID:    cmd.UserID,
+	})
+}
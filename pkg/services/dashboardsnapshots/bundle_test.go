@@ -0,0 +1,155 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func testSnapshot(expires time.Time) *DashboardSnapshot {
+	return &DashboardSnapshot{
+		Name:    "Test Snapshot",
+		Key:     "snapshot-key",
+		OrgID:   1,
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Expires: expires,
+		Dashboard: simplejson.NewFromAny(map[string]any{
+			"uid":      "dash-uid",
+			"snapshot": map[string]any{"originalUrl": "/d/dash-uid"},
+			"panels": []any{
+				map[string]any{"id": 1, "snapshotData": []any{map[string]any{"fields": []any{}}}},
+			},
+			"annotations": map[string]any{"list": []any{map[string]any{"name": "Deploys", "snapshotData": []any{}}}},
+		}),
+	}
+}
+
+// writeAndReadBundle encodes the bundle like the exported file and decodes it like the import request
+func writeAndReadBundle(t *testing.T, bundle *SnapshotBundle) SnapshotBundle {
+	t.Helper()
+
+	b, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	var result SnapshotBundle
+	require.NoError(t, json.Unmarshal(b, &result))
+	return result
+}
+
+func TestSnapshotBundle(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	t.Run("exports and reads a bundle without passphrase", func(t *testing.T) {
+		bundle, err := ExportBundle(testSnapshot(expires), "https://grafana.example.com/dashboard/snapshot/snapshot-key", "")
+		require.NoError(t, err)
+		require.Nil(t, bundle.Encryption)
+
+		read := writeAndReadBundle(t, bundle)
+		content, err := ReadBundle(&read, "")
+		require.NoError(t, err)
+		assert.Equal(t, "Test Snapshot", content.Name)
+		assert.Equal(t, "/d/dash-uid", content.OriginalURL)
+		assert.Equal(t, "https://grafana.example.com/dashboard/snapshot/snapshot-key", content.SnapshotURL)
+		assert.True(t, expires.Equal(content.Expires))
+		assert.Len(t, content.Dashboard.Get("panels").GetIndex(0).Get("snapshotData").MustArray(), 1)
+	})
+
+	t.Run("exports and reads a bundle encrypted with a passphrase", func(t *testing.T) {
+		bundle, err := ExportBundle(testSnapshot(expires), "", "correct horse")
+		require.NoError(t, err)
+		require.Nil(t, bundle.Snapshot)
+		require.NotNil(t, bundle.Encryption)
+		assert.NotContains(t, string(bundle.EncryptedSnapshot), "dash-uid")
+
+		read := writeAndReadBundle(t, bundle)
+		content, err := ReadBundle(&read, "correct horse")
+		require.NoError(t, err)
+		assert.Equal(t, "dash-uid", content.Dashboard.Get("uid").MustString())
+
+		_, err = ReadBundle(&read, "wrong horse")
+		require.ErrorIs(t, err, ErrBundlePassphrase)
+
+		_, err = ReadBundle(&read, "")
+		require.ErrorIs(t, err, ErrBundlePassphrase)
+	})
+
+	t.Run("does not export external snapshots", func(t *testing.T) {
+		snapshot := testSnapshot(expires)
+		snapshot.External = true
+		_, err := ExportBundle(snapshot, "", "")
+		require.ErrorIs(t, err, ErrBundleExternalSnapshot)
+	})
+
+	t.Run("does not read bundles with more iterations than exported", func(t *testing.T) {
+		bundle, err := ExportBundle(testSnapshot(expires), "", "correct horse")
+		require.NoError(t, err)
+		bundle.Encryption.Iterations = bundleIterations + 1
+
+		_, err = ReadBundle(bundle, "correct horse")
+		require.ErrorIs(t, err, ErrBundleInvalid)
+	})
+
+	t.Run("does not read bundles of other format versions", func(t *testing.T) {
+		_, err := ReadBundle(&SnapshotBundle{FormatVersion: 2}, "")
+		require.ErrorIs(t, err, ErrBundleInvalid)
+	})
+}
+
+func TestImportBundle(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	t.Run("keeps the key, expiry and original URL", func(t *testing.T) {
+		bundle, err := ExportBundle(testSnapshot(expires), "", "secret")
+		require.NoError(t, err)
+
+		mockService := &MockService{}
+		mockService.On("GetDashboardSnapshot", mock.Anything, &GetDashboardSnapshotQuery{Key: "snapshot-key"}).
+			Return(nil, ErrBaseNotFound.Errorf("dashboard snapshot not found"))
+		mockService.On("CreateDashboardSnapshot", mock.Anything, mock.Anything).
+			Return(&DashboardSnapshot{}, nil)
+
+		_, err = ImportBundle(context.Background(), &ImportBundleCommand{Bundle: *bundle, Passphrase: "secret", OrgID: 2, UserID: 3}, mockService)
+		require.NoError(t, err)
+
+		cmd := mockService.Calls[1].Arguments.Get(1).(*CreateDashboardSnapshotCommand)
+		assert.Equal(t, "snapshot-key", cmd.Key)
+		assert.NotEmpty(t, cmd.DeleteKey)
+		assert.True(t, expires.Equal(cmd.ExpiresAt))
+		assert.Equal(t, int64(2), cmd.OrgID)
+		assert.Equal(t, int64(3), cmd.UserID)
+		assert.Equal(t, "/d/dash-uid", cmd.Dashboard.GetNestedString("snapshot", "originalUrl"))
+	})
+
+	t.Run("generates a new key when the key is used", func(t *testing.T) {
+		bundle, err := ExportBundle(testSnapshot(expires), "", "")
+		require.NoError(t, err)
+
+		mockService := &MockService{}
+		mockService.On("GetDashboardSnapshot", mock.Anything, mock.Anything).Return(&DashboardSnapshot{}, nil)
+		mockService.On("CreateDashboardSnapshot", mock.Anything, mock.Anything).Return(&DashboardSnapshot{}, nil)
+
+		_, err = ImportBundle(context.Background(), &ImportBundleCommand{Bundle: *bundle}, mockService)
+		require.NoError(t, err)
+
+		cmd := mockService.Calls[1].Arguments.Get(1).(*CreateDashboardSnapshotCommand)
+		assert.NotEqual(t, "snapshot-key", cmd.Key)
+		assert.Len(t, cmd.Key, 32)
+	})
+
+	t.Run("rejects expired snapshots", func(t *testing.T) {
+		bundle, err := ExportBundle(testSnapshot(time.Now().Add(-time.Hour)), "", "")
+		require.NoError(t, err)
+
+		mockService := &MockService{}
+		_, err = ImportBundle(context.Background(), &ImportBundleCommand{Bundle: *bundle}, mockService)
+		require.ErrorIs(t, err, ErrBundleExpired)
+		mockService.AssertNotCalled(t, "CreateDashboardSnapshot", mock.Anything, mock.Anything)
+	})
+}
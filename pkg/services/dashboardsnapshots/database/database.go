@@ -48,7 +48,9 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 	var result *dashboardsnapshots.DashboardSnapshot
 	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
 		var expires = time.Now().Add(time.Hour * 24 * 365 * 50)
-		if cmd.Expires > 0 {
+		if !cmd.ExpiresAt.IsZero() {
+			expires = cmd.ExpiresAt
+		} else if cmd.Expires > 0 {
 			expires = time.Now().Add(time.Second * time.Duration(cmd.Expires))
 		}
 
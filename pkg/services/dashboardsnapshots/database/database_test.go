@@ -199,6 +199,32 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 	})
 }
 
+func TestIntegrationCreateSnapshotWithExpiry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore)
+
+	expiresAt := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Key:       "imported",
+		DeleteKey: "deleteimported",
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Expires: 60,
+		},
+		ExpiresAt: expiresAt,
+		UserID:    1000,
+		OrgID:     1,
+	}
+	_, err := dashStore.CreateDashboardSnapshot(context.Background(), &cmd)
+	require.NoError(t, err)
+
+	result, err := dashStore.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "imported"})
+	require.NoError(t, err)
+	assert.True(t, expiresAt.Equal(result.Expires), "expected %s, got %s", expiresAt, result.Expires)
+}
+
 func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key string, expires int64) *dashboardsnapshots.DashboardSnapshot {
 	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
 		Key:       key,
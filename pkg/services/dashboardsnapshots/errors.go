@@ -5,3 +5,10 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var (
+	ErrBundleInvalid          = errutil.BadRequest("dashboardsnapshots.bundle-invalid", errutil.WithPublicMessage("Invalid snapshot bundle"))
+	ErrBundlePassphrase       = errutil.BadRequest("dashboardsnapshots.bundle-passphrase", errutil.WithPublicMessage("Missing or wrong passphrase for the snapshot bundle"))
+	ErrBundleExpired          = errutil.BadRequest("dashboardsnapshots.bundle-expired", errutil.WithPublicMessage("The snapshot in the bundle has expired"))
+	ErrBundleExternalSnapshot = errutil.BadRequest("dashboardsnapshots.bundle-external", errutil.WithPublicMessage("Snapshots stored on an external snapshot server can't be exported"))
+)
@@ -63,6 +63,9 @@ type CreateDashboardSnapshotCommand struct {
 	// required:false
 	DeleteKey string `json:"deleteKey"`
 
+	// ExpiresAt is the expiry of imported snapshots, it takes precedence over Expires.
+	ExpiresAt time.Time `json:"-"`
+
 	OrgID  int64 `json:"-"`
 	UserID int64 `json:"-"`
 
@@ -28,6 +28,7 @@ type IConnection interface {
 	Add(*ldap.AddRequest) error
 	Del(*ldap.DelRequest) error
 	Search(*ldap.SearchRequest) (*ldap.SearchResult, error)
+	SearchWithPaging(*ldap.SearchRequest, uint32) (*ldap.SearchResult, error)
 	StartTLS(*tls.Config) error
 	Close()
 }
@@ -394,7 +395,7 @@ func (server *Server) users(logins []string) (
 	var entries = make([][]*ldap.Entry, 0, len(Config.SearchBaseDNs))
 
 	for _, base := range Config.SearchBaseDNs {
-		result, err = server.Connection.Search(
+		result, err = server.search(
 			server.getSearchRequest(base, logins),
 		)
 		if err != nil {
@@ -585,15 +586,8 @@ func (server *Server) userBind(path, password string) error {
 func (server *Server) requestMemberOf(entry *ldap.Entry) ([]string, error) {
 	var memberOf []string
 	var config = server.Config
-	var searchBaseDNs []string
 
-	if len(config.GroupSearchBaseDNs) > 0 {
-		searchBaseDNs = config.GroupSearchBaseDNs
-	} else {
-		searchBaseDNs = config.SearchBaseDNs
-	}
-
-	for _, groupSearchBase := range searchBaseDNs {
+	for _, groupSearchBase := range server.groupSearchBaseDNs() {
 		var filterReplace string
 		if config.GroupSearchFilterUserAttribute == "" {
 			filterReplace = getAttribute(config.Attr.Username, entry)
@@ -626,7 +620,7 @@ func (server *Server) requestMemberOf(entry *ldap.Entry) ([]string, error) {
 			Filter:       filter,
 		}
 
-		groupSearchResult, err := server.Connection.Search(&groupSearchReq)
+		groupSearchResult, err := server.search(&groupSearchReq)
 		if err != nil {
 			return nil, err
 		}
@@ -679,7 +673,7 @@ func (server *Server) getMemberOf(result *ldap.Entry) (
 	if server.Config.GroupSearchFilter == "" {
 		memberOf := getArrayAttribute(server.Config.Attr.MemberOf, result)
 
-		return memberOf, nil
+		return server.resolveNestedGroups(result, memberOf)
 	}
 
 	memberOf, err := server.requestMemberOf(result)
@@ -687,5 +681,25 @@ func (server *Server) getMemberOf(result *ldap.Entry) (
 		return nil, err
 	}
 
-	return memberOf, nil
+	return server.resolveNestedGroups(result, memberOf)
+}
+
+// groupSearchBaseDNs returns the base DNs to search groups in,
+// falling back to the user search base DNs
+func (server *Server) groupSearchBaseDNs() []string {
+	if len(server.Config.GroupSearchBaseDNs) > 0 {
+		return server.Config.GroupSearchBaseDNs
+	}
+
+	return server.Config.SearchBaseDNs
+}
+
+// search executes the search request, using RFC 2696 paged results
+// when a page size is configured so that large result sets are not truncated
+func (server *Server) search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	if server.Config.SearchPageSize > 0 {
+		return server.Connection.SearchWithPaging(request, server.Config.SearchPageSize)
+	}
+
+	return server.Connection.Search(request)
 }
@@ -0,0 +1,325 @@
+package ldap
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testLDAPServer is a minimal in-process LDAPv3 server. It supports simple binds
+// and searches with equality, presence and the Active Directory in chain matching rule,
+// as well as RFC 2696 paged results.
+type testLDAPServer struct {
+	t        *testing.T
+	listener net.Listener
+	entries  []*ldap.Entry
+	// sizeLimit is the maximum number of entries returned by a search without paging,
+	// like the MaxPageSize policy of Active Directory
+	sizeLimit int
+
+	mu      sync.Mutex
+	filters []string
+	paged   int
+}
+
+func newTestLDAPServer(t *testing.T, sizeLimit int, entries ...*ldap.Entry) *testLDAPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &testLDAPServer{t: t, listener: listener, entries: entries, sizeLimit: sizeLimit}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go s.serve()
+
+	return s
+}
+
+func (s *testLDAPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *testLDAPServer) searchFilters() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.filters...)
+}
+
+func (s *testLDAPServer) pagedSearches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paged
+}
+
+func (s *testLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testLDAPServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	for {
+		// the client closes the connection without unbinding
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		messageID := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			s.bind(conn, messageID, request)
+		case ldap.ApplicationSearchRequest:
+			var controls []ldap.Control
+			if len(packet.Children) > 2 {
+				for _, child := range packet.Children[2].Children {
+					control, err := ldap.DecodeControl(child)
+					require.NoError(s.t, err)
+					controls = append(controls, control)
+				}
+			}
+			s.search(conn, messageID, request, controls)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			s.t.Logf("test ldap server received unsupported request %d", request.Tag)
+			return
+		}
+	}
+}
+
+func (s *testLDAPServer) bind(conn net.Conn, messageID int64, request *ber.Packet) {
+	dn := request.Children[1].Data.String()
+	password := request.Children[2].Data.String()
+
+	code := uint16(ldap.LDAPResultInvalidCredentials)
+	if entry := s.entry(dn); entry != nil && password != "" && entry.GetEqualFoldAttributeValue("userPassword") == password {
+		code = ldap.LDAPResultSuccess
+	}
+
+	s.send(conn, messageID, ldapResult(ldap.ApplicationBindResponse, code), nil)
+}
+
+func (s *testLDAPServer) search(conn net.Conn, messageID int64, request *ber.Packet, controls []ldap.Control) {
+	base := request.Children[0].Data.String()
+	scope := request.Children[1].Value.(int64)
+	filter := request.Children[6]
+	var attributes []string
+	for _, attr := range request.Children[7].Children {
+		attributes = append(attributes, attr.Data.String())
+	}
+
+	decompiled, err := ldap.DecompileFilter(filter)
+	require.NoError(s.t, err)
+
+	paging, _ := ldap.FindControl(controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+
+	s.mu.Lock()
+	s.filters = append(s.filters, decompiled)
+	if paging != nil {
+		s.paged++
+	}
+	s.mu.Unlock()
+
+	var matches []*ldap.Entry
+	for _, entry := range s.entries {
+		if inScope(entry.DN, base, scope) && s.matches(entry, filter) {
+			matches = append(matches, entry)
+		}
+	}
+
+	code := uint16(ldap.LDAPResultSuccess)
+	var responseControls []ldap.Control
+	switch {
+	case paging != nil:
+		offset := 0
+		if len(paging.Cookie) > 0 {
+			offset, err = strconv.Atoi(string(paging.Cookie))
+			require.NoError(s.t, err)
+		}
+		end := offset + int(paging.PagingSize)
+		if end > len(matches) {
+			end = len(matches)
+		}
+
+		next := ldap.NewControlPaging(paging.PagingSize)
+		if end < len(matches) {
+			next.SetCookie([]byte(strconv.Itoa(end)))
+		}
+		responseControls = append(responseControls, next)
+		matches = matches[offset:end]
+	case s.sizeLimit > 0 && len(matches) > s.sizeLimit:
+		code = ldap.LDAPResultSizeLimitExceeded
+		matches = matches[:s.sizeLimit]
+	}
+
+	for _, entry := range matches {
+		s.send(conn, messageID, searchResultEntry(entry, attributes), nil)
+	}
+	s.send(conn, messageID, ldapResult(ldap.ApplicationSearchResultDone, code), responseControls)
+}
+
+func (s *testLDAPServer) matches(entry *ldap.Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !s.matches(entry, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if s.matches(entry, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !s.matches(entry, filter.Children[0])
+	case ldap.FilterEqualityMatch:
+		return hasValue(entry, filter.Children[0].Data.String(), filter.Children[1].Data.String())
+	case ldap.FilterPresent:
+		attr := filter.Data.String()
+		return strings.EqualFold(attr, "objectClass") || len(entry.GetEqualFoldAttributeValues(attr)) > 0
+	case ldap.FilterExtensibleMatch:
+		var rule, attr, value string
+		for _, child := range filter.Children {
+			switch child.Tag {
+			case 1:
+				rule = child.Data.String()
+			case 2:
+				attr = child.Data.String()
+			case 3:
+				value = child.Data.String()
+			}
+		}
+		require.Equal(s.t, matchingRuleInChainOID, rule, "unsupported matching rule")
+		return s.memberInChain(entry, attr, value, map[string]bool{})
+	default:
+		s.t.Errorf("test ldap server received unsupported filter %d", filter.Tag)
+		return false
+	}
+}
+
+// memberInChain returns true if dn is a member of the group entry, directly or through other groups
+func (s *testLDAPServer) memberInChain(group *ldap.Entry, attr, dn string, visited map[string]bool) bool {
+	if visited[strings.ToLower(group.DN)] {
+		return false
+	}
+	visited[strings.ToLower(group.DN)] = true
+
+	for _, member := range group.GetEqualFoldAttributeValues(attr) {
+		if strings.EqualFold(member, dn) {
+			return true
+		}
+		if nested := s.entry(member); nested != nil && s.memberInChain(nested, attr, dn, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *testLDAPServer) entry(dn string) *ldap.Entry {
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+func (s *testLDAPServer) send(conn net.Conn, messageID int64, op *ber.Packet, controls []ldap.Control) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	envelope.AppendChild(op)
+	if len(controls) > 0 {
+		packet := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		for _, control := range controls {
+			packet.AppendChild(control.Encode())
+		}
+		envelope.AppendChild(packet)
+	}
+
+	_, err := conn.Write(envelope.Bytes())
+	require.NoError(s.t, err)
+}
+
+func ldapResult(tag ber.Tag, code uint16) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+
+	return op
+}
+
+func searchResultEntry(entry *ldap.Entry, attributes []string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, attr := range entry.Attributes {
+		if len(attributes) > 0 && !containsFold(attributes, attr.Name) {
+			continue
+		}
+
+		packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr.Name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range attr.Values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		packet.AppendChild(values)
+		attrs.AppendChild(packet)
+	}
+	op.AppendChild(attrs)
+
+	return op
+}
+
+func inScope(dn, base string, scope int64) bool {
+	dn, base = strings.ToLower(dn), strings.ToLower(base)
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == base
+	case ldap.ScopeSingleLevel:
+		parts := strings.SplitN(dn, ",", 2)
+		return len(parts) == 2 && parts[1] == base
+	default:
+		return dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+func hasValue(entry *ldap.Entry, attr, value string) bool {
+	return containsFold(entry.GetEqualFoldAttributeValues(attr), value)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
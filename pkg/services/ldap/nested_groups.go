@@ -0,0 +1,145 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	// NestedGroupsMatchingRuleInChain resolves nested groups with a single search
+	// using the Active Directory LDAP_MATCHING_RULE_IN_CHAIN matching rule
+	NestedGroupsMatchingRuleInChain = "matching_rule_in_chain"
+	// NestedGroupsIterative resolves nested groups by searching the parents
+	// of the user's groups level by level, for servers without the matching rule
+	NestedGroupsIterative = "iterative"
+
+	matchingRuleInChainOID = "1.2.840.113556.1.4.1941"
+
+	defaultNestedGroupsMemberAttribute = "member"
+	defaultNestedGroupsMaxDepth        = 10
+)
+
+// resolveNestedGroups adds the groups the user is a member of
+// through other groups to the user's direct groups
+func (server *Server) resolveNestedGroups(user *ldap.Entry, memberOf []string) ([]string, error) {
+	switch server.Config.NestedGroups {
+	case NestedGroupsMatchingRuleInChain:
+		return server.nestedGroupsInChain(user, memberOf)
+	case NestedGroupsIterative:
+		return server.nestedGroupsIterative(memberOf)
+	default:
+		return memberOf, nil
+	}
+}
+
+// nestedGroupsInChain lets the server walk the group hierarchy,
+// the search returns every group the user is a direct or indirect member of
+func (server *Server) nestedGroupsInChain(user *ldap.Entry, memberOf []string) ([]string, error) {
+	groups := newGroupSet(memberOf)
+	filter := fmt.Sprintf("(%s:%s:=%s)", server.nestedGroupsMemberAttribute(), matchingRuleInChainOID, ldap.EscapeFilter(user.DN))
+
+	for _, base := range server.groupSearchBaseDNs() {
+		result, err := server.search(server.groupDNSearchRequest(base, filter))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range result.Entries {
+			groups.add(entry.DN)
+		}
+	}
+
+	return groups.values, nil
+}
+
+// nestedGroupsIterative searches the parents of the groups found in the previous level
+// until no new group is found. Groups that were already seen are not searched again,
+// which stops cycles in the group hierarchy.
+func (server *Server) nestedGroupsIterative(memberOf []string) ([]string, error) {
+	groups := newGroupSet(memberOf)
+	maxDepth := server.Config.NestedGroupsMaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultNestedGroupsMaxDepth
+	}
+
+	level := groups.values
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		search := ""
+		for _, group := range level {
+			search += fmt.Sprintf("(%s=%s)", server.nestedGroupsMemberAttribute(), ldap.EscapeFilter(group))
+		}
+		filter := fmt.Sprintf("(|%s)", search)
+
+		var next []string
+		for _, base := range server.groupSearchBaseDNs() {
+			result, err := server.search(server.groupDNSearchRequest(base, filter))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range result.Entries {
+				if groups.add(entry.DN) {
+					next = append(next, entry.DN)
+				}
+			}
+		}
+		level = next
+	}
+
+	if len(level) > 0 {
+		server.log.Warn("Reached the maximum depth while resolving nested LDAP groups", "maxDepth", maxDepth)
+	}
+
+	return groups.values, nil
+}
+
+func (server *Server) nestedGroupsMemberAttribute() string {
+	if server.Config.NestedGroupsMemberAttribute != "" {
+		return server.Config.NestedGroupsMemberAttribute
+	}
+
+	return defaultNestedGroupsMemberAttribute
+}
+
+func (server *Server) groupDNSearchRequest(base, filter string) *ldap.SearchRequest {
+	server.log.Debug("Searching for nested groups", "base", base, "filter", filter)
+
+	return &ldap.SearchRequest{
+		BaseDN:       base,
+		Scope:        ldap.ScopeWholeSubtree,
+		DerefAliases: ldap.NeverDerefAliases,
+		Attributes:   []string{"dn"},
+		Filter:       filter,
+	}
+}
+
+// groupSet keeps group DNs in the order they were found,
+// ignoring duplicates since DNs are case insensitive
+type groupSet struct {
+	values []string
+	seen   map[string]struct{}
+}
+
+func newGroupSet(groups []string) *groupSet {
+	set := &groupSet{seen: make(map[string]struct{}, len(groups))}
+	for _, group := range groups {
+		set.add(group)
+	}
+
+	return set
+}
+
+// add returns false if the group was already in the set
+func (s *groupSet) add(group string) bool {
+	key := strings.ToLower(group)
+	if _, ok := s.seen[key]; ok {
+		return false
+	}
+
+	s.seen[key] = struct{}{}
+	s.values = append(s.values, group)
+
+	return true
+}
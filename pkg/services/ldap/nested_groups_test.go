@@ -0,0 +1,199 @@
+package ldap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	testUsersDN  = "ou=users,dc=grafana,dc=org"
+	testGroupsDN = "ou=groups,dc=grafana,dc=org"
+)
+
+func testUserDN(name string) string  { return "uid=" + name + "," + testUsersDN }
+func testGroupDN(name string) string { return "cn=" + name + "," + testGroupsDN }
+
+func testDirectory() []*ldap.Entry {
+	return []*ldap.Entry{
+		ldap.NewEntry("cn=admin,dc=grafana,dc=org", map[string][]string{"userPassword": {"grafana"}}),
+		ldap.NewEntry(testUserDN("alice"), map[string][]string{
+			"uid":          {"alice"},
+			"mail":         {"alice@grafana.org"},
+			"userPassword": {"alice"},
+			"memberOf":     {testGroupDN("developers")},
+		}),
+		ldap.NewEntry(testGroupDN("developers"), map[string][]string{"member": {testUserDN("alice")}}),
+		ldap.NewEntry(testGroupDN("engineering"), map[string][]string{"member": {testGroupDN("developers")}}),
+		ldap.NewEntry(testGroupDN("staff"), map[string][]string{"member": {testGroupDN("engineering")}}),
+		// cycle-a and cycle-b are members of each other
+		ldap.NewEntry(testGroupDN("cycle-a"), map[string][]string{"member": {testGroupDN("staff"), testGroupDN("cycle-b")}}),
+		ldap.NewEntry(testGroupDN("cycle-b"), map[string][]string{"member": {testGroupDN("cycle-a")}}),
+		ldap.NewEntry(testGroupDN("unrelated"), map[string][]string{"member": {testUserDN("bob")}}),
+	}
+}
+
+func newTestServer(t *testing.T, ldapServer *testLDAPServer, config func(*ServerConfig)) *Server {
+	t.Helper()
+
+	server := &Server{
+		cfg: &Config{Enabled: true},
+		Config: &ServerConfig{
+			Host:               "127.0.0.1",
+			Port:               ldapServer.port(),
+			BindDN:             "cn=admin,dc=grafana,dc=org",
+			BindPassword:       "grafana",
+			Timeout:            5,
+			SearchFilter:       "(uid=%s)",
+			SearchBaseDNs:      []string{testUsersDN},
+			GroupSearchBaseDNs: []string{testGroupsDN},
+			Attr:               AttributeMap{Username: "uid", Email: "mail", MemberOf: "memberOf"},
+			Groups: []*GroupToOrgRole{
+				{GroupDN: testGroupDN("staff"), OrgId: 1, OrgRole: org.RoleEditor},
+			},
+		},
+		log: log.New("test-logger"),
+	}
+	if config != nil {
+		config(server.Config)
+	}
+
+	require.NoError(t, server.Dial())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServer_NestedGroups(t *testing.T) {
+	allGroups := []string{
+		testGroupDN("developers"),
+		testGroupDN("engineering"),
+		testGroupDN("staff"),
+		testGroupDN("cycle-a"),
+		testGroupDN("cycle-b"),
+	}
+
+	t.Run("should only use direct groups when nested groups are disabled", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 0, testDirectory()...)
+		server := newTestServer(t, ldapServer, nil)
+
+		_, err := server.Login(&login.LoginUserQuery{Username: "alice", Password: "alice"})
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("should resolve nested groups with the matching rule in chain", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 0, testDirectory()...)
+		server := newTestServer(t, ldapServer, func(c *ServerConfig) {
+			c.NestedGroups = NestedGroupsMatchingRuleInChain
+		})
+
+		user, err := server.Login(&login.LoginUserQuery{Username: "alice", Password: "alice"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, allGroups, user.Groups)
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleEditor}, user.OrgRoles)
+		assert.Contains(t, ldapServer.searchFilters(), "(member:1.2.840.113556.1.4.1941:="+testUserDN("alice")+")")
+	})
+
+	t.Run("should resolve nested groups iteratively and stop on cycles", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 0, testDirectory()...)
+		server := newTestServer(t, ldapServer, func(c *ServerConfig) {
+			c.NestedGroups = NestedGroupsIterative
+		})
+
+		user, err := server.Login(&login.LoginUserQuery{Username: "alice", Password: "alice"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, allGroups, user.Groups)
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleEditor}, user.OrgRoles)
+		// one search for the user and one per level of groups: engineering, staff, cycle-a, cycle-b
+		assert.Len(t, ldapServer.searchFilters(), 6)
+	})
+
+	t.Run("should stop iterative resolution at the maximum depth", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 0, testDirectory()...)
+		server := newTestServer(t, ldapServer, func(c *ServerConfig) {
+			c.NestedGroups = NestedGroupsIterative
+			c.NestedGroupsMaxDepth = 1
+		})
+
+		users, err := server.Users([]string{"alice"})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.ElementsMatch(t, []string{testGroupDN("developers"), testGroupDN("engineering")}, users[0].Groups)
+	})
+
+	t.Run("should resolve nested groups of groups found with the group search filter", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 0, testDirectory()...)
+		server := newTestServer(t, ldapServer, func(c *ServerConfig) {
+			c.NestedGroups = NestedGroupsIterative
+			c.GroupSearchFilter = "(member=%s)"
+			c.GroupSearchFilterUserAttribute = "dn"
+		})
+
+		users, err := server.Users([]string{"alice"})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.ElementsMatch(t, allGroups, users[0].Groups)
+	})
+}
+
+func TestServer_PagedSearch(t *testing.T) {
+	entries := testDirectory()
+	logins := make([]string, 0, 25)
+	for i := 0; i < 25; i++ {
+		name := fmt.Sprintf("user%d", i)
+		logins = append(logins, name)
+		entries = append(entries, ldap.NewEntry(testUserDN(name), map[string][]string{"uid": {name}}))
+	}
+	members := make([]string, 0, 15)
+	for i := 0; i < 15; i++ {
+		members = append(members, testGroupDN(fmt.Sprintf("team%d", i)))
+		entries = append(entries, ldap.NewEntry(testGroupDN(fmt.Sprintf("team%d", i)), map[string][]string{"member": {testUserDN("alice")}}))
+	}
+
+	t.Run("should fail when the search exceeds the server size limit", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 10, entries...)
+		server := newTestServer(t, ldapServer, nil)
+		require.NoError(t, server.Bind())
+
+		_, err := server.Users(logins)
+		var ldapErr *ldap.Error
+		require.ErrorAs(t, err, &ldapErr)
+		assert.EqualValues(t, ldap.LDAPResultSizeLimitExceeded, ldapErr.ResultCode)
+	})
+
+	t.Run("should return all users with paged results", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 10, entries...)
+		server := newTestServer(t, ldapServer, func(c *ServerConfig) {
+			c.SearchPageSize = 10
+		})
+		require.NoError(t, server.Bind())
+
+		users, err := server.Users(logins)
+		require.NoError(t, err)
+		assert.Len(t, users, 25)
+		assert.Equal(t, 3, ldapServer.pagedSearches())
+	})
+
+	t.Run("should return all groups with paged results", func(t *testing.T) {
+		ldapServer := newTestLDAPServer(t, 10, entries...)
+		server := newTestServer(t, ldapServer, func(c *ServerConfig) {
+			c.SearchPageSize = 10
+			c.GroupSearchFilter = "(member=%s)"
+			c.GroupSearchFilterUserAttribute = "dn"
+			c.Groups = nil
+		})
+		require.NoError(t, server.Bind())
+
+		users, err := server.Users([]string{"alice"})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.ElementsMatch(t, append([]string{testGroupDN("developers")}, members...), users[0].Groups)
+	})
+}
@@ -62,6 +62,15 @@ type ServerConfig struct {
 	GroupSearchFilterUserAttribute string   `toml:"group_search_filter_user_attribute" json:"group_search_filter_user_attribute"`
 	GroupSearchBaseDNs             []string `toml:"group_search_base_dns" json:"group_search_base_dns"`
 
+	// NestedGroups enables resolution of the groups the user belongs to through other groups,
+	// either "matching_rule_in_chain" (Active Directory) or "iterative"
+	NestedGroups                string `toml:"nested_groups" json:"nested_groups"`
+	NestedGroupsMemberAttribute string `toml:"nested_groups_member_attribute" json:"nested_groups_member_attribute"`
+	NestedGroupsMaxDepth        int    `toml:"nested_groups_max_depth" json:"nested_groups_max_depth"`
+
+	// SearchPageSize enables RFC 2696 paged results for user and group searches when set
+	SearchPageSize uint32 `toml:"search_page_size" json:"search_page_size"`
+
 	Groups []*GroupToOrgRole `toml:"group_mappings" json:"group_mappings"`
 }
 
@@ -193,6 +202,12 @@ func readConfig(configFile string) (*ServersConfig, error) {
 			}
 		}
 
+		switch server.NestedGroups {
+		case "", NestedGroupsMatchingRuleInChain, NestedGroupsIterative:
+		default:
+			return nil, fmt.Errorf("LDAP config file has invalid nested_groups option: %q", server.NestedGroups)
+		}
+
 		for _, groupMap := range server.Groups {
 			if groupMap.OrgRole == "" && groupMap.IsGrafanaAdmin == nil {
 				return nil, fmt.Errorf("LDAP group mapping: organization role or grafana admin status is required")
@@ -13,9 +13,10 @@ type searchFunc = func(request *ldap.SearchRequest) (*ldap.SearchResult, error)
 
 // MockConnection struct for testing
 type MockConnection struct {
-	SearchFunc       searchFunc
-	SearchCalled     bool
-	SearchAttributes []string
+	SearchFunc             searchFunc
+	SearchCalled           bool
+	SearchWithPagingCalled bool
+	SearchAttributes       []string
 
 	AddParams *ldap.AddRequest
 	AddCalled bool
@@ -83,6 +84,13 @@ func (c *MockConnection) Search(sr *ldap.SearchRequest) (*ldap.SearchResult, err
 	return c.SearchFunc(sr)
 }
 
+// SearchWithPaging mocks SearchWithPaging connection function
+func (c *MockConnection) SearchWithPaging(sr *ldap.SearchRequest, pagingSize uint32) (*ldap.SearchResult, error) {
+	c.SearchWithPagingCalled = true
+
+	return c.Search(sr)
+}
+
 // Add mocks Add connection function
 func (c *MockConnection) Add(request *ldap.AddRequest) error {
 	c.AddCalled = true
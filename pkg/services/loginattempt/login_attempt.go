@@ -2,8 +2,11 @@ package loginattempt
 
 import (
 	"context"
+	"errors"
 )
 
+var ErrLockoutNotFound = errors.New("login lockout not found")
+
 type Service interface {
 	// Add adds a new login attempt record for provided username
 	Add(ctx context.Context, username, ipAddress string) error
@@ -15,11 +18,37 @@ type Service interface {
 	ValidateIPAddress(ctx context.Context, ipAddress string) (bool, error)
 	// Reset resets all login attempts attached to username
 	Reset(ctx context.Context, username string) error
+	// GetLockouts returns the usernames, IP addresses and subnets that are currently locked out
+	GetLockouts(ctx context.Context) ([]*LoginLockout, error)
+	// DeleteLockout unlocks a username, IP address or subnet and resets its login attempts
+	DeleteLockout(ctx context.Context, id int64) error
 }
 
 type LoginAttempt struct {
 	Id        int64
 	Username  string
 	IpAddress string
+	Subnet    string
 	Created   int64
 }
+
+type LockoutKind string
+
+const (
+	LockoutKindUsername  LockoutKind = "username"
+	LockoutKindIPAddress LockoutKind = "ip_address"
+	LockoutKindSubnet    LockoutKind = "subnet"
+)
+
+// LoginLockout is kept for a username, IP address or subnet after it has been locked out.
+// Lockouts counts the consecutive lockouts and is used to increase the duration of the next one.
+type LoginLockout struct {
+	Id          int64
+	Kind        LockoutKind
+	Subject     string
+	Lockouts    int64
+	Attempts    int64
+	LockedUntil int64
+	Created     int64
+	Updated     int64
+}
@@ -2,31 +2,49 @@ package loginattemptimpl
 
 import (
 	"context"
+	"errors"
+	"net/netip"
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-const loginAttemptsWindow = time.Minute * 5
+const (
+	loginAttemptsWindow = time.Minute * 5
 
-func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService) *Service {
+	tmplLoginLocked      = "login_locked"
+	tmplLoginLockedAdmin = "login_locked_admin"
+)
+
+func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService, userService user.Service, emailSender notifications.EmailSender, bus bus.Bus) *Service {
 	return &Service{
-		&xormStore{db: db, now: time.Now},
-		cfg,
-		lock,
-		log.New("login_attempt"),
+		store:       &xormStore{db: db, now: time.Now},
+		cfg:         cfg,
+		lock:        lock,
+		userService: userService,
+		emailSender: emailSender,
+		bus:         bus,
+		logger:      log.New("login_attempt"),
 	}
 }
 
 type Service struct {
-	store  store
-	cfg    *setting.Cfg
-	lock   *serverlock.ServerLockService
-	logger log.Logger
+	store       store
+	cfg         *setting.Cfg
+	lock        *serverlock.ServerLockService
+	userService user.Service
+	emailSender notifications.EmailSender
+	bus         bus.Bus
+	logger      log.Logger
 }
 
 func (s *Service) Run(ctx context.Context) error {
@@ -47,19 +65,55 @@ func (s *Service) Run(ctx context.Context) error {
 }
 
 func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
-	if s.cfg.DisableBruteForceLoginProtection || (s.cfg.DisableUsernameLoginProtection && s.cfg.DisableIPAddressLoginProtection) {
+	if s.cfg.DisableBruteForceLoginProtection ||
+		(s.cfg.DisableUsernameLoginProtection && s.cfg.DisableIPAddressLoginProtection && s.cfg.DisableSubnetLoginProtection) {
 		return nil
 	}
 
+	username = strings.ToLower(username)
+	subnet := s.subnet(IPAddress)
 	_, err := s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
-		Username:  strings.ToLower(username),
+		Username:  username,
 		IPAddress: IPAddress,
+		Subnet:    subnet,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	// the attempt is recorded, failing to lock out is logged and retried on the next attempt
+	if !s.cfg.DisableUsernameLoginProtection {
+		if err := s.lockOutWhenExceeded(ctx, loginattempt.LockoutKindUsername, username, s.cfg.BruteForceLoginProtectionMaxAttempts); err != nil {
+			s.logger.Error("Failed to lock out username", "error", err)
+		}
+	}
+	if !s.cfg.DisableIPAddressLoginProtection {
+		if err := s.lockOutWhenExceeded(ctx, loginattempt.LockoutKindIPAddress, IPAddress, s.cfg.BruteForceLoginProtectionMaxAttempts); err != nil {
+			s.logger.Error("Failed to lock out IP address", "error", err)
+		}
+	}
+	if !s.cfg.DisableSubnetLoginProtection && subnet != "" {
+		if err := s.lockOutWhenExceeded(ctx, loginattempt.LockoutKindSubnet, subnet, s.cfg.BruteForceLoginProtectionSubnetMaxAttempts); err != nil {
+			s.logger.Error("Failed to lock out subnet", "error", err)
+		}
+	}
+
+	return nil
 }
 
 func (s *Service) Reset(ctx context.Context, username string) error {
-	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{strings.ToLower(username)})
+	username = strings.ToLower(username)
+	if err := s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{Username: username}); err != nil {
+		return err
+	}
+
+	// a successful login starts the backoff over
+	lockout, err := s.getLockout(ctx, loginattempt.LockoutKindUsername, username)
+	if err != nil || lockout == nil {
+		return err
+	}
+
+	return s.store.DeleteLockout(ctx, DeleteLockoutCommand{ID: lockout.Id})
 }
 
 func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
@@ -67,45 +121,250 @@ func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
 		return true, nil
 	}
 
-	loginAttemptCountQuery := GetUserLoginAttemptCountQuery{
-		Username: strings.ToLower(username),
-		Since:    time.Now().Add(-loginAttemptsWindow),
+	return s.validate(ctx, loginattempt.LockoutKindUsername, strings.ToLower(username), s.cfg.BruteForceLoginProtectionMaxAttempts)
+}
+
+func (s *Service) ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error) {
+	if s.cfg.DisableBruteForceLoginProtection {
+		return true, nil
 	}
 
-	count, err := s.store.GetUserLoginAttemptCount(ctx, loginAttemptCountQuery)
-	if err != nil {
-		return false, err
+	if !s.cfg.DisableIPAddressLoginProtection {
+		ok, err := s.validate(ctx, loginattempt.LockoutKindIPAddress, IPAddress, s.cfg.BruteForceLoginProtectionMaxAttempts)
+		if err != nil || !ok {
+			return ok, err
+		}
 	}
 
-	if count >= s.cfg.BruteForceLoginProtectionMaxAttempts {
-		return false, nil
+	if !s.cfg.DisableSubnetLoginProtection {
+		if subnet := s.subnet(IPAddress); subnet != "" {
+			return s.validate(ctx, loginattempt.LockoutKindSubnet, subnet, s.cfg.BruteForceLoginProtectionSubnetMaxAttempts)
+		}
 	}
 
 	return true, nil
 }
 
-func (s *Service) ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error) {
-	if s.cfg.DisableBruteForceLoginProtection || s.cfg.DisableIPAddressLoginProtection {
-		return true, nil
+func (s *Service) GetLockouts(ctx context.Context) ([]*loginattempt.LoginLockout, error) {
+	return s.store.GetActiveLockouts(ctx, GetActiveLockoutsQuery{Now: time.Now()})
+}
+
+func (s *Service) DeleteLockout(ctx context.Context, id int64) error {
+	lockout, err := s.store.GetLockout(ctx, GetLockoutQuery{ID: id})
+	if err != nil {
+		return err
 	}
 
-	loginAttemptCountQuery := GetIPLoginAttemptCountQuery{
-		IPAddress: IPAddress,
-		Since:     time.Now().Add(-loginAttemptsWindow),
+	// the attempts that led to the lockout would otherwise still block the login
+	cmd := DeleteLoginAttemptsCommand{}
+	switch lockout.Kind {
+	case loginattempt.LockoutKindUsername:
+		cmd.Username = lockout.Subject
+	case loginattempt.LockoutKindIPAddress:
+		cmd.IPAddress = lockout.Subject
+	case loginattempt.LockoutKindSubnet:
+		cmd.Subnet = lockout.Subject
+	}
+	if err := s.store.DeleteLoginAttempts(ctx, cmd); err != nil {
+		return err
+	}
+
+	return s.store.DeleteLockout(ctx, DeleteLockoutCommand{ID: lockout.Id})
+}
+
+// validate returns false while subject is locked out or when it has too many login attempts
+// since the window started or the last lockout ended.
+func (s *Service) validate(ctx context.Context, kind loginattempt.LockoutKind, subject string, maxAttempts int64) (bool, error) {
+	now := time.Now()
+	lockout, err := s.getLockout(ctx, kind, subject)
+	if err != nil {
+		return false, err
+	}
+	if lockout != nil && lockout.LockedUntil > now.Unix() {
+		return false, nil
 	}
 
-	count, err := s.store.GetIPLoginAttemptCount(ctx, loginAttemptCountQuery)
+	count, err := s.countAttempts(ctx, kind, subject, attemptsSince(now, lockout))
 	if err != nil {
 		return false, err
 	}
 
-	if count >= s.cfg.BruteForceLoginProtectionMaxAttempts {
+	if count >= maxAttempts {
 		return false, nil
 	}
 
 	return true, nil
 }
 
+// lockOutWhenExceeded locks out subject when it reached the maximum number of login attempts.
+// The lockout duration doubles for every consecutive lockout, it starts over once the previous
+// lockout ended more than the max lockout duration ago.
+func (s *Service) lockOutWhenExceeded(ctx context.Context, kind loginattempt.LockoutKind, subject string, maxAttempts int64) error {
+	now := time.Now()
+	lockout, err := s.getLockout(ctx, kind, subject)
+	if err != nil {
+		return err
+	}
+	if lockout != nil && lockout.LockedUntil > now.Unix() {
+		return nil
+	}
+
+	count, err := s.countAttempts(ctx, kind, subject, attemptsSince(now, lockout))
+	if err != nil {
+		return err
+	}
+	if count < maxAttempts {
+		return nil
+	}
+
+	if lockout == nil {
+		lockout = &loginattempt.LoginLockout{Kind: kind, Subject: subject}
+	}
+	if now.Sub(time.Unix(lockout.LockedUntil, 0)) > s.cfg.BruteForceLoginProtectionMaxLockoutDuration {
+		lockout.Lockouts = 0
+	}
+	lockout.Lockouts++
+	lockout.Attempts = count
+	lockout.LockedUntil = now.Add(s.lockoutDuration(lockout.Lockouts)).Unix()
+
+	if err := s.store.SaveLockout(ctx, lockout); err != nil {
+		return err
+	}
+
+	s.logger.Warn("Login locked out after too many failed attempts", "kind", kind, "subject", subject,
+		"attempts", count, "lockouts", lockout.Lockouts, "lockedUntil", time.Unix(lockout.LockedUntil, 0))
+	s.notify(ctx, lockout)
+
+	return nil
+}
+
+func (s *Service) lockoutDuration(lockouts int64) time.Duration {
+	duration := s.cfg.BruteForceLoginProtectionLockoutDuration
+	for i := int64(1); i < lockouts && duration < s.cfg.BruteForceLoginProtectionMaxLockoutDuration; i++ {
+		duration *= 2
+	}
+
+	if duration > s.cfg.BruteForceLoginProtectionMaxLockoutDuration {
+		return s.cfg.BruteForceLoginProtectionMaxLockoutDuration
+	}
+	return duration
+}
+
+func (s *Service) getLockout(ctx context.Context, kind loginattempt.LockoutKind, subject string) (*loginattempt.LoginLockout, error) {
+	lockout, err := s.store.GetLockout(ctx, GetLockoutQuery{Kind: kind, Subject: subject})
+	if errors.Is(err, loginattempt.ErrLockoutNotFound) {
+		return nil, nil
+	}
+
+	return lockout, err
+}
+
+func (s *Service) countAttempts(ctx context.Context, kind loginattempt.LockoutKind, subject string, since time.Time) (int64, error) {
+	switch kind {
+	case loginattempt.LockoutKindIPAddress:
+		return s.store.GetIPLoginAttemptCount(ctx, GetIPLoginAttemptCountQuery{IPAddress: subject, Since: since})
+	case loginattempt.LockoutKindSubnet:
+		return s.store.GetSubnetLoginAttemptCount(ctx, GetSubnetLoginAttemptCountQuery{Subnet: subject, Since: since})
+	default:
+		return s.store.GetUserLoginAttemptCount(ctx, GetUserLoginAttemptCountQuery{Username: subject, Since: since})
+	}
+}
+
+// attemptsSince returns the start of the window, attempts made before the last lockout ended aren't counted again
+func attemptsSince(now time.Time, lockout *loginattempt.LoginLockout) time.Time {
+	since := now.Add(-loginAttemptsWindow)
+	if lockout != nil && lockout.LockedUntil > since.Unix() {
+		return time.Unix(lockout.LockedUntil, 0)
+	}
+	return since
+}
+
+// subnet returns the network of the IP address in CIDR notation, or an empty string if it isn't a valid address
+func (s *Service) subnet(IPAddress string) string {
+	addr, err := netip.ParseAddr(strings.Trim(IPAddress, "[]"))
+	if err != nil {
+		return ""
+	}
+
+	addr = addr.Unmap().WithZone("")
+	bits := s.cfg.BruteForceLoginProtectionIPv6SubnetPrefix
+	if addr.Is4() {
+		bits = s.cfg.BruteForceLoginProtectionIPv4SubnetPrefix
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.String()
+}
+
+func (s *Service) notify(ctx context.Context, lockout *loginattempt.LoginLockout) {
+	err := s.bus.Publish(ctx, &events.LoginLockedOut{
+		Timestamp:   time.Now(),
+		Kind:        lockoutKindName(lockout.Kind),
+		Subject:     lockout.Subject,
+		Attempts:    lockout.Attempts,
+		LockedUntil: time.Unix(lockout.LockedUntil, 0),
+	})
+	if err != nil {
+		s.logger.Warn("Failed to publish login lockout event", "error", err)
+	}
+
+	data := map[string]any{
+		"Kind":        lockoutKindName(lockout.Kind),
+		"Target":      lockout.Subject,
+		"Attempts":    lockout.Attempts,
+		"LockedUntil": time.Unix(lockout.LockedUntil, 0).UTC().Format(time.RFC1123),
+	}
+
+	if s.cfg.BruteForceLoginProtectionNotifyUser && lockout.Kind == loginattempt.LockoutKindUsername {
+		usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: lockout.Subject})
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			// the username was guessed, there's nobody to notify
+		case err != nil:
+			s.logger.Error("Failed to get locked out user", "error", err)
+		case usr.Email != "":
+			data["Name"] = usr.NameOrFallback()
+			s.sendEmail(ctx, []string{usr.Email}, tmplLoginLocked, data)
+		}
+	}
+
+	if s.cfg.BruteForceLoginProtectionNotifyAdmins {
+		emails, err := s.store.GetServerAdminEmails(ctx)
+		if err != nil {
+			s.logger.Error("Failed to get server admin emails", "error", err)
+			return
+		}
+		if len(emails) > 0 {
+			s.sendEmail(ctx, emails, tmplLoginLockedAdmin, data)
+		}
+	}
+}
+
+func (s *Service) sendEmail(ctx context.Context, to []string, template string, data map[string]any) {
+	err := s.emailSender.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+		To:       to,
+		Template: template,
+		Data:     data,
+	})
+	if err != nil {
+		s.logger.Error("Failed to send login lockout notification", "template", template, "error", err)
+	}
+}
+
+func lockoutKindName(kind loginattempt.LockoutKind) string {
+	switch kind {
+	case loginattempt.LockoutKindIPAddress:
+		return "IP address"
+	case loginattempt.LockoutKindSubnet:
+		return "subnet"
+	default:
+		return "username"
+	}
+}
+
 func (s *Service) cleanup(ctx context.Context) {
 	err := s.lock.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func(context.Context) {
 		cmd := DeleteOldLoginAttemptsCommand{
@@ -116,8 +375,17 @@ func (s *Service) cleanup(ctx context.Context) {
 		} else {
 			s.logger.Debug("Deleted expired login attempts", "rows affected", deletedLogs)
 		}
-	})
 
+		// expired lockouts are kept until the backoff would start over
+		lockoutsCmd := DeleteOldLockoutsCommand{
+			OlderThan: time.Now().Add(-s.cfg.BruteForceLoginProtectionMaxLockoutDuration),
+		}
+		if deletedLockouts, err := s.store.DeleteOldLockouts(ctx, lockoutsCmd); err != nil {
+			s.logger.Error("Problem deleting expired login lockouts", "error", err.Error())
+		} else {
+			s.logger.Debug("Deleted expired login lockouts", "rows affected", deletedLockouts)
+		}
+	})
 	if err != nil {
 		s.logger.Error("Failed to lock and execute cleanup of old login attempts", "error", err)
 	}
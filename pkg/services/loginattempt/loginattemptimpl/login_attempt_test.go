@@ -7,10 +7,17 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -142,8 +149,10 @@ func TestIntegrationUserLoginAttempts(t *testing.T) {
 	cfg.DisableBruteForceLoginProtection = false
 	cfg.DisableUsernameLoginProtection = false
 	cfg.BruteForceLoginProtectionMaxAttempts = 5
+	cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+	cfg.BruteForceLoginProtectionMaxLockoutDuration = time.Hour
 	db := db.InitTestDB(t)
-	service := ProvideService(db, cfg, nil)
+	service := ProvideService(db, cfg, nil, nil, nil, bus.ProvideBus(tracing.InitializeTracerForTest()))
 
 	// add multiple login attempts with different uppercases, they all should be counted as the same user
 	_ = service.Add(ctx, "admin", "[::1]")
@@ -266,6 +275,7 @@ func TestService_ValidateIPAddress(t *testing.T) {
 			cfg.BruteForceLoginProtectionMaxAttempts = maxInvalidLoginAttempts
 			cfg.DisableBruteForceLoginProtection = tt.disableProtection
 			cfg.DisableIPAddressLoginProtection = tt.disableIPProtection
+			cfg.DisableSubnetLoginProtection = true
 			service := &Service{
 				store: fakeStore{
 					ExpectedCount: tt.loginAttempts,
@@ -290,8 +300,11 @@ func TestIntegrationIPLoginAttempts(t *testing.T) {
 	cfg.DisableBruteForceLoginProtection = false
 	cfg.DisableIPAddressLoginProtection = false
 	cfg.BruteForceLoginProtectionMaxAttempts = 3
+	cfg.DisableSubnetLoginProtection = true
+	cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+	cfg.BruteForceLoginProtectionMaxLockoutDuration = time.Hour
 	db := db.InitTestDB(t)
-	service := ProvideService(db, cfg, nil)
+	service := ProvideService(db, cfg, nil, nil, nil, bus.ProvideBus(tracing.InitializeTracerForTest()))
 
 	_ = service.Add(ctx, "user1", "192.168.1.1")
 	_ = service.Add(ctx, "user2", "10.0.0.123")
@@ -320,6 +333,7 @@ func TestIntegrationIPv6AddressSupport(t *testing.T) {
 	cfg := setting.NewCfg()
 	cfg.DisableBruteForceLoginProtection = false
 	cfg.DisableIPAddressLoginProtection = false
+	cfg.DisableSubnetLoginProtection = true
 	cfg.BruteForceLoginProtectionMaxAttempts = 5
 
 	// Use controlled time like other tests to avoid timestamp conversion issues
@@ -331,6 +345,7 @@ func TestIntegrationIPv6AddressSupport(t *testing.T) {
 	service := &Service{
 		store:  store,
 		cfg:    cfg,
+		bus:    bus.ProvideBus(tracing.InitializeTracerForTest()),
 		logger: log.New("test.login_attempt"),
 	}
 
@@ -375,12 +390,248 @@ func TestIntegrationIPv6AddressSupport(t *testing.T) {
 	}
 }
 
+func TestService_lockoutDuration(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+	cfg.BruteForceLoginProtectionMaxLockoutDuration = time.Hour
+	service := &Service{cfg: cfg}
+
+	assert.Equal(t, 5*time.Minute, service.lockoutDuration(1))
+	assert.Equal(t, 10*time.Minute, service.lockoutDuration(2))
+	assert.Equal(t, 20*time.Minute, service.lockoutDuration(3))
+	assert.Equal(t, 40*time.Minute, service.lockoutDuration(4))
+	assert.Equal(t, time.Hour, service.lockoutDuration(5))
+	assert.Equal(t, time.Hour, service.lockoutDuration(100))
+}
+
+func TestService_subnet(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.BruteForceLoginProtectionIPv4SubnetPrefix = 24
+	cfg.BruteForceLoginProtectionIPv6SubnetPrefix = 64
+	service := &Service{cfg: cfg}
+
+	assert.Equal(t, "192.168.1.0/24", service.subnet("192.168.1.42"))
+	assert.Equal(t, "10.0.0.0/24", service.subnet("::ffff:10.0.0.1"))
+	assert.Equal(t, "2001:db8:85a3:8d3::/64", service.subnet("2001:db8:85a3:8d3:1319:8a2e:370:7348"))
+	assert.Equal(t, "::/64", service.subnet("[::1]"))
+	assert.Empty(t, service.subnet("not-an-ip"))
+}
+
+func TestService_LockoutNotifications(t *testing.T) {
+	newService := func(t *testing.T, notifyUser, notifyAdmins bool, userService *usertest.FakeUserService) (*Service, *[]notifications.SendEmailCommand) {
+		t.Helper()
+
+		cfg := setting.NewCfg()
+		cfg.BruteForceLoginProtectionMaxAttempts = 3
+		cfg.DisableIPAddressLoginProtection = true
+		cfg.DisableSubnetLoginProtection = true
+		cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+		cfg.BruteForceLoginProtectionMaxLockoutDuration = time.Hour
+		cfg.BruteForceLoginProtectionNotifyUser = notifyUser
+		cfg.BruteForceLoginProtectionNotifyAdmins = notifyAdmins
+
+		sent := []notifications.SendEmailCommand{}
+		emailSender := &notifications.NotificationServiceMock{
+			EmailHandler: func(ctx context.Context, cmd *notifications.SendEmailCommand) error {
+				sent = append(sent, *cmd)
+				return nil
+			},
+		}
+
+		return &Service{
+			store:       fakeStore{ExpectedCount: 3, ExpectedAdminEmails: []string{"admin@example.org"}},
+			cfg:         cfg,
+			userService: userService,
+			emailSender: emailSender,
+			bus:         bus.ProvideBus(tracing.InitializeTracerForTest()),
+			logger:      log.New("test.login_attempt"),
+		}, &sent
+	}
+
+	t.Run("should notify the user and the server admins when a username is locked out", func(t *testing.T) {
+		service, sent := newService(t, true, true, &usertest.FakeUserService{
+			ExpectedUser: &user.User{Login: "alice", Email: "alice@example.org", Name: "Alice"},
+		})
+
+		require.NoError(t, service.Add(context.Background(), "Alice", "192.168.1.1"))
+		require.Len(t, *sent, 2)
+
+		assert.Equal(t, []string{"alice@example.org"}, (*sent)[0].To)
+		assert.Equal(t, tmplLoginLocked, (*sent)[0].Template)
+		assert.Equal(t, "Alice", (*sent)[0].Data["Name"])
+		assert.Equal(t, "alice", (*sent)[0].Data["Target"])
+
+		assert.Equal(t, []string{"admin@example.org"}, (*sent)[1].To)
+		assert.Equal(t, tmplLoginLockedAdmin, (*sent)[1].Template)
+		assert.Equal(t, "username", (*sent)[1].Data["Kind"])
+		assert.Equal(t, int64(3), (*sent)[1].Data["Attempts"])
+	})
+
+	t.Run("should only notify the server admins when the user doesn't exist", func(t *testing.T) {
+		service, sent := newService(t, true, true, &usertest.FakeUserService{ExpectedError: user.ErrUserNotFound})
+
+		require.NoError(t, service.Add(context.Background(), "unknown", "192.168.1.1"))
+		require.Len(t, *sent, 1)
+		assert.Equal(t, tmplLoginLockedAdmin, (*sent)[0].Template)
+	})
+
+	t.Run("should publish the lockout even when notifications are disabled", func(t *testing.T) {
+		service, _ := newService(t, false, false, &usertest.FakeUserService{})
+		var published *events.LoginLockedOut
+		service.bus.AddEventListener(func(ctx context.Context, evt *events.LoginLockedOut) error {
+			published = evt
+			return nil
+		})
+
+		require.NoError(t, service.Add(context.Background(), "alice", "192.168.1.1"))
+		require.NotNil(t, published)
+		assert.Equal(t, "username", published.Kind)
+		assert.Equal(t, "alice", published.Subject)
+		assert.Equal(t, int64(3), published.Attempts)
+	})
+
+	t.Run("should not notify anyone when notifications are disabled", func(t *testing.T) {
+		service, sent := newService(t, false, false, &usertest.FakeUserService{})
+
+		require.NoError(t, service.Add(context.Background(), "alice", "192.168.1.1"))
+		assert.Empty(t, *sent)
+	})
+}
+
+func TestIntegrationProgressiveLockout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+	cfg := setting.NewCfg()
+	cfg.BruteForceLoginProtectionMaxAttempts = 3
+	cfg.DisableIPAddressLoginProtection = true
+	cfg.DisableSubnetLoginProtection = true
+	cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+	cfg.BruteForceLoginProtectionMaxLockoutDuration = time.Hour
+	service := ProvideService(db.InitTestDB(t), cfg, nil, nil, nil, bus.ProvideBus(tracing.InitializeTracerForTest()))
+	// the first attempts are made a while ago so that they're older than the end of the first lockout
+	attemptsStore := service.store.(*xormStore)
+	attemptsStore.now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+
+	addAttempts := func(n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, service.Add(ctx, "admin", "192.168.1.1"))
+		}
+	}
+	getLockout := func() *loginattempt.LoginLockout {
+		lockout, err := service.store.GetLockout(ctx, GetLockoutQuery{Kind: loginattempt.LockoutKindUsername, Subject: "admin"})
+		require.NoError(t, err)
+		return lockout
+	}
+	// expire moves the end of the lockout to the past, as if the lockout was over
+	expire := func(lockout *loginattempt.LoginLockout, ago time.Duration) {
+		lockout.LockedUntil = time.Now().Add(-ago).Unix()
+		require.NoError(t, service.store.SaveLockout(ctx, lockout))
+	}
+	assertLockedFor := func(lockout *loginattempt.LoginLockout, duration time.Duration) {
+		assert.InDelta(t, time.Now().Add(duration).Unix(), lockout.LockedUntil, 5)
+	}
+
+	addAttempts(2)
+	ok, err := service.Validate(ctx, "admin")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	addAttempts(1)
+	ok, err = service.Validate(ctx, "admin")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	lockout := getLockout()
+	assert.Equal(t, int64(1), lockout.Lockouts)
+	assert.Equal(t, int64(3), lockout.Attempts)
+	assertLockedFor(lockout, 5*time.Minute)
+
+	// attempts made before the lockout ended don't count towards the next one
+	expire(lockout, time.Minute)
+	ok, err = service.Validate(ctx, "admin")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	attemptsStore.now = time.Now
+	addAttempts(3)
+	lockout = getLockout()
+	assert.Equal(t, int64(2), lockout.Lockouts)
+	assertLockedFor(lockout, 10*time.Minute)
+
+	// the backoff starts over when the last lockout ended more than the max lockout duration ago
+	expire(lockout, 2*time.Hour)
+	addAttempts(1)
+	lockout = getLockout()
+	assert.Equal(t, int64(1), lockout.Lockouts)
+	assertLockedFor(lockout, 5*time.Minute)
+
+	lockouts, err := service.GetLockouts(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	assert.Equal(t, lockout.Id, lockouts[0].Id)
+
+	require.NoError(t, service.DeleteLockout(ctx, lockout.Id))
+	ok, err = service.Validate(ctx, "admin")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	lockouts, err = service.GetLockouts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, lockouts)
+
+	err = service.DeleteLockout(ctx, lockout.Id)
+	assert.ErrorIs(t, err, loginattempt.ErrLockoutNotFound)
+}
+
+func TestIntegrationSubnetLockout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+	cfg := setting.NewCfg()
+	cfg.BruteForceLoginProtectionMaxAttempts = 5
+	cfg.DisableUsernameLoginProtection = true
+	cfg.DisableIPAddressLoginProtection = true
+	cfg.BruteForceLoginProtectionSubnetMaxAttempts = 3
+	cfg.BruteForceLoginProtectionIPv4SubnetPrefix = 24
+	cfg.BruteForceLoginProtectionIPv6SubnetPrefix = 64
+	cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+	cfg.BruteForceLoginProtectionMaxLockoutDuration = time.Hour
+	service := ProvideService(db.InitTestDB(t), cfg, nil, nil, nil, bus.ProvideBus(tracing.InitializeTracerForTest()))
+
+	require.NoError(t, service.Add(ctx, "user1", "10.0.0.1"))
+	require.NoError(t, service.Add(ctx, "user2", "10.0.0.2"))
+	require.NoError(t, service.Add(ctx, "user3", "10.0.0.3"))
+
+	ok, err := service.ValidateIPAddress(ctx, "10.0.0.200")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = service.ValidateIPAddress(ctx, "10.0.1.1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	lockouts, err := service.GetLockouts(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	assert.Equal(t, loginattempt.LockoutKindSubnet, lockouts[0].Kind)
+	assert.Equal(t, "10.0.0.0/24", lockouts[0].Subject)
+
+	require.NoError(t, service.DeleteLockout(ctx, lockouts[0].Id))
+	ok, err = service.ValidateIPAddress(ctx, "10.0.0.200")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
 var _ store = new(fakeStore)
 
 type fakeStore struct {
 	ExpectedErr         error
 	ExpectedCount       int64
 	ExpectedDeletedRows int64
+	ExpectedLockout     *loginattempt.LoginLockout
+	ExpectedAdminEmails []string
 }
 
 func (f fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
@@ -402,3 +653,34 @@ func (f fakeStore) DeleteOldLoginAttempts(ctx context.Context, command DeleteOld
 func (f fakeStore) DeleteLoginAttempts(ctx context.Context, command DeleteLoginAttemptsCommand) error {
 	return f.ExpectedErr
 }
+
+func (f fakeStore) GetSubnetLoginAttemptCount(ctx context.Context, query GetSubnetLoginAttemptCountQuery) (int64, error) {
+	return f.ExpectedCount, f.ExpectedErr
+}
+
+func (f fakeStore) GetLockout(ctx context.Context, query GetLockoutQuery) (*loginattempt.LoginLockout, error) {
+	if f.ExpectedLockout == nil && f.ExpectedErr == nil {
+		return nil, loginattempt.ErrLockoutNotFound
+	}
+	return f.ExpectedLockout, f.ExpectedErr
+}
+
+func (f fakeStore) GetActiveLockouts(ctx context.Context, query GetActiveLockoutsQuery) ([]*loginattempt.LoginLockout, error) {
+	return []*loginattempt.LoginLockout{f.ExpectedLockout}, f.ExpectedErr
+}
+
+func (f fakeStore) SaveLockout(ctx context.Context, lockout *loginattempt.LoginLockout) error {
+	return f.ExpectedErr
+}
+
+func (f fakeStore) DeleteLockout(ctx context.Context, cmd DeleteLockoutCommand) error {
+	return f.ExpectedErr
+}
+
+func (f fakeStore) DeleteOldLockouts(ctx context.Context, cmd DeleteOldLockoutsCommand) (int64, error) {
+	return f.ExpectedDeletedRows, f.ExpectedErr
+}
+
+func (f fakeStore) GetServerAdminEmails(ctx context.Context) ([]string, error) {
+	return f.ExpectedAdminEmails, f.ExpectedErr
+}
@@ -2,11 +2,14 @@ package loginattemptimpl
 
 import (
 	"time"
+
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 )
 
 type CreateLoginAttemptCommand struct {
 	Username  string
 	IPAddress string
+	Subnet    string
 }
 
 type GetUserLoginAttemptCountQuery struct {
@@ -19,10 +22,37 @@ type GetIPLoginAttemptCountQuery struct {
 	Since     time.Time
 }
 
+type GetSubnetLoginAttemptCountQuery struct {
+	Subnet string
+	Since  time.Time
+}
+
 type DeleteOldLoginAttemptsCommand struct {
 	OlderThan time.Time
 }
 
+// DeleteLoginAttemptsCommand deletes the login attempts matching one of the set fields
 type DeleteLoginAttemptsCommand struct {
-	Username string
+	Username  string
+	IPAddress string
+	Subnet    string
+}
+
+// GetLockoutQuery finds a lockout by ID, or by kind and subject when the ID isn't set
+type GetLockoutQuery struct {
+	ID      int64
+	Kind    loginattempt.LockoutKind
+	Subject string
+}
+
+type GetActiveLockoutsQuery struct {
+	Now time.Time
+}
+
+type DeleteLockoutCommand struct {
+	ID int64
+}
+
+type DeleteOldLockoutsCommand struct {
+	OlderThan time.Time
 }
@@ -19,6 +19,13 @@ type store interface {
 	DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error
 	GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error)
 	GetIPLoginAttemptCount(ctx context.Context, query GetIPLoginAttemptCountQuery) (int64, error)
+	GetSubnetLoginAttemptCount(ctx context.Context, query GetSubnetLoginAttemptCountQuery) (int64, error)
+	GetLockout(ctx context.Context, query GetLockoutQuery) (*loginattempt.LoginLockout, error)
+	GetActiveLockouts(ctx context.Context, query GetActiveLockoutsQuery) ([]*loginattempt.LoginLockout, error)
+	SaveLockout(ctx context.Context, lockout *loginattempt.LoginLockout) error
+	DeleteLockout(ctx context.Context, cmd DeleteLockoutCommand) error
+	DeleteOldLockouts(ctx context.Context, cmd DeleteOldLockoutsCommand) (int64, error)
+	GetServerAdminEmails(ctx context.Context) ([]string, error)
 }
 
 func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) (result loginattempt.LoginAttempt, err error) {
@@ -26,6 +33,7 @@ func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAtte
 		loginAttempt := loginattempt.LoginAttempt{
 			Username:  cmd.Username,
 			IpAddress: cmd.IPAddress,
+			Subnet:    cmd.Subnet,
 			Created:   xs.now().Unix(),
 		}
 
@@ -59,7 +67,15 @@ func (xs *xormStore) DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLo
 
 func (xs *xormStore) DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error {
 	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
-		_, err := sess.Exec("DELETE FROM login_attempt WHERE username = ?", cmd.Username)
+		var err error
+		switch {
+		case cmd.Username != "":
+			_, err = sess.Exec("DELETE FROM login_attempt WHERE username = ?", cmd.Username)
+		case cmd.IPAddress != "":
+			_, err = sess.Exec("DELETE FROM login_attempt WHERE ip_address = ?", cmd.IPAddress)
+		case cmd.Subnet != "":
+			_, err = sess.Exec("DELETE FROM login_attempt WHERE subnet = ?", cmd.Subnet)
+		}
 		return err
 	})
 }
@@ -99,3 +115,103 @@ func (xs *xormStore) GetIPLoginAttemptCount(ctx context.Context, query GetIPLogi
 
 	return total, err
 }
+
+func (xs *xormStore) GetSubnetLoginAttemptCount(ctx context.Context, query GetSubnetLoginAttemptCountQuery) (int64, error) {
+	var total int64
+	err := xs.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		var queryErr error
+		loginAttempt := new(loginattempt.LoginAttempt)
+		total, queryErr = dbSession.
+			Where("subnet = ?", query.Subnet).
+			And("created >= ?", query.Since.Unix()).
+			Count(loginAttempt)
+
+		return queryErr
+	})
+
+	return total, err
+}
+
+func (xs *xormStore) GetLockout(ctx context.Context, query GetLockoutQuery) (*loginattempt.LoginLockout, error) {
+	lockout := &loginattempt.LoginLockout{}
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if query.ID != 0 {
+			sess.Where("id = ?", query.ID)
+		} else {
+			sess.Where("kind = ? AND subject = ?", query.Kind, query.Subject)
+		}
+
+		has, err := sess.Get(lockout)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return loginattempt.ErrLockoutNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lockout, nil
+}
+
+func (xs *xormStore) GetActiveLockouts(ctx context.Context, query GetActiveLockoutsQuery) ([]*loginattempt.LoginLockout, error) {
+	lockouts := make([]*loginattempt.LoginLockout, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("locked_until > ?", query.Now.Unix()).Asc("locked_until").Find(&lockouts)
+	})
+
+	return lockouts, err
+}
+
+// SaveLockout creates the lockout when it has no ID yet and updates it otherwise
+func (xs *xormStore) SaveLockout(ctx context.Context, lockout *loginattempt.LoginLockout) error {
+	return xs.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		lockout.Updated = xs.now().Unix()
+		if lockout.Id == 0 {
+			lockout.Created = lockout.Updated
+			_, err := sess.Insert(lockout)
+			return err
+		}
+
+		_, err := sess.ID(lockout.Id).AllCols().Update(lockout)
+		return err
+	})
+}
+
+func (xs *xormStore) DeleteLockout(ctx context.Context, cmd DeleteLockoutCommand) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM login_lockout WHERE id = ?", cmd.ID)
+		return err
+	})
+}
+
+func (xs *xormStore) DeleteOldLockouts(ctx context.Context, cmd DeleteOldLockoutsCommand) (int64, error) {
+	var deletedRows int64
+	err := xs.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		deleteResult, err := sess.Exec("DELETE FROM login_lockout WHERE locked_until < ?", cmd.OlderThan.Unix())
+		if err != nil {
+			return err
+		}
+
+		deletedRows, err = deleteResult.RowsAffected()
+		return err
+	})
+	return deletedRows, err
+}
+
+// GetServerAdminEmails returns the email addresses of the enabled Grafana server administrators
+func (xs *xormStore) GetServerAdminEmails(ctx context.Context) ([]string, error) {
+	emails := make([]string, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(
+			"SELECT email FROM "+xs.db.GetDialect().Quote("user")+
+				" WHERE is_admin = ? AND is_disabled = ? AND is_service_account = ? AND email <> ''",
+			true, false, false,
+		).Find(&emails)
+	})
+
+	return emails, err
+}
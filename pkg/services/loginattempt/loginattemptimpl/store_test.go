@@ -2,12 +2,16 @@ package loginattemptimpl
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
 )
 
@@ -148,3 +152,82 @@ func TestIntegrationLoginAttemptsDelete(t *testing.T) {
 		require.Equal(t, test.DeletedRows, deletedRows, test.Name)
 	}
 }
+
+func TestIntegrationLoginLockouts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	now := time.Date(2017, 10, 22, 8, 0, 0, 0, time.Local)
+	s := &xormStore{
+		db:  db.InitTestDB(t),
+		now: func() time.Time { return now },
+	}
+
+	active := &loginattempt.LoginLockout{Kind: loginattempt.LockoutKindUsername, Subject: "user", Lockouts: 1, Attempts: 5, LockedUntil: now.Add(time.Minute).Unix()}
+	expired := &loginattempt.LoginLockout{Kind: loginattempt.LockoutKindIPAddress, Subject: "192.168.0.1", Lockouts: 2, Attempts: 5, LockedUntil: now.Add(-time.Hour).Unix()}
+	require.NoError(t, s.SaveLockout(ctx, active))
+	require.NoError(t, s.SaveLockout(ctx, expired))
+	require.NotZero(t, active.Id)
+	assert.Equal(t, now.Unix(), active.Created)
+
+	lockout, err := s.GetLockout(ctx, GetLockoutQuery{Kind: loginattempt.LockoutKindUsername, Subject: "user"})
+	require.NoError(t, err)
+	assert.Equal(t, active.Id, lockout.Id)
+	assert.Equal(t, int64(5), lockout.Attempts)
+
+	lockout, err = s.GetLockout(ctx, GetLockoutQuery{ID: expired.Id})
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.0.1", lockout.Subject)
+
+	_, err = s.GetLockout(ctx, GetLockoutQuery{Kind: loginattempt.LockoutKindSubnet, Subject: "user"})
+	assert.ErrorIs(t, err, loginattempt.ErrLockoutNotFound)
+
+	active.Lockouts = 2
+	require.NoError(t, s.SaveLockout(ctx, active))
+	lockouts, err := s.GetActiveLockouts(ctx, GetActiveLockoutsQuery{Now: now})
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	assert.Equal(t, active.Id, lockouts[0].Id)
+	assert.Equal(t, int64(2), lockouts[0].Lockouts)
+
+	deleted, err := s.DeleteOldLockouts(ctx, DeleteOldLockoutsCommand{OlderThan: now})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	require.NoError(t, s.DeleteLockout(ctx, DeleteLockoutCommand{ID: active.Id}))
+	_, err = s.GetLockout(ctx, GetLockoutQuery{ID: active.Id})
+	assert.ErrorIs(t, err, loginattempt.ErrLockoutNotFound)
+}
+
+func TestIntegrationGetServerAdminEmails(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	s := &xormStore{db: sqlStore, now: time.Now}
+
+	err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for i, u := range []*user.User{
+			{Login: "admin", Email: "admin@example.org", IsAdmin: true},
+			{Login: "disabled-admin", Email: "disabled@example.org", IsAdmin: true, IsDisabled: true},
+			{Login: "sa-admin", Email: "sa@example.org", IsAdmin: true, IsServiceAccount: true},
+			{Login: "editor", Email: "editor@example.org"},
+		} {
+			u.UID = fmt.Sprintf("uid-%d", i)
+			u.OrgID = 1
+			u.Created = time.Now()
+			u.Updated = time.Now()
+			if _, err := sess.Insert(u); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	emails, err := s.GetServerAdminEmails(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin@example.org"}, emails)
+}
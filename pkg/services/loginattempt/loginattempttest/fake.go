@@ -9,8 +9,9 @@ import (
 var _ loginattempt.Service = new(FakeLoginAttemptService)
 
 type FakeLoginAttemptService struct {
-	ExpectedValid bool
-	ExpectedErr   error
+	ExpectedValid    bool
+	ExpectedLockouts []*loginattempt.LoginLockout
+	ExpectedErr      error
 }
 
 func (f FakeLoginAttemptService) Add(ctx context.Context, username, IPAddress string) error {
@@ -28,3 +29,11 @@ func (f FakeLoginAttemptService) Validate(ctx context.Context, username string)
 func (f FakeLoginAttemptService) ValidateIPAddress(ctx context.Context, IpAddress string) (bool, error) {
 	return f.ExpectedValid, f.ExpectedErr
 }
+
+func (f FakeLoginAttemptService) GetLockouts(ctx context.Context) ([]*loginattempt.LoginLockout, error) {
+	return f.ExpectedLockouts, f.ExpectedErr
+}
+
+func (f FakeLoginAttemptService) DeleteLockout(ctx context.Context, id int64) error {
+	return f.ExpectedErr
+}
@@ -9,12 +9,14 @@ import (
 var _ loginattempt.Service = new(MockLoginAttemptService)
 
 type MockLoginAttemptService struct {
-	AddCalled      bool
-	ResetCalled    bool
-	ValidateCalled bool
-
-	ExpectedValid bool
-	ExpectedErr   error
+	AddCalled           bool
+	ResetCalled         bool
+	ValidateCalled      bool
+	DeleteLockoutCalled bool
+
+	ExpectedValid    bool
+	ExpectedLockouts []*loginattempt.LoginLockout
+	ExpectedErr      error
 }
 
 func (f *MockLoginAttemptService) Add(ctx context.Context, username, ipAddress string) error {
@@ -36,3 +38,12 @@ func (f *MockLoginAttemptService) ValidateIPAddress(ctx context.Context, ipAddre
 	f.ValidateCalled = true
 	return f.ExpectedValid, f.ExpectedErr
 }
+
+func (f *MockLoginAttemptService) GetLockouts(ctx context.Context) ([]*loginattempt.LoginLockout, error) {
+	return f.ExpectedLockouts, f.ExpectedErr
+}
+
+func (f *MockLoginAttemptService) DeleteLockout(ctx context.Context, id int64) error {
+	f.DeleteLockoutCalled = true
+	return f.ExpectedErr
+}
@@ -0,0 +1,138 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrFactorNotFound      = errutil.NotFound("mfa.factor-not-found", errutil.WithPublicMessage("Second factor not found"))
+	ErrNoPendingEnrollment = errutil.BadRequest("mfa.no-pending-enrollment", errutil.WithPublicMessage("No pending second factor enrollment"))
+	ErrAlreadyEnrolled     = errutil.Conflict("mfa.already-enrolled", errutil.WithPublicMessage("An authenticator app is already enrolled"))
+	ErrInvalidCode         = errutil.Unauthorized("mfa.invalid-code", errutil.WithPublicMessage("Invalid verification code"))
+	ErrInvalidCredential   = errutil.Unauthorized("mfa.invalid-credential", errutil.WithPublicMessage("Invalid security key response"))
+	ErrChallengeNotFound   = errutil.Unauthorized("mfa.challenge-not-found", errutil.WithPublicMessage("Second factor challenge expired, please log in again"))
+	ErrNoRecoveryCodes     = errutil.BadRequest("mfa.no-factors", errutil.WithPublicMessage("Recovery codes require an enrolled second factor"))
+	ErrDuplicateCredential = errutil.Conflict("mfa.duplicate-credential", errutil.WithPublicMessage("Security key is already registered"))
+	ErrNotEnrollment       = errutil.BadRequest("mfa.not-enrollment", errutil.WithPublicMessage("Second factor challenge is not an enrollment"))
+)
+
+type FactorType string
+
+const (
+	// FactorTOTP is a time-based one-time password generated by an authenticator app (RFC 6238).
+	FactorTOTP FactorType = "totp"
+	// FactorWebAuthn is a security key or passkey registered with WebAuthn.
+	FactorWebAuthn FactorType = "webauthn"
+)
+
+// Service manages the second factors of users and verifies them when users log in
+// with a username and password.
+type Service interface {
+	// IsEnrolled returns true if the user has at least one verified second factor.
+	IsEnrolled(ctx context.Context, userID int64) (bool, error)
+	// CreateChallenge creates a challenge that has to be answered with a second factor
+	// of the user before a session is issued.
+	CreateChallenge(ctx context.Context, userID int64, login string) (*Challenge, error)
+	// GetChallenge returns a challenge that has not been answered or expired yet.
+	GetChallenge(ctx context.Context, token string) (*Challenge, error)
+	// VerifyChallenge verifies the answer to a challenge and returns the challenge.
+	// A challenge can only be answered successfully once.
+	VerifyChallenge(ctx context.Context, cmd *VerifyChallengeCommand) (*Challenge, error)
+
+	// EnrollmentRequired returns true if the user has to enroll a second factor before
+	// logging in, because second factors are enforced for administrators.
+	EnrollmentRequired(ctx context.Context, userID int64) (bool, error)
+	// CreateEnrollmentChallenge creates a challenge that is answered by enrolling an
+	// authenticator app with EnrollChallengeTOTP and verifying a code generated by it.
+	CreateEnrollmentChallenge(ctx context.Context, userID int64, login string) (*Challenge, error)
+	// EnrollChallengeTOTP starts the enrollment of an authenticator app for the user of
+	// an enrollment challenge.
+	EnrollChallengeTOTP(ctx context.Context, token string) (*TOTPEnrollment, error)
+
+	// GetFactors returns the verified second factors of the user.
+	GetFactors(ctx context.Context, userID int64) ([]*FactorDTO, error)
+	// EnrollTOTP starts the enrollment of an authenticator app for the user. Any
+	// earlier enrollment that has not been verified is replaced.
+	EnrollTOTP(ctx context.Context, userID int64, login string) (*TOTPEnrollment, error)
+	// VerifyTOTPEnrollment completes the enrollment of an authenticator app with a
+	// code generated by the app.
+	VerifyTOTPEnrollment(ctx context.Context, userID int64, code string) (*EnrollmentResult, error)
+	// BeginWebAuthnRegistration returns the options for navigator.credentials.create()
+	// to register a security key or passkey for the user.
+	BeginWebAuthnRegistration(ctx context.Context, userID int64, login string) (*WebAuthnCreationOptions, error)
+	// FinishWebAuthnRegistration verifies and stores the credential created by the browser.
+	FinishWebAuthnRegistration(ctx context.Context, userID int64, cmd *FinishWebAuthnRegistrationCommand) (*EnrollmentResult, error)
+	// DeleteFactor deletes a second factor of the user. Recovery codes are deleted
+	// together with the last second factor.
+	DeleteFactor(ctx context.Context, userID, factorID int64) error
+	// RegenerateRecoveryCodes replaces the recovery codes of the user.
+	RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error)
+	// ResetFactors deletes all second factors and recovery codes of the user.
+	ResetFactors(ctx context.Context, userID int64) error
+}
+
+type FactorDTO struct {
+	ID       int64      `json:"id"`
+	Type     FactorType `json:"type"`
+	Name     string     `json:"name"`
+	Created  time.Time  `json:"created"`
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+}
+
+type TOTPEnrollment struct {
+	// Secret is the base32 encoded secret to enter in the authenticator app.
+	Secret string `json:"secret"`
+	// URL is the otpauth:// URL to show as a QR code.
+	URL string `json:"url"`
+}
+
+type EnrollmentResult struct {
+	Factor *FactorDTO `json:"factor"`
+	// RecoveryCodes are only returned when the first second factor of the user is enrolled.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// Challenge is the second step of a login of a user with a second factor.
+type Challenge struct {
+	Token    string                  `json:"token"`
+	Factors  []FactorType            `json:"factors"`
+	WebAuthn *WebAuthnRequestOptions `json:"webauthn,omitempty"`
+	// EnrollmentRequired is set for challenges of users that have to enroll an
+	// authenticator app before they can log in.
+	EnrollmentRequired bool `json:"enrollmentRequired,omitempty"`
+
+	UserID int64  `json:"-"`
+	Login  string `json:"-"`
+}
+
+type VerifyChallengeCommand struct {
+	Token string `json:"token" binding:"Required"`
+	// Code is a code generated by an authenticator app.
+	Code string `json:"code"`
+	// RecoveryCode is one of the recovery codes of the user. It can only be used once.
+	RecoveryCode string `json:"recoveryCode"`
+	// WebAuthn is the assertion returned by navigator.credentials.get() serialized
+	// with PublicKeyCredential.toJSON().
+	WebAuthn json.RawMessage `json:"webauthn"`
+}
+
+type FinishWebAuthnRegistrationCommand struct {
+	Name string `json:"name"`
+	// Credential is the credential returned by navigator.credentials.create()
+	// serialized with PublicKeyCredential.toJSON().
+	Credential json.RawMessage `json:"credential"`
+}
+
+// WebAuthnCreationOptions are the options for navigator.credentials.create(), with all
+// binary values encoded as base64url.
+type WebAuthnCreationOptions = protocol.PublicKeyCredentialCreationOptions
+
+// WebAuthnRequestOptions are the options for navigator.credentials.get(), with all
+// binary values encoded as base64url.
+type WebAuthnRequestOptions = protocol.PublicKeyCredentialRequestOptions
@@ -0,0 +1,701 @@
+package mfaimpl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	challengeKeyPrefix        = "mfa-challenge-%s"
+	registrationKeyPrefix     = "mfa-webauthn-registration-%d"
+	maxChallengeAttempts      = 5
+	recoveryCodeCount         = 10
+	webAuthnRegistrationTTL   = 5 * time.Minute
+	defaultTOTPFactorName     = "Authenticator app"
+	defaultWebAuthnFactorName = "Security key"
+)
+
+var _ mfa.Service = (*Service)(nil)
+
+func ProvideService(db db.DB, cfg *setting.Cfg, secretsService secrets.Service, cache remotecache.CacheStorage, userService user.Service, orgService org.Service) *Service {
+	return &Service{
+		store:       &xormStore{db: db, now: time.Now},
+		cfg:         cfg,
+		secrets:     secretsService,
+		cache:       cache,
+		userService: userService,
+		orgService:  orgService,
+		log:         log.New("mfa"),
+		now:         time.Now,
+	}
+}
+
+type Service struct {
+	store       store
+	cfg         *setting.Cfg
+	secrets     secrets.Service
+	cache       remotecache.CacheStorage
+	userService user.Service
+	orgService  org.Service
+	log         log.Logger
+	now         func() time.Time
+}
+
+func (s *Service) IsEnrolled(ctx context.Context, userID int64) (bool, error) {
+	factors, err := s.verifiedFactors(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(factors) > 0, nil
+}
+
+func (s *Service) CreateChallenge(ctx context.Context, userID int64, login string) (*mfa.Challenge, error) {
+	factors, err := s.verifiedFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(factors) == 0 {
+		return nil, mfa.ErrFactorNotFound.Errorf("user has no second factor")
+	}
+
+	token, err := util.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &mfa.Challenge{Token: token, Factors: []mfa.FactorType{}, UserID: userID, Login: login}
+	entry := &challengeEntry{UserID: userID, Login: login, Expires: s.now().Add(s.cfg.AuthMFA.ChallengeExpiration)}
+
+	for _, f := range factors {
+		if !slices.Contains(challenge.Factors, f.Type) {
+			challenge.Factors = append(challenge.Factors, f.Type)
+		}
+	}
+
+	if slices.Contains(challenge.Factors, mfa.FactorWebAuthn) {
+		wa, err := s.newWebAuthn()
+		if err != nil {
+			return nil, err
+		}
+		waUser, err := newWebAuthnUser(userID, login, factors)
+		if err != nil {
+			return nil, err
+		}
+		assertion, session, err := wa.BeginLogin(waUser)
+		if err != nil {
+			return nil, err
+		}
+		entry.WebAuthnSession = session
+		challenge.WebAuthn = &assertion.Response
+	}
+
+	if err := s.createChallengeEntry(ctx, token, entry); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func (s *Service) GetChallenge(ctx context.Context, token string) (*mfa.Challenge, error) {
+	entry, err := s.getChallengeEntry(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &mfa.Challenge{Token: token, UserID: entry.UserID, Login: entry.Login}, nil
+}
+
+func (s *Service) VerifyChallenge(ctx context.Context, cmd *mfa.VerifyChallengeCommand) (*mfa.Challenge, error) {
+	entry, err := s.getChallengeEntry(ctx, cmd.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// The attempt is counted before the answer is verified so that concurrent requests
+	// can't verify more answers than allowed. The challenge is deleted after the last
+	// attempt so that the user has to start over with the password.
+	left, ok, err := s.store.AddChallengeAttempt(ctx, hashChallengeToken(cmd.Token), maxChallengeAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.deleteChallengeEntry(ctx, cmd.Token)
+		return nil, mfa.ErrChallengeNotFound.Errorf("too many attempts")
+	}
+
+	switch {
+	case entry.Enrollment:
+		err = s.verifyEnrollment(ctx, entry.UserID, cmd.Code)
+	case len(cmd.WebAuthn) > 0:
+		err = s.verifyWebAuthnAssertion(ctx, entry, cmd.WebAuthn)
+	case cmd.RecoveryCode != "":
+		err = s.useRecoveryCode(ctx, entry.UserID, cmd.RecoveryCode)
+	case cmd.Code != "":
+		err = s.verifyTOTP(ctx, entry.UserID, cmd.Code)
+	default:
+		err = mfa.ErrInvalidCode.Errorf("no second factor provided")
+	}
+
+	if err != nil {
+		if left == 0 {
+			s.deleteChallengeEntry(ctx, cmd.Token)
+		}
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, fmt.Sprintf(challengeKeyPrefix, cmd.Token)); err != nil {
+		return nil, fmt.Errorf("failed to delete second factor challenge: %w", err)
+	}
+	if err := s.store.DeleteChallengeAttempts(ctx, hashChallengeToken(cmd.Token)); err != nil {
+		s.log.Warn("Failed to delete second factor challenge attempts", "error", err)
+	}
+	return &mfa.Challenge{Token: cmd.Token, UserID: entry.UserID, Login: entry.Login}, nil
+}
+
+func (s *Service) EnrollmentRequired(ctx context.Context, userID int64) (bool, error) {
+	if !s.cfg.AuthMFA.EnforceForAdmins {
+		return false, nil
+	}
+
+	enrolled, err := s.IsEnrolled(ctx, userID)
+	if err != nil || enrolled {
+		return false, err
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return false, err
+	}
+	if usr.IsAdmin {
+		return true, nil
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		if o.Role == org.RoleAdmin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) CreateEnrollmentChallenge(ctx context.Context, userID int64, login string) (*mfa.Challenge, error) {
+	token, err := util.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &challengeEntry{UserID: userID, Login: login, Enrollment: true, Expires: s.now().Add(s.cfg.AuthMFA.ChallengeExpiration)}
+	if err := s.createChallengeEntry(ctx, token, entry); err != nil {
+		return nil, err
+	}
+	return &mfa.Challenge{
+		Token:              token,
+		Factors:            []mfa.FactorType{mfa.FactorTOTP},
+		EnrollmentRequired: true,
+		UserID:             userID,
+		Login:              login,
+	}, nil
+}
+
+func (s *Service) EnrollChallengeTOTP(ctx context.Context, token string) (*mfa.TOTPEnrollment, error) {
+	entry, err := s.getChallengeEntry(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.Enrollment {
+		return nil, mfa.ErrNotEnrollment.Errorf("challenge is not an enrollment")
+	}
+	return s.EnrollTOTP(ctx, entry.UserID, entry.Login)
+}
+
+func (s *Service) GetFactors(ctx context.Context, userID int64) ([]*mfa.FactorDTO, error) {
+	factors, err := s.verifiedFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mfa.FactorDTO, 0, len(factors))
+	for _, f := range factors {
+		result = append(result, f.toDTO())
+	}
+	return result, nil
+}
+
+func (s *Service) EnrollTOTP(ctx context.Context, userID int64, login string) (*mfa.TOTPEnrollment, error) {
+	factors, err := s.store.GetFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range factors {
+		if f.Type == mfa.FactorTOTP && f.Verified {
+			return nil, mfa.ErrAlreadyEnrolled.Errorf("user already has an authenticator app")
+		}
+	}
+
+	if err := s.store.DeletePendingFactors(ctx, userID, mfa.FactorTOTP); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.CreateFactor(ctx, &factor{
+		UserID: userID,
+		Type:   mfa.FactorTOTP,
+		Name:   defaultTOTPFactorName,
+		Secret: base64.StdEncoding.EncodeToString(encrypted),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mfa.TOTPEnrollment{
+		Secret: secret,
+		URL:    totpURL(s.cfg.AuthMFA.TOTPIssuer, login, secret),
+	}, nil
+}
+
+func (s *Service) VerifyTOTPEnrollment(ctx context.Context, userID int64, code string) (*mfa.EnrollmentResult, error) {
+	factors, err := s.store.GetFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending *factor
+	for _, f := range factors {
+		if f.Type == mfa.FactorTOTP && !f.Verified {
+			pending = f
+		}
+	}
+	if pending == nil {
+		return nil, mfa.ErrNoPendingEnrollment.Errorf("user has no pending authenticator app")
+	}
+
+	secret, err := s.decryptSecret(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := validateTOTP(secret, code, s.now(), 0)
+	if !ok {
+		return nil, mfa.ErrInvalidCode.Errorf("invalid code for pending authenticator app")
+	}
+
+	if err := s.store.VerifyFactor(ctx, pending.ID, step); err != nil {
+		return nil, err
+	}
+	pending.Verified = true
+	pending.Counter = step
+
+	return s.enrollmentResult(ctx, userID, factors, pending)
+}
+
+func (s *Service) BeginWebAuthnRegistration(ctx context.Context, userID int64, login string) (*mfa.WebAuthnCreationOptions, error) {
+	factors, err := s.verifiedFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wa, err := s.newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+	waUser, err := newWebAuthnUser(userID, login, factors)
+	if err != nil {
+		return nil, err
+	}
+	creation, session, err := wa.BeginRegistration(waUser, webauthn.WithExclusions(waUser.credentialDescriptors()))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(&registrationEntry{Login: login, Session: session})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, fmt.Sprintf(registrationKeyPrefix, userID), data, webAuthnRegistrationTTL); err != nil {
+		return nil, err
+	}
+	return &creation.Response, nil
+}
+
+func (s *Service) FinishWebAuthnRegistration(ctx context.Context, userID int64, cmd *mfa.FinishWebAuthnRegistrationCommand) (*mfa.EnrollmentResult, error) {
+	key := fmt.Sprintf(registrationKeyPrefix, userID)
+	data, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, mfa.ErrNoPendingEnrollment.Errorf("no pending security key registration")
+		}
+		return nil, err
+	}
+	// A registration challenge can only be used once.
+	if err := s.cache.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+
+	var entry registrationEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	if entry.Session == nil {
+		return nil, mfa.ErrNoPendingEnrollment.Errorf("no pending security key registration")
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(cmd.Credential))
+	if err != nil {
+		return nil, invalidCredential(err)
+	}
+
+	factors, err := s.store.GetFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	wa, err := s.newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+	waUser, err := newWebAuthnUser(userID, entry.Login, factors)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := wa.CreateCredential(waUser, *entry.Session, parsed)
+	if err != nil {
+		return nil, invalidCredential(err)
+	}
+
+	credentialID := encodeBase64URL(credential.ID)
+	if _, ok := waUser.factors[credentialID]; ok {
+		return nil, mfa.ErrDuplicateCredential.Errorf("credential is already registered")
+	}
+
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		name = defaultWebAuthnFactorName
+	}
+	if len(name) > 190 {
+		name = name[:190]
+	}
+
+	f := &factor{
+		UserID:       userID,
+		Type:         mfa.FactorWebAuthn,
+		Name:         name,
+		CredentialID: credentialID,
+		PublicKey:    base64.StdEncoding.EncodeToString(credential.PublicKey),
+		Counter:      int64(credential.Authenticator.SignCount),
+		Verified:     true,
+	}
+	if err := s.store.CreateFactor(ctx, f); err != nil {
+		return nil, err
+	}
+
+	return s.enrollmentResult(ctx, userID, factors, f)
+}
+
+func (s *Service) DeleteFactor(ctx context.Context, userID, factorID int64) error {
+	deleted, err := s.store.DeleteFactor(ctx, userID, factorID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return mfa.ErrFactorNotFound.Errorf("factor %d not found", factorID)
+	}
+
+	remaining, err := s.verifiedFactors(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(remaining) == 0 {
+		return s.store.DeleteRecoveryCodes(ctx, userID)
+	}
+	return nil
+}
+
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	enrolled, err := s.IsEnrolled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, mfa.ErrNoRecoveryCodes.Errorf("user has no second factor")
+	}
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+func (s *Service) ResetFactors(ctx context.Context, userID int64) error {
+	if err := s.cache.Delete(ctx, fmt.Sprintf(registrationKeyPrefix, userID)); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.Warn("Failed to delete security key registration", "error", err)
+	}
+	return s.store.DeleteAll(ctx, userID)
+}
+
+func (s *Service) verifiedFactors(ctx context.Context, userID int64) ([]*factor, error) {
+	factors, err := s.store.GetFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	verified := make([]*factor, 0, len(factors))
+	for _, f := range factors {
+		if f.Verified {
+			verified = append(verified, f)
+		}
+	}
+	return verified, nil
+}
+
+// enrollmentResult returns the newly enrolled factor, together with recovery codes if
+// it is the first verified factor of the user.
+func (s *Service) enrollmentResult(ctx context.Context, userID int64, before []*factor, enrolled *factor) (*mfa.EnrollmentResult, error) {
+	result := &mfa.EnrollmentResult{Factor: enrolled.toDTO()}
+	for _, f := range before {
+		if f.Verified && f.ID != enrolled.ID {
+			return result, nil
+		}
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = codes
+	return result, nil
+}
+
+func (s *Service) replaceRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := util.GetRandomString(10, []byte("abcdefghijkmnpqrstuvwxyz23456789")...)
+		if err != nil {
+			return nil, err
+		}
+		code = code[:5] + "-" + code[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := s.store.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *Service) useRecoveryCode(ctx context.Context, userID int64, code string) error {
+	deleted, err := s.store.DeleteRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return mfa.ErrInvalidCode.Errorf("invalid recovery code")
+	}
+	return nil
+}
+
+func (s *Service) verifyTOTP(ctx context.Context, userID int64, code string) error {
+	factors, err := s.verifiedFactors(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range factors {
+		if f.Type != mfa.FactorTOTP {
+			continue
+		}
+		secret, err := s.decryptSecret(ctx, f)
+		if err != nil {
+			return err
+		}
+		step, ok := validateTOTP(secret, code, s.now(), f.Counter)
+		if !ok {
+			continue
+		}
+		// The counter is only updated if no other login has used a code in the
+		// meantime, so that the same code can't be used twice.
+		updated, err := s.store.UpdateCounter(ctx, f.ID, f.Counter, step)
+		if err != nil {
+			return err
+		}
+		if updated {
+			return nil
+		}
+	}
+	return mfa.ErrInvalidCode.Errorf("invalid authenticator app code")
+}
+
+// verifyEnrollment completes the enrollment of an authenticator app started with an
+// enrollment challenge. Recovery codes aren't returned with a login, so users generate
+// them once they are logged in.
+func (s *Service) verifyEnrollment(ctx context.Context, userID int64, code string) error {
+	if code == "" {
+		return mfa.ErrInvalidCode.Errorf("no code provided for enrollment")
+	}
+	factors, err := s.store.GetFactors(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range factors {
+		if f.Type != mfa.FactorTOTP || f.Verified {
+			continue
+		}
+		secret, err := s.decryptSecret(ctx, f)
+		if err != nil {
+			return err
+		}
+		step, ok := validateTOTP(secret, code, s.now(), 0)
+		if !ok {
+			return mfa.ErrInvalidCode.Errorf("invalid code for pending authenticator app")
+		}
+		return s.store.VerifyFactor(ctx, f.ID, step)
+	}
+	return mfa.ErrNoPendingEnrollment.Errorf("user has no pending authenticator app")
+}
+
+func (s *Service) verifyWebAuthnAssertion(ctx context.Context, entry *challengeEntry, raw json.RawMessage) error {
+	if entry.WebAuthnSession == nil {
+		return mfa.ErrInvalidCredential.Errorf("user has no security key")
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(raw))
+	if err != nil {
+		return invalidCredential(err)
+	}
+
+	factors, err := s.verifiedFactors(ctx, entry.UserID)
+	if err != nil {
+		return err
+	}
+	wa, err := s.newWebAuthn()
+	if err != nil {
+		return err
+	}
+	waUser, err := newWebAuthnUser(entry.UserID, entry.Login, factors)
+	if err != nil {
+		return err
+	}
+	credential, err := wa.ValidateLogin(waUser, *entry.WebAuthnSession, parsed)
+	if err != nil {
+		return invalidCredential(err)
+	}
+	f := waUser.factors[encodeBase64URL(credential.ID)]
+	if f == nil {
+		return mfa.ErrInvalidCredential.Errorf("unknown credential")
+	}
+
+	// Authenticators that support signature counters increase them on every use. A
+	// counter that doesn't increase means that the credential may have been cloned.
+	if credential.Authenticator.CloneWarning {
+		s.log.Warn("Security key signature counter did not increase", "userId", entry.UserID, "factorId", f.ID)
+		return mfa.ErrInvalidCredential.Errorf("signature counter did not increase")
+	}
+
+	updated, err := s.store.UpdateCounter(ctx, f.ID, f.Counter, int64(credential.Authenticator.SignCount))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return mfa.ErrInvalidCredential.Errorf("credential was used concurrently")
+	}
+	return nil
+}
+
+func (s *Service) decryptSecret(ctx context.Context, f *factor) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(f.Secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret of factor %d: %w", f.ID, err)
+	}
+	secret, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret of factor %d: %w", f.ID, err)
+	}
+	return string(secret), nil
+}
+
+func (s *Service) getChallengeEntry(ctx context.Context, token string) (*challengeEntry, error) {
+	if token == "" {
+		return nil, mfa.ErrChallengeNotFound.Errorf("empty challenge token")
+	}
+	data, err := s.cache.Get(ctx, fmt.Sprintf(challengeKeyPrefix, token))
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, mfa.ErrChallengeNotFound.Errorf("challenge not found")
+		}
+		return nil, err
+	}
+
+	var entry challengeEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse second factor challenge: %w", err)
+	}
+	if !s.now().Before(entry.Expires) {
+		return nil, mfa.ErrChallengeNotFound.Errorf("challenge expired")
+	}
+	return &entry, nil
+}
+
+// createChallengeEntry stores a new challenge in the remote cache and its attempts in
+// the database, where they can be counted atomically.
+func (s *Service) createChallengeEntry(ctx context.Context, token string, entry *challengeEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	ttl := entry.Expires.Sub(s.now())
+	if ttl <= 0 {
+		return mfa.ErrChallengeNotFound.Errorf("challenge expired")
+	}
+	if err := s.store.CreateChallengeAttempts(ctx, hashChallengeToken(token), entry.Expires); err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, fmt.Sprintf(challengeKeyPrefix, token), data, ttl)
+}
+
+func (s *Service) deleteChallengeEntry(ctx context.Context, token string) {
+	if err := s.cache.Delete(ctx, fmt.Sprintf(challengeKeyPrefix, token)); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.Warn("Failed to delete second factor challenge", "error", err)
+	}
+	if err := s.store.DeleteChallengeAttempts(ctx, hashChallengeToken(token)); err != nil {
+		s.log.Warn("Failed to delete second factor challenge attempts", "error", err)
+	}
+}
+
+// hashChallengeToken hashes a challenge token so that the tokens aren't stored in clear text.
+func hashChallengeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// hashRecoveryCode normalizes and hashes a recovery code. Recovery codes are random,
+// so a plain hash is enough to not store them in clear text.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
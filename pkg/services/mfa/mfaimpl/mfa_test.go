@@ -0,0 +1,329 @@
+package mfaimpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func setupTestService(t *testing.T) (*Service, *time.Time) {
+	t.Helper()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	cfg := setting.NewCfg()
+	cfg.AuthMFA = setting.AuthMFASettings{
+		Enabled:             true,
+		TOTPIssuer:          "Grafana",
+		WebAuthnRPID:        testRPID,
+		WebAuthnRPOrigins:   []string{testOrigin},
+		ChallengeExpiration: 5 * time.Minute,
+	}
+
+	return &Service{
+		store:       &xormStore{db: db.InitTestDB(t), now: clock},
+		cfg:         cfg,
+		secrets:     fakes.NewFakeSecretsService(),
+		cache:       remotecache.NewFakeCacheStorage(),
+		userService: &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1}},
+		orgService:  orgtest.NewOrgServiceFake(),
+		log:         log.NewNopLogger(),
+		now:         clock,
+	}, &now
+}
+
+func totpCodeAt(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+	key, err := totpEncoding.DecodeString(secret)
+	require.NoError(t, err)
+	return hotp(key, uint64(now.Unix()/totpPeriod))
+}
+
+func TestIntegrationTOTP(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, now := setupTestService(t)
+
+	enrollment, err := s.EnrollTOTP(ctx, 1, "admin")
+	require.NoError(t, err)
+	require.Contains(t, enrollment.URL, "otpauth://totp/Grafana:admin?")
+
+	enrolled, err := s.IsEnrolled(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, enrolled, "pending factors should not count")
+
+	_, err = s.VerifyTOTPEnrollment(ctx, 1, "000000")
+	require.ErrorIs(t, err, mfa.ErrInvalidCode)
+
+	result, err := s.VerifyTOTPEnrollment(ctx, 1, totpCodeAt(t, enrollment.Secret, *now))
+	require.NoError(t, err)
+	require.Equal(t, mfa.FactorTOTP, result.Factor.Type)
+	require.Len(t, result.RecoveryCodes, recoveryCodeCount)
+
+	_, err = s.EnrollTOTP(ctx, 1, "admin")
+	require.ErrorIs(t, err, mfa.ErrAlreadyEnrolled)
+
+	factors, err := s.GetFactors(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, factors, 1)
+
+	t.Run("challenge can be answered with a code once", func(t *testing.T) {
+		*now = now.Add(time.Minute)
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []mfa.FactorType{mfa.FactorTOTP}, challenge.Factors)
+		require.Nil(t, challenge.WebAuthn)
+
+		code := totpCodeAt(t, enrollment.Secret, *now)
+		verified, err := s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: code})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), verified.UserID)
+		require.Equal(t, "admin", verified.Login)
+
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: code})
+		require.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+
+		// the same code can't be used for another login
+		challenge, err = s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: code})
+		require.ErrorIs(t, err, mfa.ErrInvalidCode)
+	})
+
+	t.Run("recovery codes can be used once", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, RecoveryCode: result.RecoveryCodes[0]})
+		require.NoError(t, err)
+
+		challenge, err = s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, RecoveryCode: result.RecoveryCodes[0]})
+		require.ErrorIs(t, err, mfa.ErrInvalidCode)
+	})
+
+	t.Run("challenge is deleted after too many attempts", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		for i := 0; i < maxChallengeAttempts; i++ {
+			_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000"})
+			require.ErrorIs(t, err, mfa.ErrInvalidCode)
+		}
+		_, err = s.GetChallenge(ctx, challenge.Token)
+		require.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	t.Run("concurrent attempts are limited", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		var invalid atomic.Int32
+		for i := 0; i < 2*maxChallengeAttempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000"})
+				if errors.Is(err, mfa.ErrInvalidCode) {
+					invalid.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int32(maxChallengeAttempts), invalid.Load())
+	})
+
+	t.Run("challenge expires", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		*now = now.Add(s.cfg.AuthMFA.ChallengeExpiration)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: totpCodeAt(t, enrollment.Secret, *now)})
+		require.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	t.Run("deleting the last factor deletes the recovery codes", func(t *testing.T) {
+		require.NoError(t, s.DeleteFactor(ctx, 1, factors[0].ID))
+		require.ErrorIs(t, s.DeleteFactor(ctx, 1, factors[0].ID), mfa.ErrFactorNotFound)
+
+		enrolled, err := s.IsEnrolled(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, enrolled)
+
+		_, err = s.RegenerateRecoveryCodes(ctx, 1)
+		require.ErrorIs(t, err, mfa.ErrNoRecoveryCodes)
+		deleted, err := s.store.DeleteRecoveryCode(ctx, 1, hashRecoveryCode(result.RecoveryCodes[1]))
+		require.NoError(t, err)
+		require.False(t, deleted)
+	})
+}
+
+func TestIntegrationWebAuthn(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, _ := setupTestService(t)
+	a := newFakeAuthenticator(t)
+
+	options, err := s.BeginWebAuthnRegistration(ctx, 1, "admin")
+	require.NoError(t, err)
+	require.Equal(t, testRPID, options.RelyingParty.ID)
+	require.Empty(t, options.CredentialExcludeList)
+
+	result, err := s.FinishWebAuthnRegistration(ctx, 1, &mfa.FinishWebAuthnRegistrationCommand{Name: "YubiKey", Credential: a.create(options.Challenge)})
+	require.NoError(t, err)
+	require.Equal(t, "YubiKey", result.Factor.Name)
+	require.Len(t, result.RecoveryCodes, recoveryCodeCount)
+
+	t.Run("registration can't be finished twice", func(t *testing.T) {
+		_, err := s.FinishWebAuthnRegistration(ctx, 1, &mfa.FinishWebAuthnRegistrationCommand{Credential: a.create(options.Challenge)})
+		require.ErrorIs(t, err, mfa.ErrNoPendingEnrollment)
+	})
+
+	t.Run("same credential can't be registered twice", func(t *testing.T) {
+		options, err := s.BeginWebAuthnRegistration(ctx, 1, "admin")
+		require.NoError(t, err)
+		require.Len(t, options.CredentialExcludeList, 1)
+		_, err = s.FinishWebAuthnRegistration(ctx, 1, &mfa.FinishWebAuthnRegistrationCommand{Credential: a.create(options.Challenge)})
+		require.ErrorIs(t, err, mfa.ErrDuplicateCredential)
+	})
+
+	t.Run("registration from another origin should fail", func(t *testing.T) {
+		other := newFakeAuthenticator(t)
+		other.origin = "https://evil.example.com"
+		options, err := s.BeginWebAuthnRegistration(ctx, 1, "admin")
+		require.NoError(t, err)
+		_, err = s.FinishWebAuthnRegistration(ctx, 1, &mfa.FinishWebAuthnRegistrationCommand{Credential: other.create(options.Challenge)})
+		require.ErrorIs(t, err, mfa.ErrInvalidCredential)
+	})
+
+	t.Run("challenge can be answered with an assertion", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		require.Equal(t, []mfa.FactorType{mfa.FactorWebAuthn}, challenge.Factors)
+		require.NotNil(t, challenge.WebAuthn)
+		require.Len(t, challenge.WebAuthn.AllowedCredentials, 1)
+
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, WebAuthn: a.get(protocol.URLEncodedBase64("wrong-challenge"))})
+		require.ErrorIs(t, err, mfa.ErrInvalidCredential)
+
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, WebAuthn: a.get(challenge.WebAuthn.Challenge)})
+		require.NoError(t, err)
+
+		factors, err := s.GetFactors(ctx, 1)
+		require.NoError(t, err)
+		require.NotNil(t, factors[0].LastUsed)
+	})
+
+	t.Run("assertion with a signature counter that doesn't increase should fail", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		a.signCount = 0
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, WebAuthn: a.get(challenge.WebAuthn.Challenge)})
+		require.ErrorIs(t, err, mfa.ErrInvalidCredential)
+	})
+
+	t.Run("reset deletes all factors", func(t *testing.T) {
+		require.NoError(t, s.ResetFactors(ctx, 1))
+		enrolled, err := s.IsEnrolled(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, enrolled)
+		_, err = s.CreateChallenge(ctx, 1, "admin")
+		require.ErrorIs(t, err, mfa.ErrFactorNotFound)
+	})
+}
+
+func TestIntegrationEnrollmentChallenge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, now := setupTestService(t)
+	users := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1}}
+	orgs := orgtest.NewOrgServiceFake()
+	s.userService, s.orgService = users, orgs
+
+	t.Run("enrollment is only required for admins when enforced", func(t *testing.T) {
+		orgs.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleAdmin}}
+		required, err := s.EnrollmentRequired(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, required)
+
+		s.cfg.AuthMFA.EnforceForAdmins = true
+		required, err = s.EnrollmentRequired(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, required)
+
+		orgs.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleEditor}}
+		required, err = s.EnrollmentRequired(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, required)
+
+		users.ExpectedUser = &user.User{ID: 1, IsAdmin: true}
+		required, err = s.EnrollmentRequired(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, required)
+	})
+
+	t.Run("regular challenge can't be used to enroll", func(t *testing.T) {
+		_, err := s.EnrollChallengeTOTP(ctx, "unknown")
+		require.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	challenge, err := s.CreateEnrollmentChallenge(ctx, 1, "admin")
+	require.NoError(t, err)
+	require.True(t, challenge.EnrollmentRequired)
+	require.Equal(t, []mfa.FactorType{mfa.FactorTOTP}, challenge.Factors)
+
+	_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000"})
+	require.ErrorIs(t, err, mfa.ErrNoPendingEnrollment)
+
+	enrollment, err := s.EnrollChallengeTOTP(ctx, challenge.Token)
+	require.NoError(t, err)
+
+	_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, RecoveryCode: "recovery"})
+	require.ErrorIs(t, err, mfa.ErrInvalidCode)
+
+	verified, err := s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: totpCodeAt(t, enrollment.Secret, *now)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), verified.UserID)
+
+	enrolled, err := s.IsEnrolled(ctx, 1)
+	require.NoError(t, err)
+	require.True(t, enrolled)
+
+	required, err := s.EnrollmentRequired(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, required)
+
+	t.Run("enrolled user can't enroll with a challenge", func(t *testing.T) {
+		challenge, err := s.CreateChallenge(ctx, 1, "admin")
+		require.NoError(t, err)
+		_, err = s.EnrollChallengeTOTP(ctx, challenge.Token)
+		require.ErrorIs(t, err, mfa.ErrNotEnrollment)
+	})
+}
@@ -0,0 +1,82 @@
+package mfaimpl
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+type factor struct {
+	ID     int64          `xorm:"pk autoincr 'id'"`
+	UserID int64          `xorm:"user_id"`
+	Type   mfa.FactorType `xorm:"type"`
+	Name   string         `xorm:"name"`
+	// Secret is the encrypted and base64 encoded TOTP secret.
+	Secret string `xorm:"secret"`
+	// CredentialID is the base64url encoded ID of a WebAuthn credential.
+	CredentialID string `xorm:"credential_id"`
+	// PublicKey is the base64 encoded COSE public key of a WebAuthn credential.
+	PublicKey string `xorm:"public_key"`
+	// Counter is the signature counter of a WebAuthn credential, or the time step
+	// of the last accepted TOTP code so that codes can't be used twice.
+	Counter  int64      `xorm:"counter"`
+	Verified bool       `xorm:"verified"`
+	Created  time.Time  `xorm:"created"`
+	Updated  time.Time  `xorm:"updated"`
+	LastUsed *time.Time `xorm:"last_used"`
+}
+
+func (f *factor) TableName() string {
+	return "user_auth_factor"
+}
+
+func (f *factor) toDTO() *mfa.FactorDTO {
+	return &mfa.FactorDTO{
+		ID:       f.ID,
+		Type:     f.Type,
+		Name:     f.Name,
+		Created:  f.Created,
+		LastUsed: f.LastUsed,
+	}
+}
+
+type recoveryCode struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	UserID   int64     `xorm:"user_id"`
+	CodeHash string    `xorm:"code_hash"`
+	Created  time.Time `xorm:"created"`
+}
+
+func (c *recoveryCode) TableName() string {
+	return "user_auth_recovery_code"
+}
+
+// challengeEntry is the state of a login challenge kept in the remote cache.
+type challengeEntry struct {
+	UserID          int64                 `json:"userId"`
+	Login           string                `json:"login"`
+	WebAuthnSession *webauthn.SessionData `json:"webauthnSession,omitempty"`
+	// Enrollment is set for challenges that are answered by enrolling an authenticator app.
+	Enrollment bool      `json:"enrollment,omitempty"`
+	Expires    time.Time `json:"expires"`
+}
+
+// challengeAttempts counts the attempts to answer a login challenge.
+type challengeAttempts struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	TokenHash string    `xorm:"token_hash"`
+	Attempts  int       `xorm:"attempts"`
+	Expires   time.Time `xorm:"expires"`
+}
+
+func (c *challengeAttempts) TableName() string {
+	return "user_auth_challenge"
+}
+
+// registrationEntry is the state of a WebAuthn registration kept in the remote cache.
+type registrationEntry struct {
+	Login   string                `json:"login"`
+	Session *webauthn.SessionData `json:"session"`
+}
@@ -0,0 +1,191 @@
+package mfaimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+type store interface {
+	// GetFactors returns all factors of the user, including factors that are not verified yet.
+	GetFactors(ctx context.Context, userID int64) ([]*factor, error)
+	CreateFactor(ctx context.Context, f *factor) error
+	// VerifyFactor marks a pending factor as verified.
+	VerifyFactor(ctx context.Context, factorID int64, counter int64) error
+	// UpdateCounter sets the counter and last used time of a factor if the counter is
+	// still the expected one. It returns false if the factor was used concurrently.
+	UpdateCounter(ctx context.Context, factorID int64, expected, counter int64) (bool, error)
+	DeleteFactor(ctx context.Context, userID, factorID int64) (bool, error)
+	DeletePendingFactors(ctx context.Context, userID int64, factorType mfa.FactorType) error
+	// DeleteAll deletes all factors and recovery codes of the user.
+	DeleteAll(ctx context.Context, userID int64) error
+
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error
+	// DeleteRecoveryCode deletes a recovery code and returns false if it doesn't exist.
+	DeleteRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, userID int64) error
+
+	// CreateChallengeAttempts starts counting the attempts of a challenge and deletes the
+	// attempts of expired challenges.
+	CreateChallengeAttempts(ctx context.Context, tokenHash string, expires time.Time) error
+	// AddChallengeAttempt counts an attempt if the challenge has attempts left and returns
+	// the number of attempts left after it. It returns false if no attempts are left.
+	AddChallengeAttempt(ctx context.Context, tokenHash string, maxAttempts int) (int, bool, error)
+	DeleteChallengeAttempts(ctx context.Context, tokenHash string) error
+}
+
+type xormStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *xormStore) GetFactors(ctx context.Context, userID int64) ([]*factor, error) {
+	factors := make([]*factor, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("user_id = ?", userID).Asc("id").Find(&factors)
+	})
+	return factors, err
+}
+
+func (s *xormStore) CreateFactor(ctx context.Context, f *factor) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		f.Created = s.now()
+		f.Updated = f.Created
+		_, err := sess.Insert(f)
+		return err
+	})
+}
+
+func (s *xormStore) VerifyFactor(ctx context.Context, factorID int64, counter int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(factorID).Cols("verified", "counter", "updated").Update(&factor{
+			Verified: true,
+			Counter:  counter,
+			Updated:  s.now(),
+		})
+		return err
+	})
+}
+
+func (s *xormStore) UpdateCounter(ctx context.Context, factorID int64, expected, counter int64) (bool, error) {
+	var updated bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		now := s.now()
+		res, err := sess.Exec("UPDATE user_auth_factor SET counter = ?, last_used = ?, updated = ? WHERE id = ? AND counter = ?",
+			counter, now, now, factorID, expected)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		updated = rows > 0
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) DeleteFactor(ctx context.Context, userID, factorID int64) (bool, error) {
+	var deleted bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("id = ? AND user_id = ?", factorID, userID).Delete(&factor{})
+		deleted = rows > 0
+		return err
+	})
+	return deleted, err
+}
+
+func (s *xormStore) DeletePendingFactors(ctx context.Context, userID int64, factorType mfa.FactorType) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_auth_factor WHERE user_id = ? AND type = ? AND verified = ?", userID, factorType, false)
+		return err
+	})
+}
+
+func (s *xormStore) DeleteAll(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_auth_factor WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM user_auth_recovery_code WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *xormStore) ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_auth_recovery_code WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		now := s.now()
+		codes := make([]*recoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, &recoveryCode{UserID: userID, CodeHash: hash, Created: now})
+		}
+		_, err := sess.InsertMulti(codes)
+		return err
+	})
+}
+
+func (s *xormStore) DeleteRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error) {
+	var deleted bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_auth_recovery_code WHERE user_id = ? AND code_hash = ?", userID, hash)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		deleted = rows > 0
+		return err
+	})
+	return deleted, err
+}
+
+func (s *xormStore) DeleteRecoveryCodes(ctx context.Context, userID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_auth_recovery_code WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *xormStore) CreateChallengeAttempts(ctx context.Context, tokenHash string, expires time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_auth_challenge WHERE expires <= ?", s.now()); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&challengeAttempts{TokenHash: tokenHash, Expires: expires})
+		return err
+	})
+}
+
+func (s *xormStore) AddChallengeAttempt(ctx context.Context, tokenHash string, maxAttempts int) (int, bool, error) {
+	var (
+		left  int
+		added bool
+	)
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_auth_challenge SET attempts = attempts + 1 WHERE token_hash = ? AND attempts < ?", tokenHash, maxAttempts)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil || rows == 0 {
+			return err
+		}
+
+		attempts := challengeAttempts{}
+		if _, err := sess.Where("token_hash = ?", tokenHash).Get(&attempts); err != nil {
+			return err
+		}
+		left, added = maxAttempts-attempts.Attempts, true
+		return nil
+	})
+	return left, added, err
+}
+
+func (s *xormStore) DeleteChallengeAttempts(ctx context.Context, tokenHash string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_auth_challenge WHERE token_hash = ?", tokenHash)
+		return err
+	})
+}
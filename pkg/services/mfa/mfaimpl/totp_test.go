@@ -0,0 +1,57 @@
+package mfaimpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// Test vectors from RFC 6238 appendix B, truncated to 6 digits.
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		step, ok := validateTOTP(secret, tc.code, time.Unix(tc.unix, 0), 0)
+		require.True(t, ok, tc.unix)
+		require.Equal(t, tc.unix/totpPeriod, step)
+	}
+
+	t.Run("accepts codes of adjacent time steps", func(t *testing.T) {
+		_, ok := validateTOTP(secret, "287082", time.Unix(59+totpPeriod, 0), 0)
+		require.True(t, ok)
+		_, ok = validateTOTP(secret, "287082", time.Unix(59+2*totpPeriod, 0), 0)
+		require.False(t, ok)
+	})
+
+	t.Run("rejects codes of used time steps", func(t *testing.T) {
+		_, ok := validateTOTP(secret, "287082", time.Unix(59, 0), 59/totpPeriod)
+		require.False(t, ok)
+	})
+
+	t.Run("accepts lower case secrets and codes with spaces", func(t *testing.T) {
+		_, ok := validateTOTP(strings.ToLower(secret), " 287 082", time.Unix(59, 0), 0)
+		require.True(t, ok)
+	})
+
+	t.Run("rejects invalid codes", func(t *testing.T) {
+		for _, code := range []string{"", "28708", "2870820", "287083"} {
+			_, ok := validateTOTP(secret, code, time.Unix(59, 0), 0)
+			require.False(t, ok, code)
+		}
+	})
+}
+
+func TestTOTPURL(t *testing.T) {
+	url := totpURL("Grafana", "admin", "JBSWY3DPEHPK3PXP")
+	require.Equal(t, "otpauth://totp/Grafana:admin?algorithm=SHA1&digits=6&issuer=Grafana&period=30&secret=JBSWY3DPEHPK3PXP", url)
+}
@@ -0,0 +1,124 @@
+package mfaimpl
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+// Registration and assertion ceremonies are verified with go-webauthn. Attestation
+// is not requested, so any authenticator can be registered.
+
+// newWebAuthn returns the relying party for the configured ID and origins. It is created
+// per ceremony so that an invalid configuration only affects WebAuthn.
+func (s *Service) newWebAuthn() (*webauthn.WebAuthn, error) {
+	return webauthn.New(&webauthn.Config{
+		RPID:                  s.cfg.AuthMFA.WebAuthnRPID,
+		RPDisplayName:         s.cfg.AuthMFA.TOTPIssuer,
+		RPOrigins:             s.cfg.AuthMFA.WebAuthnRPOrigins,
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyNotRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementDiscouraged,
+			UserVerification:   protocol.VerificationDiscouraged,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login: webauthn.TimeoutConfig{
+				Enforce:    true,
+				Timeout:    s.cfg.AuthMFA.ChallengeExpiration,
+				TimeoutUVD: s.cfg.AuthMFA.ChallengeExpiration,
+			},
+			Registration: webauthn.TimeoutConfig{
+				Enforce:    true,
+				Timeout:    webAuthnRegistrationTTL,
+				TimeoutUVD: webAuthnRegistrationTTL,
+			},
+		},
+	})
+}
+
+var _ webauthn.User = (*webAuthnUser)(nil)
+
+// webAuthnUser is a user together with its registered security keys.
+type webAuthnUser struct {
+	id          int64
+	login       string
+	credentials []webauthn.Credential
+	// factors are the factors of the credentials, by base64url encoded credential ID.
+	factors map[string]*factor
+}
+
+func newWebAuthnUser(userID int64, login string, factors []*factor) (*webAuthnUser, error) {
+	u := &webAuthnUser{id: userID, login: login, factors: map[string]*factor{}}
+	for _, f := range factors {
+		if f.Type != mfa.FactorWebAuthn || !f.Verified {
+			continue
+		}
+		id, err := decodeBase64URL(f.CredentialID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode credential ID of factor %d: %w", f.ID, err)
+		}
+		publicKey, err := base64.StdEncoding.DecodeString(f.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode public key of factor %d: %w", f.ID, err)
+		}
+		u.credentials = append(u.credentials, webauthn.Credential{
+			ID:            id,
+			PublicKey:     publicKey,
+			Authenticator: webauthn.Authenticator{SignCount: uint32(f.Counter)},
+		})
+		u.factors[encodeBase64URL(id)] = f
+	}
+	return u, nil
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatInt(u.id, 10))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.login
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.login
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) credentialDescriptors() []protocol.CredentialDescriptor {
+	descriptors := make([]protocol.CredentialDescriptor, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		descriptors = append(descriptors, c.Descriptor())
+	}
+	return descriptors
+}
+
+// invalidCredential wraps errors of go-webauthn, which describe why a ceremony failed.
+func invalidCredential(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		return mfa.ErrInvalidCredential.Errorf("%s: %s %s", protocolErr.Type, protocolErr.Details, protocolErr.DevInfo)
+	}
+	return mfa.ErrInvalidCredential.Errorf("%w", err)
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
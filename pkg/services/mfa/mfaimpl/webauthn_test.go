@@ -0,0 +1,143 @@
+package mfaimpl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+const (
+	testRPID   = "grafana.example.com"
+	testOrigin = "https://grafana.example.com"
+
+	flagUserPresent  = 0x01
+	flagAttestedData = 0x40
+)
+
+// fakeAuthenticator is a P-256 security key that creates credentials and assertions
+// like a browser would.
+type fakeAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+	rpID         string
+	origin       string
+}
+
+func newFakeAuthenticator(t *testing.T) *fakeAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &fakeAuthenticator{t: t, key: key, credentialID: []byte("credential-" + t.Name()), rpID: testRPID, origin: testOrigin}
+}
+
+func (a *fakeAuthenticator) clientData(typ, challenge string) []byte {
+	data, err := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": a.origin})
+	require.NoError(a.t, err)
+	return data
+}
+
+func (a *fakeAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := slices.Concat(rpIDHash[:], []byte{flags}, binary.BigEndian.AppendUint32(nil, a.signCount))
+	if flags&flagAttestedData == 0 {
+		return data
+	}
+
+	// EC2 key type, ES256 algorithm and the P-256 curve
+	coseKey, err := cbor.Marshal(map[int]any{
+		1:  2,
+		3:  -7,
+		-1: 1,
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(a.t, err)
+
+	aaguid := make([]byte, 16)
+	idLen := binary.BigEndian.AppendUint16(nil, uint16(len(a.credentialID)))
+	return slices.Concat(data, aaguid, idLen, a.credentialID, coseKey)
+}
+
+func (a *fakeAuthenticator) credential(response map[string]string) json.RawMessage {
+	id := encodeBase64URL(a.credentialID)
+	data, err := json.Marshal(map[string]any{"id": id, "rawId": id, "type": "public-key", "response": response})
+	require.NoError(a.t, err)
+	return data
+}
+
+func (a *fakeAuthenticator) create(challenge protocol.URLEncodedBase64) json.RawMessage {
+	attestation, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(flagUserPresent | flagAttestedData),
+	})
+	require.NoError(a.t, err)
+
+	return a.credential(map[string]string{
+		"clientDataJSON":    encodeBase64URL(a.clientData("webauthn.create", encodeBase64URL(challenge))),
+		"attestationObject": encodeBase64URL(attestation),
+	})
+}
+
+func (a *fakeAuthenticator) get(challenge protocol.URLEncodedBase64) json.RawMessage {
+	a.signCount++
+	authData := a.authData(flagUserPresent)
+	clientDataJSON := a.clientData("webauthn.get", encodeBase64URL(challenge))
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(slices.Concat(authData, clientDataHash[:]))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+
+	return a.credential(map[string]string{
+		"clientDataJSON":    encodeBase64URL(clientDataJSON),
+		"authenticatorData": encodeBase64URL(authData),
+		"signature":         encodeBase64URL(sig),
+	})
+}
+
+func TestNewWebAuthnUser(t *testing.T) {
+	factors := []*factor{
+		{ID: 1, Type: mfa.FactorTOTP, Verified: true},
+		{ID: 2, Type: mfa.FactorWebAuthn, Verified: true, CredentialID: encodeBase64URL([]byte("key")), PublicKey: base64.StdEncoding.EncodeToString([]byte("public")), Counter: 3},
+		{ID: 3, Type: mfa.FactorWebAuthn, Verified: false, CredentialID: encodeBase64URL([]byte("pending"))},
+	}
+
+	u, err := newWebAuthnUser(1, "admin", factors)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), u.WebAuthnID())
+	require.Equal(t, "admin", u.WebAuthnName())
+	require.Len(t, u.WebAuthnCredentials(), 1)
+	require.Equal(t, []byte("key"), u.WebAuthnCredentials()[0].ID)
+	require.Equal(t, uint32(3), u.WebAuthnCredentials()[0].Authenticator.SignCount)
+	require.Equal(t, int64(2), u.factors[encodeBase64URL([]byte("key"))].ID)
+
+	descriptors := u.credentialDescriptors()
+	require.Len(t, descriptors, 1)
+	require.Equal(t, protocol.URLEncodedBase64("key"), descriptors[0].CredentialID)
+
+	t.Run("invalid credential ID should fail", func(t *testing.T) {
+		_, err := newWebAuthnUser(1, "admin", []*factor{{ID: 4, Type: mfa.FactorWebAuthn, Verified: true, CredentialID: "%%"}})
+		require.Error(t, err)
+	})
+}
+
+func TestInvalidCredential(t *testing.T) {
+	err := invalidCredential(protocol.ErrVerification.WithDetails("Error validating origin"))
+	require.ErrorIs(t, err, mfa.ErrInvalidCredential)
+	require.Contains(t, err.Error(), "Error validating origin")
+}
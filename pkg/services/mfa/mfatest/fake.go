@@ -0,0 +1,89 @@
+package mfatest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+var _ mfa.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedEnrolled       bool
+	ExpectedEnrollRequired bool
+	ExpectedChallenge      *mfa.Challenge
+	ExpectedFactors        []*mfa.FactorDTO
+	ExpectedTOTPEnrollment *mfa.TOTPEnrollment
+	ExpectedEnrollment     *mfa.EnrollmentResult
+	ExpectedCreationOpts   *mfa.WebAuthnCreationOptions
+	ExpectedRecoveryCodes  []string
+	ExpectedErr            error
+	// ExpectedVerifyErr is returned by VerifyChallenge instead of ExpectedErr.
+	ExpectedVerifyErr error
+
+	ResetUserID int64
+}
+
+func (f *FakeService) IsEnrolled(ctx context.Context, userID int64) (bool, error) {
+	return f.ExpectedEnrolled, f.ExpectedErr
+}
+
+func (f *FakeService) CreateChallenge(ctx context.Context, userID int64, login string) (*mfa.Challenge, error) {
+	return f.ExpectedChallenge, f.ExpectedErr
+}
+
+func (f *FakeService) GetChallenge(ctx context.Context, token string) (*mfa.Challenge, error) {
+	return f.ExpectedChallenge, f.ExpectedErr
+}
+
+func (f *FakeService) VerifyChallenge(ctx context.Context, cmd *mfa.VerifyChallengeCommand) (*mfa.Challenge, error) {
+	if f.ExpectedVerifyErr != nil {
+		return nil, f.ExpectedVerifyErr
+	}
+	return f.ExpectedChallenge, f.ExpectedErr
+}
+
+func (f *FakeService) EnrollmentRequired(ctx context.Context, userID int64) (bool, error) {
+	return f.ExpectedEnrollRequired, f.ExpectedErr
+}
+
+func (f *FakeService) CreateEnrollmentChallenge(ctx context.Context, userID int64, login string) (*mfa.Challenge, error) {
+	return f.ExpectedChallenge, f.ExpectedErr
+}
+
+func (f *FakeService) EnrollChallengeTOTP(ctx context.Context, token string) (*mfa.TOTPEnrollment, error) {
+	return f.ExpectedTOTPEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) GetFactors(ctx context.Context, userID int64) ([]*mfa.FactorDTO, error) {
+	return f.ExpectedFactors, f.ExpectedErr
+}
+
+func (f *FakeService) EnrollTOTP(ctx context.Context, userID int64, login string) (*mfa.TOTPEnrollment, error) {
+	return f.ExpectedTOTPEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) VerifyTOTPEnrollment(ctx context.Context, userID int64, code string) (*mfa.EnrollmentResult, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) BeginWebAuthnRegistration(ctx context.Context, userID int64, login string) (*mfa.WebAuthnCreationOptions, error) {
+	return f.ExpectedCreationOpts, f.ExpectedErr
+}
+
+func (f *FakeService) FinishWebAuthnRegistration(ctx context.Context, userID int64, cmd *mfa.FinishWebAuthnRegistrationCommand) (*mfa.EnrollmentResult, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) DeleteFactor(ctx context.Context, userID, factorID int64) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedErr
+}
+
+func (f *FakeService) ResetFactors(ctx context.Context, userID int64) error {
+	f.ResetUserID = userID
+	return f.ExpectedErr
+}
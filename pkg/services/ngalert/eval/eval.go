@@ -527,7 +527,7 @@ func queryDataResponseToExecutionResults(c models.Condition, execResp *backend.Q
 
 	// add capture values as data frame metadata to each result (frame) that has matching labels.
 	for _, frame := range result.Condition {
-		// classic conditions already have metadata set and only have one value, there's no need to add anything in this case.
+		// classic conditions already have metadata set, there's no need to add anything in this case.
 		if frame.Meta != nil && frame.Meta.Custom != nil {
 			if _, ok := frame.Meta.Custom.([]classic.EvalMatch); ok {
 				continue // do not overwrite EvalMatch from classic condition.
@@ -0,0 +1,502 @@
+// Package chart draws the query results of alert rules as time series or stat
+// charts, so that notifications can include an image without the image renderer.
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// Series is a time series, null values are NaN.
+type Series struct {
+	Name   string
+	Times  []time.Time
+	Values []float64
+}
+
+// Stat is a single value, such as the result of a reduce expression.
+type Stat struct {
+	Name  string
+	Value float64
+}
+
+// Threshold is the condition of a threshold expression, such as "gt" with the
+// param 80. Range conditions have two params.
+type Threshold struct {
+	Type   string
+	Params []float64
+}
+
+// Breached returns true if the value meets the condition of the threshold.
+func (t Threshold) Breached(v float64) bool {
+	if len(t.Params) == 0 || math.IsNaN(v) {
+		return false
+	}
+	p := t.Params[0]
+	lo, hi := p, p
+	if len(t.Params) > 1 {
+		lo, hi = math.Min(t.Params[0], t.Params[1]), math.Max(t.Params[0], t.Params[1])
+	}
+
+	switch t.Type {
+	case "gt":
+		return v > p
+	case "lt":
+		return v < p
+	case "gte":
+		return v >= p
+	case "lte":
+		return v <= p
+	case "eq":
+		return v == p
+	case "ne":
+		return v != p
+	case "within_range":
+		return v > lo && v < hi
+	case "outside_range":
+		return v < lo || v > hi
+	case "within_range_included":
+		return v >= lo && v <= hi
+	case "outside_range_included":
+		return v <= lo || v >= hi
+	}
+	return false
+}
+
+func (t Threshold) String() string {
+	params := make([]string, 0, len(t.Params))
+	for _, p := range t.Params {
+		params = append(params, formatValue(p))
+	}
+	if len(params) == 0 {
+		return t.Type
+	}
+
+	switch t.Type {
+	case "gt":
+		return "> " + params[0]
+	case "lt":
+		return "< " + params[0]
+	case "gte":
+		return ">= " + params[0]
+	case "lte":
+		return "<= " + params[0]
+	case "eq":
+		return "= " + params[0]
+	case "ne":
+		return "!= " + params[0]
+	}
+	if len(params) > 1 {
+		switch t.Type {
+		case "within_range", "within_range_included":
+			return fmt.Sprintf("between %s and %s", params[0], params[1])
+		case "outside_range", "outside_range_included":
+			return fmt.Sprintf("outside %s and %s", params[0], params[1])
+		}
+	}
+	return fmt.Sprintf("%s %s", t.Type, params[0])
+}
+
+// Chart is drawn as a time series chart if any of its series has more than one
+// point in time, and as a stat chart of its stats otherwise.
+type Chart struct {
+	Title      string
+	Series     []Series
+	Stats      []Stat
+	Thresholds []Threshold
+}
+
+// IsTimeSeries returns true if the chart is drawn as a time series chart.
+func (c Chart) IsTimeSeries() bool {
+	for _, s := range c.Series {
+		if len(s.Times) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+type palette struct {
+	background color.RGBA
+	text       color.RGBA
+	grid       color.RGBA
+}
+
+var (
+	darkPalette  = palette{background: rgb(0x18, 0x1b, 0x1f), text: rgb(0xcc, 0xcc, 0xdc), grid: rgb(0x2c, 0x32, 0x35)}
+	lightPalette = palette{background: rgb(0xff, 0xff, 0xff), text: rgb(0x24, 0x29, 0x2e), grid: rgb(0xe4, 0xe7, 0xe7)}
+
+	// seriesColors are the classic palette of Grafana panels.
+	seriesColors = []color.RGBA{
+		rgb(0x7e, 0xb2, 0x6d), rgb(0xea, 0xb8, 0x39), rgb(0x6e, 0xd0, 0xe0), rgb(0xef, 0x84, 0x3c),
+		rgb(0xe2, 0x4d, 0x42), rgb(0x1f, 0x78, 0xc1), rgb(0xba, 0x43, 0xa9), rgb(0x70, 0x5d, 0xa0),
+	}
+	thresholdColor = rgb(0xf2, 0x49, 0x5c)
+	okColor        = rgb(0x73, 0xbf, 0x69)
+)
+
+const (
+	padding   = 16
+	maxStats  = 12
+	statCols  = 4
+	lineWidth = 2
+)
+
+var face = basicfont.Face7x13
+
+// Draw draws the chart on an image of the given size.
+func (c Chart) Draw(width, height int, theme models.Theme) *image.RGBA {
+	p := darkPalette
+	if theme == models.ThemeLight {
+		p = lightPalette
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), p.background)
+
+	title := truncateText(c.Title, width-2*padding, 2)
+	drawText(img, padding, padding, title, p.text, 2)
+
+	top := padding + 2*face.Height + padding/2
+	legendTop := height - padding - face.Height
+	area := image.Rect(padding, top, width-padding, legendTop-padding/2)
+
+	if c.IsTimeSeries() {
+		c.drawLegend(img, padding, legendTop, width-2*padding, p)
+		c.drawTimeSeries(img, area, p)
+	} else {
+		if len(c.Thresholds) > 0 {
+			drawText(img, padding, legendTop, truncateText("Threshold: "+c.thresholdText(), width-2*padding, 1), p.text, 1)
+		}
+		c.drawStats(img, area, p)
+	}
+	return img
+}
+
+func (c Chart) thresholdText() string {
+	s := ""
+	for i, t := range c.Thresholds {
+		if i > 0 {
+			s += ", "
+		}
+		s += t.String()
+	}
+	return s
+}
+
+func (c Chart) drawLegend(img *image.RGBA, x, y, width int, p palette) {
+	right := x + width
+	for i, s := range c.Series {
+		if i > 0 {
+			more := fmt.Sprintf("+%d more", len(c.Series)-i)
+			itemWidth := 14 + textWidth(s.Name, 1)
+			// Keep room for the number of series that don't fit.
+			if x+itemWidth > right || (i < len(c.Series)-1 && x+itemWidth+16+textWidth(more, 1) > right) {
+				drawText(img, x, y, more, p.text, 1)
+				return
+			}
+		}
+		fill(img, image.Rect(x, y+2, x+10, y+12), seriesColors[i%len(seriesColors)])
+		name := truncateText(s.Name, right-x-14, 1)
+		drawText(img, x+14, y, name, p.text, 1)
+		x += 14 + textWidth(name, 1) + 16
+	}
+}
+
+func (c Chart) drawTimeSeries(img *image.RGBA, area image.Rectangle, p palette) {
+	minT, maxT := time.Time{}, time.Time{}
+	minV, maxV := math.Inf(1), math.Inf(-1)
+	for _, s := range c.Series {
+		for i, t := range s.Times {
+			if minT.IsZero() || t.Before(minT) {
+				minT = t
+			}
+			if maxT.IsZero() || t.After(maxT) {
+				maxT = t
+			}
+			if i < len(s.Values) && !math.IsNaN(s.Values[i]) && !math.IsInf(s.Values[i], 0) {
+				minV, maxV = math.Min(minV, s.Values[i]), math.Max(maxV, s.Values[i])
+			}
+		}
+	}
+	if math.IsInf(minV, 1) {
+		drawCentered(img, area, "No data", p.text, 2)
+		return
+	}
+	for _, t := range c.Thresholds {
+		for _, v := range t.Params {
+			minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+		}
+	}
+	if !maxT.After(minT) {
+		minT, maxT = minT.Add(-time.Minute), maxT.Add(time.Minute)
+	}
+
+	step := niceStep((maxV - minV) / 5)
+	lo, hi := math.Floor(minV/step)*step, math.Ceil(maxV/step)*step
+	if hi <= lo {
+		hi = lo + step
+	}
+
+	// The y-axis labels are right aligned on the left of the plot.
+	var ticks []float64
+	labelWidth := 0
+	for v := lo; v <= hi+step/2; v += step {
+		ticks = append(ticks, v)
+		labelWidth = max(labelWidth, textWidth(formatTick(v, step), 1))
+	}
+	plot := image.Rect(area.Min.X+labelWidth+8, area.Min.Y+face.Height/2, area.Max.X, area.Max.Y-face.Height-6)
+	if plot.Dx() <= 0 || plot.Dy() <= 0 {
+		return
+	}
+
+	toX := func(t time.Time) int {
+		return plot.Min.X + int(math.Round(float64(t.Sub(minT))/float64(maxT.Sub(minT))*float64(plot.Dx())))
+	}
+	toY := func(v float64) int {
+		return plot.Max.Y - int(math.Round((v-lo)/(hi-lo)*float64(plot.Dy())))
+	}
+
+	for _, v := range ticks {
+		y := toY(v)
+		fill(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), p.grid)
+		label := formatTick(v, step)
+		drawText(img, plot.Min.X-8-textWidth(label, 1), y-face.Height/2, label, p.text, 1)
+	}
+
+	tStep, layout := timeStep(maxT.Sub(minT), plot.Dx())
+	for t := minT.Truncate(tStep); !t.After(maxT); t = t.Add(tStep) {
+		if t.Before(minT) {
+			continue
+		}
+		x := toX(t)
+		fill(img, image.Rect(x, plot.Min.Y, x+1, plot.Max.Y), p.grid)
+		label := t.UTC().Format(layout)
+		lx := min(max(x-textWidth(label, 1)/2, area.Min.X), area.Max.X-textWidth(label, 1))
+		drawText(img, lx, plot.Max.Y+6, label, p.text, 1)
+	}
+
+	clipped := img.SubImage(plot.Inset(-lineWidth)).(*image.RGBA)
+	for i, s := range c.Series {
+		col := seriesColors[i%len(seriesColors)]
+		prevX, prevY, hasPrev := 0, 0, false
+		for j, t := range s.Times {
+			if j >= len(s.Values) || math.IsNaN(s.Values[j]) || math.IsInf(s.Values[j], 0) {
+				hasPrev = false
+				continue
+			}
+			x, y := toX(t), toY(s.Values[j])
+			if hasPrev {
+				drawLine(clipped, prevX, prevY, x, y, col)
+			} else {
+				drawLine(clipped, x, y, x, y, col)
+			}
+			prevX, prevY, hasPrev = x, y, true
+		}
+	}
+
+	for _, t := range c.Thresholds {
+		for _, v := range t.Params {
+			y := toY(v)
+			for x := plot.Min.X; x < plot.Max.X; x += 12 {
+				fill(img, image.Rect(x, y-1, min(x+8, plot.Max.X), y+1), thresholdColor)
+			}
+		}
+	}
+}
+
+func (c Chart) drawStats(img *image.RGBA, area image.Rectangle, p palette) {
+	stats := c.Stats
+	if len(stats) == 0 {
+		drawCentered(img, area, "No data", p.text, 2)
+		return
+	}
+	if len(stats) > maxStats {
+		stats = stats[:maxStats]
+	}
+
+	cols := min(len(stats), statCols)
+	rows := (len(stats) + cols - 1) / cols
+	cellWidth, cellHeight := area.Dx()/cols, area.Dy()/rows
+
+	for i, s := range stats {
+		x, y := area.Min.X+(i%cols)*cellWidth, area.Min.Y+(i/cols)*cellHeight
+		cell := image.Rect(x, y, x+cellWidth, y+cellHeight).Inset(4)
+
+		col := seriesColors[i%len(seriesColors)]
+		if len(c.Thresholds) > 0 {
+			col = okColor
+			for _, t := range c.Thresholds {
+				if t.Breached(s.Value) {
+					col = thresholdColor
+				}
+			}
+		}
+		fill(img, cell, p.grid)
+
+		value := formatValue(s.Value)
+		scale := 8
+		for scale > 1 && (textWidth(value, scale) > cell.Dx()-16 || scale*face.Height > cell.Dy()-face.Height-16) {
+			scale--
+		}
+		valueArea := image.Rect(cell.Min.X, cell.Min.Y, cell.Max.X, cell.Max.Y-face.Height-8)
+		drawCentered(img, valueArea, value, col, scale)
+
+		name := truncateText(s.Name, cell.Dx()-16, 1)
+		drawText(img, cell.Min.X+(cell.Dx()-textWidth(name, 1))/2, cell.Max.Y-face.Height-8, name, p.text, 1)
+	}
+}
+
+// niceStep rounds the step to 1, 2 or 5 times a power of ten.
+func niceStep(step float64) float64 {
+	if step <= 0 || math.IsNaN(step) || math.IsInf(step, 0) {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(step)))
+	for _, m := range []float64{1, 2, 5} {
+		if step <= m*magnitude {
+			return m * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+var timeSteps = []time.Duration{
+	time.Second, 5 * time.Second, 15 * time.Second, 30 * time.Second,
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour,
+}
+
+// timeStep returns the step between the ticks of the time axis and the layout of their
+// labels, so that the labels of the ticks don't overlap.
+func timeStep(span time.Duration, width int) (time.Duration, string) {
+	layout := "15:04"
+	if span >= 3*24*time.Hour {
+		layout = "01/02"
+	} else if span >= 24*time.Hour {
+		layout = "01/02 15:04"
+	}
+	maxTicks := max(width/(textWidth(layout, 1)+32), 1)
+
+	for _, step := range timeSteps {
+		if span/step <= time.Duration(maxTicks) {
+			if step < time.Minute {
+				layout = "15:04:05"
+			}
+			return step, layout
+		}
+	}
+	return timeSteps[len(timeSteps)-1], layout
+}
+
+func formatTick(v, step float64) string {
+	if math.Abs(v) >= 1e6 {
+		return formatValue(v)
+	}
+	decimals := max(0, min(6, int(-math.Floor(math.Log10(step)))))
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "No value"
+	case math.Abs(v) >= 1e6 || (v != 0 && math.Abs(v) < 1e-3):
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	default:
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	}
+}
+
+func rgb(r, g, b uint8) color.RGBA {
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawLine draws a line with the Bresenham algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		fill(img, image.Rect(x0-lineWidth/2, y0-lineWidth/2, x0-lineWidth/2+lineWidth, y0-lineWidth/2+lineWidth), c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func textWidth(s string, scale int) int {
+	return font.MeasureString(face, s).Ceil() * scale
+}
+
+// truncateText shortens the text with an ellipsis so that it fits the width.
+func truncateText(s string, width, scale int) string {
+	if textWidth(s, scale) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", scale) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// drawText draws the text with its top left corner at x, y. The font has a fixed size,
+// larger text is scaled up.
+func drawText(img *image.RGBA, x, y int, s string, c color.Color, scale int) {
+	if s == "" {
+		return
+	}
+	if scale <= 1 {
+		d := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y+face.Ascent)}
+		d.DrawString(s)
+		return
+	}
+
+	text := image.NewRGBA(image.Rect(0, 0, textWidth(s, 1), face.Height))
+	d := font.Drawer{Dst: text, Src: image.NewUniform(c), Face: face, Dot: fixed.P(0, face.Ascent)}
+	d.DrawString(s)
+	dst := image.Rect(x, y, x+text.Bounds().Dx()*scale, y+text.Bounds().Dy()*scale)
+	draw.NearestNeighbor.Scale(img, dst, text, text.Bounds(), draw.Over, nil)
+}
+
+func drawCentered(img *image.RGBA, area image.Rectangle, s string, c color.Color, scale int) {
+	x := area.Min.X + (area.Dx()-textWidth(s, scale))/2
+	y := area.Min.Y + (area.Dy()-face.Height*scale)/2
+	drawText(img, x, y, s, c, scale)
+}
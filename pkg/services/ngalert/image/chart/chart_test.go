@@ -0,0 +1,128 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestThresholdBreached(t *testing.T) {
+	tests := []struct {
+		threshold Threshold
+		value     float64
+		expected  bool
+	}{
+		{Threshold{Type: "gt", Params: []float64{80}}, 81, true},
+		{Threshold{Type: "gt", Params: []float64{80}}, 80, false},
+		{Threshold{Type: "gte", Params: []float64{80}}, 80, true},
+		{Threshold{Type: "lt", Params: []float64{10}}, 5, true},
+		{Threshold{Type: "lte", Params: []float64{10}}, 11, false},
+		{Threshold{Type: "eq", Params: []float64{1}}, 1, true},
+		{Threshold{Type: "ne", Params: []float64{1}}, 1, false},
+		{Threshold{Type: "within_range", Params: []float64{10, 5}}, 7, true},
+		{Threshold{Type: "within_range", Params: []float64{5, 10}}, 10, false},
+		{Threshold{Type: "within_range_included", Params: []float64{5, 10}}, 10, true},
+		{Threshold{Type: "outside_range", Params: []float64{5, 10}}, 11, true},
+		{Threshold{Type: "outside_range_included", Params: []float64{5, 10}}, 5, true},
+		{Threshold{Type: "gt", Params: []float64{80}}, math.NaN(), false},
+		{Threshold{Type: "no_value"}, 1, false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, tc.threshold.Breached(tc.value), "%s %v", tc.threshold, tc.value)
+	}
+
+	assert.Equal(t, "> 80", Threshold{Type: "gt", Params: []float64{80}}.String())
+	assert.Equal(t, "between 5 and 10.5", Threshold{Type: "within_range", Params: []float64{5, 10.5}}.String())
+}
+
+func TestDraw(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	series := Series{Name: "{host=a}"}
+	for i := 0; i < 60; i++ {
+		series.Times = append(series.Times, start.Add(time.Duration(i)*time.Minute))
+		series.Values = append(series.Values, float64(i))
+	}
+	// A gap in the series is not drawn.
+	series.Values[30] = math.NaN()
+
+	t.Run("time series", func(t *testing.T) {
+		img := Chart{
+			Title:      "High CPU",
+			Series:     []Series{series},
+			Thresholds: []Threshold{{Type: "gt", Params: []float64{80}}},
+		}.Draw(1000, 500, models.ThemeDark)
+
+		require.Equal(t, image.Rect(0, 0, 1000, 500), img.Bounds())
+		assert.Equal(t, darkPalette.background, img.RGBAAt(0, 0))
+		assert.Positive(t, countColor(img, seriesColors[0]))
+		// The value axis includes the threshold above the values.
+		assert.Positive(t, countColor(img, thresholdColor))
+	})
+
+	t.Run("light theme", func(t *testing.T) {
+		img := Chart{Series: []Series{series}}.Draw(400, 300, models.ThemeLight)
+		assert.Equal(t, lightPalette.background, img.RGBAAt(0, 0))
+		assert.Zero(t, countColor(img, thresholdColor))
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		c := Chart{
+			Title:      "High CPU",
+			Series:     []Series{{Name: "{host=a}", Values: []float64{90}}},
+			Stats:      []Stat{{Name: "{host=a}", Value: 90}, {Name: "{host=b}", Value: 50}},
+			Thresholds: []Threshold{{Type: "gt", Params: []float64{80}}},
+		}
+		require.False(t, c.IsTimeSeries())
+
+		img := c.Draw(1000, 500, models.ThemeDark)
+		assert.Positive(t, countColor(img, thresholdColor))
+		assert.Positive(t, countColor(img, okColor))
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		img := Chart{Title: "High CPU"}.Draw(1000, 500, models.ThemeDark)
+		assert.Zero(t, countColor(img, seriesColors[0]))
+	})
+}
+
+func TestNiceStep(t *testing.T) {
+	assert.Equal(t, 1.0, niceStep(0.8))
+	assert.Equal(t, 2.0, niceStep(1.5))
+	assert.Equal(t, 50.0, niceStep(31))
+	assert.Equal(t, 0.1, niceStep(0.07))
+	assert.Equal(t, 1.0, niceStep(0))
+}
+
+func TestTimeStep(t *testing.T) {
+	step, layout := timeStep(time.Hour, 900)
+	assert.Equal(t, 5*time.Minute, step)
+	assert.Equal(t, "15:04", layout)
+
+	// Fewer ticks fit on narrow charts.
+	step, layout = timeStep(time.Hour, 300)
+	assert.Equal(t, 15*time.Minute, step)
+	assert.Equal(t, "15:04", layout)
+
+	step, layout = timeStep(7*24*time.Hour, 900)
+	assert.Equal(t, 24*time.Hour, step)
+	assert.Equal(t, "01/02", layout)
+}
+
+func countColor(img *image.RGBA, c color.RGBA) int {
+	n := 0
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				n++
+			}
+		}
+	}
+	return n
+}
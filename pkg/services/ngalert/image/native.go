@@ -0,0 +1,319 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image/chart"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/screenshot"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// NativeImageService draws the query results of the alert rule as a time series
+// or stat chart, with the thresholds of the rule, and saves the image in the store.
+// Unlike ScreenshotImageService it doesn't need the image renderer, and it draws
+// images of alert rules that are not associated with a dashboard panel.
+type NativeImageService struct {
+	cache        CacheService
+	evalFactory  eval.EvaluatorFactory
+	imagesDir    string
+	limiter      screenshot.RateLimiter
+	logger       log.Logger
+	now          func() time.Time
+	singleflight singleflight.Group
+	store        store.ImageStore
+	timeout      time.Duration
+	uploads      *UploadingService
+}
+
+// NewNativeImageService returns a new NativeImageService that writes the images
+// to imagesDir. The images are drawn within the limits of limiter, like screenshots.
+func NewNativeImageService(
+	cache CacheService,
+	evalFactory eval.EvaluatorFactory,
+	imagesDir string,
+	limiter screenshot.RateLimiter,
+	logger log.Logger,
+	timeout time.Duration,
+	store store.ImageStore,
+	uploads *UploadingService) ImageService {
+	return &NativeImageService{
+		cache:       cache,
+		evalFactory: evalFactory,
+		imagesDir:   imagesDir,
+		limiter:     limiter,
+		logger:      logger,
+		now:         time.Now,
+		store:       store,
+		timeout:     timeout,
+		uploads:     uploads,
+	}
+}
+
+// NewImage evaluates the queries of the alert rule and returns an image of their
+// results, or an error.
+func (s *NativeImageService) NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error) {
+	logger := s.logger.FromContext(ctx).New("rule_uid", r.UID)
+
+	// All of the alert instances of the rule share the image of an evaluation.
+	key := nativeImageKey(r)
+	if image, ok := s.cache.Get(ctx, key); ok {
+		logger.Debug("Found cached image", "token", image.Token)
+		return &image, nil
+	}
+
+	result, err, _ := s.singleflight.Do(key, func() (any, error) {
+		drawCtx, cancelFunc := context.WithTimeout(ctx, s.timeout)
+		defer cancelFunc()
+
+		drawn, err := s.limiter.Do(drawCtx, screenshot.ScreenshotOptions{}, func(ctx context.Context, _ screenshot.ScreenshotOptions) (*screenshot.Screenshot, error) {
+			return s.draw(ctx, r)
+		})
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("Drew image", "path", drawn.Path)
+
+		return saveImage(ctx, logger, s.uploads, s.store, models.Image{Path: drawn.Path})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	image := result.(models.Image)
+	if err = s.cache.Set(ctx, key, image); err != nil {
+		logger.Warn("Failed to cache image", "token", image.Token, "error", err)
+	}
+
+	return &image, nil
+}
+
+// draw evaluates the queries of the alert rule and writes an image of their results.
+func (s *NativeImageService) draw(ctx context.Context, r *models.AlertRule) (*screenshot.Screenshot, error) {
+	evaluator, err := s.evalFactory.Create(eval.NewContext(ctx, schedule.SchedulerUserFor(r.OrgID)), r.GetEvalCondition())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evaluator: %w", err)
+	}
+	resp, err := evaluator.EvaluateRaw(ctx, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate queries: %w", err)
+	}
+
+	path, err := s.writeImage(newChart(r, resp))
+	if err != nil {
+		return nil, err
+	}
+	return &screenshot.Screenshot{Path: path}, nil
+}
+
+func (s *NativeImageService) writeImage(c chart.Chart) (string, error) {
+	if err := os.MkdirAll(s.imagesDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create images directory: %w", err)
+	}
+	name, err := util.GetRandomString(20)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.imagesDir, name+".png")
+
+	// #nosec G304 -- the path is generated above
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create image: %w", err)
+	}
+	img := c.Draw(screenshot.DefaultWidth, screenshot.DefaultHeight, screenshot.DefaultTheme)
+	if err := png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to write image: %w", err)
+	}
+	return path, f.Close()
+}
+
+func nativeImageKey(r *models.AlertRule) string {
+	h := fnv.New64()
+	_, _ = h.Write([]byte(strconv.FormatInt(r.OrgID, 10)))
+	_, _ = h.Write([]byte(r.UID))
+	_, _ = h.Write([]byte(strconv.FormatInt(r.Version, 10)))
+	return "native:" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// expressionModel are the fields of threshold and classic condition expressions
+// that are drawn on the chart.
+type expressionModel struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression"`
+	Conditions []struct {
+		Evaluator struct {
+			Type   string    `json:"type"`
+			Params []float64 `json:"params"`
+		} `json:"evaluator"`
+	} `json:"conditions"`
+}
+
+// newChart returns a chart of the results of the data queries of the rule. If they
+// aren't time series, the chart shows the values of the expressions the thresholds
+// are applied to, for example the result of a reduce expression.
+func newChart(r *models.AlertRule, resp *backend.QueryDataResponse) chart.Chart {
+	c := chart.Chart{Title: r.Title}
+
+	var statRefIDs []string
+	for _, q := range r.Data {
+		if isExpr, _ := q.IsExpression(); !isExpr {
+			for _, frame := range resp.Responses[q.RefID].Frames {
+				c.Series = append(c.Series, frameSeries(q.RefID, frame)...)
+			}
+			continue
+		}
+
+		var model expressionModel
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			continue
+		}
+		if model.Type != "threshold" && model.Type != "classic_conditions" {
+			continue
+		}
+		for _, condition := range model.Conditions {
+			if len(condition.Evaluator.Params) > 0 {
+				c.Thresholds = append(c.Thresholds, chart.Threshold{Type: condition.Evaluator.Type, Params: condition.Evaluator.Params})
+			}
+		}
+		if model.Type == "threshold" && model.Expression != "" {
+			statRefIDs = append(statRefIDs, model.Expression)
+		}
+	}
+
+	if c.IsTimeSeries() {
+		return c
+	}
+
+	for _, refID := range statRefIDs {
+		for _, frame := range resp.Responses[refID].Frames {
+			for _, s := range frameSeries(refID, frame) {
+				c.Stats = append(c.Stats, chart.Stat{Name: s.Name, Value: s.Values[len(s.Values)-1]})
+			}
+		}
+	}
+	if len(c.Stats) == 0 {
+		for _, s := range c.Series {
+			c.Stats = append(c.Stats, chart.Stat{Name: s.Name, Value: s.Values[len(s.Values)-1]})
+		}
+	}
+	return c
+}
+
+// frameSeries returns a series for every numeric field of the frame that has values.
+// Frames without a time field, such as the results of reduce expressions, return
+// series without times.
+func frameSeries(refID string, frame *data.Frame) []chart.Series {
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong {
+		if wide, err := data.LongToWide(frame, nil); err == nil {
+			frame = wide
+		}
+	}
+
+	var timeField *data.Field
+	if schema := frame.TimeSeriesSchema(); schema.Type == data.TimeSeriesTypeWide {
+		timeField = frame.Fields[schema.TimeIndex]
+	}
+
+	var result []chart.Series
+	for _, field := range frame.Fields {
+		if !field.Type().Numeric() || field.Len() == 0 {
+			continue
+		}
+
+		s := chart.Series{Name: seriesName(refID, frame, field), Values: make([]float64, 0, field.Len())}
+		for i := 0; i < field.Len(); i++ {
+			v, err := field.NullableFloatAt(i)
+			if err != nil || v == nil {
+				s.Values = append(s.Values, math.NaN())
+			} else {
+				s.Values = append(s.Values, *v)
+			}
+			if timeField != nil {
+				s.Times = append(s.Times, fieldTime(timeField, i))
+			}
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func fieldTime(f *data.Field, i int) time.Time {
+	switch t := f.At(i).(type) {
+	case time.Time:
+		return t
+	case *time.Time:
+		if t != nil {
+			return *t
+		}
+	}
+	return time.Time{}
+}
+
+func seriesName(refID string, frame *data.Frame, field *data.Field) string {
+	switch {
+	case field.Config != nil && field.Config.DisplayNameFromDS != "":
+		return field.Config.DisplayNameFromDS
+	case len(field.Labels) > 0:
+		return field.Labels.String()
+	case field.Name != "" && field.Name != data.TimeSeriesValueFieldName:
+		return field.Name
+	case frame.Name != "":
+		return frame.Name
+	}
+	return refID
+}
+
+// rendererFallbackImageService takes screenshots with the image renderer when it is
+// available, and draws the images natively when it isn't.
+type rendererFallbackImageService struct {
+	rs          rendering.Service
+	screenshots ImageService
+	native      ImageService
+}
+
+func (s *rendererFallbackImageService) NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error) {
+	if s.rs.IsAvailable(ctx) {
+		return s.screenshots.NewImage(ctx, r)
+	}
+	return s.native.NewImage(ctx, r)
+}
+
+// saveImage uploads the image if uploading is enabled, and saves it in the store.
+// Failed uploads are logged, the image is saved without a URL.
+func saveImage(ctx context.Context, logger log.Logger, uploads *UploadingService, images store.ImageStore, image models.Image) (models.Image, error) {
+	var err error
+	if uploads != nil {
+		if image, err = uploads.Upload(ctx, image); err != nil {
+			logger.Warn("Failed to upload image", "error", err)
+		} else {
+			logger.Debug("Uploaded image", "url", image.URL)
+		}
+	}
+
+	if err := images.SaveImage(ctx, &image); err != nil {
+		return models.Image{}, fmt.Errorf("failed to save image: %w", err)
+	}
+	logger.Debug("Saved image", "token", image.Token)
+	return image, nil
+}
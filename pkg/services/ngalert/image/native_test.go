@@ -0,0 +1,172 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/image/chart"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/screenshot"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func testRule() *models.AlertRule {
+	return &models.AlertRule{
+		OrgID:     1,
+		UID:       "cpu",
+		Title:     "High CPU",
+		Condition: "C",
+		Data: []models.AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"cpu"}`)},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type":"reduce","expression":"A","reducer":"last"}`)},
+			{RefID: "C", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type":"threshold","expression":"B","conditions":[{"evaluator":{"type":"gt","params":[80]}}]}`)},
+		},
+	}
+}
+
+func testResponse(points int) *backend.QueryDataResponse {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 0, points)
+	values := make([]*float64, 0, points)
+	for i := 0; i < points; i++ {
+		times = append(times, start.Add(time.Duration(i)*time.Minute))
+		values = append(values, util.Pointer(float64(70+i)))
+	}
+	if points > 1 {
+		values[0] = nil
+	}
+
+	return &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("",
+			data.NewField("Time", nil, times),
+			data.NewField("Value", data.Labels{"host": "a"}, values))}},
+		"B": {Frames: data.Frames{data.NewFrame("",
+			data.NewField("", data.Labels{"host": "a"}, []*float64{values[points-1]}))}},
+		"C": {Frames: data.Frames{data.NewFrame("",
+			data.NewField("", data.Labels{"host": "a"}, []*float64{util.Pointer(1.0)}))}},
+	}}
+}
+
+func TestNewChart(t *testing.T) {
+	t.Run("time series of the data queries", func(t *testing.T) {
+		c := newChart(testRule(), testResponse(20))
+		require.True(t, c.IsTimeSeries())
+		assert.Equal(t, "High CPU", c.Title)
+		assert.Equal(t, []chart.Threshold{{Type: "gt", Params: []float64{80}}}, c.Thresholds)
+		require.Len(t, c.Series, 1)
+		assert.Equal(t, "host=a", c.Series[0].Name)
+		assert.Len(t, c.Series[0].Times, 20)
+		assert.True(t, math.IsNaN(c.Series[0].Values[0]))
+		assert.Equal(t, 89.0, c.Series[0].Values[19])
+		assert.Empty(t, c.Stats)
+	})
+
+	t.Run("stats of the threshold input without time series", func(t *testing.T) {
+		c := newChart(testRule(), testResponse(1))
+		require.False(t, c.IsTimeSeries())
+		assert.Equal(t, []chart.Stat{{Name: "host=a", Value: 70}}, c.Stats)
+	})
+
+	t.Run("thresholds of classic conditions", func(t *testing.T) {
+		r := testRule()
+		r.Data = r.Data[:1]
+		r.Data = append(r.Data, models.AlertQuery{RefID: "C", DatasourceUID: expr.DatasourceUID,
+			Model: json.RawMessage(`{"type":"classic_conditions","conditions":[{"evaluator":{"type":"outside_range","params":[10,90]},"query":{"params":["A"]}}]}`)})
+
+		c := newChart(r, testResponse(20))
+		assert.Equal(t, []chart.Threshold{{Type: "outside_range", Params: []float64{10, 90}}}, c.Thresholds)
+		assert.Len(t, c.Series, 1)
+	})
+}
+
+func TestNativeImageService(t *testing.T) {
+	evaluator := eval_mocks.NewConditionEvaluatorMock(t)
+	evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(testResponse(20), nil).Once()
+
+	images := store.NewFakeImageStore(t)
+	dir := t.TempDir()
+	s := NewNativeImageService(NewInmemCacheService(time.Minute, prometheus.NewRegistry()), eval_mocks.NewEvaluatorFactory(evaluator),
+		dir, &screenshot.NoOpRateLimiter{}, log.NewNopLogger(), 5*time.Second, images, nil)
+
+	image, err := s.NewImage(context.Background(), testRule())
+	require.NoError(t, err)
+	require.NotEmpty(t, image.Token)
+	require.Equal(t, dir, filepath.Dir(image.Path))
+
+	f, err := os.Open(image.Path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	config, err := png.DecodeConfig(f)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, config.Width)
+	assert.Equal(t, 500, config.Height)
+
+	saved, err := images.GetImage(context.Background(), image.Token)
+	require.NoError(t, err)
+	assert.Equal(t, image.Path, saved.Path)
+
+	// The other alert instances of the rule get the same image, without evaluating
+	// the queries again.
+	cached, err := s.NewImage(context.Background(), testRule())
+	require.NoError(t, err)
+	assert.Equal(t, image.Token, cached.Token)
+}
+
+func TestNativeImageService_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	limiter := screenshot.NewMockRateLimiter(ctrl)
+	limiter.EXPECT().Do(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, context.DeadlineExceeded)
+
+	// The queries are not evaluated while the limit of concurrent images is reached.
+	evaluator := eval_mocks.NewConditionEvaluatorMock(t)
+	s := NewNativeImageService(NewInmemCacheService(time.Minute, prometheus.NewRegistry()), eval_mocks.NewEvaluatorFactory(evaluator),
+		t.TempDir(), limiter, log.NewNopLogger(), 5*time.Second, store.NewFakeImageStore(t), nil)
+
+	_, err := s.NewImage(context.Background(), testRule())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRendererFallbackImageService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rs := rendering.NewMockService(ctrl)
+	screenshots := &fakeImageService{image: &models.Image{Token: "screenshot"}}
+	native := &fakeImageService{image: &models.Image{Token: "native"}}
+	s := &rendererFallbackImageService{rs: rs, screenshots: screenshots, native: native}
+
+	rs.EXPECT().IsAvailable(gomock.Any()).Return(true)
+	image, err := s.NewImage(context.Background(), testRule())
+	require.NoError(t, err)
+	assert.Equal(t, "screenshot", image.Token)
+
+	rs.EXPECT().IsAvailable(gomock.Any()).Return(false)
+	image, err = s.NewImage(context.Background(), testRule())
+	require.NoError(t, err)
+	assert.Equal(t, "native", image.Token)
+}
+
+type fakeImageService struct {
+	image *models.Image
+}
+
+func (s *fakeImageService) NewImage(_ context.Context, _ *models.AlertRule) (*models.Image, error) {
+	return s.image, nil
+}
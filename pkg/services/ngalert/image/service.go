@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/imguploader"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/rendering"
@@ -78,9 +79,10 @@ func NewScreenshotImageService(
 }
 
 // NewScreenshotImageServiceFromCfg returns a new ScreenshotImageService
-// from the configuration.
+// from the configuration. When screenshots are enabled and the image renderer
+// is not available, the images are drawn by a NativeImageService instead.
 func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds dashboards.DashboardService,
-	rs rendering.Service, r prometheus.Registerer) (ImageService, error) {
+	rs rendering.Service, evalFactory eval.EvaluatorFactory, r prometheus.Registerer) (ImageService, error) {
 	var (
 		cache             CacheService                 = &NoOpCacheService{}
 		limiter           screenshot.RateLimiter       = &screenshot.NoOpRateLimiter{}
@@ -106,8 +108,18 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 		}
 	}
 
-	return NewScreenshotImageService(cache, limiter, log.New("ngalert.image"),
-		screenshots, screenshotTimeout, db, uploads), nil
+	logger := log.New("ngalert.image")
+	screenshotImages := NewScreenshotImageService(cache, limiter, logger,
+		screenshots, screenshotTimeout, db, uploads)
+	if !cfg.UnifiedAlerting.Screenshots.Capture {
+		return screenshotImages, nil
+	}
+
+	return &rendererFallbackImageService{
+		rs:          rs,
+		screenshots: screenshotImages,
+		native:      NewNativeImageService(cache, evalFactory, cfg.ImagesDir, limiter, logger, screenshotTimeout, db, uploads),
+	}, nil
 }
 
 // NewImage returns a screenshot of the alert rule or an error.
@@ -172,23 +184,7 @@ func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRu
 		}
 
 		logger.Debug("Took screenshot", "path", screenshot.Path)
-		image := models.Image{Path: screenshot.Path}
-
-		// Uploading images is optional
-		if s.uploads != nil {
-			if image, err = s.uploads.Upload(ctx, image); err != nil {
-				logger.Warn("Failed to upload image", "error", err)
-			} else {
-				logger.Debug("Uploaded image", "url", image.URL)
-			}
-		}
-
-		if err := s.store.SaveImage(ctx, &image); err != nil {
-			return nil, fmt.Errorf("failed to save image: %w", err)
-		}
-		logger.Debug("Saved image", "token", image.Token)
-
-		return image, nil
+		return saveImage(ctx, logger, s.uploads, s.store, models.Image{Path: screenshot.Path})
 	})
 	if err != nil {
 		return nil, err
@@ -8,12 +8,17 @@ import (
 
 type NotificationServiceMock struct {
 	Webhook           SendWebhookSync
+	WebhookAsync      SendWebhook
 	EmailSync         SendEmailCommandSync
 	Email             SendEmailCommand
 	EmailVerified     bool
 	EmailVerification SendVerifyEmailCommand
 	ShouldError       error
 
+	ExpectedOutboxMessages []*OutboxMessage
+	RetriedOutboxMessage   int64
+	DeletedOutboxMessage   int64
+
 	WebhookHandler   func(context.Context, *SendWebhookSync) error
 	EmailHandlerSync func(context.Context, *SendEmailCommandSync) error
 	EmailHandler     func(context.Context, *SendEmailCommand) error
@@ -27,6 +32,11 @@ func (ns *NotificationServiceMock) SendWebhookSync(ctx context.Context, cmd *Sen
 	return ns.ShouldError
 }
 
+func (ns *NotificationServiceMock) SendWebhook(ctx context.Context, cmd *SendWebhook) error {
+	ns.WebhookAsync = *cmd
+	return ns.ShouldError
+}
+
 func (ns *NotificationServiceMock) SendEmailCommandHandlerSync(ctx context.Context, cmd *SendEmailCommandSync) error {
 	ns.EmailSync = *cmd
 	if ns.EmailHandlerSync != nil {
@@ -59,4 +69,18 @@ func (ns *NotificationServiceMock) SendVerificationEmail(ctx context.Context, cm
 	return ns.ShouldError
 }
 
+func (ns *NotificationServiceMock) GetOutboxMessages(ctx context.Context, query *GetOutboxMessagesQuery) ([]*OutboxMessage, error) {
+	return ns.ExpectedOutboxMessages, ns.ShouldError
+}
+
+func (ns *NotificationServiceMock) RetryOutboxMessage(ctx context.Context, id int64) error {
+	ns.RetriedOutboxMessage = id
+	return ns.ShouldError
+}
+
+func (ns *NotificationServiceMock) DeleteOutboxMessage(ctx context.Context, id int64) error {
+	ns.DeletedOutboxMessage = id
+	return ns.ShouldError
+}
+
 func MockNotificationService() *NotificationServiceMock { return &NotificationServiceMock{} }
@@ -47,6 +47,19 @@ type SendWebhookSync struct {
 	TLSConfig   *tls.Config
 }
 
+// SendWebhook is the command for sending webhooks asynchronously. Unlike SendWebhookSync
+// it has no TLS configuration and response validation, because it is stored until the
+// webhook is delivered.
+type SendWebhook struct {
+	Url         string
+	User        string
+	Password    string
+	Body        string
+	HttpMethod  string
+	HttpHeader  map[string]string
+	ContentType string
+}
+
 type SendResetPasswordEmailCommand struct {
 	User *user.User
 }
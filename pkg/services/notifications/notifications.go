@@ -14,7 +14,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -23,6 +25,7 @@ import (
 
 type WebhookSender interface {
 	SendWebhookSync(ctx context.Context, cmd *SendWebhookSync) error
+	SendWebhook(ctx context.Context, cmd *SendWebhook) error
 }
 type EmailSender interface {
 	SendEmailCommandHandlerSync(ctx context.Context, cmd *SendEmailCommandSync) error
@@ -35,11 +38,18 @@ type PasswordResetMailer interface {
 type EmailVerificationMailer interface {
 	SendVerificationEmail(ctx context.Context, cmd *SendVerifyEmailCommand) error
 }
+type OutboxService interface {
+	GetOutboxMessages(ctx context.Context, query *GetOutboxMessagesQuery) ([]*OutboxMessage, error)
+	// RetryOutboxMessage resets a failed message, so that it is sent again right away.
+	RetryOutboxMessage(ctx context.Context, id int64) error
+	DeleteOutboxMessage(ctx context.Context, id int64) error
+}
 type Service interface {
 	WebhookSender
 	EmailSender
 	PasswordResetMailer
 	EmailVerificationMailer
+	OutboxService
 }
 
 var mailTemplates *template.Template
@@ -51,7 +61,10 @@ const (
 	tmplVerifyEmail     = "verify_email"
 )
 
-func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUserStore) (*NotificationService, error) {
+// ProvideService provides the notification service. Emails and webhooks that are sent
+// asynchronously are stored in the outbox in the database until they are delivered.
+// Without a database they are queued in memory.
+func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUserStore, sqlStore db.DB, secretsService secrets.Service) (*NotificationService, error) {
 	ns := &NotificationService{
 		Bus:          bus,
 		Cfg:          cfg,
@@ -97,6 +110,26 @@ func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUser
 	if cfg.EmailCodeValidMinutes == 0 {
 		cfg.EmailCodeValidMinutes = 120
 	}
+
+	if sqlStore != nil {
+		settings, err := readOutboxSettings(cfg)
+		if err != nil {
+			return nil, err
+		}
+		ns.outbox = newOutbox(sqlStore, secretsService, settings, func(ctx context.Context, msg *Message) error {
+			_, err := ns.Send(ctx, msg)
+			return err
+		}, ns.sendWebRequestSync)
+	}
+
+	targets, err := readSystemNotificationTargets(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > 0 {
+		newSystemNotifier(targets, ns, ns).subscribe(ns.Bus)
+	}
+
 	return ns, nil
 }
 
@@ -110,12 +143,17 @@ type NotificationService struct {
 
 	mailQueue    chan *Message
 	webhookQueue chan *Webhook
+	outbox       *outbox
 	mailer       Mailer
 	log          log.Logger
 	store        TempUserStore
 }
 
 func (ns *NotificationService) Run(ctx context.Context) error {
+	if ns.outbox != nil {
+		return ns.outbox.run(ctx)
+	}
+
 	for {
 		select {
 		case webhook := <-ns.webhookQueue:
@@ -145,6 +183,25 @@ func (ns *NotificationService) GetMailer() Mailer {
 	return ns.mailer
 }
 
+// SendWebhook queues a webhook, it is retried until it succeeds or runs out of attempts.
+func (ns *NotificationService) SendWebhook(ctx context.Context, cmd *SendWebhook) error {
+	webhook := &Webhook{
+		Url:         cmd.Url,
+		User:        cmd.User,
+		Password:    cmd.Password,
+		Body:        cmd.Body,
+		HttpMethod:  cmd.HttpMethod,
+		HttpHeader:  cmd.HttpHeader,
+		ContentType: cmd.ContentType,
+	}
+	if ns.outbox != nil {
+		return ns.outbox.enqueueWebhook(ctx, webhook)
+	}
+
+	ns.webhookQueue <- webhook
+	return nil
+}
+
 func (ns *NotificationService) SendWebhookSync(ctx context.Context, cmd *SendWebhookSync) error {
 	return ns.sendWebRequestSync(ctx, &Webhook{
 		Url:         cmd.Url,
@@ -225,11 +282,35 @@ func (ns *NotificationService) SendEmailCommandHandler(ctx context.Context, cmd
 	if err != nil {
 		return err
 	}
+	if ns.outbox != nil {
+		return ns.outbox.enqueueEmail(ctx, message)
+	}
 
 	ns.mailQueue <- message
 	return nil
 }
 
+func (ns *NotificationService) GetOutboxMessages(ctx context.Context, query *GetOutboxMessagesQuery) ([]*OutboxMessage, error) {
+	if ns.outbox == nil {
+		return []*OutboxMessage{}, nil
+	}
+	return ns.outbox.getMessages(ctx, query)
+}
+
+func (ns *NotificationService) RetryOutboxMessage(ctx context.Context, id int64) error {
+	if ns.outbox == nil {
+		return ErrOutboxMessageNotFound
+	}
+	return ns.outbox.retry(ctx, id)
+}
+
+func (ns *NotificationService) DeleteOutboxMessage(ctx context.Context, id int64) error {
+	if ns.outbox == nil {
+		return ErrOutboxMessageNotFound
+	}
+	return ns.outbox.store.Delete(ctx, id)
+}
+
 func (ns *NotificationService) SendResetPasswordEmail(ctx context.Context, cmd *SendResetPasswordEmailCommand) error {
 	code, err := createUserEmailCode(ns.Cfg, cmd.User, "")
 	if err != nil {
@@ -0,0 +1,447 @@
+package notifications
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var ErrOutboxMessageNotFound = errors.New("outbox message not found")
+
+type OutboxMessageKind string
+
+const (
+	OutboxMessageKindEmail   OutboxMessageKind = "email"
+	OutboxMessageKindWebhook OutboxMessageKind = "webhook"
+)
+
+type OutboxMessageStatus string
+
+const (
+	// OutboxMessageStatusPending messages are waiting for their next attempt.
+	OutboxMessageStatusPending OutboxMessageStatus = "pending"
+	// OutboxMessageStatusFailed messages ran out of attempts and are kept until they
+	// are retried, deleted or the dead letter retention expires.
+	OutboxMessageStatusFailed OutboxMessageStatus = "failed"
+)
+
+// OutboxMessage is an email or webhook that is waiting to be sent, or failed to be sent.
+type OutboxMessage struct {
+	ID   int64
+	Kind OutboxMessageKind
+	// Target is the recipients of an email or the redacted URL of a webhook.
+	Target      string
+	Subject     string
+	Status      OutboxMessageStatus
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+	Created     time.Time
+	Updated     time.Time
+}
+
+type GetOutboxMessagesQuery struct {
+	// Status filters the messages by status, all messages are returned if it is empty.
+	Status OutboxMessageStatus
+	Limit  int
+}
+
+const (
+	outboxBatchSize = 100
+	// outboxLease is how long a claimed message is reserved for the instance that
+	// claimed it. Messages of instances that stop while sending are retried after it.
+	outboxLease           = 5 * time.Minute
+	outboxCleanupInterval = time.Hour
+)
+
+type outboxSettings struct {
+	pollInterval        time.Duration
+	maxAttempts         int
+	minRetryDelay       time.Duration
+	maxRetryDelay       time.Duration
+	deadLetterRetention time.Duration
+	// rateLimit is the number of messages per minute sent to a destination by this instance, 0 for no limit.
+	rateLimit          int
+	rateLimitOverrides map[string]int
+}
+
+func readOutboxSettings(cfg *setting.Cfg) (outboxSettings, error) {
+	section := cfg.SectionWithEnvOverrides("outbox")
+	s := outboxSettings{
+		pollInterval:        section.Key("poll_interval").MustDuration(10 * time.Second),
+		maxAttempts:         section.Key("max_attempts").MustInt(10),
+		minRetryDelay:       section.Key("min_retry_delay").MustDuration(30 * time.Second),
+		maxRetryDelay:       section.Key("max_retry_delay").MustDuration(time.Hour),
+		deadLetterRetention: section.Key("dead_letter_retention").MustDuration(7 * 24 * time.Hour),
+		rateLimit:           section.Key("rate_limit").MustInt(0),
+		rateLimitOverrides:  map[string]int{},
+	}
+
+	if s.pollInterval <= 0 {
+		return s, fmt.Errorf("outbox poll_interval must be positive")
+	}
+	if s.maxAttempts < 1 {
+		s.maxAttempts = 1
+	}
+	if s.maxRetryDelay < s.minRetryDelay {
+		s.maxRetryDelay = s.minRetryDelay
+	}
+
+	for _, override := range util.SplitString(section.Key("rate_limit_overrides").String()) {
+		destination, limit, ok := strings.Cut(override, ":")
+		n, err := strconv.Atoi(limit)
+		if !ok || destination == "" || err != nil || n < 0 {
+			return s, fmt.Errorf("invalid outbox rate_limit_overrides entry %q, expected destination:limit", override)
+		}
+		s.rateLimitOverrides[strings.ToLower(destination)] = n
+	}
+
+	return s, nil
+}
+
+// outbox stores the emails and webhooks that are sent asynchronously in the database
+// until they are delivered, so that they survive restarts and outages of the SMTP
+// server or webhook receiver. Failed sends are retried with exponential backoff, and
+// kept as dead letters once they run out of attempts.
+//
+// Every instance processes the outbox. A message is claimed by one instance before it
+// is sent, so that it is not sent by several instances at once.
+type outbox struct {
+	store    outboxStore
+	secrets  secrets.Service
+	settings outboxSettings
+	log      log.Logger
+	now      func() time.Time
+
+	sendEmail   func(ctx context.Context, msg *Message) error
+	sendWebhook func(ctx context.Context, webhook *Webhook) error
+
+	// wake is signaled when a message is enqueued, so that it is sent right away
+	// instead of at the next poll.
+	wake chan struct{}
+
+	limitersMu sync.Mutex
+	limiters   map[string]*rate.Limiter
+}
+
+func newOutbox(sqlStore db.DB, secretsService secrets.Service, settings outboxSettings, sendEmail func(context.Context, *Message) error, sendWebhook func(context.Context, *Webhook) error) *outbox {
+	return &outbox{
+		store:       &xormOutboxStore{db: sqlStore},
+		secrets:     secretsService,
+		settings:    settings,
+		log:         log.New("notifications.outbox"),
+		now:         time.Now,
+		sendEmail:   sendEmail,
+		sendWebhook: sendWebhook,
+		wake:        make(chan struct{}, 1),
+		limiters:    map[string]*rate.Limiter{},
+	}
+}
+
+// outboxPayload is the content of a message, it is stored encrypted.
+type outboxPayload struct {
+	Email   *Message `json:"email,omitempty"`
+	Webhook *Webhook `json:"webhook,omitempty"`
+}
+
+func (o *outbox) enqueueEmail(ctx context.Context, msg *Message) error {
+	// Emails to several recipients are sent one by one, they are queued separately so
+	// that a failed recipient doesn't cause the email to be sent again to the others.
+	messages := []*Message{msg}
+	if !msg.SingleEmail && len(msg.To) > 1 {
+		messages = make([]*Message, 0, len(msg.To))
+		for _, address := range msg.To {
+			copy := *msg
+			copy.To = []string{address}
+			messages = append(messages, &copy)
+		}
+	}
+
+	rows := make([]*outboxMessage, 0, len(messages))
+	for _, m := range messages {
+		row, err := o.newMessage(ctx, OutboxMessageKindEmail, emailDestination(m.To), strings.Join(m.To, ", "), m.Subject, outboxPayload{Email: m})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	return o.enqueue(ctx, rows...)
+}
+
+func (o *outbox) enqueueWebhook(ctx context.Context, webhook *Webhook) error {
+	u, err := url.Parse(webhook.Url)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", redactURL(err))
+	}
+
+	row, err := o.newMessage(ctx, OutboxMessageKindWebhook, strings.ToLower(u.Hostname()), u.Redacted(), "", outboxPayload{Webhook: webhook})
+	if err != nil {
+		return err
+	}
+	return o.enqueue(ctx, row)
+}
+
+func (o *outbox) newMessage(ctx context.Context, kind OutboxMessageKind, destination, target, subject string, payload outboxPayload) (*outboxMessage, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := o.secrets.Encrypt(ctx, content, secrets.WithoutScope())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt outbox message: %w", err)
+	}
+
+	now := o.now()
+	return &outboxMessage{
+		Kind:        kind,
+		Destination: destination,
+		Target:      truncate(target, 255),
+		Subject:     truncate(subject, 255),
+		Payload:     base64.StdEncoding.EncodeToString(encrypted),
+		Status:      OutboxMessageStatusPending,
+		NextAttempt: now.Unix(),
+		Created:     now,
+		Updated:     now,
+	}, nil
+}
+
+func (o *outbox) enqueue(ctx context.Context, rows ...*outboxMessage) error {
+	if err := o.store.Insert(ctx, rows...); err != nil {
+		return fmt.Errorf("failed to queue message: %w", err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (o *outbox) run(ctx context.Context) error {
+	ticker := time.NewTicker(o.settings.pollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		o.processDue(ctx)
+
+		if now := o.now(); now.Sub(lastCleanup) >= outboxCleanupInterval {
+			lastCleanup = now
+			deleted, err := o.store.DeleteFailedBefore(ctx, now.Add(-o.settings.deadLetterRetention))
+			if err != nil {
+				o.log.Error("Failed to delete expired dead letters", "error", err)
+			} else if deleted > 0 {
+				o.log.Info("Deleted expired dead letters", "count", deleted)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-o.wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// processDue sends the messages that are due, until there are none left.
+func (o *outbox) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := o.store.GetDue(ctx, o.now().Unix(), outboxBatchSize)
+		if err != nil {
+			o.log.Error("Failed to get due messages", "error", err)
+			return
+		}
+
+		for _, m := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			o.process(ctx, m)
+		}
+
+		if len(due) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (o *outbox) process(ctx context.Context, m *outboxMessage) {
+	logger := o.log.New("id", m.ID, "kind", m.Kind, "destination", m.Destination)
+
+	now := o.now()
+	claimedUntil := now.Add(outboxLease).Unix()
+	claimed, err := o.store.Claim(ctx, m.ID, m.ClaimedUntil, claimedUntil)
+	if err != nil {
+		logger.Error("Failed to claim message", "error", err)
+		return
+	}
+	if !claimed {
+		// Another instance is sending it.
+		return
+	}
+	m.ClaimedUntil = claimedUntil
+
+	// Rate limited messages are postponed without using up an attempt.
+	if delay := o.reserve(m.Destination, now); delay > 0 {
+		m.NextAttempt = now.Add(delay).Unix()
+		m.ClaimedUntil = 0
+		if err := o.store.Release(ctx, m, claimedUntil); err != nil {
+			logger.Error("Failed to postpone rate limited message", "error", err)
+		}
+		return
+	}
+
+	sendErr := o.send(ctx, m)
+	if sendErr == nil {
+		if err := o.store.Delete(ctx, m.ID); err != nil {
+			logger.Error("Failed to delete sent message", "error", err)
+		}
+		return
+	}
+
+	m.Attempts++
+	m.LastError = truncate(sendErr.Error(), 1000)
+	m.ClaimedUntil = 0
+	m.Updated = o.now()
+	if m.Attempts >= o.settings.maxAttempts {
+		m.Status = OutboxMessageStatusFailed
+		logger.Error("Failed to send message, giving up", "attempts", m.Attempts, "error", sendErr)
+	} else {
+		m.NextAttempt = m.Updated.Add(o.retryDelay(m.Attempts)).Unix()
+		logger.Warn("Failed to send message, retrying later", "attempts", m.Attempts, "nextAttempt", time.Unix(m.NextAttempt, 0), "error", sendErr)
+	}
+
+	if err := o.store.Release(ctx, m, claimedUntil); err != nil {
+		logger.Error("Failed to update message", "error", err)
+	}
+}
+
+func (o *outbox) send(ctx context.Context, m *outboxMessage) error {
+	encrypted, err := base64.StdEncoding.DecodeString(m.Payload)
+	if err != nil {
+		return err
+	}
+	content, err := o.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt message: %w", err)
+	}
+	var payload outboxPayload
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return err
+	}
+
+	switch {
+	case payload.Email != nil:
+		return o.sendEmail(ctx, payload.Email)
+	case payload.Webhook != nil:
+		return o.sendWebhook(ctx, payload.Webhook)
+	default:
+		return fmt.Errorf("empty message")
+	}
+}
+
+// retryDelay returns the delay before the next attempt after the given number of
+// failed attempts. It doubles with every attempt, from the min to the max retry delay.
+func (o *outbox) retryDelay(attempts int) time.Duration {
+	delay := o.settings.minRetryDelay
+	for i := 1; i < attempts && delay < o.settings.maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, o.settings.maxRetryDelay)
+}
+
+// reserve takes a token from the rate limiter of the destination. It returns how long
+// the message has to wait if there is none left, or 0 if it can be sent right away.
+// The limiters are kept in memory, so the limit applies to every instance separately.
+func (o *outbox) reserve(destination string, now time.Time) time.Duration {
+	limit := o.settings.rateLimit
+	if override, ok := o.settings.rateLimitOverrides[destination]; ok {
+		limit = override
+	}
+	if limit <= 0 {
+		return 0
+	}
+
+	o.limitersMu.Lock()
+	defer o.limitersMu.Unlock()
+
+	limiter, ok := o.limiters[destination]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(limit)), limit)
+		o.limiters[destination] = limiter
+	}
+
+	r := limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+func (o *outbox) getMessages(ctx context.Context, query *GetOutboxMessagesQuery) ([]*OutboxMessage, error) {
+	rows, err := o.store.List(ctx, query.Status, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*OutboxMessage, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.toDTO())
+	}
+	return result, nil
+}
+
+func (o *outbox) retry(ctx context.Context, id int64) error {
+	if err := o.store.Retry(ctx, id, o.now()); err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// emailDestination returns the domain of the first recipient, emails are rate limited
+// per recipient domain.
+func emailDestination(to []string) string {
+	if len(to) == 0 {
+		return ""
+	}
+	address := to[0]
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		address = address[i+1:]
+	}
+	return strings.ToLower(strings.TrimRight(address, "> "))
+}
+
+// truncate shortens s to at most n bytes, without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
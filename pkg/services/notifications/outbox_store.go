@@ -0,0 +1,166 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type outboxMessage struct {
+	ID          int64             `xorm:"pk autoincr 'id'"`
+	Kind        OutboxMessageKind `xorm:"kind"`
+	Destination string            `xorm:"destination"`
+	Target      string            `xorm:"target"`
+	Subject     string            `xorm:"subject"`
+	// Payload is the encrypted and base64 encoded JSON of the outboxPayload.
+	Payload   string              `xorm:"payload"`
+	Status    OutboxMessageStatus `xorm:"status"`
+	Attempts  int                 `xorm:"attempts"`
+	LastError string              `xorm:"last_error"`
+	// NextAttempt and ClaimedUntil are unix timestamps.
+	NextAttempt  int64     `xorm:"next_attempt"`
+	ClaimedUntil int64     `xorm:"claimed_until"`
+	Created      time.Time `xorm:"'created'"`
+	Updated      time.Time `xorm:"'updated'"`
+}
+
+func (m *outboxMessage) TableName() string {
+	return "notification_outbox"
+}
+
+func (m *outboxMessage) toDTO() *OutboxMessage {
+	return &OutboxMessage{
+		ID:          m.ID,
+		Kind:        m.Kind,
+		Target:      m.Target,
+		Subject:     m.Subject,
+		Status:      m.Status,
+		Attempts:    m.Attempts,
+		LastError:   m.LastError,
+		NextAttempt: time.Unix(m.NextAttempt, 0).UTC(),
+		Created:     m.Created,
+		Updated:     m.Updated,
+	}
+}
+
+type outboxStore interface {
+	Insert(ctx context.Context, messages ...*outboxMessage) error
+	// GetDue returns the pending messages whose next attempt is due and that aren't
+	// claimed by an instance, oldest first.
+	GetDue(ctx context.Context, now int64, limit int) ([]*outboxMessage, error)
+	// Claim reserves the message until the given time. It returns false if the message
+	// was claimed by another instance since it was read.
+	Claim(ctx context.Context, id int64, prev, until int64) (bool, error)
+	// Release updates a claimed message after an attempt to send it. It doesn't update
+	// the message if the claim expired and it was claimed by another instance.
+	Release(ctx context.Context, m *outboxMessage, claimedUntil int64) error
+	Delete(ctx context.Context, id int64) error
+	DeleteFailedBefore(ctx context.Context, before time.Time) (int64, error)
+
+	List(ctx context.Context, status OutboxMessageStatus, limit int) ([]*outboxMessage, error)
+	// Retry resets a failed message to pending, due right away.
+	Retry(ctx context.Context, id int64, now time.Time) error
+}
+
+type xormOutboxStore struct {
+	db db.DB
+}
+
+func (s *xormOutboxStore) Insert(ctx context.Context, messages ...*outboxMessage) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, m := range messages {
+			if _, err := sess.Insert(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *xormOutboxStore) GetDue(ctx context.Context, now int64, limit int) ([]*outboxMessage, error) {
+	result := make([]*outboxMessage, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("status = ? AND next_attempt <= ? AND claimed_until <= ?", OutboxMessageStatusPending, now, now).
+			Asc("next_attempt", "id").Limit(limit).Find(&result)
+	})
+	return result, err
+}
+
+func (s *xormOutboxStore) Claim(ctx context.Context, id int64, prev, until int64) (bool, error) {
+	var claimed bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE notification_outbox SET claimed_until = ? WHERE id = ? AND status = ? AND claimed_until = ?", until, id, OutboxMessageStatusPending, prev)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = rows == 1
+		return nil
+	})
+	return claimed, err
+}
+
+func (s *xormOutboxStore) Release(ctx context.Context, m *outboxMessage, claimedUntil int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("id = ? AND claimed_until = ?", m.ID, claimedUntil).
+			Cols("status", "attempts", "last_error", "next_attempt", "claimed_until", "updated").Update(m)
+		return err
+	})
+}
+
+func (s *xormOutboxStore) Delete(ctx context.Context, id int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(id).Delete(&outboxMessage{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrOutboxMessageNotFound
+		}
+		return nil
+	})
+}
+
+func (s *xormOutboxStore) DeleteFailedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("status = ? AND updated < ?", OutboxMessageStatusFailed, before).Delete(&outboxMessage{})
+		return err
+	})
+	return deleted, err
+}
+
+func (s *xormOutboxStore) List(ctx context.Context, status OutboxMessageStatus, limit int) ([]*outboxMessage, error) {
+	result := make([]*outboxMessage, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if status != "" {
+			sess.Where("status = ?", status)
+		}
+		return sess.Omit("payload").Desc("id").Limit(limit).Find(&result)
+	})
+	return result, err
+}
+
+func (s *xormOutboxStore) Retry(ctx context.Context, id int64, now time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("id = ? AND status = ?", id, OutboxMessageStatusFailed).
+			Cols("status", "attempts", "last_error", "next_attempt", "claimed_until", "updated").
+			Update(&outboxMessage{
+				Status:      OutboxMessageStatusPending,
+				NextAttempt: now.Unix(),
+				Updated:     now,
+			})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrOutboxMessageNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,306 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// Events that system notifications are sent for.
+const (
+	SystemEventLoginLockedOut         = "login_locked_out"
+	SystemEventUserTokenRevoked       = "user_token_revoked"
+	SystemEventProvisioningSyncFailed = "provisioning_sync_failed"
+	SystemEventUpdateAvailable        = "update_available"
+)
+
+var systemEvents = []string{
+	SystemEventLoginLockedOut,
+	SystemEventUserTokenRevoked,
+	SystemEventProvisioningSyncFailed,
+	SystemEventUpdateAvailable,
+}
+
+type SystemTargetType string
+
+const (
+	SystemTargetWebhook SystemTargetType = "webhook"
+	SystemTargetSlack   SystemTargetType = "slack"
+	SystemTargetEmail   SystemTargetType = "email"
+)
+
+const (
+	tmplSystemNotification = "system_notification"
+
+	systemNotificationsSection      = "system_notifications"
+	systemNotificationTargetsPrefix = "system_notifications.target."
+)
+
+// SystemNotification is a notification about an internal event, such as a login lockout
+// or a failed provisioning, that is sent to the targets that subscribe to the event.
+// It is the data of the templates of the targets.
+type SystemNotification struct {
+	Event   string         `json:"event"`
+	Title   string         `json:"title"`
+	Message string         `json:"message"`
+	Time    time.Time      `json:"time"`
+	Data    map[string]any `json:"data"`
+}
+
+// systemTarget is a webhook, Slack compatible webhook or list of email addresses
+// that system notifications are sent to.
+type systemTarget struct {
+	name       string
+	targetType SystemTargetType
+	url        string
+	httpMethod string
+	username   string
+	password   string
+	addresses  []string
+	// events the target subscribes to, all events if it is empty
+	events   map[string]bool
+	template *template.Template
+}
+
+func (t *systemTarget) subscribes(event string) bool {
+	return len(t.events) == 0 || t.events[event]
+}
+
+// readSystemNotificationTargets reads the [system_notifications.target.<name>] sections,
+// no targets are returned when system notifications aren't enabled.
+func readSystemNotificationTargets(cfg *setting.Cfg) ([]*systemTarget, error) {
+	if !cfg.SectionWithEnvOverrides(systemNotificationsSection).Key("enabled").MustBool(false) {
+		return nil, nil
+	}
+
+	targets := make([]*systemTarget, 0)
+	for _, section := range cfg.Raw.Sections() {
+		if !strings.HasPrefix(section.Name(), systemNotificationTargetsPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(section.Name(), systemNotificationTargetsPrefix)
+		target, err := readSystemNotificationTarget(name, cfg.SectionWithEnvOverrides(section.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("invalid system notification target %q: %w", name, err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func readSystemNotificationTarget(name string, section *setting.DynamicSection) (*systemTarget, error) {
+	target := &systemTarget{
+		name:       name,
+		targetType: SystemTargetType(section.Key("type").MustString(string(SystemTargetWebhook))),
+		url:        section.Key("url").MustString(""),
+		httpMethod: strings.ToUpper(section.Key("http_method").MustString(http.MethodPost)),
+		username:   section.Key("username").MustString(""),
+		password:   section.Key("password").MustString(""),
+		addresses:  util.SplitString(section.Key("addresses").MustString("")),
+		events:     map[string]bool{},
+	}
+
+	switch target.targetType {
+	case SystemTargetWebhook, SystemTargetSlack:
+		u, err := url.Parse(target.url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("url must be an absolute http or https URL")
+		}
+		if target.httpMethod != http.MethodPost && target.httpMethod != http.MethodPut {
+			return nil, fmt.Errorf("http_method must be POST or PUT")
+		}
+	case SystemTargetEmail:
+		if len(target.addresses) == 0 {
+			return nil, fmt.Errorf("addresses are required for email targets")
+		}
+		for _, address := range target.addresses {
+			if !util.IsEmail(address) {
+				return nil, fmt.Errorf("invalid email address %q", address)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q, expected webhook, slack or email", target.targetType)
+	}
+
+	for _, event := range util.SplitString(section.Key("events").MustString("")) {
+		if event == "*" {
+			target.events = map[string]bool{}
+			break
+		}
+		if !slices.Contains(systemEvents, event) {
+			return nil, fmt.Errorf("unknown event %q, expected one of %s", event, strings.Join(systemEvents, ", "))
+		}
+		target.events[event] = true
+	}
+
+	if text := section.Key("template").MustString(""); text != "" {
+		tmpl, err := template.New(name).Option("missingkey=error").Funcs(sprig.TxtFuncMap()).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		target.template = tmpl
+	}
+
+	return target, nil
+}
+
+// systemNotifier routes internal events published on the bus to the system notification targets.
+// Notifications are sent asynchronously, so that they are retried by the outbox and don't
+// delay or fail the operation that published the event.
+type systemNotifier struct {
+	targets  []*systemTarget
+	webhooks WebhookSender
+	emails   EmailSender
+	log      log.Logger
+}
+
+func newSystemNotifier(targets []*systemTarget, webhooks WebhookSender, emails EmailSender) *systemNotifier {
+	return &systemNotifier{
+		targets:  targets,
+		webhooks: webhooks,
+		emails:   emails,
+		log:      log.New("notifications.system"),
+	}
+}
+
+func (sn *systemNotifier) subscribe(b bus.Bus) {
+	b.AddEventListener(func(ctx context.Context, evt *events.LoginLockedOut) error {
+		sn.notify(ctx, newSystemNotification(SystemEventLoginLockedOut, evt.Timestamp, "Login locked out",
+			fmt.Sprintf("The %s %s has been locked out after %d failed login attempts, until %s.",
+				evt.Kind, evt.Subject, evt.Attempts, evt.LockedUntil.UTC().Format(time.RFC1123)), evt))
+		return nil
+	})
+	b.AddEventListener(func(ctx context.Context, evt *events.UserTokenRevoked) error {
+		switch evt.Kind {
+		case events.UserTokenRevokedAllSessions:
+			sn.notify(ctx, newSystemNotification(SystemEventUserTokenRevoked, evt.Timestamp, "Sessions revoked",
+				fmt.Sprintf("All the sessions of the user %s have been revoked by %s.", evt.Login, evt.RevokedBy), evt))
+		case events.UserTokenRevokedServiceAccountToken:
+			sn.notify(ctx, newSystemNotification(SystemEventUserTokenRevoked, evt.Timestamp, "Service account token revoked",
+				fmt.Sprintf("The token %d of the service account %d has been revoked by %s.", evt.TokenID, evt.UserID, evt.RevokedBy), evt))
+		default:
+			sn.notify(ctx, newSystemNotification(SystemEventUserTokenRevoked, evt.Timestamp, "Session revoked",
+				fmt.Sprintf("A session of the user %s has been revoked by %s.", evt.Login, evt.RevokedBy), evt))
+		}
+		return nil
+	})
+	b.AddEventListener(func(ctx context.Context, evt *events.ProvisioningSyncFailed) error {
+		sn.notify(ctx, newSystemNotification(SystemEventProvisioningSyncFailed, evt.Timestamp, "Provisioning failed",
+			fmt.Sprintf("Provisioning %s failed: %s", evt.Kind, evt.Error), evt))
+		return nil
+	})
+	b.AddEventListener(func(ctx context.Context, evt *events.UpdateAvailable) error {
+		sn.notify(ctx, newSystemNotification(SystemEventUpdateAvailable, evt.Timestamp, "Grafana update available",
+			fmt.Sprintf("Grafana %s is available, this server runs Grafana %s.", evt.LatestVersion, evt.CurrentVersion), evt))
+		return nil
+	})
+}
+
+func newSystemNotification(event string, timestamp time.Time, title, message string, evt any) *SystemNotification {
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	// the data of the event is exposed to templates with the names of its JSON fields
+	data := map[string]any{}
+	if b, err := json.Marshal(evt); err == nil {
+		_ = json.Unmarshal(b, &data)
+	}
+
+	return &SystemNotification{
+		Event:   event,
+		Title:   title,
+		Message: message,
+		Time:    timestamp.UTC(),
+		Data:    data,
+	}
+}
+
+// notify sends the notification to every target that subscribes to its event. Errors are
+// logged rather than returned, so that they don't fail the publisher of the event.
+func (sn *systemNotifier) notify(ctx context.Context, notification *SystemNotification) {
+	for _, target := range sn.targets {
+		if !target.subscribes(notification.Event) {
+			continue
+		}
+		if err := sn.send(ctx, target, notification); err != nil {
+			sn.log.Error("Failed to send system notification", "target", target.name, "event", notification.Event, "error", err)
+		}
+	}
+}
+
+func (sn *systemNotifier) send(ctx context.Context, target *systemTarget, notification *SystemNotification) error {
+	text, err := target.render(notification)
+	if err != nil {
+		return err
+	}
+
+	switch target.targetType {
+	case SystemTargetSlack:
+		body, err := json.Marshal(map[string]string{"text": text})
+		if err != nil {
+			return err
+		}
+		return sn.webhooks.SendWebhook(ctx, &SendWebhook{
+			Url:        target.url,
+			Body:       string(body),
+			HttpMethod: target.httpMethod,
+		})
+	case SystemTargetEmail:
+		return sn.emails.SendEmailCommandHandler(ctx, &SendEmailCommand{
+			To:       target.addresses,
+			Template: tmplSystemNotification,
+			Data: map[string]any{
+				"Event":   notification.Event,
+				"Title":   notification.Title,
+				"Message": text,
+				"Time":    notification.Time.Format(time.RFC1123),
+			},
+		})
+	default:
+		return sn.webhooks.SendWebhook(ctx, &SendWebhook{
+			Url:        target.url,
+			User:       target.username,
+			Password:   target.password,
+			Body:       text,
+			HttpMethod: target.httpMethod,
+		})
+	}
+}
+
+// render executes the template of the target. Without a template webhooks get the notification
+// as JSON, Slack messages the title and the message, and emails the message.
+func (t *systemTarget) render(notification *SystemNotification) (string, error) {
+	if t.template != nil {
+		var buf bytes.Buffer
+		if err := t.template.Execute(&buf, notification); err != nil {
+			return "", fmt.Errorf("failed to execute template: %w", err)
+		}
+		return buf.String(), nil
+	}
+
+	switch t.targetType {
+	case SystemTargetSlack:
+		return fmt.Sprintf("*%s*\n%s", notification.Title, notification.Message), nil
+	case SystemTargetEmail:
+		return notification.Message, nil
+	default:
+		body, err := json.Marshal(notification)
+		return string(body), err
+	}
+}
@@ -0,0 +1,256 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeSystemSender struct {
+	webhooks []*SendWebhook
+	emails   []*SendEmailCommand
+}
+
+func (f *fakeSystemSender) SendWebhookSync(ctx context.Context, cmd *SendWebhookSync) error {
+	return nil
+}
+
+func (f *fakeSystemSender) SendWebhook(ctx context.Context, cmd *SendWebhook) error {
+	f.webhooks = append(f.webhooks, cmd)
+	return nil
+}
+
+func (f *fakeSystemSender) SendEmailCommandHandlerSync(ctx context.Context, cmd *SendEmailCommandSync) error {
+	return nil
+}
+
+func (f *fakeSystemSender) SendEmailCommandHandler(ctx context.Context, cmd *SendEmailCommand) error {
+	f.emails = append(f.emails, cmd)
+	return nil
+}
+
+func systemNotificationConfig(t *testing.T, config string) *setting.Cfg {
+	t.Helper()
+	cfg := createSmtpConfig()
+	raw, err := ini.Load([]byte(config))
+	require.NoError(t, err)
+	cfg.Raw = raw
+	return cfg
+}
+
+func TestReadSystemNotificationTargets(t *testing.T) {
+	t.Run("no targets are read when system notifications are disabled", func(t *testing.T) {
+		cfg := systemNotificationConfig(t, `
+[system_notifications.target.ops]
+url = https://example.com/hook
+`)
+		targets, err := readSystemNotificationTargets(cfg)
+		require.NoError(t, err)
+		assert.Empty(t, targets)
+	})
+
+	t.Run("reads every target", func(t *testing.T) {
+		cfg := systemNotificationConfig(t, `
+[system_notifications]
+enabled = true
+
+[system_notifications.target.ops]
+url = https://example.com/hook
+events = login_locked_out, user_token_revoked
+
+[system_notifications.target.chat]
+type = slack
+url = https://hooks.slack.com/services/T000/B000/XXX
+events = *
+
+[system_notifications.target.admins]
+type = email
+addresses = admin@example.com, security@example.com
+template = {{ .Title }}: {{ .Data.subject }}
+`)
+		targets, err := readSystemNotificationTargets(cfg)
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+
+		assert.Equal(t, "ops", targets[0].name)
+		assert.Equal(t, SystemTargetWebhook, targets[0].targetType)
+		assert.True(t, targets[0].subscribes(SystemEventLoginLockedOut))
+		assert.False(t, targets[0].subscribes(SystemEventUpdateAvailable))
+
+		assert.Equal(t, SystemTargetSlack, targets[1].targetType)
+		assert.True(t, targets[1].subscribes(SystemEventUpdateAvailable))
+
+		assert.Equal(t, SystemTargetEmail, targets[2].targetType)
+		assert.Equal(t, []string{"admin@example.com", "security@example.com"}, targets[2].addresses)
+		assert.NotNil(t, targets[2].template)
+	})
+
+	invalid := map[string]string{
+		"unknown type":          "type = sms\nurl = https://example.com",
+		"missing url":           "type = webhook",
+		"relative url":          "url = /hook",
+		"unsupported method":    "url = https://example.com\nhttp_method = GET",
+		"email without address": "type = email",
+		"invalid address":       "type = email\naddresses = not-an-email",
+		"unknown event":         "url = https://example.com\nevents = user_created",
+		"invalid template":      "url = https://example.com\ntemplate = {{ .Title",
+	}
+	for name, target := range invalid {
+		t.Run(name, func(t *testing.T) {
+			cfg := systemNotificationConfig(t, "[system_notifications]\nenabled = true\n[system_notifications.target.invalid]\n"+target)
+			_, err := readSystemNotificationTargets(cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSystemNotifier(t *testing.T) {
+	lockout := &events.LoginLockedOut{
+		Timestamp:   time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Kind:        "username",
+		Subject:     "alice",
+		Attempts:    5,
+		LockedUntil: time.Date(2024, time.March, 1, 12, 5, 0, 0, time.UTC),
+	}
+
+	newNotifier := func(t *testing.T, config string) (*fakeSystemSender, func(evt any)) {
+		t.Helper()
+		targets, err := readSystemNotificationTargets(systemNotificationConfig(t, "[system_notifications]\nenabled = true\n"+config))
+		require.NoError(t, err)
+
+		sender := &fakeSystemSender{}
+		b := newBus(t)
+		newSystemNotifier(targets, sender, sender).subscribe(b)
+		return sender, func(evt any) {
+			require.NoError(t, b.Publish(context.Background(), evt))
+		}
+	}
+
+	t.Run("webhooks get the notification as JSON", func(t *testing.T) {
+		sender, publish := newNotifier(t, `
+[system_notifications.target.ops]
+url = https://example.com/hook
+username = user
+password = secret
+`)
+		publish(lockout)
+		require.Len(t, sender.webhooks, 1)
+		assert.Equal(t, "https://example.com/hook", sender.webhooks[0].Url)
+		assert.Equal(t, "POST", sender.webhooks[0].HttpMethod)
+		assert.Equal(t, "user", sender.webhooks[0].User)
+
+		notification := SystemNotification{}
+		require.NoError(t, json.Unmarshal([]byte(sender.webhooks[0].Body), &notification))
+		assert.Equal(t, SystemEventLoginLockedOut, notification.Event)
+		assert.Equal(t, "Login locked out", notification.Title)
+		assert.Equal(t, "The username alice has been locked out after 5 failed login attempts, until Fri, 01 Mar 2024 12:05:00 UTC.", notification.Message)
+		assert.Equal(t, "alice", notification.Data["subject"])
+	})
+
+	t.Run("slack targets get a message", func(t *testing.T) {
+		sender, publish := newNotifier(t, `
+[system_notifications.target.chat]
+type = slack
+url = https://hooks.slack.com/services/T000/B000/XXX
+`)
+		publish(&events.UpdateAvailable{CurrentVersion: "11.0.0", LatestVersion: "11.1.0"})
+		require.Len(t, sender.webhooks, 1)
+		assert.JSONEq(t, `{"text": "*Grafana update available*\nGrafana 11.1.0 is available, this server runs Grafana 11.0.0."}`, sender.webhooks[0].Body)
+	})
+
+	t.Run("email targets get the rendered template", func(t *testing.T) {
+		sender, publish := newNotifier(t, `
+[system_notifications.target.admins]
+type = email
+addresses = admin@example.com
+template = {{ .Data.kind }} {{ .Data.subject | upper }} locked out
+`)
+		publish(lockout)
+		require.Len(t, sender.emails, 1)
+		assert.Equal(t, []string{"admin@example.com"}, sender.emails[0].To)
+		assert.Equal(t, tmplSystemNotification, sender.emails[0].Template)
+		assert.Equal(t, "username ALICE locked out", sender.emails[0].Data["Message"])
+		assert.Equal(t, "Login locked out", sender.emails[0].Data["Title"])
+	})
+
+	t.Run("targets only get the events they subscribe to", func(t *testing.T) {
+		sender, publish := newNotifier(t, `
+[system_notifications.target.provisioning]
+url = https://example.com/provisioning
+events = provisioning_sync_failed
+
+[system_notifications.target.all]
+url = https://example.com/all
+`)
+		publish(lockout)
+		publish(&events.ProvisioningSyncFailed{Kind: "dashboards", Error: "invalid JSON"})
+		require.Len(t, sender.webhooks, 3)
+		assert.Equal(t, "https://example.com/all", sender.webhooks[0].Url)
+		assert.Equal(t, "https://example.com/provisioning", sender.webhooks[1].Url)
+		assert.Equal(t, "https://example.com/all", sender.webhooks[2].Url)
+	})
+
+	t.Run("revoked tokens are described by their kind", func(t *testing.T) {
+		sender, publish := newNotifier(t, `
+[system_notifications.target.chat]
+type = slack
+url = https://hooks.slack.com/services/T000/B000/XXX
+`)
+		publish(&events.UserTokenRevoked{Kind: events.UserTokenRevokedSession, Login: "alice", RevokedBy: "admin"})
+		publish(&events.UserTokenRevoked{Kind: events.UserTokenRevokedAllSessions, Login: "alice", RevokedBy: "admin"})
+		publish(&events.UserTokenRevoked{Kind: events.UserTokenRevokedServiceAccountToken, UserID: 2, TokenID: 3, RevokedBy: "secret scan"})
+		require.Len(t, sender.webhooks, 3)
+		assert.JSONEq(t, `{"text": "*Session revoked*\nA session of the user alice has been revoked by admin."}`, sender.webhooks[0].Body)
+		assert.JSONEq(t, `{"text": "*Sessions revoked*\nAll the sessions of the user alice have been revoked by admin."}`, sender.webhooks[1].Body)
+		assert.JSONEq(t, `{"text": "*Service account token revoked*\nThe token 3 of the service account 2 has been revoked by secret scan."}`, sender.webhooks[2].Body)
+	})
+
+	t.Run("template errors don't fail the publisher", func(t *testing.T) {
+		sender, publish := newNotifier(t, `
+[system_notifications.target.ops]
+url = https://example.com/hook
+template = {{ .Data.missing.field }}
+`)
+		publish(&events.UpdateAvailable{CurrentVersion: "12.0.0", LatestVersion: "12.1.0"})
+		assert.Empty(t, sender.webhooks)
+	})
+}
+
+func TestProvideServiceWithSystemNotifications(t *testing.T) {
+	bus := newBus(t)
+	cfg := systemNotificationConfig(t, `
+[system_notifications]
+enabled = true
+
+[system_notifications.target.ops]
+url = https://example.com/hook
+
+[system_notifications.target.admins]
+type = email
+addresses = admin@example.com
+`)
+	ns, _, err := createSutWithConfig(t, bus, cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(context.Background(), &events.UserTokenRevoked{Login: "alice", RevokedBy: "admin"}))
+	webhook := <-ns.webhookQueue
+	assert.Equal(t, "https://example.com/hook", webhook.Url)
+	assert.Contains(t, webhook.Body, "A session of the user alice has been revoked by admin.")
+
+	email := <-ns.mailQueue
+	assert.Equal(t, []string{"admin@example.com"}, email.To)
+	assert.Equal(t, "Grafana - Session revoked", email.Subject)
+	assert.Contains(t, email.Body["text/plain"], "A session of the user alice has been revoked by admin.")
+
+	cfg.Raw.Section("system_notifications.target.ops").Key("type").SetValue("sms")
+	_, _, err = createSutWithConfig(t, newBus(t), cfg)
+	require.Error(t, err)
+}
@@ -245,6 +245,8 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM team_role WHERE org_id = ?",
 			"DELETE FROM user_role WHERE org_id = ?",
 			"DELETE FROM builtin_role WHERE org_id = ?",
+			"DELETE FROM report WHERE org_id = ?",
+			"DELETE FROM report_run WHERE org_id = ?",
 		}
 
 		// Add registered deletes
@@ -830,6 +832,8 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM team_member WHERE user_id = ?",
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
+		"DELETE FROM user_auth_factor WHERE user_id = ?",
+		"DELETE FROM user_auth_recovery_code WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
 	}
 	return deletes
@@ -39,6 +39,9 @@ type PlaylistDTO struct {
 	// The ordered list of items that the playlist will iterate over.
 	Items []PlaylistItemDTO `json:"items"`
 
+	// The times at which kiosk devices play the playlist.
+	Schedules []PlaylistSchedule `json:"schedules,omitempty" db:"-"`
+
 	// Returned for k8s
 	CreatedAt int64 `json:"-" db:"created_at"`
 
@@ -68,6 +71,35 @@ type PlaylistItemDTO struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+
+	// Duration overrides the interval of the playlist for the dashboards of this item.
+	Duration string `json:"duration,omitempty"`
+
+	// TimeRange overrides the time range of the dashboards of this item.
+	TimeRange *PlaylistTimeRange `json:"timeRange,omitempty"`
+}
+
+type PlaylistTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PlaylistSchedule is a time of day, on some weekdays, at which kiosk devices play a playlist
+type PlaylistSchedule struct {
+	// Weekdays the schedule applies to, every day if empty.
+	Weekdays []string `json:"weekdays,omitempty"`
+
+	// From is the time of day the schedule starts at, formatted as 15:04.
+	From string `json:"from"`
+
+	// To is the time of day the schedule ends at, formatted as 15:04.
+	To string `json:"to"`
+
+	// Timezone of from and to. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Kiosk is the name of the kiosk devices the schedule applies to, all kiosk devices if empty.
+	Kiosk string `json:"kiosk,omitempty"`
 }
 
 type PlaylistItem struct {
@@ -77,6 +109,11 @@ type PlaylistItem struct {
 	Value      string `json:"value" db:"value"`
 	Order      int    `json:"order" db:"order"`
 	Title      string `json:"title" db:"title"`
+	Duration   string `json:"duration,omitempty" db:"duration"`
+	TimeFrom   string `json:"-" db:"time_from"`
+	TimeTo     string `json:"-" db:"time_to"`
+
+	TimeRange *PlaylistTimeRange `json:"timeRange,omitempty" xorm:"-" db:"-"`
 }
 
 type Playlists []*Playlist
@@ -86,18 +123,20 @@ type Playlists []*Playlist
 //
 
 type UpdatePlaylistCommand struct {
-	OrgId    int64          `json:"-"`
-	UID      string         `json:"uid"`
-	Name     string         `json:"name" binding:"Required"`
-	Interval string         `json:"interval"`
-	Items    []PlaylistItem `json:"items"`
+	OrgId     int64              `json:"-"`
+	UID       string             `json:"uid"`
+	Name      string             `json:"name" binding:"Required"`
+	Interval  string             `json:"interval"`
+	Items     []PlaylistItem     `json:"items"`
+	Schedules []PlaylistSchedule `json:"schedules,omitempty"`
 }
 
 type CreatePlaylistCommand struct {
-	Name     string         `json:"name" binding:"Required"`
-	Interval string         `json:"interval"`
-	Items    []PlaylistItem `json:"items"`
-	OrgId    int64          `json:"-"`
+	Name      string             `json:"name" binding:"Required"`
+	Interval  string             `json:"interval"`
+	Items     []PlaylistItem     `json:"items"`
+	Schedules []PlaylistSchedule `json:"schedules,omitempty"`
+	OrgId     int64              `json:"-"`
 	// Used to create playlists from kubectl with a known uid/name
 	UID string `json:"-"`
 }
@@ -56,10 +56,19 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 	if err != nil {
 		return nil, err
 	}
+	schedules, err := s.store.GetSchedules(ctx, &playlist.GetPlaylistItemsByUidQuery{
+		PlaylistUID: v.UID,
+		OrgId:       q.OrgId,
+	})
+	if err != nil {
+		return nil, err
+	}
 	items := make([]playlist.PlaylistItemDTO, len(rawItems))
 	for i := 0; i < len(rawItems); i++ {
 		items[i].Type = rawItems[i].Type
 		items[i].Value = rawItems[i].Value
+		items[i].Duration = rawItems[i].Duration
+		items[i].TimeRange = rawItems[i].TimeRange
 
 		// Add the unused title to the result
 		title := rawItems[i].Title
@@ -73,6 +82,7 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 		Name:      v.Name,
 		Interval:  v.Interval,
 		Items:     items,
+		Schedules: schedules,
 		CreatedAt: v.CreatedAt,
 		UpdatedAt: v.UpdatedAt,
 		OrgID:     v.OrgId,
@@ -110,6 +110,51 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Can create playlist with item overrides and schedules", func(t *testing.T) {
+		items := []playlist.PlaylistItem{
+			{Value: "incidents", Type: "dashboard_by_uid", Duration: "2m", TimeRange: &playlist.PlaylistTimeRange{From: "now-1h", To: "now"}},
+			{Value: "noc", Type: "dashboard_by_tag"},
+		}
+		schedules := []playlist.PlaylistSchedule{
+			{Weekdays: []string{"monday", "friday"}, From: "08:00", To: "20:00", Timezone: "Europe/Berlin", Kiosk: "noc-wall"},
+			{From: "20:00", To: "08:00"},
+		}
+		cmd := playlist.CreatePlaylistCommand{Name: "NOC", Interval: "20s", OrgId: 3, Items: items, Schedules: schedules}
+		p, err := playlistStore.Insert(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		storedItems, err := playlistStore.GetItems(context.Background(), &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: p.UID, OrgId: 3})
+		require.NoError(t, err)
+		require.Len(t, storedItems, 2)
+		require.Equal(t, "2m", storedItems[0].Duration)
+		require.Equal(t, &playlist.PlaylistTimeRange{From: "now-1h", To: "now"}, storedItems[0].TimeRange)
+		require.Nil(t, storedItems[1].TimeRange)
+
+		storedSchedules, err := playlistStore.GetSchedules(context.Background(), &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: p.UID, OrgId: 3})
+		require.NoError(t, err)
+		require.Equal(t, schedules, storedSchedules)
+
+		all, err := playlistStore.ListAll(context.Background(), 3)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, schedules, all[0].Schedules)
+		require.Equal(t, "2m", all[0].Items[0].Duration)
+		require.Equal(t, "now-1h", all[0].Items[0].TimeRange.From)
+
+		t.Run("Can replace schedules on update", func(t *testing.T) {
+			update := playlist.UpdatePlaylistCommand{Name: "NOC", Interval: "20s", OrgId: 3, UID: p.UID, Items: items, Schedules: schedules[1:]}
+			_, err := playlistStore.Update(context.Background(), &update)
+			require.NoError(t, err)
+
+			storedSchedules, err := playlistStore.GetSchedules(context.Background(), &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: p.UID, OrgId: 3})
+			require.NoError(t, err)
+			require.Equal(t, schedules[1:], storedSchedules)
+		})
+
+		err = playlistStore.Delete(context.Background(), &playlist.DeletePlaylistCommand{OrgId: 3, UID: p.UID})
+		require.NoError(t, err)
+	})
+
 	t.Run("Search playlist", func(t *testing.T) {
 		startTime := time.Now().UnixMilli()
 		time.Sleep(time.Millisecond * 20)
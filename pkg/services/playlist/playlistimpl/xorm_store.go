@@ -3,6 +3,7 @@ package playlistimpl
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
@@ -54,20 +55,13 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 			return err
 		}
 
-		playlistItems := make([]playlist.PlaylistItem, 0)
-		for order, item := range cmd.Items {
-			playlistItems = append(playlistItems, playlist.PlaylistItem{
-				PlaylistId: p.Id,
-				Type:       item.Type,
-				Value:      item.Value,
-				Order:      order + 1,
-				Title:      item.Title,
-			})
-		}
-
+		playlistItems := newPlaylistItems(p.Id, cmd.Items)
 		_, err = sess.Insert(&playlistItems)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return insertSchedules(sess, p.Id, cmd.Schedules)
 	})
 	return &p, err
 }
@@ -109,20 +103,18 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 			return err
 		}
 
-		playlistItems := make([]playlist.PlaylistItem, 0)
-
-		for index, item := range cmd.Items {
-			playlistItems = append(playlistItems, playlist.PlaylistItem{
-				PlaylistId: p.Id,
-				Type:       item.Type,
-				Value:      item.Value,
-				Order:      index + 1,
-				Title:      item.Title,
-			})
+		_, err = sess.Exec("DELETE FROM playlist_schedule WHERE playlist_id = ?", p.Id)
+		if err != nil {
+			return err
 		}
 
+		playlistItems := newPlaylistItems(p.Id, cmd.Items)
 		_, err = sess.Insert(&playlistItems)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return insertSchedules(sess, p.Id, cmd.Schedules)
 	})
 	return &dto, err
 }
@@ -165,7 +157,11 @@ func (s *sqlStore) Delete(ctx context.Context, cmd *playlist.DeletePlaylistComma
 
 		var rawItemSQL = "DELETE FROM playlist_item WHERE playlist_id = ?"
 		_, err = sess.Exec(rawItemSQL, playlist.Id)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Exec("DELETE FROM playlist_schedule WHERE playlist_id = ?", playlist.Id)
 		return err
 	})
 }
@@ -213,8 +209,12 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 	var playlistId int64
 	var itemType string
 	var itemValue string
+	var itemDuration string
+	var itemTimeFrom string
+	var itemTimeTo string
 
-	rows, err := db.Query(ctx, `SELECT playlist.id,playlist_item.type,playlist_item.value
+	rows, err := db.Query(ctx, `SELECT playlist.id,playlist_item.type,playlist_item.value,
+		COALESCE(playlist_item.duration, ''),COALESCE(playlist_item.time_from, ''),COALESCE(playlist_item.time_to, '')
 		FROM playlist_item 
 		JOIN playlist ON playlist_item.playlist_id = playlist.id
 		WHERE playlist.org_id = ?
@@ -228,7 +228,7 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 	}()
 
 	for rows.Next() {
-		err = rows.Scan(&playlistId, &itemType, &itemValue)
+		err = rows.Scan(&playlistId, &itemType, &itemValue, &itemDuration, &itemTimeFrom, &itemTimeTo)
 		if err != nil {
 			return nil, err
 		}
@@ -237,12 +237,34 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 			return nil, fmt.Errorf("could not find playlist by id")
 		}
 		items := append(playlists[idx].Items, playlist.PlaylistItemDTO{
-			Type:  itemType,
-			Value: itemValue,
+			Type:      itemType,
+			Value:     itemValue,
+			Duration:  itemDuration,
+			TimeRange: newTimeRange(itemTimeFrom, itemTimeTo),
 		})
 		playlists[idx].Items = items
 	}
-	return playlists, err
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	schedules := []playlistSchedule{}
+	err = db.Select(ctx, &schedules, `SELECT playlist_schedule.*
+		FROM playlist_schedule
+		JOIN playlist ON playlist_schedule.playlist_id = playlist.id
+		WHERE playlist.org_id = ?
+		ORDER BY playlist_schedule.id asc`, orgId)
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		idx, ok := lookup[schedule.PlaylistId]
+		if !ok {
+			continue
+		}
+		playlists[idx].Schedules = append(playlists[idx].Schedules, schedule.toDTO())
+	}
+	return playlists, nil
 }
 
 func (s *sqlStore) GetItems(ctx context.Context, query *playlist.GetPlaylistItemsByUidQuery) ([]playlist.PlaylistItem, error) {
@@ -262,5 +284,105 @@ func (s *sqlStore) GetItems(ctx context.Context, query *playlist.GetPlaylistItem
 
 		return err
 	})
+	for i := range playlistItems {
+		playlistItems[i].TimeRange = newTimeRange(playlistItems[i].TimeFrom, playlistItems[i].TimeTo)
+	}
 	return playlistItems, err
 }
+
+func (s *sqlStore) GetSchedules(ctx context.Context, query *playlist.GetPlaylistItemsByUidQuery) ([]playlist.PlaylistSchedule, error) {
+	result := make([]playlist.PlaylistSchedule, 0)
+	if query.PlaylistUID == "" || query.OrgId == 0 {
+		return result, playlist.ErrCommandValidationFailed
+	}
+	p, err := s.Get(ctx, &playlist.GetPlaylistByUidQuery{UID: query.PlaylistUID, OrgId: query.OrgId})
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]playlistSchedule, 0)
+	err = s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("playlist_id=?", p.Id).Asc("id").Find(&schedules)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		result = append(result, schedule.toDTO())
+	}
+	return result, nil
+}
+
+// playlistSchedule is a schedule of a playlist in the playlist_schedule table
+type playlistSchedule struct {
+	Id         int64  `xorm:"pk autoincr 'id'" db:"id"`
+	PlaylistId int64  `xorm:"playlist_id" db:"playlist_id"`
+	Weekdays   string `xorm:"weekdays" db:"weekdays"`
+	TimeFrom   string `xorm:"time_from" db:"time_from"`
+	TimeTo     string `xorm:"time_to" db:"time_to"`
+	Timezone   string `xorm:"timezone" db:"timezone"`
+	Kiosk      string `xorm:"kiosk" db:"kiosk"`
+}
+
+func (playlistSchedule) TableName() string {
+	return "playlist_schedule"
+}
+
+func (s playlistSchedule) toDTO() playlist.PlaylistSchedule {
+	dto := playlist.PlaylistSchedule{
+		From:     s.TimeFrom,
+		To:       s.TimeTo,
+		Timezone: s.Timezone,
+		Kiosk:    s.Kiosk,
+	}
+	if s.Weekdays != "" {
+		dto.Weekdays = strings.Split(s.Weekdays, ",")
+	}
+	return dto
+}
+
+func insertSchedules(sess *db.Session, playlistId int64, schedules []playlist.PlaylistSchedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+	rows := make([]playlistSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		rows = append(rows, playlistSchedule{
+			PlaylistId: playlistId,
+			Weekdays:   strings.Join(schedule.Weekdays, ","),
+			TimeFrom:   schedule.From,
+			TimeTo:     schedule.To,
+			Timezone:   schedule.Timezone,
+			Kiosk:      schedule.Kiosk,
+		})
+	}
+	_, err := sess.Insert(&rows)
+	return err
+}
+
+func newPlaylistItems(playlistId int64, items []playlist.PlaylistItem) []playlist.PlaylistItem {
+	playlistItems := make([]playlist.PlaylistItem, 0, len(items))
+	for index, item := range items {
+		playlistItem := playlist.PlaylistItem{
+			PlaylistId: playlistId,
+			Type:       item.Type,
+			Value:      item.Value,
+			Order:      index + 1,
+			Title:      item.Title,
+			Duration:   item.Duration,
+		}
+		if item.TimeRange != nil {
+			playlistItem.TimeFrom = item.TimeRange.From
+			playlistItem.TimeTo = item.TimeRange.To
+		}
+		playlistItems = append(playlistItems, playlistItem)
+	}
+	return playlistItems
+}
+
+func newTimeRange(from string, to string) *playlist.PlaylistTimeRange {
+	if from == "" && to == "" {
+		return nil
+	}
+	return &playlist.PlaylistTimeRange{From: from, To: to}
+}
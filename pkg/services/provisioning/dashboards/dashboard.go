@@ -20,12 +20,15 @@ import (
 type DashboardProvisioner interface {
 	HasDashboardSources() bool
 	Provision(ctx context.Context) error
-	PollChanges(ctx context.Context)
+	PollChanges(ctx context.Context, onSyncFailed SyncFailedHandler)
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
 }
 
+// SyncFailedHandler is called when the dashboards of a provider can't be synced while polling for changes.
+type SyncFailedHandler func(ctx context.Context, err error)
+
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
 type DashboardProvisionerFactory func(context.Context, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore, folder.Service, dualwrite.Service) (DashboardProvisioner, error)
 
@@ -118,10 +121,10 @@ func (provider *Provisioner) CleanUpOrphanedDashboards(ctx context.Context) {
 }
 
 // PollChanges starts polling for changes in dashboard definition files. It creates a goroutine for each provider
-// defined in the config.
-func (provider *Provisioner) PollChanges(ctx context.Context) {
+// defined in the config. Failed syncs are passed to onSyncFailed.
+func (provider *Provisioner) PollChanges(ctx context.Context, onSyncFailed SyncFailedHandler) {
 	for _, reader := range provider.fileReaders {
-		go reader.pollChanges(ctx)
+		go reader.pollChanges(ctx, onSyncFailed)
 	}
 
 	go provider.duplicateValidator.Run(ctx)
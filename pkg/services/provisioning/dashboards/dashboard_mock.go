@@ -14,7 +14,7 @@ type calls struct {
 type ProvisionerMock struct {
 	Calls                           *calls
 	ProvisionFunc                   func(ctx context.Context) error
-	PollChangesFunc                 func(ctx context.Context)
+	PollChangesFunc                 func(ctx context.Context, onSyncFailed SyncFailedHandler)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
 }
@@ -40,10 +40,10 @@ func (dpm *ProvisionerMock) Provision(ctx context.Context) error {
 }
 
 // PollChanges is a mock implementation of `Provisioner.PollChanges`
-func (dpm *ProvisionerMock) PollChanges(ctx context.Context) {
+func (dpm *ProvisionerMock) PollChanges(ctx context.Context, onSyncFailed SyncFailedHandler) {
 	dpm.Calls.PollChanges = append(dpm.Calls.PollChanges, ctx)
 	if dpm.PollChangesFunc != nil {
-		dpm.PollChangesFunc(ctx)
+		dpm.PollChangesFunc(ctx, onSyncFailed)
 	}
 }
 
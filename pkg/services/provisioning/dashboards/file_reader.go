@@ -78,13 +78,17 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 }
 
 // pollChanges periodically runs walkDisk based on interval specified in the config.
-func (fr *FileReader) pollChanges(ctx context.Context) {
+// The errors of walkDisk are passed to onSyncFailed.
+func (fr *FileReader) pollChanges(ctx context.Context, onSyncFailed SyncFailedHandler) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
 	for {
 		select {
 		case <-ticker.C:
 			if err := fr.walkDisk(ctx); err != nil {
 				fr.log.Error("failed to search for dashboards", "error", err)
+				if onSyncFailed != nil {
+					onSyncFailed(ctx, fmt.Errorf("provider %s: %w", fr.Cfg.Name, err))
+				}
 			}
 		case <-ctx.Done():
 			return
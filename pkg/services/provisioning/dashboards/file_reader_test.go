@@ -108,6 +108,37 @@ func TestCreatingNewDashboardFileReader(t *testing.T) {
 	})
 }
 
+func TestDashboardFileReader_PollChanges(t *testing.T) {
+	cfg := &config{
+		Name:                  "Default",
+		Type:                  "file",
+		OrgID:                 1,
+		UpdateIntervalSeconds: 1,
+		Options:               map[string]any{"path": filepath.Join(t.TempDir(), "missing")},
+	}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	failed := make(chan error, 1)
+	go reader.pollChanges(ctx, func(ctx context.Context, err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	})
+
+	select {
+	case err := <-failed:
+		require.ErrorIs(t, err, os.ErrNotExist)
+		require.Contains(t, err.Error(), "provider Default")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the failed sync wasn't reported")
+	}
+}
+
 func TestIntegrationDashboardFileReader(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
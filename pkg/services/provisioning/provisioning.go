@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -59,6 +62,7 @@ func ProvideService(
 	resourcePermissions accesscontrol.ReceiverPermissionsService,
 	tracer tracing.Tracer,
 	dual dualwrite.Service,
+	bus bus.Bus,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -85,6 +89,7 @@ func ProvideService(
 		folderService:                folderService,
 		resourcePermissions:          resourcePermissions,
 		tracer:                       tracer,
+		bus:                          bus,
 	}
 
 	if err := s.setDashboardProvisioner(); err != nil {
@@ -167,6 +172,7 @@ type ProvisioningServiceImpl struct {
 	resourcePermissions          accesscontrol.ReceiverPermissionsService
 	tracer                       tracing.Tracer
 	dual                         dualwrite.Service
+	bus                          bus.Bus
 	onceInitProvisioners         sync.Once
 }
 
@@ -228,7 +234,9 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 		// non-deterministically take one of the route possibly going into one polling loop before exiting.
 		pollingContext, cancelFun := context.WithCancel(context.Background())
 		ps.pollingCtxCancel = cancelFun
-		ps.dashboardProvisioner.PollChanges(pollingContext)
+		ps.dashboardProvisioner.PollChanges(pollingContext, func(ctx context.Context, err error) {
+			ps.publishSyncFailed(ctx, "dashboards", err)
+		})
 		ps.mutex.Unlock()
 
 		select {
@@ -250,6 +258,7 @@ func (ps *ProvisioningServiceImpl) ProvisionDatasources(ctx context.Context) err
 	if err := ps.provisionDatasources(ctx, datasourcePath, ps.datasourceService, ps.correlationsService, ps.orgService); err != nil {
 		err = fmt.Errorf("%v: %w", "Datasource provisioning error", err)
 		ps.log.Error("Failed to provision data sources", "error", err)
+		ps.publishSyncFailed(ctx, "data sources", err)
 		return err
 	}
 	return nil
@@ -262,6 +271,7 @@ func (ps *ProvisioningServiceImpl) ProvisionPlugins(ctx context.Context) error {
 	if err := ps.provisionPlugins(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService); err != nil {
 		err = fmt.Errorf("%v: %w", "app provisioning error", err)
 		ps.log.Error("Failed to provision plugins", "error", err)
+		ps.publishSyncFailed(ctx, "plugins", err)
 		return err
 	}
 	return nil
@@ -283,7 +293,9 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 	if err != nil {
 		// If we fail to provision with the new provisioner, the mutex will unlock and the polling will restart with the
 		// old provisioner as we did not switch them yet.
-		return fmt.Errorf("%v: %w", "Failed to provision dashboards", err)
+		err = fmt.Errorf("%v: %w", "Failed to provision dashboards", err)
+		ps.publishSyncFailed(ctx, "dashboards", err)
+		return err
 	}
 	return nil
 }
@@ -332,7 +344,11 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
 	}
-	return ps.provisionAlerting(ctx, cfg)
+	if err := ps.provisionAlerting(ctx, cfg); err != nil {
+		ps.publishSyncFailed(ctx, "alerting", err)
+		return err
+	}
+	return nil
 }
 
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
@@ -343,6 +359,21 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+// publishSyncFailed publishes a ProvisioningSyncFailed event, so that the failure is sent to the
+// system notification targets.
+func (ps *ProvisioningServiceImpl) publishSyncFailed(ctx context.Context, kind string, err error) {
+	if ps.bus == nil {
+		return
+	}
+	if pubErr := ps.bus.Publish(ctx, &events.ProvisioningSyncFailed{
+		Timestamp: time.Now(),
+		Kind:      kind,
+		Error:     err.Error(),
+	}); pubErr != nil {
+		ps.log.Warn("Failed to publish provisioning failure", "kind", kind, "error", pubErr)
+	}
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
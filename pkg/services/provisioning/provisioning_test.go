@@ -153,7 +153,7 @@ func setup(t *testing.T) *serviceTestStruct {
 	serviceStopped := make(chan interface{})
 
 	serviceTest.mock = dashboards.NewDashboardProvisionerMock()
-	serviceTest.mock.PollChangesFunc = func(ctx context.Context) {
+	serviceTest.mock.PollChangesFunc = func(ctx context.Context, onSyncFailed dashboards.SyncFailedHandler) {
 		pollChangesChannel <- ctx
 	}
 
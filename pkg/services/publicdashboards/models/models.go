@@ -48,21 +48,23 @@ type PublicDashboard struct {
 	CreatedAt    time.Time `json:"createdAt" xorm:"created_at"`
 	UpdatedAt    time.Time `json:"updatedAt" xorm:"updated_at"`
 	//config fields
-	TimeSettings         *TimeSettings `json:"-" xorm:"time_settings"`
-	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
-	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
-	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
-	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	TimeSettings         *TimeSettings    `json:"-" xorm:"time_settings"`
+	TimeSelectionEnabled bool             `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
+	IsEnabled            bool             `json:"isEnabled" xorm:"is_enabled"`
+	AnnotationsEnabled   bool             `json:"annotationsEnabled" xorm:"annotations_enabled"`
+	Share                ShareType        `json:"share" xorm:"share"`
+	AllowedVariables     AllowedVariables `json:"allowedVariables" xorm:"allowed_variables"`
+	Recipients           []EmailDTO       `json:"recipients,omitempty" xorm:"-"`
 }
 
 type PublicDashboardDTO struct {
-	Uid                  string    `json:"uid"`
-	AccessToken          string    `json:"accessToken"`
-	TimeSelectionEnabled *bool     `json:"timeSelectionEnabled"`
-	IsEnabled            *bool     `json:"isEnabled"`
-	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
-	Share                ShareType `json:"share"`
+	Uid                  string            `json:"uid"`
+	AccessToken          string            `json:"accessToken"`
+	TimeSelectionEnabled *bool             `json:"timeSelectionEnabled"`
+	IsEnabled            *bool             `json:"isEnabled"`
+	AnnotationsEnabled   *bool             `json:"annotationsEnabled"`
+	Share                ShareType         `json:"share"`
+	AllowedVariables     *AllowedVariables `json:"allowedVariables"`
 }
 
 type EmailDTO struct {
@@ -135,6 +137,43 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// AllowedVariable is a template variable of the dashboard that viewers of the public
+// dashboard can change, with the values they can select.
+type AllowedVariable struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// HasValue returns true if the value is one of the allowed values of the variable.
+func (v AllowedVariable) HasValue(value string) bool {
+	for _, allowed := range v.Values {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+type AllowedVariables []AllowedVariable
+
+// Find returns the allowed variable with the given name, or nil if it isn't allowed.
+func (av AllowedVariables) Find(name string) *AllowedVariable {
+	for i := range av {
+		if av[i].Name == name {
+			return &av[i]
+		}
+	}
+	return nil
+}
+
+func (av *AllowedVariables) FromDB(data []byte) error {
+	return json.Unmarshal(data, av)
+}
+
+func (av *AllowedVariables) ToDB() ([]byte, error) {
+	return json.Marshal(av)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -155,6 +194,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the values of the allowed template variables selected by the viewer.
+	Variables map[string]string
 }
 
 type AnnotationsQueryDTO struct {
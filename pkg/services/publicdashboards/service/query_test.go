@@ -922,6 +922,42 @@ func TestIntegrationBuildMetricRequest(t *testing.T) {
 			reqDTO.Queries[0],
 		)
 	})
+
+	t.Run("metric request built with allowed variables", func(t *testing.T) {
+		customPanels := []interface{}{
+			map[string]interface{}{
+				"id": 1,
+				"datasource": map[string]interface{}{
+					"uid": "ds2",
+				},
+				"targets": []interface{}{
+					map[string]interface{}{
+						"datasource": map[string]interface{}{
+							"type": "prometheus",
+							"uid":  "ds2",
+						},
+						"expr":  `up{region="$region", job="$job"}`,
+						"refId": "A",
+					},
+				},
+			}}
+
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashWithVariables", 1, 0, "", true, []map[string]interface{}{}, customPanels)
+		pubdash := *publicDashboardPD
+		pubdash.AllowedVariables = AllowedVariables{{Name: "region", Values: []string{"us-east", "eu-west"}}}
+
+		reqDTO, err := service.buildMetricRequest(dashboard, &pubdash, 1, publicDashboardQueryDTO)
+		require.NoError(t, err)
+		require.Len(t, reqDTO.Queries, 1)
+		require.Equal(t, `up{region="us-east", job="$job"}`, reqDTO.Queries[0].Get("expr").MustString())
+
+		queryDTO := publicDashboardQueryDTO
+		queryDTO.Variables = map[string]string{"region": "eu-west"}
+		reqDTO, err = service.buildMetricRequest(dashboard, &pubdash, 1, queryDTO)
+		require.NoError(t, err)
+		require.Len(t, reqDTO.Queries, 1)
+		require.Equal(t, `up{region="eu-west", job="$job"}`, reqDTO.Queries[0].Get("expr").MustString())
+	})
 }
 
 func TestGroupQueriesByPanelId(t *testing.T) {
@@ -111,6 +111,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 		PublicDashboardEnabled: pubdash.IsEnabled,
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
+	restrictVariables(dash.Data, pubdash)
 
 	sanitizeData(dash.Data)
 
@@ -224,11 +225,17 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dash, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	if dto.PublicDashboard.AllowedVariables != nil {
+		if err := validation.ValidateAllowedVariables(dash.Data, *dto.PublicDashboard.AllowedVariables); err != nil {
+			return nil, err
+		}
+	}
+
 	// validate the dashboard does not already have a public dashboard
 	existingPubdash, err := pd.FindByDashboardUid(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil && !errors.Is(err, ErrPublicDashboardNotFound) {
@@ -283,11 +290,17 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	// validate dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dash, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	if dto.PublicDashboard.AllowedVariables != nil {
+		if err := validation.ValidateAllowedVariables(dash.Data, *dto.PublicDashboard.AllowedVariables); err != nil {
+			return nil, err
+		}
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, dto.Uid)
 	if err != nil {
@@ -572,6 +585,11 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		share = PublicShareType
 	}
 
+	var allowedVariables AllowedVariables
+	if dto.PublicDashboard.AllowedVariables != nil {
+		allowedVariables = *dto.PublicDashboard.AllowedVariables
+	}
+
 	now := time.Now()
 
 	return &PublicDashboard{
@@ -583,6 +601,7 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
 		Share:                share,
+		AllowedVariables:     allowedVariables,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
 		UpdatedBy:            dto.UserId,
@@ -602,6 +621,11 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	allowedVariables := pd.AllowedVariables
+	if pubdashDTO.AllowedVariables != nil {
+		allowedVariables = *pubdashDTO.AllowedVariables
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
@@ -609,6 +633,7 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		AllowedVariables:     allowedVariables,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
 	}
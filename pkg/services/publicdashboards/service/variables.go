@@ -0,0 +1,149 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// variableHideVariable is the hide option of dashboard variables that hides both the label and the value
+const variableHideVariable = 2
+
+// variableRegex matches the $var, ${var}, ${var:format} and [[var]] template variable syntaxes
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::(\w+))?\}`)
+
+// getVariableValues returns the values of the allowed variables of the public dashboard. The values selected by the
+// viewer are validated beforehand, variables without a selected value use the value saved in the dashboard if it is
+// allowed, or the first allowed value.
+func getVariableValues(dashboard *simplejson.Json, pubdash *models.PublicDashboard, selected map[string]string) map[string]string {
+	values := make(map[string]string, len(pubdash.AllowedVariables))
+	for _, v := range pubdash.AllowedVariables {
+		if value, ok := selected[v.Name]; ok {
+			values[v.Name] = value
+		} else {
+			values[v.Name] = getDefaultVariableValue(dashboard, v)
+		}
+	}
+	return values
+}
+
+func getDefaultVariableValue(dashboard *simplejson.Json, variable models.AllowedVariable) string {
+	for _, v := range dashboard.GetPath("templating", "list").MustArray() {
+		dashVariable := simplejson.NewFromAny(v)
+		if dashVariable.Get("name").MustString() != variable.Name {
+			continue
+		}
+
+		current := dashVariable.GetPath("current", "value")
+		value, err := current.String()
+		if err != nil {
+			// multi-value variables save an array of values
+			value = current.GetIndex(0).MustString()
+		}
+		if variable.HasValue(value) {
+			return value
+		}
+	}
+
+	if len(variable.Values) == 0 {
+		return ""
+	}
+	return variable.Values[0]
+}
+
+// interpolateVariables returns a copy of the query with the references to the given variables replaced in every
+// string. References to other variables are left as they are. The query shares its values with the dashboard, so
+// it is copied rather than modified.
+func interpolateVariables(query *simplejson.Json, values map[string]string) *simplejson.Json {
+	if len(values) == 0 {
+		return query
+	}
+	return simplejson.NewFromAny(interpolateValue(query.Interface(), values))
+}
+
+func interpolateValue(value any, values map[string]string) any {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, values)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = interpolateValue(item, values)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = interpolateValue(item, values)
+		}
+		return result
+	}
+	return value
+}
+
+func interpolateString(s string, values map[string]string) string {
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name, format := groups[1]+groups[2]+groups[4], groups[3]+groups[5]
+
+		value, ok := values[name]
+		if !ok {
+			return match
+		}
+		return formatVariableValue(value, format)
+	})
+}
+
+// formatVariableValue applies the formats of the ${var:format} syntax that are meaningful for a single value
+func formatVariableValue(value string, format string) string {
+	switch format {
+	case "regex":
+		return regexp.QuoteMeta(value)
+	case "singlequote":
+		return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+	case "doublequote":
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	case "sqlstring":
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case "percentencode":
+		return url.QueryEscape(value)
+	case "queryparam":
+		return "var-" + url.QueryEscape(value)
+	}
+	return value
+}
+
+// restrictVariables turns the allowed variables of the dashboard into custom variables with the allowed values as
+// options, so viewers can only select those values and the variables don't run queries. The other variables are
+// hidden, since viewers can't change them.
+func restrictVariables(dashboard *simplejson.Json, pubdash *models.PublicDashboard) {
+	for _, v := range dashboard.GetPath("templating", "list").MustArray() {
+		dashVariable := simplejson.NewFromAny(v)
+		allowed := pubdash.AllowedVariables.Find(dashVariable.Get("name").MustString())
+		if allowed == nil {
+			dashVariable.Set("hide", variableHideVariable)
+			continue
+		}
+
+		current := getDefaultVariableValue(dashboard, *allowed)
+		options := make([]any, 0, len(allowed.Values))
+		escaped := make([]string, 0, len(allowed.Values))
+		for _, value := range allowed.Values {
+			options = append(options, map[string]any{"text": value, "value": value, "selected": value == current})
+			escaped = append(escaped, strings.ReplaceAll(value, ",", `\,`))
+		}
+
+		dashVariable.Set("type", "custom")
+		dashVariable.Set("query", strings.Join(escaped, ","))
+		dashVariable.Set("options", options)
+		dashVariable.Set("current", map[string]any{"text": current, "value": current})
+		dashVariable.Set("multi", false)
+		dashVariable.Set("includeAll", false)
+		dashVariable.Del("datasource")
+		dashVariable.Del("definition")
+		dashVariable.Del("regex")
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func dashboardWithVariables() *simplejson.Json {
+	return simplejson.NewFromAny(map[string]any{
+		"templating": map[string]any{
+			"list": []any{
+				map[string]any{
+					"name":       "region",
+					"type":       "query",
+					"query":      "label_values(up, region)",
+					"datasource": map[string]any{"uid": "prom"},
+					"current":    map[string]any{"text": "eu-west", "value": "eu-west"},
+					"multi":      true,
+				},
+				map[string]any{
+					"name":    "service",
+					"type":    "custom",
+					"current": map[string]any{"text": []any{"web", "api"}, "value": []any{"web", "api"}},
+				},
+				map[string]any{
+					"name":    "job",
+					"type":    "textbox",
+					"current": map[string]any{"text": "node", "value": "node"},
+				},
+			},
+		},
+	})
+}
+
+func TestGetVariableValues(t *testing.T) {
+	pubdash := &PublicDashboard{
+		AllowedVariables: AllowedVariables{
+			{Name: "region", Values: []string{"us-east", "eu-west"}},
+			{Name: "service", Values: []string{"api", "db"}},
+		},
+	}
+
+	t.Run("uses the selected values", func(t *testing.T) {
+		values := getVariableValues(dashboardWithVariables(), pubdash, map[string]string{"region": "us-east", "service": "db"})
+		assert.Equal(t, map[string]string{"region": "us-east", "service": "db"}, values)
+	})
+
+	t.Run("uses the dashboard value if it is allowed or the first allowed value", func(t *testing.T) {
+		values := getVariableValues(dashboardWithVariables(), pubdash, nil)
+		assert.Equal(t, map[string]string{"region": "eu-west", "service": "api"}, values)
+	})
+
+	t.Run("returns no values without allowed variables", func(t *testing.T) {
+		values := getVariableValues(dashboardWithVariables(), &PublicDashboard{}, map[string]string{"region": "us-east"})
+		assert.Empty(t, values)
+	})
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	values := map[string]string{"region": "eu-west", "service": "api.v1"}
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{name: "dollar syntax", query: `up{region="$region"}`, expected: `up{region="eu-west"}`},
+		{name: "braces syntax", query: `up{region="${region}"}`, expected: `up{region="eu-west"}`},
+		{name: "brackets syntax", query: `up{region="[[region]]"}`, expected: `up{region="eu-west"}`},
+		{name: "regex format", query: `up{service=~"${service:regex}"}`, expected: `up{service=~"api\.v1"}`},
+		{name: "sqlstring format", query: `WHERE service = ${service:sqlstring}`, expected: `WHERE service = 'api.v1'`},
+		{name: "other variables are not replaced", query: `up{job="$job", region="$regions"}`, expected: `up{job="$job", region="$regions"}`},
+		{name: "built-in variables are not replaced", query: `rate(up[$__rate_interval])`, expected: `rate(up[$__rate_interval])`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := simplejson.NewFromAny(map[string]any{"refId": "A", "expr": tc.query})
+			result := interpolateVariables(query, values)
+			assert.Equal(t, tc.expected, result.Get("expr").MustString())
+			assert.Equal(t, tc.query, query.Get("expr").MustString())
+		})
+	}
+
+	t.Run("replaces variables in nested values", func(t *testing.T) {
+		query := simplejson.NewFromAny(map[string]any{
+			"datasource": map[string]any{"uid": "${region}"},
+			"filters":    []any{map[string]any{"value": "$service"}, 1},
+		})
+		result := interpolateVariables(query, values)
+		assert.Equal(t, "eu-west", result.GetPath("datasource", "uid").MustString())
+		assert.Equal(t, "api.v1", result.Get("filters").GetIndex(0).Get("value").MustString())
+		assert.Equal(t, 1, result.Get("filters").GetIndex(1).MustInt())
+	})
+}
+
+func TestRestrictVariables(t *testing.T) {
+	dashboard := dashboardWithVariables()
+	pubdash := &PublicDashboard{
+		AllowedVariables: AllowedVariables{{Name: "region", Values: []string{"us-east", "eu-west", "ap,south"}}},
+	}
+
+	restrictVariables(dashboard, pubdash)
+
+	region := dashboard.GetPath("templating", "list").GetIndex(0)
+	assert.Equal(t, "custom", region.Get("type").MustString())
+	assert.Equal(t, `us-east,eu-west,ap\,south`, region.Get("query").MustString())
+	assert.False(t, region.Get("multi").MustBool())
+	assert.Equal(t, "eu-west", region.GetPath("current", "value").MustString())
+	_, ok := region.CheckGet("datasource")
+	assert.False(t, ok)
+
+	options := region.Get("options").MustArray()
+	require.Len(t, options, 3)
+	assert.Equal(t, map[string]any{"text": "eu-west", "value": "eu-west", "selected": true}, options[1])
+
+	// variables that are not allowed are hidden
+	job := dashboard.GetPath("templating", "list").GetIndex(2)
+	assert.Equal(t, "textbox", job.Get("type").MustString())
+	assert.Equal(t, 2, job.Get("hide").MustInt())
+}
@@ -3,6 +3,7 @@ package validation
 import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -16,6 +17,37 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 	return nil
 }
 
+// ValidateAllowedVariables asserts that the allowed variables are template variables of the
+// dashboard that can be substituted in queries, and that each of them has at least one value.
+func ValidateAllowedVariables(dashboard *simplejson.Json, variables AllowedVariables) error {
+	types := make(map[string]string)
+	for _, v := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(v)
+		types[variable.Get("name").MustString()] = variable.Get("type").MustString()
+	}
+
+	seen := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		t, ok := types[v.Name]
+		if !ok {
+			return ErrInvalidAllowedVariables.Errorf("ValidateAllowedVariables: variable %s not found in dashboard", v.Name)
+		}
+		// ad hoc filters are not referenced by name in queries
+		if t == "adhoc" {
+			return ErrInvalidAllowedVariables.Errorf("ValidateAllowedVariables: variable %s is an ad hoc filter", v.Name)
+		}
+		if seen[v.Name] {
+			return ErrInvalidAllowedVariables.Errorf("ValidateAllowedVariables: variable %s is allowed more than once", v.Name)
+		}
+		if len(v.Values) == 0 {
+			return ErrInvalidAllowedVariables.Errorf("ValidateAllowedVariables: variable %s has no values", v.Name)
+		}
+		seen[v.Name] = true
+	}
+
+	return nil
+}
+
 func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
 	if req.IntervalMs < 0 {
 		return ErrInvalidInterval.Errorf("ValidateQueryPublicDashboardRequest: intervalMS should be greater than 0")
@@ -38,6 +70,14 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 		}
 	}
 
+	// the values of variables that are not allowed are ignored, they are never interpolated
+	for name, value := range req.Variables {
+		variable := pd.AllowedVariables.Find(name)
+		if variable != nil && !variable.HasValue(value) {
+			return ErrInvalidVariableValue.Errorf("ValidateQueryPublicDashboardRequest: value of variable %s is not allowed", name)
+		}
+	}
+
 	return nil
 }
 
@@ -3,6 +3,7 @@ package validation
 import (
 	"testing"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -129,6 +130,42 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns no error when variable value is allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string]string{"region": "eu-west"},
+				},
+				pd: &PublicDashboard{
+					AllowedVariables: AllowedVariables{{Name: "region", Values: []string{"us-east", "eu-west"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when variable value is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string]string{"region": "ap-south"},
+				},
+				pd: &PublicDashboard{
+					AllowedVariables: AllowedVariables{{Name: "region", Values: []string{"us-east", "eu-west"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns no error when variable is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string]string{"service": "api"},
+				},
+				pd: &PublicDashboard{
+					AllowedVariables: AllowedVariables{{Name: "region", Values: []string{"us-east", "eu-west"}}},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -139,6 +176,45 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 	}
 }
 
+func TestValidateAllowedVariables(t *testing.T) {
+	dashboard := simplejson.NewFromAny(map[string]any{
+		"templating": map[string]any{
+			"list": []any{
+				map[string]any{"name": "region", "type": "query"},
+				map[string]any{"name": "filters", "type": "adhoc"},
+			},
+		},
+	})
+
+	t.Run("Returns no error when variables are in the dashboard", func(t *testing.T) {
+		err := ValidateAllowedVariables(dashboard, AllowedVariables{{Name: "region", Values: []string{"eu-west"}}})
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when variable is not in the dashboard", func(t *testing.T) {
+		err := ValidateAllowedVariables(dashboard, AllowedVariables{{Name: "service", Values: []string{"api"}}})
+		require.ErrorIs(t, err, ErrInvalidAllowedVariables)
+	})
+
+	t.Run("Returns error when variable is an ad hoc filter", func(t *testing.T) {
+		err := ValidateAllowedVariables(dashboard, AllowedVariables{{Name: "filters", Values: []string{"job"}}})
+		require.ErrorIs(t, err, ErrInvalidAllowedVariables)
+	})
+
+	t.Run("Returns error when variable has no values", func(t *testing.T) {
+		err := ValidateAllowedVariables(dashboard, AllowedVariables{{Name: "region"}})
+		require.ErrorIs(t, err, ErrInvalidAllowedVariables)
+	})
+
+	t.Run("Returns error when variable is allowed twice", func(t *testing.T) {
+		err := ValidateAllowedVariables(dashboard, AllowedVariables{
+			{Name: "region", Values: []string{"eu-west"}},
+			{Name: "region", Values: []string{"us-east"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidAllowedVariables)
+	})
+}
+
 func TestValidAccessToken(t *testing.T) {
 	t.Run("true", func(t *testing.T) {
 		uuid := "da82510c2aa64d78a2e87fef36c58e89"
@@ -23,6 +23,19 @@ func (s *QueryHistoryService) registerAPIEndpoints() {
 		entities.Delete("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.unstarHandler, "Failed to unstar query history")))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.patchCommentHandler, "Failed to update comment of query in query history")))
 	})
+
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := ScopeSavedQueriesProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+	s.RouteRegister.Group("/api/saved-queries", func(entities routing.RouteRegister) {
+		entities.Get("/", authorize(ac.EvalPermission(ActionSavedQueriesRead)), routing.Wrap(s.searchSavedQueriesHandler))
+		entities.Post("/", authorize(ac.EvalPermission(ActionSavedQueriesCreate)), routing.Wrap(s.createSavedQueryHandler))
+		entities.Get("/:uid", authorize(ac.EvalPermission(ActionSavedQueriesRead, uidScope)), routing.Wrap(s.getSavedQueryHandler))
+		entities.Put("/:uid", authorize(ac.EvalPermission(ActionSavedQueriesWrite, uidScope)), routing.Wrap(s.updateSavedQueryHandler))
+		entities.Delete("/:uid", authorize(ac.EvalPermission(ActionSavedQueriesDelete, uidScope)), routing.Wrap(s.deleteSavedQueryHandler))
+		entities.Post("/:uid/use", authorize(ac.EvalPermission(ActionSavedQueriesRead, uidScope)), routing.Wrap(s.useSavedQueryHandler))
+		entities.Get("/:uid/versions", authorize(ac.EvalPermission(ActionSavedQueriesRead, uidScope)), routing.Wrap(s.getSavedQueryVersionsHandler))
+		entities.Post("/:uid/versions/:version/restore", authorize(ac.EvalPermission(ActionSavedQueriesWrite, uidScope)), routing.Wrap(s.restoreSavedQueryVersionHandler))
+	})
 }
 
 type CallbackHandler func(c *contextmodel.ReqContext) response.Response
@@ -88,6 +101,11 @@ func (s *QueryHistoryService) searchHandler(c *contextmodel.ReqContext) response
 		To:             timeRange.GetToAsSecondsEpoch(),
 	}
 
+	if c.QueryBoolWithDefault("includeSaved", false) {
+		hasAccess := ac.HasAccess(s.accessControl, c)
+		query.IncludeSaved = hasAccess(ac.EvalPermission(ActionSavedQueriesRead))
+	}
+
 	result, err := s.SearchInQueryHistory(c.Req.Context(), c.SignedInUser, query)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to get query history", err)
@@ -246,6 +264,10 @@ type SearchQueriesParams struct {
 	// in:query
 	// required: false
 	To int64 `json:"to"`
+	// Flag indicating if the saved queries of the same data sources should be returned next to the query history
+	// in:query
+	// required: false
+	IncludeSaved bool `json:"includeSaved"`
 }
 
 // swagger:parameters createQuery
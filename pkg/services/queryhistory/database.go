@@ -132,6 +132,19 @@ func (s QueryHistoryService) searchQueries(ctx context.Context, user *user.Signe
 		PerPage:      query.Limit,
 	}
 
+	if query.IncludeSaved {
+		saved, err := s.searchSavedQueries(ctx, user, SearchSavedQueriesQuery{
+			DatasourceUIDs: query.DatasourceUIDs,
+			SearchString:   query.SearchString,
+			Sort:           "usage-desc",
+			Limit:          query.Limit,
+		})
+		if err != nil {
+			return QueryHistorySearchResult{}, err
+		}
+		response.SavedQueries = saved.SavedQueries
+	}
+
 	return response, nil
 }
 
@@ -40,6 +40,9 @@ type SearchInQueryHistoryQuery struct {
 	Limit          int      `json:"limit"`
 	From           int64    `json:"from"`
 	To             int64    `json:"to"`
+	// IncludeSaved adds the saved queries the user can see, of the same data sources and
+	// matching the search string, to the result.
+	IncludeSaved bool `json:"includeSaved"`
 }
 
 type QueryHistoryDTO struct {
@@ -62,6 +65,8 @@ type QueryHistorySearchResult struct {
 	QueryHistory []QueryHistoryDTO `json:"queryHistory"`
 	Page         int               `json:"page"`
 	PerPage      int               `json:"perPage"`
+	// SavedQueries are the most used saved queries matching the search, if they were included.
+	SavedQueries []SavedQueryDTO `json:"savedQueries,omitempty"`
 }
 
 type QueryHistorySearchResponse struct {
@@ -8,11 +8,12 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl) *QueryHistoryService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl, accesscontrolService ac.Service, teamService team.Service) (*QueryHistoryService, error) {
 	s := &QueryHistoryService{
 		store:         sqlStore,
 		Cfg:           cfg,
@@ -20,14 +21,18 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.Rout
 		log:           log.New("query-history"),
 		now:           time.Now,
 		accessControl: accessControl,
+		teamService:   teamService,
 	}
 
 	// Register routes only when query history is enabled
 	if s.Cfg.QueryHistoryEnabled {
+		if err := declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
 		s.registerAPIEndpoints()
 	}
 
-	return s
+	return s, nil
 }
 
 type Service interface {
@@ -39,6 +44,17 @@ type Service interface {
 	UnstarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
 	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
 	EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error)
+
+	CreateSavedQuery(ctx context.Context, user *user.SignedInUser, cmd CreateSavedQueryCommand) (SavedQueryDTO, error)
+	UpdateSavedQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateSavedQueryCommand) (SavedQueryDTO, error)
+	GetSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error)
+	SearchSavedQueries(ctx context.Context, user *user.SignedInUser, query SearchSavedQueriesQuery) (SavedQuerySearchResult, error)
+	DeleteSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) error
+	// UseSavedQuery counts a use of the saved query, for example when it is run in Explore.
+	UseSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error)
+	GetSavedQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]SavedQueryVersionDTO, error)
+	// RestoreSavedQueryVersion saves the content of a previous version as a new version of the saved query.
+	RestoreSavedQueryVersion(ctx context.Context, user *user.SignedInUser, UID string, version int) (SavedQueryDTO, error)
 }
 
 type QueryHistoryService struct {
@@ -48,6 +64,7 @@ type QueryHistoryService struct {
 	log           log.Logger
 	now           func() time.Time
 	accessControl ac.AccessControl
+	teamService   team.Service
 }
 
 func (s QueryHistoryService) CreateQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
@@ -81,3 +98,35 @@ func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Contex
 func (s QueryHistoryService) EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error) {
 	return s.enforceQueryHistoryRowLimit(ctx, limit, starredQueries)
 }
+
+func (s QueryHistoryService) CreateSavedQuery(ctx context.Context, user *user.SignedInUser, cmd CreateSavedQueryCommand) (SavedQueryDTO, error) {
+	return s.createSavedQuery(ctx, user, cmd)
+}
+
+func (s QueryHistoryService) UpdateSavedQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateSavedQueryCommand) (SavedQueryDTO, error) {
+	return s.updateSavedQuery(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) GetSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error) {
+	return s.getSavedQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) SearchSavedQueries(ctx context.Context, user *user.SignedInUser, query SearchSavedQueriesQuery) (SavedQuerySearchResult, error) {
+	return s.searchSavedQueries(ctx, user, query)
+}
+
+func (s QueryHistoryService) DeleteSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) error {
+	return s.deleteSavedQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) UseSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error) {
+	return s.useSavedQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) GetSavedQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]SavedQueryVersionDTO, error) {
+	return s.getSavedQueryVersions(ctx, user, UID)
+}
+
+func (s QueryHistoryService) RestoreSavedQueryVersion(ctx context.Context, user *user.SignedInUser, UID string, version int) (SavedQueryDTO, error) {
+	return s.restoreSavedQueryVersion(ctx, user, UID, version)
+}
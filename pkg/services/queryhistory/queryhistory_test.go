@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlestest"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/userimpl"
 	"github.com/grafana/grafana/pkg/setting"
@@ -44,6 +45,7 @@ func TestMain(m *testing.M) {
 type scenarioContext struct {
 	ctx           *web.Context
 	service       *QueryHistoryService
+	teamService   *teamtest.FakeService
 	reqContext    *contextmodel.ReqContext
 	sqlStore      db.DB
 	initialResult QueryHistoryResponse
@@ -59,11 +61,13 @@ func testScenario(t *testing.T, desc string, isViewer bool, hasDatasourceExplore
 		}}
 		ctx.Req.Header.Add("Content-Type", "application/json")
 		sqlStore, cfg := db.InitTestDBWithCfg(t)
+		teamService := teamtest.NewFakeService()
 		service := QueryHistoryService{
 			Cfg:           setting.NewCfg(),
 			store:         sqlStore,
 			now:           time.Now,
 			accessControl: accesscontrolmock.New(),
+			teamService:   teamService,
 		}
 		service.Cfg.QueryHistoryEnabled = true
 		quotaService := quotatest.New(false, nil)
@@ -82,11 +86,12 @@ func testScenario(t *testing.T, desc string, isViewer bool, hasDatasourceExplore
 			role = org.RoleEditor
 		}
 
-		permissions := make(map[int64]map[string][]string)
+		permissions := map[int64]map[string][]string{
+			testOrgID: {ActionSavedQueriesRead: {ScopeSavedQueriesAll}},
+		}
 
 		if hasDatasourceExplorePermission {
-			permissions[testUserID] = make(map[string][]string)
-			permissions[testUserID]["datasources:explore"] = []string{}
+			permissions[testOrgID]["datasources:explore"] = []string{}
 		}
 
 		usr := user.SignedInUser{
@@ -108,9 +113,10 @@ func testScenario(t *testing.T, desc string, isViewer bool, hasDatasourceExplore
 		require.NoError(t, err)
 
 		sc := scenarioContext{
-			ctx:      &ctx,
-			service:  &service,
-			sqlStore: sqlStore,
+			ctx:         &ctx,
+			service:     &service,
+			teamService: teamService,
+			sqlStore:    sqlStore,
 			reqContext: &contextmodel.ReqContext{
 				Context:      &ctx,
 				SignedInUser: &usr,
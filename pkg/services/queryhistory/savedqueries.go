@@ -0,0 +1,270 @@
+package queryhistory
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	ActionSavedQueriesRead   = "savedqueries:read"
+	ActionSavedQueriesCreate = "savedqueries:create"
+	ActionSavedQueriesWrite  = "savedqueries:write"
+	ActionSavedQueriesDelete = "savedqueries:delete"
+	// ActionSavedQueriesAdmin grants access to all the saved queries of the organization, whoever owns them.
+	ActionSavedQueriesAdmin = "savedqueries:admin"
+
+	ScopeSavedQueriesRoot = "savedqueries"
+)
+
+var (
+	ScopeSavedQueriesProvider = ac.NewScopeProvider(ScopeSavedQueriesRoot)
+	ScopeSavedQueriesAll      = ScopeSavedQueriesProvider.GetResourceAllScope()
+)
+
+var (
+	ErrSavedQueryNotFound        = errutil.NotFound("savedqueries.not-found", errutil.WithPublicMessage("Saved query not found"))
+	ErrSavedQueryVersionNotFound = errutil.NotFound("savedqueries.version-not-found", errutil.WithPublicMessage("Version of the saved query not found"))
+	ErrSavedQueryNotOwner        = errutil.Forbidden("savedqueries.not-owner", errutil.WithPublicMessage("Only the creator and the owner team of the saved query can change it"))
+	ErrInvalidSavedQuery         = errutil.BadRequest("savedqueries.invalid").MustTemplate("invalid saved query: {{ .Public.Reason }}", errutil.WithPublic("Invalid saved query: {{ .Public.Reason }}"))
+)
+
+func invalidSavedQuery(format string, args ...any) error {
+	return ErrInvalidSavedQuery.Build(errutil.TemplateData{
+		Public: map[string]any{"Reason": fmt.Sprintf(format, args...)},
+	})
+}
+
+// SavedQueryVisibility is who can find and use a saved query, in addition to its creator.
+type SavedQueryVisibility string
+
+const (
+	// SavedQueryVisibilityPrivate saved queries are only visible to their creator.
+	SavedQueryVisibilityPrivate SavedQueryVisibility = "private"
+	// SavedQueryVisibilityTeam saved queries are visible to the members of their owner team.
+	SavedQueryVisibilityTeam SavedQueryVisibility = "team"
+	// SavedQueryVisibilityOrg saved queries are visible to the whole organization.
+	SavedQueryVisibilityOrg SavedQueryVisibility = "org"
+)
+
+func (v SavedQueryVisibility) IsValid() bool {
+	return v == SavedQueryVisibilityPrivate || v == SavedQueryVisibilityTeam || v == SavedQueryVisibilityOrg
+}
+
+// SavedQuery is the model for saved queries shared from the query history
+type SavedQuery struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	UID            string `xorm:"uid"`
+	OrgID          int64  `xorm:"org_id"`
+	Title          string
+	Description    string
+	DatasourceUID  string `xorm:"datasource_uid"`
+	DatasourceType string
+	Queries        *simplejson.Json
+	Visibility     SavedQueryVisibility
+	// TeamID is the owner team of the saved query, its members can change it.
+	TeamID     int64 `xorm:"team_id"`
+	Version    int
+	UsageCount int64
+	LastUsedAt int64
+	CreatedBy  int64
+	CreatedAt  int64
+	UpdatedBy  int64
+	UpdatedAt  int64
+}
+
+// SavedQueryTag is the model for the tags of saved queries
+type SavedQueryTag struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	QueryUID string `xorm:"query_uid"`
+	Term     string
+}
+
+// SavedQueryVersion is the model for the content of every version of a saved query
+type SavedQueryVersion struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	OrgID          int64  `xorm:"org_id"`
+	QueryUID       string `xorm:"query_uid"`
+	Version        int
+	Title          string
+	Description    string
+	Tags           *simplejson.Json
+	DatasourceUID  string `xorm:"datasource_uid"`
+	DatasourceType string
+	Queries        *simplejson.Json
+	CreatedBy      int64
+	CreatedAt      int64
+}
+
+type SavedQueryDTO struct {
+	UID            string               `json:"uid"`
+	Title          string               `json:"title"`
+	Description    string               `json:"description"`
+	Tags           []string             `json:"tags"`
+	DatasourceUID  string               `json:"datasourceUid"`
+	DatasourceType string               `json:"datasourceType"`
+	Queries        *simplejson.Json     `json:"queries"`
+	Visibility     SavedQueryVisibility `json:"visibility"`
+	TeamID         int64                `json:"teamId"`
+	Version        int                  `json:"version"`
+	UsageCount     int64                `json:"usageCount"`
+	LastUsedAt     int64                `json:"lastUsedAt"`
+	CreatedBy      int64                `json:"createdBy"`
+	CreatedAt      int64                `json:"createdAt"`
+	UpdatedBy      int64                `json:"updatedBy"`
+	UpdatedAt      int64                `json:"updatedAt"`
+}
+
+type SavedQueryVersionDTO struct {
+	Version        int              `json:"version"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Tags           []string         `json:"tags"`
+	DatasourceUID  string           `json:"datasourceUid"`
+	DatasourceType string           `json:"datasourceType"`
+	Queries        *simplejson.Json `json:"queries"`
+	CreatedBy      int64            `json:"createdBy"`
+	CreatedAt      int64            `json:"createdAt"`
+}
+
+// SavedQueryResponse is a response struct for SavedQueryDTO
+type SavedQueryResponse struct {
+	Result SavedQueryDTO `json:"result"`
+}
+
+type SavedQuerySearchResult struct {
+	TotalCount   int             `json:"totalCount"`
+	SavedQueries []SavedQueryDTO `json:"savedQueries"`
+	Page         int             `json:"page"`
+	PerPage      int             `json:"perPage"`
+}
+
+type SavedQuerySearchResponse struct {
+	Result SavedQuerySearchResult `json:"result"`
+}
+
+type SavedQueryVersionsResponse struct {
+	Result []SavedQueryVersionDTO `json:"result"`
+}
+
+type SearchSavedQueriesQuery struct {
+	DatasourceUIDs []string             `json:"datasourceUids"`
+	DatasourceType string               `json:"datasourceType"`
+	SearchString   string               `json:"searchString"`
+	Tags           []string             `json:"tags"`
+	Visibility     SavedQueryVisibility `json:"visibility"`
+	TeamID         int64                `json:"teamId"`
+	Sort           string               `json:"sort"`
+	Page           int                  `json:"page"`
+	Limit          int                  `json:"limit"`
+}
+
+// CreateSavedQueryCommand is the command for saving queries in the saved query library
+// swagger:model
+type CreateSavedQueryCommand struct {
+	// Title of the saved query.
+	// required: true
+	Title string `json:"title"`
+	// Description of the saved query.
+	Description string `json:"description"`
+	// Tags of the saved query.
+	Tags []string `json:"tags"`
+	// UID of the data source of the queries.
+	// example: PE1C5CBDA0504A6A3
+	DatasourceUID string `json:"datasourceUid"`
+	// Type of the data source of the queries.
+	// example: prometheus
+	DatasourceType string `json:"datasourceType"`
+	// The JSON model of queries. Required unless queryHistoryUid is set.
+	Queries *simplejson.Json `json:"queries"`
+	// UID of a query in the query history of the user to save, instead of queries.
+	QueryHistoryUID string `json:"queryHistoryUid"`
+	// Who can use the saved query: private, team or org. The default value is private.
+	Visibility SavedQueryVisibility `json:"visibility"`
+	// ID of the owner team of the saved query. Required for team visibility.
+	TeamID int64 `json:"teamId"`
+}
+
+// UpdateSavedQueryCommand is the command for updating a saved query, it creates a new version
+// swagger:model
+type UpdateSavedQueryCommand struct {
+	// Title of the saved query.
+	// required: true
+	Title string `json:"title"`
+	// Description of the saved query.
+	Description string `json:"description"`
+	// Tags of the saved query.
+	Tags []string `json:"tags"`
+	// UID of the data source of the queries.
+	DatasourceUID string `json:"datasourceUid"`
+	// Type of the data source of the queries.
+	DatasourceType string `json:"datasourceType"`
+	// The JSON model of queries.
+	// required: true
+	Queries *simplejson.Json `json:"queries"`
+	// Who can use the saved query: private, team or org.
+	Visibility SavedQueryVisibility `json:"visibility"`
+	// ID of the owner team of the saved query. Required for team visibility.
+	TeamID int64 `json:"teamId"`
+}
+
+var (
+	savedQueriesReaderRole = ac.RoleDTO{
+		Name:        "fixed:savedqueries:reader",
+		DisplayName: "Reader",
+		Description: "Find and use the saved queries shared with the user.",
+		Group:       "Saved queries",
+		Permissions: []ac.Permission{
+			{Action: ActionSavedQueriesRead, Scope: ScopeSavedQueriesAll},
+		},
+	}
+
+	savedQueriesWriterRole = ac.RoleDTO{
+		Name:        "fixed:savedqueries:writer",
+		DisplayName: "Writer",
+		Description: "Create saved queries, and update and delete the saved queries owned by the user or their teams.",
+		Group:       "Saved queries",
+		Permissions: []ac.Permission{
+			{Action: ActionSavedQueriesCreate},
+			{Action: ActionSavedQueriesRead, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesWrite, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesDelete, Scope: ScopeSavedQueriesAll},
+		},
+	}
+
+	savedQueriesAdminRole = ac.RoleDTO{
+		Name:        "fixed:savedqueries:admin",
+		DisplayName: "Admin",
+		Description: "Create saved queries, and find, update and delete all the saved queries of the organization.",
+		Group:       "Saved queries",
+		Permissions: []ac.Permission{
+			{Action: ActionSavedQueriesCreate},
+			{Action: ActionSavedQueriesRead, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesWrite, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesDelete, Scope: ScopeSavedQueriesAll},
+			{Action: ActionSavedQueriesAdmin},
+		},
+	}
+)
+
+func declareFixedRoles(service ac.Service) error {
+	reader := ac.RoleRegistration{
+		Role:   savedQueriesReaderRole,
+		Grants: []string{string(org.RoleViewer)},
+	}
+	writer := ac.RoleRegistration{
+		Role:   savedQueriesWriterRole,
+		Grants: []string{string(org.RoleEditor)},
+	}
+
+	admin := ac.RoleRegistration{
+		Role:   savedQueriesAdminRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	return service.DeclareFixedRoles(reader, writer, admin)
+}
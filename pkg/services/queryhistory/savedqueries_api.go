@@ -0,0 +1,297 @@
+package queryhistory
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /saved-queries saved_queries searchSavedQueries
+//
+// Saved query search.
+//
+// Returns the saved queries the user can see that match the search criteria: their own, the ones shared with their teams and the ones shared with the organization.
+// The search supports pagination. Use the `limit` parameter to control the maximum number of queries returned; the default limit is 100.
+//
+// Responses:
+// 200: getSavedQuerySearchResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *QueryHistoryService) searchSavedQueriesHandler(c *contextmodel.ReqContext) response.Response {
+	query := SearchSavedQueriesQuery{
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		DatasourceType: c.Query("datasourceType"),
+		SearchString:   c.Query("searchString"),
+		Tags:           c.QueryStrings("tag"),
+		Visibility:     SavedQueryVisibility(c.Query("visibility")),
+		TeamID:         c.QueryInt64("teamId"),
+		Sort:           c.Query("sort"),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+	}
+
+	result, err := s.SearchSavedQueries(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to search saved queries", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQuerySearchResponse{Result: result})
+}
+
+// swagger:route POST /saved-queries saved_queries createSavedQuery
+//
+// Save query.
+//
+// Adds a query to the saved query library. The queries can be copied from the query history of the user with `queryHistoryUid`.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *QueryHistoryService) createSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateSavedQueryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query, err := s.CreateSavedQuery(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route GET /saved-queries/{saved_query_uid} saved_queries getSavedQuery
+//
+// Get saved query.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	query, err := s.GetSavedQuery(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route PUT /saved-queries/{saved_query_uid} saved_queries updateSavedQuery
+//
+// Update saved query.
+//
+// Replaces the saved query and adds a new version. Only the creator and the members of the owner team of the saved query can update it.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) updateSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := UpdateSavedQueryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query, err := s.UpdateSavedQuery(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route DELETE /saved-queries/{saved_query_uid} saved_queries deleteSavedQuery
+//
+// Delete saved query.
+//
+// Deletes the saved query and all of its versions. Only the creator and the members of the owner team of the saved query can delete it.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) deleteSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteSavedQuery(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete saved query", err)
+	}
+
+	return response.Success("Saved query deleted")
+}
+
+// swagger:route POST /saved-queries/{saved_query_uid}/use saved_queries useSavedQuery
+//
+// Count use of saved query.
+//
+// Increments the usage count of the saved query, for example when it is run in Explore.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) useSavedQueryHandler(c *contextmodel.ReqContext) response.Response {
+	query, err := s.UseSavedQuery(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update saved query", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:route GET /saved-queries/{saved_query_uid}/versions saved_queries getSavedQueryVersions
+//
+// Get saved query versions.
+//
+// Returns the versions of the saved query, newest first.
+//
+// Responses:
+// 200: getSavedQueryVersionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getSavedQueryVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	versions, err := s.GetSavedQueryVersions(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get saved query versions", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryVersionsResponse{Result: versions})
+}
+
+// swagger:route POST /saved-queries/{saved_query_uid}/versions/{version}/restore saved_queries restoreSavedQueryVersion
+//
+// Restore saved query version.
+//
+// Saves the content of a previous version as a new version of the saved query.
+//
+// Responses:
+// 200: getSavedQueryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) restoreSavedQueryVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.Atoi(web.Params(c.Req)[":version"])
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	query, err := s.RestoreSavedQueryVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to restore saved query version", err)
+	}
+
+	return response.JSON(http.StatusOK, SavedQueryResponse{Result: query})
+}
+
+// swagger:parameters getSavedQuery updateSavedQuery deleteSavedQuery useSavedQuery getSavedQueryVersions restoreSavedQueryVersion
+type SavedQueryByUID struct {
+	// in:path
+	// required:true
+	UID string `json:"saved_query_uid"`
+}
+
+// swagger:parameters restoreSavedQueryVersion
+type SavedQueryVersionParam struct {
+	// in:path
+	// required:true
+	Version int `json:"version"`
+}
+
+// swagger:parameters searchSavedQueries
+type SearchSavedQueriesParams struct {
+	// List of data source UIDs to search for
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	DatasourceUid []string `json:"datasourceUid"`
+	// Data source type to search for
+	// in:query
+	// required: false
+	DatasourceType string `json:"datasourceType"`
+	// Text inside the title, description or queries that is searched for
+	// in:query
+	// required: false
+	SearchString string `json:"searchString"`
+	// Tags the saved queries must have
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Tag []string `json:"tag"`
+	// Visibility of the saved queries
+	// in:query
+	// required: false
+	// Enum: private,team,org
+	Visibility string `json:"visibility"`
+	// ID of the owner team of the saved queries
+	// in:query
+	// required: false
+	TeamID int64 `json:"teamId"`
+	// Sort method
+	// in:query
+	// required: false
+	// default: title-asc
+	// Enum: title-asc,title-desc,usage-desc,time-desc
+	Sort string `json:"sort"`
+	// Use this parameter to access hits beyond limit. Numbering starts at 1. limit param acts as page size.
+	// in:query
+	// required: false
+	Page int `json:"page"`
+	// Limit the number of returned results
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters createSavedQuery
+type CreateSavedQueryParams struct {
+	// in:body
+	// required:true
+	Body CreateSavedQueryCommand `json:"body"`
+}
+
+// swagger:parameters updateSavedQuery
+type UpdateSavedQueryParams struct {
+	// in:body
+	// required:true
+	Body UpdateSavedQueryCommand `json:"body"`
+}
+
+// swagger:response getSavedQuerySearchResponse
+type GetSavedQuerySearchResponse struct {
+	// in: body
+	Body SavedQuerySearchResponse `json:"body"`
+}
+
+// swagger:response getSavedQueryResponse
+type GetSavedQueryResponse struct {
+	// in: body
+	Body SavedQueryResponse `json:"body"`
+}
+
+// swagger:response getSavedQueryVersionsResponse
+type GetSavedQueryVersionsResponse struct {
+	// in: body
+	Body SavedQueryVersionsResponse `json:"body"`
+}
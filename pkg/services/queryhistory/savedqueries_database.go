@@ -0,0 +1,600 @@
+package queryhistory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	maxSavedQueryTitleLength = 190
+	maxSavedQueryTagLength   = 50
+	maxSavedQueriesLimit     = 1000
+)
+
+// savedQueryAccess is what the user can see and change in the saved query library. Users with the
+// savedqueries:admin action can see and change all of the saved queries of the organization. Searches are also
+// restricted to the saved queries that the user has the read permission for.
+type savedQueryAccess struct {
+	userID    int64
+	teamIDs   []int64
+	accessAll bool
+	filter    ac.SQLFilter
+}
+
+func (s QueryHistoryService) getSavedQueryAccess(ctx context.Context, user *user.SignedInUser) (savedQueryAccess, error) {
+	teamIDs, err := s.teamService.GetTeamIDsByUser(ctx, &team.GetTeamIDsByUserQuery{OrgID: user.OrgID, UserID: user.UserID})
+	if err != nil {
+		return savedQueryAccess{}, err
+	}
+	filter, err := ac.Filter(user, "saved_query.uid", ScopeSavedQueriesProvider.GetResourceScopeUID(""), ActionSavedQueriesRead)
+	if err != nil {
+		return savedQueryAccess{}, err
+	}
+	accessAll, err := s.accessControl.Evaluate(ctx, user, ac.EvalPermission(ActionSavedQueriesAdmin))
+	if err != nil {
+		return savedQueryAccess{}, err
+	}
+	return savedQueryAccess{
+		userID:    user.UserID,
+		teamIDs:   teamIDs,
+		accessAll: accessAll,
+		filter:    filter,
+	}, nil
+}
+
+func (a savedQueryAccess) canRead(q *SavedQuery) bool {
+	switch {
+	case a.accessAll, q.CreatedBy == a.userID, q.Visibility == SavedQueryVisibilityOrg:
+		return true
+	case q.Visibility == SavedQueryVisibilityTeam:
+		return slices.Contains(a.teamIDs, q.TeamID)
+	}
+	return false
+}
+
+// canWrite returns true if the user can see the saved query, and created it or is a member of its owner team.
+func (a savedQueryAccess) canWrite(q *SavedQuery) bool {
+	if !a.canRead(q) {
+		return false
+	}
+	return a.accessAll || q.CreatedBy == a.userID || (q.TeamID != 0 && slices.Contains(a.teamIDs, q.TeamID))
+}
+
+func (a savedQueryAccess) writeFilterSQL(builder *db.SQLBuilder) {
+	builder.Write(" AND"+a.filter.Where, a.filter.Args...)
+	if a.accessAll {
+		return
+	}
+	builder.Write(" AND (saved_query.created_by = ? OR saved_query.visibility = ?", a.userID, SavedQueryVisibilityOrg)
+	if len(a.teamIDs) > 0 {
+		params := []any{SavedQueryVisibilityTeam}
+		for _, id := range a.teamIDs {
+			params = append(params, id)
+		}
+		builder.Write(" OR (saved_query.visibility = ? AND saved_query.team_id IN (?"+strings.Repeat(",?", len(a.teamIDs)-1)+"))", params...)
+	}
+	builder.Write(")")
+}
+
+// validateSavedQuery validates the fields of a saved query and returns its normalized tags
+func validateSavedQuery(access savedQueryAccess, title string, queries *simplejson.Json, visibility SavedQueryVisibility, teamID int64, tags []string) ([]string, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, invalidSavedQuery("title is required")
+	}
+	if len(title) > maxSavedQueryTitleLength {
+		return nil, invalidSavedQuery("title is longer than %d characters", maxSavedQueryTitleLength)
+	}
+	if queries == nil || len(queries.MustArray()) == 0 {
+		return nil, invalidSavedQuery("queries are required")
+	}
+	if !visibility.IsValid() {
+		return nil, invalidSavedQuery("unknown visibility %q", visibility)
+	}
+	if visibility == SavedQueryVisibilityTeam && teamID == 0 {
+		return nil, invalidSavedQuery("team visibility requires an owner team")
+	}
+	if teamID != 0 && !access.accessAll && !slices.Contains(access.teamIDs, teamID) {
+		return nil, invalidSavedQuery("the user is not a member of the owner team")
+	}
+
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+		if len(tag) > maxSavedQueryTagLength {
+			return nil, invalidSavedQuery("tag %q is longer than %d characters", tag, maxSavedQueryTagLength)
+		}
+		result = append(result, tag)
+	}
+	slices.Sort(result)
+
+	return result, nil
+}
+
+// createSavedQuery adds a query to the saved query library, from the command or from the query history of the user
+func (s QueryHistoryService) createSavedQuery(ctx context.Context, user *user.SignedInUser, cmd CreateSavedQueryCommand) (SavedQueryDTO, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	var savedQuery SavedQuery
+	var tags []string
+	err = s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		if cmd.QueryHistoryUID != "" {
+			var queryHistory QueryHistory
+			exists, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, cmd.QueryHistoryUID).Get(&queryHistory)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return invalidSavedQuery("query %s not found in query history", cmd.QueryHistoryUID)
+			}
+			cmd.Queries = queryHistory.Queries
+			if cmd.DatasourceUID == "" {
+				cmd.DatasourceUID = queryHistory.DatasourceUID
+			}
+			if cmd.Description == "" {
+				cmd.Description = queryHistory.Comment
+			}
+		}
+		if cmd.Visibility == "" {
+			cmd.Visibility = SavedQueryVisibilityPrivate
+		}
+
+		var err error
+		tags, err = validateSavedQuery(access, cmd.Title, cmd.Queries, cmd.Visibility, cmd.TeamID, cmd.Tags)
+		if err != nil {
+			return err
+		}
+
+		now := s.now().Unix()
+		savedQuery = SavedQuery{
+			UID:            util.GenerateShortUID(),
+			OrgID:          user.OrgID,
+			Title:          cmd.Title,
+			Description:    cmd.Description,
+			DatasourceUID:  cmd.DatasourceUID,
+			DatasourceType: cmd.DatasourceType,
+			Queries:        cmd.Queries,
+			Visibility:     cmd.Visibility,
+			TeamID:         cmd.TeamID,
+			Version:        1,
+			CreatedBy:      user.UserID,
+			CreatedAt:      now,
+			UpdatedBy:      user.UserID,
+			UpdatedAt:      now,
+		}
+		if _, err := session.Insert(&savedQuery); err != nil {
+			return err
+		}
+
+		return insertSavedQueryVersion(session, &savedQuery, tags)
+	})
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	return savedQuery.toDTO(tags), nil
+}
+
+// updateSavedQuery replaces the content of the saved query and adds a new version
+func (s QueryHistoryService) updateSavedQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateSavedQueryCommand) (SavedQueryDTO, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	var savedQuery SavedQuery
+	var tags []string
+	err = s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		if err := getSavedQueryForWrite(session, access, user.OrgID, UID, &savedQuery); err != nil {
+			return err
+		}
+
+		if cmd.Visibility == "" {
+			cmd.Visibility = savedQuery.Visibility
+		}
+
+		var err error
+		tags, err = validateSavedQuery(access, cmd.Title, cmd.Queries, cmd.Visibility, cmd.TeamID, cmd.Tags)
+		if err != nil {
+			return err
+		}
+
+		savedQuery.Title = cmd.Title
+		savedQuery.Description = cmd.Description
+		savedQuery.DatasourceUID = cmd.DatasourceUID
+		savedQuery.DatasourceType = cmd.DatasourceType
+		savedQuery.Queries = cmd.Queries
+		savedQuery.Visibility = cmd.Visibility
+		savedQuery.TeamID = cmd.TeamID
+		savedQuery.Version++
+		savedQuery.UpdatedBy = user.UserID
+		savedQuery.UpdatedAt = s.now().Unix()
+
+		if _, err := session.ID(savedQuery.ID).AllCols().Update(&savedQuery); err != nil {
+			return err
+		}
+
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(&SavedQueryTag{}); err != nil {
+			return err
+		}
+
+		return insertSavedQueryVersion(session, &savedQuery, tags)
+	})
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	return savedQuery.toDTO(tags), nil
+}
+
+// insertSavedQueryVersion inserts the tags and the current version of the saved query
+func insertSavedQueryVersion(session *db.Session, q *SavedQuery, tags []string) error {
+	for _, tag := range tags {
+		if _, err := session.Insert(&SavedQueryTag{OrgID: q.OrgID, QueryUID: q.UID, Term: tag}); err != nil {
+			return err
+		}
+	}
+
+	tagsJSON := make([]any, 0, len(tags))
+	for _, tag := range tags {
+		tagsJSON = append(tagsJSON, tag)
+	}
+
+	_, err := session.Insert(&SavedQueryVersion{
+		OrgID:          q.OrgID,
+		QueryUID:       q.UID,
+		Version:        q.Version,
+		Title:          q.Title,
+		Description:    q.Description,
+		Tags:           simplejson.NewFromAny(tagsJSON),
+		DatasourceUID:  q.DatasourceUID,
+		DatasourceType: q.DatasourceType,
+		Queries:        q.Queries,
+		CreatedBy:      q.UpdatedBy,
+		CreatedAt:      q.UpdatedAt,
+	})
+	return err
+}
+
+func getSavedQuery(session *db.Session, access savedQueryAccess, orgID int64, UID string, q *SavedQuery) error {
+	exists, err := session.Where("org_id = ? AND uid = ?", orgID, UID).Get(q)
+	if err != nil {
+		return err
+	}
+	// saved queries the user can't see are reported as not found
+	if !exists || !access.canRead(q) {
+		return ErrSavedQueryNotFound.Errorf("saved query %s not found", UID)
+	}
+	return nil
+}
+
+func getSavedQueryForWrite(session *db.Session, access savedQueryAccess, orgID int64, UID string, q *SavedQuery) error {
+	if err := getSavedQuery(session, access, orgID, UID, q); err != nil {
+		return err
+	}
+	if !access.canWrite(q) {
+		return ErrSavedQueryNotOwner.Errorf("user %d can't change saved query %s", access.userID, UID)
+	}
+	return nil
+}
+
+func getSavedQueryTags(session *db.Session, orgID int64, UIDs ...string) (map[string][]string, error) {
+	result := make(map[string][]string, len(UIDs))
+	if len(UIDs) == 0 {
+		return result, nil
+	}
+
+	var tags []SavedQueryTag
+	if err := session.Where("org_id = ?", orgID).In("query_uid", UIDs).Asc("term").Find(&tags); err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		result[tag.QueryUID] = append(result[tag.QueryUID], tag.Term)
+	}
+	return result, nil
+}
+
+func (s QueryHistoryService) getSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	var savedQuery SavedQuery
+	var tags map[string][]string
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		if err := getSavedQuery(session, access, user.OrgID, UID, &savedQuery); err != nil {
+			return err
+		}
+		var err error
+		tags, err = getSavedQueryTags(session, user.OrgID, UID)
+		return err
+	})
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	return savedQuery.toDTO(tags[UID]), nil
+}
+
+// searchSavedQueries searches the saved queries the user can see based on provided parameters
+func (s QueryHistoryService) searchSavedQueries(ctx context.Context, user *user.SignedInUser, query SearchSavedQueriesQuery) (SavedQuerySearchResult, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return SavedQuerySearchResult{}, err
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	} else if query.Limit > maxSavedQueriesLimit {
+		query.Limit = maxSavedQueriesLimit
+	}
+
+	var savedQueries []SavedQuery
+	var totalCount int
+	var tags map[string][]string
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		builder := db.SQLBuilder{}
+		builder.Write("SELECT saved_query.* FROM saved_query")
+		s.writeSavedQueriesFilterSQL(query, user.OrgID, access, &builder)
+		writeSavedQueriesSortSQL(query, &builder)
+		builder.Write(" LIMIT ? OFFSET ?", query.Limit, query.Limit*(query.Page-1))
+		if err := session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&savedQueries); err != nil {
+			return err
+		}
+
+		countBuilder := db.SQLBuilder{}
+		countBuilder.Write("SELECT COUNT(*) FROM saved_query")
+		s.writeSavedQueriesFilterSQL(query, user.OrgID, access, &countBuilder)
+		if _, err := session.SQL(countBuilder.GetSQLString(), countBuilder.GetParams()...).Get(&totalCount); err != nil {
+			return err
+		}
+
+		uids := make([]string, 0, len(savedQueries))
+		for _, q := range savedQueries {
+			uids = append(uids, q.UID)
+		}
+		var err error
+		tags, err = getSavedQueryTags(session, user.OrgID, uids...)
+		return err
+	})
+	if err != nil {
+		return SavedQuerySearchResult{}, err
+	}
+
+	dtos := make([]SavedQueryDTO, 0, len(savedQueries))
+	for _, q := range savedQueries {
+		dtos = append(dtos, q.toDTO(tags[q.UID]))
+	}
+
+	return SavedQuerySearchResult{
+		SavedQueries: dtos,
+		TotalCount:   totalCount,
+		Page:         query.Page,
+		PerPage:      query.Limit,
+	}, nil
+}
+
+func (s QueryHistoryService) writeSavedQueriesFilterSQL(query SearchSavedQueriesQuery, orgID int64, access savedQueryAccess, builder *db.SQLBuilder) {
+	builder.Write(" WHERE saved_query.org_id = ?", orgID)
+	access.writeFilterSQL(builder)
+
+	if query.SearchString != "" {
+		dialect := s.store.GetDialect()
+		titleSQL, titleParam := dialect.LikeOperator("saved_query.title", true, query.SearchString, true)
+		descriptionSQL, descriptionParam := dialect.LikeOperator("saved_query.description", true, query.SearchString, true)
+		queriesSQL, queriesParam := dialect.LikeOperator("saved_query.queries", true, query.SearchString, true)
+		builder.Write(" AND ("+titleSQL+" OR "+descriptionSQL+" OR "+queriesSQL+")", titleParam, descriptionParam, queriesParam)
+	}
+
+	if len(query.DatasourceUIDs) > 0 {
+		params := make([]any, 0, len(query.DatasourceUIDs))
+		for _, uid := range query.DatasourceUIDs {
+			params = append(params, uid)
+		}
+		builder.Write(" AND saved_query.datasource_uid IN (?"+strings.Repeat(",?", len(query.DatasourceUIDs)-1)+")", params...)
+	}
+
+	if query.DatasourceType != "" {
+		builder.Write(" AND saved_query.datasource_type = ?", query.DatasourceType)
+	}
+
+	if query.Visibility != "" {
+		builder.Write(" AND saved_query.visibility = ?", query.Visibility)
+	}
+
+	if query.TeamID != 0 {
+		builder.Write(" AND saved_query.team_id = ?", query.TeamID)
+	}
+
+	// saved queries must have all of the tags
+	for _, tag := range query.Tags {
+		builder.Write(" AND saved_query.uid IN (SELECT query_uid FROM saved_query_tag WHERE org_id = ? AND term = ?)", orgID, tag)
+	}
+}
+
+func writeSavedQueriesSortSQL(query SearchSavedQueriesQuery, builder *db.SQLBuilder) {
+	switch query.Sort {
+	case "title-desc":
+		builder.Write(" ORDER BY saved_query.title DESC")
+	case "usage-desc":
+		builder.Write(" ORDER BY saved_query.usage_count DESC, saved_query.title ASC")
+	case "time-desc":
+		builder.Write(" ORDER BY saved_query.updated_at DESC")
+	default:
+		builder.Write(" ORDER BY saved_query.title ASC")
+	}
+}
+
+func (s QueryHistoryService) deleteSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) error {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		var savedQuery SavedQuery
+		if err := getSavedQueryForWrite(session, access, user.OrgID, UID, &savedQuery); err != nil {
+			return err
+		}
+
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(&SavedQueryTag{}); err != nil {
+			return err
+		}
+		if _, err := session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(&SavedQueryVersion{}); err != nil {
+			return err
+		}
+		_, err := session.ID(savedQuery.ID).Delete(&SavedQuery{})
+		return err
+	})
+}
+
+// useSavedQuery increments the usage count of the saved query
+func (s QueryHistoryService) useSavedQuery(ctx context.Context, user *user.SignedInUser, UID string) (SavedQueryDTO, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	var savedQuery SavedQuery
+	var tags map[string][]string
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		if err := getSavedQuery(session, access, user.OrgID, UID, &savedQuery); err != nil {
+			return err
+		}
+
+		now := s.now().Unix()
+		if _, err := session.Exec("UPDATE saved_query SET usage_count = usage_count + 1, last_used_at = ? WHERE id = ?", now, savedQuery.ID); err != nil {
+			return err
+		}
+		savedQuery.UsageCount++
+		savedQuery.LastUsedAt = now
+
+		var err error
+		tags, err = getSavedQueryTags(session, user.OrgID, UID)
+		return err
+	})
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	return savedQuery.toDTO(tags[UID]), nil
+}
+
+// getSavedQueryVersions returns the versions of the saved query, newest first
+func (s QueryHistoryService) getSavedQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]SavedQueryVersionDTO, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []SavedQueryVersion
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		var savedQuery SavedQuery
+		if err := getSavedQuery(session, access, user.OrgID, UID, &savedQuery); err != nil {
+			return err
+		}
+		return session.Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Desc("version").Find(&versions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SavedQueryVersionDTO, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, v.toDTO())
+	}
+	return result, nil
+}
+
+func (s QueryHistoryService) restoreSavedQueryVersion(ctx context.Context, user *user.SignedInUser, UID string, version int) (SavedQueryDTO, error) {
+	access, err := s.getSavedQueryAccess(ctx, user)
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	var savedQuery SavedQuery
+	var previous SavedQueryVersion
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		if err := getSavedQueryForWrite(session, access, user.OrgID, UID, &savedQuery); err != nil {
+			return err
+		}
+		exists, err := session.Where("org_id = ? AND query_uid = ? AND version = ?", user.OrgID, UID, version).Get(&previous)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrSavedQueryVersionNotFound.Errorf("version %d of saved query %s not found", version, UID)
+		}
+		return nil
+	})
+	if err != nil {
+		return SavedQueryDTO{}, err
+	}
+
+	return s.updateSavedQuery(ctx, user, UID, UpdateSavedQueryCommand{
+		Title:          previous.Title,
+		Description:    previous.Description,
+		Tags:           previous.Tags.MustStringArray(),
+		DatasourceUID:  previous.DatasourceUID,
+		DatasourceType: previous.DatasourceType,
+		Queries:        previous.Queries,
+		Visibility:     savedQuery.Visibility,
+		TeamID:         savedQuery.TeamID,
+	})
+}
+
+func (q SavedQuery) toDTO(tags []string) SavedQueryDTO {
+	if tags == nil {
+		tags = []string{}
+	}
+	return SavedQueryDTO{
+		UID:            q.UID,
+		Title:          q.Title,
+		Description:    q.Description,
+		Tags:           tags,
+		DatasourceUID:  q.DatasourceUID,
+		DatasourceType: q.DatasourceType,
+		Queries:        q.Queries,
+		Visibility:     q.Visibility,
+		TeamID:         q.TeamID,
+		Version:        q.Version,
+		UsageCount:     q.UsageCount,
+		LastUsedAt:     q.LastUsedAt,
+		CreatedBy:      q.CreatedBy,
+		CreatedAt:      q.CreatedAt,
+		UpdatedBy:      q.UpdatedBy,
+		UpdatedAt:      q.UpdatedAt,
+	}
+}
+
+func (v SavedQueryVersion) toDTO() SavedQueryVersionDTO {
+	return SavedQueryVersionDTO{
+		Version:        v.Version,
+		Title:          v.Title,
+		Description:    v.Description,
+		Tags:           v.Tags.MustStringArray(),
+		DatasourceUID:  v.DatasourceUID,
+		DatasourceType: v.DatasourceType,
+		Queries:        v.Queries,
+		CreatedBy:      v.CreatedBy,
+		CreatedAt:      v.CreatedAt,
+	}
+}
@@ -0,0 +1,287 @@
+package queryhistory
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var testTeamID = int64(10)
+
+func TestIntegrationCreateSavedQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenario(t, "When user saves a query, it should be private with a first version",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			result := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title:          "Error rate",
+				Tags:           []string{" errors", "http", "errors"},
+				DatasourceUID:  testDsUID1,
+				DatasourceType: "prometheus",
+				Queries:        testSavedQueries("rate(errors[5m])"),
+			})
+			require.Equal(t, SavedQueryVisibilityPrivate, result.Visibility)
+			require.Equal(t, 1, result.Version)
+			require.Equal(t, []string{"errors", "http"}, result.Tags)
+			require.Equal(t, testUserID, result.CreatedBy)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When user saves a query from the query history, it should copy the queries",
+		func(t *testing.T, sc scenarioContext) {
+			result := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title:           "From history",
+				QueryHistoryUID: sc.initialResult.Result.UID,
+			})
+			require.Equal(t, testDsUID1, result.DatasourceUID)
+			require.Equal(t, "test", result.Queries.GetIndex(0).Get("expr").MustString())
+		})
+
+	testScenario(t, "When user saves a query without title or queries, it should fail",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(CreateSavedQueryCommand{Queries: testSavedQueries("up")})
+			resp := sc.service.createSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+
+			sc.reqContext.Req.Body = mockRequestBody(CreateSavedQueryCommand{Title: "No queries"})
+			resp = sc.service.createSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	testScenario(t, "When user shares a query with a team they are not a member of, it should fail",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(CreateSavedQueryCommand{
+				Title:      "Team query",
+				Queries:    testSavedQueries("up"),
+				Visibility: SavedQueryVisibilityTeam,
+				TeamID:     testTeamID,
+			})
+			resp := sc.service.createSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
+
+func TestIntegrationSavedQueryVisibility(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenario(t, "When other users get saved queries, they should only see the ones shared with them",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			sc.teamService.ExpectedTeamsByUser = []*team.TeamDTO{{ID: testTeamID}}
+			private := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Private", Queries: testSavedQueries("a")})
+			teamQuery := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "Team", Queries: testSavedQueries("b"), Visibility: SavedQueryVisibilityTeam, TeamID: testTeamID,
+			})
+			orgQuery := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "Org", Queries: testSavedQueries("c"), Visibility: SavedQueryVisibilityOrg,
+			})
+
+			// a member of another team
+			sc.teamService.ExpectedTeamsByUser = []*team.TeamDTO{{ID: testTeamID + 1}}
+			sc.reqContext.SignedInUser = otherSignedInUser(sc, org.RoleEditor)
+			require.Equal(t, []string{"Org"}, searchSavedQueryTitles(t, sc, url.Values{}))
+			require.Equal(t, 404, getSavedQueryStatus(sc, private.UID))
+			require.Equal(t, 404, getSavedQueryStatus(sc, teamQuery.UID))
+			require.Equal(t, 200, getSavedQueryStatus(sc, orgQuery.UID))
+
+			// a member of the owner team
+			sc.teamService.ExpectedTeamsByUser = []*team.TeamDTO{{ID: testTeamID}}
+			require.Equal(t, []string{"Org", "Team"}, searchSavedQueryTitles(t, sc, url.Values{}))
+
+			// an org admin without the savedqueries:admin action
+			sc.teamService.ExpectedTeamsByUser = nil
+			sc.reqContext.SignedInUser = otherSignedInUser(sc, org.RoleAdmin)
+			require.Equal(t, []string{"Org"}, searchSavedQueryTitles(t, sc, url.Values{}))
+
+			// a user with the savedqueries:admin action
+			sc.reqContext.SignedInUser = otherSignedInUser(sc, org.RoleEditor)
+			sc.reqContext.SignedInUser.Permissions = map[int64]map[string][]string{testOrgID: maps.Clone(sc.reqContext.SignedInUser.Permissions[testOrgID])}
+			sc.reqContext.SignedInUser.Permissions[testOrgID][ActionSavedQueriesAdmin] = []string{}
+			require.Equal(t, []string{"Org", "Private", "Team"}, searchSavedQueryTitles(t, sc, url.Values{}))
+			require.Equal(t, 200, getSavedQueryStatus(sc, private.UID))
+		})
+
+	testScenario(t, "When other users update saved queries, only the owner team members should succeed",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			sc.teamService.ExpectedTeamsByUser = []*team.TeamDTO{{ID: testTeamID}}
+			teamQuery := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "Team", Queries: testSavedQueries("b"), Visibility: SavedQueryVisibilityTeam, TeamID: testTeamID,
+			})
+			orgQuery := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "Org", Queries: testSavedQueries("c"), Visibility: SavedQueryVisibilityOrg,
+			})
+
+			sc.reqContext.SignedInUser = otherSignedInUser(sc, org.RoleEditor)
+			cmd := UpdateSavedQueryCommand{Title: "Changed", Queries: testSavedQueries("d"), Visibility: SavedQueryVisibilityTeam, TeamID: testTeamID}
+			require.Equal(t, 200, updateSavedQueryStatus(sc, teamQuery.UID, cmd))
+
+			sc.teamService.ExpectedTeamsByUser = nil
+			require.Equal(t, 403, updateSavedQueryStatus(sc, orgQuery.UID, cmd))
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": orgQuery.UID})
+			resp := sc.service.deleteSavedQueryHandler(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+		})
+}
+
+func TestIntegrationSavedQueryVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenario(t, "When user updates and restores a saved query, it should keep every version",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			created := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "Latency", Tags: []string{"v1"}, Queries: testSavedQueries("latency_v1"),
+			})
+
+			cmd := UpdateSavedQueryCommand{Title: "Latency p99", Tags: []string{"v2"}, Queries: testSavedQueries("latency_v2")}
+			require.Equal(t, 200, updateSavedQueryStatus(sc, created.UID, cmd))
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID})
+			resp := sc.service.getSavedQueryVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var versions SavedQueryVersionsResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &versions))
+			require.Len(t, versions.Result, 2)
+			require.Equal(t, 2, versions.Result[0].Version)
+			require.Equal(t, "Latency", versions.Result[1].Title)
+			require.Equal(t, []string{"v1"}, versions.Result[1].Tags)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID, ":version": "1"})
+			resp = sc.service.restoreSavedQueryVersionHandler(sc.reqContext)
+			restored := validateAndUnMarshalSavedQueryResponse(t, resp)
+			require.Equal(t, 3, restored.Version)
+			require.Equal(t, "Latency", restored.Title)
+			require.Equal(t, []string{"v1"}, restored.Tags)
+			require.Equal(t, "latency_v1", restored.Queries.GetIndex(0).Get("expr").MustString())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID, ":version": "5"})
+			resp = sc.service.restoreSavedQueryVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+}
+
+func TestIntegrationSearchSavedQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenario(t, "When user searches saved queries, it should filter and sort them",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "CPU usage", Tags: []string{"infra"}, DatasourceUID: testDsUID1, DatasourceType: "prometheus", Queries: testSavedQueries("cpu"),
+			})
+			memory := createSavedQuery(t, sc, CreateSavedQueryCommand{
+				Title: "Memory usage", Tags: []string{"infra", "memory"}, DatasourceUID: testDsUID2, DatasourceType: "loki", Queries: testSavedQueries("memory"),
+			})
+
+			require.Equal(t, []string{"Memory usage"}, searchSavedQueryTitles(t, sc, url.Values{"datasourceUid": {testDsUID2}}))
+			require.Equal(t, []string{"CPU usage"}, searchSavedQueryTitles(t, sc, url.Values{"datasourceType": {"prometheus"}}))
+			require.Equal(t, []string{"Memory usage"}, searchSavedQueryTitles(t, sc, url.Values{"tag": {"infra", "memory"}}))
+			require.Equal(t, []string{"CPU usage"}, searchSavedQueryTitles(t, sc, url.Values{"searchString": {"cpu"}}))
+			require.Equal(t, []string{"Memory usage", "CPU usage"}, searchSavedQueryTitles(t, sc, url.Values{"sort": {"title-desc"}}))
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": memory.UID})
+			resp := sc.service.useSavedQueryHandler(sc.reqContext)
+			used := validateAndUnMarshalSavedQueryResponse(t, resp)
+			require.Equal(t, int64(1), used.UsageCount)
+			require.NotZero(t, used.LastUsedAt)
+			require.Equal(t, []string{"Memory usage", "CPU usage"}, searchSavedQueryTitles(t, sc, url.Values{"sort": {"usage-desc"}}))
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When user searches query history with saved queries, it should return both",
+		func(t *testing.T, sc scenarioContext) {
+			createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Saved", DatasourceUID: testDsUID1, Queries: testSavedQueries("test")})
+			createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Other data source", DatasourceUID: testDsUID2, Queries: testSavedQueries("test")})
+
+			sc.reqContext.Req.Form = url.Values{"datasourceUid": {testDsUID1}, "includeSaved": {"true"}}
+			resp := sc.service.searchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &response))
+			require.Len(t, response.Result.QueryHistory, 1)
+			require.Len(t, response.Result.SavedQueries, 1)
+			require.Equal(t, "Saved", response.Result.SavedQueries[0].Title)
+		})
+
+	testScenario(t, "When user can only read some saved queries, it should only return them",
+		false, false, func(t *testing.T, sc scenarioContext) {
+			cpu := createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "CPU usage", DatasourceUID: testDsUID1, Queries: testSavedQueries("cpu")})
+			createSavedQuery(t, sc, CreateSavedQueryCommand{Title: "Memory usage", DatasourceUID: testDsUID1, Queries: testSavedQueries("memory")})
+
+			sc.reqContext.SignedInUser.Permissions[testOrgID][ActionSavedQueriesRead] = []string{ScopeSavedQueriesProvider.GetResourceScopeUID(cpu.UID)}
+			require.Equal(t, []string{"CPU usage"}, searchSavedQueryTitles(t, sc, url.Values{}))
+
+			sc.reqContext.SignedInUser.Permissions[testOrgID][ActionSavedQueriesRead] = []string{}
+			require.Empty(t, searchSavedQueryTitles(t, sc, url.Values{}))
+		})
+}
+
+func testSavedQueries(expr string) *simplejson.Json {
+	return simplejson.NewFromAny([]any{map[string]any{"expr": expr}})
+}
+
+func otherSignedInUser(sc scenarioContext, role org.RoleType) *user.SignedInUser {
+	return &user.SignedInUser{
+		UserID:      testUserID + 1,
+		OrgID:       testOrgID,
+		OrgRole:     role,
+		Permissions: sc.reqContext.SignedInUser.Permissions,
+	}
+}
+
+func createSavedQuery(t *testing.T, sc scenarioContext, cmd CreateSavedQueryCommand) SavedQueryDTO {
+	t.Helper()
+
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	resp := sc.service.createSavedQueryHandler(sc.reqContext)
+	return validateAndUnMarshalSavedQueryResponse(t, resp)
+}
+
+func updateSavedQueryStatus(sc scenarioContext, uid string, cmd UpdateSavedQueryCommand) int {
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": uid})
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	return sc.service.updateSavedQueryHandler(sc.reqContext).Status()
+}
+
+func getSavedQueryStatus(sc scenarioContext, uid string) int {
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": uid})
+	return sc.service.getSavedQueryHandler(sc.reqContext).Status()
+}
+
+func searchSavedQueryTitles(t *testing.T, sc scenarioContext, params url.Values) []string {
+	t.Helper()
+
+	sc.reqContext.Req.Form = params
+	resp := sc.service.searchSavedQueriesHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+
+	var result SavedQuerySearchResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	require.Equal(t, len(result.Result.SavedQueries), result.Result.TotalCount)
+
+	titles := make([]string, 0, len(result.Result.SavedQueries))
+	for _, q := range result.Result.SavedQueries {
+		titles = append(titles, q.Title)
+	}
+	return titles
+}
+
+func validateAndUnMarshalSavedQueryResponse(t *testing.T, resp response.Response) SavedQueryDTO {
+	t.Helper()
+
+	require.Equal(t, 200, resp.Status())
+
+	var result SavedQueryResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+
+	return result.Result
+}
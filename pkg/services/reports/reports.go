@@ -0,0 +1,153 @@
+package reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrReportNotFound      = errutil.NotFound("reports.not-found", errutil.WithPublicMessage("Report not found"))
+	ErrReportUIDExists     = errutil.Conflict("reports.uid-exists", errutil.WithPublicMessage("A report with the same uid already exists"))
+	ErrDashboardNotFound   = errutil.BadRequest("reports.dashboard-not-found", errutil.WithPublicMessage("Dashboard of the report not found"))
+	ErrRendererUnsupported = errutil.Internal("reports.renderer-unsupported", errutil.WithPublicMessage("Reports require an image renderer that supports full height images"))
+	ErrInvalidReport       = errutil.BadRequest("reports.invalid").MustTemplate("invalid report: {{ .Public.Reason }}", errutil.WithPublic("Invalid report: {{ .Public.Reason }}"))
+)
+
+const (
+	ActionRead   = "reports:read"
+	ActionCreate = "reports:create"
+	ActionWrite  = "reports:write"
+	ActionDelete = "reports:delete"
+	// ActionSend allows sending a report outside of its schedule.
+	ActionSend = "reports:send"
+
+	ScopeAll = "reports:*"
+)
+
+type Orientation string
+
+const (
+	OrientationPortrait  Orientation = "portrait"
+	OrientationLandscape Orientation = "landscape"
+)
+
+type RunStatus string
+
+const (
+	RunStatusSuccess RunStatus = "success"
+	RunStatusFailed  RunStatus = "failed"
+)
+
+type RunTrigger string
+
+const (
+	// RunTriggerSchedule is a run started by the scheduler.
+	RunTriggerSchedule RunTrigger = "schedule"
+	// RunTriggerManual is a run started through the send API.
+	RunTriggerManual RunTrigger = "manual"
+)
+
+// Service manages scheduled dashboard reports. A report renders a dashboard to a
+// PDF, optionally together with CSV exports of some of its panels, and emails it to
+// its recipients on a cron schedule.
+type Service interface {
+	CreateReport(ctx context.Context, cmd *CreateReportCommand) (*Report, error)
+	UpdateReport(ctx context.Context, cmd *UpdateReportCommand) (*Report, error)
+	GetReport(ctx context.Context, orgID int64, uid string) (*Report, error)
+	GetReports(ctx context.Context, orgID int64) ([]*Report, error)
+	DeleteReport(ctx context.Context, orgID int64, uid string) error
+	// SendReport renders and sends the report right away, independent of its schedule.
+	SendReport(ctx context.Context, orgID int64, uid string) (*Run, error)
+	// GetRuns returns the most recent runs of the report, newest first.
+	GetRuns(ctx context.Context, orgID int64, uid string, limit int) ([]*Run, error)
+}
+
+type Report struct {
+	UID          string              `json:"uid"`
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo,omitempty"`
+	Message      string              `json:"message,omitempty"`
+	Schedule     Schedule            `json:"schedule"`
+	TimeRange    TimeRange           `json:"timeRange"`
+	Variables    map[string][]string `json:"variables,omitempty"`
+	Options      Options             `json:"options"`
+	Enabled      bool                `json:"enabled"`
+	// NextRun is the next time the report is sent, it is unset when the report is disabled.
+	NextRun   *time.Time `json:"nextRun,omitempty"`
+	CreatedBy int64      `json:"createdBy"`
+	UpdatedBy int64      `json:"updatedBy"`
+	Created   time.Time  `json:"created"`
+	Updated   time.Time  `json:"updated"`
+
+	ID    int64 `json:"-"`
+	OrgID int64 `json:"-"`
+}
+
+type Schedule struct {
+	// Cron is a standard five field cron expression, or one of the @hourly, @daily,
+	// @weekly and @monthly descriptors.
+	Cron string `json:"cron"`
+	// Timezone is the IANA time zone the cron expression is evaluated in. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Options struct {
+	Orientation Orientation `json:"orientation"`
+	// Theme is the theme the dashboard is rendered with, light or dark.
+	Theme string `json:"theme"`
+	// CSVPanelIDs are the panels whose data is attached as CSV files.
+	CSVPanelIDs []int64 `json:"csvPanelIds,omitempty"`
+}
+
+type CreateReportCommand struct {
+	UID          string              `json:"uid"`
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo"`
+	Message      string              `json:"message"`
+	Schedule     Schedule            `json:"schedule"`
+	TimeRange    TimeRange           `json:"timeRange"`
+	Variables    map[string][]string `json:"variables"`
+	Options      Options             `json:"options"`
+	Enabled      bool                `json:"enabled"`
+
+	OrgID     int64 `json:"-"`
+	CreatedBy int64 `json:"-"`
+}
+
+type UpdateReportCommand struct {
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo"`
+	Message      string              `json:"message"`
+	Schedule     Schedule            `json:"schedule"`
+	TimeRange    TimeRange           `json:"timeRange"`
+	Variables    map[string][]string `json:"variables"`
+	Options      Options             `json:"options"`
+	Enabled      bool                `json:"enabled"`
+
+	UID       string `json:"-"`
+	OrgID     int64  `json:"-"`
+	UpdatedBy int64  `json:"-"`
+}
+
+// Run is a single delivery attempt of a report.
+type Run struct {
+	ID       int64      `json:"id"`
+	Trigger  RunTrigger `json:"trigger"`
+	Status   RunStatus  `json:"status"`
+	Error    string     `json:"error,omitempty"`
+	Started  time.Time  `json:"started"`
+	Finished time.Time  `json:"finished"`
+}
@@ -0,0 +1,121 @@
+package reportsimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultRunsLimit = 20
+	maxRunsLimit     = 100
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/reports", func(entities routing.RouteRegister) {
+		entities.Get("/", authorize(ac.EvalPermission(reports.ActionRead, reports.ScopeAll)), routing.Wrap(s.getReportsHandler))
+		entities.Post("/", authorize(ac.EvalPermission(reports.ActionCreate)), routing.Wrap(s.createReportHandler))
+		entities.Get("/:uid", authorize(ac.EvalPermission(reports.ActionRead, reports.ScopeAll)), routing.Wrap(s.getReportHandler))
+		entities.Put("/:uid", authorize(ac.EvalPermission(reports.ActionWrite, reports.ScopeAll)), routing.Wrap(s.updateReportHandler))
+		entities.Delete("/:uid", authorize(ac.EvalPermission(reports.ActionDelete, reports.ScopeAll)), routing.Wrap(s.deleteReportHandler))
+		entities.Post("/:uid/send", authorize(ac.EvalPermission(reports.ActionSend, reports.ScopeAll)), routing.Wrap(s.sendReportHandler))
+		entities.Get("/:uid/runs", authorize(ac.EvalPermission(reports.ActionRead, reports.ScopeAll)), routing.Wrap(s.getRunsHandler))
+	})
+}
+
+func (s *Service) getReportsHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.GetReports(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) getReportHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.GetReport(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) createReportHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.CreateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	userID, err := c.SignedInUser.GetInternalID()
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Reports can only be created by users and service accounts", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.CreatedBy = userID
+
+	result, err := s.CreateReport(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) updateReportHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	userID, err := c.SignedInUser.GetInternalID()
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Reports can only be updated by users and service accounts", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UID = web.Params(c.Req)[":uid"]
+	cmd.UpdatedBy = userID
+
+	result, err := s.UpdateReport(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) deleteReportHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteReport(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+	return response.Success("Report deleted")
+}
+
+func (s *Service) sendReportHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.SendReport(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) getRunsHandler(c *contextmodel.ReqContext) response.Response {
+	limit := defaultRunsLimit
+	if l := c.Query("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+			return response.Error(http.StatusBadRequest, "limit must be a positive number", err)
+		}
+		limit = min(limit, maxRunsLimit)
+	}
+
+	result, err := s.GetRuns(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"], limit)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report runs", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
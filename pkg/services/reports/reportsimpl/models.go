@@ -0,0 +1,157 @@
+package reportsimpl
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type report struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"org_id"`
+	UID          string `xorm:"uid"`
+	Name         string `xorm:"name"`
+	DashboardUID string `xorm:"dashboard_uid"`
+	// Recipients is a comma separated list of email addresses.
+	Recipients       string `xorm:"recipients"`
+	ReplyTo          string `xorm:"reply_to"`
+	Message          string `xorm:"message"`
+	ScheduleCron     string `xorm:"schedule_cron"`
+	ScheduleTimezone string `xorm:"schedule_timezone"`
+	TimeFrom         string `xorm:"time_from"`
+	TimeTo           string `xorm:"time_to"`
+	// Variables and Options are JSON encoded.
+	Variables string `xorm:"variables"`
+	Options   string `xorm:"options"`
+	Enabled   bool   `xorm:"enabled"`
+	// NextRun is the unix timestamp of the next scheduled run, 0 if the report is disabled.
+	NextRun   int64     `xorm:"next_run"`
+	CreatedBy int64     `xorm:"created_by"`
+	UpdatedBy int64     `xorm:"updated_by"`
+	Created   time.Time `xorm:"'created'"`
+	Updated   time.Time `xorm:"'updated'"`
+}
+
+func (r *report) TableName() string {
+	return "report"
+}
+
+func (r *report) toDTO() (*reports.Report, error) {
+	dto := &reports.Report{
+		ID:           r.ID,
+		OrgID:        r.OrgID,
+		UID:          r.UID,
+		Name:         r.Name,
+		DashboardUID: r.DashboardUID,
+		Recipients:   splitRecipients(r.Recipients),
+		ReplyTo:      r.ReplyTo,
+		Message:      r.Message,
+		Schedule: reports.Schedule{
+			Cron:     r.ScheduleCron,
+			Timezone: r.ScheduleTimezone,
+		},
+		TimeRange: reports.TimeRange{
+			From: r.TimeFrom,
+			To:   r.TimeTo,
+		},
+		Enabled:   r.Enabled,
+		CreatedBy: r.CreatedBy,
+		UpdatedBy: r.UpdatedBy,
+		Created:   r.Created,
+		Updated:   r.Updated,
+	}
+
+	if r.NextRun > 0 {
+		next := time.Unix(r.NextRun, 0).UTC()
+		dto.NextRun = &next
+	}
+	if r.Variables != "" {
+		if err := json.Unmarshal([]byte(r.Variables), &dto.Variables); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal([]byte(r.Options), &dto.Options); err != nil {
+		return nil, err
+	}
+
+	return dto, nil
+}
+
+func splitRecipients(recipients string) []string {
+	result := make([]string, 0)
+	for _, r := range strings.Split(recipients, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+type run struct {
+	ID          int64              `xorm:"pk autoincr 'id'"`
+	OrgID       int64              `xorm:"org_id"`
+	ReportID    int64              `xorm:"report_id"`
+	TriggeredBy reports.RunTrigger `xorm:"triggered_by"`
+	Status      reports.RunStatus  `xorm:"status"`
+	Error       string             `xorm:"error"`
+	Started     time.Time          `xorm:"started"`
+	Finished    time.Time          `xorm:"finished"`
+}
+
+func (r *run) TableName() string {
+	return "report_run"
+}
+
+func (r *run) toDTO() *reports.Run {
+	return &reports.Run{
+		ID:       r.ID,
+		Trigger:  r.TriggeredBy,
+		Status:   r.Status,
+		Error:    r.Error,
+		Started:  r.Started,
+		Finished: r.Finished,
+	}
+}
+
+var (
+	reportsReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:reader",
+		DisplayName: "Reader",
+		Description: "Read all reports and their delivery history.",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: reports.ActionRead, Scope: reports.ScopeAll},
+		},
+	}
+
+	reportsWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:writer",
+		DisplayName: "Writer",
+		Description: "Create, read, update, delete and send all reports.",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: reports.ActionCreate},
+			{Action: reports.ActionRead, Scope: reports.ScopeAll},
+			{Action: reports.ActionWrite, Scope: reports.ScopeAll},
+			{Action: reports.ActionDelete, Scope: reports.ScopeAll},
+			{Action: reports.ActionSend, Scope: reports.ScopeAll},
+		},
+	}
+)
+
+func declareFixedRoles(ac accesscontrol.Service) error {
+	reader := accesscontrol.RoleRegistration{
+		Role:   reportsReaderRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+	writer := accesscontrol.RoleRegistration{
+		Role:   reportsWriterRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	return ac.DeclareFixedRoles(reader, writer)
+}
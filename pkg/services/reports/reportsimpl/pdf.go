@@ -0,0 +1,135 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/grafana/gofpdf"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+const (
+	pdfMargin       = 10.0 // mm
+	pdfHeaderHeight = 14.0 // mm
+	pdfFooterHeight = 8.0  // mm
+
+	// The page break is moved up to the first row of uniform color in the last part
+	// of a page, so that panels are not cut in half when there is a gap between them.
+	pageBreakSearchRatio = 0.25
+	pageBreakSampleStep  = 4
+)
+
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+type pdfDocument struct {
+	Title       string
+	Subtitle    string
+	Orientation reports.Orientation
+	Created     time.Time
+}
+
+// buildPDF lays out a full height dashboard screenshot on as many A4 pages as needed.
+func buildPDF(doc pdfDocument, screenshot io.Reader, w io.Writer) error {
+	img, err := png.Decode(screenshot)
+	if err != nil {
+		return fmt.Errorf("failed to decode dashboard image: %w", err)
+	}
+	sub, ok := img.(subImager)
+	if !ok {
+		return fmt.Errorf("unsupported dashboard image type %T", img)
+	}
+
+	orientation := "P"
+	if doc.Orientation == reports.OrientationLandscape {
+		orientation = "L"
+	}
+
+	pdf := gofpdf.New(orientation, "mm", "A4", "")
+	pdf.SetCreator("Grafana", true)
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetCreationDate(doc.Created)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.AliasNbPages("")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin - pdfFooterHeight/2)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, pdfFooterHeight/2, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*pdfMargin
+	contentHeight := pageHeight - 2*pdfMargin - pdfHeaderHeight - pdfFooterHeight
+
+	bounds := img.Bounds()
+	pxPerMM := float64(bounds.Dx()) / contentWidth
+	pageRows := int(contentHeight * pxPerMM)
+	if pageRows <= 0 {
+		return fmt.Errorf("dashboard image is too narrow")
+	}
+
+	for top, page := bounds.Min.Y, 0; top < bounds.Max.Y; page++ {
+		bottom := pageBreak(img, top, pageRows)
+
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 7, tr(doc.Title), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(0, 5, tr(doc.Subtitle), "", 1, "L", false, 0, "")
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, sub.SubImage(image.Rect(bounds.Min.X, top, bounds.Max.X, bottom))); err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("page-%d", page)
+		pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "PNG"}, &buf)
+		height := float64(bottom-top) / pxPerMM
+		pdf.ImageOptions(name, pdfMargin, pdfMargin+pdfHeaderHeight, contentWidth, height, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+		top = bottom
+	}
+
+	return pdf.Output(w)
+}
+
+// pageBreak returns the row where the page starting at top ends.
+func pageBreak(img image.Image, top, pageRows int) int {
+	bounds := img.Bounds()
+	bottom := top + pageRows
+	if bottom >= bounds.Max.Y {
+		return bounds.Max.Y
+	}
+
+	limit := bottom - int(float64(pageRows)*pageBreakSearchRatio)
+	for y := bottom; y > limit; y-- {
+		if uniformRow(img, y) {
+			return y
+		}
+	}
+	return bottom
+}
+
+func uniformRow(img image.Image, y int) bool {
+	bounds := img.Bounds()
+	r0, g0, b0, a0 := img.At(bounds.Min.X, y).RGBA()
+	for x := bounds.Min.X; x < bounds.Max.X; x += pageBreakSampleStep {
+		r, g, b, a := img.At(x, y).RGBA()
+		if r != r0 || g != g0 || b != b0 || a != a0 {
+			return false
+		}
+	}
+	return true
+}
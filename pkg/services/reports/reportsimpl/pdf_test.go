@@ -0,0 +1,78 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+func TestBuildPDF(t *testing.T) {
+	// A dashboard of ten 200px high panels separated by 20px gaps.
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 2200))
+	for y := 0; y < 2200; y++ {
+		for x := 0; x < 1000; x++ {
+			c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			if y%220 < 200 && x%8 < 4 {
+				c = color.RGBA{R: 50, G: 100, B: 200, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var screenshot bytes.Buffer
+	require.NoError(t, png.Encode(&screenshot, img))
+
+	for _, tc := range []struct {
+		orientation reports.Orientation
+		pages       int
+	}{
+		{reports.OrientationPortrait, 2},
+		{reports.OrientationLandscape, 4},
+	} {
+		t.Run(string(tc.orientation), func(t *testing.T) {
+			var out bytes.Buffer
+			err := buildPDF(pdfDocument{
+				Title:       "SLO",
+				Subtitle:    "last week",
+				Orientation: tc.orientation,
+				Created:     time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			}, bytes.NewReader(screenshot.Bytes()), &out)
+			require.NoError(t, err)
+			require.True(t, bytes.HasPrefix(out.Bytes(), []byte("%PDF-")))
+			require.Equal(t, tc.pages, bytes.Count(out.Bytes(), []byte("/Type /Page\n")))
+		})
+	}
+
+	t.Run("rejects invalid images", func(t *testing.T) {
+		err := buildPDF(pdfDocument{}, bytes.NewReader([]byte("not a png")), &bytes.Buffer{})
+		require.Error(t, err)
+	})
+}
+
+func TestPageBreak(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 1000))
+	for y := 0; y < 1000; y++ {
+		for x := 0; x < 100; x++ {
+			c := color.RGBA{A: 255}
+			// Every row is uneven except the rows 700 and 900.
+			if y != 700 && y != 900 && x%8 < 4 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	// The page ends at the last uniform row in the last quarter of the page.
+	require.Equal(t, 900, pageBreak(img, 0, 950))
+	require.Equal(t, 700, pageBreak(img, 0, 800))
+	// Without a uniform row the page is cut at its full height.
+	require.Equal(t, 600, pageBreak(img, 0, 600))
+	// The last page ends at the bottom of the image.
+	require.Equal(t, 1000, pageBreak(img, 500, 600))
+}
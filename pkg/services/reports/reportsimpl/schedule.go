@@ -0,0 +1,60 @@
+package reportsimpl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// parseSchedule parses the cron expression of the schedule. The time zone is part of
+// the schedule and can't be set in the expression with a TZ= or CRON_TZ= prefix.
+func parseSchedule(s reports.Schedule) (cron.Schedule, error) {
+	expr := strings.TrimSpace(s.Cron)
+	if expr == "" {
+		return nil, fmt.Errorf("cron expression is required")
+	}
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, fmt.Errorf("set the time zone of the schedule with the timezone field")
+	}
+	if strings.HasPrefix(expr, "@every") {
+		return nil, fmt.Errorf("@every is not supported, use a cron expression")
+	}
+
+	loc := time.UTC
+	if s.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", s.Timezone)
+		}
+	}
+
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = loc
+	}
+	return schedule, nil
+}
+
+// nextRun returns the first time after the given time the schedule fires.
+func nextRun(s reports.Schedule, after time.Time) (time.Time, error) {
+	schedule, err := parseSchedule(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %q never fires", s.Cron)
+	}
+	return next, nil
+}
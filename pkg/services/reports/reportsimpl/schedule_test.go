@@ -0,0 +1,46 @@
+package reportsimpl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+func TestNextRun(t *testing.T) {
+	after := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC) // Friday
+
+	t.Run("uses UTC by default", func(t *testing.T) {
+		next, err := nextRun(reports.Schedule{Cron: "0 9 * * 1"}, after)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("uses the timezone of the schedule", func(t *testing.T) {
+		next, err := nextRun(reports.Schedule{Cron: "0 9 * * 1", Timezone: "Europe/Berlin"}, after)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("supports descriptors", func(t *testing.T) {
+		next, err := nextRun(reports.Schedule{Cron: "@daily"}, after)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("rejects invalid schedules", func(t *testing.T) {
+		for _, s := range []reports.Schedule{
+			{},
+			{Cron: "0 9 * *"},
+			{Cron: "CRON_TZ=Europe/Berlin 0 9 * * 1"},
+			{Cron: "@every 1m"},
+			{Cron: "0 9 * * 1", Timezone: "Mars/Olympus"},
+			{Cron: "0 0 30 2 *"},
+		} {
+			_, err := nextRun(s, after)
+			require.Error(t, err, s.Cron)
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const emailTemplate = "report"
+
+// send renders the report and emails it to its recipients, and records the outcome
+// in the run history of the report.
+func (s *Service) send(ctx context.Context, r *reports.Report, trigger reports.RunTrigger) *reports.Run {
+	ctx, span := tracer.Start(ctx, "reports.send")
+	defer span.End()
+
+	row := &run{
+		OrgID:       r.OrgID,
+		ReportID:    r.ID,
+		TriggeredBy: trigger,
+		Status:      reports.RunStatusSuccess,
+		Started:     s.now(),
+	}
+
+	logger := s.log.FromContext(ctx).New("orgID", r.OrgID, "uid", r.UID, "trigger", trigger)
+	if err := s.deliver(ctx, r); err != nil {
+		logger.Error("Failed to send report", "error", err)
+		span.RecordError(err)
+		row.Status = reports.RunStatusFailed
+		row.Error = err.Error()
+	} else {
+		logger.Info("Sent report", "recipients", len(r.Recipients))
+	}
+	row.Finished = s.now()
+
+	if err := s.store.InsertRun(ctx, row, s.historyLimit); err != nil {
+		logger.Error("Failed to record report run", "error", err)
+	}
+
+	return row.toDTO()
+}
+
+func (s *Service) deliver(ctx context.Context, r *reports.Report) error {
+	// The dashboard is rendered as the user who last saved the report, so that the
+	// report doesn't contain anything that user isn't allowed to see. Reports saved
+	// before the updater was recorded are rendered as their creator.
+	ownerID := r.UpdatedBy
+	if ownerID == 0 {
+		ownerID = r.CreatedBy
+	}
+	owner, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: ownerID, OrgID: r.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to get the owner of the report: %w", err)
+	}
+	ctx = identity.WithRequester(ctx, owner)
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: r.OrgID, UID: r.DashboardUID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return reports.ErrDashboardNotFound.Errorf("dashboard %s not found", r.DashboardUID)
+		}
+		return err
+	}
+
+	loc := time.UTC
+	if r.Schedule.Timezone != "" {
+		if loc, err = time.LoadLocation(r.Schedule.Timezone); err != nil {
+			return err
+		}
+	}
+	from, to, err := s.timeRange(r, dash, loc)
+	if err != nil {
+		return err
+	}
+
+	if err := s.renderService.IsCapabilitySupported(ctx, rendering.FullHeightImages); err != nil {
+		return reports.ErrRendererUnsupported.Errorf("%w", err)
+	}
+
+	authOpts := rendering.AuthOpts{OrgID: r.OrgID, UserID: owner.UserID, OrgRole: owner.OrgRole}
+	session, err := s.renderService.CreateRenderingSession(ctx, authOpts, rendering.SessionOpts{
+		Expiry:                     s.renderingTimeout * 2,
+		RefreshExpiryOnEachRequest: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer session.Dispose(ctx)
+
+	commonOpts := rendering.CommonOpts{
+		TimeoutOpts:     rendering.TimeoutOpts{Timeout: s.renderingTimeout},
+		AuthOpts:        authOpts,
+		Timezone:        loc.String(),
+		ConcurrentLimit: s.cfg.RendererConcurrentRequestLimit,
+	}
+
+	commonOpts.Path = dashboardPath("d", dash, r, from, to, 0)
+	res, err := s.renderService.Render(ctx, rendering.RenderPNG, rendering.Opts{
+		CommonOpts: commonOpts,
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		Width:             s.imageWidth,
+		Height:            -1,
+		DeviceScaleFactor: s.imageScaleFactor,
+		Theme:             models.Theme(r.Options.Theme),
+	}, session)
+	if err != nil {
+		return fmt.Errorf("failed to render dashboard: %w", err)
+	}
+
+	screenshot, err := os.Open(filepath.Clean(res.FilePath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = screenshot.Close() }()
+
+	var pdf bytes.Buffer
+	if err := buildPDF(pdfDocument{
+		Title:       dash.Title,
+		Subtitle:    fmt.Sprintf("%s to %s", from.In(loc).Format(time.RFC1123), to.In(loc).Format(time.RFC1123)),
+		Orientation: r.Options.Orientation,
+		Created:     s.now(),
+	}, screenshot, &pdf); err != nil {
+		return err
+	}
+
+	fileName := dash.Slug
+	if fileName == "" {
+		fileName = "report"
+	}
+	attachments := []*notifications.SendEmailAttachFile{
+		{Name: fileName + ".pdf", Content: pdf.Bytes()},
+	}
+
+	for _, panelID := range r.Options.CSVPanelIDs {
+		commonOpts.Path = dashboardPath("d-solo", dash, r, from, to, panelID)
+		res, err := s.renderService.RenderCSV(ctx, rendering.CSVOpts{CommonOpts: commonOpts}, session)
+		if err != nil {
+			return fmt.Errorf("failed to export the data of panel %d: %w", panelID, err)
+		}
+
+		content, err := os.ReadFile(filepath.Clean(res.FilePath))
+		if err != nil {
+			return err
+		}
+
+		name := res.FileName
+		if name == "" {
+			name = fmt.Sprintf("%s-panel-%d.csv", fileName, panelID)
+		}
+		attachments = append(attachments, &notifications.SendEmailAttachFile{Name: name, Content: content})
+	}
+
+	var replyTo []string
+	if r.ReplyTo != "" {
+		replyTo = []string{r.ReplyTo}
+	}
+
+	return s.emailSender.SendEmailCommandHandlerSync(ctx, &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       r.Recipients,
+			Template: emailTemplate,
+			ReplyTo:  replyTo,
+			Data: map[string]any{
+				"Name":           r.Name,
+				"Message":        r.Message,
+				"DashboardTitle": dash.Title,
+				"DashboardURL":   s.cfg.AppURL + dashboardPath("d", dash, r, from, to, 0),
+				"From":           from.In(loc).Format(time.RFC1123),
+				"To":             to.In(loc).Format(time.RFC1123),
+			},
+			AttachedFiles: attachments,
+		},
+	})
+}
+
+// timeRange resolves the time range of the report, or the default time range of the
+// dashboard if the report doesn't have one, to absolute times.
+func (s *Service) timeRange(r *reports.Report, dash *dashboards.Dashboard, loc *time.Location) (time.Time, time.Time, error) {
+	from, to := r.TimeRange.From, r.TimeRange.To
+	if from == "" && to == "" && dash.Data != nil {
+		from = dash.Data.GetPath("time", "from").MustString()
+		to = dash.Data.GetPath("time", "to").MustString()
+	}
+	if from == "" {
+		from = "now-6h"
+	}
+	if to == "" {
+		to = "now"
+	}
+
+	tr := gtime.NewTimeRange(from, to)
+	now := s.now()
+	fromTime, err := tr.ParseFrom(gtime.WithLocation(loc), gtime.WithNow(now))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid time range from %q: %w", from, err)
+	}
+	toTime, err := tr.ParseTo(gtime.WithLocation(loc), gtime.WithNow(now))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid time range to %q: %w", to, err)
+	}
+	return fromTime, toTime, nil
+}
+
+// dashboardPath returns the path of the dashboard, or of a single panel if panelID
+// is set, relative to the root URL of Grafana.
+func dashboardPath(prefix string, dash *dashboards.Dashboard, r *reports.Report, from, to time.Time, panelID int64) string {
+	u := url.URL{Path: path.Join(prefix, dash.UID, dash.Slug)}
+	q := u.Query()
+	q.Set("orgId", strconv.FormatInt(r.OrgID, 10))
+	q.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+	q.Set("to", strconv.FormatInt(to.UnixMilli(), 10))
+	for name, values := range r.Variables {
+		for _, v := range values {
+			q.Add("var-"+name, v)
+		}
+	}
+	if panelID > 0 {
+		q.Set("panelId", strconv.FormatInt(panelID, 10))
+	} else {
+		q.Set("kiosk", "1")
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,424 @@
+package reportsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var tracer = otel.Tracer("github.com/grafana/grafana/pkg/services/reports/reportsimpl")
+
+const (
+	schedulerInterval = time.Minute
+	// schedulerLockTimeout is the time after which the lock of an instance that
+	// stopped while sending reports is taken over by another instance.
+	schedulerLockTimeout = 30 * time.Minute
+
+	maxNameLength = 190
+)
+
+var _ reports.Service = (*Service)(nil)
+
+type Service struct {
+	cfg              *setting.Cfg
+	store            store
+	accessControl    ac.AccessControl
+	dashboardService dashboards.DashboardService
+	renderService    rendering.Service
+	userService      user.Service
+	emailSender      notifications.EmailSender
+	serverLock       *serverlock.ServerLockService
+	log              log.Logger
+	now              func() time.Time
+
+	enabled          bool
+	renderingTimeout time.Duration
+	imageWidth       int
+	imageScaleFactor float64
+	historyLimit     int
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sqlStore db.DB,
+	routeRegister routing.RouteRegister,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	dashboardService dashboards.DashboardService,
+	renderService rendering.Service,
+	userService user.Service,
+	emailSender notifications.EmailSender,
+	serverLock *serverlock.ServerLockService,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("reporting")
+	s := &Service{
+		cfg:              cfg,
+		store:            &xormStore{db: sqlStore},
+		accessControl:    accessControl,
+		dashboardService: dashboardService,
+		renderService:    renderService,
+		userService:      userService,
+		emailSender:      emailSender,
+		serverLock:       serverLock,
+		log:              log.New("reports"),
+		now:              time.Now,
+		enabled:          section.Key("enabled").MustBool(false),
+		renderingTimeout: section.Key("rendering_timeout").MustDuration(time.Minute),
+		imageWidth:       section.Key("image_width").MustInt(1400),
+		imageScaleFactor: section.Key("image_scale_factor").MustFloat64(1),
+		historyLimit:     section.Key("history_limit").MustInt(100),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	if err := declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.enabled
+}
+
+// Run sends the reports that are due. Every instance checks for due reports, the
+// server lock makes sure that only one of them sends reports at a time.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.serverLock.LockExecuteAndRelease(ctx, "send scheduled reports", schedulerLockTimeout, func(ctx context.Context) {
+				s.sendDueReports(ctx)
+			})
+			var lockErr *serverlock.ServerLockExistsError
+			if errors.As(err, &lockErr) {
+				s.log.Debug("Scheduled reports are sent by another instance")
+			} else if err != nil {
+				s.log.Error("Failed to lock and execute sending scheduled reports", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) sendDueReports(ctx context.Context) {
+	ctx, span := tracer.Start(ctx, "reports.sendDueReports")
+	defer span.End()
+
+	now := s.now()
+	due, err := s.store.GetDue(ctx, now.Unix())
+	if err != nil {
+		s.log.Error("Failed to get due reports", "error", err)
+		return
+	}
+
+	for _, r := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		dto, err := r.toDTO()
+		if err != nil {
+			s.log.Error("Failed to read report", "orgID", r.OrgID, "uid", r.UID, "error", err)
+			continue
+		}
+
+		// The next run is moved forward before the report is sent, so that a report
+		// is not sent twice if this instance stops while sending it.
+		var next int64
+		if t, err := nextRun(dto.Schedule, now); err != nil {
+			s.log.Error("Failed to schedule the next run of the report, disabling its schedule", "orgID", r.OrgID, "uid", r.UID, "error", err)
+		} else {
+			next = t.Unix()
+		}
+
+		claimed, err := s.store.ClaimRun(ctx, r.ID, r.NextRun, next)
+		if err != nil {
+			s.log.Error("Failed to claim report run", "orgID", r.OrgID, "uid", r.UID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.send(ctx, dto, reports.RunTriggerSchedule)
+	}
+}
+
+func (s *Service) CreateReport(ctx context.Context, cmd *reports.CreateReportCommand) (*reports.Report, error) {
+	uid := cmd.UID
+	if uid == "" {
+		uid = util.GenerateShortUID()
+	} else if err := util.ValidateUID(uid); err != nil {
+		return nil, invalidReport("%s", err)
+	}
+
+	now := s.now()
+	r := &report{
+		OrgID:     cmd.OrgID,
+		UID:       uid,
+		CreatedBy: cmd.CreatedBy,
+		UpdatedBy: cmd.CreatedBy,
+		Created:   now,
+	}
+	if err := s.setFields(ctx, r, reportFields{
+		Name:         cmd.Name,
+		DashboardUID: cmd.DashboardUID,
+		Recipients:   cmd.Recipients,
+		ReplyTo:      cmd.ReplyTo,
+		Message:      cmd.Message,
+		Schedule:     cmd.Schedule,
+		TimeRange:    cmd.TimeRange,
+		Variables:    cmd.Variables,
+		Options:      cmd.Options,
+		Enabled:      cmd.Enabled,
+	}, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Insert(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return r.toDTO()
+}
+
+func (s *Service) UpdateReport(ctx context.Context, cmd *reports.UpdateReportCommand) (*reports.Report, error) {
+	r, err := s.store.Get(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The report is rendered as whoever saved it last, so that changing the dashboard
+	// of a report doesn't expose what only the previous editor could see.
+	r.UpdatedBy = cmd.UpdatedBy
+
+	now := s.now()
+	if err := s.setFields(ctx, r, reportFields{
+		Name:         cmd.Name,
+		DashboardUID: cmd.DashboardUID,
+		Recipients:   cmd.Recipients,
+		ReplyTo:      cmd.ReplyTo,
+		Message:      cmd.Message,
+		Schedule:     cmd.Schedule,
+		TimeRange:    cmd.TimeRange,
+		Variables:    cmd.Variables,
+		Options:      cmd.Options,
+		Enabled:      cmd.Enabled,
+	}, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Update(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return r.toDTO()
+}
+
+func (s *Service) GetReport(ctx context.Context, orgID int64, uid string) (*reports.Report, error) {
+	r, err := s.store.Get(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.toDTO()
+}
+
+func (s *Service) GetReports(ctx context.Context, orgID int64) ([]*reports.Report, error) {
+	rows, err := s.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*reports.Report, 0, len(rows))
+	for _, r := range rows {
+		dto, err := r.toDTO()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+func (s *Service) DeleteReport(ctx context.Context, orgID int64, uid string) error {
+	return s.store.Delete(ctx, orgID, uid)
+}
+
+func (s *Service) SendReport(ctx context.Context, orgID int64, uid string) (*reports.Run, error) {
+	r, err := s.GetReport(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, r, reports.RunTriggerManual), nil
+}
+
+func (s *Service) GetRuns(ctx context.Context, orgID int64, uid string, limit int) ([]*reports.Run, error) {
+	r, err := s.store.Get(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.GetRuns(ctx, r.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*reports.Run, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.toDTO())
+	}
+	return result, nil
+}
+
+// reportFields are the fields of a report that can be set on creation and update.
+type reportFields struct {
+	Name         string
+	DashboardUID string
+	Recipients   []string
+	ReplyTo      string
+	Message      string
+	Schedule     reports.Schedule
+	TimeRange    reports.TimeRange
+	Variables    map[string][]string
+	Options      reports.Options
+	Enabled      bool
+}
+
+// setFields validates the fields and sets them on the report, together with the
+// next run of the report if it is enabled.
+func (s *Service) setFields(ctx context.Context, r *report, f reportFields, now time.Time) error {
+	name := strings.TrimSpace(f.Name)
+	if name == "" {
+		return invalidReport("name is required")
+	}
+	if len(name) > maxNameLength {
+		return invalidReport("name can't be longer than %d characters", maxNameLength)
+	}
+
+	if f.DashboardUID == "" {
+		return invalidReport("dashboard is required")
+	}
+	if _, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: r.OrgID, UID: f.DashboardUID}); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return reports.ErrDashboardNotFound.Errorf("dashboard %s not found", f.DashboardUID)
+		}
+		return err
+	}
+
+	recipients := make([]string, 0, len(f.Recipients))
+	for _, recipient := range f.Recipients {
+		recipient = strings.TrimSpace(recipient)
+		if recipient == "" {
+			continue
+		}
+		if !util.IsEmail(recipient) {
+			return invalidReport("%q is not a valid email address", recipient)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return invalidReport("at least one recipient is required")
+	}
+	replyTo := strings.TrimSpace(f.ReplyTo)
+	if replyTo != "" && !util.IsEmail(replyTo) {
+		return invalidReport("%q is not a valid reply to address", replyTo)
+	}
+
+	next, err := nextRun(f.Schedule, now)
+	if err != nil {
+		return invalidReport("%s", err)
+	}
+
+	if f.TimeRange.From != "" || f.TimeRange.To != "" {
+		tr := gtime.NewTimeRange(f.TimeRange.From, f.TimeRange.To)
+		if _, err := tr.ParseFrom(); err != nil {
+			return invalidReport("invalid time range from %q", f.TimeRange.From)
+		}
+		if _, err := tr.ParseTo(); err != nil {
+			return invalidReport("invalid time range to %q", f.TimeRange.To)
+		}
+	}
+
+	options := f.Options
+	switch options.Orientation {
+	case "":
+		options.Orientation = reports.OrientationLandscape
+	case reports.OrientationLandscape, reports.OrientationPortrait:
+	default:
+		return invalidReport("orientation must be %s or %s", reports.OrientationLandscape, reports.OrientationPortrait)
+	}
+	switch options.Theme {
+	case "":
+		options.Theme = "light"
+	case "light", "dark":
+	default:
+		return invalidReport("theme must be light or dark")
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	var variablesJSON []byte
+	if len(f.Variables) > 0 {
+		if variablesJSON, err = json.Marshal(f.Variables); err != nil {
+			return err
+		}
+	}
+
+	r.Name = name
+	r.DashboardUID = f.DashboardUID
+	r.Recipients = strings.Join(recipients, ",")
+	r.ReplyTo = replyTo
+	r.Message = f.Message
+	r.ScheduleCron = strings.TrimSpace(f.Schedule.Cron)
+	r.ScheduleTimezone = f.Schedule.Timezone
+	r.TimeFrom = f.TimeRange.From
+	r.TimeTo = f.TimeRange.To
+	r.Variables = string(variablesJSON)
+	r.Options = string(optionsJSON)
+	r.Enabled = f.Enabled
+	r.NextRun = 0
+	if f.Enabled {
+		r.NextRun = next.Unix()
+	}
+	r.Updated = now
+
+	return nil
+}
+
+func invalidReport(format string, args ...any) error {
+	return reports.ErrInvalidReport.Build(errutil.TemplateData{
+		Public: map[string]any{"Reason": fmt.Sprintf(format, args...)},
+	})
+}
@@ -0,0 +1,218 @@
+package reportsimpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func setupTestService(t *testing.T) (*Service, *time.Time, *rendering.MockService) {
+	t.Helper()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) // Friday
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+		return q.UID == "slo"
+	})).Return(&dashboards.Dashboard{UID: "slo", Slug: "slo", Title: "SLO"}, nil).Maybe()
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+
+	renderService := rendering.NewMockService(gomock.NewController(t))
+
+	return &Service{
+		cfg:              setting.NewCfg(),
+		store:            &xormStore{db: db.InitTestDB(t)},
+		dashboardService: dashboardService,
+		renderService:    renderService,
+		userService:      &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 1, OrgID: 1}},
+		emailSender:      notifications.MockNotificationService(),
+		log:              log.NewNopLogger(),
+		now:              func() time.Time { return now },
+		enabled:          true,
+		renderingTimeout: time.Minute,
+		imageWidth:       1400,
+		imageScaleFactor: 1,
+		historyLimit:     2,
+	}, &now, renderService
+}
+
+func weeklyReport() *reports.CreateReportCommand {
+	return &reports.CreateReportCommand{
+		OrgID:        1,
+		CreatedBy:    1,
+		Name:         "Weekly SLO",
+		DashboardUID: "slo",
+		Recipients:   []string{"manager@example.com", " lead@example.com "},
+		Schedule:     reports.Schedule{Cron: "0 9 * * 1", Timezone: "Europe/Berlin"},
+		TimeRange:    reports.TimeRange{From: "now-7d", To: "now"},
+		Enabled:      true,
+	}
+}
+
+func TestIntegrationReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, _, _ := setupTestService(t)
+
+	created, err := s.CreateReport(ctx, weeklyReport())
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+	require.Equal(t, []string{"manager@example.com", "lead@example.com"}, created.Recipients)
+	require.Equal(t, reports.OrientationLandscape, created.Options.Orientation)
+	require.Equal(t, "light", created.Options.Theme)
+	require.NotNil(t, created.NextRun)
+	require.Equal(t, time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), *created.NextRun)
+	require.Equal(t, int64(1), created.UpdatedBy)
+
+	t.Run("rejects duplicate uids", func(t *testing.T) {
+		cmd := weeklyReport()
+		cmd.UID = created.UID
+		_, err := s.CreateReport(ctx, cmd)
+		require.ErrorIs(t, err, reports.ErrReportUIDExists)
+	})
+
+	t.Run("rejects invalid reports", func(t *testing.T) {
+		for name, modify := range map[string]func(cmd *reports.CreateReportCommand){
+			"no name":           func(cmd *reports.CreateReportCommand) { cmd.Name = " " },
+			"no recipients":     func(cmd *reports.CreateReportCommand) { cmd.Recipients = nil },
+			"invalid recipient": func(cmd *reports.CreateReportCommand) { cmd.Recipients = []string{"manager"} },
+			"invalid schedule":  func(cmd *reports.CreateReportCommand) { cmd.Schedule.Cron = "every monday" },
+			"invalid time":      func(cmd *reports.CreateReportCommand) { cmd.TimeRange.From = "yesterday" },
+			"invalid theme":     func(cmd *reports.CreateReportCommand) { cmd.Options.Theme = "blue" },
+		} {
+			cmd := weeklyReport()
+			modify(cmd)
+			_, err := s.CreateReport(ctx, cmd)
+			var gfErr errutil.Error
+			require.ErrorAs(t, err, &gfErr, name)
+			require.Equal(t, "reports.invalid", gfErr.MessageID, name)
+		}
+
+		cmd := weeklyReport()
+		cmd.DashboardUID = "missing"
+		_, err := s.CreateReport(ctx, cmd)
+		require.ErrorIs(t, err, reports.ErrDashboardNotFound)
+	})
+
+	t.Run("updates reports", func(t *testing.T) {
+		updated, err := s.UpdateReport(ctx, &reports.UpdateReportCommand{
+			OrgID:        1,
+			UID:          created.UID,
+			UpdatedBy:    2,
+			Name:         "Daily SLO",
+			DashboardUID: "slo",
+			Recipients:   []string{"manager@example.com"},
+			Schedule:     reports.Schedule{Cron: "@daily"},
+			Options:      reports.Options{Orientation: reports.OrientationPortrait, CSVPanelIDs: []int64{2}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, created.ID, updated.ID)
+		require.Equal(t, "Daily SLO", updated.Name)
+		require.True(t, created.Created.Equal(updated.Created))
+		require.Equal(t, int64(1), updated.CreatedBy)
+		require.Equal(t, int64(2), updated.UpdatedBy)
+		require.False(t, updated.Enabled)
+		require.Nil(t, updated.NextRun)
+
+		list, err := s.GetReports(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, []int64{2}, list[0].Options.CSVPanelIDs)
+
+		list, err = s.GetReports(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+
+	t.Run("deletes reports", func(t *testing.T) {
+		require.NoError(t, s.DeleteReport(ctx, 1, created.UID))
+		_, err := s.GetReport(ctx, 1, created.UID)
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+		require.ErrorIs(t, s.DeleteReport(ctx, 1, created.UID), reports.ErrReportNotFound)
+	})
+}
+
+func TestIntegrationSendDueReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s, now, renderService := setupTestService(t)
+	renderService.EXPECT().IsCapabilitySupported(gomock.Any(), rendering.FullHeightImages).Return(errors.New("renderer is too old")).AnyTimes()
+
+	created, err := s.CreateReport(ctx, weeklyReport())
+	require.NoError(t, err)
+
+	// Nothing is sent before the report is due.
+	s.sendDueReports(ctx)
+	runs, err := s.GetRuns(ctx, 1, created.UID, 10)
+	require.NoError(t, err)
+	require.Empty(t, runs)
+
+	*now = time.Date(2024, 3, 4, 8, 0, 30, 0, time.UTC)
+	s.sendDueReports(ctx)
+
+	runs, err = s.GetRuns(ctx, 1, created.UID, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, reports.RunTriggerSchedule, runs[0].Trigger)
+	require.Equal(t, reports.RunStatusFailed, runs[0].Status)
+	require.Contains(t, runs[0].Error, "renderer is too old")
+
+	r, err := s.GetReport(ctx, 1, created.UID)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC), *r.NextRun)
+
+	// A run that was already claimed is not sent again.
+	claimed, err := s.store.ClaimRun(ctx, r.ID, created.NextRun.Unix(), r.NextRun.Unix())
+	require.NoError(t, err)
+	require.False(t, claimed)
+	s.sendDueReports(ctx)
+	runs, err = s.GetRuns(ctx, 1, created.UID, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	t.Run("keeps a limited history", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			run, err := s.SendReport(ctx, 1, created.UID)
+			require.NoError(t, err)
+			require.Equal(t, reports.RunTriggerManual, run.Trigger)
+		}
+		runs, err := s.GetRuns(ctx, 1, created.UID, 10)
+		require.NoError(t, err)
+		require.Len(t, runs, s.historyLimit)
+		require.Equal(t, reports.RunTriggerManual, runs[1].Trigger)
+	})
+}
+
+func TestDashboardPath(t *testing.T) {
+	dash := &dashboards.Dashboard{UID: "slo", Slug: "slo-overview"}
+	r := &reports.Report{OrgID: 2, Variables: map[string][]string{"cluster": {"eu", "us"}}}
+	from := time.UnixMilli(1000)
+	to := time.UnixMilli(2000)
+
+	require.Equal(t, "d/slo/slo-overview?from=1000&kiosk=1&orgId=2&to=2000&var-cluster=eu&var-cluster=us", dashboardPath("d", dash, r, from, to, 0))
+	require.Equal(t, "d-solo/slo/slo-overview?from=1000&orgId=2&panelId=4&to=2000&var-cluster=eu&var-cluster=us", dashboardPath("d-solo", dash, r, from, to, 4))
+}
@@ -0,0 +1,151 @@
+package reportsimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type store interface {
+	Insert(ctx context.Context, r *report) error
+	Update(ctx context.Context, r *report) error
+	Get(ctx context.Context, orgID int64, uid string) (*report, error)
+	List(ctx context.Context, orgID int64) ([]*report, error)
+	// Delete deletes the report together with its run history.
+	Delete(ctx context.Context, orgID int64, uid string) error
+	// GetDue returns the enabled reports of all orgs whose next run is at or before now.
+	GetDue(ctx context.Context, now int64) ([]*report, error)
+	// ClaimRun moves the next run of the report from prev to next. It returns false
+	// if the run was already claimed, or the report was changed in the meantime.
+	ClaimRun(ctx context.Context, id int64, prev, next int64) (bool, error)
+
+	// InsertRun records a run and deletes the oldest runs of the report beyond keep.
+	InsertRun(ctx context.Context, r *run, keep int) error
+	GetRuns(ctx context.Context, reportID int64, limit int) ([]*run, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) Insert(ctx context.Context, r *report) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", r.OrgID, r.UID).Exist(&report{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return reports.ErrReportUIDExists.Errorf("report with uid %s already exists", r.UID)
+		}
+
+		_, err = sess.Insert(r)
+		return err
+	})
+}
+
+func (s *xormStore) Update(ctx context.Context, r *report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(r.ID).AllCols().Omit("id", "org_id", "uid", "created_by", "created").Update(r)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reports.ErrReportNotFound.Errorf("report with uid %s not found", r.UID)
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) Get(ctx context.Context, orgID int64, uid string) (*report, error) {
+	r := &report{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(r)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return reports.ErrReportNotFound.Errorf("report with uid %s not found", uid)
+		}
+		return nil
+	})
+	return r, err
+}
+
+func (s *xormStore) List(ctx context.Context, orgID int64) ([]*report, error) {
+	result := make([]*report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&result)
+	})
+	return result, err
+}
+
+func (s *xormStore) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		r := &report{}
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(r)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return reports.ErrReportNotFound.Errorf("report with uid %s not found", uid)
+		}
+
+		if _, err := sess.Exec("DELETE FROM report_run WHERE report_id = ?", r.ID); err != nil {
+			return err
+		}
+		_, err = sess.Exec("DELETE FROM report WHERE id = ?", r.ID)
+		return err
+	})
+}
+
+func (s *xormStore) GetDue(ctx context.Context, now int64) ([]*report, error) {
+	result := make([]*report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("enabled = ? AND next_run > 0 AND next_run <= ?", true, now).
+			Asc("next_run").Find(&result)
+	})
+	return result, err
+}
+
+func (s *xormStore) ClaimRun(ctx context.Context, id int64, prev, next int64) (bool, error) {
+	var claimed bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE report SET next_run = ? WHERE id = ? AND next_run = ?", next, id, prev)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		claimed = rows > 0
+		return err
+	})
+	return claimed, err
+}
+
+func (s *xormStore) InsertRun(ctx context.Context, r *run, keep int) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(r); err != nil {
+			return err
+		}
+
+		var ids []int64
+		if err := sess.Table("report_run").Cols("id").Where("report_id = ?", r.ReportID).
+			Desc("id").Limit(1, keep).Find(&ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		_, err := sess.Exec("DELETE FROM report_run WHERE report_id = ? AND id <= ?", r.ReportID, ids[0])
+		return err
+	})
+}
+
+func (s *xormStore) GetRuns(ctx context.Context, reportID int64, limit int) ([]*run, error) {
+	result := make([]*run, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("report_id = ?", reportID).Desc("id").Limit(limit).Find(&result)
+	})
+	return result, err
+}
@@ -0,0 +1,51 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+)
+
+// Detail error types, https://datatracker.ietf.org/doc/html/rfc7644#section-3.12
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeUniqueness    = "uniqueness"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeNoTarget      = "noTarget"
+	scimTypeInvalidValue  = "invalidValue"
+)
+
+// scimError is returned to the client in the SCIM error format instead of the Grafana one
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string {
+	return e.detail
+}
+
+func newError(status int, scimType string, format string, args ...any) *scimError {
+	return &scimError{status: status, scimType: scimType, detail: fmt.Sprintf(format, args...)}
+}
+
+func errNotFound(resourceType, id string) *scimError {
+	return newError(http.StatusNotFound, "", "%s %s not found", resourceType, id)
+}
+
+func errorResponse(err *scimError) response.Response {
+	return scimResponse(err.status, ErrorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(err.status),
+		ScimType: err.scimType,
+		Detail:   err.detail,
+	})
+}
+
+func scimResponse(status int, body any) *response.NormalResponse {
+	return response.JSON(status, body).SetHeader("Content-Type", "application/scim+json")
+}
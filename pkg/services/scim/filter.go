@@ -0,0 +1,497 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// filter is a parsed SCIM filter, https://datatracker.ietf.org/doc/html/rfc7644#section-3.4.2.2
+// Filters are evaluated against the JSON representation of a resource.
+type filter interface {
+	matches(resource map[string]any) bool
+}
+
+type attrPath struct {
+	attr    string
+	subAttr string
+}
+
+func (p attrPath) String() string {
+	if p.subAttr == "" {
+		return p.attr
+	}
+	return p.attr + "." + p.subAttr
+}
+
+type logicalFilter struct {
+	and         bool
+	left, right filter
+}
+
+func (f *logicalFilter) matches(resource map[string]any) bool {
+	if f.and {
+		return f.left.matches(resource) && f.right.matches(resource)
+	}
+	return f.left.matches(resource) || f.right.matches(resource)
+}
+
+type notFilter struct {
+	filter filter
+}
+
+func (f *notFilter) matches(resource map[string]any) bool {
+	return !f.filter.matches(resource)
+}
+
+// valuePathFilter matches when an element of a multi-valued attribute matches the filter
+type valuePathFilter struct {
+	attr   string
+	filter filter
+}
+
+func (f *valuePathFilter) matches(resource map[string]any) bool {
+	value, _ := lookup(resource, f.attr)
+	for _, elem := range asSlice(value) {
+		if m, ok := elem.(map[string]any); ok && f.filter.matches(m) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareFilter struct {
+	path  attrPath
+	op    string
+	value any
+}
+
+func (f *compareFilter) matches(resource map[string]any) bool {
+	values := resolve(resource, f.path)
+
+	switch f.op {
+	case "pr":
+		for _, v := range values {
+			if !isEmpty(v) {
+				return true
+			}
+		}
+		return false
+	case "ne":
+		return !(&compareFilter{path: f.path, op: "eq", value: f.value}).matches(resource)
+	}
+
+	caseExact := caseExactAttributes[strings.ToLower(f.path.String())]
+	for _, v := range values {
+		if compare(v, f.op, f.value, caseExact) {
+			return true
+		}
+	}
+	return false
+}
+
+// caseExactAttributes are compared case sensitively, all other strings are compared case insensitively
+var caseExactAttributes = map[string]bool{
+	"id":         true,
+	"externalid": true,
+}
+
+var compareOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true, "pr": true,
+}
+
+func compare(actual any, op string, expected any, caseExact bool) bool {
+	switch e := expected.(type) {
+	case nil:
+		return op == "eq" && actual == nil
+	case bool:
+		a, ok := actual.(bool)
+		return ok && op == "eq" && a == e
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+		return false
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		if !caseExact {
+			a, e = strings.ToLower(a), strings.ToLower(e)
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	}
+	return false
+}
+
+// resolve returns the values of the attribute. Multi-valued attributes are flattened,
+// complex values without a sub-attribute resolve to their "value" sub-attribute.
+func resolve(resource map[string]any, path attrPath) []any {
+	value, ok := lookup(resource, path.attr)
+	if !ok {
+		return nil
+	}
+
+	values := make([]any, 0)
+	for _, elem := range asSlice(value) {
+		m, isMap := elem.(map[string]any)
+		switch {
+		case path.subAttr != "" && isMap:
+			if v, ok := lookup(m, path.subAttr); ok {
+				values = append(values, v)
+			}
+		case path.subAttr == "" && isMap:
+			if v, ok := lookup(m, "value"); ok {
+				values = append(values, v)
+			}
+		case path.subAttr == "":
+			values = append(values, elem)
+		}
+	}
+	return values
+}
+
+// lookup returns the value of an attribute, attribute names are case insensitive
+func lookup(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func asSlice(value any) []any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// equalityValue returns the compared value when the filter is a single eq comparison of the attribute,
+// which can be resolved without evaluating the filter against every resource.
+func equalityValue(f filter, attr string) (string, bool) {
+	c, ok := f.(*compareFilter)
+	if !ok || c.op != "eq" || !strings.EqualFold(c.path.String(), attr) {
+		return "", false
+	}
+	value, ok := c.value.(string)
+	return value, ok
+}
+
+// parseAttrPath parses an attribute path, the schema URI prefix is optional
+func parseAttrPath(s string) attrPath {
+	if strings.HasPrefix(strings.ToLower(s), "urn:") {
+		s = s[strings.LastIndex(s, ":")+1:]
+	}
+
+	attr, subAttr, _ := strings.Cut(s, ".")
+	return attrPath{attr: attr, subAttr: subAttr}
+}
+
+func parseFilter(s string) (filter, error) {
+	tokens, err := tokenizeFilter(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return nil, errInvalidFilter("unexpected %q", t.text)
+	}
+	return f, nil
+}
+
+func errInvalidFilter(format string, args ...any) *scimError {
+	return newError(http.StatusBadRequest, scimTypeInvalidFilter, "invalid filter: "+format, args...)
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenizeFilter(s string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case ' ', '\t', '\n', '\r':
+			i++
+		case '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "("})
+			i++
+		case ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")"})
+			i++
+		case '[':
+			tokens = append(tokens, token{kind: tokenLBracket, text: "["})
+			i++
+		case ']':
+			tokens = append(tokens, token{kind: tokenRBracket, text: "]"})
+			i++
+		case '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, errInvalidFilter("unterminated string")
+			}
+			var value string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &value); err != nil {
+				return nil, errInvalidFilter("invalid string %s", s[i:end+1])
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value})
+			i = end + 1
+		default:
+			end := len(s)
+			if n := strings.IndexAny(s[i:], " \t\n\r()[]\""); n >= 0 {
+				end = i + n
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser parses filters with the precedence not, and, or
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return token{}, errInvalidFilter("unexpected end of filter")
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) expect(kind tokenKind, text string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != kind {
+		return errInvalidFilter("expected %q, got %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *filterParser) keyword(word string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokenWord && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filter, error) {
+	if p.keyword("not") {
+		if err := p.expect(tokenLParen, "("); err != nil {
+			return nil, err
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return &notFilter{filter: f}, nil
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.kind {
+	case tokenLParen:
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case tokenWord:
+		return p.parseAttrExpression(parseAttrPath(t.text))
+	default:
+		return nil, errInvalidFilter("unexpected %q", t.text)
+	}
+}
+
+func (p *filterParser) parseAttrExpression(path attrPath) (filter, error) {
+	if next, ok := p.peek(); ok && next.kind == tokenLBracket {
+		p.pos++
+		if path.subAttr != "" {
+			return nil, errInvalidFilter("unexpected sub-attribute %q", path)
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRBracket, "]"); err != nil {
+			return nil, err
+		}
+		return &valuePathFilter{attr: path.attr, filter: f}, nil
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(t.text)
+	if t.kind != tokenWord || !compareOperators[op] {
+		return nil, errInvalidFilter("unknown operator %q", t.text)
+	}
+	if op == "pr" {
+		return &compareFilter{path: path, op: op}, nil
+	}
+
+	t, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseCompareValue(t)
+	if err != nil {
+		return nil, err
+	}
+	return &compareFilter{path: path, op: op, value: value}, nil
+}
+
+func parseCompareValue(t token) (any, error) {
+	if t.kind == tokenString {
+		return t.text, nil
+	}
+	if t.kind != tokenWord {
+		return nil, errInvalidFilter("unexpected %q", t.text)
+	}
+
+	switch strings.ToLower(t.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseFloat(t.text, 64); err == nil {
+		return n, nil
+	}
+	return nil, errInvalidFilter("invalid value %q", t.text)
+}
@@ -0,0 +1,100 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	resource := map[string]any{
+		"id":         "u1",
+		"externalId": "Ext-1",
+		"userName":   "Alice",
+		"active":     true,
+		"name":       map[string]any{"givenName": "Alice", "familyName": "Smith"},
+		"emails": []any{
+			map[string]any{"value": "alice@example.org", "type": "work", "primary": true},
+			map[string]any{"value": "alice@home.example", "type": "home"},
+		},
+		"meta": map[string]any{"resourceType": "User"},
+	}
+
+	tests := []struct {
+		desc    string
+		filter  string
+		matches bool
+	}{
+		{desc: "should compare strings case insensitively", filter: `userName eq "alice"`, matches: true},
+		{desc: "should compare ids case sensitively", filter: `externalId eq "ext-1"`, matches: false},
+		{desc: "should match ids exactly", filter: `externalId eq "Ext-1"`, matches: true},
+		{desc: "should support operators case insensitively", filter: `userName EQ "alice"`, matches: true},
+		{desc: "should support ne", filter: `userName ne "bob"`, matches: true},
+		{desc: "should support co", filter: `userName co "lic"`, matches: true},
+		{desc: "should support sw", filter: `userName sw "al"`, matches: true},
+		{desc: "should support ew", filter: `userName ew "ce"`, matches: true},
+		{desc: "should support pr", filter: `externalId pr`, matches: true},
+		{desc: "should not match missing attributes with pr", filter: `displayName pr`, matches: false},
+		{desc: "should compare booleans", filter: `active eq true`, matches: true},
+		{desc: "should support sub-attributes", filter: `name.familyName eq "Smith"`, matches: true},
+		{desc: "should match any value of multi-valued attributes", filter: `emails eq "alice@home.example"`, matches: true},
+		{desc: "should match sub-attributes of multi-valued attributes", filter: `emails.type eq "home"`, matches: true},
+		{desc: "should support value paths", filter: `emails[type eq "work" and value co "example.org"]`, matches: true},
+		{desc: "should evaluate value paths per element", filter: `emails[type eq "home" and value co "example.org"]`, matches: false},
+		{desc: "should support and", filter: `userName eq "alice" and active eq false`, matches: false},
+		{desc: "should support or", filter: `userName eq "bob" or active eq true`, matches: true},
+		{desc: "should support not", filter: `not (userName eq "alice")`, matches: false},
+		{desc: "should give and precedence over or", filter: `userName eq "bob" and active eq true or externalId pr`, matches: true},
+		{desc: "should support grouping", filter: `userName eq "bob" and (active eq true or externalId pr)`, matches: false},
+		{desc: "should support schema prefixed attributes", filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice"`, matches: true},
+		{desc: "should support escaped strings", filter: `userName eq "al\"ice"`, matches: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f, err := parseFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matches, f.matches(resource))
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	filters := []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName foo "alice"`,
+		`userName eq "alice`,
+		`userName eq alice`,
+		`(userName eq "alice"`,
+		`emails[type eq "work"`,
+		`userName eq "alice" and`,
+		`userName eq "alice" "bob"`,
+	}
+	for _, filter := range filters {
+		t.Run(filter, func(t *testing.T) {
+			_, err := parseFilter(filter)
+			var scimErr *scimError
+			require.ErrorAs(t, err, &scimErr)
+			assert.Equal(t, scimTypeInvalidFilter, scimErr.scimType)
+		})
+	}
+}
+
+func TestEqualityValue(t *testing.T) {
+	f, err := parseFilter(`userName eq "alice"`)
+	require.NoError(t, err)
+
+	value, ok := equalityValue(f, "username")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", value)
+
+	_, ok = equalityValue(f, "externalId")
+	assert.False(t, ok)
+
+	f, err = parseFilter(`userName eq "alice" or userName eq "bob"`)
+	require.NoError(t, err)
+	_, ok = equalityValue(f, "userName")
+	assert.False(t, ok)
+}
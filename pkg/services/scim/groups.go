@@ -0,0 +1,347 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (api *API) getGroup(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	t, scimErr := api.findTeam(ctx, c, web.Params(c.Req)[":id"])
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	resource, err := api.toGroupResource(ctx, c, t, !excludesMembers(c))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get team", err)
+	}
+	return resourceResponse(c, http.StatusOK, resource, resource.Meta, "")
+}
+
+func (api *API) listGroups(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	params, scimErr := parseListParams(c)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	query := &team.SearchTeamsQuery{OrgID: c.SignedInUser.GetOrgID(), SignedInUser: c.SignedInUser}
+	// identity providers look up groups by name before creating them
+	if name, ok := equalityValue(params.filter, "displayName"); ok {
+		query.Name = name
+	}
+	result, err := api.teamService.SearchTeams(ctx, query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search teams", err)
+	}
+
+	withMembers := !excludesMembers(c)
+	resources := make([]any, 0, len(result.Teams))
+	for _, t := range result.Teams {
+		resource, err := api.toGroupResource(ctx, c, t, withMembers || params.filter != nil)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to search teams", err)
+		}
+		if params.filter != nil {
+			m, err := toMap(resource)
+			if err != nil {
+				return response.Error(http.StatusInternalServerError, "Failed to search teams", err)
+			}
+			if !params.filter.matches(m) {
+				continue
+			}
+			if !withMembers {
+				resource.Members = nil
+			}
+		}
+		resources = append(resources, resource)
+	}
+	return scimResponse(http.StatusOK, params.page(resources))
+}
+
+// excludesMembers reports whether the client asked to omit members, which is expensive for large teams
+func excludesMembers(c *contextmodel.ReqContext) bool {
+	for _, attr := range splitAttributes(c.Req.URL.Query().Get("excludedAttributes")) {
+		if parseAttrPath(attr).attr == "members" {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *API) findTeam(ctx context.Context, c *contextmodel.ReqContext, id string) (*team.TeamDTO, *scimError) {
+	t, err := api.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		UID:          id,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return nil, errNotFound(ResourceTypeGroup, id)
+		}
+		return nil, newError(http.StatusInternalServerError, "", "failed to get team")
+	}
+	return t, nil
+}
+
+func (api *API) toGroupResource(ctx context.Context, c *contextmodel.ReqContext, t *team.TeamDTO, withMembers bool) (*Group, error) {
+	resource := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          t.UID,
+		ExternalID:  t.ExternalUID,
+		DisplayName: t.Name,
+	}
+
+	if withMembers {
+		members, err := api.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{
+			OrgID:        c.SignedInUser.GetOrgID(),
+			TeamID:       t.ID,
+			SignedInUser: c.SignedInUser,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resource.Members = make([]Member, 0, len(members))
+		for _, m := range members {
+			resource.Members = append(resource.Members, Member{
+				Value:   m.UserUID,
+				Display: m.Login,
+				Ref:     api.location("Users", m.UserUID),
+			})
+		}
+	}
+
+	resource.Meta = &Meta{
+		ResourceType: ResourceTypeGroup,
+		Location:     api.location("Groups", t.UID),
+		Version:      version(resource),
+	}
+	return resource, nil
+}
+
+func (api *API) createGroup(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	var resource Group
+	if scimErr := decode(c, &resource); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	if resource.DisplayName == "" {
+		return errorResponse(newError(http.StatusBadRequest, scimTypeInvalidValue, "displayName is required"))
+	}
+
+	members, scimErr := api.resolveMembers(ctx, c, resource.Members)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	t, err := api.teamService.CreateTeam(ctx, &team.CreateTeamCommand{
+		Name:          resource.DisplayName,
+		ExternalUID:   resource.ExternalID,
+		IsProvisioned: true,
+		OrgID:         c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNameTaken) {
+			return errorResponse(newError(http.StatusConflict, scimTypeUniqueness, "group %s already exists", resource.DisplayName))
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to create team", err)
+	}
+
+	if err := api.syncMembers(ctx, c.SignedInUser.GetOrgID(), t.ID, nil, members); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to add team members", err)
+	}
+	return api.groupResponse(c, http.StatusCreated, t.UID)
+}
+
+func (api *API) replaceGroup(c *contextmodel.ReqContext) response.Response {
+	var resource Group
+	if scimErr := decode(c, &resource); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	return api.modifyGroup(c, func(*Group) (*Group, *scimError) {
+		return &resource, nil
+	})
+}
+
+func (api *API) patchGroup(c *contextmodel.ReqContext) response.Response {
+	var patch PatchRequest
+	if scimErr := decode(c, &patch); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	return api.modifyGroup(c, func(current *Group) (*Group, *scimError) {
+		base := *current
+		base.Meta = nil
+
+		m, err := toMap(base)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "", "failed to patch group")
+		}
+		if err := applyPatch(m, patch.Operations); err != nil {
+			var scimErr *scimError
+			if errors.As(err, &scimErr) {
+				return nil, scimErr
+			}
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "%s", err)
+		}
+
+		var patched Group
+		if scimErr := fromMap(m, &patched); scimErr != nil {
+			return nil, scimErr
+		}
+		return &patched, nil
+	})
+}
+
+// modifyGroup replaces the team and its members with the resource returned by modify
+func (api *API) modifyGroup(c *contextmodel.ReqContext, modify func(current *Group) (*Group, *scimError)) response.Response {
+	ctx := c.Req.Context()
+	t, scimErr := api.findTeam(ctx, c, web.Params(c.Req)[":id"])
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	current, err := api.toGroupResource(ctx, c, t, true)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get team", err)
+	}
+	if scimErr := checkPrecondition(c, current.Meta.Version); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	resource, scimErr := modify(current)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	if resource.DisplayName == "" {
+		return errorResponse(newError(http.StatusBadRequest, scimTypeInvalidValue, "displayName is required"))
+	}
+	if resource.ID != "" && resource.ID != t.UID {
+		return errorResponse(newError(http.StatusBadRequest, scimTypeInvalidValue, "id is immutable"))
+	}
+
+	desired, scimErr := api.resolveMembers(ctx, c, resource.Members)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	existing, scimErr := api.resolveMembers(ctx, c, current.Members)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	if err := api.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{
+		ID:          t.ID,
+		Name:        resource.DisplayName,
+		Email:       t.Email,
+		ExternalUID: resource.ExternalID,
+		OrgID:       t.OrgID,
+	}); err != nil {
+		if errors.Is(err, team.ErrTeamNameTaken) {
+			return errorResponse(newError(http.StatusConflict, scimTypeUniqueness, "group %s already exists", resource.DisplayName))
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to update team", err)
+	}
+
+	if err := api.syncMembers(ctx, t.OrgID, t.ID, existing, desired); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to update team members", err)
+	}
+	return api.groupResponse(c, http.StatusOK, t.UID)
+}
+
+// resolveMembers returns the ids of the referenced users by their SCIM id, members must belong to the org
+func (api *API) resolveMembers(ctx context.Context, c *contextmodel.ReqContext, members []Member) (map[string]int64, *scimError) {
+	ids := make(map[string]int64, len(members))
+	for _, m := range members {
+		if _, ok := ids[m.Value]; ok {
+			continue
+		}
+		usr, err := api.userService.GetByUID(ctx, &user.GetUserByUIDQuery{UID: m.Value})
+		if errors.Is(err, user.ErrUserNotFound) || (err == nil && usr.IsServiceAccount) {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "member %s not found", m.Value)
+		}
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "", "failed to get member")
+		}
+		member, err := api.isOrgMember(ctx, c, usr.ID)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "", "failed to get member")
+		}
+		if !member {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "member %s not found", m.Value)
+		}
+		ids[m.Value] = usr.ID
+	}
+	return ids, nil
+}
+
+// syncMembers adds and removes team members so that the team has exactly the desired members
+func (api *API) syncMembers(ctx context.Context, orgID, teamID int64, existing, desired map[string]int64) error {
+	resourceID := strconv.FormatInt(teamID, 10)
+	for uid, userID := range desired {
+		if _, ok := existing[uid]; ok {
+			continue
+		}
+		if _, err := api.teamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID}, resourceID,
+			team.PermissionTypeMember.String()); err != nil {
+			return err
+		}
+	}
+	for uid, userID := range existing {
+		if _, ok := desired[uid]; ok {
+			continue
+		}
+		if _, err := api.teamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID}, resourceID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (api *API) groupResponse(c *contextmodel.ReqContext, status int, uid string) response.Response {
+	ctx := c.Req.Context()
+	t, scimErr := api.findTeam(ctx, c, uid)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	resource, err := api.toGroupResource(ctx, c, t, true)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get team", err)
+	}
+	return resourceResponse(c, status, resource, resource.Meta, resource.Meta.Location)
+}
+
+func (api *API) deleteGroup(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	t, scimErr := api.findTeam(ctx, c, web.Params(c.Req)[":id"])
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	if c.Req.Header.Get("If-Match") != "" {
+		current, err := api.toGroupResource(ctx, c, t, true)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get team", err)
+		}
+		if scimErr := checkPrecondition(c, current.Meta.Version); scimErr != nil {
+			return errorResponse(scimErr)
+		}
+	}
+
+	if err := api.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: t.OrgID, ID: t.ID}); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return errorResponse(errNotFound(ResourceTypeGroup, t.UID))
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to delete team", err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
@@ -0,0 +1,287 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// patchPath is the target of a PATCH operation, https://datatracker.ietf.org/doc/html/rfc7644#section-3.5.2
+// For example "displayName", "name.givenName", `members[value eq "id"]` or `emails[type eq "work"].value`.
+type patchPath struct {
+	attr    string
+	filter  filter
+	subAttr string
+}
+
+func parsePatchPath(s string) (patchPath, error) {
+	open := strings.Index(s, "[")
+	if open < 0 {
+		path := parseAttrPath(s)
+		return patchPath{attr: path.attr, subAttr: path.subAttr}, nil
+	}
+
+	path := parseAttrPath(s[:open])
+	closing := strings.LastIndex(s, "]")
+	if path.subAttr != "" || closing < open {
+		return patchPath{}, errInvalidPath(s)
+	}
+
+	f, err := parseFilter(s[open+1 : closing])
+	if err != nil {
+		return patchPath{}, err
+	}
+
+	pp := patchPath{attr: path.attr, filter: f}
+	if rest := s[closing+1:]; rest != "" {
+		if !strings.HasPrefix(rest, ".") || len(rest) == 1 {
+			return patchPath{}, errInvalidPath(s)
+		}
+		pp.subAttr = rest[1:]
+	}
+	return pp, nil
+}
+
+func errInvalidPath(path string) *scimError {
+	return newError(http.StatusBadRequest, scimTypeInvalidPath, "invalid path %q", path)
+}
+
+// applyPatch applies the operations to the JSON representation of a resource
+func applyPatch(resource map[string]any, operations []PatchOperation) error {
+	for _, op := range operations {
+		var value any
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "invalid value for operation %q", op.Op)
+			}
+		}
+
+		var err error
+		// operation names are case insensitive, Azure AD sends them capitalized
+		switch strings.ToLower(op.Op) {
+		case "add":
+			err = patchSet(resource, op.Path, value, false)
+		case "replace":
+			err = patchSet(resource, op.Path, value, true)
+		case "remove":
+			err = patchRemove(resource, op.Path, value)
+		default:
+			err = newError(http.StatusBadRequest, scimTypeInvalidSyntax, "unsupported operation %q", op.Op)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchSet(resource map[string]any, path string, value any, replace bool) error {
+	if path == "" {
+		values, ok := value.(map[string]any)
+		if !ok {
+			return newError(http.StatusBadRequest, scimTypeInvalidValue, "operation without path requires an object value")
+		}
+		for k, v := range values {
+			if err := patchSet(resource, k, v, replace); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	pp, err := parsePatchPath(path)
+	if err != nil {
+		return err
+	}
+
+	existing, _ := lookup(resource, pp.attr)
+	switch {
+	case pp.filter != nil:
+		elems := asSlice(existing)
+		matched := false
+		for i, elem := range elems {
+			m, ok := elem.(map[string]any)
+			if !ok || !pp.filter.matches(m) {
+				continue
+			}
+			matched = true
+			switch {
+			case pp.subAttr != "":
+				setKey(m, pp.subAttr, value)
+			case replace:
+				elems[i] = value
+			default:
+				merge(m, value)
+			}
+		}
+		if !matched {
+			// add the value when no element matches, identity providers rely on this
+			// to set for example the work email of a user without one
+			seed, ok := seedFromFilter(pp.filter)
+			if !ok {
+				return newError(http.StatusBadRequest, scimTypeNoTarget, "no value matches path %q", path)
+			}
+			if pp.subAttr != "" {
+				setKey(seed, pp.subAttr, value)
+			} else {
+				merge(seed, value)
+			}
+			elems = append(elems, seed)
+		}
+		setKey(resource, pp.attr, elems)
+	case pp.subAttr != "":
+		m, ok := existing.(map[string]any)
+		if !ok {
+			if existing != nil {
+				return errInvalidPath(path)
+			}
+			m = map[string]any{}
+		}
+		setKey(m, pp.subAttr, value)
+		setKey(resource, pp.attr, m)
+	default:
+		current, isSlice := existing.([]any)
+		if !replace && isSlice {
+			for _, v := range asSlice(value) {
+				if !containsValue(current, v) {
+					current = append(current, v)
+				}
+			}
+			setKey(resource, pp.attr, current)
+			return nil
+		}
+		if m, ok := existing.(map[string]any); ok && !isSlice {
+			if _, isMap := value.(map[string]any); isMap {
+				merge(m, value)
+				return nil
+			}
+		}
+		setKey(resource, pp.attr, value)
+	}
+	return nil
+}
+
+func patchRemove(resource map[string]any, path string, value any) error {
+	if path == "" {
+		return newError(http.StatusBadRequest, scimTypeNoTarget, "remove operation requires a path")
+	}
+
+	pp, err := parsePatchPath(path)
+	if err != nil {
+		return err
+	}
+
+	existing, ok := lookup(resource, pp.attr)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case pp.filter != nil:
+		elems := make([]any, 0)
+		for _, elem := range asSlice(existing) {
+			m, ok := elem.(map[string]any)
+			if !ok || !pp.filter.matches(m) {
+				elems = append(elems, elem)
+				continue
+			}
+			if pp.subAttr != "" {
+				deleteKey(m, pp.subAttr)
+				elems = append(elems, m)
+			}
+		}
+		setKey(resource, pp.attr, elems)
+	case pp.subAttr != "":
+		if m, ok := existing.(map[string]any); ok {
+			deleteKey(m, pp.subAttr)
+		}
+	default:
+		current, isSlice := existing.([]any)
+		if !isSlice || value == nil {
+			deleteKey(resource, pp.attr)
+			return nil
+		}
+		// Azure AD removes members by listing them in the value instead of filtering the path
+		remaining := make([]any, 0)
+		for _, elem := range current {
+			if !containsValue(asSlice(value), elem) {
+				remaining = append(remaining, elem)
+			}
+		}
+		setKey(resource, pp.attr, remaining)
+	}
+	return nil
+}
+
+// seedFromFilter returns the attributes a new value needs to match a filter of eq comparisons
+func seedFromFilter(f filter) (map[string]any, bool) {
+	switch f := f.(type) {
+	case *compareFilter:
+		if f.op != "eq" || f.path.subAttr != "" {
+			return nil, false
+		}
+		return map[string]any{f.path.attr: f.value}, true
+	case *logicalFilter:
+		if !f.and {
+			return nil, false
+		}
+		left, ok := seedFromFilter(f.left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := seedFromFilter(f.right)
+		if !ok {
+			return nil, false
+		}
+		merge(left, right)
+		return left, true
+	}
+	return nil, false
+}
+
+// containsValue compares complex values by their "value" sub-attribute
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		a, aok := v.(map[string]any)
+		b, bok := value.(map[string]any)
+		if aok && bok {
+			av, _ := lookup(a, "value")
+			bv, _ := lookup(b, "value")
+			if as, ok := av.(string); ok && as != "" && as == bv {
+				return true
+			}
+		}
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func merge(dst map[string]any, src any) {
+	if m, ok := src.(map[string]any); ok {
+		for k, v := range m {
+			setKey(dst, k, v)
+		}
+	}
+}
+
+// setKey sets the attribute keeping the case of an existing attribute name
+func setKey(m map[string]any, key string, value any) {
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			m[k] = value
+			return
+		}
+	}
+	m[key] = value
+}
+
+func deleteKey(m map[string]any, key string) {
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			delete(m, k)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	newResource := func() map[string]any {
+		return map[string]any{
+			"userName": "alice",
+			"active":   true,
+			"emails": []any{
+				map[string]any{"value": "alice@example.org", "type": "work", "primary": true},
+			},
+			"members": []any{
+				map[string]any{"value": "u1"},
+				map[string]any{"value": "u2"},
+			},
+		}
+	}
+
+	tests := []struct {
+		desc       string
+		operations string
+		expected   map[string]any
+	}{
+		{
+			desc:       "should replace an attribute",
+			operations: `[{"op": "replace", "path": "userName", "value": "bob"}]`,
+			expected:   map[string]any{"userName": "bob"},
+		},
+		{
+			desc:       "should support capitalized operations",
+			operations: `[{"op": "Replace", "path": "active", "value": false}]`,
+			expected:   map[string]any{"active": false},
+		},
+		{
+			desc:       "should replace attributes without path",
+			operations: `[{"op": "replace", "value": {"active": false, "name.givenName": "Alice"}}]`,
+			expected:   map[string]any{"active": false, "name": map[string]any{"givenName": "Alice"}},
+		},
+		{
+			desc:       "should keep the case of existing attributes",
+			operations: `[{"op": "replace", "path": "USERNAME", "value": "bob"}]`,
+			expected:   map[string]any{"userName": "bob"},
+		},
+		{
+			desc:       "should set the sub-attribute of filtered values",
+			operations: `[{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "alice@example.com"}]`,
+			expected: map[string]any{"emails": []any{
+				map[string]any{"value": "alice@example.com", "type": "work", "primary": true},
+			}},
+		},
+		{
+			desc:       "should add a value when no value matches the filter",
+			operations: `[{"op": "add", "path": "emails[type eq \"home\"].value", "value": "alice@home.example"}]`,
+			expected: map[string]any{"emails": []any{
+				map[string]any{"value": "alice@example.org", "type": "work", "primary": true},
+				map[string]any{"value": "alice@home.example", "type": "home"},
+			}},
+		},
+		{
+			desc:       "should add members without duplicates",
+			operations: `[{"op": "add", "path": "members", "value": [{"value": "u2"}, {"value": "u3"}]}]`,
+			expected: map[string]any{"members": []any{
+				map[string]any{"value": "u1"},
+				map[string]any{"value": "u2"},
+				map[string]any{"value": "u3"},
+			}},
+		},
+		{
+			desc:       "should remove filtered members",
+			operations: `[{"op": "remove", "path": "members[value eq \"u1\"]"}]`,
+			expected:   map[string]any{"members": []any{map[string]any{"value": "u2"}}},
+		},
+		{
+			desc:       "should remove members listed in the value",
+			operations: `[{"op": "remove", "path": "members", "value": [{"value": "u2"}]}]`,
+			expected:   map[string]any{"members": []any{map[string]any{"value": "u1"}}},
+		},
+		{
+			desc:       "should remove an attribute",
+			operations: `[{"op": "remove", "path": "members"}]`,
+			expected:   map[string]any{"members": nil},
+		},
+		{
+			desc:       "should replace all members",
+			operations: `[{"op": "replace", "path": "members", "value": [{"value": "u3"}]}]`,
+			expected:   map[string]any{"members": []any{map[string]any{"value": "u3"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var operations []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.operations), &operations))
+
+			resource := newResource()
+			require.NoError(t, applyPatch(resource, operations))
+			for attr, value := range tt.expected {
+				assert.Equal(t, value, resource[attr], attr)
+			}
+		})
+	}
+}
+
+func TestApplyPatch_Invalid(t *testing.T) {
+	tests := []struct {
+		desc       string
+		operations string
+		scimType   string
+	}{
+		{
+			desc:       "should reject unknown operations",
+			operations: `[{"op": "move", "path": "userName", "value": "bob"}]`,
+			scimType:   scimTypeInvalidSyntax,
+		},
+		{
+			desc:       "should reject remove without path",
+			operations: `[{"op": "remove"}]`,
+			scimType:   scimTypeNoTarget,
+		},
+		{
+			desc:       "should reject operations without path and object value",
+			operations: `[{"op": "replace", "value": "bob"}]`,
+			scimType:   scimTypeInvalidValue,
+		},
+		{
+			desc:       "should reject invalid filters",
+			operations: `[{"op": "replace", "path": "emails[type eq]", "value": "bob"}]`,
+			scimType:   scimTypeInvalidFilter,
+		},
+		{
+			desc:       "should reject filters that cannot seed a value",
+			operations: `[{"op": "replace", "path": "emails[type co \"work\"].value", "value": "bob"}]`,
+			scimType:   scimTypeNoTarget,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var operations []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.operations), &operations))
+
+			err := applyPatch(map[string]any{"userName": "alice"}, operations)
+			var scimErr *scimError
+			require.ErrorAs(t, err, &scimErr)
+			assert.Equal(t, tt.scimType, scimErr.scimType)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package scim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	basePath = "/api/scim/v2"
+
+	defaultCount = 100
+	maxCount     = 1000
+)
+
+// API is a SCIM 2.0 service provider, https://datatracker.ietf.org/doc/html/rfc7644
+// Identity providers push users and groups, which are mapped onto org users and teams
+// of the org of the signed in service account.
+type API struct {
+	cfg                    *setting.Cfg
+	userService            user.Service
+	orgService             org.Service
+	teamService            team.Service
+	teamPermissionsService accesscontrol.TeamPermissionsService
+	authInfoService        login.AuthInfoService
+	authTokenService       auth.UserTokenService
+	accessControl          accesscontrol.AccessControl
+	settings               setting.Provider
+	logger                 log.Logger
+}
+
+func ProvideAPI(
+	routeRegister routing.RouteRegister,
+	cfg *setting.Cfg,
+	features featuremgmt.FeatureToggles,
+	acEvaluator accesscontrol.AccessControl,
+	userService user.Service,
+	orgService org.Service,
+	teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	authInfoService login.AuthInfoService,
+	authTokenService auth.UserTokenService,
+	settingsProvider setting.Provider,
+) *API {
+	api := &API{
+		cfg:                    cfg,
+		userService:            userService,
+		orgService:             orgService,
+		teamService:            teamService,
+		teamPermissionsService: teamPermissionsService,
+		authInfoService:        authInfoService,
+		authTokenService:       authTokenService,
+		accessControl:          acEvaluator,
+		settings:               settingsProvider,
+		logger:                 log.New("scim"),
+	}
+
+	//nolint:staticcheck // not yet migrated to OpenFeature
+	if features.IsEnabledGlobally(featuremgmt.FlagEnableSCIM) {
+		api.registerRoutes(routeRegister, acEvaluator)
+	}
+	return api
+}
+
+func (api *API) registerRoutes(router routing.RouteRegister, ac accesscontrol.AccessControl) {
+	authorize := accesscontrol.Middleware(ac)
+	router.Group(basePath, func(scimRoute routing.RouteRegister) {
+		scimRoute.Get("/ServiceProviderConfig", middleware.ReqSignedIn, routing.Wrap(api.getServiceProviderConfig))
+		scimRoute.Get("/ResourceTypes", middleware.ReqSignedIn, routing.Wrap(api.getResourceTypes))
+
+		scimRoute.Group("/Users", func(usersRoute routing.RouteRegister) {
+			usersRoute.Get("/", authorize(accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersRead, accesscontrol.ScopeUsersAll)),
+				routing.Wrap(api.listUsers))
+			usersRoute.Get("/:id", authorize(accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersRead, accesscontrol.ScopeUsersAll)),
+				routing.Wrap(api.getUser))
+			usersRoute.Post("/", authorize(accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersAdd, accesscontrol.ScopeUsersAll)),
+				routing.Wrap(api.createUser))
+			usersRoute.Put("/:id", authorize(accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersWrite, accesscontrol.ScopeUsersAll)),
+				routing.Wrap(api.replaceUser))
+			usersRoute.Patch("/:id", authorize(accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersWrite, accesscontrol.ScopeUsersAll)),
+				routing.Wrap(api.patchUser))
+			usersRoute.Delete("/:id", authorize(accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersRemove, accesscontrol.ScopeUsersAll)),
+				routing.Wrap(api.deleteUser))
+		}, api.requireUserSync)
+
+		writeTeam := accesscontrol.EvalAll(
+			accesscontrol.EvalPermission(accesscontrol.ActionTeamsWrite, accesscontrol.ScopeTeamsAll),
+			accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite, accesscontrol.ScopeTeamsAll),
+		)
+		scimRoute.Group("/Groups", func(groupsRoute routing.RouteRegister) {
+			groupsRoute.Get("/", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRead, accesscontrol.ScopeTeamsAll)),
+				routing.Wrap(api.listGroups))
+			groupsRoute.Get("/:id", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRead, accesscontrol.ScopeTeamsAll)),
+				routing.Wrap(api.getGroup))
+			groupsRoute.Post("/", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsCreate)),
+				routing.Wrap(api.createGroup))
+			groupsRoute.Put("/:id", authorize(writeTeam), routing.Wrap(api.replaceGroup))
+			groupsRoute.Patch("/:id", authorize(writeTeam), routing.Wrap(api.patchGroup))
+			groupsRoute.Delete("/:id", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsDelete, accesscontrol.ScopeTeamsAll)),
+				routing.Wrap(api.deleteGroup))
+		}, api.requireGroupSync)
+	})
+}
+
+// requireUserSync and requireGroupSync read the settings on every request, so that
+// changes of the [auth.scim] section apply without a restart
+func (api *API) requireUserSync(c *contextmodel.ReqContext) {
+	if !api.settings.KeyValue("auth.scim", "user_sync_enabled").MustBool(false) {
+		errorResponse(newError(http.StatusForbidden, "", "SCIM user sync is disabled")).WriteTo(c)
+	}
+}
+
+func (api *API) requireGroupSync(c *contextmodel.ReqContext) {
+	if !api.settings.KeyValue("auth.scim", "group_sync_enabled").MustBool(false) {
+		errorResponse(newError(http.StatusForbidden, "", "SCIM group sync is disabled")).WriteTo(c)
+	}
+}
+
+func (api *API) getServiceProviderConfig(c *contextmodel.ReqContext) response.Response {
+	return scimResponse(http.StatusOK, ServiceProviderConfig{
+		Schemas:          []string{SchemaServiceProviderConfig},
+		DocumentationURI: "https://grafana.com/docs/grafana/latest/developers/http_api/scim/",
+		Patch:            Supported{Supported: true},
+		Bulk:             BulkSupport{Supported: false},
+		Filter:           FilterSupport{Supported: true, MaxResults: maxCount},
+		ChangePassword:   Supported{Supported: false},
+		Sort:             Supported{Supported: false},
+		ETag:             Supported{Supported: true},
+		AuthenticationSchemes: []AuthenticationScheme{
+			{
+				Type:        "oauthbearertoken",
+				Name:        "Service account token",
+				Description: "Authentication with a Grafana service account token",
+				Primary:     true,
+			},
+		},
+	})
+}
+
+func (api *API) getResourceTypes(c *contextmodel.ReqContext) response.Response {
+	resources := []any{
+		ResourceType{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeUser,
+			Name:        ResourceTypeUser,
+			Endpoint:    "/Users",
+			Description: "Grafana users of the organization",
+			Schema:      SchemaUser,
+			Meta:        &Meta{ResourceType: "ResourceType", Location: api.location("ResourceTypes", ResourceTypeUser)},
+		},
+		ResourceType{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeGroup,
+			Name:        ResourceTypeGroup,
+			Endpoint:    "/Groups",
+			Description: "Grafana teams of the organization",
+			Schema:      SchemaGroup,
+			Meta:        &Meta{ResourceType: "ResourceType", Location: api.location("ResourceTypes", ResourceTypeGroup)},
+		},
+	}
+	return scimResponse(http.StatusOK, listResponse(resources, len(resources), 1))
+}
+
+func (api *API) location(endpoint, id string) string {
+	return strings.TrimSuffix(api.cfg.AppURL, "/") + basePath + "/" + endpoint + "/" + id
+}
+
+// decode reads the request body, SCIM clients send application/scim+json which web.Bind rejects
+func decode(c *contextmodel.ReqContext, v any) *scimError {
+	if contentType := c.Req.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && mediaType != "application/scim+json") {
+			return newError(http.StatusUnsupportedMediaType, "", "unsupported content type %q", contentType)
+		}
+	}
+	if c.Req.Body == nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "missing request body")
+	}
+	if err := json.NewDecoder(c.Req.Body).Decode(v); err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body: %s", err)
+	}
+	return nil
+}
+
+// toMap returns the JSON representation of a resource, which filters and patches are applied to
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func fromMap(m map[string]any, v any) *scimError {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid resource: %s", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid resource: %s", err)
+	}
+	return nil
+}
+
+type listParams struct {
+	filter     filter
+	startIndex int
+	count      int
+}
+
+func parseListParams(c *contextmodel.ReqContext) (listParams, *scimError) {
+	params := listParams{startIndex: 1, count: defaultCount}
+
+	query := c.Req.URL.Query()
+	if s := query.Get("startIndex"); s != "" {
+		// values below one are interpreted as one, https://datatracker.ietf.org/doc/html/rfc7644#section-3.4.2.4
+		if n, err := strconv.Atoi(s); err == nil && n > 1 {
+			params.startIndex = n
+		}
+	}
+	if s := query.Get("count"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return params, newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid count %q", s)
+		}
+		params.count = min(max(n, 0), maxCount)
+	}
+	if s := query.Get("filter"); s != "" {
+		f, err := parseFilter(s)
+		if err != nil {
+			if scimErr, ok := err.(*scimError); ok {
+				return params, scimErr
+			}
+			return params, errInvalidFilter("%s", err)
+		}
+		params.filter = f
+	}
+	return params, nil
+}
+
+// page returns the resources of the requested page, resources are sorted by the caller
+func (p listParams) page(resources []any) ListResponse {
+	start := min(p.startIndex-1, len(resources))
+	end := min(start+p.count, len(resources))
+	return listResponse(resources[start:end], len(resources), p.startIndex)
+}
+
+func listResponse(resources []any, total, startIndex int) ListResponse {
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// version computes a weak ETag from the resource without its meta attribute
+func version(resource any) string {
+	data, _ := json.Marshal(resource)
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// checkPrecondition compares the If-Match header of a modifying request with the current version
+func checkPrecondition(c *contextmodel.ReqContext, current string) *scimError {
+	ifMatch := c.Req.Header.Get("If-Match")
+	if ifMatch == "" || ifMatch == "*" {
+		return nil
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(tag) == current {
+			return nil
+		}
+	}
+	return newError(http.StatusPreconditionFailed, "", "resource has been modified, current version is %s", current)
+}
+
+// resourceResponse returns a resource with its ETag, unless the client already has the current version
+func resourceResponse(c *contextmodel.ReqContext, status int, resource any, meta *Meta, location string) response.Response {
+	if status == http.StatusOK && c.Req.Method == http.MethodGet {
+		for _, tag := range strings.Split(c.Req.Header.Get("If-None-Match"), ",") {
+			if strings.TrimSpace(tag) == meta.Version {
+				return response.Empty(http.StatusNotModified).SetHeader("ETag", meta.Version)
+			}
+		}
+	}
+
+	resp := scimResponse(status, resource).SetHeader("ETag", meta.Version)
+	if status == http.StatusCreated {
+		resp.SetHeader("Location", location)
+	}
+	return resp
+}
+
+// splitAttributes splits the comma separated attributes and excludedAttributes parameters
+func splitAttributes(s string) []string {
+	attrs := make([]string, 0)
+	for _, attr := range strings.Split(s, ",") {
+		if attr = strings.TrimSpace(attr); attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
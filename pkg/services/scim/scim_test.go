@@ -0,0 +1,647 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+const testOrgID = 1
+
+// fakeStore keeps the users, teams and sessions shared by the fake services
+type fakeStore struct {
+	users       map[int64]*user.User
+	orgMembers  map[int64]org.RoleType
+	otherOrgs   map[int64]bool
+	teams       map[int64]*team.TeamDTO
+	teamMembers map[int64]map[int64]bool
+	authInfo    map[int64]*login.UserAuth
+	revoked     []int64
+	nextID      int64
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users:       map[int64]*user.User{},
+		orgMembers:  map[int64]org.RoleType{},
+		otherOrgs:   map[int64]bool{},
+		teams:       map[int64]*team.TeamDTO{},
+		teamMembers: map[int64]map[int64]bool{},
+		authInfo:    map[int64]*login.UserAuth{},
+		nextID:      1,
+	}
+}
+
+func (s *fakeStore) addUser(login, email string, member bool) *user.User {
+	s.nextID++
+	usr := &user.User{ID: s.nextID, UID: fmt.Sprintf("uid-%d", s.nextID), Login: login, Email: email, Name: login,
+		Created: time.Unix(0, 0).UTC(), Updated: time.Unix(0, 0).UTC()}
+	s.users[usr.ID] = usr
+	if member {
+		s.orgMembers[usr.ID] = org.RoleViewer
+	}
+	return usr
+}
+
+type fakeUserService struct {
+	user.Service
+	store *fakeStore
+}
+
+func (f *fakeUserService) find(match func(u *user.User) bool) (*user.User, error) {
+	for _, u := range f.store.users {
+		if match(u) {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (f *fakeUserService) GetByID(_ context.Context, q *user.GetUserByIDQuery) (*user.User, error) {
+	return f.find(func(u *user.User) bool { return u.ID == q.ID })
+}
+
+func (f *fakeUserService) GetByUID(_ context.Context, q *user.GetUserByUIDQuery) (*user.User, error) {
+	return f.find(func(u *user.User) bool { return u.UID == q.UID })
+}
+
+func (f *fakeUserService) GetByLogin(_ context.Context, q *user.GetUserByLoginQuery) (*user.User, error) {
+	return f.find(func(u *user.User) bool {
+		return strings.EqualFold(u.Login, q.LoginOrEmail) || strings.EqualFold(u.Email, q.LoginOrEmail)
+	})
+}
+
+func (f *fakeUserService) GetByEmail(_ context.Context, q *user.GetUserByEmailQuery) (*user.User, error) {
+	return f.find(func(u *user.User) bool { return strings.EqualFold(u.Email, q.Email) })
+}
+
+func (f *fakeUserService) Create(_ context.Context, cmd *user.CreateUserCommand) (*user.User, error) {
+	if _, err := f.GetByLogin(context.Background(), &user.GetUserByLoginQuery{LoginOrEmail: cmd.Login}); err == nil {
+		return nil, user.ErrUserAlreadyExists
+	}
+	usr := f.store.addUser(strings.ToLower(cmd.Login), strings.ToLower(cmd.Email), false)
+	usr.Name = cmd.Name
+	usr.IsDisabled = cmd.IsDisabled
+	usr.IsProvisioned = cmd.IsProvisioned
+	copied := *usr
+	return &copied, nil
+}
+
+func (f *fakeUserService) Update(_ context.Context, cmd *user.UpdateUserCommand) error {
+	usr, ok := f.store.users[cmd.UserID]
+	if !ok {
+		return user.ErrUserNotFound
+	}
+	if cmd.Login != "" {
+		usr.Login = strings.ToLower(cmd.Login)
+	}
+	if cmd.Email != "" {
+		usr.Email = strings.ToLower(cmd.Email)
+	}
+	if cmd.Name != "" {
+		usr.Name = cmd.Name
+	}
+	if cmd.IsDisabled != nil {
+		usr.IsDisabled = *cmd.IsDisabled
+	}
+	if cmd.IsProvisioned != nil {
+		usr.IsProvisioned = *cmd.IsProvisioned
+	}
+	usr.Updated = usr.Updated.Add(time.Second)
+	return nil
+}
+
+type fakeOrgService struct {
+	org.Service
+	store *fakeStore
+}
+
+func (f *fakeOrgService) SearchOrgUsers(_ context.Context, q *org.SearchOrgUsersQuery) (*org.SearchOrgUsersQueryResult, error) {
+	result := &org.SearchOrgUsersQueryResult{OrgUsers: []*org.OrgUserDTO{}}
+	if q.Page > 1 {
+		return result, nil
+	}
+	for id, role := range f.store.orgMembers {
+		if q.UserID != 0 && q.UserID != id {
+			continue
+		}
+		u := f.store.users[id]
+		result.OrgUsers = append(result.OrgUsers, &org.OrgUserDTO{
+			OrgID: testOrgID, UserID: u.ID, UID: u.UID, Login: u.Login, Email: u.Email, Name: u.Name, Role: string(role),
+			IsDisabled: u.IsDisabled, IsProvisioned: u.IsProvisioned, Created: u.Created, Updated: u.Updated,
+		})
+	}
+	result.TotalCount = int64(len(result.OrgUsers))
+	return result, nil
+}
+
+func (f *fakeOrgService) GetUserOrgList(_ context.Context, q *org.GetUserOrgListQuery) ([]*org.UserOrgDTO, error) {
+	orgs := make([]*org.UserOrgDTO, 0)
+	if role, ok := f.store.orgMembers[q.UserID]; ok {
+		orgs = append(orgs, &org.UserOrgDTO{OrgID: testOrgID, Role: role})
+	}
+	if f.store.otherOrgs[q.UserID] {
+		orgs = append(orgs, &org.UserOrgDTO{OrgID: testOrgID + 1, Role: org.RoleViewer})
+	}
+	return orgs, nil
+}
+
+func (f *fakeOrgService) AddOrgUser(_ context.Context, cmd *org.AddOrgUserCommand) error {
+	if _, ok := f.store.orgMembers[cmd.UserID]; ok {
+		return org.ErrOrgUserAlreadyAdded
+	}
+	f.store.orgMembers[cmd.UserID] = cmd.Role
+	return nil
+}
+
+func (f *fakeOrgService) RemoveOrgUser(_ context.Context, cmd *org.RemoveOrgUserCommand) error {
+	delete(f.store.orgMembers, cmd.UserID)
+	return nil
+}
+
+type fakeTeamService struct {
+	team.Service
+	store *fakeStore
+}
+
+func (f *fakeTeamService) CreateTeam(_ context.Context, cmd *team.CreateTeamCommand) (team.Team, error) {
+	for _, t := range f.store.teams {
+		if t.Name == cmd.Name {
+			return team.Team{}, team.ErrTeamNameTaken
+		}
+	}
+	f.store.nextID++
+	t := &team.TeamDTO{ID: f.store.nextID, UID: fmt.Sprintf("team-%d", f.store.nextID), OrgID: cmd.OrgID, Name: cmd.Name,
+		ExternalUID: cmd.ExternalUID, IsProvisioned: cmd.IsProvisioned}
+	f.store.teams[t.ID] = t
+	f.store.teamMembers[t.ID] = map[int64]bool{}
+	return team.Team{ID: t.ID, UID: t.UID, OrgID: t.OrgID, Name: t.Name}, nil
+}
+
+func (f *fakeTeamService) UpdateTeam(_ context.Context, cmd *team.UpdateTeamCommand) error {
+	t, ok := f.store.teams[cmd.ID]
+	if !ok {
+		return team.ErrTeamNotFound
+	}
+	t.Name, t.Email, t.ExternalUID = cmd.Name, cmd.Email, cmd.ExternalUID
+	return nil
+}
+
+func (f *fakeTeamService) DeleteTeam(_ context.Context, cmd *team.DeleteTeamCommand) error {
+	if _, ok := f.store.teams[cmd.ID]; !ok {
+		return team.ErrTeamNotFound
+	}
+	delete(f.store.teams, cmd.ID)
+	return nil
+}
+
+func (f *fakeTeamService) GetTeamByID(_ context.Context, q *team.GetTeamByIDQuery) (*team.TeamDTO, error) {
+	for _, t := range f.store.teams {
+		if t.UID == q.UID {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, team.ErrTeamNotFound
+}
+
+func (f *fakeTeamService) SearchTeams(_ context.Context, q *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	result := team.SearchTeamQueryResult{Teams: []*team.TeamDTO{}}
+	for _, t := range f.store.teams {
+		if q.Name == "" || q.Name == t.Name {
+			copied := *t
+			result.Teams = append(result.Teams, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeTeamService) GetTeamMembers(_ context.Context, q *team.GetTeamMembersQuery) ([]*team.TeamMemberDTO, error) {
+	members := make([]*team.TeamMemberDTO, 0)
+	for userID := range f.store.teamMembers[q.TeamID] {
+		u := f.store.users[userID]
+		members = append(members, &team.TeamMemberDTO{TeamID: q.TeamID, UserID: u.ID, UserUID: u.UID, Login: u.Login})
+	}
+	return members, nil
+}
+
+type fakeTeamPermissionsService struct {
+	accesscontrol.TeamPermissionsService
+	store *fakeStore
+}
+
+func (f *fakeTeamPermissionsService) SetUserPermission(_ context.Context, _ int64, u accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
+	var teamID int64
+	_, _ = fmt.Sscan(resourceID, &teamID)
+	if permission == "" {
+		delete(f.store.teamMembers[teamID], u.ID)
+	} else {
+		f.store.teamMembers[teamID][u.ID] = true
+	}
+	return &accesscontrol.ResourcePermission{}, nil
+}
+
+type fakeAuthInfoService struct {
+	login.AuthInfoService
+	store *fakeStore
+}
+
+func (f *fakeAuthInfoService) GetAuthInfo(_ context.Context, q *login.GetAuthInfoQuery) (*login.UserAuth, error) {
+	for userID, info := range f.store.authInfo {
+		if (q.UserId == 0 || q.UserId == userID) && (q.AuthId == "" || q.AuthId == info.AuthId) && q.AuthModule == info.AuthModule {
+			return info, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (f *fakeAuthInfoService) SetAuthInfo(_ context.Context, cmd *login.SetAuthInfoCommand) error {
+	f.store.authInfo[cmd.UserId] = &login.UserAuth{UserId: cmd.UserId, AuthModule: cmd.AuthModule, AuthId: cmd.AuthId, ExternalUID: cmd.ExternalUID}
+	return nil
+}
+
+func (f *fakeAuthInfoService) UpdateAuthInfo(_ context.Context, cmd *login.UpdateAuthInfoCommand) error {
+	f.store.authInfo[cmd.UserId] = &login.UserAuth{UserId: cmd.UserId, AuthModule: cmd.AuthModule, AuthId: cmd.AuthId, ExternalUID: cmd.ExternalUID}
+	return nil
+}
+
+type fakeTokenService struct {
+	auth.UserTokenService
+	store *fakeStore
+}
+
+func (f *fakeTokenService) RevokeAllUserTokens(_ context.Context, userID int64) error {
+	f.store.revoked = append(f.store.revoked, userID)
+	return nil
+}
+
+func setupTestServer(t *testing.T, store *fakeStore, userSync, groupSync bool) *webtest.Server {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.AutoAssignOrgRole = string(org.RoleViewer)
+	cfg.Raw.Section("auth.scim").Key("user_sync_enabled").SetValue(fmt.Sprint(userSync))
+	cfg.Raw.Section("auth.scim").Key("group_sync_enabled").SetValue(fmt.Sprint(groupSync))
+
+	router := routing.NewRouteRegister()
+	ProvideAPI(router, cfg, featuremgmt.WithFeatures(featuremgmt.FlagEnableSCIM),
+		acimpl.ProvideAccessControl(featuremgmt.WithFeatures()),
+		&fakeUserService{store: store},
+		&fakeOrgService{store: store},
+		&fakeTeamService{store: store},
+		&fakeTeamPermissionsService{store: store},
+		&fakeAuthInfoService{store: store},
+		&fakeTokenService{store: store},
+		setting.ProvideProvider(cfg),
+	)
+	return webtest.NewServer(t, router)
+}
+
+var provisionerPermissions = []accesscontrol.Permission{
+	{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	{Action: accesscontrol.ActionOrgUsersAdd, Scope: accesscontrol.ScopeUsersAll},
+	{Action: accesscontrol.ActionOrgUsersWrite, Scope: accesscontrol.ScopeUsersAll},
+	{Action: accesscontrol.ActionOrgUsersRemove, Scope: accesscontrol.ScopeUsersAll},
+	{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+	{Action: accesscontrol.ActionTeamsCreate},
+	{Action: accesscontrol.ActionTeamsWrite, Scope: accesscontrol.ScopeTeamsAll},
+	{Action: accesscontrol.ActionTeamsPermissionsWrite, Scope: accesscontrol.ScopeTeamsAll},
+	{Action: accesscontrol.ActionTeamsDelete, Scope: accesscontrol.ScopeTeamsAll},
+}
+
+func send(t *testing.T, server *webtest.Server, method, target, body string, permissions []accesscontrol.Permission, headers map[string]string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := server.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/scim+json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req = webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: testOrgID, OrgRole: org.RoleAdmin,
+		Permissions: map[int64]map[string][]string{testOrgID: accesscontrol.GroupScopesByActionContext(context.Background(), permissions)}})
+
+	res, err := server.Send(req)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, res.Body.Close()) }()
+
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	result := map[string]any{}
+	if len(data) > 0 {
+		require.NoError(t, json.Unmarshal(data, &result), string(data))
+	}
+	return res, result
+}
+
+func TestAPI_Users(t *testing.T) {
+	t.Run("should create a provisioned user in the org", func(t *testing.T) {
+		store := newFakeStore()
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodPost, "/api/scim/v2/Users", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "Alice",
+			"externalId": "ext-alice",
+			"name": {"givenName": "Alice", "familyName": "Smith"},
+			"emails": [{"value": "alice@example.org", "primary": true}],
+			"active": true
+		}`, provisionerPermissions, nil)
+		require.Equal(t, http.StatusCreated, res.StatusCode, body)
+
+		assert.Equal(t, "application/scim+json", res.Header.Get("Content-Type"))
+		assert.Equal(t, "http://localhost:3000/api/scim/v2/Users/"+body["id"].(string), res.Header.Get("Location"))
+		assert.NotEmpty(t, res.Header.Get("ETag"))
+		assert.Equal(t, "alice", body["userName"])
+		assert.Equal(t, "ext-alice", body["externalId"])
+		assert.Equal(t, "Alice Smith", body["displayName"])
+
+		created, err := (&fakeUserService{store: store}).GetByUID(context.Background(), &user.GetUserByUIDQuery{UID: body["id"].(string)})
+		require.NoError(t, err)
+		assert.True(t, created.IsProvisioned)
+		assert.Equal(t, org.RoleViewer, store.orgMembers[created.ID])
+		assert.Equal(t, login.SAMLAuthModule, store.authInfo[created.ID].AuthModule)
+
+		res, body = send(t, server, http.MethodPost, "/api/scim/v2/Users", `{"userName": "alice"}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, scimTypeUniqueness, body["scimType"])
+	})
+
+	t.Run("should link an existing user", func(t *testing.T) {
+		store := newFakeStore()
+		existing := store.addUser("bob", "bob@example.org", false)
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodPost, "/api/scim/v2/Users",
+			`{"userName": "bob", "emails": [{"value": "bob@example.org"}]}`, provisionerPermissions, nil)
+		require.Equal(t, http.StatusCreated, res.StatusCode, body)
+		assert.Equal(t, existing.UID, body["id"])
+		assert.True(t, store.users[existing.ID].IsProvisioned)
+		assert.Contains(t, store.orgMembers, existing.ID)
+
+		// linked users are managed by provisioning from now on
+		res, body = send(t, server, http.MethodPut, "/api/scim/v2/Users/"+existing.UID,
+			`{"userName": "robert", "emails": [{"value": "bob@example.org"}]}`, provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.Equal(t, existing.UID, body["id"])
+		assert.Equal(t, "robert", store.users[existing.ID].Login)
+	})
+
+	t.Run("should require the global users permission to change the login or email of users not created by provisioning", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		bob := store.addUser("bob", "bob@example.org", false)
+		server := setupTestServer(t, store, true, true)
+
+		// the org permissions of an org admin don't allow to take over accounts by changing their email
+		res, body := send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID,
+			`{"userName": "alice", "emails": [{"value": "mallory@example.org"}]}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+		res, body = send(t, server, http.MethodPatch, "/api/scim/v2/Users/"+alice.UID, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "Replace", "path": "userName", "value": "mallory"}]
+		}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+		res, body = send(t, server, http.MethodPost, "/api/scim/v2/Users",
+			`{"userName": "bob", "emails": [{"value": "mallory@example.org"}]}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+		assert.Equal(t, "alice@example.org", store.users[alice.ID].Email)
+		assert.Equal(t, "alice", store.users[alice.ID].Login)
+		assert.Equal(t, "bob@example.org", store.users[bob.ID].Email)
+		assert.False(t, store.users[bob.ID].IsProvisioned)
+
+		writer := append([]accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: accesscontrol.ScopeGlobalUsersAll}}, provisionerPermissions...)
+		res, body = send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID,
+			`{"userName": "alice", "emails": [{"value": "alice@example.com"}]}`, writer, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.Equal(t, "alice@example.com", store.users[alice.ID].Email)
+	})
+
+	t.Run("should filter users", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		store.addUser("bob", "bob@example.org", true)
+		store.addUser("carol", "carol@example.org", false)
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodGet, `/api/scim/v2/Users?filter=userName+eq+"ALICE"`, "", provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.EqualValues(t, 1, body["totalResults"])
+		assert.Equal(t, alice.UID, body["Resources"].([]any)[0].(map[string]any)["id"])
+
+		res, body = send(t, server, http.MethodGet, `/api/scim/v2/Users?filter=userName+eq+"carol"`, "", provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.EqualValues(t, 0, body["totalResults"])
+
+		res, body = send(t, server, http.MethodGet, `/api/scim/v2/Users?filter=emails.value+ew+"example.org"&count=1`, "", provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.EqualValues(t, 2, body["totalResults"])
+		assert.EqualValues(t, 1, body["itemsPerPage"])
+
+		res, body = send(t, server, http.MethodGet, `/api/scim/v2/Users?filter=userName+foo`, "", provisionerPermissions, nil)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, scimTypeInvalidFilter, body["scimType"])
+	})
+
+	t.Run("should patch a user and revoke sessions when deactivated", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		alice.IsProvisioned = true
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodPatch, "/api/scim/v2/Users/"+alice.UID, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "Replace", "path": "emails[type eq \"work\"].value", "value": "alice@example.com"},
+				{"op": "Replace", "path": "active", "value": "False"}
+			]
+		}`, provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+
+		assert.Equal(t, false, body["active"])
+		assert.Equal(t, "alice@example.com", store.users[alice.ID].Email)
+		assert.Equal(t, "alice", store.users[alice.ID].Name)
+		assert.True(t, store.users[alice.ID].IsDisabled)
+		assert.Equal(t, []int64{alice.ID}, store.revoked)
+	})
+
+	t.Run("should check ETags", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		server := setupTestServer(t, store, true, true)
+
+		res, _ := send(t, server, http.MethodGet, "/api/scim/v2/Users/"+alice.UID, "", provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		etag := res.Header.Get("ETag")
+		require.NotEmpty(t, etag)
+
+		res, _ = send(t, server, http.MethodGet, "/api/scim/v2/Users/"+alice.UID, "", provisionerPermissions,
+			map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+
+		res, body := send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID, `{"userName": "alice", "displayName": "Alice"}`,
+			provisionerPermissions, map[string]string{"If-Match": etag})
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.NotEqual(t, etag, res.Header.Get("ETag"))
+
+		res, _ = send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID, `{"userName": "alice", "displayName": "Al"}`,
+			provisionerPermissions, map[string]string{"If-Match": etag})
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+	})
+
+	t.Run("should deprovision a user on delete", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		server := setupTestServer(t, store, true, true)
+
+		res, _ := send(t, server, http.MethodDelete, "/api/scim/v2/Users/"+alice.UID, "", provisionerPermissions, nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		assert.True(t, store.users[alice.ID].IsDisabled)
+		assert.Equal(t, []int64{alice.ID}, store.revoked)
+		assert.NotContains(t, store.orgMembers, alice.ID)
+
+		res, _ = send(t, server, http.MethodGet, "/api/scim/v2/Users/"+alice.UID, "", provisionerPermissions, nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should reject requests when user sync is disabled", func(t *testing.T) {
+		server := setupTestServer(t, newFakeStore(), false, true)
+
+		res, body := send(t, server, http.MethodGet, "/api/scim/v2/Users", "", provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		assert.Equal(t, []any{SchemaError}, body["schemas"])
+	})
+
+	t.Run("should require the global users permissions to change shared users", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		store.otherOrgs[alice.ID] = true
+		admin := store.addUser("admin", "admin@example.org", false)
+		admin.IsAdmin = true
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID, `{"userName": "mallory"}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+		res, body = send(t, server, http.MethodDelete, "/api/scim/v2/Users/"+alice.UID, "", provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+		res, body = send(t, server, http.MethodPost, "/api/scim/v2/Users", `{"userName": "admin"}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+		assert.Equal(t, "alice", store.users[alice.ID].Login)
+		assert.False(t, store.users[alice.ID].IsDisabled)
+		assert.NotContains(t, store.orgMembers, admin.ID)
+
+		writer := append([]accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: accesscontrol.ScopeGlobalUsersAll}}, provisionerPermissions...)
+		res, body = send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID, `{"userName": "alice", "displayName": "Alice"}`, writer, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		res, body = send(t, server, http.MethodPut, "/api/scim/v2/Users/"+alice.UID, `{"userName": "alice", "active": false}`, writer, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, body)
+
+		disabler := append([]accesscontrol.Permission{{Action: accesscontrol.ActionUsersDisable, Scope: accesscontrol.ScopeGlobalUsersAll}}, provisionerPermissions...)
+		res, body = send(t, server, http.MethodDelete, "/api/scim/v2/Users/"+alice.UID, "", disabler, nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, body)
+		assert.True(t, store.users[alice.ID].IsDisabled)
+	})
+
+	t.Run("should require permissions", func(t *testing.T) {
+		server := setupTestServer(t, newFakeStore(), true, true)
+
+		res, _ := send(t, server, http.MethodPost, "/api/scim/v2/Users", `{"userName": "alice"}`,
+			[]accesscontrol.Permission{{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll}}, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func TestAPI_Groups(t *testing.T) {
+	t.Run("should create a group and sync members", func(t *testing.T) {
+		store := newFakeStore()
+		alice := store.addUser("alice", "alice@example.org", true)
+		bob := store.addUser("bob", "bob@example.org", true)
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodPost, "/api/scim/v2/Groups",
+			fmt.Sprintf(`{"displayName": "Engineering", "externalId": "ext-eng", "members": [{"value": %q}]}`, alice.UID),
+			provisionerPermissions, nil)
+		require.Equal(t, http.StatusCreated, res.StatusCode, body)
+		id := body["id"].(string)
+		assert.Len(t, body["members"], 1)
+
+		var teamID int64
+		for _, tm := range store.teams {
+			teamID = tm.ID
+			assert.True(t, tm.IsProvisioned)
+			assert.Equal(t, "ext-eng", tm.ExternalUID)
+		}
+		assert.Equal(t, map[int64]bool{alice.ID: true}, store.teamMembers[teamID])
+
+		res, body = send(t, server, http.MethodPatch, "/api/scim/v2/Groups/"+id, fmt.Sprintf(`{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "add", "path": "members", "value": [{"value": %q}]},
+				{"op": "remove", "path": "members[value eq \"%s\"]"},
+				{"op": "replace", "path": "displayName", "value": "Platform"}
+			]
+		}`, bob.UID, alice.UID), provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.Equal(t, "Platform", body["displayName"])
+		assert.Equal(t, map[int64]bool{bob.ID: true}, store.teamMembers[teamID])
+
+		res, body = send(t, server, http.MethodGet, `/api/scim/v2/Groups?filter=displayName+eq+"Platform"&excludedAttributes=members`,
+			"", provisionerPermissions, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, body)
+		assert.EqualValues(t, 1, body["totalResults"])
+		assert.NotContains(t, body["Resources"].([]any)[0], "members")
+
+		res, body = send(t, server, http.MethodPost, "/api/scim/v2/Groups", `{"displayName": "Platform"}`, provisionerPermissions, nil)
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, scimTypeUniqueness, body["scimType"])
+
+		res, _ = send(t, server, http.MethodDelete, "/api/scim/v2/Groups/"+id, "", provisionerPermissions, nil)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Empty(t, store.teams)
+	})
+
+	t.Run("should reject members outside of the org", func(t *testing.T) {
+		store := newFakeStore()
+		outsider := store.addUser("carol", "carol@example.org", false)
+		server := setupTestServer(t, store, true, true)
+
+		res, body := send(t, server, http.MethodPost, "/api/scim/v2/Groups",
+			fmt.Sprintf(`{"displayName": "Engineering", "members": [{"value": %q}]}`, outsider.UID), provisionerPermissions, nil)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, scimTypeInvalidValue, body["scimType"])
+		assert.Empty(t, store.teams)
+	})
+
+	t.Run("should reject requests when group sync is disabled", func(t *testing.T) {
+		server := setupTestServer(t, newFakeStore(), true, false)
+
+		res, _ := send(t, server, http.MethodGet, "/api/scim/v2/Groups", "", provisionerPermissions, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
@@ -0,0 +1,643 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// userSearchBatch is the page size used when a filter has to be evaluated against every org user
+const userSearchBatch = 1000
+
+func (api *API) getUser(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	usr, scimErr := api.findUser(ctx, c, web.Params(c.Req)[":id"])
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	resource, err := api.toUserResource(ctx, usr)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+	return resourceResponse(c, http.StatusOK, resource, resource.Meta, "")
+}
+
+func (api *API) listUsers(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	params, scimErr := parseListParams(c)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	users, err := api.userCandidates(ctx, c, params.filter)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search users", err)
+	}
+
+	resources := make([]any, 0, len(users))
+	for _, usr := range users {
+		resource, err := api.toUserResource(ctx, usr)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to search users", err)
+		}
+		if params.filter != nil {
+			m, err := toMap(resource)
+			if err != nil {
+				return response.Error(http.StatusInternalServerError, "Failed to search users", err)
+			}
+			if !params.filter.matches(m) {
+				continue
+			}
+		}
+		resources = append(resources, resource)
+	}
+	return scimResponse(http.StatusOK, params.page(resources))
+}
+
+// userCandidates returns the org users the filter has to be evaluated against. Equality filters
+// on unique attributes, which identity providers use to look up users before creating them,
+// are resolved with a single query.
+func (api *API) userCandidates(ctx context.Context, c *contextmodel.ReqContext, f filter) ([]*user.User, error) {
+	var usr *user.User
+	var err error
+	if v, ok := equalityValue(f, "userName"); ok {
+		usr, err = api.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: v})
+	} else if v, ok := equalityValue(f, "id"); ok {
+		usr, err = api.userService.GetByUID(ctx, &user.GetUserByUIDQuery{UID: v})
+	} else if v, ok := emailEqualityValue(f); ok {
+		usr, err = api.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: v})
+	} else if v, ok := equalityValue(f, "externalId"); ok {
+		var info *login.UserAuth
+		info, err = api.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{AuthModule: login.SAMLAuthModule, AuthId: v})
+		if err == nil {
+			usr, err = api.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: info.UserId})
+		}
+	} else {
+		return api.orgUsers(ctx, c)
+	}
+
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if usr.IsServiceAccount {
+		return nil, nil
+	}
+	member, err := api.isOrgMember(ctx, c, usr.ID)
+	if err != nil || !member {
+		return nil, err
+	}
+	return []*user.User{usr}, nil
+}
+
+func emailEqualityValue(f filter) (string, bool) {
+	if v, ok := equalityValue(f, "emails"); ok {
+		return v, true
+	}
+	return equalityValue(f, "emails.value")
+}
+
+func (api *API) orgUsers(ctx context.Context, c *contextmodel.ReqContext) ([]*user.User, error) {
+	users := make([]*user.User, 0)
+	for page := 1; ; page++ {
+		result, err := api.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+			OrgID: c.SignedInUser.GetOrgID(),
+			Limit: userSearchBatch,
+			Page:  page,
+			User:  c.SignedInUser,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ou := range result.OrgUsers {
+			users = append(users, &user.User{
+				ID:            ou.UserID,
+				UID:           ou.UID,
+				Login:         ou.Login,
+				Email:         ou.Email,
+				Name:          ou.Name,
+				IsDisabled:    ou.IsDisabled,
+				IsProvisioned: ou.IsProvisioned,
+				Created:       ou.Created,
+				Updated:       ou.Updated,
+			})
+		}
+		if len(result.OrgUsers) < userSearchBatch {
+			return users, nil
+		}
+	}
+}
+
+func (api *API) isOrgMember(ctx context.Context, c *contextmodel.ReqContext, userID int64) (bool, error) {
+	result, err := api.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+		OrgID:  c.SignedInUser.GetOrgID(),
+		UserID: userID,
+		Limit:  1,
+		User:   c.SignedInUser,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(result.OrgUsers) > 0, nil
+}
+
+// findUser returns the user with the SCIM id, users outside of the org are not found
+func (api *API) findUser(ctx context.Context, c *contextmodel.ReqContext, id string) (*user.User, *scimError) {
+	usr, err := api.userService.GetByUID(ctx, &user.GetUserByUIDQuery{UID: id})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errNotFound(ResourceTypeUser, id)
+		}
+		return nil, newError(http.StatusInternalServerError, "", "failed to get user")
+	}
+	if usr.IsServiceAccount {
+		return nil, errNotFound(ResourceTypeUser, id)
+	}
+
+	member, err := api.isOrgMember(ctx, c, usr.ID)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "", "failed to get user")
+	}
+	if !member {
+		return nil, errNotFound(ResourceTypeUser, id)
+	}
+	return usr, nil
+}
+
+func (api *API) toUserResource(ctx context.Context, usr *user.User) (*User, error) {
+	externalID, err := api.externalID(ctx, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := !usr.IsDisabled
+	resource := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          usr.UID,
+		ExternalID:  externalID,
+		UserName:    usr.Login,
+		DisplayName: usr.Name,
+		Active:      &active,
+	}
+	if usr.Name != "" {
+		resource.Name = &Name{Formatted: usr.Name}
+	}
+	if usr.Email != "" {
+		resource.Emails = []Email{{Value: usr.Email, Type: "work", Primary: true}}
+	}
+
+	created, updated := usr.Created, usr.Updated
+	resource.Meta = &Meta{
+		ResourceType: ResourceTypeUser,
+		Created:      &created,
+		LastModified: &updated,
+		Location:     api.location("Users", usr.UID),
+		Version:      version(resource),
+	}
+	return resource, nil
+}
+
+// externalID returns the id of the user at the identity provider, it is matched against
+// the SAML assertion of provisioned users when they log in
+func (api *API) externalID(ctx context.Context, userID int64) (string, error) {
+	info, err := api.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{UserId: userID, AuthModule: login.SAMLAuthModule})
+	if errors.Is(err, user.ErrUserNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return info.ExternalUID, nil
+}
+
+func (api *API) setExternalID(ctx context.Context, userID int64, externalID string) error {
+	current, err := api.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{UserId: userID, AuthModule: login.SAMLAuthModule})
+	if errors.Is(err, user.ErrUserNotFound) {
+		if externalID == "" {
+			return nil
+		}
+		return api.authInfoService.SetAuthInfo(ctx, &login.SetAuthInfoCommand{
+			UserId:      userID,
+			AuthModule:  login.SAMLAuthModule,
+			AuthId:      externalID,
+			ExternalUID: externalID,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if current.ExternalUID == externalID {
+		return nil
+	}
+	return api.authInfoService.UpdateAuthInfo(ctx, &login.UpdateAuthInfoCommand{
+		UserId:      userID,
+		AuthModule:  login.SAMLAuthModule,
+		AuthId:      externalID,
+		ExternalUID: externalID,
+	})
+}
+
+func (api *API) createUser(c *contextmodel.ReqContext) response.Response {
+	// provisioned users are created in the org of the requester
+	ctx := identity.WithRequester(c.Req.Context(), c.SignedInUser)
+
+	var resource User
+	if scimErr := decode(c, &resource); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	if resource.UserName == "" {
+		return errorResponse(newError(http.StatusBadRequest, scimTypeInvalidValue, "userName is required"))
+	}
+
+	usr, scimErr := api.existingUser(ctx, c, &resource)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	if usr == nil {
+		active := resource.Active == nil || *resource.Active
+		created, err := api.userService.Create(ctx, &user.CreateUserCommand{
+			Login:         resource.UserName,
+			Email:         primaryEmail(resource.Emails, ""),
+			Name:          displayName(&resource, ""),
+			IsDisabled:    !active,
+			IsProvisioned: true,
+			SkipOrgSetup:  true,
+			OrgID:         c.SignedInUser.GetOrgID(),
+		})
+		if err != nil {
+			if errors.Is(err, user.ErrUserAlreadyExists) {
+				return errorResponse(newError(http.StatusConflict, scimTypeUniqueness, "user %s already exists", resource.UserName))
+			}
+			return response.Error(http.StatusInternalServerError, "Failed to create user", err)
+		}
+		usr = created
+	} else {
+		// link the existing user, for example one that signed in before provisioning was enabled
+		if scimErr := api.checkUserChange(ctx, c, usr, &resource); scimErr != nil {
+			return errorResponse(scimErr)
+		}
+		if scimErr := api.updateUser(ctx, usr, &resource, true); scimErr != nil {
+			return errorResponse(scimErr)
+		}
+	}
+
+	if err := api.addOrgUser(ctx, c.SignedInUser.GetOrgID(), usr.ID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to add user to organization", err)
+	}
+	if err := api.setExternalID(ctx, usr.ID, resource.ExternalID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to set user external id", err)
+	}
+
+	return api.userResponse(c, http.StatusCreated, usr.UID)
+}
+
+// existingUser returns the user with the same login or email that a new provisioned user is linked to
+func (api *API) existingUser(ctx context.Context, c *contextmodel.ReqContext, resource *User) (*user.User, *scimError) {
+	usr, err := api.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: resource.UserName})
+	if errors.Is(err, user.ErrUserNotFound) {
+		if email := primaryEmail(resource.Emails, ""); email != "" {
+			usr, err = api.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: email})
+		}
+	}
+	if errors.Is(err, user.ErrUserNotFound) || (err == nil && usr == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "", "failed to get user")
+	}
+	if usr.IsServiceAccount {
+		return nil, newError(http.StatusConflict, scimTypeUniqueness, "user %s already exists", resource.UserName)
+	}
+
+	if usr.IsProvisioned {
+		member, err := api.isOrgMember(ctx, c, usr.ID)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "", "failed to get user")
+		}
+		if member {
+			return nil, newError(http.StatusConflict, scimTypeUniqueness, "user %s already exists", resource.UserName)
+		}
+	}
+	return usr, nil
+}
+
+func (api *API) addOrgUser(ctx context.Context, orgID, userID int64) error {
+	role := org.RoleType(api.cfg.AutoAssignOrgRole)
+	if !role.IsValid() {
+		role = org.RoleViewer
+	}
+	err := api.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: orgID, UserID: userID, Role: role})
+	if errors.Is(err, org.ErrOrgUserAlreadyAdded) {
+		return nil
+	}
+	return err
+}
+
+func (api *API) replaceUser(c *contextmodel.ReqContext) response.Response {
+	var resource User
+	if scimErr := decode(c, &resource); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	return api.modifyUser(c, func(*User) (*User, *scimError) {
+		return &resource, nil
+	})
+}
+
+func (api *API) patchUser(c *contextmodel.ReqContext) response.Response {
+	var patch PatchRequest
+	if scimErr := decode(c, &patch); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	return api.modifyUser(c, func(current *User) (*User, *scimError) {
+		// the name is derived from displayName and name, only the patched ones are applied
+		base := *current
+		base.DisplayName, base.Name, base.Meta = "", nil, nil
+
+		m, err := toMap(base)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "", "failed to patch user")
+		}
+		if err := applyPatch(m, patch.Operations); err != nil {
+			var scimErr *scimError
+			if errors.As(err, &scimErr) {
+				return nil, scimErr
+			}
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "%s", err)
+		}
+		normalizeActive(m)
+
+		var patched User
+		if scimErr := fromMap(m, &patched); scimErr != nil {
+			return nil, scimErr
+		}
+		return &patched, nil
+	})
+}
+
+// normalizeActive converts string booleans, Azure AD sends "False" to deactivate users
+func normalizeActive(m map[string]any) {
+	v, ok := lookup(m, "active")
+	if !ok {
+		return
+	}
+	if s, isString := v.(string); isString {
+		setKey(m, "active", strings.EqualFold(s, "true"))
+	}
+}
+
+// modifyUser replaces the user with the resource returned by modify
+func (api *API) modifyUser(c *contextmodel.ReqContext, modify func(current *User) (*User, *scimError)) response.Response {
+	ctx := c.Req.Context()
+	usr, scimErr := api.findUser(ctx, c, web.Params(c.Req)[":id"])
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	current, err := api.toUserResource(ctx, usr)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+	if scimErr := checkPrecondition(c, current.Meta.Version); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	resource, scimErr := modify(current)
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	if resource.UserName == "" {
+		return errorResponse(newError(http.StatusBadRequest, scimTypeInvalidValue, "userName is required"))
+	}
+	if resource.ID != "" && resource.ID != usr.UID {
+		return errorResponse(newError(http.StatusBadRequest, scimTypeInvalidValue, "id is immutable"))
+	}
+	if scimErr := api.checkUserChange(ctx, c, usr, resource); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	if scimErr := api.updateUser(ctx, usr, resource, false); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+	if err := api.setExternalID(ctx, usr.ID, resource.ExternalID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to set user external id", err)
+	}
+
+	return api.userResponse(c, http.StatusOK, usr.UID)
+}
+
+// checkUserChange checks that the requester can apply the resource to the user, see checkGlobalAccess.
+// Changing the login or email of a user that wasn't created by provisioning also requires the global
+// users permission: whoever controls the email of an account can reset its password and take it over.
+func (api *API) checkUserChange(ctx context.Context, c *contextmodel.ReqContext, usr *user.User, resource *User) *scimError {
+	if !usr.IsProvisioned && (resource.UserName != usr.Login || primaryEmail(resource.Emails, usr.Email) != usr.Email) {
+		ok, scimErr := api.hasGlobalPermission(ctx, c, usr, accesscontrol.ActionUsersWrite)
+		if scimErr != nil {
+			return scimErr
+		}
+		if !ok {
+			return newError(http.StatusForbidden, "", "user %s was not created by provisioning, changing its login or email requires the %s permission", usr.Login, accesscontrol.ActionUsersWrite)
+		}
+	}
+	if scimErr := api.checkGlobalAccess(ctx, c, usr, accesscontrol.ActionUsersWrite); scimErr != nil {
+		return scimErr
+	}
+	if resource.Active != nil && !*resource.Active && !usr.IsDisabled {
+		return api.checkGlobalAccess(ctx, c, usr, accesscontrol.ActionUsersDisable)
+	}
+	return nil
+}
+
+// checkGlobalAccess requires the global users permission for changes to Grafana server admins and users
+// that belong to other orgs, since users are shared by orgs and the changes apply to all of them.
+// The org permissions of the route only cover users that belong to the org of the requester alone.
+func (api *API) checkGlobalAccess(ctx context.Context, c *contextmodel.ReqContext, usr *user.User, action string) *scimError {
+	if !usr.IsAdmin {
+		orgs, err := api.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+		if err != nil {
+			return newError(http.StatusInternalServerError, "", "failed to get user orgs")
+		}
+		shared := false
+		for _, o := range orgs {
+			if o.OrgID != c.SignedInUser.GetOrgID() {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			return nil
+		}
+	}
+
+	ok, scimErr := api.hasGlobalPermission(ctx, c, usr, action)
+	if scimErr != nil {
+		return scimErr
+	}
+	if !ok {
+		return newError(http.StatusForbidden, "", "user %s is a server admin or belongs to other organizations, changing it requires the %s permission", usr.Login, action)
+	}
+	return nil
+}
+
+// hasGlobalPermission returns whether the requester has the global users permission on the user
+func (api *API) hasGlobalPermission(ctx context.Context, c *contextmodel.ReqContext, usr *user.User, action string) (bool, *scimError) {
+	scope := accesscontrol.Scope("global.users", "id", strconv.FormatInt(usr.ID, 10))
+	ok, err := api.accessControl.Evaluate(ctx, c.SignedInUser, accesscontrol.EvalPermission(action, scope))
+	if err != nil {
+		return false, newError(http.StatusInternalServerError, "", "failed to check permissions")
+	}
+	return ok, nil
+}
+
+// updateUser updates the user from the resource, deactivated users are disabled and signed out
+func (api *API) updateUser(ctx context.Context, usr *user.User, resource *User, provision bool) *scimError {
+	login := resource.UserName
+	email := primaryEmail(resource.Emails, usr.Email)
+	if scimErr := api.checkConflict(ctx, usr.ID, login, email); scimErr != nil {
+		return scimErr
+	}
+
+	cmd := &user.UpdateUserCommand{
+		UserID: usr.ID,
+		Login:  login,
+		Email:  email,
+		Name:   displayName(resource, usr.Name),
+	}
+	if resource.Active != nil {
+		disabled := !*resource.Active
+		cmd.IsDisabled = &disabled
+	}
+	if provision {
+		provisioned := true
+		cmd.IsProvisioned = &provisioned
+	}
+
+	if err := api.userService.Update(ctx, cmd); err != nil {
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return newError(http.StatusConflict, scimTypeUniqueness, "user %s already exists", login)
+		}
+		api.logger.Error("Failed to update user", "userID", usr.ID, "error", err)
+		return newError(http.StatusInternalServerError, "", "failed to update user")
+	}
+
+	if cmd.IsDisabled != nil && *cmd.IsDisabled && !usr.IsDisabled {
+		if err := api.authTokenService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+			api.logger.Error("Failed to revoke sessions of deactivated user", "userID", usr.ID, "error", err)
+			return newError(http.StatusInternalServerError, "", "failed to revoke user sessions")
+		}
+	}
+	return nil
+}
+
+// checkConflict rejects a login or email that belongs to another user
+func (api *API) checkConflict(ctx context.Context, userID int64, login, email string) *scimError {
+	for _, loginOrEmail := range []string{login, email} {
+		if loginOrEmail == "" {
+			continue
+		}
+		other, err := api.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+		if errors.Is(err, user.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return newError(http.StatusInternalServerError, "", "failed to get user")
+		}
+		if other.ID != userID {
+			return newError(http.StatusConflict, scimTypeUniqueness, "user %s already exists", loginOrEmail)
+		}
+	}
+	return nil
+}
+
+func (api *API) userResponse(c *contextmodel.ReqContext, status int, uid string) response.Response {
+	ctx := c.Req.Context()
+	usr, err := api.userService.GetByUID(ctx, &user.GetUserByUIDQuery{UID: uid})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+	resource, err := api.toUserResource(ctx, usr)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+	return resourceResponse(c, status, resource, resource.Meta, resource.Meta.Location)
+}
+
+// deleteUser deprovisions the user. The user is disabled, signed out and removed from the org
+// instead of deleted, so that resources owned by the user are kept.
+func (api *API) deleteUser(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	usr, scimErr := api.findUser(ctx, c, web.Params(c.Req)[":id"])
+	if scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	if c.Req.Header.Get("If-Match") != "" {
+		current, err := api.toUserResource(ctx, usr)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+		}
+		if scimErr := checkPrecondition(c, current.Meta.Version); scimErr != nil {
+			return errorResponse(scimErr)
+		}
+	}
+	if scimErr := api.checkGlobalAccess(ctx, c, usr, accesscontrol.ActionUsersDisable); scimErr != nil {
+		return errorResponse(scimErr)
+	}
+
+	disabled := true
+	if err := api.userService.Update(ctx, &user.UpdateUserCommand{UserID: usr.ID, IsDisabled: &disabled}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to disable user", err)
+	}
+	if err := api.authTokenService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to revoke user sessions", err)
+	}
+	if err := api.orgService.RemoveOrgUser(ctx, &org.RemoveOrgUserCommand{OrgID: c.SignedInUser.GetOrgID(), UserID: usr.ID}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to remove user from organization", err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// displayName derives the Grafana name of the user, fallback is kept when the resource has no name
+func displayName(resource *User, fallback string) string {
+	if resource.DisplayName != "" {
+		return resource.DisplayName
+	}
+	if resource.Name != nil {
+		if resource.Name.Formatted != "" {
+			return resource.Name.Formatted
+		}
+		if name := strings.TrimSpace(resource.Name.GivenName + " " + resource.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return fallback
+}
+
+func primaryEmail(emails []Email, fallback string) string {
+	for _, email := range emails {
+		if email.Primary && email.Value != "" {
+			return email.Value
+		}
+	}
+	for _, email := range emails {
+		if email.Value != "" {
+			return email.Value
+		}
+	}
+	return fallback
+}
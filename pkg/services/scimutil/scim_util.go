@@ -44,6 +44,25 @@ func (s *SCIMUtil) IsUserSyncEnabled(ctx context.Context, orgID int64, staticEna
 	return staticEnabled
 }
 
+// IsGroupSyncEnabled checks if SCIM group sync is enabled using dynamic configuration with static fallback
+func (s *SCIMUtil) IsGroupSyncEnabled(ctx context.Context, orgID int64, staticEnabled bool) bool {
+	if s.k8sClient == nil {
+		s.logger.Debug("K8s client not configured, using static SCIM config for group sync")
+		return staticEnabled
+	}
+
+	dynamicEnabled, dynamicConfigFetched := s.fetchDynamicSCIMSetting(ctx, orgID, "group")
+
+	if dynamicConfigFetched {
+		s.logger.Debug("Using dynamic SCIM config for group sync", "orgID", orgID, "enabled", dynamicEnabled)
+		return dynamicEnabled
+	}
+
+	// Fallback to static config if dynamic config wasn't fetched successfully
+	s.logger.Debug("Using static SCIM config for group sync", "orgID", orgID, "enabled", staticEnabled)
+	return staticEnabled
+}
+
 // AreNonProvisionedUsersRejected checks if non-provisioned users are rejected using dynamic configuration with static fallback
 func (s *SCIMUtil) AreNonProvisionedUsersRejected(ctx context.Context, orgID int64, staticRejected bool) bool {
 	if s.k8sClient == nil {
@@ -7,7 +7,6 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
-	"github.com/grafana/grafana/pkg/components/satokengen"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/web"
@@ -15,7 +14,6 @@ import (
 
 const (
 	failedToDeleteMsg = "Failed to delete service account token"
-	ServiceID         = "sa"
 )
 
 // swagger:model
@@ -36,6 +34,10 @@ type TokenDTO struct {
 	HasExpired bool `json:"hasExpired"`
 	// example: false
 	IsRevoked *bool `json:"isRevoked"`
+	// Permissions the token is restricted to, mapping actions to scopes.
+	Permissions map[string][]string `json:"permissions,omitempty"`
+	// Addresses the token can be used from.
+	AllowedCIDRs []string `json:"allowedCidrs,omitempty"`
 }
 
 func hasExpired(expiration *int64) bool {
@@ -104,6 +106,8 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *contextmodel.ReqContext) response
 			HasExpired:             isExpired,
 			LastUsedAt:             token.LastUsedAt,
 			IsRevoked:              token.IsRevoked,
+			Permissions:            token.Permissions,
+			AllowedCIDRs:           token.AllowedCIDRs,
 		}
 	}
 
@@ -131,14 +135,6 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
 	}
 
-	// confirm service account exists
-	if _, err = api.service.RetrieveServiceAccount(c.Req.Context(), &serviceaccounts.GetServiceAccountQuery{
-		OrgID: c.GetOrgID(),
-		ID:    saID,
-	}); err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to retrieve service account", err)
-	}
-
 	cmd := serviceaccounts.AddServiceAccountTokenCommand{}
 	if err = web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "Bad request data", err)
@@ -147,39 +143,15 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 	// Force affected service account to be the one referenced in the URL
 	cmd.OrgId = c.GetOrgID()
 
-	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
-		if cmd.SecondsToLive == 0 {
-			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
-		}
-		if cmd.SecondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
-			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
-		}
-	}
-
-	if api.cfg.SATokenExpirationDayLimit > 0 {
-		dayExpireLimit := time.Now().Add(time.Duration(api.cfg.SATokenExpirationDayLimit) * time.Hour * 24).Truncate(24 * time.Hour)
-		expirationDate := time.Now().Add(time.Duration(cmd.SecondsToLive) * time.Second).Truncate(24 * time.Hour)
-		if expirationDate.After(dayExpireLimit) {
-			return response.Respond(http.StatusBadRequest, "The expiration date input exceeds the limit for service account access tokens expiration date")
-		}
-	}
-
-	newKeyInfo, err := satokengen.New(ServiceID)
-	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Generating service account token failed", err)
-	}
-
-	cmd.Key = newKeyInfo.HashedKey
-
-	apiKey, err := api.service.AddServiceAccountToken(c.Req.Context(), saID, &cmd)
+	token, err := api.service.CreateServiceAccountToken(c.Req.Context(), saID, &cmd)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to add service account token", err)
 	}
 
 	result := &dtos.NewApiKeyResult{
-		ID:   apiKey.ID,
-		Name: apiKey.Name,
-		Key:  newKeyInfo.ClientSecret,
+		ID:   token.ID,
+		Name: token.Name,
+		Key:  token.Secret,
 	}
 
 	return response.JSON(http.StatusOK, result)
@@ -2,17 +2,17 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
-	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	satests "github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -60,31 +60,28 @@ func TestServiceAccountsAPI_ListTokens(t *testing.T) {
 
 func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 	type TestCase struct {
-		desc           string
-		id             int64
-		body           string
-		permissions    []accesscontrol.Permission
-		tokenTTL       int64
-		expectedErr    error
-		expectedAPIKey *apikey.APIKey
-		expectedCode   int
+		desc          string
+		id            int64
+		body          string
+		permissions   []accesscontrol.Permission
+		expectedErr   error
+		expectedToken *serviceaccounts.NewServiceAccountToken
+		expectedCode  int
 	}
 
 	tests := []TestCase{
 		{
-			desc:           "should be able to create token for service account with correct permission",
-			id:             1,
-			body:           `{"name": "test"}`,
-			tokenTTL:       -1,
-			permissions:    []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
-			expectedAPIKey: &apikey.APIKey{},
-			expectedCode:   http.StatusOK,
+			desc:          "should be able to create token for service account with correct permission",
+			id:            1,
+			body:          `{"name": "test"}`,
+			permissions:   []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedToken: &serviceaccounts.NewServiceAccountToken{ID: 1, Name: "test", Secret: "glsa_secret"},
+			expectedCode:  http.StatusOK,
 		},
 		{
 			desc:         "should not be able to create token for service account with wrong permission",
 			id:           2,
 			body:         `{"name": "test"}`,
-			tokenTTL:     -1,
 			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
 			expectedCode: http.StatusForbidden,
 		},
@@ -92,7 +89,6 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			desc:         "should not be able to create token for service account that dont exists",
 			id:           1,
 			body:         `{"name": "test"}`,
-			tokenTTL:     -1,
 			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
 			expectedErr:  serviceaccounts.ErrServiceAccountNotFound.Errorf(""),
 			expectedCode: http.StatusNotFound,
@@ -101,8 +97,16 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			desc:         "should not be able to create token for service account if max ttl is configured but not set in body",
 			id:           1,
 			body:         `{"name": "test"}`,
-			tokenTTL:     10 * int64(time.Hour),
 			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedErr:  serviceaccounts.ErrTokenExpirationRequired.Errorf(""),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not be able to create token with invalid restrictions",
+			id:           1,
+			body:         `{"name": "test", "allowedCidrs": ["10.0.0.0/33"]}`,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedErr:  serviceaccounts.ErrInvalidTokenAllowedCIDR.Errorf(""),
 			expectedCode: http.StatusBadRequest,
 		},
 	}
@@ -110,10 +114,9 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			server := setupTests(t, func(a *ServiceAccountsAPI) {
-				a.cfg.ApiKeyMaxSecondsToLive = tt.tokenTTL
 				a.service = &satests.FakeServiceAccountService{
-					ExpectedErr:    tt.expectedErr,
-					ExpectedAPIKey: tt.expectedAPIKey,
+					ExpectedErr:                 tt.expectedErr,
+					ExpectedServiceAccountToken: tt.expectedToken,
 				}
 			})
 			req := server.NewRequest(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens", tt.id), strings.NewReader(tt.body))
@@ -122,6 +125,11 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			require.NoError(t, err)
 
 			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			if tt.expectedToken != nil {
+				result := dtos.NewApiKeyResult{}
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+				assert.Equal(t, tt.expectedToken.Secret, result.Key)
+			}
 			require.NoError(t, res.Body.Close())
 		})
 	}
@@ -99,6 +99,39 @@ func TestIntegration_Store_AddServiceAccountToken_WrongServiceAccount(t *testing
 	require.Error(t, err, "It should not be possible to add token to non-existing service account")
 }
 
+func TestIntegration_Store_AddServiceAccountToken_Restricted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, store.cfg, saToCreate)
+
+	keyName := t.Name()
+	key, err := apikeygen.New(sa.OrgID, keyName)
+	require.NoError(t, err)
+
+	cmd := serviceaccounts.AddServiceAccountTokenCommand{
+		Name:         keyName,
+		OrgId:        sa.OrgID,
+		Key:          key.HashedKey,
+		Permissions:  map[string][]string{"dashboards:read": {"folders:uid:ci"}},
+		AllowedCIDRs: []string{"10.0.0.0/8"},
+	}
+
+	_, err = store.AddServiceAccountToken(context.Background(), sa.ID, &cmd)
+	require.NoError(t, err)
+
+	keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{
+		OrgID:            &sa.OrgID,
+		ServiceAccountID: &sa.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, cmd.Permissions, keys[0].Permissions)
+	require.Equal(t, cmd.AllowedCIDRs, keys[0].AllowedCIDRs)
+}
+
 func TestIntegration_Store_RevokeServiceAccountToken(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
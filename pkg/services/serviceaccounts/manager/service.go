@@ -10,6 +10,9 @@ import (
 	claims "github.com/grafana/authlib/types"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/satokengen"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -17,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/database"
@@ -26,8 +30,9 @@ import (
 )
 
 const (
-	metricsCollectionInterval = time.Minute * 30
-	defaultSecretScanInterval = time.Minute * 5
+	metricsCollectionInterval  = time.Minute * 30
+	defaultSecretScanInterval  = time.Minute * 5
+	defaultContentScanInterval = time.Hour
 )
 
 type ServiceAccountsService struct {
@@ -40,11 +45,15 @@ type ServiceAccountsService struct {
 	log               log.Logger
 	backgroundLog     log.Logger
 	secretScanService secretscan.Checker
+	contentScanner    secretscan.ContentScanner
 	orgService        org.Service
 	serverLock        *serverlock.ServerLockService
+	bus               bus.Bus
 
-	secretScanEnabled  bool
-	secretScanInterval time.Duration
+	secretScanEnabled   bool
+	secretScanInterval  time.Duration
+	contentScanEnabled  bool
+	contentScanInterval time.Duration
 }
 
 func ProvideServiceAccountsService(
@@ -58,6 +67,8 @@ func ProvideServiceAccountsService(
 	acService accesscontrol.Service,
 	permissions accesscontrol.ServiceAccountPermissionsService,
 	serverLockService *serverlock.ServerLockService,
+	emailSender notifications.EmailSender,
+	bus bus.Bus,
 ) (*ServiceAccountsService, error) {
 	serviceAccountsStore := database.ProvideServiceAccountsStore(
 		cfg,
@@ -77,6 +88,7 @@ func ProvideServiceAccountsService(
 		backgroundLog: log.New("serviceaccounts.background"),
 		orgService:    orgService,
 		serverLock:    serverLockService,
+		bus:           bus,
 	}
 
 	if err := RegisterRoles(acService); err != nil {
@@ -90,7 +102,7 @@ func ProvideServiceAccountsService(
 		Key("interval").MustDuration(defaultSecretScanInterval)
 	if s.secretScanEnabled {
 		var errSecret error
-		s.secretScanService, errSecret = secretscan.NewService(s.store, cfg)
+		s.secretScanService, errSecret = secretscan.NewService(s.store, cfg, bus)
 		if errSecret != nil {
 			s.secretScanEnabled = false
 			s.log.Warn("Failed to initialize secret scan service. secret scan is disabled",
@@ -98,6 +110,19 @@ func ProvideServiceAccountsService(
 		}
 	}
 
+	s.contentScanEnabled = cfg.SectionWithEnvOverrides("secretscan").Key("content_scan_enabled").MustBool(false)
+	s.contentScanInterval = cfg.SectionWithEnvOverrides("secretscan").
+		Key("content_scan_interval").MustDuration(defaultContentScanInterval)
+	if s.contentScanEnabled {
+		var errScan error
+		s.contentScanner, errScan = secretscan.NewContentScanService(store, apiKeyService, serviceAccountsStore, emailSender, cfg)
+		if errScan != nil {
+			s.contentScanEnabled = false
+			s.log.Warn("Failed to initialize content scan service. content scan is disabled",
+				"error", errScan.Error())
+		}
+	}
+
 	return s, nil
 }
 
@@ -143,6 +168,22 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 		defer tokenCheckTicker.Stop()
 	}
 
+	// A nil channel never fires, so the content scan only runs when enabled.
+	var contentScan <-chan time.Time
+	if sa.contentScanEnabled {
+		// Enforce a minimum interval of 1 minute.
+		if sa.contentScanInterval < time.Minute {
+			sa.backgroundLog.Warn("Content scan interval is too low, increasing to " +
+				defaultContentScanInterval.String())
+
+			sa.contentScanInterval = defaultContentScanInterval
+		}
+
+		contentScanTicker := time.NewTicker(sa.contentScanInterval)
+		defer contentScanTicker.Stop()
+		contentScan = contentScanTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -165,6 +206,17 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 			if err := sa.secretScanService.CheckTokens(ctx); err != nil {
 				sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
 			}
+		case <-contentScan:
+			sa.backgroundLog.Debug("Scanning stored content for leaked tokens")
+
+			err := sa.serverLock.LockAndExecute(ctx, "scan stored content for leaked tokens", sa.contentScanInterval, func(ctx context.Context) {
+				if err := sa.contentScanner.ScanContent(ctx); err != nil {
+					sa.backgroundLog.Warn("Failed to scan stored content for leaked tokens", "error", err.Error())
+				}
+			})
+			if err != nil {
+				sa.backgroundLog.Error("Failed to lock and execute the scan of stored content", "error", err)
+			}
 		}
 	}
 }
@@ -288,6 +340,52 @@ func (sa *ServiceAccountsService) AddServiceAccountToken(ctx context.Context, se
 	return sa.store.AddServiceAccountToken(ctx, serviceAccountID, query)
 }
 
+func (sa *ServiceAccountsService) CreateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*serviceaccounts.NewServiceAccountToken, error) {
+	if _, err := sa.RetrieveServiceAccount(ctx, &serviceaccounts.GetServiceAccountQuery{OrgID: cmd.OrgId, ID: serviceAccountID}); err != nil {
+		return nil, err
+	}
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	if err := sa.validateTokenExpiration(cmd.SecondsToLive); err != nil {
+		return nil, err
+	}
+
+	key, err := satokengen.New(serviceaccounts.TokenServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate service account token: %w", err)
+	}
+	cmd.Key = key.HashedKey
+
+	token, err := sa.AddServiceAccountToken(ctx, serviceAccountID, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &serviceaccounts.NewServiceAccountToken{ID: token.ID, Name: token.Name, Secret: key.ClientSecret}, nil
+}
+
+// validateTokenExpiration checks the number of seconds before a token expires against the maximum lifetime of
+// tokens, and the day after which tokens can't expire.
+func (sa *ServiceAccountsService) validateTokenExpiration(secondsToLive int64) error {
+	if sa.cfg.ApiKeyMaxSecondsToLive != -1 {
+		if secondsToLive == 0 {
+			return serviceaccounts.ErrTokenExpirationRequired.Errorf("tokens must expire")
+		}
+		if secondsToLive > sa.cfg.ApiKeyMaxSecondsToLive {
+			return serviceaccounts.ErrTokenExpirationTooLong.Errorf("tokens can live at most %d seconds", sa.cfg.ApiKeyMaxSecondsToLive)
+		}
+	}
+
+	if sa.cfg.SATokenExpirationDayLimit > 0 {
+		dayExpireLimit := time.Now().Add(time.Duration(sa.cfg.SATokenExpirationDayLimit) * time.Hour * 24).Truncate(24 * time.Hour)
+		expirationDate := time.Now().Add(time.Duration(secondsToLive) * time.Second).Truncate(24 * time.Hour)
+		if expirationDate.After(dayExpireLimit) {
+			return serviceaccounts.ErrTokenExpirationDateLimit.Errorf("tokens must expire within %d days", sa.cfg.SATokenExpirationDayLimit)
+		}
+	}
+	return nil
+}
+
 func (sa *ServiceAccountsService) DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID int64, tokenID int64) error {
 	if err := validOrgID(orgID); err != nil {
 		return err
@@ -298,7 +396,26 @@ func (sa *ServiceAccountsService) DeleteServiceAccountToken(ctx context.Context,
 	if err := validServiceAccountTokenID(tokenID); err != nil {
 		return err
 	}
-	return sa.store.DeleteServiceAccountToken(ctx, orgID, serviceAccountID, tokenID)
+	if err := sa.store.DeleteServiceAccountToken(ctx, orgID, serviceAccountID, tokenID); err != nil {
+		return err
+	}
+
+	if sa.bus != nil {
+		var revokedBy string
+		if requester, err := identity.GetRequester(ctx); err == nil {
+			revokedBy = requester.GetLogin()
+		}
+		if err := sa.bus.Publish(ctx, &events.UserTokenRevoked{
+			Timestamp: time.Now(),
+			Kind:      events.UserTokenRevokedServiceAccountToken,
+			UserID:    serviceAccountID,
+			TokenID:   tokenID,
+			RevokedBy: revokedBy,
+		}); err != nil {
+			sa.log.Warn("Failed to publish service account token revoked event", "error", err)
+		}
+	}
+	return nil
 }
 
 func (sa *ServiceAccountsService) MigrateApiKeysToServiceAccounts(ctx context.Context, orgID int64) (*serviceaccounts.MigrationResult, error) {
@@ -2,16 +2,23 @@ package manager
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
 )
 
@@ -158,3 +165,86 @@ func TestIntegrationProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestServiceAccountsService_CreateServiceAccountToken(t *testing.T) {
+	newService := func(cfg *setting.Cfg) (*ServiceAccountsService, *FakeServiceAccountStore) {
+		storeMock := newServiceAccountStoreFake()
+		storeMock.ExpectedServiceAccountProfileDTO = &serviceaccounts.ServiceAccountProfileDTO{Id: 1}
+		storeMock.ExpectedAPIKey = &apikey.APIKey{ID: 2, Name: "test"}
+		return &ServiceAccountsService{cfg: cfg, store: storeMock, log: log.NewNopLogger()}, storeMock
+	}
+	newCfg := func(maxSecondsToLive int64, dayLimit int) *setting.Cfg {
+		cfg := setting.NewCfg()
+		cfg.ApiKeyMaxSecondsToLive = maxSecondsToLive
+		cfg.SATokenExpirationDayLimit = dayLimit
+		return cfg
+	}
+
+	t.Run("should create a token with a key of the service account tokens", func(t *testing.T) {
+		svc, _ := newService(newCfg(-1, 0))
+		cmd := &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1}
+		token, err := svc.CreateServiceAccountToken(context.Background(), 1, cmd)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), token.ID)
+		require.True(t, strings.HasPrefix(token.Secret, "glsa_"))
+		require.NotEmpty(t, cmd.Key)
+	})
+
+	t.Run("should not create a token for a service account that doesn't exist", func(t *testing.T) {
+		svc, storeMock := newService(newCfg(-1, 0))
+		storeMock.ExpectedError = serviceaccounts.ErrServiceAccountNotFound.Errorf("not found")
+		_, err := svc.CreateServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1})
+		require.ErrorIs(t, err, serviceaccounts.ErrServiceAccountNotFound)
+	})
+
+	t.Run("should validate the restrictions of the token", func(t *testing.T) {
+		svc, _ := newService(newCfg(-1, 0))
+		_, err := svc.CreateServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1, AllowedCIDRs: []string{"10.0.0.0/33"}})
+		require.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenAllowedCIDR)
+	})
+
+	t.Run("should require an expiration within the maximum lifetime", func(t *testing.T) {
+		svc, _ := newService(newCfg(3600, 0))
+		_, err := svc.CreateServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1})
+		require.ErrorIs(t, err, serviceaccounts.ErrTokenExpirationRequired)
+
+		_, err = svc.CreateServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1, SecondsToLive: 7200})
+		require.ErrorIs(t, err, serviceaccounts.ErrTokenExpirationTooLong)
+	})
+
+	t.Run("should limit the expiration date of the token", func(t *testing.T) {
+		svc, _ := newService(newCfg(-1, 1))
+		_, err := svc.CreateServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1, SecondsToLive: 7 * 24 * 3600})
+		require.ErrorIs(t, err, serviceaccounts.ErrTokenExpirationDateLimit)
+
+		_, err = svc.CreateServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "test", OrgId: 1})
+		require.NoError(t, err)
+	})
+}
+
+func TestServiceAccountsService_DeleteServiceAccountToken(t *testing.T) {
+	revoked := []*events.UserTokenRevoked{}
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	b.AddEventListener(func(ctx context.Context, evt *events.UserTokenRevoked) error {
+		revoked = append(revoked, evt)
+		return nil
+	})
+	storeMock := newServiceAccountStoreFake()
+	svc := &ServiceAccountsService{store: storeMock, log: log.NewNopLogger(), bus: b}
+
+	t.Run("should publish the revocation of the token", func(t *testing.T) {
+		ctx := identity.WithRequester(context.Background(), &user.SignedInUser{Login: "admin"})
+		require.NoError(t, svc.DeleteServiceAccountToken(ctx, 1, 2, 3))
+		require.Len(t, revoked, 1)
+		require.Equal(t, events.UserTokenRevokedServiceAccountToken, revoked[0].Kind)
+		require.Equal(t, int64(2), revoked[0].UserID)
+		require.Equal(t, int64(3), revoked[0].TokenID)
+		require.Equal(t, "admin", revoked[0].RevokedBy)
+	})
+
+	t.Run("should not publish when the token can't be deleted", func(t *testing.T) {
+		storeMock.ExpectedError = serviceaccounts.ErrServiceAccountTokenNotFound
+		require.Error(t, svc.DeleteServiceAccountToken(context.Background(), 1, 2, 4))
+		require.Len(t, revoked, 1)
+	})
+}
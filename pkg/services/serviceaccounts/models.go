@@ -2,6 +2,7 @@ package serviceaccounts
 
 import (
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -19,6 +20,8 @@ var (
 const (
 	ServiceAccountPrefix = "sa-"
 	ExtSvcPrefix         = "extsvc-"
+	// TokenServiceID is the service the keys of service account tokens are generated for
+	TokenServiceID = "sa"
 )
 
 const (
@@ -42,6 +45,11 @@ var (
 	ErrServiceAccountTokenNotFound       = errutil.NotFound("serviceaccounts.ErrTokenNotFound", errutil.WithPublicMessage("service account token not found"))
 	ErrInvalidTokenExpiration            = errutil.ValidationFailed("serviceaccounts.ErrInvalidInput", errutil.WithPublicMessage("invalid SecondsToLive value"))
 	ErrDuplicateToken                    = errutil.BadRequest("serviceaccounts.ErrTokenAlreadyExists", errutil.WithPublicMessage("service account token with given name already exists in the organization"))
+	ErrInvalidTokenPermissions           = errutil.ValidationFailed("serviceaccounts.ErrInvalidTokenPermissions", errutil.WithPublicMessage("invalid service account token permissions"))
+	ErrInvalidTokenAllowedCIDR           = errutil.ValidationFailed("serviceaccounts.ErrInvalidTokenAllowedCIDR", errutil.WithPublicMessage("invalid service account token allowed address"))
+	ErrTokenExpirationRequired           = errutil.BadRequest("serviceaccounts.ErrTokenExpirationRequired", errutil.WithPublicMessage("Number of seconds before expiration should be set"))
+	ErrTokenExpirationTooLong            = errutil.BadRequest("serviceaccounts.ErrTokenExpirationTooLong", errutil.WithPublicMessage("Number of seconds before expiration is greater than the global limit"))
+	ErrTokenExpirationDateLimit          = errutil.BadRequest("serviceaccounts.ErrTokenExpirationDateLimit", errutil.WithPublicMessage("The expiration date input exceeds the limit for service account access tokens expiration date"))
 )
 
 type MigrationResult struct {
@@ -111,6 +119,44 @@ type AddServiceAccountTokenCommand struct {
 	OrgId         int64  `json:"-"`
 	Key           string `json:"-"`
 	SecondsToLive int64  `json:"secondsToLive"`
+	// Permissions restricts the token to a subset of the service account's permissions,
+	// mapping actions to scopes. An action without scopes keeps all the scopes granted
+	// to the service account.
+	Permissions map[string][]string `json:"permissions,omitempty"`
+	// AllowedCIDRs restricts the addresses the token can be used from.
+	AllowedCIDRs []string `json:"allowedCidrs,omitempty"`
+}
+
+func (cmd *AddServiceAccountTokenCommand) Validate() error {
+	for action, scopes := range cmd.Permissions {
+		if strings.TrimSpace(action) == "" {
+			return ErrInvalidTokenPermissions.Errorf("empty action")
+		}
+		for _, scope := range scopes {
+			if scope == "" || !accesscontrol.ValidateScope(scope) {
+				return ErrInvalidTokenPermissions.Errorf("invalid scope %q for action %s", scope, action)
+			}
+		}
+	}
+
+	for _, cidr := range cmd.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err == nil {
+			continue
+		}
+		if net.ParseIP(cidr) == nil {
+			return ErrInvalidTokenAllowedCIDR.Errorf("invalid address %q", cidr)
+		}
+	}
+
+	return nil
+}
+
+// NewServiceAccountToken is a token created for a service account, with the secret of its key. The secret is
+// only available when the token is created.
+type NewServiceAccountToken struct {
+	ID     int64
+	Name   string
+	Secret string
 }
 
 type SearchOrgServiceAccountsQuery struct {
@@ -50,21 +50,39 @@ func ProvideServiceAccountsProxy(
 var _ serviceaccounts.Service = (*ServiceAccountsProxy)(nil)
 
 func (s *ServiceAccountsProxy) AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*apikey.APIKey, error) {
-	if s.isProxyEnabled {
-		sa, err := s.proxiedService.RetrieveServiceAccount(ctx, &serviceaccounts.GetServiceAccountQuery{ID: serviceAccountID, OrgID: cmd.OrgId})
-		if err != nil {
-			return nil, err
-		}
-
-		if serviceaccounts.IsExternalServiceAccount(sa.Login) {
-			s.log.Error("unable to create tokens for external service accounts", "serviceAccountID", serviceAccountID)
-			return nil, extsvcaccounts.ErrCannotCreateToken
-		}
+	if err := s.checkCanCreateToken(ctx, serviceAccountID, cmd.OrgId); err != nil {
+		return nil, err
 	}
 
 	return s.proxiedService.AddServiceAccountToken(ctx, serviceAccountID, cmd)
 }
 
+func (s *ServiceAccountsProxy) CreateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*serviceaccounts.NewServiceAccountToken, error) {
+	if err := s.checkCanCreateToken(ctx, serviceAccountID, cmd.OrgId); err != nil {
+		return nil, err
+	}
+
+	return s.proxiedService.CreateServiceAccountToken(ctx, serviceAccountID, cmd)
+}
+
+// checkCanCreateToken prevents creating tokens for external service accounts, which are managed by their service.
+func (s *ServiceAccountsProxy) checkCanCreateToken(ctx context.Context, serviceAccountID, orgID int64) error {
+	if !s.isProxyEnabled {
+		return nil
+	}
+
+	sa, err := s.proxiedService.RetrieveServiceAccount(ctx, &serviceaccounts.GetServiceAccountQuery{ID: serviceAccountID, OrgID: orgID})
+	if err != nil {
+		return err
+	}
+
+	if serviceaccounts.IsExternalServiceAccount(sa.Login) {
+		s.log.Error("unable to create tokens for external service accounts", "serviceAccountID", serviceAccountID)
+		return extsvcaccounts.ErrCannotCreateToken
+	}
+	return nil
+}
+
 func (s *ServiceAccountsProxy) CreateServiceAccount(ctx context.Context, orgID int64, saForm *serviceaccounts.CreateServiceAccountForm) (*serviceaccounts.ServiceAccountDTO, error) {
 	if s.isProxyEnabled {
 		if !isNameValid(saForm.Name) {
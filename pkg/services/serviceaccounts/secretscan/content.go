@@ -0,0 +1,309 @@
+package secretscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/components/satokengen"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	contentScanBatchSize = 500
+
+	tokenTypeServiceAccount = "grafana_service_account_token"
+	tokenTypeAPIKey         = "grafana_api_key"
+
+	tmplTokenLeaked = "token_leaked"
+)
+
+var (
+	// serviceAccountTokenPattern matches tokens generated by satokengen, e.g. glsa_<secret>_<checksum>.
+	serviceAccountTokenPattern = regexp.MustCompile(`gl[a-z]+_[A-Za-z0-9]{32}_[0-9a-f]{8}`)
+	// apiKeyPattern matches legacy API keys, base64 encoded JSON documents starting with {"k":".
+	apiKeyPattern = regexp.MustCompile(`eyJrIjoi[A-Za-z0-9+/]+={0,2}`)
+)
+
+type ContentScanner interface {
+	ScanContent(ctx context.Context) error
+}
+
+type KeyRetriever interface {
+	GetApiKeyByName(ctx context.Context, query *apikey.GetByNameQuery) (*apikey.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*apikey.APIKey, error)
+}
+
+// contentSource describes a table holding user provided content.
+type contentSource struct {
+	name  string
+	table string
+	// ref is the column used to point to the row in logs and notifications.
+	ref     string
+	columns []string
+}
+
+var contentSources = []contentSource{
+	{name: "dashboard", table: "dashboard", ref: "uid", columns: []string{"data"}},
+	{name: "library_element", table: "library_element", ref: "uid", columns: []string{"description", "model"}},
+	{name: "annotation", table: "annotation", ref: "id", columns: []string{"text"}},
+	{name: "query_history", table: "query_history", ref: "uid", columns: []string{"comment", "queries"}},
+}
+
+// leak is a token found in stored content that belongs to this instance.
+type leak struct {
+	key       *apikey.APIKey
+	tokenType string
+	locations []string
+}
+
+// ContentScanService looks for Grafana tokens that were pasted into content stored in the database,
+// such as dashboards, library elements, annotations and query history. Matches are verified against
+// the token store so that only tokens issued by this instance are reported.
+type ContentScanService struct {
+	db            db.DB
+	keys          KeyRetriever
+	store         SATokenRetriever
+	webHookClient WebHookClient
+	emailSender   notifications.EmailSender
+	logger        log.Logger
+	webHookNotify bool
+	notifyOwners  bool
+	revoke        bool
+	batchSize     int
+}
+
+func NewContentScanService(sqlStore db.DB, keys KeyRetriever, store SATokenRetriever, emailSender notifications.EmailSender, cfg *setting.Cfg) (*ContentScanService, error) {
+	revoke := cfg.SectionWithEnvOverrides("secretscan").Key("revoke").MustBool(true)
+	notifyOwners := cfg.SectionWithEnvOverrides("secretscan").Key("content_scan_notify_owners").MustBool(true)
+
+	webHookClient, err := webHookClientFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContentScanService{
+		db:            sqlStore,
+		keys:          keys,
+		store:         store,
+		webHookClient: webHookClient,
+		emailSender:   emailSender,
+		logger:        log.New("secretscan.content"),
+		webHookNotify: webHookClient != nil,
+		notifyOwners:  notifyOwners && emailSender != nil,
+		revoke:        revoke,
+		batchSize:     contentScanBatchSize,
+	}, nil
+}
+
+// ScanContent walks the stored content looking for tokens issued by this instance.
+// Leaked tokens are revoked and reported through the webhook and to their owners when configured.
+func (s *ContentScanService) ScanContent(ctx context.Context) error {
+	leaks := make(map[int64]*leak)
+	for _, source := range contentSources {
+		if err := s.scanSource(ctx, source, leaks); err != nil {
+			return fmt.Errorf("failed to scan %s content: %w", source.name, err)
+		}
+	}
+
+	for _, l := range leaks {
+		s.handleLeak(ctx, l)
+	}
+
+	return nil
+}
+
+func (s *ContentScanService) scanSource(ctx context.Context, source contentSource, leaks map[int64]*leak) error {
+	rawSQL := "SELECT id, org_id, " + source.ref + " AS ref, " + strings.Join(source.columns, ", ") +
+		" FROM " + source.table + " WHERE id > ? ORDER BY id ASC " + s.db.GetDialect().Limit(int64(s.batchSize))
+
+	var lastID int64
+	for {
+		var rows []map[string]string
+		err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			rows, err = sess.SQL(rawSQL, lastID).QueryString()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			location := source.name + "/" + row["ref"]
+			for _, column := range source.columns {
+				if err := s.scanText(ctx, row[column], location, leaks); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(rows) < s.batchSize {
+			return nil
+		}
+		lastID, err = strconv.ParseInt(rows[len(rows)-1]["id"], 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *ContentScanService) scanText(ctx context.Context, text, location string, leaks map[int64]*leak) error {
+	for _, match := range serviceAccountTokenPattern.FindAllString(text, -1) {
+		key, err := s.verifyServiceAccountToken(ctx, match)
+		if err != nil {
+			return err
+		}
+		addLeak(leaks, key, tokenTypeServiceAccount, location)
+	}
+
+	for _, match := range apiKeyPattern.FindAllString(text, -1) {
+		key, err := s.verifyAPIKey(ctx, match)
+		if err != nil {
+			return err
+		}
+		addLeak(leaks, key, tokenTypeAPIKey, location)
+	}
+
+	return nil
+}
+
+// verifyServiceAccountToken returns the stored key for the token or nil if it was not issued by this instance.
+func (s *ContentScanService) verifyServiceAccountToken(ctx context.Context, token string) (*apikey.APIKey, error) {
+	decoded, err := satokengen.Decode(token)
+	if err != nil {
+		return nil, nil
+	}
+
+	hash, err := decoded.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keys.GetAPIKeyByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, apikey.ErrInvalid) || errors.Is(err, apikey.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// verifyAPIKey returns the stored key for the legacy API key or nil if it was not issued by this instance.
+func (s *ContentScanService) verifyAPIKey(ctx context.Context, token string) (*apikey.APIKey, error) {
+	decoded, err := apikeygen.Decode(token)
+	if err != nil {
+		return nil, nil
+	}
+
+	key, err := s.keys.GetApiKeyByName(ctx, &apikey.GetByNameQuery{KeyName: decoded.Name, OrgID: decoded.OrgId})
+	if err != nil {
+		if errors.Is(err, apikey.ErrInvalid) || errors.Is(err, apikey.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	valid, err := apikeygen.IsValid(decoded, key.Key)
+	if err != nil || !valid {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func addLeak(leaks map[int64]*leak, key *apikey.APIKey, tokenType, location string) {
+	if key == nil || hasExpired(key.Expires) || (key.IsRevoked != nil && *key.IsRevoked) {
+		return
+	}
+
+	l, ok := leaks[key.ID]
+	if !ok {
+		l = &leak{key: key, tokenType: tokenType}
+		leaks[key.ID] = l
+	}
+	for _, existing := range l.locations {
+		if existing == location {
+			return
+		}
+	}
+	l.locations = append(l.locations, location)
+}
+
+func (s *ContentScanService) handleLeak(ctx context.Context, l *leak) {
+	revoked := false
+	if s.revoke && l.key.ServiceAccountId != nil {
+		if err := s.store.RevokeServiceAccountToken(ctx, l.key.OrgID, *l.key.ServiceAccountId, l.key.ID); err != nil {
+			s.logger.Error("Failed to revoke token found in stored content. Revoke manually.",
+				"error", err, "token_id", l.key.ID, "token", l.key.Name, "org", l.key.OrgID,
+				"serviceAccount", *l.key.ServiceAccountId)
+		} else {
+			revoked = true
+		}
+	}
+
+	if s.webHookNotify {
+		token := &Token{
+			Type:       l.tokenType,
+			URL:        strings.Join(l.locations, ", "),
+			Hash:       l.key.Key,
+			ReportedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		if err := s.webHookClient.Notify(ctx, token, l.key.Name, revoked); err != nil {
+			s.logger.Warn("Failed to call token leak webhook", "error", err)
+		}
+	}
+
+	if s.notifyOwners {
+		s.notifyTokenOwners(ctx, l, revoked)
+	}
+
+	s.logger.Warn("Found token in stored content",
+		"locations", strings.Join(l.locations, ", "),
+		"token_id", l.key.ID, "token", l.key.Name, "org", l.key.OrgID, "revoked", revoked)
+}
+
+// notifyTokenOwners emails the admins of the organization the token belongs to. Service accounts
+// and API keys are managed by the organization admins, they are the ones able to rotate the token.
+func (s *ContentScanService) notifyTokenOwners(ctx context.Context, l *leak, revoked bool) {
+	emails := make([]string, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(
+			"SELECT u.email FROM org_user AS ou INNER JOIN "+s.db.GetDialect().Quote("user")+" AS u ON u.id = ou.user_id"+
+				" WHERE ou.org_id = ? AND ou.role = ? AND u.is_disabled = ? AND u.is_service_account = ? AND u.email <> ''",
+			l.key.OrgID, org.RoleAdmin, false, false,
+		).Find(&emails)
+	})
+	if err != nil {
+		s.logger.Error("Failed to get the owners of the token found in stored content", "error", err, "token_id", l.key.ID)
+		return
+	}
+	if len(emails) == 0 {
+		return
+	}
+
+	err = s.emailSender.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+		To:       emails,
+		Template: tmplTokenLeaked,
+		Data: map[string]any{
+			"TokenName": l.key.Name,
+			"Locations": strings.Join(l.locations, ", "),
+			"Revoked":   revoked,
+		},
+	})
+	if err != nil {
+		s.logger.Error("Failed to notify the owners of the token found in stored content", "error", err, "token_id", l.key.ID)
+	}
+}
@@ -0,0 +1,133 @@
+package secretscan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/components/satokengen"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationContentScanService_ScanContent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	sqlStore := db.InitTestDB(t)
+
+	leaked, err := satokengen.New("sa")
+	require.NoError(t, err)
+	unknown, err := satokengen.New("sa")
+	require.NoError(t, err)
+	legacy, err := apikeygen.New(1, "legacy")
+	require.NoError(t, err)
+	revokedToken, err := satokengen.New("sa")
+	require.NoError(t, err)
+
+	revoked := true
+	keys := &MockKeyRetriever{keys: []*apikey.APIKey{
+		{ID: 1, OrgID: 1, Name: "ci", Key: leaked.HashedKey, ServiceAccountId: intPtr(10)},
+		{ID: 2, OrgID: 1, Name: "legacy", Key: legacy.HashedKey, ServiceAccountId: intPtr(11)},
+		{ID: 3, OrgID: 1, Name: "old", Key: revokedToken.HashedKey, ServiceAccountId: intPtr(10), IsRevoked: &revoked},
+	}}
+
+	err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		statements := [][]any{
+			{`INSERT INTO dashboard (version, slug, title, data, org_id, created, updated, uid) VALUES (1, 'a', 'A', ?, 1, ?, ?, 'dash-a')`,
+				`{"panels":[{"description":"Authorization: Bearer ` + leaked.ClientSecret + `"}]}`, "2024-01-01", "2024-01-01"},
+			{`INSERT INTO dashboard (version, slug, title, data, org_id, created, updated, uid) VALUES (1, 'b', 'B', ?, 1, ?, ?, 'dash-b')`,
+				`{"panels":[{"description":"` + unknown.ClientSecret + " " + revokedToken.ClientSecret + `"}]}`, "2024-01-01", "2024-01-01"},
+			{`INSERT INTO dashboard (version, slug, title, data, org_id, created, updated, uid) VALUES (1, 'c', 'C', ?, 1, ?, ?, 'dash-c')`,
+				`{"templating":{"list":[{"query":"` + leaked.ClientSecret + `"}]}}`, "2024-01-01", "2024-01-01"},
+			{`INSERT INTO annotation (org_id, type, title, text, prev_state, new_state, data, epoch) VALUES (1, '', '', ?, '', '', '{}', 0)`,
+				"token is " + leaked.ClientSecret},
+			{`INSERT INTO query_history (uid, org_id, datasource_uid, created_by, created_at, comment, queries) VALUES ('query-a', 1, 'ds', 1, 0, 'use this key', ?)`,
+				`[{"headers":{"Authorization":"Bearer ` + legacy.ClientSecret + `"}}]`},
+		}
+		for _, statement := range statements {
+			if _, err := sess.Exec(statement...); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		for _, u := range []struct {
+			login string
+			role  org.RoleType
+		}{{"admin", org.RoleAdmin}, {"editor", org.RoleEditor}} {
+			usr := &user.User{UID: u.login, Login: u.login, Email: u.login + "@example.com", OrgID: 1, Created: now, Updated: now, LastSeenAt: now}
+			if _, err := sess.Insert(usr); err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&org.OrgUser{OrgID: 1, UserID: usr.ID, Role: u.role, Created: now, Updated: now}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	store := &MockTokenRetriever{}
+	notifier := &MockSecretScanNotifier{}
+	emails := []*notifications.SendEmailCommand{}
+	emailSender := &notifications.NotificationServiceMock{EmailHandler: func(_ context.Context, cmd *notifications.SendEmailCommand) error {
+		emails = append(emails, cmd)
+		return nil
+	}}
+	s := &ContentScanService{
+		db:            sqlStore,
+		keys:          keys,
+		store:         store,
+		webHookClient: notifier,
+		emailSender:   emailSender,
+		logger:        log.New("secretscan.content"),
+		webHookNotify: true,
+		notifyOwners:  true,
+		revoke:        true,
+		batchSize:     1,
+	}
+
+	require.NoError(t, s.ScanContent(context.Background()))
+
+	assert.ElementsMatch(t, [][]any{{int64(1), int64(10), int64(1)}, {int64(1), int64(11), int64(2)}}, store.revokeCalls)
+	require.Len(t, notifier.notifyCalls, 2)
+
+	notified := map[string]*Token{}
+	for _, call := range notifier.notifyCalls {
+		assert.True(t, call[2].(bool))
+		notified[call[1].(string)] = call[0].(*Token)
+	}
+	require.Contains(t, notified, "ci")
+	assert.Equal(t, tokenTypeServiceAccount, notified["ci"].Type)
+	assert.Equal(t, "dashboard/dash-a, dashboard/dash-c, annotation/1", notified["ci"].URL)
+	require.Contains(t, notified, "legacy")
+	assert.Equal(t, tokenTypeAPIKey, notified["legacy"].Type)
+	assert.Equal(t, "query_history/query-a", notified["legacy"].URL)
+
+	// the org admins are notified about every token
+	require.Len(t, emails, 2)
+	for _, email := range emails {
+		assert.Equal(t, tmplTokenLeaked, email.Template)
+		assert.Equal(t, []string{"admin@example.com"}, email.To)
+		assert.True(t, email.Data["Revoked"].(bool))
+	}
+}
+
+func intPtr(n int64) *int64 {
+	return &n
+}
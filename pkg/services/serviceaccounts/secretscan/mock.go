@@ -64,3 +64,27 @@ func (m *MockSecretScanNotifier) Notify(ctx context.Context,
 
 	return m.err
 }
+
+type MockKeyRetriever struct {
+	keys []*apikey.APIKey
+}
+
+func (m *MockKeyRetriever) GetApiKeyByName(ctx context.Context, query *apikey.GetByNameQuery) (*apikey.APIKey, error) {
+	for _, key := range m.keys {
+		if key.OrgID == query.OrgID && key.Name == query.KeyName {
+			return key, nil
+		}
+	}
+
+	return nil, apikey.ErrInvalid
+}
+
+func (m *MockKeyRetriever) GetAPIKeyByHash(ctx context.Context, hash string) (*apikey.APIKey, error) {
+	for _, key := range m.keys {
+		if key.Key == hash {
+			return key, nil
+		}
+	}
+
+	return nil, apikey.ErrInvalid
+}
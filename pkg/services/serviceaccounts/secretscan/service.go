@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -36,14 +38,13 @@ type Service struct {
 	client        CheckerClient
 	webHookClient WebHookClient
 	logger        log.Logger
+	bus           bus.Bus
 	webHookNotify bool
 	revoke        bool // whether to revoke leaked tokens
 }
 
-func NewService(store SATokenRetriever, cfg *setting.Cfg) (*Service, error) {
+func NewService(store SATokenRetriever, cfg *setting.Cfg, bus bus.Bus) (*Service, error) {
 	secretscanBaseURL := cfg.SectionWithEnvOverrides("secretscan").Key("base_url").MustString(defaultURL)
-	// URL to send outgoing webhook when a token is leaked.
-	oncallURL := cfg.SectionWithEnvOverrides("secretscan").Key("oncall_url").MustString("")
 	revoke := cfg.SectionWithEnvOverrides("secretscan").Key("revoke").MustBool(true)
 
 	client, err := newClient(secretscanBaseURL, cfg.BuildVersion, cfg.Env == setting.Dev)
@@ -51,13 +52,9 @@ func NewService(store SATokenRetriever, cfg *setting.Cfg) (*Service, error) {
 		return nil, fmt.Errorf("failed to create secretscan client: %w", err)
 	}
 
-	var webHookClient WebHookClient
-	if oncallURL != "" {
-		var errWebhook error
-		webHookClient, errWebhook = newWebHookClient(oncallURL, cfg.BuildVersion, cfg.Env == setting.Dev)
-		if errWebhook != nil {
-			return nil, fmt.Errorf("failed to create secretscan webhook client: %w", errWebhook)
-		}
+	webHookClient, err := webHookClientFromConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Service{
@@ -65,11 +62,28 @@ func NewService(store SATokenRetriever, cfg *setting.Cfg) (*Service, error) {
 		client:        client,
 		webHookClient: webHookClient,
 		logger:        log.New("secretscan"),
-		webHookNotify: oncallURL != "",
+		bus:           bus,
+		webHookNotify: webHookClient != nil,
 		revoke:        revoke,
 	}, nil
 }
 
+// webHookClientFromConfig returns the client for the configured oncall URL, or nil if none is set.
+func webHookClientFromConfig(cfg *setting.Cfg) (WebHookClient, error) {
+	// URL to send outgoing webhook when a token is leaked.
+	oncallURL := cfg.SectionWithEnvOverrides("secretscan").Key("oncall_url").MustString("")
+	if oncallURL == "" {
+		return nil, nil
+	}
+
+	webHookClient, err := newWebHookClient(oncallURL, cfg.BuildVersion, cfg.Env == setting.Dev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secretscan webhook client: %w", err)
+	}
+
+	return webHookClient, nil
+}
+
 func (s *Service) RetrieveActiveTokens(ctx context.Context) ([]apikey.APIKey, error) {
 	saTokens, err := s.store.ListTokens(ctx, &serviceaccounts.GetSATokensQuery{})
 	if err != nil {
@@ -125,6 +139,8 @@ func (s *Service) CheckTokens(ctx context.Context) error {
 					"error", err, "url", secretscanToken.URL, "reported_at", secretscanToken.ReportedAt,
 					"token_id", leakedToken.ID, "token", leakedToken.Name, "org", leakedToken.OrgID,
 					"serviceAccount", *leakedToken.ServiceAccountId)
+			} else {
+				s.publishRevoked(ctx, *leakedToken.ServiceAccountId, leakedToken.ID)
 			}
 		}
 
@@ -143,6 +159,23 @@ func (s *Service) CheckTokens(ctx context.Context) error {
 	return nil
 }
 
+// publishRevoked publishes a UserTokenRevoked event for a leaked token, so that the revocation is
+// sent to the system notification targets.
+func (s *Service) publishRevoked(ctx context.Context, serviceAccountID, tokenID int64) {
+	if s.bus == nil {
+		return
+	}
+	if err := s.bus.Publish(ctx, &events.UserTokenRevoked{
+		Timestamp: time.Now(),
+		Kind:      events.UserTokenRevokedServiceAccountToken,
+		UserID:    serviceAccountID,
+		TokenID:   tokenID,
+		RevokedBy: "secret scan",
+	}); err != nil {
+		s.logger.Warn("Failed to publish leaked token revoked event", "error", err)
+	}
+}
+
 // filterCheckableTokens returns a list of tokens that can be checked and a map of tokens to their hashes.
 func (*Service) filterCheckableTokens(tokens []apikey.APIKey) ([]string, map[string]apikey.APIKey) {
 	hashes := make([]string, 0, len(tokens))
@@ -13,7 +13,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -142,12 +145,19 @@ func TestService_CheckTokens(t *testing.T) {
 			tokenStore := &MockTokenRetriever{keys: tt.retrievedTokens}
 			client := &MockSecretScanClient{tokens: tt.leakedTokens}
 			notifier := &MockSecretScanNotifier{}
+			revoked := []*events.UserTokenRevoked{}
+			b := bus.ProvideBus(tracing.InitializeTracerForTest())
+			b.AddEventListener(func(ctx context.Context, evt *events.UserTokenRevoked) error {
+				revoked = append(revoked, evt)
+				return nil
+			})
 
 			service := &Service{
 				store:         tokenStore,
 				client:        client,
 				webHookClient: notifier,
 				logger:        log.New("secretscan"),
+				bus:           b,
 				webHookNotify: tt.notify,
 				revoke:        tt.revoke,
 			}
@@ -164,8 +174,11 @@ func TestService_CheckTokens(t *testing.T) {
 			if len(tt.leakedTokens) > 0 {
 				if tt.revoke {
 					assert.Len(t, tokenStore.revokeCalls, len(tt.leakedTokens))
+					require.Len(t, revoked, len(tt.leakedTokens))
+					assert.Equal(t, events.UserTokenRevokedServiceAccountToken, revoked[0].Kind)
 				} else {
 					assert.Empty(t, tokenStore.revokeCalls)
+					assert.Empty(t, revoked)
 				}
 			}
 
@@ -278,7 +291,7 @@ func TestService(t *testing.T) {
 	revoke := section.Key("revoke")
 	revoke.SetValue("true")
 
-	service, err := NewService(tokenRetriever, cfg)
+	service, err := NewService(tokenRetriever, cfg, nil)
 	require.NoError(t, err)
 	require.NotNil(t, service)
 
@@ -38,6 +38,10 @@ type Service interface {
 	// Tokens
 	AddServiceAccountToken(ctx context.Context, serviceAccountID int64,
 		cmd *AddServiceAccountTokenCommand) (*apikey.APIKey, error)
+	// CreateServiceAccountToken generates the key of a new token of the service account and adds the token,
+	// once the command is valid and the expiration of the token is within the configured limits.
+	CreateServiceAccountToken(ctx context.Context, serviceAccountID int64,
+		cmd *AddServiceAccountTokenCommand) (*NewServiceAccountToken, error)
 	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
 	ListTokens(ctx context.Context, query *GetSATokensQuery) ([]apikey.APIKey, error)
 
@@ -15,6 +15,7 @@ type FakeServiceAccountService struct {
 	ExpectedServiceAccount                 *serviceaccounts.ServiceAccountDTO
 	ExpectedServiceAccountID               int64
 	ExpectedServiceAccountProfile          *serviceaccounts.ServiceAccountProfileDTO
+	ExpectedServiceAccountToken            *serviceaccounts.NewServiceAccountToken
 	ExpectedServiceAccountTokens           []apikey.APIKey
 }
 
@@ -28,6 +29,10 @@ func (f *FakeServiceAccountService) CreateServiceAccount(ctx context.Context, or
 	return f.ExpectedServiceAccount, f.ExpectedErr
 }
 
+func (f *FakeServiceAccountService) CreateServiceAccountToken(ctx context.Context, id int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*serviceaccounts.NewServiceAccountToken, error) {
+	return f.ExpectedServiceAccountToken, f.ExpectedErr
+}
+
 func (f *FakeServiceAccountService) DeleteServiceAccount(ctx context.Context, orgID, id int64) error {
 	return f.ExpectedErr
 }
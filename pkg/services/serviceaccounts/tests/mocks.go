@@ -77,6 +77,36 @@ func (_m *MockServiceAccountService) CreateServiceAccount(ctx context.Context, o
 	return r0, r1
 }
 
+// CreateServiceAccountToken provides a mock function with given fields: ctx, serviceAccountID, cmd
+func (_m *MockServiceAccountService) CreateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*serviceaccounts.NewServiceAccountToken, error) {
+	ret := _m.Called(ctx, serviceAccountID, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateServiceAccountToken")
+	}
+
+	var r0 *serviceaccounts.NewServiceAccountToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *serviceaccounts.AddServiceAccountTokenCommand) (*serviceaccounts.NewServiceAccountToken, error)); ok {
+		return rf(ctx, serviceAccountID, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *serviceaccounts.AddServiceAccountTokenCommand) *serviceaccounts.NewServiceAccountToken); ok {
+		r0 = rf(ctx, serviceAccountID, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serviceaccounts.NewServiceAccountToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *serviceaccounts.AddServiceAccountTokenCommand) error); ok {
+		r1 = rf(ctx, serviceAccountID, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteServiceAccount provides a mock function with given fields: ctx, orgID, serviceAccountID
 func (_m *MockServiceAccountService) DeleteServiceAccount(ctx context.Context, orgID int64, serviceAccountID int64) error {
 	ret := _m.Called(ctx, orgID, serviceAccountID)
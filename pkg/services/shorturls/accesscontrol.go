@@ -0,0 +1,15 @@
+package shorturls
+
+import ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+
+const (
+	ScopeRoot = "shorturls"
+
+	// ActionRevoke lets users revoke short URLs they didn't create. Creators can always revoke their short URLs.
+	ActionRevoke = "shorturls:revoke"
+)
+
+var (
+	ScopeProvider = ac.NewScopeProvider(ScopeRoot)
+	ScopeAll      = ScopeProvider.GetResourceAllScope()
+)
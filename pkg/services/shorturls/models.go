@@ -1,18 +1,25 @@
 package shorturls
 
 import (
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 )
 
 var (
-	ErrShortURLBadRequest   = errutil.BadRequest("shorturl.bad-request")
-	ErrShortURLNotFound     = errutil.NotFound("shorturl.not-found")
-	ErrShortURLAbsolutePath = errutil.ValidationFailed("shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
-	ErrShortURLInvalidPath  = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
-	ErrShortURLInternal     = errutil.Internal("shorturl.internal")
-	ErrShortURLConflict     = errutil.Conflict("shorturl.conflict")
+	ErrShortURLBadRequest      = errutil.BadRequest("shorturl.bad-request")
+	ErrShortURLNotFound        = errutil.NotFound("shorturl.not-found")
+	ErrShortURLAbsolutePath    = errutil.ValidationFailed("shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
+	ErrShortURLInvalidPath     = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
+	ErrShortURLInternal        = errutil.Internal("shorturl.internal")
+	ErrShortURLConflict        = errutil.Conflict("shorturl.conflict")
+	ErrShortURLExpired         = errutil.NotFound("shorturl.expired", errutil.WithPublicMessage("Short URL has expired"))
+	ErrShortURLUsedUp          = errutil.NotFound("shorturl.used-up", errutil.WithPublicMessage("Short URL has reached its maximum number of uses"))
+	ErrShortURLPassword        = errutil.Unauthorized("shorturl.password", errutil.WithPublicMessage("Short URL requires a valid password"))
+	ErrShortURLTooManyAttempts = errutil.TooManyRequests("shorturl.too-many-attempts", errutil.WithPublicMessage("Too many incorrect passwords, try again later"))
+	ErrShortURLForbidden       = errutil.Forbidden("shorturl.forbidden", errutil.WithPublicMessage("Only the creator of a short URL or users allowed to revoke short URLs can revoke it"))
+	ErrShortURLNotSupported    = errutil.NotImplemented("shorturl.not-supported", errutil.WithPublicMessage("Listing, revoking and auditing short URLs isn't supported by the kubernetes short URL API"))
 )
 
 type ShortUrl struct {
@@ -23,11 +30,65 @@ type ShortUrl struct {
 	CreatedBy  int64  `json:"-"`
 	CreatedAt  int64  `json:"-"`
 	LastSeenAt int64  `json:"lastSeenAt"`
+	// ExpiresAt is the unix time after which the short URL no longer resolves, 0 if it never expires
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	// MaxUses is the number of times the short URL can be resolved, 0 if it is unlimited
+	MaxUses  int64  `json:"maxUses,omitempty"`
+	UseCount int64  `json:"useCount"`
+	Password string `json:"-"`
+	Salt     string `json:"-"`
+}
+
+// IsPasswordProtected returns true if the short URL only resolves with its password
+func (s *ShortUrl) IsPasswordProtected() bool {
+	return s.Password != ""
+}
+
+// DashboardUID returns the UID of the dashboard the short URL resolves to, or an empty string if its path is not a dashboard
+func (s *ShortUrl) DashboardUID() string {
+	p, _, _ := strings.Cut(s.Path, "?")
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	switch parts[0] {
+	case "d", "d-solo", "d-embed":
+		return parts[1]
+	}
+	return ""
+}
+
+// IsExpired returns true if the expiry of the short URL is before t
+func (s *ShortUrl) IsExpired(t time.Time) bool {
+	return s.ExpiresAt > 0 && s.ExpiresAt <= t.Unix()
+}
+
+// IsUsedUp returns true if the short URL has been resolved as many times as it is allowed to
+func (s *ShortUrl) IsUsedUp() bool {
+	return s.MaxUses > 0 && s.UseCount >= s.MaxUses
 }
 
 type DeleteShortUrlCommand struct {
-	Uid       string
+	Uid string
+	// OrgId restricts the deletion of a short URL by UID to an organization, if set
+	OrgId     int64
 	OlderThan time.Time
 
 	NumDeleted int64
 }
+
+type SearchShortURLsQuery struct {
+	// OrgId filters the short URLs of an organization, 0 for all organizations
+	OrgId int64
+	// CreatedBy filters the short URLs created by a user, 0 for all users
+	CreatedBy int64
+	Page      int
+	Limit     int
+}
+
+type SearchShortURLsResult struct {
+	ShortURLs  []*ShortUrl
+	TotalCount int64
+	Page       int
+	PerPage    int
+}
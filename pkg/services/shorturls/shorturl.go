@@ -10,7 +10,14 @@ import (
 type Service interface {
 	GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*ShortUrl, error)
 	CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *dtos.CreateShortURLCmd) (*ShortUrl, error)
+	// ResolveShortURL returns the short URL if it has not expired or been used up and the password matches, and
+	// counts the use
+	ResolveShortURL(ctx context.Context, user *user.SignedInUser, uid string, password string) (*ShortUrl, error)
 	UpdateLastSeenAt(ctx context.Context, shortURL *ShortUrl) error
+	SearchShortURLs(ctx context.Context, query *SearchShortURLsQuery) (*SearchShortURLsResult, error)
+	// RevokeShortURL deletes a short URL of the organization of the user, if the user created it or has the
+	// permission to revoke it
+	RevokeShortURL(ctx context.Context, user *user.SignedInUser, uid string) error
 	DeleteStaleShortURLs(ctx context.Context, cmd *DeleteShortUrlCommand) error
 	ConvertShortURLToDTO(shortURL *ShortUrl, appURL string) *dtos.ShortURL
 }
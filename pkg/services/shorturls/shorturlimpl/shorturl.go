@@ -2,6 +2,7 @@ package shorturlimpl
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"path"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
@@ -17,15 +19,22 @@ import (
 
 var getTime = time.Now
 
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
 type ShortURLService struct {
-	SQLStore store
+	SQLStore      store
+	accessControl ac.AccessControl
 }
 
-func ProvideService(db db.DB) *ShortURLService {
+func ProvideService(db db.DB, accessControl ac.AccessControl) *ShortURLService {
 	return &ShortURLService{
 		SQLStore: &sqlStore{
 			db: db,
 		},
+		accessControl: accessControl,
 	}
 }
 
@@ -33,6 +42,35 @@ func (s ShortURLService) GetShortURLByUID(ctx context.Context, user *user.Signed
 	return s.SQLStore.Get(ctx, user, uid)
 }
 
+func (s ShortURLService) ResolveShortURL(ctx context.Context, user *user.SignedInUser, uid string, password string) (*shorturls.ShortUrl, error) {
+	shortURL, err := s.SQLStore.Get(ctx, user, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if shortURL.IsExpired(getTime()) {
+		return nil, shorturls.ErrShortURLExpired.Errorf("short URL %s expired at %d", uid, shortURL.ExpiresAt)
+	}
+	if shortURL.IsUsedUp() {
+		return nil, shorturls.ErrShortURLUsedUp.Errorf("short URL %s was used %d times", uid, shortURL.UseCount)
+	}
+	if shortURL.IsPasswordProtected() {
+		encoded, err := util.EncodePassword(password, shortURL.Salt)
+		if err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to encode password: %w", err)
+		}
+		if password == "" || subtle.ConstantTimeCompare([]byte(encoded), []byte(shortURL.Password)) != 1 {
+			return nil, shorturls.ErrShortURLPassword.Errorf("invalid password for short URL %s", uid)
+		}
+	}
+
+	// the use is counted in the database so that concurrent requests cannot exceed the maximum number of uses
+	if err := s.SQLStore.Use(ctx, shortURL); err != nil {
+		return nil, err
+	}
+	return shortURL, nil
+}
+
 func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	return s.SQLStore.Update(ctx, shortURL)
 }
@@ -74,12 +112,34 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedIn
 	}
 
 	now := time.Now().Unix()
+	if cmd.ExpiresAt != 0 && cmd.ExpiresAt <= now {
+		return nil, shorturls.ErrShortURLBadRequest.Errorf("expiry must be in the future: %d", cmd.ExpiresAt)
+	}
+	if cmd.MaxUses < 0 {
+		return nil, shorturls.ErrShortURLBadRequest.Errorf("maximum number of uses cannot be negative: %d", cmd.MaxUses)
+	}
+
 	shortURL := shorturls.ShortUrl{
 		OrgId:     user.OrgID,
 		Uid:       uid,
 		Path:      relPath,
 		CreatedBy: user.UserID,
 		CreatedAt: now,
+		ExpiresAt: cmd.ExpiresAt,
+		MaxUses:   cmd.MaxUses,
+	}
+
+	if cmd.Password != "" {
+		salt, err := util.GetRandomString(10)
+		if err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to generate salt: %w", err)
+		}
+		encoded, err := util.EncodePassword(cmd.Password, salt)
+		if err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to encode password: %w", err)
+		}
+		shortURL.Salt = salt
+		shortURL.Password = encoded
 	}
 
 	if err := s.SQLStore.Insert(ctx, &shortURL); err != nil {
@@ -93,6 +153,33 @@ func (s ShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturl
 	return s.SQLStore.Delete(ctx, cmd)
 }
 
+func (s ShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	if query.Limit <= 0 || query.Limit > maxSearchLimit {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	return s.SQLStore.Search(ctx, query)
+}
+
+func (s ShortURLService) RevokeShortURL(ctx context.Context, user *user.SignedInUser, uid string) error {
+	shortURL, err := s.SQLStore.Get(ctx, user, uid)
+	if err != nil {
+		return err
+	}
+	if shortURL.CreatedBy != user.UserID {
+		ok, err := s.accessControl.Evaluate(ctx, user, ac.EvalPermission(shorturls.ActionRevoke, shorturls.ScopeProvider.GetResourceScopeUID(uid)))
+		if err != nil {
+			return shorturls.ErrShortURLInternal.Errorf("failed to evaluate permissions: %w", err)
+		}
+		if !ok {
+			return shorturls.ErrShortURLForbidden.Errorf("user %d cannot revoke short URL %s", user.UserID, uid)
+		}
+	}
+	return s.SQLStore.Delete(ctx, &shorturls.DeleteShortUrlCommand{Uid: uid, OrgId: user.OrgID})
+}
+
 func (s ShortURLService) ConvertShortURLToDTO(shortURL *shorturls.ShortUrl, appURL string) *dtos.ShortURL {
 	url := fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(appURL, "/"), shortURL.Uid, shortURL.OrgId)
 
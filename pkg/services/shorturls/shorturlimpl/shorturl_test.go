@@ -9,6 +9,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
@@ -181,4 +184,137 @@ func TestIntegrationShortURLService(t *testing.T) {
 		require.ErrorIs(t, err, shorturls.ErrShortURLConflict)
 		require.Nil(t, newShortURL2)
 	})
+
+	t.Run("Expiring short URLs only resolve before their expiry", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+		ctx := context.Background()
+
+		_, err := service.CreateShortURL(ctx, user, &dtos.CreateShortURLCmd{Path: "d/abc", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+		require.ErrorIs(t, err, shorturls.ErrShortURLBadRequest)
+
+		expiresAt := time.Now().Add(time.Hour)
+		shortURL, err := service.CreateShortURL(ctx, user, &dtos.CreateShortURLCmd{Path: "d/abc", ExpiresAt: expiresAt.Unix()})
+		require.NoError(t, err)
+
+		resolved, err := service.ResolveShortURL(ctx, user, shortURL.Uid, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), resolved.UseCount)
+
+		origGetTime := getTime
+		t.Cleanup(func() {
+			getTime = origGetTime
+		})
+		getTime = func() time.Time {
+			return expiresAt.Add(time.Second)
+		}
+
+		_, err = service.ResolveShortURL(ctx, user, shortURL.Uid, "")
+		require.ErrorIs(t, err, shorturls.ErrShortURLExpired)
+
+		t.Run("and are deleted with the stale short URLs once expired", func(t *testing.T) {
+			cmd := shorturls.DeleteShortUrlCommand{OlderThan: time.Unix(0, 0)}
+			require.NoError(t, service.DeleteStaleShortURLs(ctx, &cmd))
+			require.Equal(t, int64(1), cmd.NumDeleted)
+
+			_, err := service.GetShortURLByUID(ctx, user, shortURL.Uid)
+			require.ErrorIs(t, err, shorturls.ErrShortURLNotFound)
+		})
+	})
+
+	t.Run("Short URLs with a maximum number of uses", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+		ctx := context.Background()
+
+		_, err := service.CreateShortURL(ctx, user, &dtos.CreateShortURLCmd{Path: "d/abc", MaxUses: -1})
+		require.ErrorIs(t, err, shorturls.ErrShortURLBadRequest)
+
+		shortURL, err := service.CreateShortURL(ctx, user, &dtos.CreateShortURLCmd{Path: "d/abc", MaxUses: 2})
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, err := service.ResolveShortURL(ctx, user, shortURL.Uid, "")
+			require.NoError(t, err)
+		}
+		_, err = service.ResolveShortURL(ctx, user, shortURL.Uid, "")
+		require.ErrorIs(t, err, shorturls.ErrShortURLUsedUp)
+
+		stored, err := service.GetShortURLByUID(ctx, user, shortURL.Uid)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stored.UseCount)
+	})
+
+	t.Run("Password protected short URLs only resolve with their password", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+		ctx := context.Background()
+
+		shortURL, err := service.CreateShortURL(ctx, user, &dtos.CreateShortURLCmd{Path: "d/abc", Password: "incident-42"})
+		require.NoError(t, err)
+		require.True(t, shortURL.IsPasswordProtected())
+		require.NotEqual(t, "incident-42", shortURL.Password)
+
+		_, err = service.ResolveShortURL(ctx, user, shortURL.Uid, "")
+		require.ErrorIs(t, err, shorturls.ErrShortURLPassword)
+
+		_, err = service.ResolveShortURL(ctx, user, shortURL.Uid, "wrong")
+		require.ErrorIs(t, err, shorturls.ErrShortURLPassword)
+
+		resolved, err := service.ResolveShortURL(ctx, user, shortURL.Uid, "incident-42")
+		require.NoError(t, err)
+		require.Equal(t, "d/abc", resolved.Path)
+	})
+
+	t.Run("Creators can list and revoke their short URLs", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}, accessControl: acimpl.ProvideAccessControl(featuremgmt.WithFeatures())}
+		ctx := context.Background()
+
+		creator := newSignedInUser(10, 2, org.RoleViewer)
+		other := newSignedInUser(11, 2, org.RoleEditor)
+		admin := newSignedInUser(12, 2, org.RoleAdmin)
+		admin.Permissions = map[int64]map[string][]string{2: {shorturls.ActionRevoke: {shorturls.ScopeAll}}}
+
+		first, err := service.CreateShortURL(ctx, creator, &dtos.CreateShortURLCmd{Path: "d/first/dashboard?orgId=2"})
+		require.NoError(t, err)
+		second, err := service.CreateShortURL(ctx, creator, &dtos.CreateShortURLCmd{Path: "explore?orgId=2"})
+		require.NoError(t, err)
+		_, err = service.CreateShortURL(ctx, other, &dtos.CreateShortURLCmd{Path: "d/other"})
+		require.NoError(t, err)
+
+		result, err := service.SearchShortURLs(ctx, &shorturls.SearchShortURLsQuery{OrgId: 2, CreatedBy: creator.UserID})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.TotalCount)
+		require.Len(t, result.ShortURLs, 2)
+
+		result, err = service.SearchShortURLs(ctx, &shorturls.SearchShortURLsQuery{OrgId: 2, Limit: 1, Page: 2})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.TotalCount)
+		require.Len(t, result.ShortURLs, 1)
+
+		err = service.RevokeShortURL(ctx, other, first.Uid)
+		require.ErrorIs(t, err, shorturls.ErrShortURLForbidden)
+
+		require.NoError(t, service.RevokeShortURL(ctx, creator, first.Uid))
+		_, err = service.GetShortURLByUID(ctx, creator, first.Uid)
+		require.ErrorIs(t, err, shorturls.ErrShortURLNotFound)
+
+		require.NoError(t, service.RevokeShortURL(ctx, admin, second.Uid))
+		_, err = service.GetShortURLByUID(ctx, creator, second.Uid)
+		require.ErrorIs(t, err, shorturls.ErrShortURLNotFound)
+	})
+}
+
+func TestShortURLDashboardUID(t *testing.T) {
+	for path, expected := range map[string]string{
+		"d/abc/my-dashboard?orgId=1&from=now-1h": "abc",
+		"d/abc":                                  "abc",
+		"d-solo/abc/my-dashboard?panelId=2":      "abc",
+		"explore?left=%7B%7D":                    "",
+		"d":                                      "",
+	} {
+		shortURL := &shorturls.ShortUrl{Path: path}
+		require.Equal(t, expected, shortURL.DashboardUID(), path)
+	}
+}
+
+func newSignedInUser(userID int64, orgID int64, role org.RoleType) *user.SignedInUser {
+	return &user.SignedInUser{UserID: userID, OrgID: orgID, OrgRole: role}
 }
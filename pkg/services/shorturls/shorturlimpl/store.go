@@ -2,6 +2,7 @@ package shorturlimpl
 
 import (
 	"context"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/shorturls"
@@ -12,6 +13,8 @@ type store interface {
 	Get(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error)
 	Update(ctx context.Context, shortURL *shorturls.ShortUrl) error
 	Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error
+	Use(ctx context.Context, shortURL *shorturls.ShortUrl) error
+	Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error)
 	Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error
 }
 
@@ -50,6 +53,65 @@ func (s sqlStore) Update(ctx context.Context, shortURL *shorturls.ShortUrl) erro
 	})
 }
 
+func (s sqlStore) Use(ctx context.Context, shortURL *shorturls.ShortUrl) error {
+	now := getTime().Unix()
+	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		var rawSQL = "UPDATE short_url SET use_count = use_count + 1, last_seen_at = ? WHERE id = ? AND (max_uses IS NULL OR max_uses = 0 OR use_count < max_uses)"
+
+		result, err := session.Exec(rawSQL, now, shortURL.Id)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return shorturls.ErrShortURLUsedUp.Errorf("short URL %s was used %d times", shortURL.Uid, shortURL.MaxUses)
+		}
+
+		shortURL.UseCount++
+		shortURL.LastSeenAt = now
+		return nil
+	})
+}
+
+func (s sqlStore) Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	result := &shorturls.SearchShortURLsResult{
+		ShortURLs: make([]*shorturls.ShortUrl, 0),
+		Page:      query.Page,
+		PerPage:   query.Limit,
+	}
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		where := []string{"1 = 1"}
+		args := []any{}
+		if query.OrgId != 0 {
+			where = append(where, "org_id = ?")
+			args = append(args, query.OrgId)
+		}
+		if query.CreatedBy != 0 {
+			where = append(where, "created_by = ?")
+			args = append(args, query.CreatedBy)
+		}
+		whereSQL := strings.Join(where, " AND ")
+
+		count, err := dbSession.Where(whereSQL, args...).Count(&shorturls.ShortUrl{})
+		if err != nil {
+			return err
+		}
+		result.TotalCount = count
+
+		return dbSession.Where(whereSQL, args...).
+			Desc("created_at").
+			Limit(query.Limit, (query.Page-1)*query.Limit).
+			Find(&result.ShortURLs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s sqlStore) Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	return s.db.WithDbSession(ctx, func(session *db.Session) error {
 		_, err := session.Insert(shortURL)
@@ -62,8 +124,13 @@ func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlComma
 	if cmd.Uid != "" {
 		return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
 			var rawSql = "DELETE FROM short_url WHERE uid = ?"
+			args := []any{cmd.Uid}
+			if cmd.OrgId != 0 {
+				rawSql += " AND org_id = ?"
+				args = append(args, cmd.OrgId)
+			}
 
-			if result, err := session.Exec(rawSql, cmd.Uid); err != nil {
+			if result, err := session.Exec(append([]any{rawSql}, args...)...); err != nil {
 				return err
 			} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
 				return err
@@ -72,11 +139,13 @@ func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlComma
 		})
 	}
 
-	// Otherwise, delete all stale short URLs older than the specified time
+	// Otherwise, delete all stale short URLs older than the specified time, and the ones that expired or were used up
 	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
-		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)"
+		var rawSql = "DELETE FROM short_url WHERE (created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0))" +
+			" OR (expires_at > 0 AND expires_at <= ?)" +
+			" OR (max_uses > 0 AND use_count >= max_uses)"
 
-		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix()); err != nil {
+		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix(), getTime().Unix()); err != nil {
 			return err
 		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
 			return err
@@ -210,4 +210,44 @@ func AddMigration(mg *migrator.Migrator) {
 		Type: migrator.UniqueIndex,
 		Cols: []string{"org_id", "user_id", "role_id"},
 	}))
+
+	mg.AddMigration("add expires column to permission table", migrator.NewAddColumnMigration(permissionV1, &migrator.Column{
+		Name: "expires", Type: migrator.DB_DateTime, Nullable: true,
+	}))
+
+	mg.AddMigration("add permission expires index", migrator.NewAddIndexMigration(permissionV1, &migrator.Index{
+		Cols: []string{"expires"},
+	}))
+
+	mg.AddMigration("add expires column to user_role table", migrator.NewAddColumnMigration(userRoleV1, &migrator.Column{
+		Name: "expires", Type: migrator.DB_DateTime, Nullable: true,
+	}))
+
+	mg.AddMigration("add expires column to team_role table", migrator.NewAddColumnMigration(teamRoleV1, &migrator.Column{
+		Name: "expires", Type: migrator.DB_DateTime, Nullable: true,
+	}))
+
+	expiredGrantAuditV1 := migrator.Table{
+		Name: "expired_grant_audit",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "type", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "role_name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "is_service_account", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "team_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "built_in_role", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "action", Type: migrator.DB_Varchar, Length: 190, Nullable: false},
+			{Name: "scope", Type: migrator.DB_Varchar, Length: 190, Nullable: false},
+			{Name: "expires", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "removed", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "removed"}},
+		},
+	}
+
+	mg.AddMigration("create expired_grant_audit table", migrator.NewAddTableMigration(expiredGrantAuditV1))
+	mg.AddMigration("add expired_grant_audit org_id removed index", migrator.NewAddIndexMigration(expiredGrantAuditV1, expiredGrantAuditV1.Indices[0]))
 }
@@ -96,4 +96,10 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add allowed_variables column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "allowed_variables",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
 }
@@ -49,4 +49,31 @@ func addLoginAttemptMigrations(mg *Migrator) {
 	mg.AddMigration("alter table login_attempt alter column created type to bigint", NewRawSQLMigration("").
 		Mysql("ALTER TABLE login_attempt MODIFY created BIGINT;").
 		Postgres("ALTER TABLE login_attempt ALTER COLUMN created TYPE BIGINT;"))
+
+	// Subnet of the IP address, used to lock out a whole subnet
+	mg.AddMigration("add column subnet to login_attempt", NewAddColumnMigration(loginAttemptV2, &Column{
+		Name: "subnet", Type: DB_NVarchar, Length: 50, Nullable: true,
+	}))
+
+	loginLockoutV1 := Table{
+		Name: "login_lockout",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "kind", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "subject", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "lockouts", Type: DB_BigInt, Nullable: false},
+			{Name: "attempts", Type: DB_BigInt, Nullable: false},
+			{Name: "locked_until", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+			{Name: "updated", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"kind", "subject"}, Type: UniqueIndex},
+			{Cols: []string{"locked_until"}},
+		},
+	}
+
+	mg.AddMigration("create login lockout table", NewAddTableMigration(loginLockoutV1))
+	mg.AddMigration("add unique index login_lockout.kind_subject", NewAddIndexMigration(loginLockoutV1, loginLockoutV1.Indices[0]))
+	mg.AddMigration("add index login_lockout.locked_until", NewAddIndexMigration(loginLockoutV1, loginLockoutV1.Indices[1]))
 }
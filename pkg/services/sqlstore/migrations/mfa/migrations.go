@@ -0,0 +1,63 @@
+package mfa
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func AddMigration(mg *migrator.Migrator) {
+	userAuthFactorV1 := migrator.Table{
+		Name: "user_auth_factor",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "type", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "secret", Type: migrator.DB_Text, Nullable: true},
+			{Name: "credential_id", Type: migrator.DB_Text, Nullable: true},
+			{Name: "public_key", Type: migrator.DB_Text, Nullable: true},
+			{Name: "counter", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "verified", Type: migrator.DB_Bool, Nullable: false, Default: "0"},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "last_used", Type: migrator.DB_DateTime, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"user_id"}},
+		},
+	}
+
+	mg.AddMigration("create user_auth_factor table", migrator.NewAddTableMigration(userAuthFactorV1))
+	mg.AddMigration("add index user_auth_factor.user_id", migrator.NewAddIndexMigration(userAuthFactorV1, userAuthFactorV1.Indices[0]))
+
+	userAuthRecoveryCodeV1 := migrator.Table{
+		Name: "user_auth_recovery_code",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "code_hash", Type: migrator.DB_Char, Length: 44, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"user_id"}},
+		},
+	}
+
+	mg.AddMigration("create user_auth_recovery_code table", migrator.NewAddTableMigration(userAuthRecoveryCodeV1))
+	mg.AddMigration("add index user_auth_recovery_code.user_id", migrator.NewAddIndexMigration(userAuthRecoveryCodeV1, userAuthRecoveryCodeV1.Indices[0]))
+
+	userAuthChallengeV1 := migrator.Table{
+		Name: "user_auth_challenge",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "token_hash", Type: migrator.DB_Char, Length: 44, Nullable: false},
+			{Name: "attempts", Type: migrator.DB_Int, Nullable: false, Default: "0"},
+			{Name: "expires", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"token_hash"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"expires"}},
+		},
+	}
+
+	mg.AddMigration("create user_auth_challenge table", migrator.NewAddTableMigration(userAuthChallengeV1))
+	mg.AddMigration("add unique index user_auth_challenge.token_hash", migrator.NewAddIndexMigration(userAuthChallengeV1, userAuthChallengeV1.Indices[0]))
+	mg.AddMigration("add index user_auth_challenge.expires", migrator.NewAddIndexMigration(userAuthChallengeV1, userAuthChallengeV1.Indices[1]))
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addNotificationOutboxMigrations(mg *Migrator) {
+	outboxV1 := Table{
+		Name: "notification_outbox",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "kind", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "destination", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "target", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "subject", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "payload", Type: DB_MediumText, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "next_attempt", Type: DB_BigInt, Nullable: false},
+			{Name: "claimed_until", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"status", "next_attempt"}},
+		},
+	}
+
+	mg.AddMigration("create notification_outbox table", NewAddTableMigration(outboxV1))
+	mg.AddMigration("add index notification_outbox.status_next_attempt", NewAddIndexMigration(outboxV1, outboxV1.Indices[0]))
+}
@@ -60,6 +60,40 @@ func addPlaylistUIDMigration(mg *Migrator) {
 	}))
 }
 
+func addPlaylistScheduleMigrations(mg *Migrator) {
+	playlistItemV2 := Table{Name: "playlist_item"}
+
+	// Per item overrides of the playlist interval and of the time range of the dashboards
+	mg.AddMigration("Add playlist_item column duration", NewAddColumnMigration(playlistItemV2, &Column{
+		Name: "duration", Type: DB_NVarchar, Length: 32, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column time_from", NewAddColumnMigration(playlistItemV2, &Column{
+		Name: "time_from", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column time_to", NewAddColumnMigration(playlistItemV2, &Column{
+		Name: "time_to", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	playlistScheduleV1 := Table{
+		Name: "playlist_schedule",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "playlist_id", Type: DB_BigInt, Nullable: false},
+			{Name: "weekdays", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "time_from", Type: DB_NVarchar, Length: 5, Nullable: false},
+			{Name: "time_to", Type: DB_NVarchar, Length: 5, Nullable: false},
+			{Name: "timezone", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "kiosk", Type: DB_NVarchar, Length: 190, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"playlist_id"}},
+		},
+	}
+
+	mg.AddMigration("create playlist_schedule table v1", NewAddTableMigration(playlistScheduleV1))
+	mg.AddMigration("add index playlist_schedule.playlist_id", NewAddIndexMigration(playlistScheduleV1, playlistScheduleV1.Indices[0]))
+}
+
 func playlistV2() Table {
 	return Table{
 		Name: "playlist",
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addSavedQueryMigrations(mg *Migrator) {
+	savedQueryV1 := Table{
+		Name: "saved_query",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "datasource_type", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "queries", Type: DB_Text, Nullable: false},
+			{Name: "visibility", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "usage_count", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "last_used_at", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "datasource_uid"}},
+		},
+	}
+
+	mg.AddMigration("create saved_query table v1", NewAddTableMigration(savedQueryV1))
+	mg.AddMigration("add unique index saved_query.org_id-uid", NewAddIndexMigration(savedQueryV1, savedQueryV1.Indices[0]))
+	mg.AddMigration("add index saved_query.org_id-datasource_uid", NewAddIndexMigration(savedQueryV1, savedQueryV1.Indices[1]))
+
+	savedQueryTagV1 := Table{
+		Name: "saved_query_tag",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "query_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "term", Type: DB_NVarchar, Length: 50, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "query_uid"}},
+			{Cols: []string{"org_id", "term"}},
+		},
+	}
+
+	mg.AddMigration("create saved_query_tag table v1", NewAddTableMigration(savedQueryTagV1))
+	mg.AddMigration("add index saved_query_tag.org_id-query_uid", NewAddIndexMigration(savedQueryTagV1, savedQueryTagV1.Indices[0]))
+	mg.AddMigration("add index saved_query_tag.org_id-term", NewAddIndexMigration(savedQueryTagV1, savedQueryTagV1.Indices[1]))
+
+	savedQueryVersionV1 := Table{
+		Name: "saved_query_version",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "query_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "tags", Type: DB_Text, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "datasource_type", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "queries", Type: DB_Text, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "query_uid", "version"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create saved_query_version table v1", NewAddTableMigration(savedQueryVersionV1))
+	mg.AddMigration("add unique index saved_query_version.org_id-query_uid-version", NewAddIndexMigration(savedQueryVersionV1, savedQueryVersionV1.Indices[0]))
+}
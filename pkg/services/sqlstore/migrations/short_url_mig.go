@@ -28,4 +28,28 @@ func addShortURLMigrations(mg *Migrator) {
 	mg.AddMigration("alter table short_url alter column created_by type to bigint", NewRawSQLMigration("").
 		Mysql("ALTER TABLE short_url MODIFY created_by BIGINT;").
 		Postgres("ALTER TABLE short_url ALTER COLUMN created_by TYPE BIGINT;"))
+
+	mg.AddMigration("add expires_at column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "expires_at", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add max_uses column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "max_uses", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add use_count column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "use_count", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add password column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "password", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	mg.AddMigration("add salt column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "salt", Type: DB_NVarchar, Length: 50, Nullable: true,
+	}))
+
+	mg.AddMigration("add index short_url.org_id-created_by", NewAddIndexMigration(shortURLV1, &Index{
+		Cols: []string{"org_id", "created_by"},
+	}))
 }
@@ -39,8 +39,10 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses(di
 	}
 
 	orgID := f.user.GetOrgID()
-	filter, params := accesscontrol.UserRolesFilter(orgID, userID, f.user.GetTeams(), accesscontrol.GetOrgRoles(f.user), dialect)
-	rolesFilter := " AND role_id IN(SELECT id FROM role " + filter + ") "
+	expiry, params := accesscontrol.NotExpiredFilter("permission.expires")
+	filter, filterParams := accesscontrol.UserRolesFilter(orgID, userID, f.user.GetTeams(), accesscontrol.GetOrgRoles(f.user), dialect)
+	params = append(params, filterParams...)
+	rolesFilter := " AND " + expiry + " AND role_id IN(SELECT id FROM role " + filter + ") "
 	var args []any
 	builder := strings.Builder{}
 	builder.WriteRune('(')
@@ -68,8 +68,12 @@ var (
 					"group_search_filter":                "",
 					"group_search_filter_user_attribute": "",
 					"min_tls_version":                    "",
+					"nested_groups":                      "",
+					"nested_groups_member_attribute":     "",
+					"nested_groups_max_depth":            int64(0),
 					"root_ca_cert":                       "",
 					"root_ca_cert_value":                 nil,
+					"search_page_size":                   int64(0),
 					"start_tls":                          false,
 					"use_ssl":                            false,
 					"tls_ciphers":                        nil,
@@ -45,6 +45,7 @@ var (
 	allowed_groups =
 	team_ids = first, second
 	allowed_organizations = org1, org2
+	device_auth_url = test_device_auth_url
 	tls_skip_verify_insecure = true
 	tls_client_cert =
 	tls_client_key =
@@ -103,6 +104,7 @@ var (
 		"login_attribute_path":          "login",
 		"name_attribute_path":           "name",
 		"team_ids":                      "first, second",
+		"device_auth_url":               "test_device_auth_url",
 		"org_attribute_path":            "groups",
 		"org_mapping":                   "Group1:*:Editor",
 		"login_prompt":                  "select_account",
@@ -14,13 +14,22 @@ import (
 	"github.com/hashicorp/go-version"
 	"go.opentelemetry.io/otel/codes"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-const grafanaStableVersionURL = "https://grafana.com/api/grafana/versions/stable"
+const (
+	grafanaStableVersionURL = "https://grafana.com/api/grafana/versions/stable"
+
+	kvNamespace = "updatemanager"
+	// announcedVersionKey is the latest version that an UpdateAvailable event was published for
+	announcedVersionKey = "announced_version"
+)
 
 type GrafanaService struct {
 	hasUpdate     bool
@@ -30,11 +39,13 @@ type GrafanaService struct {
 	grafanaVersion string
 	httpClient     httpClient
 	mutex          sync.RWMutex
+	bus            bus.Bus
+	kvStore        *kvstore.NamespacedKVStore
 	log            log.Logger
 	tracer         tracing.Tracer
 }
 
-func ProvideGrafanaService(cfg *setting.Cfg, tracer tracing.Tracer) (*GrafanaService, error) {
+func ProvideGrafanaService(cfg *setting.Cfg, tracer tracing.Tracer, bus bus.Bus, kv kvstore.KVStore) (*GrafanaService, error) {
 	logger := log.New("grafana.update.checker")
 	cl, err := httpclient.New(httpclient.Options{
 		Middlewares: []httpclient.Middleware{
@@ -48,6 +59,8 @@ func ProvideGrafanaService(cfg *setting.Cfg, tracer tracing.Tracer) (*GrafanaSer
 		enabled:        cfg.CheckForGrafanaUpdates,
 		grafanaVersion: cfg.BuildVersion,
 		httpClient:     cl,
+		bus:            bus,
+		kvStore:        kvstore.WithNamespace(kv, 0, kvNamespace),
 		log:            logger,
 		tracer:         tracer,
 	}, nil
@@ -120,7 +133,6 @@ func (s *GrafanaService) checkForUpdates(ctx context.Context) error {
 	}
 
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
 	// only check for updates in stable versions
 	if !strings.Contains(s.grafanaVersion, "-") {
 		s.latestVersion = latest.Version
@@ -133,9 +145,43 @@ func (s *GrafanaService) checkForUpdates(ctx context.Context) error {
 		s.hasUpdate = currVersion.LessThan(latestVersion)
 	}
 
+	hasUpdate := s.hasUpdate
+	evt := &events.UpdateAvailable{
+		Timestamp:      time.Now(),
+		CurrentVersion: s.grafanaVersion,
+		LatestVersion:  s.latestVersion,
+	}
+	s.mutex.Unlock()
+
+	if hasUpdate {
+		s.announceUpdate(ctx, evt)
+	}
+
 	return nil
 }
 
+// announceUpdate publishes an UpdateAvailable event for every new version only once. The announced
+// version is stored in the database so that restarts and other instances don't announce it again.
+func (s *GrafanaService) announceUpdate(ctx context.Context, evt *events.UpdateAvailable) {
+	ctxLogger := s.log.FromContext(ctx)
+	announced, _, err := s.kvStore.Get(ctx, announcedVersionKey)
+	if err != nil {
+		ctxLogger.Warn("Failed to get the announced version", "error", err)
+		return
+	}
+	if announced == evt.LatestVersion {
+		return
+	}
+
+	if err := s.kvStore.Set(ctx, announcedVersionKey, evt.LatestVersion); err != nil {
+		ctxLogger.Warn("Failed to save the announced version", "error", err)
+		return
+	}
+	if err := s.bus.Publish(ctx, evt); err != nil {
+		ctxLogger.Warn("Failed to publish update available event", "error", err)
+	}
+}
+
 func (s *GrafanaService) UpdateAvailable() bool {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -5,6 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/stretchr/testify/require"
@@ -32,6 +35,8 @@ func TestGrafanaService(t *testing.T) {
 				enabled:        true,
 				grafanaVersion: grafanaVersion,
 				httpClient:     httpClient,
+				bus:            bus.ProvideBus(tracing.NewNoopTracerService()),
+				kvStore:        kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, kvNamespace),
 				log:            log.NewNopLogger(),
 				tracer:         tracing.NewNoopTracerService(),
 			}
@@ -58,6 +63,8 @@ func TestGrafanaService(t *testing.T) {
 				enabled:        true,
 				grafanaVersion: grafanaVersion,
 				httpClient:     httpClient,
+				bus:            bus.ProvideBus(tracing.NewNoopTracerService()),
+				kvStore:        kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, kvNamespace),
 				log:            log.NewNopLogger(),
 				tracer:         tracing.NewNoopTracerService(),
 			}
@@ -85,6 +92,8 @@ func TestGrafanaService(t *testing.T) {
 			enabled:        true,
 			grafanaVersion: grafanaVersion,
 			httpClient:     httpClient,
+			bus:            bus.ProvideBus(tracing.NewNoopTracerService()),
+			kvStore:        kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, kvNamespace),
 			log:            log.NewNopLogger(),
 			tracer:         tracing.NewNoopTracerService(),
 		}
@@ -98,6 +107,47 @@ func TestGrafanaService(t *testing.T) {
 	})
 }
 
+func TestGrafanaService_UpdateAvailableEvent(t *testing.T) {
+	ctx := context.Background()
+	kv := kvstore.NewFakeKVStore()
+	b := bus.ProvideBus(tracing.NewNoopTracerService())
+	newService := func(latestVersion string) *GrafanaService {
+		return &GrafanaService{
+			enabled:        true,
+			grafanaVersion: "99.0.0",
+			httpClient:     &fakeHTTPClient{fakeResp: `{"version": "` + latestVersion + `"}`},
+			bus:            b,
+			kvStore:        kvstore.WithNamespace(kv, 0, kvNamespace),
+			log:            log.NewNopLogger(),
+			tracer:         tracing.NewNoopTracerService(),
+		}
+	}
+
+	published := []*events.UpdateAvailable{}
+	b.AddEventListener(func(ctx context.Context, evt *events.UpdateAvailable) error {
+		published = append(published, evt)
+		return nil
+	})
+
+	service := newService("99.0.1")
+	require.NoError(t, service.checkForUpdates(ctx))
+	require.Len(t, published, 1)
+	require.Equal(t, "99.0.0", published[0].CurrentVersion)
+	require.Equal(t, "99.0.1", published[0].LatestVersion)
+
+	// the same version is only announced once
+	require.NoError(t, service.checkForUpdates(ctx))
+	require.Len(t, published, 1)
+
+	// the announced version is persisted across restarts
+	require.NoError(t, newService("99.0.1").checkForUpdates(ctx))
+	require.Len(t, published, 1)
+
+	require.NoError(t, newService("99.0.2").checkForUpdates(ctx))
+	require.Len(t, published, 2)
+	require.Equal(t, "99.0.2", published[1].LatestVersion)
+}
+
 func TestGrafanaService_Run(t *testing.T) {
 	latestVersion := "99.0.1"
 
@@ -107,8 +157,10 @@ func TestGrafanaService_Run(t *testing.T) {
 		httpClient: &fakeHTTPClient{
 			fakeResp: `{"version": "` + latestVersion + `"}`,
 		},
-		log:    log.NewNopLogger(),
-		tracer: tracing.NewNoopTracerService(),
+		bus:     bus.ProvideBus(tracing.NewNoopTracerService()),
+		kvStore: kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, kvNamespace),
+		log:     log.NewNopLogger(),
+		tracer:  tracing.NewNoopTracerService(),
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
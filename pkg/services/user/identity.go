@@ -43,6 +43,8 @@ type SignedInUser struct {
 	Teams            []int64
 	// Permissions grouped by orgID and actions
 	Permissions map[int64]map[string][]string `json:"-"`
+	// RestrictedPermissions the user is restricted to by the credentials it authenticated with, grouped by action
+	RestrictedPermissions map[string][]string `json:"-" xorm:"-"`
 
 	// IDToken is a signed token representing the identity that can be forwarded to plugins and external services.
 	IDToken           string                                       `json:"-" xorm:"-"`
@@ -53,6 +55,12 @@ type SignedInUser struct {
 	FallbackType claims.IdentityType
 }
 
+// GetRestrictedPermissions returns the permissions the user is restricted to by the credentials it authenticated with,
+// grouped by action. It is empty when the user isn't restricted.
+func (u *SignedInUser) GetRestrictedPermissions() map[string][]string {
+	return u.RestrictedPermissions
+}
+
 func (u *SignedInUser) GetID() string {
 	ns, id := u.getTypeAndID()
 	return claims.NewTypeID(ns, id)
@@ -187,6 +187,17 @@ type Cfg struct {
 	DataProxyWhiteList              map[string]bool
 	ActionsAllowPostURL             string
 
+	// Progressive brute force login protection, the duration of a lockout doubles
+	// for every consecutive lockout until the max lockout duration is reached
+	DisableSubnetLoginProtection                bool
+	BruteForceLoginProtectionSubnetMaxAttempts  int64
+	BruteForceLoginProtectionIPv4SubnetPrefix   int
+	BruteForceLoginProtectionIPv6SubnetPrefix   int
+	BruteForceLoginProtectionLockoutDuration    time.Duration
+	BruteForceLoginProtectionMaxLockoutDuration time.Duration
+	BruteForceLoginProtectionNotifyUser         bool
+	BruteForceLoginProtectionNotifyAdmins       bool
+
 	// K8s Dashboard Cleanup
 	K8sDashboardCleanup K8sDashboardCleanupSettings
 
@@ -278,12 +289,16 @@ type Cfg struct {
 	OAuthCookieMaxAge                    int
 	OAuthAllowInsecureEmailLookup        bool
 	OAuthRefreshTokenServerLockMinWaitMs int64
+	// OAuthDeviceLoginEnabled allows devices to log in with the device authorization grant of the generic OAuth provider
+	OAuthDeviceLoginEnabled bool
 
 	JWTAuth    AuthJWTSettings
 	ExtJWTAuth ExtJWTSettings
 
 	PasswordlessMagicLinkAuth AuthPasswordlessMagicLinkSettings
 
+	AuthMFA AuthMFASettings
+
 	// SSO Settings Auth
 	SSOSettingsReloadInterval        time.Duration
 	SSOSettingsConfigurableProviders map[string]bool
@@ -360,6 +375,7 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	SqlDatasourceSQLiteAllowedPaths     []string
 
 	// Snapshots
 	SnapshotEnabled      bool
@@ -1324,6 +1340,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.readAuthProxySettings()
 	cfg.readSessionConfig()
 	cfg.readPasswordlessMagicLinkSettings()
+	cfg.readAuthMFASettings()
 	if err := cfg.readSmtpSettings(); err != nil {
 		return err
 	}
@@ -1595,11 +1612,28 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	cfg.BruteForceLoginProtectionMaxAttempts = security.Key("brute_force_login_protection_max_attempts").MustInt64(5)
 	cfg.DisableUsernameLoginProtection = security.Key("disable_username_login_protection").MustBool(false)
 	cfg.DisableIPAddressLoginProtection = security.Key("disable_ip_address_login_protection").MustBool(true)
+	cfg.DisableSubnetLoginProtection = security.Key("disable_subnet_login_protection").MustBool(true)
+	cfg.BruteForceLoginProtectionSubnetMaxAttempts = security.Key("brute_force_login_protection_subnet_max_attempts").MustInt64(50)
+	cfg.BruteForceLoginProtectionIPv4SubnetPrefix = security.Key("brute_force_login_protection_ipv4_subnet_prefix").RangeInt(24, 0, 32)
+	cfg.BruteForceLoginProtectionIPv6SubnetPrefix = security.Key("brute_force_login_protection_ipv6_subnet_prefix").RangeInt(64, 0, 128)
+	cfg.BruteForceLoginProtectionLockoutDuration = security.Key("brute_force_login_protection_lockout_duration").MustDuration(5 * time.Minute)
+	cfg.BruteForceLoginProtectionMaxLockoutDuration = security.Key("brute_force_login_protection_max_lockout_duration").MustDuration(24 * time.Hour)
+	cfg.BruteForceLoginProtectionNotifyUser = security.Key("brute_force_login_protection_notify_user").MustBool(false)
+	cfg.BruteForceLoginProtectionNotifyAdmins = security.Key("brute_force_login_protection_notify_admins").MustBool(false)
 
 	// Ensure at least one login attempt can be performed.
 	if cfg.BruteForceLoginProtectionMaxAttempts <= 0 {
 		cfg.BruteForceLoginProtectionMaxAttempts = 1
 	}
+	if cfg.BruteForceLoginProtectionSubnetMaxAttempts <= 0 {
+		cfg.BruteForceLoginProtectionSubnetMaxAttempts = 1
+	}
+	if cfg.BruteForceLoginProtectionLockoutDuration <= 0 {
+		cfg.BruteForceLoginProtectionLockoutDuration = 5 * time.Minute
+	}
+	if cfg.BruteForceLoginProtectionMaxLockoutDuration < cfg.BruteForceLoginProtectionLockoutDuration {
+		cfg.BruteForceLoginProtectionMaxLockoutDuration = cfg.BruteForceLoginProtectionLockoutDuration
+	}
 
 	CookieSecure = security.Key("cookie_secure").MustBool(false)
 	cfg.CookieSecure = CookieSecure
@@ -1681,6 +1715,7 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	}
 
 	cfg.OAuthAllowInsecureEmailLookup = auth.Key("oauth_allow_insecure_email_lookup").MustBool(false)
+	cfg.OAuthDeviceLoginEnabled = auth.Key("oauth_device_login_enabled").MustBool(false)
 
 	const defaultMaxLifetime = "30d"
 	maxLifetimeDurationVal := valueAsString(auth, "login_maximum_lifetime_duration", defaultMaxLifetime)
@@ -2013,6 +2048,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqlDatasourceSQLiteAllowedPaths = util.SplitString(sqlDatasources.Key("sqlite_allowed_paths").MustString(""))
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
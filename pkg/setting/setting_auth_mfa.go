@@ -0,0 +1,49 @@
+package setting
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type AuthMFASettings struct {
+	// Enabled requires users with a second factor to verify it when they log in with a username and password.
+	Enabled bool
+	// TOTPIssuer is the issuer shown in authenticator apps.
+	TOTPIssuer string
+	// WebAuthnRPID is the WebAuthn relying party ID, which is the domain that security keys are registered for.
+	WebAuthnRPID string
+	// WebAuthnRPOrigins are the origins that WebAuthn requests are accepted from.
+	WebAuthnRPOrigins []string
+	// ChallengeExpiration is how long the second step of a login is valid.
+	ChallengeExpiration time.Duration
+	// EnforceForAdmins requires server admins and organization admins to enroll a second factor when they log in.
+	EnforceForAdmins bool
+}
+
+func (cfg *Cfg) readAuthMFASettings() {
+	section := cfg.SectionWithEnvOverrides("auth.mfa")
+	settings := AuthMFASettings{}
+	settings.Enabled = section.Key("enabled").MustBool(false)
+	settings.TOTPIssuer = section.Key("totp_issuer").MustString("Grafana")
+	settings.ChallengeExpiration = section.Key("challenge_expiration").MustDuration(5 * time.Minute)
+	settings.EnforceForAdmins = section.Key("enforce_for_admins").MustBool(false)
+
+	var appURL *url.URL
+	if u, err := url.Parse(cfg.AppURL); err == nil {
+		appURL = u
+	}
+
+	settings.WebAuthnRPID = section.Key("webauthn_rp_id").MustString("")
+	if settings.WebAuthnRPID == "" && appURL != nil {
+		settings.WebAuthnRPID = appURL.Hostname()
+	}
+
+	settings.WebAuthnRPOrigins = util.SplitString(section.Key("webauthn_rp_origins").MustString(""))
+	if len(settings.WebAuthnRPOrigins) == 0 && appURL != nil {
+		settings.WebAuthnRPOrigins = []string{appURL.Scheme + "://" + appURL.Host}
+	}
+
+	cfg.AuthMFA = settings
+}
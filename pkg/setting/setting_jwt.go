@@ -1,8 +1,12 @@
 package setting
 
 import (
+	"slices"
+	"strings"
 	"time"
 
+	"gopkg.in/ini.v1"
+
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -34,6 +38,82 @@ type AuthJWTSettings struct {
 	EmailAttributePath      string
 	UsernameAttributePath   string
 	TlsSkipVerify           bool
+
+	// Issuers lists the additional trusted token issuers configured in
+	// [auth.jwt.issuer.<name>] sections. Tokens are matched to an issuer
+	// by their iss claim.
+	Issuers []AuthJWTIssuerSettings
+}
+
+// AuthJWTIssuerSettings holds the key source, claim expectations and
+// claim mappings used for tokens signed by a single issuer.
+type AuthJWTIssuerSettings struct {
+	Name                    string
+	Issuer                  string
+	Audience                []string
+	ExpectClaims            string
+	JWKSetURL               string
+	JWKSetFile              string
+	KeyFile                 string
+	KeyID                   string
+	CacheTTL                time.Duration
+	TlsSkipVerify           bool
+	EmailClaim              string
+	UsernameClaim           string
+	EmailAttributePath      string
+	UsernameAttributePath   string
+	AutoSignUp              bool
+	RoleAttributePath       string
+	RoleAttributeStrict     bool
+	OrgMapping              []string
+	OrgAttributePath        string
+	AllowAssignGrafanaAdmin bool
+	SkipOrgRoleSync         bool
+	GroupsAttributePath     string
+}
+
+// DefaultJWTIssuerName is the name of the issuer built from the keys of
+// the [auth.jwt] section itself.
+const DefaultJWTIssuerName = "default"
+
+// DefaultIssuer returns the issuer described by the [auth.jwt] section.
+// It is used for tokens whose iss claim matches none of the Issuers.
+func (s AuthJWTSettings) DefaultIssuer() AuthJWTIssuerSettings {
+	return AuthJWTIssuerSettings{
+		Name:                    DefaultJWTIssuerName,
+		ExpectClaims:            s.ExpectClaims,
+		JWKSetURL:               s.JWKSetURL,
+		JWKSetFile:              s.JWKSetFile,
+		KeyFile:                 s.KeyFile,
+		KeyID:                   s.KeyID,
+		CacheTTL:                s.CacheTTL,
+		TlsSkipVerify:           s.TlsSkipVerify,
+		EmailClaim:              s.EmailClaim,
+		UsernameClaim:           s.UsernameClaim,
+		EmailAttributePath:      s.EmailAttributePath,
+		UsernameAttributePath:   s.UsernameAttributePath,
+		AutoSignUp:              s.AutoSignUp,
+		RoleAttributePath:       s.RoleAttributePath,
+		RoleAttributeStrict:     s.RoleAttributeStrict,
+		OrgMapping:              s.OrgMapping,
+		OrgAttributePath:        s.OrgAttributePath,
+		AllowAssignGrafanaAdmin: s.AllowAssignGrafanaAdmin,
+		SkipOrgRoleSync:         s.SkipOrgRoleSync,
+		GroupsAttributePath:     s.GroupsAttributePath,
+	}
+}
+
+// IssuerFor returns the settings of the issuer matching the iss claim of
+// a token, falling back to the default issuer.
+func (s AuthJWTSettings) IssuerFor(iss string) AuthJWTIssuerSettings {
+	if iss != "" {
+		for _, issuer := range s.Issuers {
+			if issuer.Issuer == iss {
+				return issuer
+			}
+		}
+	}
+	return s.DefaultIssuer()
 }
 
 type ExtJWTSettings struct {
@@ -80,5 +160,55 @@ func (cfg *Cfg) readAuthJWTSettings() {
 	jwtSettings.OrgAttributePath = valueAsString(authJWT, "org_attribute_path", "")
 	jwtSettings.OrgMapping = util.SplitString(valueAsString(authJWT, "org_mapping", ""))
 
+	const issuerSectionPrefix = "auth.jwt.issuer."
+	for _, section := range cfg.Raw.Sections() {
+		if !strings.HasPrefix(section.Name(), issuerSectionPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(section.Name(), issuerSectionPrefix)
+		jwtSettings.Issuers = append(jwtSettings.Issuers, readAuthJWTIssuerSettings(name, section))
+	}
+
 	cfg.JWTAuth = jwtSettings
 }
+
+// readAuthJWTIssuerSettings reads an [auth.jwt.issuer.<name>] section. As a
+// child section it inherits the claim mappings of [auth.jwt], while the key
+// source and claim expectations are only read from the section itself.
+func readAuthJWTIssuerSettings(name string, section *ini.Section) AuthJWTIssuerSettings {
+	ownValueAsString := func(keyName string, defaultValue string) string {
+		if !slices.Contains(section.KeyStrings(), keyName) {
+			return defaultValue
+		}
+		return valueAsString(section, keyName, defaultValue)
+	}
+
+	issuer := AuthJWTIssuerSettings{
+		Name:                    name,
+		Issuer:                  ownValueAsString("issuer", ""),
+		Audience:                util.SplitString(ownValueAsString("audience", "")),
+		ExpectClaims:            ownValueAsString("expect_claims", "{}"),
+		JWKSetURL:               ownValueAsString("jwk_set_url", ""),
+		JWKSetFile:              ownValueAsString("jwk_set_file", ""),
+		KeyFile:                 ownValueAsString("key_file", ""),
+		KeyID:                   ownValueAsString("key_id", ""),
+		CacheTTL:                section.Key("cache_ttl").MustDuration(time.Minute * 60),
+		EmailClaim:              valueAsString(section, "email_claim", ""),
+		UsernameClaim:           valueAsString(section, "username_claim", ""),
+		EmailAttributePath:      valueAsString(section, "email_attribute_path", ""),
+		UsernameAttributePath:   valueAsString(section, "username_attribute_path", ""),
+		AutoSignUp:              section.Key("auto_sign_up").MustBool(false),
+		RoleAttributePath:       valueAsString(section, "role_attribute_path", ""),
+		RoleAttributeStrict:     section.Key("role_attribute_strict").MustBool(false),
+		OrgMapping:              util.SplitString(valueAsString(section, "org_mapping", "")),
+		OrgAttributePath:        valueAsString(section, "org_attribute_path", ""),
+		AllowAssignGrafanaAdmin: section.Key("allow_assign_grafana_admin").MustBool(false),
+		SkipOrgRoleSync:         section.Key("skip_org_role_sync").MustBool(false),
+		GroupsAttributePath:     valueAsString(section, "groups_attribute_path", ""),
+	}
+	if slices.Contains(section.KeyStrings(), "tls_skip_verify_insecure") {
+		issuer.TlsSkipVerify = section.Key("tls_skip_verify_insecure").MustBool(false)
+	}
+
+	return issuer
+}
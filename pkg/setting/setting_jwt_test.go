@@ -0,0 +1,62 @@
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadAuthJWTIssuers(t *testing.T) {
+	f, err := ini.Load([]byte(`
+[auth.jwt]
+enabled = true
+email_claim = email
+role_attribute_path = role
+org_mapping = org1:Org1:Viewer
+cache_ttl = 30m
+jwk_set_url = https://default.example.com/jwks.json
+
+[auth.jwt.issuer.legacy]
+issuer = https://legacy.example.com
+key_file = /path/to/key.pem
+
+[auth.jwt.issuer.next]
+issuer = https://next.example.com
+audience = grafana, grafana-api
+jwk_set_url = https://next.example.com/jwks.json
+username_claim = preferred_username
+org_mapping = team1:Org2:Editor
+auto_sign_up = true
+`))
+	require.NoError(t, err)
+
+	cfg := NewCfg()
+	cfg.Raw = f
+	cfg.readAuthJWTSettings()
+
+	require.Len(t, cfg.JWTAuth.Issuers, 2)
+
+	legacy := cfg.JWTAuth.Issuers[0]
+	assert.Equal(t, "legacy", legacy.Name)
+	assert.Equal(t, "https://legacy.example.com", legacy.Issuer)
+	assert.Equal(t, "/path/to/key.pem", legacy.KeyFile)
+	assert.Empty(t, legacy.JWKSetURL)
+	assert.Equal(t, "{}", legacy.ExpectClaims)
+	assert.Equal(t, 30*time.Minute, legacy.CacheTTL)
+	assert.Equal(t, "email", legacy.EmailClaim)
+	assert.Equal(t, "role", legacy.RoleAttributePath)
+	assert.Equal(t, []string{"org1:Org1:Viewer"}, legacy.OrgMapping)
+
+	next := cfg.JWTAuth.Issuers[1]
+	assert.Equal(t, []string{"grafana", "grafana-api"}, next.Audience)
+	assert.Equal(t, "preferred_username", next.UsernameClaim)
+	assert.Equal(t, []string{"team1:Org2:Editor"}, next.OrgMapping)
+	assert.True(t, next.AutoSignUp)
+
+	assert.Equal(t, "next", cfg.JWTAuth.IssuerFor("https://next.example.com").Name)
+	assert.Equal(t, DefaultJWTIssuerName, cfg.JWTAuth.IssuerFor("https://other.example.com").Name)
+	assert.Equal(t, "https://default.example.com/jwks.json", cfg.JWTAuth.IssuerFor("").JWKSetURL)
+}
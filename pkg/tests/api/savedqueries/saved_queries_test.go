@@ -0,0 +1,102 @@
+package savedqueries
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/queryhistory"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tests"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSavedQueries(t *testing.T) {
+	tests.SkipIntegrationTestInShortMode(t)
+
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		AppModeProduction: true,
+		DisableAnonymous:  true,
+	})
+	grafanaListedAddr, env := testinfra.StartGrafanaEnv(t, dir, path)
+
+	tests.CreateUser(t, env.SQLStore, env.Cfg, user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleEditor),
+		Login:          "editor",
+		Password:       "editor",
+	})
+	tests.CreateUser(t, env.SQLStore, env.Cfg, user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleViewer),
+		Login:          "viewer",
+		Password:       "viewer",
+	})
+
+	editor := fmt.Sprintf("http://editor:editor@%s", grafanaListedAddr)
+	viewer := fmt.Sprintf("http://viewer:viewer@%s", grafanaListedAddr)
+
+	var created queryhistory.SavedQueryResponse
+	status := doRequest(t, http.MethodPost, editor+"/api/saved-queries", `{
+		"title": "Request rate",
+		"datasourceUid": "prometheus",
+		"visibility": "org",
+		"queries": [{"expr": "rate(http_requests_total[5m])"}]
+	}`, &created)
+	require.Equal(t, http.StatusOK, status)
+	uid := created.Result.UID
+	require.NotEmpty(t, uid)
+
+	t.Run("viewers can search and use saved queries of the organization", func(t *testing.T) {
+		var search queryhistory.SavedQuerySearchResponse
+		require.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, viewer+"/api/saved-queries", "", &search))
+		require.Len(t, search.Result.SavedQueries, 1)
+		assert.Equal(t, uid, search.Result.SavedQueries[0].UID)
+
+		require.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, viewer+"/api/saved-queries/"+uid, "", nil))
+		require.Equal(t, http.StatusOK, doRequest(t, http.MethodPost, viewer+"/api/saved-queries/"+uid+"/use", "", nil))
+	})
+
+	t.Run("viewers can't change saved queries", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, doRequest(t, http.MethodPost, viewer+"/api/saved-queries", `{"title": "Viewer", "queries": [{}]}`, nil))
+		assert.Equal(t, http.StatusForbidden, doRequest(t, http.MethodPut, viewer+"/api/saved-queries/"+uid, `{"title": "Changed", "queries": [{}]}`, nil))
+		assert.Equal(t, http.StatusForbidden, doRequest(t, http.MethodDelete, viewer+"/api/saved-queries/"+uid, "", nil))
+	})
+
+	t.Run("editors can delete saved queries", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, doRequest(t, http.MethodDelete, editor+"/api/saved-queries/"+uid, "", nil))
+		assert.Equal(t, http.StatusNotFound, doRequest(t, http.MethodGet, editor+"/api/saved-queries/"+uid, "", nil))
+	})
+}
+
+func doRequest(t *testing.T, method, url, body string, result any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	// nolint:gosec
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if result != nil && resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.Unmarshal(raw, result))
+	}
+	return resp.StatusCode
+}
@@ -2,7 +2,9 @@ package dashboards
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -320,3 +322,91 @@ func TestIntegrationLegacySupport(t *testing.T) {
 	}, &dtos.DashboardFullWithMeta{})
 	require.Equal(t, 406, rsp.Response.StatusCode) // not acceptable
 }
+
+func TestIntegrationRestrictedServiceAccountToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	gvr := dashboardV1.DashboardResourceInfo.GroupVersionResource()
+
+	for _, mode := range []rest.DualWriterMode{rest.Mode0, rest.Mode5} {
+		t.Run(fmt.Sprintf("with dual writer mode %d", mode), func(t *testing.T) {
+			ctx := context.Background()
+			helper := apis.NewK8sTestHelper(t, testinfra.GrafanaOpts{
+				UnifiedStorageConfig: map[string]setting.UnifiedStorageConfig{
+					"dashboards.dashboard.grafana.app": {
+						DualWriterMode: mode,
+					},
+				},
+			})
+
+			adminClient := helper.GetResourceClient(apis.ResourceClientArgs{
+				User: helper.Org1.Admin,
+				GVR:  gvr,
+			})
+			for _, name := range []string{"allowed", "denied"} {
+				obj := &unstructured.Unstructured{
+					Object: map[string]any{
+						"spec": map[string]any{
+							"title":         name,
+							"schemaVersion": 41,
+						},
+					},
+				}
+				obj.SetName(name)
+				obj.SetAPIVersion(gvr.GroupVersion().String())
+				obj.SetKind("Dashboard")
+				_, err := adminClient.Resource.Create(ctx, obj, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			body, err := json.Marshal(map[string]any{
+				"name":        "restricted-token",
+				"permissions": map[string][]string{"dashboards:read": {"dashboards:uid:allowed"}},
+			})
+			require.NoError(t, err)
+			rsp := apis.DoRequest(helper, apis.RequestParams{
+				User:   helper.Org1.Admin,
+				Method: http.MethodPost,
+				Path:   fmt.Sprintf("/api/serviceaccounts/%d/tokens", helper.Org1.AdminServiceAccount.Id),
+				Body:   body,
+			}, &struct {
+				Key string `json:"key"`
+			}{})
+			require.Equal(t, http.StatusOK, rsp.Response.StatusCode, string(rsp.Body))
+
+			client := helper.GetResourceClient(apis.ResourceClientArgs{
+				ServiceAccountToken: rsp.Result.Key,
+				Namespace:           helper.Namespacer(helper.Org1.Admin.Identity.GetOrgID()),
+				GVR:                 gvr,
+			})
+
+			t.Run("should get the dashboard the token is restricted to", func(t *testing.T) {
+				obj, err := client.Resource.Get(ctx, "allowed", metav1.GetOptions{})
+				require.NoError(t, err)
+				require.Equal(t, "allowed", obj.GetName())
+			})
+
+			t.Run("should deny other dashboards the service account can read", func(t *testing.T) {
+				_, err := client.Resource.Get(ctx, "denied", metav1.GetOptions{})
+				require.Equal(t, int32(http.StatusForbidden), helper.AsStatusError(err).ErrStatus.Code)
+
+				// the service account itself can still read it with an unrestricted token
+				unrestricted := helper.GetResourceClient(apis.ResourceClientArgs{
+					ServiceAccountToken: helper.Org1.AdminServiceAccountToken,
+					Namespace:           helper.Namespacer(helper.Org1.Admin.Identity.GetOrgID()),
+					GVR:                 gvr,
+				})
+				_, err = unrestricted.Resource.Get(ctx, "denied", metav1.GetOptions{})
+				require.NoError(t, err)
+			})
+
+			t.Run("should only list the dashboards the token is restricted to", func(t *testing.T) {
+				list, err := client.Resource.List(ctx, metav1.ListOptions{})
+				require.NoError(t, err)
+				require.Len(t, list.Items, 1)
+				require.Equal(t, "allowed", list.Items[0].GetName())
+			})
+		})
+	}
+}
@@ -68,6 +68,17 @@ func TestIntegrationPlaylist(t *testing.T) {
 				  "scope": "Namespaced",
 				  "singularResource": "playlist",
 				  "subresources": [
+					{
+					  "responseKind": {
+						"group": "",
+						"kind": "ResourceCallOptions",
+						"version": ""
+					  },
+					  "subresource": "scheduled",
+					  "verbs": [
+						"get"
+					  ]
+					},
 					{
 					  "responseKind": {
 						"group": "",
@@ -702,6 +713,76 @@ func doPlaylistTests(t *testing.T, helper *apis.K8sTestHelper) *apis.K8sTestHelp
 		require.Empty(t, list.Items)
 	})
 
+	t.Run("Get the scheduled playlist", func(t *testing.T) {
+		client := helper.GetResourceClient(apis.ResourceClientArgs{
+			User: helper.Org1.Editor,
+			GVR:  gvr,
+		})
+		scheduledPath := "/apis/playlist.grafana.app/v0alpha1/namespaces/default/scheduled?kiosk=lobby"
+
+		// Nothing is scheduled yet
+		rsp := apis.DoRequest(helper, apis.RequestParams{
+			User:   helper.Org1.Viewer,
+			Method: http.MethodGet,
+			Path:   scheduledPath,
+		}, &map[string]any{})
+		require.Equal(t, http.StatusNotFound, rsp.Response.StatusCode)
+
+		// The two schedules cover the whole day
+		_, err := client.Resource.Create(context.Background(), &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": gvr.GroupVersion().String(),
+			"kind":       "Playlist",
+			"metadata":   map[string]any{"name": "lobby"},
+			"spec": map[string]any{
+				"title":    "Lobby",
+				"interval": "5m",
+				"items":    []any{map[string]any{"type": "dashboard_by_tag", "value": "lobby"}},
+				"schedules": []any{
+					map[string]any{"from": "00:00", "to": "12:00", "kiosk": "lobby"},
+					map[string]any{"from": "12:00", "to": "00:00", "kiosk": "lobby"},
+				},
+			},
+		}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		scheduled := apis.DoRequest(helper, apis.RequestParams{
+			User:   helper.Org1.Viewer,
+			Method: http.MethodGet,
+			Path:   scheduledPath,
+		}, &struct {
+			Name string `json:"name"`
+		}{})
+		require.Equal(t, http.StatusOK, scheduled.Response.StatusCode, string(scheduled.Body))
+		require.Equal(t, "lobby", scheduled.Result.Name)
+
+		// The legacy endpoint returns the same playlist
+		legacy := apis.DoRequest(helper, apis.RequestParams{
+			User:   helper.Org1.Viewer,
+			Method: http.MethodGet,
+			Path:   "/api/playlists-scheduled?kiosk=lobby",
+		}, &playlist.PlaylistDTO{})
+		require.Equal(t, http.StatusOK, legacy.Response.StatusCode)
+		require.Equal(t, "lobby", legacy.Result.Uid)
+
+		// Other kiosk devices have no schedule
+		rsp = apis.DoRequest(helper, apis.RequestParams{
+			User:   helper.Org1.Viewer,
+			Method: http.MethodGet,
+			Path:   "/apis/playlist.grafana.app/v0alpha1/namespaces/default/scheduled?kiosk=office",
+		}, &map[string]any{})
+		require.Equal(t, http.StatusNotFound, rsp.Response.StatusCode)
+
+		// Users of other orgs can't read the schedules of the namespace
+		rsp = apis.DoRequest(helper, apis.RequestParams{
+			User:   helper.OrgB.Viewer,
+			Method: http.MethodGet,
+			Path:   scheduledPath,
+		}, &map[string]any{})
+		require.Equal(t, http.StatusForbidden, rsp.Response.StatusCode)
+
+		require.NoError(t, client.Resource.Delete(context.Background(), "lobby", metav1.DeleteOptions{}))
+	})
+
 	return helper
 }
 
@@ -20,6 +20,8 @@ type JaegerQuery struct {
 	MinDuration string `json:"minDuration"`
 	MaxDuration string `json:"maxDuration"`
 	Limit       int    `json:"limit"`
+	// UploadedJson is the content of an uploaded trace file, used by the upload query type
+	UploadedJson string `json:"uploadedJson,omitempty"`
 }
 
 func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
@@ -38,10 +40,14 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 
 		// Handle "Upload" query type
 		if query.QueryType == "upload" {
-			logger.Debug("upload query type is not supported in backend mode")
+			frames, err := transformUpload(query.UploadedJson, q.RefID)
+			if err != nil {
+				logger.Debug("Failed to parse uploaded trace", "error", err)
+				response.Responses[q.RefID] = backend.ErrorResponseWithErrorSource(err)
+				continue
+			}
 			response.Responses[q.RefID] = backend.DataResponse{
-				Error:       fmt.Errorf("unsupported query type %s. only available in frontend mode", query.QueryType),
-				ErrorSource: backend.ErrorSourceDownstream,
+				Frames: frames,
 			}
 			continue
 		}
@@ -0,0 +1,212 @@
+package jaeger
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var errInvalidUpload = errors.New("the JSON file uploaded is not in a valid Jaeger or OTLP format")
+
+// transformUpload converts an uploaded trace file into a trace frame. Both the Jaeger
+// JSON format, as downloaded from the Jaeger UI or API, and OTLP JSON are supported.
+func transformUpload(uploadedJson string, refID string) (data.Frames, error) {
+	if uploadedJson == "" {
+		return data.Frames{}, nil
+	}
+
+	var probe struct {
+		Data          json.RawMessage `json:"data"`
+		ResourceSpans json.RawMessage `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal([]byte(uploadedJson), &probe); err != nil {
+		return nil, backend.DownstreamError(fmt.Errorf("%w: %w", errInvalidUpload, err))
+	}
+
+	var trace TraceResponse
+	switch {
+	case probe.Data != nil:
+		var traces []TraceResponse
+		if err := json.Unmarshal(probe.Data, &traces); err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("%w: %w", errInvalidUpload, err))
+		}
+		if len(traces) == 0 {
+			return nil, backend.DownstreamError(fmt.Errorf("%w: no trace found", errInvalidUpload))
+		}
+		// the Jaeger UI can only download a single trace, same as the frontend we only show the first one
+		trace = traces[0]
+	case probe.ResourceSpans != nil:
+		unmarshaler := ptrace.JSONUnmarshaler{}
+		traces, err := unmarshaler.UnmarshalTraces([]byte(uploadedJson))
+		if err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("%w: %w", errInvalidUpload, err))
+		}
+		trace = otlpToTraceResponse(traces)
+	default:
+		return nil, backend.DownstreamError(errInvalidUpload)
+	}
+
+	return data.Frames{transformTraceResponse(trace, refID)}, nil
+}
+
+// otlpToTraceResponse converts OTLP traces into the Jaeger trace format, creating one
+// process per resource.
+func otlpToTraceResponse(traces ptrace.Traces) TraceResponse {
+	response := TraceResponse{
+		Processes: map[string]TraceProcess{},
+	}
+
+	resourceSpans := traces.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		rs := resourceSpans.At(i)
+		processID := "p" + strconv.Itoa(i+1)
+		process := TraceProcess{ServiceName: "unknown", Tags: []TraceKeyValuePair{}}
+		rs.Resource().Attributes().Range(func(k string, v pcommon.Value) bool {
+			if k == "service.name" {
+				process.ServiceName = v.AsString()
+			} else {
+				process.Tags = append(process.Tags, otlpValueToKeyValue(k, v))
+			}
+			return true
+		})
+		response.Processes[processID] = process
+
+		scopeSpans := rs.ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			ss := scopeSpans.At(j)
+			spans := ss.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := otlpSpanToSpan(spans.At(k), ss.Scope(), processID)
+				if response.TraceID == "" {
+					response.TraceID = span.TraceID
+				}
+				response.Spans = append(response.Spans, span)
+			}
+		}
+	}
+
+	return response
+}
+
+func otlpSpanToSpan(s ptrace.Span, scope pcommon.InstrumentationScope, processID string) Span {
+	traceID := traceIDToHex(s.TraceID())
+	span := Span{
+		TraceID:       traceID,
+		SpanID:        spanIDToHex(s.SpanID()),
+		ProcessID:     processID,
+		OperationName: s.Name(),
+		StartTime:     s.StartTimestamp().AsTime().UnixMicro(),
+		Duration:      s.EndTimestamp().AsTime().Sub(s.StartTimestamp().AsTime()).Microseconds(),
+		Logs:          []TraceLog{},
+		References:    []TraceSpanReference{},
+		Tags:          []TraceKeyValuePair{},
+	}
+
+	if !s.ParentSpanID().IsEmpty() {
+		span.References = append(span.References, TraceSpanReference{
+			RefType: "CHILD_OF",
+			SpanID:  spanIDToHex(s.ParentSpanID()),
+			TraceID: traceID,
+		})
+	}
+	links := s.Links()
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		span.References = append(span.References, TraceSpanReference{
+			RefType: "FOLLOWS_FROM",
+			SpanID:  spanIDToHex(link.SpanID()),
+			TraceID: traceIDToHex(link.TraceID()),
+		})
+	}
+
+	s.Attributes().Range(func(k string, v pcommon.Value) bool {
+		span.Tags = append(span.Tags, otlpValueToKeyValue(k, v))
+		return true
+	})
+	if kind := otlpSpanKind(s.Kind()); kind != "" {
+		span.Tags = append(span.Tags, TraceKeyValuePair{Key: "span.kind", Type: "string", Value: kind})
+	}
+	if scope.Name() != "" {
+		span.Tags = append(span.Tags, TraceKeyValuePair{Key: "otel.library.name", Type: "string", Value: scope.Name()})
+	}
+	if scope.Version() != "" {
+		span.Tags = append(span.Tags, TraceKeyValuePair{Key: "otel.library.version", Type: "string", Value: scope.Version()})
+	}
+	if s.Status().Code() == ptrace.StatusCodeError {
+		span.Tags = append(span.Tags, TraceKeyValuePair{Key: "error", Type: "bool", Value: true})
+		if msg := s.Status().Message(); msg != "" {
+			span.Tags = append(span.Tags, TraceKeyValuePair{Key: "otel.status_description", Type: "string", Value: msg})
+		}
+	}
+
+	events := s.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		log := TraceLog{
+			Timestamp: event.Timestamp().AsTime().UnixMicro(),
+			Name:      event.Name(),
+			Fields:    []TraceKeyValuePair{},
+		}
+		if event.Name() != "" {
+			log.Fields = append(log.Fields, TraceKeyValuePair{Key: "event", Type: "string", Value: event.Name()})
+		}
+		event.Attributes().Range(func(k string, v pcommon.Value) bool {
+			log.Fields = append(log.Fields, otlpValueToKeyValue(k, v))
+			return true
+		})
+		span.Logs = append(span.Logs, log)
+	}
+
+	return span
+}
+
+func otlpSpanKind(kind ptrace.SpanKind) string {
+	switch kind {
+	case ptrace.SpanKindClient:
+		return "client"
+	case ptrace.SpanKindServer:
+		return "server"
+	case ptrace.SpanKindProducer:
+		return "producer"
+	case ptrace.SpanKindConsumer:
+		return "consumer"
+	case ptrace.SpanKindInternal:
+		return "internal"
+	default:
+		return ""
+	}
+}
+
+func otlpValueToKeyValue(key string, v pcommon.Value) TraceKeyValuePair {
+	switch v.Type() {
+	case pcommon.ValueTypeBool:
+		return TraceKeyValuePair{Key: key, Type: "bool", Value: v.Bool()}
+	case pcommon.ValueTypeInt:
+		return TraceKeyValuePair{Key: key, Type: "int64", Value: v.Int()}
+	case pcommon.ValueTypeDouble:
+		return TraceKeyValuePair{Key: key, Type: "float64", Value: v.Double()}
+	default:
+		return TraceKeyValuePair{Key: key, Type: "string", Value: v.AsString()}
+	}
+}
+
+func traceIDToHex(id pcommon.TraceID) string {
+	if id.IsEmpty() {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
+
+func spanIDToHex(id pcommon.SpanID) string {
+	if id.IsEmpty() {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,114 @@
+package jaeger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+const otlpTrace = `{
+  "resourceSpans": [{
+    "resource": {"attributes": [
+      {"key": "service.name", "value": {"stringValue": "frontend"}},
+      {"key": "host.name", "value": {"stringValue": "host-1"}}
+    ]},
+    "scopeSpans": [{
+      "scope": {"name": "http", "version": "1.0.0"},
+      "spans": [
+        {
+          "traceId": "5b8efff798038103d269b633813fc60c",
+          "spanId": "eee19b7ec3c1b174",
+          "name": "GET /api",
+          "kind": 2,
+          "startTimeUnixNano": "1605873894680409000",
+          "endTimeUnixNano": "1605873894681409000",
+          "attributes": [{"key": "http.status_code", "value": {"intValue": "500"}}],
+          "events": [{"timeUnixNano": "1605873894680909000", "name": "exception", "attributes": [{"key": "exception.message", "value": {"stringValue": "boom"}}]}],
+          "status": {"code": 2, "message": "internal error"}
+        },
+        {
+          "traceId": "5b8efff798038103d269b633813fc60c",
+          "spanId": "eee19b7ec3c1b175",
+          "parentSpanId": "eee19b7ec3c1b174",
+          "name": "SELECT",
+          "kind": 3,
+          "startTimeUnixNano": "1605873894680509000",
+          "endTimeUnixNano": "1605873894680609000"
+        }
+      ]
+    }]
+  }]
+}`
+
+func TestTransformUpload(t *testing.T) {
+	t.Run("empty upload", func(t *testing.T) {
+		frames, err := transformUpload("", "A")
+		require.NoError(t, err)
+		require.Empty(t, frames)
+	})
+
+	t.Run("jaeger json", func(t *testing.T) {
+		upload := `{"data": [{
+			"traceID": "3fa414edcef6ad90",
+			"spans": [
+				{"traceID": "3fa414edcef6ad90", "spanID": "3fa414edcef6ad90", "operationName": "HTTP GET - api_traces_traceid", "processID": "p1", "startTime": 1605873894680409, "duration": 1049141, "references": []},
+				{"traceID": "3fa414edcef6ad90", "spanID": "0f5c1808567e4403", "operationName": "/tempopb.Querier/FindTraceByID", "processID": "p1", "startTime": 1605873894680587, "duration": 1847, "references": [{"refType": "CHILD_OF", "traceID": "3fa414edcef6ad90", "spanID": "3fa414edcef6ad90"}]}
+			],
+			"processes": {"p1": {"serviceName": "tempo-querier", "tags": []}}
+		}]}`
+
+		frames, err := transformUpload(upload, "A")
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, "A", frame.Name)
+		require.Equal(t, "trace", string(frame.Meta.PreferredVisualization))
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "tempo-querier", frame.Fields[4].At(0))
+		require.Nil(t, frame.Fields[2].At(0))
+		require.Equal(t, "3fa414edcef6ad90", *frame.Fields[2].At(1).(*string))
+	})
+
+	t.Run("otlp json", func(t *testing.T) {
+		frames, err := transformUpload(otlpTrace, "A")
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "5b8efff798038103d269b633813fc60c", frame.Fields[0].At(0))
+		require.Equal(t, "eee19b7ec3c1b174", frame.Fields[1].At(0))
+		require.Equal(t, "GET /api", frame.Fields[3].At(0))
+		require.Equal(t, "frontend", frame.Fields[4].At(0))
+		require.Equal(t, 1605873894680.409, frame.Fields[6].At(0))
+		require.Equal(t, 1.0, frame.Fields[7].At(0))
+		require.Equal(t, "eee19b7ec3c1b174", *frame.Fields[2].At(1).(*string))
+
+		var serviceTags []TraceKeyValuePair
+		require.NoError(t, json.Unmarshal(frame.Fields[5].At(0).(json.RawMessage), &serviceTags))
+		require.Equal(t, []TraceKeyValuePair{{Key: "host.name", Type: "string", Value: "host-1"}}, serviceTags)
+
+		var tags []TraceKeyValuePair
+		require.NoError(t, json.Unmarshal(frame.Fields[10].At(0).(json.RawMessage), &tags))
+		require.Contains(t, tags, TraceKeyValuePair{Key: "span.kind", Type: "string", Value: "server"})
+		require.Contains(t, tags, TraceKeyValuePair{Key: "error", Type: "bool", Value: true})
+		require.Contains(t, tags, TraceKeyValuePair{Key: "http.status_code", Type: "int64", Value: float64(500)})
+
+		var logs []TraceLog
+		require.NoError(t, json.Unmarshal(frame.Fields[8].At(0).(json.RawMessage), &logs))
+		require.Len(t, logs, 1)
+		require.Equal(t, int64(1605873894680909), logs[0].Timestamp)
+		require.Equal(t, "exception", logs[0].Name)
+	})
+
+	t.Run("invalid upload", func(t *testing.T) {
+		for _, upload := range []string{`not json`, `{"foo": "bar"}`, `{"data": []}`} {
+			_, err := transformUpload(upload, "A")
+			require.ErrorIs(t, err, errInvalidUpload, upload)
+			require.True(t, backend.IsDownstreamError(err), upload)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+//go:build cgo
+
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverName is a dedicated driver whose connections can't reach outside of the opened
+// database file. Restrictions are enforced by SQLite itself, as any text based check of
+// the query can be bypassed.
+const driverName = "sqlite3_grafana_datasource"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			// no other database files can be attached, this includes VACUUM INTO
+			conn.SetLimit(sqlite3.SQLITE_LIMIT_ATTACHED, 0)
+			conn.RegisterAuthorizer(authorize)
+			return nil
+		},
+	})
+}
+
+// authorize denies statements that would change the connection state or load native code.
+func authorize(action int, arg1, arg2, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_ATTACH, sqlite3.SQLITE_DETACH, sqlite3.SQLITE_PRAGMA:
+		return sqlite3.SQLITE_DENY
+	case sqlite3.SQLITE_FUNCTION:
+		if strings.EqualFold(arg2, "load_extension") {
+			return sqlite3.SQLITE_DENY
+		}
+	}
+	return sqlite3.SQLITE_OK
+}
@@ -0,0 +1,26 @@
+//go:build !cgo
+
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+// driverName is a dedicated driver whose connections can't reach outside of the opened
+// database file. The pure go driver has no authorizer API, so the data source is
+// unavailable in builds without cgo.
+const driverName = "sqlite3_grafana_datasource"
+
+var errCgoRequired = errors.New("the SQLite data source requires a build with cgo enabled")
+
+func init() {
+	sql.Register(driverName, unsupportedDriver{})
+}
+
+type unsupportedDriver struct{}
+
+func (unsupportedDriver) Open(string) (driver.Conn, error) {
+	return nil, errCgoRequired
+}
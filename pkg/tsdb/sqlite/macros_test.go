@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newSqliteMacroEngine()
+	query := &backend.DataQuery{
+		JSON: []byte("{}"),
+	}
+
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	t.Run("interpolate __time function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, "select CAST(strftime('%s', time_column) AS INTEGER) AS time", sql)
+	})
+
+	t.Run("interpolate __timeFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("WHERE CAST(strftime('%%s', time_column) AS INTEGER) BETWEEN %d AND %d", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeFrom and __timeTo functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom(), $__timeTo()")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("select datetime(%d, 'unixepoch'), datetime(%d, 'unixepoch')", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY (CAST(strftime('%s', time_column) AS INTEGER) / 300) * 300", sql)
+	})
+
+	t.Run("interpolate __timeGroupAlias function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__timeGroupAlias(time_column,'1h')")
+		require.NoError(t, err)
+
+		require.Equal(t, "select (CAST(strftime('%s', time_column) AS INTEGER) / 3600) * 3600 AS time", sql)
+	})
+
+	t.Run("interpolate __timeGroup function with fill", func(t *testing.T) {
+		fillQuery := &backend.DataQuery{JSON: []byte("{}")}
+		_, err := engine.Interpolate(fillQuery, timeRange, "GROUP BY $__timeGroup(time_column,'5m', NULL)")
+		require.NoError(t, err)
+		require.Contains(t, string(fillQuery.JSON), `"fillMode":"null"`)
+	})
+
+	t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__unixEpochFilter(time)")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("WHERE time >= %d AND time <= %d", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __unixEpochGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__unixEpochGroup(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY (CAST(time_column AS INTEGER) / 300) * 300", sql)
+	})
+
+	t.Run("unknown macro", func(t *testing.T) {
+		_, err := engine.Interpolate(query, timeRange, "select $__unknown(time_column)")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,240 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqlite/sqleng"
+)
+
+var (
+	ErrNoAllowedPaths     = errors.New("the SQLite data source is disabled, configure sql_datasources.sqlite_allowed_paths to enable it")
+	ErrMissingPath        = errors.New("no database file path configured")
+	ErrPathNotAllowed     = errors.New("database file is not inside an allowed directory")
+	errUnexpectedInstance = errors.New("unexpected data source instance type")
+)
+
+type Service struct {
+	im     instancemgmt.InstanceManager
+	logger log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	logger := backend.NewLoggerWith("logger", "tsdb.sqlite")
+	return &Service{
+		im:     datasource.NewInstanceManager(NewInstanceSettings(cfg.SqlDatasourceSQLiteAllowedPaths, logger)),
+		logger: logger,
+	}
+}
+
+// instance wraps the shared sql engine handler together with the underlying
+// connection pool, which is needed for SQLite specific health checks.
+type instance struct {
+	*sqleng.DataSourceHandler
+	db *sql.DB
+}
+
+func (s *Service) getInstance(ctx context.Context, pluginCtx backend.PluginContext) (*instance, error) {
+	i, err := s.im.Get(ctx, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := i.(*instance)
+	if !ok {
+		return nil, errUnexpectedInstance
+	}
+	return inst, nil
+}
+
+// CheckHealth verifies that the configured file can be opened and is a SQLite database.
+// A plain ping is not enough, as the driver opens database files lazily.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+	}
+
+	var tables int64
+	if err := inst.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&tables); err != nil {
+		s.logger.FromContext(ctx).Error("Check health failed", "error", err)
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: fmt.Sprintf("Database error: %s", err.Error())}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+// NOTE: do not put any business logic into this method. it's whole job is to forward the call "inside"
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return inst.QueryData(ctx, req)
+}
+
+func NewInstanceSettings(allowedPaths []string, logger log.Logger) datasource.InstanceFactoryFunc {
+	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		cfg := backend.GrafanaConfigFromContext(ctx)
+		sqlCfg, err := cfg.SQL()
+		if err != nil {
+			return nil, err
+		}
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    sqlCfg.DefaultMaxOpenConns,
+			MaxIdleConns:    sqlCfg.DefaultMaxIdleConns,
+			ConnMaxLifetime: sqlCfg.DefaultMaxConnLifetimeSeconds,
+		}
+
+		err = json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+
+		path, err := resolveDatabasePath(database, allowedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData: jsonData,
+			Database: path,
+			ID:       settings.ID,
+			Updated:  settings.Updated,
+			UID:      settings.UID,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DSInfo:             dsInfo,
+			TimeColumnNames:    []string{"time", "time_sec"},
+			MetricColumnTypes:  []string{"TEXT", "VARCHAR", "CHAR", "NVARCHAR", "NCHAR", "CLOB"},
+			RowLimit:           sqlCfg.RowLimit,
+			DynamicColumnTypes: true,
+		}
+
+		userFacingDefaultError, err := cfg.UserFacingDefaultError()
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := sql.Open(driverName, connectionString(path))
+		if err != nil {
+			return nil, err
+		}
+
+		db.SetMaxOpenConns(config.DSInfo.JsonData.MaxOpenConns)
+		db.SetMaxIdleConns(config.DSInfo.JsonData.MaxIdleConns)
+		db.SetConnMaxLifetime(time.Duration(config.DSInfo.JsonData.ConnMaxLifetime) * time.Second)
+
+		rowTransformer := sqliteQueryResultTransformer{
+			userError: userFacingDefaultError,
+		}
+
+		handler, err := sqleng.NewQueryDataHandler(userFacingDefaultError, db, config, &rowTransformer, newSqliteMacroEngine(), logger)
+		if err != nil {
+			return nil, err
+		}
+		return &instance{DataSourceHandler: handler, db: db}, nil
+	}
+}
+
+// connectionString builds a URI filename that opens the database read-only. Both the
+// cgo and the pure go driver understand the mode parameter.
+func connectionString(path string) string {
+	u := url.URL{
+		Scheme:   "file",
+		Path:     filepath.ToSlash(path),
+		RawQuery: "mode=ro",
+	}
+	return u.String()
+}
+
+// resolveDatabasePath returns the cleaned absolute path of the database file if it is
+// located inside one of the allowed directories. Relative paths are resolved against
+// each allowed directory in order. Symlinks are resolved before the check so a link
+// inside an allowed directory cannot point outside of it.
+func resolveDatabasePath(path string, allowedPaths []string) (string, error) {
+	if len(allowedPaths) == 0 {
+		return "", ErrNoAllowedPaths
+	}
+	if strings.TrimSpace(path) == "" {
+		return "", ErrMissingPath
+	}
+
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = candidates[:0]
+		for _, dir := range allowedPaths {
+			candidates = append(candidates, filepath.Join(dir, path))
+		}
+	}
+
+	for _, candidate := range candidates {
+		resolved, err := evalPath(candidate)
+		if err != nil {
+			continue
+		}
+		for _, dir := range allowedPaths {
+			allowedDir, err := evalPath(dir)
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(allowedDir, resolved)
+			if err != nil {
+				continue
+			}
+			if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && rel != "." {
+				return resolved, nil
+			}
+		}
+	}
+
+	return "", ErrPathNotAllowed
+}
+
+func evalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+type sqliteQueryResultTransformer struct {
+	userError string
+}
+
+// TransformQueryError hides driver errors that are unrelated to the query itself,
+// such as I/O errors that could leak details about the host file system.
+func (t *sqliteQueryResultTransformer) TransformQueryError(logger log.Logger, err error) error {
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "disk i/o") || strings.Contains(msg, "unable to open") {
+		logger.Error("Query error", "error", err)
+		return fmt.Errorf("query failed - %s", t.userError)
+	}
+	return err
+}
+
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	// SQLite uses dynamic typing and the driver can only report declared column types,
+	// which are missing for expressions and aggregates. Field types are therefore
+	// inferred from the returned values, see DataPluginConfiguration.DynamicColumnTypes.
+	return nil
+}
@@ -0,0 +1,244 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestDatabase(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "metrics.db")
+	db, err := sql.Open(driverName, path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE metric (time TEXT, host TEXT, value REAL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO metric (time, host, value) VALUES
+		('2018-03-15T12:55:00Z', 'a', 1.5),
+		('2018-03-15T12:56:00Z', 'a', 2.5),
+		('2018-03-15T12:55:00Z', 'b', 3),
+		('2018-03-15T13:30:00Z', 'b', 4)`)
+	require.NoError(t, err)
+	return path
+}
+
+func newTestInstance(t *testing.T, dir string, database string) *instance {
+	t.Helper()
+
+	cfg := backend.NewGrafanaCfg(map[string]string{
+		backend.SQLMaxOpenConnsDefault:           "0",
+		backend.SQLMaxIdleConnsDefault:           "2",
+		backend.SQLMaxConnLifetimeSecondsDefault: "14400",
+		backend.SQLRowLimit:                      "1000000",
+		backend.UserFacingDefaultError:           "",
+	})
+	ctx := backend.WithGrafanaConfig(context.Background(), cfg)
+
+	factory := NewInstanceSettings([]string{dir}, backend.NewLoggerWith("logger", "sqlite.test"))
+	i, err := factory(ctx, backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{}`),
+		Database: database,
+	})
+	require.NoError(t, err)
+	t.Cleanup(i.(*instance).Dispose)
+	return i.(*instance)
+}
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	createTestDatabase(t, dir)
+	inst := newTestInstance(t, dir, "metrics.db")
+
+	from := time.Date(2018, 3, 15, 12, 50, 0, 0, time.UTC)
+	timeRange := backend.TimeRange{From: from, To: from.Add(30 * time.Minute)}
+
+	t.Run("time series query with macros", func(t *testing.T) {
+		query := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID:     "A",
+					TimeRange: timeRange,
+					JSON: []byte(`{
+						"rawSql": "SELECT $__timeGroupAlias(time, '5m'), host AS metric, sum(value) AS value FROM metric WHERE $__timeFilter(time) GROUP BY 1, 2 ORDER BY 1",
+						"format": "time_series"
+					}`),
+				},
+			},
+		}
+
+		resp, err := inst.QueryData(context.Background(), query)
+		require.NoError(t, err)
+		queryResult := resp.Responses["A"]
+		require.NoError(t, queryResult.Error)
+
+		frames := queryResult.Frames
+		require.Len(t, frames, 1)
+		require.Len(t, frames[0].Fields, 3)
+		require.Equal(t, data.TimeSeriesTimeFieldName, frames[0].Fields[0].Name)
+		require.Equal(t, 1, frames[0].Rows())
+		require.Equal(t, from.Add(5*time.Minute), frames[0].Fields[0].At(0).(time.Time).UTC())
+		require.Equal(t, "a", frames[0].Fields[1].Name)
+		value, err := frames[0].Fields[1].FloatAt(0)
+		require.NoError(t, err)
+		require.Equal(t, 4.0, value)
+		require.Equal(t, "b", frames[0].Fields[2].Name)
+		value, err = frames[0].Fields[2].FloatAt(0)
+		require.NoError(t, err)
+		require.Equal(t, 3.0, value)
+	})
+
+	t.Run("table query", func(t *testing.T) {
+		query := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID:     "A",
+					TimeRange: timeRange,
+					JSON:      []byte(`{"rawSql": "SELECT host, count(*) AS c FROM metric GROUP BY host ORDER BY host", "format": "table"}`),
+				},
+			},
+		}
+
+		resp, err := inst.QueryData(context.Background(), query)
+		require.NoError(t, err)
+		queryResult := resp.Responses["A"]
+		require.NoError(t, queryResult.Error)
+		require.Len(t, queryResult.Frames, 1)
+		require.Equal(t, 2, queryResult.Frames[0].Rows())
+	})
+
+	t.Run("database is opened read-only", func(t *testing.T) {
+		query := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID:     "A",
+					TimeRange: timeRange,
+					JSON:      []byte(`{"rawSql": "DELETE FROM metric", "format": "table"}`),
+				},
+			},
+		}
+
+		resp, err := inst.QueryData(context.Background(), query)
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		require.Contains(t, resp.Responses["A"].Error.Error(), "readonly")
+	})
+
+	t.Run("statements escaping the database file are rejected by the connection", func(t *testing.T) {
+		for _, rawSQL := range []string{
+			"ATTACH DATABASE '/etc/grafana/grafana.db' AS g",
+			"/**/ATTACH/**/'/etc/grafana/grafana.db' AS g",
+			"PRAGMA writable_schema = 1",
+			"SELECT * FROM pragma_database_list",
+			"VACUUM INTO '" + filepath.Join(dir, "copy.db") + "'",
+			"SELECT load_extension('/tmp/ext.so')",
+		} {
+			rawJSON, err := json.Marshal(map[string]string{"rawSql": rawSQL, "format": "table"})
+			require.NoError(t, err)
+			query := &backend.QueryDataRequest{
+				Queries: []backend.DataQuery{{RefID: "A", TimeRange: timeRange, JSON: rawJSON}},
+			}
+
+			resp, err := inst.QueryData(context.Background(), query)
+			require.NoError(t, err)
+			require.Error(t, resp.Responses["A"].Error, rawSQL)
+		}
+		require.NoFileExists(t, filepath.Join(dir, "copy.db"))
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	dir := t.TempDir()
+	createTestDatabase(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "not-a-db.txt"), []byte("hello, this is not a database file"), 0o600))
+
+	t.Run("valid database", func(t *testing.T) {
+		s := &Service{im: &fakeInstanceManager{inst: newTestInstance(t, dir, "metrics.db")}, logger: backend.NewLoggerWith("logger", "sqlite.test")}
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("file that is not a database", func(t *testing.T) {
+		s := &Service{im: &fakeInstanceManager{inst: newTestInstance(t, dir, "not-a-db.txt")}, logger: backend.NewLoggerWith("logger", "sqlite.test")}
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+	})
+}
+
+func TestResolveDatabasePath(t *testing.T) {
+	allowed := t.TempDir()
+	other := t.TempDir()
+	dbPath := createTestDatabase(t, allowed)
+	outsidePath := createTestDatabase(t, other)
+	require.NoError(t, os.Symlink(outsidePath, filepath.Join(allowed, "link.db")))
+
+	resolvedAllowed, err := filepath.EvalSymlinks(allowed)
+	require.NoError(t, err)
+
+	t.Run("no allowed paths disables the data source", func(t *testing.T) {
+		_, err := resolveDatabasePath(dbPath, nil)
+		require.ErrorIs(t, err, ErrNoAllowedPaths)
+	})
+
+	t.Run("empty path", func(t *testing.T) {
+		_, err := resolveDatabasePath("", []string{allowed})
+		require.ErrorIs(t, err, ErrMissingPath)
+	})
+
+	t.Run("absolute path inside allowed directory", func(t *testing.T) {
+		path, err := resolveDatabasePath(dbPath, []string{allowed})
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(resolvedAllowed, "metrics.db"), path)
+	})
+
+	t.Run("relative path is resolved against allowed directories", func(t *testing.T) {
+		path, err := resolveDatabasePath("metrics.db", []string{other, allowed})
+		require.NoError(t, err)
+		require.NotEmpty(t, path)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		_, err := resolveDatabasePath(filepath.Join("..", filepath.Base(other), "metrics.db"), []string{allowed})
+		require.ErrorIs(t, err, ErrPathNotAllowed)
+	})
+
+	t.Run("absolute path outside allowed directory", func(t *testing.T) {
+		_, err := resolveDatabasePath(outsidePath, []string{allowed})
+		require.ErrorIs(t, err, ErrPathNotAllowed)
+	})
+
+	t.Run("symlink pointing outside allowed directory", func(t *testing.T) {
+		_, err := resolveDatabasePath(filepath.Join(allowed, "link.db"), []string{allowed})
+		require.ErrorIs(t, err, ErrPathNotAllowed)
+	})
+
+	t.Run("allowed directory itself", func(t *testing.T) {
+		_, err := resolveDatabasePath(allowed, []string{allowed})
+		require.ErrorIs(t, err, ErrPathNotAllowed)
+	})
+}
+
+type fakeInstanceManager struct {
+	inst *instance
+}
+
+func (f *fakeInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.inst, nil
+}
+
+func (f *fakeInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
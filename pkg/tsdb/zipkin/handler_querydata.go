@@ -30,10 +30,17 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 
 		switch query.QueryType {
 		case zipkinQueryTypeUpload:
-			logger.Debug("upload query type is not supported in backend mode")
+			frames, err := transformUpload(query.UploadedJson, q.RefID)
+			if err != nil {
+				logger.Debug("Failed to parse uploaded trace", "error", err)
+				response.Responses[q.RefID] = backend.DataResponse{
+					Error:       err,
+					ErrorSource: backend.ErrorSourceDownstream,
+				}
+				continue
+			}
 			response.Responses[q.RefID] = backend.DataResponse{
-				Error:       fmt.Errorf("unsupported query type %s. only available in frontend mode", query.QueryType),
-				ErrorSource: backend.ErrorSourcePlugin,
+				Frames: frames,
 			}
 		default:
 			traces, err := dsInfo.ZipkinClient.Trace(query.Query)
@@ -68,6 +75,8 @@ const (
 type zipkinQuery struct {
 	Query     string          `json:"query,omitempty"`
 	QueryType zipkinQueryType `json:"queryType,omitempty"`
+	// UploadedJson is the content of an uploaded trace file, used by the upload query type
+	UploadedJson string `json:"uploadedJson,omitempty"`
 }
 
 func loadQuery(backendQuery backend.DataQuery) (zipkinQuery, error) {
@@ -0,0 +1,135 @@
+package zipkin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/openzipkin/zipkin-go/model"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var errInvalidUpload = errors.New("JSON is not valid Zipkin or OTLP format")
+
+// transformUpload converts an uploaded trace file into a trace frame. Both Zipkin v2
+// JSON, which is a list of spans, and OTLP JSON are supported.
+func transformUpload(uploadedJson string, refId string) (data.Frames, error) {
+	payload := bytes.TrimSpace([]byte(uploadedJson))
+	if len(payload) == 0 {
+		return data.Frames{}, nil
+	}
+
+	var spans []model.SpanModel
+	if payload[0] == '[' {
+		if err := json.Unmarshal(payload, &spans); err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("%w: %w", errInvalidUpload, err))
+		}
+	} else {
+		var probe struct {
+			ResourceSpans json.RawMessage `json:"resourceSpans"`
+		}
+		if err := json.Unmarshal(payload, &probe); err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("%w: %w", errInvalidUpload, err))
+		}
+		if probe.ResourceSpans == nil {
+			return nil, backend.DownstreamError(errInvalidUpload)
+		}
+		unmarshaler := ptrace.JSONUnmarshaler{}
+		traces, err := unmarshaler.UnmarshalTraces(payload)
+		if err != nil {
+			return nil, backend.DownstreamError(fmt.Errorf("%w: %w", errInvalidUpload, err))
+		}
+		spans = otlpToSpanModels(traces)
+	}
+
+	return data.Frames{transformResponse(spans, refId)}, nil
+}
+
+// otlpToSpanModels converts OTLP traces into Zipkin v2 spans, following the mapping of
+// the OpenTelemetry Zipkin exporter.
+func otlpToSpanModels(traces ptrace.Traces) []model.SpanModel {
+	var spans []model.SpanModel
+
+	resourceSpans := traces.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		rs := resourceSpans.At(i)
+		serviceName := "unknown"
+		if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+			serviceName = v.AsString()
+		}
+
+		scopeSpans := rs.ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			otlpSpans := scopeSpans.At(j).Spans()
+			for k := 0; k < otlpSpans.Len(); k++ {
+				spans = append(spans, otlpSpanToSpanModel(otlpSpans.At(k), serviceName))
+			}
+		}
+	}
+
+	return spans
+}
+
+func otlpSpanToSpanModel(s ptrace.Span, serviceName string) model.SpanModel {
+	traceID := s.TraceID()
+	spanID := s.SpanID()
+	span := model.SpanModel{
+		SpanContext: model.SpanContext{
+			TraceID: model.TraceID{
+				High: binary.BigEndian.Uint64(traceID[:8]),
+				Low:  binary.BigEndian.Uint64(traceID[8:]),
+			},
+			ID: model.ID(binary.BigEndian.Uint64(spanID[:])),
+		},
+		Name:          s.Name(),
+		Kind:          otlpSpanKind(s.Kind()),
+		Timestamp:     s.StartTimestamp().AsTime(),
+		Duration:      s.EndTimestamp().AsTime().Sub(s.StartTimestamp().AsTime()),
+		LocalEndpoint: &model.Endpoint{ServiceName: serviceName},
+		Tags:          map[string]string{},
+	}
+
+	if parentID := s.ParentSpanID(); !parentID.IsEmpty() {
+		id := model.ID(binary.BigEndian.Uint64(parentID[:]))
+		span.ParentID = &id
+	}
+
+	s.Attributes().Range(func(k string, v pcommon.Value) bool {
+		span.Tags[k] = v.AsString()
+		return true
+	})
+	if s.Status().Code() == ptrace.StatusCodeError {
+		span.Tags["error"] = s.Status().Message()
+	}
+
+	events := s.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		span.Annotations = append(span.Annotations, model.Annotation{
+			Timestamp: event.Timestamp().AsTime(),
+			Value:     event.Name(),
+		})
+	}
+
+	return span
+}
+
+func otlpSpanKind(kind ptrace.SpanKind) model.Kind {
+	switch kind {
+	case ptrace.SpanKindClient:
+		return model.Client
+	case ptrace.SpanKindServer:
+		return model.Server
+	case ptrace.SpanKindProducer:
+		return model.Producer
+	case ptrace.SpanKindConsumer:
+		return model.Consumer
+	default:
+		return model.Undetermined
+	}
+}
@@ -0,0 +1,92 @@
+package zipkin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformUpload(t *testing.T) {
+	t.Run("empty upload", func(t *testing.T) {
+		frames, err := transformUpload("  ", "A")
+		require.NoError(t, err)
+		require.Empty(t, frames)
+	})
+
+	t.Run("zipkin v2 json", func(t *testing.T) {
+		upload := `[
+			{"traceId": "3fa414edcef6ad90", "id": "3fa414edcef6ad90", "name": "get /api", "kind": "SERVER", "timestamp": 1605873894680409, "duration": 1049141, "localEndpoint": {"serviceName": "frontend"}},
+			{"traceId": "3fa414edcef6ad90", "parentId": "3fa414edcef6ad90", "id": "0f5c1808567e4403", "name": "select", "timestamp": 1605873894680587, "duration": 1847, "localEndpoint": {"serviceName": "db"}}
+		]`
+
+		frames, err := transformUpload(upload, "A")
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, "A", frame.Name)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "frontend", frame.Fields[4].At(0))
+		require.Equal(t, "3fa414edcef6ad90", *frame.Fields[2].At(1).(*string))
+	})
+
+	t.Run("otlp json", func(t *testing.T) {
+		upload := `{"resourceSpans": [{
+			"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "frontend"}}]},
+			"scopeSpans": [{"spans": [
+				{
+					"traceId": "5b8efff798038103d269b633813fc60c",
+					"spanId": "eee19b7ec3c1b174",
+					"name": "GET /api",
+					"kind": 2,
+					"startTimeUnixNano": "1605873894680409000",
+					"endTimeUnixNano": "1605873894681409000",
+					"events": [{"timeUnixNano": "1605873894680909000", "name": "exception"}],
+					"status": {"code": 2, "message": "internal error"}
+				},
+				{
+					"traceId": "5b8efff798038103d269b633813fc60c",
+					"spanId": "eee19b7ec3c1b175",
+					"parentSpanId": "eee19b7ec3c1b174",
+					"name": "SELECT",
+					"startTimeUnixNano": "1605873894680509000",
+					"endTimeUnixNano": "1605873894680609000"
+				}
+			]}]
+		}]}`
+
+		frames, err := transformUpload(upload, "A")
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "5b8efff798038103d269b633813fc60c", frame.Fields[0].At(0))
+		require.Equal(t, "eee19b7ec3c1b174", frame.Fields[1].At(0))
+		require.Equal(t, "frontend", frame.Fields[4].At(0))
+		require.Equal(t, 1605873894680.409, frame.Fields[6].At(0))
+		require.Equal(t, 1.0, frame.Fields[7].At(0))
+		require.Equal(t, "eee19b7ec3c1b174", *frame.Fields[2].At(1).(*string))
+
+		var tags []TraceKeyValuePair
+		require.NoError(t, json.Unmarshal(frame.Fields[9].At(0).(json.RawMessage), &tags))
+		require.Contains(t, tags, TraceKeyValuePair{Key: "kind", Value: "SERVER"})
+		require.Contains(t, tags, TraceKeyValuePair{Key: "error", Value: true})
+		require.Contains(t, tags, TraceKeyValuePair{Key: "errorValue", Value: "internal error"})
+
+		var logs []TraceLog
+		require.NoError(t, json.Unmarshal(frame.Fields[8].At(0).(json.RawMessage), &logs))
+		require.Len(t, logs, 1)
+		require.Equal(t, int64(1605873894680909), logs[0].Timestamp)
+	})
+
+	t.Run("invalid upload", func(t *testing.T) {
+		for _, upload := range []string{`not json`, `{"foo": "bar"}`, `[{"traceId": 1}]`} {
+			_, err := transformUpload(upload, "A")
+			require.ErrorIs(t, err, errInvalidUpload, upload)
+			require.True(t, backend.IsDownstreamError(err), upload)
+		}
+	})
+}
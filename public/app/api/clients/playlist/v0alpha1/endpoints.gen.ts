@@ -272,10 +272,35 @@ export type PlaylistItem = {
      dashboards behind the tag will be added to the playlist.
      - dashboard_by_uid: The value is the dashboard UID */
   value: string;
+  /** duration overrides the interval of the playlist for the dashboards of this item, for example "2m". */
+  duration?: string;
+  /** timeRange overrides the time range of the dashboards of this item. */
+  timeRange?: PlaylistTimeRange;
+};
+export type PlaylistTimeRange = {
+  /** from is the start of the time range, for example "now-6h". */
+  from: string;
+  /** to is the end of the time range, for example "now". */
+  to: string;
+};
+export type PlaylistSchedule = {
+  /** weekdays the schedule applies to, in lower case ("monday" to "sunday"). It applies to every day if empty. */
+  weekdays?: string[];
+  /** from is the time of day the schedule starts at, formatted as "15:04". */
+  from: string;
+  /** to is the time of day the schedule ends at, formatted as "15:04". The schedule continues
+    on the next day if it is before from. */
+  to: string;
+  /** timezone of from and to, as an IANA time zone name. Defaults to UTC. */
+  timezone?: string;
+  /** kiosk is the name of the kiosk devices the schedule applies to. It applies to every kiosk if empty. */
+  kiosk?: string;
 };
 export type PlaylistSpec = {
   interval: string;
   items: PlaylistItem[];
+  /** schedules are the times at which kiosk devices play the playlist. */
+  schedules?: PlaylistSchedule[];
   title: string;
 };
 export type PlayliststatusOperatorState = {
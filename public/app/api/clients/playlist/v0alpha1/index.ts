@@ -75,6 +75,30 @@ async function migrateInternalIDs(playlist: PlaylistSpec) {
   }
 }
 
+export type GetScheduledPlaylistApiResponse = {
+  /** name of the playlist the kiosk device plays now. */
+  name: string;
+};
+export type GetScheduledPlaylistApiArg = {
+  /** kiosk is the name of the kiosk device. Schedules for other kiosk devices are ignored. */
+  kiosk?: string;
+};
+
+// The scheduled playlist doesn't depend on a playlist, so it is requested from the namespace
+// rather than from the subresource of a playlist the generated client expects.
+const playlistScheduleAPI = playlistAPIv0alpha1.injectEndpoints({
+  endpoints: (build) => ({
+    getScheduledPlaylist: build.query<GetScheduledPlaylistApiResponse, GetScheduledPlaylistApiArg>({
+      query: (queryArg) => ({
+        url: `/scheduled`,
+        params: {
+          kiosk: queryArg.kiosk,
+        },
+      }),
+    }),
+  }),
+});
+
 export const {
   useCreatePlaylistMutation,
   useDeletePlaylistMutation,
@@ -83,5 +107,7 @@ export const {
   useReplacePlaylistMutation,
 } = playlistAPIv0alpha1;
 
+export const { useGetScheduledPlaylistQuery } = playlistScheduleAPI;
+
 // eslint-disable-next-line no-barrel-files/no-barrel-files
 export type { Playlist } from './endpoints.gen';
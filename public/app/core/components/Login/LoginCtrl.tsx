@@ -4,7 +4,7 @@ import { t } from '@grafana/i18n';
 import { FetchError, getBackendSrv, isFetchError, locationService } from '@grafana/runtime';
 import config from 'app/core/config';
 
-import { LoginDTO, AuthNRedirectDTO } from './types';
+import { LoginDTO, AuthNRedirectDTO, MFAChallengeDTO } from './types';
 
 const isOauthEnabled = () => {
   return !!config.oauth && Object.keys(config.oauth).length > 0;
@@ -21,6 +21,13 @@ export interface FormModel {
   email: string;
 }
 
+/** Answer to a second factor challenge, with one of the fields set. */
+export interface MFAFormModel {
+  code?: string;
+  recoveryCode?: string;
+  webauthn?: unknown;
+}
+
 export interface PasswordlessFormModel {
   email: string;
 }
@@ -41,6 +48,9 @@ interface Props {
     isChangingPassword: boolean;
     skipPasswordChange: Function;
     login: (data: FormModel) => void;
+    loginMFA: (data: MFAFormModel) => void;
+    cancelMFA: () => void;
+    mfaChallenge: MFAChallengeDTO | undefined;
     passwordlessStart: (data: PasswordlessFormModel) => void;
     passwordlessConfirm: (data: PasswordlessConfirmationFormModel) => void;
     showPasswordlessConfirmation: boolean;
@@ -59,6 +69,7 @@ interface State {
   isChangingPassword: boolean;
   showDefaultPasswordWarning: boolean;
   loginErrorMessage?: string;
+  mfaChallenge?: MFAChallengeDTO;
 }
 
 export class LoginCtrl extends PureComponent<Props, State> {
@@ -121,15 +132,53 @@ export class LoginCtrl extends PureComponent<Props, State> {
           this.changeView(formModel.password === 'admin');
         }
       })
+      .catch((err) => {
+        // Users with a second factor get a challenge that is answered with loginMFA
+        if (isFetchError(err) && err.data?.messageId === 'mfa.required' && err.data.extra) {
+          this.setState({ isLoggingIn: false, mfaChallenge: err.data.extra });
+          return;
+        }
+
+        const fetchErrorMessage = isFetchError(err) ? getErrorMessage(err) : undefined;
+        this.setState({
+          isLoggingIn: false,
+          loginErrorMessage: fetchErrorMessage || t('login.error.unknown', 'Unknown error occurred'),
+        });
+      });
+  };
+
+  loginMFA = (formModel: MFAFormModel) => {
+    const { mfaChallenge } = this.state;
+    if (!mfaChallenge) {
+      return;
+    }
+
+    this.setState({
+      loginErrorMessage: undefined,
+      isLoggingIn: true,
+    });
+
+    getBackendSrv()
+      .post<LoginDTO>('/api/login/mfa', { ...formModel, token: mfaChallenge.token }, { showErrorAlert: false })
+      .then((result) => {
+        this.result = result;
+        this.toGrafana();
+      })
       .catch((err) => {
         const fetchErrorMessage = isFetchError(err) ? getErrorMessage(err) : undefined;
         this.setState({
           isLoggingIn: false,
           loginErrorMessage: fetchErrorMessage || t('login.error.unknown', 'Unknown error occurred'),
+          // The user has to log in with the password again once the challenge is gone
+          mfaChallenge: isChallengeGone(err) ? undefined : mfaChallenge,
         });
       });
   };
 
+  cancelMFA = () => {
+    this.setState({ loginErrorMessage: undefined, mfaChallenge: undefined });
+  };
+
   passwordlessStart = (formModel: PasswordlessFormModel) => {
     this.setState({
       loginErrorMessage: undefined,
@@ -199,8 +248,8 @@ export class LoginCtrl extends PureComponent<Props, State> {
 
   render() {
     const { children } = this.props;
-    const { isLoggingIn, isChangingPassword, showDefaultPasswordWarning, loginErrorMessage } = this.state;
-    const { login, toGrafana, changePassword, passwordlessStart, passwordlessConfirm } = this;
+    const { isLoggingIn, isChangingPassword, showDefaultPasswordWarning, loginErrorMessage, mfaChallenge } = this.state;
+    const { login, loginMFA, cancelMFA, toGrafana, changePassword, passwordlessStart, passwordlessConfirm } = this;
     const { loginHint, passwordHint, disableLoginForm, disableUserSignUp } = config;
 
     return (
@@ -212,6 +261,9 @@ export class LoginCtrl extends PureComponent<Props, State> {
           disableLoginForm,
           disableUserSignUp,
           login,
+          loginMFA,
+          cancelMFA,
+          mfaChallenge,
           passwordlessStart,
           passwordlessConfirm,
           showPasswordlessConfirmation: showPasswordlessConfirmation(),
@@ -229,22 +281,35 @@ export class LoginCtrl extends PureComponent<Props, State> {
 
 export default LoginCtrl;
 
-function getErrorMessage(err: FetchError<undefined | { messageId?: string; message?: string }>): string | undefined {
+type LoginErrorData = undefined | { messageId?: string; message?: string; extra?: MFAChallengeDTO };
+
+function getErrorMessage(err: FetchError<LoginErrorData>): string | undefined {
   switch (err.data?.messageId) {
     case 'password-auth.empty':
     case 'password-auth.failed':
     case 'password-auth.invalid':
       return t('login.error.invalid-user-or-password', 'Invalid username or password');
     case 'login-attempt.blocked':
+    case 'mfa.invalid.login-attempt':
       return t(
         'login.error.blocked',
         'You have exceeded the number of login attempts for this user. Please try again later.'
       );
+    case 'mfa.invalid-code':
+      return t('login.error.mfa-invalid-code', 'Invalid verification code');
+    case 'mfa.invalid-credential':
+      return t('login.error.mfa-invalid-credential', 'The security key could not be verified');
+    case 'mfa.challenge-not-found':
+      return t('login.error.mfa-challenge-expired', 'The verification expired, please log in again');
     default:
       return err.data?.message;
   }
 }
 
+function isChallengeGone(err: unknown): boolean {
+  return isFetchError<LoginErrorData>(err) && err.data?.messageId === 'mfa.challenge-not-found';
+}
+
 function getBootDataErrMessage(str?: string) {
   switch (str) {
     case 'oauth.login.error':
@@ -164,4 +164,71 @@ describe('Login Page', () => {
       'You have exceeded the number of login attempts for this user. Please try again later.'
     );
   });
+
+  it('asks for the second factor of users with a second factor', async () => {
+    Object.defineProperty(window, 'location', {
+      value: {
+        assign: jest.fn(),
+      },
+    });
+    postMock
+      .mockRejectedValueOnce({
+        data: {
+          message: 'Second factor required',
+          messageId: 'mfa.required',
+          statusCode: 401,
+          extra: { token: 'challenge-token', factors: ['totp'] },
+        },
+        status: 401,
+        statusText: 'Unauthorized',
+      })
+      .mockResolvedValueOnce({ message: 'Logged in' });
+
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await userEvent.type(await screen.findByLabelText(/Verification code/), '123456');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    await waitFor(() =>
+      expect(postMock).toHaveBeenCalledWith(
+        '/api/login/mfa',
+        { code: '123456', token: 'challenge-token' },
+        { showErrorAlert: false }
+      )
+    );
+    expect(window.location.assign).toHaveBeenCalledWith('/');
+  });
+
+  it('returns to the login form when the second factor challenge expired', async () => {
+    postMock
+      .mockRejectedValueOnce({
+        data: {
+          messageId: 'mfa.required',
+          statusCode: 401,
+          extra: { token: 'challenge-token', factors: ['totp'] },
+        },
+        status: 401,
+      })
+      .mockRejectedValueOnce({
+        data: { messageId: 'mfa.challenge-not-found', statusCode: 401 },
+        status: 401,
+      });
+
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await userEvent.type(await screen.findByLabelText(/Verification code/), '123456');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    const alert = await screen.findByRole('alert', { name: 'Login failed' });
+    expect(alert).toHaveTextContent('The verification expired, please log in again');
+    expect(screen.getByLabelText('Password')).toBeInTheDocument();
+  });
 });
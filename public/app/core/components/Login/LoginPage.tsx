@@ -15,6 +15,7 @@ import LoginCtrl from './LoginCtrl';
 import { LoginForm } from './LoginForm';
 import { LoginLayout, InnerBox } from './LoginLayout';
 import { LoginServiceButtons } from './LoginServiceButtons';
+import { MFALoginForm } from './MFALoginForm';
 import { PasswordlessConfirmation } from './PasswordlessConfirmationForm';
 import { PasswordlessLoginForm } from './PasswordlessLoginForm';
 import { UserSignup } from './UserSignup';
@@ -33,6 +34,9 @@ const LoginPage = () => {
           disableLoginForm,
           disableUserSignUp,
           login,
+          loginMFA,
+          cancelMFA,
+          mfaChallenge,
           passwordlessStart,
           passwordlessConfirm,
           showPasswordlessConfirmation,
@@ -44,7 +48,7 @@ const LoginPage = () => {
           loginErrorMessage,
         }) => (
           <LoginLayout isChangingPassword={isChangingPassword}>
-            {!isChangingPassword && !showPasswordlessConfirmation && (
+            {!isChangingPassword && !showPasswordlessConfirmation && !mfaChallenge && (
               <InnerBox>
                 {loginErrorMessage && (
                   <Alert className={styles.alert} severity="error" title={t('login.error.title', 'Login failed')}>
@@ -80,6 +84,22 @@ const LoginPage = () => {
               </InnerBox>
             )}
 
+            {!isChangingPassword && mfaChallenge && (
+              <InnerBox>
+                {loginErrorMessage && (
+                  <Alert className={styles.alert} severity="error" title={t('login.error.title', 'Login failed')}>
+                    {loginErrorMessage}
+                  </Alert>
+                )}
+                <MFALoginForm
+                  challenge={mfaChallenge}
+                  onSubmit={loginMFA}
+                  onCancel={cancelMFA}
+                  isLoggingIn={isLoggingIn}
+                />
+              </InnerBox>
+            )}
+
             {config.auth.passwordlessEnabled && showPasswordlessConfirmation && (
               <InnerBox>
                 <PasswordlessConfirmation
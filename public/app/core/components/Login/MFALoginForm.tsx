@@ -0,0 +1,229 @@
+import { css } from '@emotion/css';
+import { useEffect, useId, useState } from 'react';
+import { useForm } from 'react-hook-form';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { selectors } from '@grafana/e2e-selectors';
+import { t, Trans } from '@grafana/i18n';
+import { getBackendSrv } from '@grafana/runtime';
+import { Alert, Button, ClipboardButton, Field, Input, Stack, Text, useStyles2 } from '@grafana/ui';
+
+import { MFAFormModel } from './LoginCtrl';
+import { MFAChallengeDTO, TOTPEnrollmentDTO } from './types';
+
+interface Props {
+  challenge: MFAChallengeDTO;
+  onSubmit: (data: MFAFormModel) => void;
+  onCancel: () => void;
+  isLoggingIn: boolean;
+}
+
+interface CodeFormModel {
+  code: string;
+}
+
+/**
+ * MFALoginForm is the second step of the login for users with a second factor. Users answer the
+ * challenge with a code from their authenticator app, a recovery code or a security key.
+ * Administrators that have to enroll a second factor set up an authenticator app first.
+ */
+export const MFALoginForm = ({ challenge, onSubmit, onCancel, isLoggingIn }: Props) => {
+  const styles = useStyles2(getStyles);
+  const codeId = useId();
+  const [useRecoveryCode, setUseRecoveryCode] = useState(false);
+  const [enrollment, setEnrollment] = useState<TOTPEnrollmentDTO>();
+  const [securityKeyError, setSecurityKeyError] = useState<string>();
+  const {
+    handleSubmit,
+    register,
+    reset,
+    formState: { errors },
+  } = useForm<CodeFormModel>({ mode: 'onChange' });
+
+  const hasTOTP = challenge.enrollmentRequired || challenge.factors.includes('totp');
+  const hasWebAuthn = !challenge.enrollmentRequired && !!challenge.webauthn;
+
+  useEffect(() => {
+    if (!challenge.enrollmentRequired) {
+      return;
+    }
+    getBackendSrv()
+      .post<TOTPEnrollmentDTO>('/api/login/mfa/enroll', { token: challenge.token })
+      .then(setEnrollment);
+  }, [challenge]);
+
+  const submitCode = ({ code }: CodeFormModel) => {
+    onSubmit(useRecoveryCode ? { recoveryCode: code } : { code });
+  };
+
+  const submitSecurityKey = async () => {
+    setSecurityKeyError(undefined);
+    try {
+      onSubmit({ webauthn: await getWebAuthnAssertion(challenge) });
+    } catch {
+      setSecurityKeyError(t('login.mfa.security-key-failed', 'The security key did not respond'));
+    }
+  };
+
+  const toggleRecoveryCode = () => {
+    reset();
+    setUseRecoveryCode(!useRecoveryCode);
+  };
+
+  return (
+    <div className={styles.wrapper}>
+      {challenge.enrollmentRequired && (
+        <Stack direction="column" gap={2}>
+          <Text element="p">
+            <Trans i18nKey="login.mfa.enroll-description">
+              Administrators have to set up a second factor. Add the following secret to your authenticator app and
+              enter the code it generates.
+            </Trans>
+          </Text>
+          {enrollment && (
+            <Field label={t('login.mfa.enroll-secret-label', 'Secret')}>
+              <Stack>
+                <Input value={enrollment.secret} readOnly />
+                <ClipboardButton icon="copy" variant="secondary" getText={() => enrollment.secret}>
+                  <Trans i18nKey="login.mfa.copy-secret">Copy</Trans>
+                </ClipboardButton>
+              </Stack>
+            </Field>
+          )}
+        </Stack>
+      )}
+
+      {hasTOTP || useRecoveryCode ? (
+        <form onSubmit={handleSubmit(submitCode)}>
+          <Field
+            label={
+              useRecoveryCode
+                ? t('login.mfa.recovery-code-label', 'Recovery code')
+                : t('login.mfa.code-label', 'Verification code')
+            }
+            description={
+              useRecoveryCode
+                ? undefined
+                : t('login.mfa.code-description', 'Enter the code from your authenticator app')
+            }
+            invalid={!!errors.code}
+            error={errors.code?.message}
+          >
+            <Input
+              {...register('code', { required: t('login.mfa.code-required', 'Code is required') })}
+              id={codeId}
+              autoFocus
+              autoComplete="one-time-code"
+              autoCapitalize="none"
+              inputMode={useRecoveryCode ? 'text' : 'numeric'}
+            />
+          </Field>
+          <Button
+            type="submit"
+            data-testid={selectors.pages.Login.submit}
+            className={styles.submitButton}
+            disabled={isLoggingIn}
+          >
+            {isLoggingIn ? t('login.mfa.verify-loading-label', 'Verifying...') : t('login.mfa.verify-label', 'Verify')}
+          </Button>
+        </form>
+      ) : null}
+
+      {hasWebAuthn && !useRecoveryCode && (
+        <div className={styles.securityKey}>
+          {securityKeyError && (
+            <Alert severity="error" title={t('login.mfa.security-key-failed-title', 'Security key failed')}>
+              {securityKeyError}
+            </Alert>
+          )}
+          <Button
+            className={styles.submitButton}
+            variant={hasTOTP ? 'secondary' : 'primary'}
+            icon="key-skeleton-alt"
+            onClick={submitSecurityKey}
+            disabled={isLoggingIn}
+          >
+            <Trans i18nKey="login.mfa.use-security-key">Use a security key</Trans>
+          </Button>
+        </div>
+      )}
+
+      <Stack justifyContent="space-between">
+        <Button fill="text" onClick={onCancel}>
+          <Trans i18nKey="login.mfa.back">Back to login</Trans>
+        </Button>
+        {!challenge.enrollmentRequired && (
+          <Button fill="text" onClick={toggleRecoveryCode}>
+            {useRecoveryCode
+              ? t('login.mfa.use-second-factor', 'Use your second factor')
+              : t('login.mfa.use-recovery-code', 'Use a recovery code')}
+          </Button>
+        )}
+      </Stack>
+    </div>
+  );
+};
+
+// getWebAuthnAssertion asks the browser for an assertion of a security key and serializes it
+// the same way as PublicKeyCredential.toJSON(), which isn't supported by all browsers yet.
+async function getWebAuthnAssertion(challenge: MFAChallengeDTO) {
+  const options = challenge.webauthn!;
+  const credential = await navigator.credentials.get({
+    publicKey: {
+      challenge: base64URLToBuffer(options.challenge),
+      timeout: options.timeout,
+      rpId: options.rpId,
+      userVerification: options.userVerification,
+      allowCredentials: options.allowCredentials?.map((c) => ({ ...c, id: base64URLToBuffer(c.id) })),
+    },
+  });
+  if (!(credential instanceof PublicKeyCredential)) {
+    throw new Error('no credential returned');
+  }
+
+  const response = credential.response as AuthenticatorAssertionResponse;
+  return {
+    id: credential.id,
+    rawId: bufferToBase64URL(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: bufferToBase64URL(response.clientDataJSON),
+      authenticatorData: bufferToBase64URL(response.authenticatorData),
+      signature: bufferToBase64URL(response.signature),
+      userHandle: response.userHandle ? bufferToBase64URL(response.userHandle) : undefined,
+    },
+    clientExtensionResults: credential.getClientExtensionResults(),
+  };
+}
+
+function base64URLToBuffer(value: string): ArrayBuffer {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/');
+  const binary = atob(base64.padEnd(base64.length + ((4 - (base64.length % 4)) % 4), '='));
+  return Uint8Array.from(binary, (c) => c.charCodeAt(0)).buffer;
+}
+
+function bufferToBase64URL(buffer: ArrayBuffer): string {
+  const binary = String.fromCharCode(...new Uint8Array(buffer));
+  return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+export const getStyles = (theme: GrafanaTheme2) => {
+  return {
+    wrapper: css({
+      width: '100%',
+      paddingBottom: theme.spacing(2),
+    }),
+
+    submitButton: css({
+      justifyContent: 'center',
+      width: '100%',
+    }),
+
+    securityKey: css({
+      display: 'flex',
+      flexDirection: 'column',
+      gap: theme.spacing(1),
+      marginTop: theme.spacing(2),
+    }),
+  };
+};
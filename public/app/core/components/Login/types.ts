@@ -6,3 +6,26 @@ export interface LoginDTO {
 export interface AuthNRedirectDTO {
   URL: string;
 }
+
+export type MFAFactorType = 'totp' | 'webauthn';
+
+/** Challenge returned with the `mfa.required` error for users with a second factor. */
+export interface MFAChallengeDTO {
+  token: string;
+  factors: MFAFactorType[];
+  /** Options for navigator.credentials.get(), with binary values encoded as base64url. */
+  webauthn?: {
+    challenge: string;
+    timeout?: number;
+    rpId?: string;
+    allowCredentials?: Array<{ type: 'public-key'; id: string; transports?: AuthenticatorTransport[] }>;
+    userVerification?: UserVerificationRequirement;
+  };
+  /** Set for administrators that have to enroll an authenticator app before they can log in. */
+  enrollmentRequired?: boolean;
+}
+
+export interface TOTPEnrollmentDTO {
+  secret: string;
+  url: string;
+}
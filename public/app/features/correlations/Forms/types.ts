@@ -93,6 +93,79 @@ export function getSupportedTransTypeDetails(
           ),
         },
       };
+    case SupportedTransformationType.Json:
+      return {
+        label: t('correlations.trans-details.json-label', 'JSON'),
+        value: SupportedTransformationType.Json,
+        description: t(
+          'correlations.trans-details.json-description',
+          'Extract a value from a field containing a JSON document'
+        ),
+        expressionDetails: {
+          show: true,
+          required: true,
+          helpText: t(
+            'correlations.trans-details.json-expression',
+            'JSONPath of the value, made of field names and array indexes, for example $.user.roles[0].'
+          ),
+        },
+        mapValueDetails: {
+          show: true,
+          required: false,
+          helpText: t(
+            'correlations.trans-details.json-map-value',
+            'Defines the name of the variable. By default, the value overrides the variable of the field.'
+          ),
+        },
+      };
+    case SupportedTransformationType.Label:
+      return {
+        label: t('correlations.trans-details.label-label', 'Label'),
+        value: SupportedTransformationType.Label,
+        description: t(
+          'correlations.trans-details.label-description',
+          'Copy the labels of the log line to variables. Labels are read from the labels field by default.'
+        ),
+        expressionDetails: {
+          show: true,
+          required: false,
+          helpText: t(
+            'correlations.trans-details.label-expression',
+            'Name of the label to copy. Every label is copied when it is empty.'
+          ),
+        },
+        mapValueDetails: {
+          show: true,
+          required: false,
+          helpText: t(
+            'correlations.trans-details.label-map-value',
+            'Defines the name of the variable when a label name is set. By default, it is the name of the label.'
+          ),
+        },
+      };
+    case SupportedTransformationType.Template:
+      return {
+        label: t('correlations.trans-details.template-label', 'Template'),
+        value: SupportedTransformationType.Template,
+        description: t(
+          'correlations.trans-details.template-description',
+          'Combine fields and the variables of previous transformations into a new variable'
+        ),
+        expressionDetails: {
+          show: true,
+          required: true,
+          helpText: t(
+            'correlations.trans-details.template-expression',
+            'Template referencing variables, for example {{example}}.',
+            { example: '{{ .app }}-{{ .env }}' }
+          ),
+        },
+        mapValueDetails: {
+          show: true,
+          required: true,
+          helpText: t('correlations.trans-details.template-map-value', 'Defines the name of the variable.'),
+        },
+      };
     default:
       return {
         label: transType,
@@ -0,0 +1,60 @@
+import { SupportedTransformationType } from '@grafana/data';
+
+import { getTransformationVars } from './transformations';
+
+describe('getTransformationVars', () => {
+  const body = '{"order":{"id":42,"items":["book"]}}';
+
+  it('extracts a value with a JSONPath expression', () => {
+    expect(
+      getTransformationVars(
+        { type: SupportedTransformationType.Json, expression: '$.order.id', mapValue: 'orderId' },
+        body,
+        'body'
+      )
+    ).toEqual({ orderId: { value: '42' } });
+    expect(
+      getTransformationVars({ type: SupportedTransformationType.Json, expression: '$.order.items[0]' }, body, 'body')
+    ).toEqual({ body: { value: 'book' } });
+  });
+
+  it('ignores missing values and unsupported JSONPath expressions', () => {
+    const json = (expression: string) =>
+      getTransformationVars({ type: SupportedTransformationType.Json, expression }, body, 'body');
+
+    expect(json('$.order.missing')).toEqual({});
+    expect(json('$.order.items[?(@ == "book")]')).toEqual({});
+    const notJSON = getTransformationVars({ type: SupportedTransformationType.Json, expression: '$.id' }, 'id=1', 'body');
+    expect(notJSON).toEqual({});
+  });
+
+  it('copies all labels or a single one', () => {
+    const labels = { app: 'checkout', env: 'prod' } as unknown as string;
+
+    expect(getTransformationVars({ type: SupportedTransformationType.Label }, labels, 'message')).toEqual({
+      app: { value: 'checkout' },
+      env: { value: 'prod' },
+    });
+    expect(
+      getTransformationVars(
+        { type: SupportedTransformationType.Label, expression: 'app', mapValue: 'service' },
+        labels,
+        'message'
+      )
+    ).toEqual({ service: { value: 'checkout' } });
+  });
+
+  it('combines variables with a template', () => {
+    const variables = { app: { value: 'checkout' }, orderId: { value: '42' } };
+    const template = (expression: string) =>
+      getTransformationVars(
+        { type: SupportedTransformationType.Template, expression, mapValue: 'path' },
+        '',
+        'message',
+        variables
+      );
+
+    expect(template('{{ .app }}/orders/{{.orderId}}')).toEqual({ path: { value: 'checkout/orders/42' } });
+    expect(template('{{ .app }}/{{ .missing }}')).toEqual({});
+  });
+});
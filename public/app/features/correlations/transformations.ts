@@ -3,13 +3,25 @@ import logfmt from 'logfmt';
 import { ScopedVars, DataLinkTransformationConfig, SupportedTransformationType } from '@grafana/data';
 import { safeStringifyValue } from 'app/core/utils/explore';
 
+// DEFAULT_LABELS_FIELD is the field with the labels of the log lines in data plane logs frames
+export const DEFAULT_LABELS_FIELD = 'labels';
+
+// Field names and array indexes of JSONPath expressions, the only JSONPath syntax the backend accepts
+const jsonPathSegmentRegex = /\.([\w-]+)|\[(\d+)\]/g;
+const templateVariableRegex = /\{\{\s*\.(\w+)\s*\}\}/g;
+
+/**
+ * Returns the variables extracted by the transformation. `variables` are the fields of the row and the
+ * variables extracted by the previous transformations, which template transformations can reference.
+ */
 export const getTransformationVars = (
   transformation: DataLinkTransformationConfig,
   fieldValue: string,
-  fieldName: string
+  fieldName: string,
+  variables: ScopedVars = {}
 ): ScopedVars => {
   let transformationScopedVars: ScopedVars = {};
-  let transformVal: { [key: string]: string | boolean | null | undefined } = {};
+  let transformVal: { [key: string]: unknown } = {};
   if (transformation.type === SupportedTransformationType.Regex && transformation.expression) {
     const regexp = new RegExp(transformation.expression, 'gi');
     const stringFieldVal = typeof fieldValue === 'string' ? fieldValue : safeStringifyValue(fieldValue);
@@ -24,6 +36,38 @@ export const getTransformationVars = (
     }
   } else if (transformation.type === SupportedTransformationType.Logfmt && fieldValue !== undefined) {
     transformVal = logfmt.parse(fieldValue);
+  } else if (transformation.type === SupportedTransformationType.Json && transformation.expression) {
+    const value = getJSONPathValue(parseJSONValue(fieldValue), transformation.expression);
+    if (value !== undefined) {
+      transformVal[transformation.mapValue || fieldName] = value;
+    }
+  } else if (transformation.type === SupportedTransformationType.Label) {
+    const labels = parseJSONValue(fieldValue);
+    if (isRecord(labels)) {
+      if (!transformation.expression) {
+        transformVal = labels;
+      } else if (transformation.expression in labels) {
+        transformVal[transformation.mapValue || transformation.expression] = labels[transformation.expression];
+      }
+    }
+  } else if (
+    transformation.type === SupportedTransformationType.Template &&
+    transformation.expression &&
+    transformation.mapValue
+  ) {
+    let missingVariable = false;
+    const value = transformation.expression.replace(templateVariableRegex, (_, name: string) => {
+      if (variables[name] === undefined) {
+        missingVariable = true;
+        return '';
+      }
+      const variableValue = variables[name].value;
+      return typeof variableValue === 'string' ? variableValue : safeStringifyValue(variableValue);
+    });
+    // like the backend, a template referencing a missing variable doesn't create a variable
+    if (!missingVariable) {
+      transformVal[transformation.mapValue] = value;
+    }
   }
 
   Object.keys(transformVal).forEach((key) => {
@@ -34,3 +78,44 @@ export const getTransformationVars = (
 
   return transformationScopedVars;
 };
+
+function parseJSONValue(value: unknown): unknown {
+  if (typeof value !== 'string') {
+    return value;
+  }
+  try {
+    return JSON.parse(value);
+  } catch (e) {
+    // a value that is not JSON has nothing to extract
+    return undefined;
+  }
+}
+
+function getJSONPathValue(data: unknown, expression: string): unknown {
+  if (!expression.startsWith('$')) {
+    return undefined;
+  }
+  let value = data;
+  let position = 1;
+  for (const segment of expression.matchAll(jsonPathSegmentRegex)) {
+    if (segment.index !== position) {
+      // unsupported JSONPath syntax
+      return undefined;
+    }
+    position += segment[0].length;
+
+    const [, key, index] = segment;
+    if (key !== undefined && isRecord(value)) {
+      value = value[key];
+    } else if (index !== undefined && Array.isArray(value)) {
+      value = value[Number(index)];
+    } else {
+      return undefined;
+    }
+  }
+  return position === expression.length ? value : undefined;
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+  return typeof value === 'object' && value !== null && !Array.isArray(value);
+}
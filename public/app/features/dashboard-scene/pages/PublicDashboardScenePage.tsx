@@ -2,7 +2,7 @@ import { css } from '@emotion/css';
 import { useEffect, useState } from 'react';
 import { useParams } from 'react-router-dom-v5-compat';
 
-import { GrafanaTheme2, PageLayoutType } from '@grafana/data';
+import { GrafanaTheme2, PageLayoutType, VariableHide } from '@grafana/data';
 import { selectors as e2eSelectors } from '@grafana/e2e-selectors';
 import { SceneComponentProps, UrlSyncContextProvider } from '@grafana/scenes';
 import { Alert, Box, Icon, Stack, useStyles2 } from '@grafana/ui';
@@ -20,6 +20,7 @@ import { AppNotificationSeverity } from 'app/types/appNotifications';
 import { DashboardRoutes } from 'app/types/dashboard';
 
 import { DashboardScene } from '../scene/DashboardScene';
+import { VariableControls } from '../scene/VariableControls';
 
 import { getDashboardScenePageStateManager, LoadError } from './DashboardScenePageStateManager';
 
@@ -70,11 +71,15 @@ export function PublicDashboardScenePage({ route }: Props) {
 
 function PublicDashboardSceneRenderer({ model }: SceneComponentProps<DashboardScene>) {
   const [isActive, setIsActive] = useState(false);
-  const { controls, title } = model.useState();
+  const { controls, title, $variables } = model.useState();
   const { timePicker, refreshPicker, hideTimeControls } = controls!.useState();
   const bodyToRender = model.getBodyToRender();
   const styles = useStyles2(getStyles);
   const conf = useGetPublicDashboardConfig();
+  // only the variables viewers are allowed to change are visible
+  const hasVariables = $variables?.state.variables.some(
+    (variable) => variable.state.hide !== VariableHide.hideVariable
+  );
 
   useEffect(() => {
     return refreshPicker.activate();
@@ -107,6 +112,11 @@ function PublicDashboardSceneRenderer({ model }: SceneComponentProps<DashboardSc
           </Stack>
         )}
       </div>
+      {hasVariables && (
+        <div className={styles.variables}>
+          <VariableControls dashboard={model} />
+        </div>
+      )}
       <div className={styles.body}>
         <bodyToRender.Component model={bodyToRender} />
       </div>
@@ -138,6 +148,12 @@ function getStyles(theme: GrafanaTheme2) {
         alignItems: 'stretch',
       },
     }),
+    variables: css({
+      display: 'flex',
+      flexWrap: 'wrap',
+      gap: theme.spacing(1),
+      paddingBottom: theme.spacing(2),
+    }),
     iconTitle: css({
       display: 'none',
       [theme.breakpoints.up('sm')]: {
@@ -3,6 +3,7 @@ import { EmailSharingPricingAlert } from 'app/features/dashboard/components/Shar
 import { UnsupportedDataSourcesAlert } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/UnsupportedDataSourcesAlert';
 import { UnsupportedTemplateVariablesAlert } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/UnsupportedTemplateVariablesAlert';
 import {
+  getNotAllowedTemplateVariables,
   isEmailSharingEnabled,
   PublicDashboard,
   PublicDashboardShareType,
@@ -13,16 +14,19 @@ import { NoUpsertPermissionsAlert } from '../../../../dashboard/components/Share
 import { PublicDashboardAlert } from '../../../../dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/PublicDashboardAlert';
 import { useShareDrawerContext } from '../../ShareDrawer/ShareDrawerContext';
 import { useUnsupportedDatasources } from '../../public-dashboards/hooks';
+import { getPublicDashboardVariables } from '../../public-dashboards/utils';
 
 export default function ShareAlerts({ publicDashboard }: { publicDashboard?: PublicDashboard }) {
   const { dashboard } = useShareDrawerContext();
   const hasWritePermissions = contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite);
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
+  const notAllowedVariables = getNotAllowedTemplateVariables(getPublicDashboardVariables(dashboard), publicDashboard);
 
   return (
     <>
-      {hasWritePermissions && hasTemplateVariables && <UnsupportedTemplateVariablesAlert showDescription={false} />}
+      {hasWritePermissions && notAllowedVariables.length > 0 && (
+        <UnsupportedTemplateVariablesAlert variables={notAllowedVariables} />
+      )}
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode={publicDashboard ? 'edit' : 'create'} />}
       {hasWritePermissions && !!unsupportedDataSources?.length && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDataSources.join(', ')} />
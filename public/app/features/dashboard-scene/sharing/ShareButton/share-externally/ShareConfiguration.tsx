@@ -8,11 +8,14 @@ import { sceneGraph } from '@grafana/scenes';
 import { FieldSet, Icon, Label, Spinner, Stack, Switch, Text, TimeRangeLabel, Tooltip, useStyles2 } from '@grafana/ui';
 import { contextSrv } from 'app/core/core';
 import { publicDashboardApi, useUpdatePublicDashboardMutation } from 'app/features/dashboard/api/publicDashboardApi';
+import { AllowedVariablesConfiguration } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/AllowedVariablesConfiguration';
 import { ConfigPublicDashboardForm } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/ConfigPublicDashboard';
+import { AllowedVariable } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 import { DashboardInteractions } from 'app/features/dashboard-scene/utils/interactions';
 import { AccessControlAction } from 'app/types/accessControl';
 
 import { useShareDrawerContext } from '../../ShareDrawer/ShareDrawerContext';
+import { getPublicDashboardVariables } from '../../public-dashboards/utils';
 
 const selectors = e2eSelectors.pages.ShareDashboardDrawer.ShareExternally.Configuration;
 
@@ -31,6 +34,7 @@ export default function ShareConfiguration() {
   const disableForm = isLoading || !hasWritePermissions;
   const timeRangeState = sceneGraph.getTimeRange(dashboard);
   const timeRange = timeRangeState.useState();
+  const variables = getPublicDashboardVariables(dashboard);
 
   const { handleSubmit, setValue, control } = useForm<FormInput>({
     defaultValues: {
@@ -57,6 +61,16 @@ export default function ShareConfiguration() {
     });
   };
 
+  const onAllowedVariablesChange = (allowedVariables: AllowedVariable[]) => {
+    update({
+      dashboard: dashboard,
+      payload: {
+        ...publicDashboard!,
+        allowedVariables,
+      },
+    });
+  };
+
   return (
     <Stack direction="column" gap={2}>
       <Text element="p">
@@ -138,6 +152,12 @@ export default function ShareConfiguration() {
                   <Icon name="info-circle" size="md" />
                 </Tooltip>
               </Stack>
+              <AllowedVariablesConfiguration
+                variables={variables}
+                allowedVariables={publicDashboard?.allowedVariables}
+                disabled={disableForm}
+                onChange={onAllowedVariablesChange}
+              />
             </Stack>
           </FieldSet>
         </form>
@@ -17,6 +17,7 @@ import { ShareModal } from '../ShareModal';
 import { ConfirmModal } from './ConfirmModal';
 import { SharePublicDashboardTab } from './SharePublicDashboardTab';
 import { useUnsupportedDatasources } from './hooks';
+import { getPublicDashboardVariables } from './utils';
 
 interface Props extends SceneComponentProps<SharePublicDashboardTab> {
   publicDashboard?: PublicDashboard;
@@ -31,7 +32,7 @@ export function ConfigPublicDashboard({ model, publicDashboard, isGetLoading }:
   const dashboard = getDashboardSceneFor(model);
   const { isDirty } = dashboard.useState();
   const [deletePublicDashboard] = useDeletePublicDashboardMutation();
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
+  const variables = getPublicDashboardVariables(dashboard);
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
   const timeRangeState = sceneGraph.getTimeRange(model);
   const timeRange = timeRangeState.useState();
@@ -70,7 +71,7 @@ export function ConfigPublicDashboard({ model, publicDashboard, isGetLoading }:
       }}
       timeRange={timeRange.value}
       showSaveChangesAlert={hasWritePermissions && isDirty}
-      hasTemplateVariables={hasTemplateVariables}
+      variables={variables}
     />
   );
 }
@@ -1,21 +1,23 @@
 import { SceneComponentProps } from '@grafana/scenes';
 import { CreatePublicDashboardBase } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/CreatePublicDashboard/CreatePublicDashboard';
+import { getNotAllowedTemplateVariables } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 
 import { getDashboardSceneFor } from '../../utils/utils';
 
 import { SharePublicDashboardTab } from './SharePublicDashboardTab';
 import { useUnsupportedDatasources } from './hooks';
+import { getPublicDashboardVariables } from './utils';
 
 export function CreatePublicDashboard({ model }: SceneComponentProps<SharePublicDashboardTab>) {
   const dashboard = getDashboardSceneFor(model);
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
+  const notAllowedVariables = getNotAllowedTemplateVariables(getPublicDashboardVariables(dashboard));
 
   return (
     <CreatePublicDashboardBase
       dashboard={dashboard}
       unsupportedDatasources={unsupportedDataSources}
-      unsupportedTemplateVariables={hasTemplateVariables}
+      notAllowedVariables={notAllowedVariables}
     />
   );
 }
@@ -1,5 +1,9 @@
 import { DataSourceWithBackend } from '@grafana/runtime';
-import { VizPanel } from '@grafana/scenes';
+import { AdHocFiltersVariable, MultiValueVariable, VizPanel } from '@grafana/scenes';
+import {
+  getOptionValues,
+  PublicDashboardVariable,
+} from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 import { supportedDatasources } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SupportedPubdashDatasources';
 import { getDatasourceSrv } from 'app/features/plugins/datasource_srv';
 
@@ -23,6 +27,22 @@ export const getUnsupportedDashboardDatasources = async (types: string[]): Promi
   return Array.from(unsupportedDS);
 };
 
+export function getPublicDashboardVariables(scene: DashboardScene): PublicDashboardVariable[] {
+  const variables = scene.state.$variables?.state.variables ?? [];
+
+  // ad hoc filters are not referenced by name in queries, so they can't be allowed
+  return variables
+    .filter((variable) => !(variable instanceof AdHocFiltersVariable))
+    .map((variable) => ({
+      name: variable.state.name,
+      label: variable.state.label,
+      options:
+        variable instanceof MultiValueVariable
+          ? getOptionValues(variable.state.options.map((option) => String(option.value)))
+          : [],
+    }));
+}
+
 export function getPanelDatasourceTypes(scene: DashboardScene): string[] {
   const types = new Set<string>();
 
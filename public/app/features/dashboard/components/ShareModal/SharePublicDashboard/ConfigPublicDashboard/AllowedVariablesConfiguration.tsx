@@ -0,0 +1,58 @@
+import { Trans, t } from '@grafana/i18n';
+import { Field, FieldSet, MultiSelect, Stack, Text } from '@grafana/ui';
+
+import { AllowedVariable, PublicDashboardVariable } from '../SharePublicDashboardUtils';
+
+interface Props {
+  variables: PublicDashboardVariable[];
+  allowedVariables?: AllowedVariable[];
+  disabled: boolean;
+  onChange: (allowedVariables: AllowedVariable[]) => void;
+}
+
+export const AllowedVariablesConfiguration = ({ variables, allowedVariables = [], disabled, onChange }: Props) => {
+  if (variables.length === 0) {
+    return null;
+  }
+
+  const onValuesChange = (name: string, values: string[]) => {
+    const others = allowedVariables.filter((allowed) => allowed.name !== name);
+    // a variable without values is not allowed
+    onChange(values.length > 0 ? [...others, { name, values }] : others);
+  };
+
+  return (
+    <FieldSet disabled={disabled}>
+      <Stack direction="column" gap={1}>
+        <Text element="p">
+          <Trans i18nKey="public-dashboard.allowed-variables.title">Template variables</Trans>
+        </Text>
+        <Text element="p" color="secondary" variant="bodySmall">
+          <Trans i18nKey="public-dashboard.allowed-variables.description">
+            Viewers can switch between the values you allow. Queries using variables without allowed values are not
+            interpolated.
+          </Trans>
+        </Text>
+        {variables.map((variable) => {
+          const values = allowedVariables.find((allowed) => allowed.name === variable.name)?.values ?? [];
+          const options = Array.from(new Set([...variable.options, ...values]));
+          const inputId = `public-dashboard-allowed-variable-${variable.name}`;
+
+          return (
+            <Field key={variable.name} label={variable.label || variable.name} htmlFor={inputId} noMargin>
+              <MultiSelect
+                inputId={inputId}
+                allowCustomValue
+                disabled={disabled}
+                options={options.map((value) => ({ label: value, value }))}
+                value={values.map((value) => ({ label: value, value }))}
+                placeholder={t('public-dashboard.allowed-variables.placeholder', 'Not allowed')}
+                onChange={(selected) => onValuesChange(variable.name, selected.map(({ value }) => String(value)))}
+              />
+            </Field>
+          );
+        })}
+      </Stack>
+    </FieldSet>
+  );
+};
@@ -38,12 +38,16 @@ import { SaveDashboardChangesAlert } from '../ModalAlerts/SaveDashboardChangesAl
 import { UnsupportedDataSourcesAlert } from '../ModalAlerts/UnsupportedDataSourcesAlert';
 import { UnsupportedTemplateVariablesAlert } from '../ModalAlerts/UnsupportedTemplateVariablesAlert';
 import {
-  dashboardHasTemplateVariables,
+  AllowedVariable,
   generatePublicDashboardUrl,
+  getNotAllowedTemplateVariables,
+  getPublicDashboardVariables,
   isEmailSharingEnabled,
   PublicDashboard,
+  PublicDashboardVariable,
 } from '../SharePublicDashboardUtils';
 
+import { AllowedVariablesConfiguration } from './AllowedVariablesConfiguration';
 import { Configuration } from './Configuration';
 import { EmailSharingConfiguration } from './EmailSharingConfiguration';
 import { SettingsBar } from './SettingsBar';
@@ -61,7 +65,7 @@ interface Props {
   unsupportedDatasources?: string[];
   showSaveChangesAlert?: boolean;
   publicDashboard?: PublicDashboard;
-  hasTemplateVariables?: boolean;
+  variables?: PublicDashboardVariable[];
   timeRange: TimeRange;
   onRevoke: () => void;
   dashboard: DashboardModel | DashboardScene;
@@ -70,7 +74,7 @@ interface Props {
 export function ConfigPublicDashboardBase({
   onRevoke,
   timeRange,
-  hasTemplateVariables = false,
+  variables = [],
   showSaveChangesAlert = false,
   unsupportedDatasources = [],
   publicDashboard,
@@ -83,6 +87,7 @@ export function ConfigPublicDashboardBase({
   const [pauseOrResume, { isLoading: isPauseOrResumeLoading }] = usePauseOrResumePublicDashboardMutation();
   const hasWritePermissions = contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite);
   const disableInputs = !hasWritePermissions || isLoading || isPauseOrResumeLoading;
+  const notAllowedVariables = getNotAllowedTemplateVariables(variables, publicDashboard);
 
   const { handleSubmit, setValue, register } = useForm<ConfigPublicDashboardForm>({
     defaultValues: {
@@ -124,6 +129,16 @@ export function ConfigPublicDashboardBase({
     await handleSubmit((data) => onPublicDashboardUpdate(data))();
   };
 
+  const onAllowedVariablesChange = (allowedVariables: AllowedVariable[]) => {
+    update({
+      dashboard: dashboard,
+      payload: {
+        ...publicDashboard!,
+        allowedVariables,
+      },
+    });
+  };
+
   const onTogglePause = async (value: boolean) => {
     setValue('isPaused', value);
     await handleSubmit((data) => onPauseOrResume(data))();
@@ -137,7 +152,7 @@ export function ConfigPublicDashboardBase({
     <div className={styles.configContainer}>
       {showSaveChangesAlert && <SaveDashboardChangesAlert />}
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode="edit" />}
-      {hasTemplateVariables && <UnsupportedTemplateVariablesAlert />}
+      {notAllowedVariables.length > 0 && <UnsupportedTemplateVariablesAlert variables={notAllowedVariables} />}
       {unsupportedDatasources.length > 0 && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDatasources.join(', ')} />
       )}
@@ -205,6 +220,12 @@ export function ConfigPublicDashboardBase({
           data-testid={selectors.SettingsDropdown}
         >
           <Configuration disabled={disableInputs} onChange={onChange} register={register} timeRange={timeRange} />
+          <AllowedVariablesConfiguration
+            variables={variables}
+            allowedVariables={publicDashboard?.allowedVariables}
+            disabled={disableInputs}
+            onChange={onAllowedVariablesChange}
+          />
         </SettingsBar>
       </Field>
 
@@ -241,7 +262,7 @@ export function ConfigPublicDashboard({ publicDashboard, unsupportedDatasources
   const dashboard = dashboardState.getModel()!;
   const timeRange = getTimeRange(dashboard.getDefaultTime(), dashboard);
   const hasWritePermissions = contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite);
-  const hasTemplateVariables = dashboardHasTemplateVariables(dashboard.getVariables());
+  const variables = getPublicDashboardVariables(dashboard.getVariables());
   const [deletePublicDashboard] = useDeletePublicDashboardMutation();
   const onDeletePublicDashboardClick = (onDelete: () => void) => {
     deletePublicDashboard({
@@ -261,7 +282,7 @@ export function ConfigPublicDashboard({ publicDashboard, unsupportedDatasources
           unsupportedDatasources={unsupportedDatasources}
           timeRange={timeRange}
           showSaveChangesAlert={hasWritePermissions && dashboard.hasUnsavedChanges()}
-          hasTemplateVariables={hasTemplateVariables}
+          variables={variables}
           onRevoke={() => {
             DashboardInteractions.revokePublicDashboardClicked();
             showModal(DeletePublicDashboardModal, {
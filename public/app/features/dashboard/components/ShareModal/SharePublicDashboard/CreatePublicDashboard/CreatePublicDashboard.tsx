@@ -16,7 +16,7 @@ import { useSelector } from 'app/types/store';
 import { NoUpsertPermissionsAlert } from '../ModalAlerts/NoUpsertPermissionsAlert';
 import { UnsupportedDataSourcesAlert } from '../ModalAlerts/UnsupportedDataSourcesAlert';
 import { UnsupportedTemplateVariablesAlert } from '../ModalAlerts/UnsupportedTemplateVariablesAlert';
-import { dashboardHasTemplateVariables } from '../SharePublicDashboardUtils';
+import { getNotAllowedTemplateVariables, getPublicDashboardVariables } from '../SharePublicDashboardUtils';
 import { useGetUnsupportedDataSources } from '../useGetUnsupportedDataSources';
 
 import { AcknowledgeCheckboxes } from './AcknowledgeCheckboxes';
@@ -31,14 +31,14 @@ export type SharePublicDashboardAcknowledgmentInputs = {
 
 interface CreatePublicDashboarBaseProps {
   unsupportedDatasources?: string[];
-  unsupportedTemplateVariables?: boolean;
+  notAllowedVariables?: string[];
   dashboard: DashboardModel | DashboardScene;
   hasError?: boolean;
 }
 
 export const CreatePublicDashboardBase = ({
   unsupportedDatasources = [],
-  unsupportedTemplateVariables = false,
+  notAllowedVariables = [],
   dashboard,
   hasError = false,
 }: CreatePublicDashboarBaseProps) => {
@@ -65,14 +65,14 @@ export const CreatePublicDashboardBase = ({
         </p>
         <p className={styles.description}>
           <Trans i18nKey="public-dashboard.create-page.unsupported-features-desc">
-            Currently, we don’t support template variables or frontend data sources
+            Currently, we don’t support frontend data sources
           </Trans>
         </p>
       </div>
 
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode="create" />}
 
-      {unsupportedTemplateVariables && <UnsupportedTemplateVariablesAlert />}
+      {notAllowedVariables.length > 0 && <UnsupportedTemplateVariablesAlert variables={notAllowedVariables} />}
 
       {unsupportedDatasources.length > 0 && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDatasources.join(', ')} />
@@ -97,13 +97,13 @@ export function CreatePublicDashboard({ hasError }: { hasError?: boolean }) {
   const dashboardState = useSelector((store) => store.dashboard);
   const dashboard = dashboardState.getModel()!;
   const { unsupportedDataSources } = useGetUnsupportedDataSources(dashboard);
-  const hasTemplateVariables = dashboardHasTemplateVariables(dashboard.getVariables());
+  const notAllowedVariables = getNotAllowedTemplateVariables(getPublicDashboardVariables(dashboard.getVariables()));
 
   return (
     <CreatePublicDashboardBase
       dashboard={dashboard}
       unsupportedDatasources={unsupportedDataSources}
-      unsupportedTemplateVariables={hasTemplateVariables}
+      notAllowedVariables={notAllowedVariables}
       hasError={hasError}
     />
   );
@@ -4,20 +4,30 @@ import { Alert } from '@grafana/ui';
 
 const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard;
 
-export const UnsupportedTemplateVariablesAlert = ({ showDescription = true }: { showDescription?: boolean }) => {
+export const UnsupportedTemplateVariablesAlert = ({
+  variables,
+  showDescription = true,
+}: {
+  variables: string[];
+  showDescription?: boolean;
+}) => {
   return (
     <Alert
       severity="warning"
       title={t(
         'public-dashboard.modal-alerts.unsupported-template-variable-alert-title',
-        'Template variables are not supported'
+        'Some template variables are not allowed'
       )}
       data-testid={selectors.TemplateVariablesWarningAlert}
       bottomSpacing={0}
     >
       {showDescription && (
-        <Trans i18nKey="public-dashboard.modal-alerts.unsupported-template-variable-alert-desc">
-          This public dashboard may not work since it uses template variables
+        <Trans
+          i18nKey="public-dashboard.modal-alerts.unsupported-template-variable-alert-desc"
+          values={{ variables: variables.join(', ') }}
+        >
+          Queries using {'{{variables}}'} are not interpolated until you allow values for them in the public dashboard
+          settings
         </Trans>
       )}
     </Alert>
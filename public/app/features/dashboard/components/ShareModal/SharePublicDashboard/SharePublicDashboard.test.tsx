@@ -116,7 +116,7 @@ const alertTests = () => {
     expect(screen.queryByTestId(selectors.NoUpsertPermissionsWarningAlert)).toBeInTheDocument();
   });
   it('when dashboard has template variables, warning is shown', async () => {
-    jest.spyOn(sharePublicDashboardUtils, 'dashboardHasTemplateVariables').mockReturnValue(true);
+    jest.spyOn(sharePublicDashboardUtils, 'getNotAllowedTemplateVariables').mockReturnValue(['custom']);
 
     await renderSharePublicDashboard();
     expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).toBeInTheDocument();
@@ -6,7 +6,8 @@ import { PanelModel } from 'app/features/dashboard/state/PanelModel';
 
 import {
   PublicDashboard,
-  dashboardHasTemplateVariables,
+  getNotAllowedTemplateVariables,
+  getPublicDashboardVariables,
   publicDashboardPersisted,
   generatePublicDashboardUrl,
   getUnsupportedDashboardDatasources,
@@ -34,16 +35,41 @@ jest.mock('@grafana/runtime', () => ({
   }),
 }));
 
-describe('dashboardHasTemplateVariables', () => {
-  it('false', () => {
-    let variables: TypedVariableModel[] = [];
-    expect(dashboardHasTemplateVariables(variables)).toBe(false);
+describe('getPublicDashboardVariables', () => {
+  it('returns the options of the variables except ad hoc filters', () => {
+    const variables = [
+      {
+        type: 'custom',
+        name: 'region',
+        label: 'Region',
+        options: [
+          { text: 'All', value: '$__all', selected: false },
+          { text: 'us-east', value: 'us-east', selected: true },
+          { text: 'eu-west', value: 'eu-west', selected: false },
+        ],
+      },
+      { type: 'adhoc', name: 'filters' },
+    ] as unknown as TypedVariableModel[];
+
+    expect(getPublicDashboardVariables(variables)).toEqual([
+      { name: 'region', label: 'Region', options: ['us-east', 'eu-west'] },
+    ]);
   });
+});
 
-  it('true', () => {
-    //@ts-ignore
-    let variables: TypedVariableModel[] = ['a'];
-    expect(dashboardHasTemplateVariables(variables)).toBe(true);
+describe('getNotAllowedTemplateVariables', () => {
+  const variables = [
+    { name: 'region', options: [] },
+    { name: 'service', options: [] },
+  ];
+
+  it('returns every variable without a public dashboard', () => {
+    expect(getNotAllowedTemplateVariables(variables)).toEqual(['region', 'service']);
+  });
+
+  it('leaves out the allowed variables', () => {
+    const pubdash = { allowedVariables: [{ name: 'region', values: ['us-east'] }] } as PublicDashboard;
+    expect(getNotAllowedTemplateVariables(variables, pubdash)).toEqual(['service']);
   });
 });
 
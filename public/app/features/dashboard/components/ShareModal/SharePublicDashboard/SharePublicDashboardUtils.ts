@@ -2,6 +2,7 @@ import { TypedVariableModel } from '@grafana/data';
 import { config, DataSourceWithBackend, featureEnabled } from '@grafana/runtime';
 import { getConfig } from 'app/core/config';
 import { getDatasourceSrv } from 'app/features/plugins/datasource_srv';
+import { ALL_VARIABLE_VALUE } from 'app/features/variables/constants';
 
 import { PanelModel } from '../../../state/PanelModel';
 import { shareDashboardType } from '../utils';
@@ -26,6 +27,24 @@ export interface PublicDashboard extends PublicDashboardSettings {
   dashboardUid: string;
   timeSettings?: object;
   recipients?: Array<{ uid: string; recipient: string }>;
+  allowedVariables?: AllowedVariable[];
+}
+
+/**
+ * Template variable that viewers of the public dashboard can change, with the values they can select.
+ */
+export interface AllowedVariable {
+  name: string;
+  values: string[];
+}
+
+/**
+ * Template variable of the dashboard that can be allowed in the public dashboard.
+ */
+export interface PublicDashboardVariable {
+  name: string;
+  label?: string;
+  options: string[];
 }
 
 export interface SessionDashboard {
@@ -43,8 +62,32 @@ export interface SessionUser {
 }
 
 // Instance methods
-export const dashboardHasTemplateVariables = (variables: TypedVariableModel[]): boolean => {
-  return variables.length > 0;
+export const getPublicDashboardVariables = (variables: TypedVariableModel[]): PublicDashboardVariable[] => {
+  // ad hoc filters are not referenced by name in queries, so they can't be allowed
+  return variables
+    .filter((variable) => variable.type !== 'adhoc')
+    .map((variable) => ({
+      name: variable.name,
+      label: variable.label,
+      options: 'options' in variable ? getOptionValues(variable.options.map((option) => option.value)) : [],
+    }));
+};
+
+export const getOptionValues = (values: Array<string | string[]>): string[] => {
+  return Array.from(new Set(values.flat().filter((value) => value !== ALL_VARIABLE_VALUE)));
+};
+
+/**
+ * Returns the names of the template variables that viewers are not allowed to change. Queries using them are
+ * not interpolated.
+ */
+export const getNotAllowedTemplateVariables = (
+  variables: PublicDashboardVariable[],
+  publicDashboard?: PublicDashboard
+): string[] => {
+  return variables
+    .filter((variable) => !publicDashboard?.allowedVariables?.some((allowed) => allowed.name === variable.name))
+    .map((variable) => variable.name);
 };
 
 export const publicDashboardPersisted = (publicDashboard?: PublicDashboard): boolean => {
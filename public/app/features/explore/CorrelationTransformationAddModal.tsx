@@ -3,7 +3,7 @@ import { useId, useState, useMemo, useEffect } from 'react';
 import Highlighter from 'react-highlight-words';
 import { useForm, Controller } from 'react-hook-form';
 
-import { DataLinkTransformationConfig, ScopedVars } from '@grafana/data';
+import { DataLinkTransformationConfig, ScopedVars, SupportedTransformationType } from '@grafana/data';
 import { Trans, t } from '@grafana/i18n';
 import { Button, Field, Icon, Input, Label, Modal, Select, Tooltip, Stack } from '@grafana/ui';
 
@@ -35,6 +35,9 @@ const LabelWithTooltip = ({ label, tooltipText }: { label: string; tooltipText:
   </Stack>
 );
 
+const getFieldVars = (fieldList: Record<string, string>): ScopedVars =>
+  Object.fromEntries(Object.entries(fieldList).map(([name, value]) => [name, { value }]));
+
 export const CorrelationTransformationAddModal = ({
   onSave,
   onCancel,
@@ -70,7 +73,8 @@ export const CorrelationTransformationAddModal = ({
             mapValue: transformationToEdit?.mapValue,
           },
           exampleVal || '',
-          transformationToEdit?.field!
+          transformationToEdit?.field!,
+          getFieldVars(fieldList)
         );
         setTransformationVars({ ...transformationVars });
         setValidToSave(true);
@@ -88,15 +92,19 @@ export const CorrelationTransformationAddModal = ({
   const id = useId();
 
   useEffect(() => {
+    // template transformations can reference every field of the row
+    const fieldVars = getFieldVars(fieldList);
     const subscription = watch((formValues) => {
       const expression = formValues.expression;
       let isExpressionValid = false;
       if (expression !== undefined) {
         isExpressionValid = true;
-        try {
-          new RegExp(expression);
-        } catch (e) {
-          isExpressionValid = false;
+        if (formValues.type === SupportedTransformationType.Regex) {
+          try {
+            new RegExp(expression);
+          } catch (e) {
+            isExpressionValid = false;
+          }
         }
       } else {
         isExpressionValid = !formFieldsVis.expressionDetails.show;
@@ -111,7 +119,8 @@ export const CorrelationTransformationAddModal = ({
             mapValue: formValues.mapValue,
           },
           fieldList[formValues.field!] || '',
-          formValues.field!
+          formValues.field!,
+          fieldVars
         );
 
         transKeys = Object.keys(transformationVars);
@@ -0,0 +1,164 @@
+import { useState } from 'react';
+import { useAsyncRetry, useDebounce } from 'react-use';
+
+import { dateTimeFormat } from '@grafana/data';
+import { Trans, t } from '@grafana/i18n';
+import { getDataSourceSrv } from '@grafana/runtime';
+import {
+  Alert,
+  Button,
+  Card,
+  ConfirmButton,
+  Drawer,
+  EmptyState,
+  FilterInput,
+  LoadingPlaceholder,
+  Stack,
+  TagList,
+} from '@grafana/ui';
+import { notifyApp } from 'app/core/actions';
+import { createErrorNotification, createSuccessNotification } from 'app/core/copy/appNotification';
+import { contextSrv } from 'app/core/services/context_srv';
+import { dispatch } from 'app/store/store';
+import { AccessControlAction } from 'app/types/accessControl';
+
+import { deleteSavedQuery, markSavedQueryUsed, SavedQueryDTO, searchSavedQueries } from './savedQueries';
+import { OnSelectQueryType } from './types';
+
+export interface Props {
+  /** Data source names the list is filtered by. */
+  datasourceFilters: string[];
+  onSelectQuery: OnSelectQueryType;
+  isReplacingQuery?: boolean;
+  highlightQuery?: string;
+  onEdit: (savedQuery: SavedQueryDTO) => void;
+  onDismiss: () => void;
+}
+
+export const SavedQueriesDrawer = ({
+  datasourceFilters,
+  onSelectQuery,
+  isReplacingQuery,
+  highlightQuery,
+  onEdit,
+  onDismiss,
+}: Props) => {
+  const [searchString, setSearchString] = useState('');
+  const [debouncedSearchString, setDebouncedSearchString] = useState('');
+  useDebounce(() => setDebouncedSearchString(searchString), 300, [searchString]);
+
+  const datasourceUids = datasourceFilters
+    .map((name) => getDataSourceSrv().getInstanceSettings(name)?.uid)
+    .filter((uid): uid is string => Boolean(uid));
+
+  const { value, loading, error, retry } = useAsyncRetry(
+    () => searchSavedQueries({ datasourceUids, searchString: debouncedSearchString, sort: 'time-desc' }),
+    [datasourceUids.join(','), debouncedSearchString]
+  );
+
+  const onUse = async (savedQuery: SavedQueryDTO) => {
+    for (const query of savedQuery.queries) {
+      onSelectQuery(query);
+      if (isReplacingQuery) {
+        break;
+      }
+    }
+    onDismiss();
+    await markSavedQueryUsed(savedQuery.uid);
+  };
+
+  const onDelete = async (savedQuery: SavedQueryDTO) => {
+    try {
+      await deleteSavedQuery(savedQuery.uid);
+      dispatch(
+        notifyApp(createSuccessNotification(t('explore.saved-queries-drawer.notification-deleted', 'Query deleted')))
+      );
+      retry();
+    } catch (err) {
+      dispatch(
+        notifyApp(
+          createErrorNotification(
+            t('explore.saved-queries-drawer.notification-delete-failed', 'Failed to delete query')
+          )
+        )
+      );
+    }
+  };
+
+  const visibilityLabels = {
+    private: t('explore.saved-queries-drawer.visibility.private', 'Only me'),
+    team: t('explore.saved-queries-drawer.visibility.team', 'Team'),
+    org: t('explore.saved-queries-drawer.visibility.org', 'Organization'),
+  };
+  const canWrite = contextSrv.hasPermission(AccessControlAction.SavedQueriesWrite);
+  const canDelete = contextSrv.hasPermission(AccessControlAction.SavedQueriesDelete);
+
+  return (
+    <Drawer title={t('explore.saved-queries-drawer.title', 'Saved queries')} onClose={onDismiss} size="md">
+      <Stack direction="column" gap={2}>
+        <FilterInput
+          placeholder={t('explore.saved-queries-drawer.placeholder-search', 'Search by title, description or tag')}
+          value={searchString}
+          onChange={setSearchString}
+          escapeRegex={false}
+        />
+        {error && (
+          <Alert title={t('explore.saved-queries-drawer.error-title', 'Failed to load saved queries')}>
+            {error.message}
+          </Alert>
+        )}
+        {loading && !value && (
+          <LoadingPlaceholder text={t('explore.saved-queries-drawer.loading', 'Loading saved queries...')} />
+        )}
+        {value && value.savedQueries.length === 0 && (
+          <EmptyState variant="not-found" message={t('explore.saved-queries-drawer.empty', 'No saved queries found')} />
+        )}
+        {value?.savedQueries.map((savedQuery) => (
+          <Card key={savedQuery.uid} isSelected={savedQuery.uid === highlightQuery}>
+            <Card.Heading>{savedQuery.title}</Card.Heading>
+            <Card.Description>{savedQuery.description}</Card.Description>
+            <Card.Meta>
+              {visibilityLabels[savedQuery.visibility]}
+              {getDataSourceSrv().getInstanceSettings(savedQuery.datasourceUid)?.name ?? savedQuery.datasourceType}
+              {t('explore.saved-queries-drawer.usage-count', 'Used {{count}} times', {
+                count: savedQuery.usageCount,
+              })}
+              {dateTimeFormat(savedQuery.updatedAt * 1000)}
+            </Card.Meta>
+            <Card.Tags>
+              <TagList tags={savedQuery.tags} />
+            </Card.Tags>
+            <Card.Actions>
+              <Button variant="primary" onClick={() => onUse(savedQuery)}>
+                {isReplacingQuery ? (
+                  <Trans i18nKey="explore.saved-queries-drawer.button-replace">Replace query</Trans>
+                ) : (
+                  <Trans i18nKey="explore.saved-queries-drawer.button-use">Use query</Trans>
+                )}
+              </Button>
+            </Card.Actions>
+            <Card.SecondaryActions>
+              {canWrite && (
+                <Button
+                  variant="secondary"
+                  icon="share-alt"
+                  tooltip={t('explore.saved-queries-drawer.tooltip-edit', 'Edit and share')}
+                  onClick={() => onEdit(savedQuery)}
+                />
+              )}
+              {canDelete && (
+                <ConfirmButton
+                  confirmText={t('explore.saved-queries-drawer.button-confirm-delete', 'Delete')}
+                  confirmVariant="destructive"
+                  onConfirm={() => onDelete(savedQuery)}
+                >
+                  <Trans i18nKey="explore.saved-queries-drawer.button-delete">Delete</Trans>
+                </ConfirmButton>
+              )}
+            </Card.SecondaryActions>
+          </Card>
+        ))}
+      </Stack>
+    </Drawer>
+  );
+};
@@ -0,0 +1,133 @@
+import { screen, waitFor } from '@testing-library/react';
+import userEvent from '@testing-library/user-event';
+
+import { config } from '@grafana/runtime';
+import { contextSrv } from 'app/core/services/context_srv';
+
+import { render } from '../../../../test/test-utils';
+
+import { useQueryLibraryContext } from './QueryLibraryContext';
+import { SavedQueriesProvider } from './SavedQueriesProvider';
+import { createSavedQuery, markSavedQueryUsed, SavedQueryDTO, searchSavedQueries } from './savedQueries';
+
+jest.mock('./savedQueries', () => ({
+  ...jest.requireActual('./savedQueries'),
+  searchSavedQueries: jest.fn(),
+  createSavedQuery: jest.fn(),
+  markSavedQueryUsed: jest.fn(),
+}));
+
+jest.mock('@grafana/runtime', () => ({
+  ...jest.requireActual('@grafana/runtime'),
+  getDataSourceSrv: () => ({
+    getInstanceSettings: (name: string) =>
+      name === 'Prometheus' ? { uid: 'prom-uid', name: 'Prometheus' } : undefined,
+  }),
+}));
+
+const savedQuery: SavedQueryDTO = {
+  uid: 'sq-1',
+  title: 'HTTP error rate',
+  description: 'Rate of 5xx responses',
+  tags: ['http'],
+  datasourceUid: 'prom-uid',
+  datasourceType: 'prometheus',
+  queries: [{ refId: 'A' }],
+  visibility: 'org',
+  teamId: 0,
+  version: 1,
+  usageCount: 3,
+  lastUsedAt: 0,
+  createdBy: 1,
+  createdAt: 0,
+  updatedBy: 1,
+  updatedAt: 0,
+};
+
+const onSelectQuery = jest.fn();
+
+function Consumer() {
+  const { queryLibraryEnabled, openDrawer, renderSaveQueryButton } = useQueryLibraryContext();
+  if (!queryLibraryEnabled) {
+    return <span>disabled</span>;
+  }
+  return (
+    <>
+      <button onClick={() => openDrawer(['Prometheus'], onSelectQuery)}>open library</button>
+      {renderSaveQueryButton({ refId: 'B', datasource: { uid: 'prom-uid', type: 'prometheus' } })}
+    </>
+  );
+}
+
+describe('SavedQueriesProvider', () => {
+  const queryHistoryEnabled = config.queryHistoryEnabled;
+
+  beforeEach(() => {
+    config.queryHistoryEnabled = true;
+    jest.spyOn(contextSrv, 'hasPermission').mockReturnValue(true);
+    jest
+      .mocked(searchSavedQueries)
+      .mockResolvedValue({ savedQueries: [savedQuery], totalCount: 1, page: 1, perPage: 100 });
+    jest.mocked(createSavedQuery).mockResolvedValue(savedQuery);
+    jest.mocked(markSavedQueryUsed).mockResolvedValue();
+  });
+
+  afterEach(() => {
+    config.queryHistoryEnabled = queryHistoryEnabled;
+    jest.clearAllMocks();
+  });
+
+  it('should disable the library without the read permission', () => {
+    jest.spyOn(contextSrv, 'hasPermission').mockReturnValue(false);
+    render(
+      <SavedQueriesProvider>
+        <Consumer />
+      </SavedQueriesProvider>
+    );
+
+    expect(screen.getByText('disabled')).toBeInTheDocument();
+  });
+
+  it('should list the saved queries of the data source and use the selected query', async () => {
+    render(
+      <SavedQueriesProvider>
+        <Consumer />
+      </SavedQueriesProvider>
+    );
+
+    await userEvent.click(screen.getByRole('button', { name: 'open library' }));
+
+    expect(await screen.findByText('HTTP error rate')).toBeInTheDocument();
+    expect(searchSavedQueries).toHaveBeenCalledWith(expect.objectContaining({ datasourceUids: ['prom-uid'] }));
+
+    await userEvent.click(screen.getByRole('button', { name: /Use query/i }));
+
+    expect(onSelectQuery).toHaveBeenCalledWith({ refId: 'A' });
+    expect(markSavedQueryUsed).toHaveBeenCalledWith('sq-1');
+  });
+
+  it('should save a query shared with the organization', async () => {
+    render(
+      <SavedQueriesProvider>
+        <Consumer />
+      </SavedQueriesProvider>
+    );
+
+    await userEvent.click(screen.getByRole('button', { name: 'Save query' }));
+    await userEvent.type(screen.getByLabelText(/Title/), 'Error budget');
+    await userEvent.click(screen.getByLabelText('Organization'));
+    await userEvent.click(screen.getByRole('button', { name: 'Save' }));
+
+    await waitFor(() =>
+      expect(createSavedQuery).toHaveBeenCalledWith(
+        expect.objectContaining({
+          title: 'Error budget',
+          datasourceUid: 'prom-uid',
+          datasourceType: 'prometheus',
+          queries: [{ refId: 'B', datasource: { uid: 'prom-uid', type: 'prometheus' } }],
+          visibility: 'org',
+        })
+      )
+    );
+  });
+});
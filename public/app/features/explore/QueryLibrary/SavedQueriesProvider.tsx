@@ -0,0 +1,178 @@
+import { PropsWithChildren, useCallback, useMemo, useState } from 'react';
+
+import { CoreApp } from '@grafana/data';
+import { t } from '@grafana/i18n';
+import { config } from '@grafana/runtime';
+import { DataQuery } from '@grafana/schema';
+import { Button } from '@grafana/ui';
+import { notifyApp } from 'app/core/actions';
+import { createErrorNotification, createSuccessNotification } from 'app/core/copy/appNotification';
+import { contextSrv } from 'app/core/services/context_srv';
+import { dispatch } from 'app/store/store';
+import { AccessControlAction } from 'app/types/accessControl';
+
+import { QueryLibraryContext, QueryLibraryContextType } from './QueryLibraryContext';
+import { SavedQueriesDrawer } from './SavedQueriesDrawer';
+import { SavedQueryModal } from './SavedQueryModal';
+import {
+  createSavedQuery,
+  getSavedQuery,
+  SavedQueryCommand,
+  SavedQueryDTO,
+  toUpdateCommand,
+  updateSavedQuery,
+} from './savedQueries';
+import { OnSelectQueryType } from './types';
+
+type DrawerState = {
+  datasourceFilters: string[];
+  onSelectQuery: OnSelectQueryType;
+  isReplacingQuery?: boolean;
+  highlightQuery?: string;
+};
+
+type ModalState =
+  | { query: DataQuery; title?: string; onSave?: () => void; savedQuery?: undefined }
+  | { savedQuery: SavedQueryDTO; query?: undefined; title?: undefined; onSave?: undefined };
+
+/**
+ * Provides the saved query library of the query history service to Explore and the query editor. Users need the
+ * savedqueries:read action to see the library.
+ */
+export function SavedQueriesProvider({ children }: PropsWithChildren) {
+  const [drawer, setDrawer] = useState<DrawerState>();
+  const [modal, setModal] = useState<ModalState>();
+  const [context, setContext] = useState('unknown');
+
+  const queryLibraryEnabled =
+    config.queryHistoryEnabled && contextSrv.hasPermission(AccessControlAction.SavedQueriesRead);
+
+  const openDrawer = useCallback<QueryLibraryContextType['openDrawer']>((datasourceFilters, onSelectQuery, options) => {
+    setContext(options?.context ?? 'unknown');
+    setDrawer({
+      datasourceFilters,
+      onSelectQuery,
+      isReplacingQuery: options?.isReplacingQuery,
+      highlightQuery: options?.highlightQuery,
+    });
+  }, []);
+
+  const openAddQueryModal = useCallback<QueryLibraryContextType['openAddQueryModal']>((query, options) => {
+    setContext(options?.context ?? 'unknown');
+    setModal({ query, title: options?.title, onSave: options?.onSave });
+  }, []);
+
+  const onModalSave = async (cmd: SavedQueryCommand) => {
+    if (!modal) {
+      return;
+    }
+    try {
+      if (modal.savedQuery) {
+        await updateSavedQuery(modal.savedQuery.uid, cmd);
+      } else {
+        await createSavedQuery(cmd);
+      }
+      dispatch(
+        notifyApp(createSuccessNotification(t('explore.saved-queries.notification-saved', 'Query saved to library')))
+      );
+      modal.onSave?.();
+      setModal(undefined);
+    } catch (err) {
+      dispatch(
+        notifyApp(createErrorNotification(t('explore.saved-queries.notification-save-failed', 'Failed to save query')))
+      );
+    }
+  };
+
+  const renderSaveQueryButton = useCallback<QueryLibraryContextType['renderSaveQueryButton']>(
+    (query: DataQuery, app?: CoreApp, queryLibraryRef?: string, onUpdateSuccess?: () => void) => {
+      if (!queryLibraryEnabled) {
+        return null;
+      }
+      if (!queryLibraryRef) {
+        if (!contextSrv.hasPermission(AccessControlAction.SavedQueriesCreate)) {
+          return null;
+        }
+        return (
+          <Button
+            variant="secondary"
+            size="sm"
+            fill="text"
+            icon="save"
+            aria-label={t('explore.saved-queries.button-save', 'Save query')}
+            tooltip={t('explore.saved-queries.tooltip-save', 'Save query to library')}
+            onClick={() => openAddQueryModal(query, { context: app })}
+          />
+        );
+      }
+      if (!contextSrv.hasPermission(AccessControlAction.SavedQueriesWrite)) {
+        return null;
+      }
+      return (
+        <Button
+          variant="secondary"
+          size="sm"
+          fill="text"
+          icon="save"
+          aria-label={t('explore.saved-queries.button-update', 'Update saved query')}
+          tooltip={t('explore.saved-queries.tooltip-update', 'Update the saved query in the library')}
+          onClick={() => updateSavedQueryFromEditor(queryLibraryRef, query, onUpdateSuccess)}
+        />
+      );
+    },
+    [queryLibraryEnabled, openAddQueryModal]
+  );
+
+  const value = useMemo<QueryLibraryContextType>(
+    () => ({
+      openDrawer,
+      closeDrawer: () => setDrawer(undefined),
+      isDrawerOpen: Boolean(drawer),
+      openAddQueryModal,
+      closeAddQueryModal: () => setModal(undefined),
+      renderSaveQueryButton,
+      queryLibraryEnabled,
+      context,
+    }),
+    [openDrawer, drawer, openAddQueryModal, renderSaveQueryButton, queryLibraryEnabled, context]
+  );
+
+  return (
+    <QueryLibraryContext.Provider value={value}>
+      {children}
+      {queryLibraryEnabled && drawer && (
+        <SavedQueriesDrawer
+          {...drawer}
+          onEdit={(savedQuery) => setModal({ savedQuery })}
+          onDismiss={() => setDrawer(undefined)}
+        />
+      )}
+      {queryLibraryEnabled && modal && (
+        <SavedQueryModal
+          query={modal.query}
+          savedQuery={modal.savedQuery}
+          title={modal.title}
+          onSave={onModalSave}
+          onDismiss={() => setModal(undefined)}
+        />
+      )}
+    </QueryLibraryContext.Provider>
+  );
+}
+
+async function updateSavedQueryFromEditor(uid: string, query: DataQuery, onUpdateSuccess?: () => void) {
+  try {
+    const savedQuery = await getSavedQuery(uid);
+    await updateSavedQuery(uid, toUpdateCommand(savedQuery, [query]));
+    dispatch(
+      notifyApp(createSuccessNotification(t('explore.saved-queries.notification-updated', 'Saved query updated')))
+    );
+    onUpdateSuccess?.();
+  } catch (err) {
+    dispatch(
+      notifyApp(
+        createErrorNotification(t('explore.saved-queries.notification-update-failed', 'Failed to update saved query'))
+      )
+    );
+  }
+}
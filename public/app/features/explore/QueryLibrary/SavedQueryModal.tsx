@@ -0,0 +1,111 @@
+import { useState } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { Trans, t } from '@grafana/i18n';
+import { DataQuery } from '@grafana/schema';
+import { Button, Field, FieldSet, Input, Modal, RadioButtonGroup, TagsInput, TextArea } from '@grafana/ui';
+import { TeamPicker } from 'app/core/components/Select/TeamPicker';
+import { Team } from 'app/types/teams';
+
+import { SavedQueryCommand, SavedQueryDTO, SavedQueryVisibility } from './savedQueries';
+
+export interface Props {
+  /** Query to save, when adding a new query to the library. */
+  query?: DataQuery;
+  /** Saved query to change, when editing the details or the sharing of a saved query. */
+  savedQuery?: SavedQueryDTO;
+  title?: string;
+  onSave: (cmd: SavedQueryCommand) => Promise<void>;
+  onDismiss: () => void;
+}
+
+export const SavedQueryModal = ({ query, savedQuery, title, onSave, onDismiss }: Props) => {
+  const [queryTitle, setQueryTitle] = useState(savedQuery?.title ?? title ?? '');
+  const [description, setDescription] = useState(savedQuery?.description ?? '');
+  const [tags, setTags] = useState<string[]>(savedQuery?.tags ?? []);
+  const [visibility, setVisibility] = useState<SavedQueryVisibility>(savedQuery?.visibility ?? 'private');
+  const [teamId, setTeamId] = useState<number | undefined>(savedQuery?.teamId || undefined);
+  const [isSaving, setIsSaving] = useState(false);
+
+  const visibilityOptions: Array<SelectableValue<SavedQueryVisibility>> = [
+    { label: t('explore.saved-query-modal.visibility.private', 'Only me'), value: 'private' },
+    { label: t('explore.saved-query-modal.visibility.team', 'Team'), value: 'team' },
+    { label: t('explore.saved-query-modal.visibility.org', 'Organization'), value: 'org' },
+  ];
+
+  const canSave = queryTitle.trim() !== '' && (visibility !== 'team' || Boolean(teamId));
+
+  const onSubmit = async () => {
+    const queries = savedQuery ? savedQuery.queries : query ? [query] : [];
+    setIsSaving(true);
+    try {
+      await onSave({
+        title: queryTitle.trim(),
+        description,
+        tags,
+        datasourceUid: savedQuery?.datasourceUid ?? query?.datasource?.uid,
+        datasourceType: savedQuery?.datasourceType ?? query?.datasource?.type,
+        queries,
+        visibility,
+        teamId: visibility === 'team' ? teamId : undefined,
+      });
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  return (
+    <Modal
+      isOpen
+      title={
+        savedQuery
+          ? t('explore.saved-query-modal.title-edit', 'Edit saved query')
+          : t('explore.saved-query-modal.title-add', 'Save query to library')
+      }
+      onDismiss={onDismiss}
+    >
+      <FieldSet>
+        <Field label={t('explore.saved-query-modal.label-title', 'Title')} required>
+          <Input
+            id="saved-query-title"
+            value={queryTitle}
+            onChange={(e) => setQueryTitle(e.currentTarget.value)}
+            maxLength={190}
+          />
+        </Field>
+        <Field label={t('explore.saved-query-modal.label-description', 'Description')}>
+          <TextArea
+            id="saved-query-description"
+            value={description}
+            onChange={(e) => setDescription(e.currentTarget.value)}
+          />
+        </Field>
+        <Field label={t('explore.saved-query-modal.label-tags', 'Tags')}>
+          <TagsInput id="saved-query-tags" tags={tags} onChange={setTags} />
+        </Field>
+        <Field
+          label={t('explore.saved-query-modal.label-share-with', 'Share with')}
+          description={t(
+            'explore.saved-query-modal.description-share-with',
+            'Private queries are only listed for you. Team queries are listed for the members of the team.'
+          )}
+        >
+          <RadioButtonGroup value={visibility} options={visibilityOptions} onChange={setVisibility} />
+        </Field>
+        {visibility === 'team' && (
+          <Field label={t('explore.saved-query-modal.label-team', 'Team')} required>
+            <TeamPicker teamId={teamId} onSelected={(team: SelectableValue<Team>) => setTeamId(team.value?.id)} />
+          </Field>
+        )}
+      </FieldSet>
+      <Modal.ButtonRow>
+        <Button variant="secondary" fill="outline" onClick={onDismiss}>
+          <Trans i18nKey="explore.saved-query-modal.button-cancel">Cancel</Trans>
+        </Button>
+        <Button variant="primary" onClick={onSubmit} disabled={!canSave || isSaving}>
+          <Trans i18nKey="explore.saved-query-modal.button-save">Save</Trans>
+        </Button>
+      </Modal.ButtonRow>
+    </Modal>
+  );
+};
@@ -0,0 +1,130 @@
+import { lastValueFrom } from 'rxjs';
+
+import { getBackendSrv } from '@grafana/runtime';
+import { DataQuery } from '@grafana/schema';
+
+export type SavedQueryVisibility = 'private' | 'team' | 'org';
+
+export type SavedQueryDTO = {
+  uid: string;
+  title: string;
+  description: string;
+  tags: string[];
+  datasourceUid: string;
+  datasourceType: string;
+  queries: DataQuery[];
+  visibility: SavedQueryVisibility;
+  teamId: number;
+  version: number;
+  usageCount: number;
+  lastUsedAt: number;
+  createdBy: number;
+  createdAt: number;
+  updatedBy: number;
+  updatedAt: number;
+};
+
+export type SavedQuerySearchFilters = {
+  datasourceUids?: string[];
+  searchString?: string;
+  tags?: string[];
+  visibility?: SavedQueryVisibility;
+  teamId?: number;
+  sort?: string;
+  page?: number;
+  limit?: number;
+};
+
+export type SavedQuerySearchResult = {
+  savedQueries: SavedQueryDTO[];
+  totalCount: number;
+  page: number;
+  perPage: number;
+};
+
+export type SavedQueryCommand = {
+  title: string;
+  description: string;
+  tags: string[];
+  datasourceUid?: string;
+  datasourceType?: string;
+  queries: DataQuery[];
+  visibility: SavedQueryVisibility;
+  teamId?: number;
+};
+
+export async function searchSavedQueries(filters: SavedQuerySearchFilters): Promise<SavedQuerySearchResult> {
+  const params = new URLSearchParams();
+  filters.datasourceUids?.forEach((uid) => params.append('datasourceUid', uid));
+  filters.tags?.forEach((tag) => params.append('tag', tag));
+  if (filters.searchString) {
+    params.append('searchString', filters.searchString);
+  }
+  if (filters.visibility) {
+    params.append('visibility', filters.visibility);
+  }
+  if (filters.teamId) {
+    params.append('teamId', String(filters.teamId));
+  }
+  if (filters.sort) {
+    params.append('sort', filters.sort);
+  }
+  if (filters.page) {
+    params.append('page', String(filters.page));
+  }
+  if (filters.limit) {
+    params.append('limit', String(filters.limit));
+  }
+
+  const response = await lastValueFrom(
+    getBackendSrv().fetch<{ result: SavedQuerySearchResult }>({
+      method: 'GET',
+      url: `/api/saved-queries?${params}`,
+      // to ensure any previous requests are cancelled
+      requestId: 'saved-queries-search',
+    })
+  );
+  return {
+    ...response.data.result,
+    savedQueries: response.data.result.savedQueries || [],
+  };
+}
+
+export async function getSavedQuery(uid: string): Promise<SavedQueryDTO> {
+  const { result } = await getBackendSrv().get<{ result: SavedQueryDTO }>(`/api/saved-queries/${uid}`);
+  return result;
+}
+
+export async function createSavedQuery(cmd: SavedQueryCommand): Promise<SavedQueryDTO> {
+  const { result } = await getBackendSrv().post<{ result: SavedQueryDTO }>('/api/saved-queries', cmd);
+  return result;
+}
+
+export async function updateSavedQuery(uid: string, cmd: SavedQueryCommand): Promise<SavedQueryDTO> {
+  const { result } = await getBackendSrv().put<{ result: SavedQueryDTO }>(`/api/saved-queries/${uid}`, cmd);
+  return result;
+}
+
+export async function deleteSavedQuery(uid: string): Promise<void> {
+  await getBackendSrv().delete(`/api/saved-queries/${uid}`);
+}
+
+export async function markSavedQueryUsed(uid: string): Promise<void> {
+  await getBackendSrv().post(`/api/saved-queries/${uid}/use`);
+}
+
+/**
+ * Returns the update command that keeps everything of the saved query but its queries.
+ */
+export function toUpdateCommand(savedQuery: SavedQueryDTO, queries: DataQuery[]): SavedQueryCommand {
+  return {
+    title: savedQuery.title,
+    description: savedQuery.description,
+    tags: savedQuery.tags,
+    datasourceUid: savedQuery.datasourceUid,
+    datasourceType: savedQuery.datasourceType,
+    queries,
+    visibility: savedQuery.visibility,
+    teamId: savedQuery.teamId || undefined,
+  };
+}
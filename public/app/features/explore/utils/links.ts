@@ -20,12 +20,13 @@ import {
   ExploreUrlState,
   urlUtil,
   DataFrameType,
+  SupportedTransformationType,
 } from '@grafana/data';
 import { t } from '@grafana/i18n';
 import { getTemplateSrv, reportInteraction, VariableInterpolation } from '@grafana/runtime';
 import { DataQuery } from '@grafana/schema';
 import { contextSrv } from 'app/core/services/context_srv';
-import { getTransformationVars } from 'app/features/correlations/transformations';
+import { DEFAULT_LABELS_FIELD, getTransformationVars } from 'app/features/correlations/transformations';
 import { parseDataplaneLogsFrame } from 'app/features/logs/logsFrame';
 import { ExploreItemState } from 'app/types/explore';
 
@@ -178,16 +179,26 @@ export const getFieldLinksForExplore = (options: {
       if (link.meta?.transformations) {
         link.meta?.transformations.forEach((transformation) => {
           let fieldValue;
-          if (transformation.field) {
-            const transformField = dataFrame?.fields.find((field) => field.name === transformation.field);
-            fieldValue = transformField?.values[rowIndex];
+          // label transformations read the labels of log lines by default
+          const inputFieldName =
+            transformation.field ||
+            (transformation.type === SupportedTransformationType.Label ? DEFAULT_LABELS_FIELD : undefined);
+          if (inputFieldName) {
+            const transformField = dataFrame?.fields.find((field) => field.name === inputFieldName);
+            // the input can also be a variable extracted by a previous transformation
+            fieldValue = transformField
+              ? transformField.values[rowIndex]
+              : internalLinkSpecificVars[inputFieldName]?.value;
           } else {
             fieldValue = field.values[rowIndex];
           }
 
           internalLinkSpecificVars = {
             ...internalLinkSpecificVars,
-            ...getTransformationVars(transformation, fieldValue, field.name),
+            ...getTransformationVars(transformation, fieldValue, field.name, {
+              ...scopedVars,
+              ...internalLinkSpecificVars,
+            }),
           };
         });
       }
@@ -0,0 +1,55 @@
+import { css } from '@emotion/css';
+import { useEffect } from 'react';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { Trans } from '@grafana/i18n';
+import { locationService } from '@grafana/runtime';
+import { Text, useStyles2 } from '@grafana/ui';
+
+import { useGetPlaylistQuery, useGetScheduledPlaylistQuery } from '../../api/clients/playlist/v0alpha1';
+
+import { playlistSrv } from './PlaylistSrv';
+
+// How often kiosk devices without a scheduled playlist check the schedules again
+const schedulePollingInterval = 60 * 1000;
+
+// PlaylistScheduledPage plays the playlist scheduled for the kiosk device named by the device query parameter,
+// since the kiosk query parameter already turns on kiosk mode. The playlist reloads this page after a few
+// loops, which picks up the playlist scheduled by then.
+export default function PlaylistScheduledPage() {
+  const styles = useStyles2(getStyles);
+  const { device } = locationService.getSearchObject();
+  const scheduled = useGetScheduledPlaylistQuery(
+    { kiosk: typeof device === 'string' ? device : undefined },
+    { pollingInterval: schedulePollingInterval }
+  );
+  const name = scheduled.data?.name;
+  const { data } = useGetPlaylistQuery({ name: name ?? '' }, { skip: !name });
+
+  useEffect(() => {
+    if (data) {
+      playlistSrv.start(data);
+    }
+  }, [data]);
+
+  if (!scheduled.isError) {
+    return null;
+  }
+  return (
+    <div className={styles.wrapper}>
+      <Text element="h2" color="secondary">
+        <Trans i18nKey="playlist-scheduled.not-scheduled">No playlist is scheduled for this device</Trans>
+      </Text>
+    </div>
+  );
+}
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  wrapper: css({
+    display: 'flex',
+    alignItems: 'center',
+    justifyContent: 'center',
+    height: '100%',
+    padding: theme.spacing(4),
+  }),
+});
@@ -145,6 +145,29 @@ describe('PlaylistSrv', () => {
     expect(srv.state.isPlaying).toBe(true);
   });
 
+  it('shows the dashboards of items with their own duration and time range', async () => {
+    const setTimeoutSpy = jest.spyOn(global, 'setTimeout');
+    await srv.start({
+      ...mockPlaylist,
+      spec: {
+        ...mockPlaylist.spec,
+        items: [
+          { type: 'dashboard_by_uid', value: 'aaa', duration: '2m', timeRange: { from: 'now-7d', to: 'now' } },
+          { type: 'dashboard_by_uid', value: 'bbb' },
+        ],
+      },
+    });
+
+    expect(setTimeoutSpy).toHaveBeenLastCalledWith(expect.any(Function), 120000);
+    expect(locationService.getSearchObject()).toMatchObject({ from: 'now-7d', to: 'now' });
+
+    srv.next();
+
+    expect(setTimeoutSpy).toHaveBeenLastCalledWith(expect.any(Function), 1000);
+    expect(locationService.getSearchObject()).not.toHaveProperty('from');
+    setTimeoutSpy.mockRestore();
+  });
+
   it('should replace playlist start page in history when starting playlist', async () => {
     // Start at playlists page
     locationService.push('/playlists');
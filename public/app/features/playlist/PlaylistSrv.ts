@@ -7,6 +7,7 @@ import { StateManagerBase } from 'app/core/services/StateManagerBase';
 
 import { Playlist } from '../../api/clients/playlist/v0alpha1';
 
+import { PlaylistItemUI } from './types';
 import { loadDashboards } from './utils';
 
 export const queryParamsToPreserve: { [key: string]: boolean } = {
@@ -22,11 +23,17 @@ export interface PlaylistSrvState {
   isPlaying: boolean;
 }
 
+interface PlaylistDashboard {
+  url: string;
+  // interval is how long the dashboard is shown, in milliseconds
+  interval: number;
+  timeRange?: PlaylistItemUI['timeRange'];
+}
+
 export class PlaylistSrv extends StateManagerBase<PlaylistSrvState> {
   private nextTimeoutId: ReturnType<typeof setTimeout> | undefined;
-  private urls: string[] = []; // the URLs we need to load
+  private dashboards: PlaylistDashboard[] = []; // the dashboards we need to load
   private index = 0;
-  declare private interval: number;
   declare private startUrl: string;
   private numberOfLoops = 0;
   declare private validPlaylistUrl: string;
@@ -39,14 +46,18 @@ export class PlaylistSrv extends StateManagerBase<PlaylistSrvState> {
   }
 
   private navigateToDashboard(replaceHistoryEntry = false) {
-    const url = this.urls[this.index];
+    const { url, interval, timeRange } = this.dashboards[this.index];
     const queryParams = locationService.getSearchObject();
     const filteredParams = pickBy(queryParams, (value: unknown, key: string) => queryParamsToPreserve[key]);
+    if (timeRange) {
+      filteredParams.from = timeRange.from;
+      filteredParams.to = timeRange.to;
+    }
     const nextDashboardUrl = locationUtil.stripBaseFromUrl(url);
 
     this.index++;
     this.validPlaylistUrl = nextDashboardUrl;
-    this.nextTimeoutId = setTimeout(() => this.next(), this.interval);
+    this.nextTimeoutId = setTimeout(() => this.next(), interval);
 
     const urlWithParams = nextDashboardUrl + '?' + urlUtil.toUrlParams(filteredParams);
 
@@ -63,7 +74,7 @@ export class PlaylistSrv extends StateManagerBase<PlaylistSrvState> {
   next() {
     clearTimeout(this.nextTimeoutId);
 
-    const playedAllDashboards = this.index > this.urls.length - 1;
+    const playedAllDashboards = this.index > this.dashboards.length - 1;
     if (playedAllDashboards) {
       this.numberOfLoops++;
 
@@ -101,30 +112,32 @@ export class PlaylistSrv extends StateManagerBase<PlaylistSrvState> {
 
     // setup location tracking
     this.locationListenerUnsub = locationService.getHistory().listen(this.locationUpdated);
-    const urls: string[] = [];
+    const dashboards: PlaylistDashboard[] = [];
 
     if (!playlist.spec.items?.length) {
       // alert
       return;
     }
 
-    this.interval = rangeUtil.intervalToMs(playlist.spec.interval);
+    const interval = rangeUtil.intervalToMs(playlist.spec.interval);
 
     const items = await loadDashboards(playlist.spec.items);
     for (const item of items) {
       if (item.dashboards) {
+        // Items can show their dashboards longer or shorter than the playlist, and with another time range
+        const itemInterval = item.duration ? rangeUtil.intervalToMs(item.duration) : interval;
         for (const dash of item.dashboards) {
-          urls.push(dash.url);
+          dashboards.push({ url: dash.url, interval: itemInterval, timeRange: item.timeRange });
         }
       }
     }
 
-    if (!urls.length) {
+    if (!dashboards.length) {
       // alert... not found, etc
       return;
     }
 
-    this.urls = urls;
+    this.dashboards = dashboards;
     this.setState({ isPlaying: true });
 
     // Replace current history entry with first dashboard instead of pushing
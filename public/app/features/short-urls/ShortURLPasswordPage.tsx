@@ -0,0 +1,84 @@
+import { useState } from 'react';
+import { useParams } from 'react-router-dom-v5-compat';
+
+import { Trans, t } from '@grafana/i18n';
+import { getBackendSrv, isFetchError } from '@grafana/runtime';
+import { Button, Field, Input } from '@grafana/ui';
+import { Form } from 'app/core/components/Form/Form';
+import { Page } from 'app/core/components/Page/Page';
+
+interface FormModel {
+  password: string;
+}
+
+interface ResolvedShortURL {
+  path: string;
+  url: string;
+}
+
+export const ShortURLPasswordPage = () => {
+  const { uid = '' } = useParams();
+  const [error, setError] = useState<string>();
+
+  const navModel = {
+    main: {
+      icon: 'link' as const,
+      text: t('short-urls.password-page.title', 'Password protected link'),
+      subTitle: t('short-urls.password-page.subtitle', 'Enter the password of the link to open it'),
+    },
+    node: {
+      text: '',
+    },
+  };
+
+  const onSubmit = async ({ password }: FormModel) => {
+    setError(undefined);
+    try {
+      const resolved = await getBackendSrv().post<ResolvedShortURL>(
+        `/api/short-urls/${encodeURIComponent(uid)}/resolve`,
+        { password },
+        { showErrorAlert: false }
+      );
+      window.location.href = resolved.url;
+    } catch (err) {
+      if (isFetchError(err) && err.data?.message) {
+        setError(err.data.message);
+        return;
+      }
+      setError(t('short-urls.password-page.error', 'The link could not be opened'));
+    }
+  };
+
+  return (
+    <Page navModel={navModel}>
+      <Page.Contents>
+        <Form<FormModel> onSubmit={onSubmit} maxWidth={480}>
+          {({ register, errors }) => (
+            <>
+              <Field
+                invalid={!!errors.password || !!error}
+                error={errors.password?.message || error}
+                label={t('short-urls.password-page.label-password', 'Password')}
+              >
+                <Input
+                  {...register('password', {
+                    required: t('short-urls.password-page.password-required', 'Password is required'),
+                  })}
+                  id="short-url-password"
+                  type="password"
+                  autoFocus
+                  autoComplete="off"
+                />
+              </Field>
+              <Button type="submit">
+                <Trans i18nKey="short-urls.password-page.open">Open link</Trans>
+              </Button>
+            </>
+          )}
+        </Form>
+      </Page.Contents>
+    </Page>
+  );
+};
+
+export default ShortURLPasswordPage;
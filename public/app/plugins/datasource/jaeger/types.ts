@@ -46,6 +46,8 @@ export type JaegerQuery = {
   minDuration?: string;
   maxDuration?: string;
   limit?: number;
+  // content of an uploaded trace file, used by the upload query type
+  uploadedJson?: string;
 } & DataQuery;
 
 export type JaegerQueryType = 'search' | 'upload' | 'dependencyGraph';
@@ -0,0 +1,24 @@
+import { RAQBFieldTypes, SQLSelectableValue } from '@grafana/sql';
+
+// mapFieldsToTypes follows the SQLite type affinity rules, as declared column types can be any string.
+// https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+export function mapFieldsToTypes(columns: SQLSelectableValue[]) {
+  return columns.map((col) => ({ ...col, raqbFieldType: fieldType(col.type) }));
+}
+
+function fieldType(declared = ''): RAQBFieldTypes {
+  const type = declared.toUpperCase();
+  if (type.includes('BOOL')) {
+    return 'boolean';
+  }
+  if (type.includes('DATETIME') || type.includes('TIMESTAMP')) {
+    return 'datetime';
+  }
+  if (type === 'DATE') {
+    return 'date';
+  }
+  if (['INT', 'REAL', 'FLOA', 'DOUB', 'NUM', 'DEC'].some((t) => type.includes(t))) {
+    return 'number';
+  }
+  return 'text';
+}
@@ -0,0 +1,35 @@
+import { QueryEditorExpressionType } from '@grafana/sql';
+
+import { buildColumnQuery } from './sqliteMetaQuery';
+import { quoteIdentifierIfNecessary, toRawSql, unquoteIdentifier } from './sqlUtil';
+
+describe('sqlUtil', () => {
+  it('quotes identifiers only when necessary', () => {
+    expect(quoteIdentifierIfNecessary('metric')).toBe('metric');
+    expect(quoteIdentifierIfNecessary('my table')).toBe('"my table"');
+    expect(quoteIdentifierIfNecessary('a"b')).toBe('"a""b"');
+    expect(unquoteIdentifier('"a""b"')).toBe('a"b');
+  });
+
+  it('builds column queries with a quoted table literal', () => {
+    expect(buildColumnQuery(`"it's"`)).toBe(`SELECT name, type FROM pragma_table_info('it''s') ORDER BY cid`);
+  });
+
+  it('builds raw sql without the schema', () => {
+    const sql = toRawSql({
+      refId: 'A',
+      dataset: 'main',
+      table: 'metric',
+      sql: {
+        columns: [
+          {
+            type: QueryEditorExpressionType.Function,
+            parameters: [{ type: QueryEditorExpressionType.FunctionParameter, name: 'value' }],
+          },
+        ],
+        limit: 10,
+      },
+    });
+    expect(sql).toBe('SELECT value FROM metric LIMIT 10 ');
+  });
+});
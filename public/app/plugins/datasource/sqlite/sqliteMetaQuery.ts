@@ -0,0 +1,10 @@
+import { quoteLiteral, unquoteIdentifier } from './sqlUtil';
+
+export function buildTableQuery() {
+  return `SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`;
+}
+
+// pragma_table_info is read-only and allowed by the connection, unlike the PRAGMA statement.
+export function buildColumnQuery(table: string) {
+  return `SELECT name, type FROM pragma_table_info(${quoteLiteral(unquoteIdentifier(table))}) ORDER BY cid`;
+}
@@ -0,0 +1,9 @@
+import { SQLOptions, SQLQuery } from '@grafana/sql';
+
+/**
+ * The database file is stored in jsonData.database. It must be located inside one of the
+ * directories configured in sql_datasources.sqlite_allowed_paths.
+ */
+export interface SQLiteOptions extends SQLOptions {}
+
+export interface SQLiteQuery extends SQLQuery {}
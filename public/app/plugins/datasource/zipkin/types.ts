@@ -31,4 +31,6 @@ export type ZipkinQueryType = 'traceID' | 'upload';
 export interface ZipkinQuery extends DataQuery {
   query: string;
   queryType?: ZipkinQueryType;
+  // content of an uploaded trace file, used by the upload query type
+  uploadedJson?: string;
 }
@@ -451,6 +451,12 @@ export function getAppRoutes(): RouteDescriptor[] {
         () => import(/* webpackChunkName: "PlaylistPage"*/ 'app/features/playlist/PlaylistPage')
       ),
     },
+    {
+      path: '/playlists/scheduled',
+      component: SafeDynamicImport(
+        () => import(/* webpackChunkName: "PlaylistScheduledPage"*/ 'app/features/playlist/PlaylistScheduledPage')
+      ),
+    },
     {
       path: '/playlists/play/:uid',
       component: SafeDynamicImport(
@@ -544,6 +550,12 @@ export function getAppRoutes(): RouteDescriptor[] {
     ...getPublicDashboardRoutes(),
     ...getDataConnectionsRoutes(),
     ...getProvisioningRoutes(),
+    {
+      path: '/goto/:uid/password',
+      component: SafeDynamicImport(
+        () => import(/* webpackChunkName: "ShortURLPasswordPage" */ 'app/features/short-urls/ShortURLPasswordPage')
+      ),
+    },
     {
       path: '/goto/*',
       component: HandleGoToRedirect,
@@ -81,6 +81,11 @@ export enum AccessControlAction {
   SnapshotsDelete = 'snapshots:delete',
   SnapshotsRead = 'snapshots:read',
 
+  SavedQueriesRead = 'savedqueries:read',
+  SavedQueriesCreate = 'savedqueries:create',
+  SavedQueriesWrite = 'savedqueries:write',
+  SavedQueriesDelete = 'savedqueries:delete',
+
   FoldersRead = 'folders:read',
   FoldersWrite = 'folders:write',
   FoldersDelete = 'folders:delete',